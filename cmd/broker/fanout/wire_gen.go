@@ -9,24 +9,25 @@ import (
 	"context"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/clients"
 )
 
 // Injectors from wire.go:
 
-func InitializeSyncPool(ctx context.Context, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, targetsVolumeOpts []volume.Option, opts ...handler.Option) (*handler.FanoutPool, error) {
+func InitializeSyncPool(ctx context.Context, projectID clients.ProjectID, queueSettings queue.Settings, podName metrics.PodName, containerName metrics.ContainerName, targetsVolumeOpts []volume.Option, opts ...handler.Option) (*handler.FanoutPool, error) {
 	readonlyTargets, err := volume.NewTargetsFromFile(targetsVolumeOpts...)
 	if err != nil {
 		return nil, err
 	}
-	client, err := clients.NewPubsubClient(ctx, projectID)
+	client, err := queue.NewClient(ctx, projectID, queueSettings)
 	if err != nil {
 		return nil, err
 	}
 	httpClient := _wireClientValue
 	v := _wireValue
-	retryClient, err := handler.NewRetryClient(ctx, client, v...)
+	retryClient, err := handler.NewRetryClient(client, v...)
 	if err != nil {
 		return nil, err
 	}
@@ -17,15 +17,30 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config/volume"
+	"github.com/google/knative-gcp/pkg/broker/ingress"
+	"github.com/google/knative-gcp/pkg/broker/ingress/auth"
+	"github.com/google/knative-gcp/pkg/broker/queue"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/utils/dedup"
 	"github.com/google/knative-gcp/pkg/utils/mainhelper"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 )
 
 type envConfig struct {
@@ -37,17 +52,64 @@ type envConfig struct {
 
 	// Default 300Mi.
 	PublishBufferedByteLimit int `envconfig:"PUBLISH_BUFFERED_BYTES_LIMIT" default:"314572800"`
+	// Maximum time events are buffered before they are published. The Pub/Sub client default is
+	// used if 0.
+	PublishDelayThreshold time.Duration `envconfig:"PUBLISH_DELAY_THRESHOLD" default:"0"`
+	// Number of buffered events that triggers a publish. The Pub/Sub client default is used if 0.
+	PublishCountThreshold int `envconfig:"PUBLISH_COUNT_THRESHOLD" default:"0"`
+	// Size in bytes of the buffered events that triggers a publish. The Pub/Sub client default is
+	// used if 0.
+	PublishByteThreshold int `envconfig:"PUBLISH_BYTE_THRESHOLD" default:"0"`
+	// Maximum time to wait for an event to be published. The Pub/Sub client default is used if 0.
+	PublishTimeout time.Duration `envconfig:"PUBLISH_TIMEOUT" default:"0"`
+
+	// Address of the Redis server shared by the ingress replicas to deduplicate events. If
+	// empty, each replica deduplicates the events it receives in memory.
+	DedupRedisAddress string `envconfig:"DEDUP_REDIS_ADDRESS" default:""`
+	// AUTH string of the Redis server, if it requires one.
+	DedupRedisPassword string `envconfig:"DEDUP_REDIS_PASSWORD" default:""`
+	// Whether the connections to the Redis server use TLS.
+	DedupRedisTLS bool `envconfig:"DEDUP_REDIS_TLS" default:"false"`
+	// PEM encoded certificates of the CAs of the Redis server, if DedupRedisTLS is set. If empty,
+	// the system CAs are used.
+	DedupRedisCACert string `envconfig:"DEDUP_REDIS_CA_CERT" default:""`
+	// Number of events held in memory to deduplicate events, if DedupRedisAddress is empty.
+	DedupMemoryStoreSize int `envconfig:"DEDUP_MEMORY_STORE_SIZE" default:"100000"`
+
+	// Cloud Storage bucket to offload the data of large events to. If empty, events larger than
+	// the Pub/Sub message limit are rejected.
+	ClaimCheckBucket string `envconfig:"CLAIM_CHECK_BUCKET" default:""`
+	// Data size in bytes above which events are offloaded to ClaimCheckBucket.
+	ClaimCheckThreshold int `envconfig:"CLAIM_CHECK_THRESHOLD_BYTES" default:"5000000"`
+	// Maximum size in bytes of a request if ClaimCheckBucket is set. Default 100Mi.
+	ClaimCheckMaxRequestBytes int64 `envconfig:"CLAIM_CHECK_MAX_REQUEST_BYTES" default:"104857600"`
+	// Age after which the offloaded data is deleted by the bucket lifecycle, rounded up to whole
+	// days. It should not be shorter than the retention of the broker queues.
+	ClaimCheckRetention time.Duration `envconfig:"CLAIM_CHECK_RETENTION" default:"168h"`
+
+	// Port of the gRPC Ingress service. The service is disabled if 0.
+	GRPCPort int `envconfig:"GRPC_PORT" default:"0"`
+	// Whether to accept WebSocket connections on the broker paths of Port.
+	EnableWebSocket bool `envconfig:"ENABLE_WEBSOCKET" default:"false"`
+
+	// QueueBackend is the backend of the decouple and retry queues, "pubsub" or "bolt".
+	QueueBackend queue.Backend `envconfig:"QUEUE_BACKEND" default:"pubsub"`
+	// QueueBoltPath is the path of the database file of the bolt queue backend. The ingress,
+	// fanout and retry must share the file.
+	QueueBoltPath string `envconfig:"QUEUE_BOLT_PATH" default:""`
 }
 
 const (
 	component       = "broker-ingress"
 	metricNamespace = "broker"
+
+	jwksFetchTimeout = 10 * time.Second
 )
 
 // main creates and starts an ingress handler using default options.
 // 1. It listens on port specified by "PORT" env var, or default 8080 if env var is not set
 // 2. It reads "PROJECT_ID" env var for pubsub project. If the env var is empty, it retrieves project ID from
-//    GCE metadata.
+//    GCE metadata. The project is not needed if "QUEUE_BACKEND" is "bolt".
 // 3. It expects broker configmap mounted at "/var/run/cloud-run-events/broker/targets"
 func main() {
 	appcredentials.MustExistOrUnsetEnv()
@@ -57,12 +119,23 @@ func main() {
 	defer res.Cleanup()
 	logger := res.Logger
 
-	projectID, err := utils.ProjectIDOrDefault("")
-	if err != nil {
-		logger.Desugar().Fatal("Failed to create project id", zap.Error(err))
+	var projectID string
+	if env.QueueBackend == queue.PubsubBackend {
+		var err error
+		projectID, err = utils.ProjectIDOrDefault("")
+		if err != nil {
+			logger.Desugar().Fatal("Failed to create project id", zap.Error(err))
+		}
 	}
 	logger.Desugar().Info("Starting ingress handler", zap.Any("envConfig", env), zap.Any("Project ID", projectID))
 
+	// Trust Google-signed ID tokens and the service account tokens of the cluster, for the
+	// brokers that require authentication.
+	verifier := auth.NewVerifier()
+	auth.AddGoogleIssuer(verifier, &http.Client{Timeout: jwksFetchTimeout})
+	go auth.AddKubernetesIssuer(ctx, verifier, kubeclient.Get(ctx).Discovery().RESTClient())
+
+	targetsUpdateCh := make(chan struct{})
 	ingress, err := InitializeHandler(
 		ctx,
 		clients.Port(env.Port),
@@ -71,10 +144,26 @@ func main() {
 		metrics.ContainerName(component),
 		publishSetting(logger.Desugar(), env),
 		env.AuthType,
+		verifier,
+		dedupStore(logger.Desugar(), env),
+		claimCheck(ctx, logger.Desugar(), env),
+		ingress.StreamingOptions{WebSocket: env.EnableWebSocket, GRPCPort: env.GRPCPort},
+		queue.Settings{Backend: env.QueueBackend, BoltPath: env.QueueBoltPath},
+		[]volume.Option{volume.WithNotifyChan(targetsUpdateCh)},
 	)
 	if err != nil {
 		logger.Desugar().Fatal("Unable to create ingress handler: ", zap.Error(err))
 	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-targetsUpdateCh:
+				ingress.TargetsReloaded()
+			}
+		}
+	}()
 
 	logger.Desugar().Info("Starting ingress.", zap.Any("ingress", ingress))
 	if err := ingress.Start(ctx); err != nil {
@@ -82,6 +171,62 @@ func main() {
 	}
 }
 
+func dedupStore(logger *zap.Logger, env envConfig) dedup.Store {
+	if env.DedupRedisAddress != "" {
+		opts := &redis.Options{
+			Addr:     env.DedupRedisAddress,
+			Password: env.DedupRedisPassword,
+		}
+		if env.DedupRedisTLS {
+			opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+			if env.DedupRedisCACert != "" {
+				opts.TLSConfig.RootCAs = x509.NewCertPool()
+				if !opts.TLSConfig.RootCAs.AppendCertsFromPEM([]byte(env.DedupRedisCACert)) {
+					logger.Fatal("Failed to parse DEDUP_REDIS_CA_CERT")
+				}
+			}
+		}
+		return dedup.NewRedisStore(opts)
+	}
+	size := env.DedupMemoryStoreSize
+	if size <= 0 {
+		logger.Warn("DEDUP_MEMORY_STORE_SIZE is less or equal than 0; ignoring it", zap.Int("DedupMemoryStoreSize", size))
+		size = dedup.DefaultMemoryStoreSize
+	}
+	return dedup.NewMemoryStore(size)
+}
+
+// claimCheck returns the claim check configuration of the ingress, or nil if claim check is
+// disabled. It also ensures that the expired offloaded data is deleted by the bucket lifecycle.
+func claimCheck(ctx context.Context, logger *zap.Logger, env envConfig) *ingress.ClaimCheck {
+	if env.ClaimCheckBucket == "" {
+		return nil
+	}
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		logger.Fatal("Failed to create storage client", zap.Error(err))
+	}
+	store := claimcheck.NewStore(client, env.ClaimCheckBucket)
+	threshold := env.ClaimCheckThreshold
+	if threshold <= 0 {
+		logger.Warn("CLAIM_CHECK_THRESHOLD_BYTES is less or equal than 0; ignoring it", zap.Int("ClaimCheckThreshold", threshold))
+		threshold = claimcheck.DefaultThreshold
+	}
+	retention := env.ClaimCheckRetention
+	if retention <= 0 {
+		logger.Warn("CLAIM_CHECK_RETENTION is less or equal than 0; ignoring it", zap.Duration("ClaimCheckRetention", retention))
+		retention = claimcheck.DefaultRetention
+	}
+	if err := store.EnsureLifecycle(ctx, retention); err != nil {
+		logger.Error("Failed to set the lifecycle rule deleting the expired claim check objects; set it on the bucket manually", zap.Error(err))
+	}
+	return &ingress.ClaimCheck{
+		Store:           store,
+		Threshold:       threshold,
+		MaxRequestBytes: env.ClaimCheckMaxRequestBytes,
+	}
+}
+
 func publishSetting(logger *zap.Logger, env envConfig) pubsub.PublishSettings {
 	s := pubsub.DefaultPublishSettings
 	if env.PublishBufferedByteLimit > 0 {
@@ -89,5 +234,17 @@ func publishSetting(logger *zap.Logger, env envConfig) pubsub.PublishSettings {
 	} else {
 		logger.Warn("PUBLISH_BUFFERED_BYTES_LIMIT is less or equal than 0; ignoring it", zap.Int("PublishBufferedByteLimit", env.PublishBufferedByteLimit))
 	}
+	if env.PublishDelayThreshold > 0 {
+		s.DelayThreshold = env.PublishDelayThreshold
+	}
+	if env.PublishCountThreshold > 0 {
+		s.CountThreshold = env.PublishCountThreshold
+	}
+	if env.PublishByteThreshold > 0 {
+		s.ByteThreshold = env.PublishByteThreshold
+	}
+	if env.PublishTimeout > 0 {
+		s.Timeout = env.PublishTimeout
+	}
 	return s
 }
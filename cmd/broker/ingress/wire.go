@@ -24,9 +24,12 @@ import (
 	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
+	"github.com/google/knative-gcp/pkg/broker/ingress/auth"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/utils/dedup"
 	"github.com/google/wire"
 )
 
@@ -38,10 +41,15 @@ func InitializeHandler(
 	containerName metrics.ContainerName,
 	publishSettings pubsub.PublishSettings,
 	authType authcheck.AuthType,
+	verifier *auth.Verifier,
+	dedupStore dedup.Store,
+	claimCheck *ingress.ClaimCheck,
+	streaming ingress.StreamingOptions,
+	queueSettings queue.Settings,
+	targetsVolumeOpts []volume.Option,
 ) (*ingress.Handler, error) {
 	panic(wire.Build(
 		ingress.HandlerSet,
-		wire.Value([]volume.Option(nil)),
 		volume.NewTargetsFromFile,
 	))
 }
@@ -10,33 +10,33 @@ import (
 	"context"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
+	"github.com/google/knative-gcp/pkg/broker/ingress/auth"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/utils/dedup"
 )
 
 // Injectors from wire.go:
 
-func InitializeHandler(ctx context.Context, port clients.Port, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings, authType authcheck.AuthType) (*ingress.Handler, error) {
+func InitializeHandler(ctx context.Context, port clients.Port, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings, authType authcheck.AuthType, verifier *auth.Verifier, dedupStore dedup.Store, claimCheck *ingress.ClaimCheck, streaming ingress.StreamingOptions, queueSettings queue.Settings, targetsVolumeOpts []volume.Option) (*ingress.Handler, error) {
 	httpMessageReceiver := clients.NewHTTPMessageReceiverWithChecker(port, authType)
-	v := _wireValue
-	readonlyTargets, err := volume.NewTargetsFromFile(v...)
+	readonlyTargets, err := volume.NewTargetsFromFile(targetsVolumeOpts...)
 	if err != nil {
 		return nil, err
 	}
-	client, err := clients.NewPubsubClient(ctx, projectID)
+	client, err := queue.NewClient(ctx, projectID, queueSettings)
 	if err != nil {
 		return nil, err
 	}
-	multiTopicDecoupleSink := ingress.NewMultiTopicDecoupleSink(ctx, readonlyTargets, client, publishSettings)
+	multiTopicDecoupleSink := ingress.NewMultiTopicDecoupleSink(ctx, readonlyTargets, client, publishSettings, dedupStore)
 	ingressReporter, err := metrics.NewIngressReporter(podName, containerName)
 	if err != nil {
 		return nil, err
 	}
-	handler := ingress.NewHandler(ctx, httpMessageReceiver, multiTopicDecoupleSink, ingressReporter, authType)
+	authenticator := ingress.NewAuthenticator(readonlyTargets, verifier)
+	quotas := ingress.NewQuotas(readonlyTargets, ingressReporter, publishSettings)
+	handler := ingress.NewHandler(ctx, httpMessageReceiver, multiTopicDecoupleSink, ingressReporter, authType, authenticator, readonlyTargets, claimCheck, quotas, publishSettings, streaming)
 	return handler, nil
 }
-
-var (
-	_wireValue = []volume.Option(nil)
-)
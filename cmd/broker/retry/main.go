@@ -24,8 +24,15 @@ import (
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
 
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/queue"
+	"github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
@@ -38,6 +45,12 @@ const (
 	component        = "broker-retry"
 	metricNamespace  = "trigger"
 	poolResyncPeriod = 15 * time.Second
+	// circuitBreakerAnnotationPeriod is how often the open circuit breakers are
+	// published on the pod's annotations.
+	circuitBreakerAnnotationPeriod = 10 * time.Second
+	// replayAnnotationPeriod is how often the progress of the replays is
+	// published on the pod's annotations.
+	replayAnnotationPeriod = 10 * time.Second
 )
 
 type envConfig struct {
@@ -61,6 +74,23 @@ type envConfig struct {
 
 	// Max to 10m.
 	TimeoutPerEvent time.Duration `envconfig:"TIMEOUT_PER_EVENT"`
+
+	// CircuitBreakerFailureThreshold is the number of consecutive failed deliveries that
+	// opens the circuit breaker of a Trigger's subscriber. Zero disables circuit breakers.
+	CircuitBreakerFailureThreshold int `envconfig:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" default:"20"`
+
+	// CircuitBreakerOpenTimeout is how long an open circuit breaker waits before probing the subscriber again.
+	CircuitBreakerOpenTimeout time.Duration `envconfig:"CIRCUIT_BREAKER_OPEN_TIMEOUT" default:"30s"`
+
+	// ClaimCheckBucket is the Cloud Storage bucket the ingress offloads the data of large events to.
+	// If empty, events are delivered with their claim check reference.
+	ClaimCheckBucket string `envconfig:"CLAIM_CHECK_BUCKET" default:""`
+
+	// QueueBackend is the backend of the decouple and retry queues, "pubsub" or "bolt".
+	QueueBackend queue.Backend `envconfig:"QUEUE_BACKEND" default:"pubsub"`
+	// QueueBoltPath is the path of the database file of the bolt queue backend. The ingress,
+	// fanout and retry must share the file.
+	QueueBoltPath string `envconfig:"QUEUE_BOLT_PATH" default:""`
 }
 
 func main() {
@@ -81,22 +111,35 @@ func main() {
 	targetsUpdateCh := make(chan struct{})
 	logger.Info("Starting the broker retry")
 
-	projectID, err := utils.ProjectIDOrDefault("")
-	if err != nil {
-		logger.Fatalf("failed to get default ProjectID: %v", err)
+	var projectID string
+	if env.QueueBackend == queue.PubsubBackend {
+		var err error
+		projectID, err = utils.ProjectIDOrDefault("")
+		if err != nil {
+			logger.Fatalf("failed to get default ProjectID: %v", err)
+		}
 	}
 
 	syncSignal := poolSyncSignal(ctx, targetsUpdateCh)
+	opts := buildHandlerOptions(env)
+	if env.ClaimCheckBucket != "" {
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			logger.Fatal("Failed to create storage client", zap.Error(err))
+		}
+		opts = append(opts, handler.WithClaimCheck(claimcheck.NewStore(client, env.ClaimCheckBucket)))
+	}
 	syncPool, err := InitializeSyncPool(
 		ctx,
 		clients.ProjectID(projectID),
+		queue.Settings{Backend: env.QueueBackend, BoltPath: env.QueueBoltPath},
 		metrics.PodName(env.PodName),
 		metrics.ContainerName(component),
 		[]volume.Option{
 			volume.WithPath(env.TargetsConfigPath),
 			volume.WithNotifyChan(targetsUpdateCh),
 		},
-		buildHandlerOptions(env)...,
+		opts...,
 	)
 	if err != nil {
 		logger.Fatal("Failed to get retry sync pool", zap.Error(err))
@@ -104,6 +147,12 @@ func main() {
 	if _, err := handler.StartSyncPool(ctx, syncPool, syncSignal, env.MaxStaleDuration, handler.DefaultProbeCheckPort, authcheck.NewDefault(env.AuthType)); err != nil {
 		logger.Fatal("Failed to start retry sync pool", zap.Error(err))
 	}
+	if cb := syncPool.CircuitBreakers(); cb != nil {
+		// Publish the open circuit breakers so the Trigger reconciler can surface them.
+		go cb.AnnotatePod(ctx, kubeclient.Get(ctx), system.Namespace(), env.PodName, circuitBreakerAnnotationPeriod)
+	}
+	// Publish the progress of the replays so the Replay reconciler can surface it.
+	go syncPool.ReplayTracker().AnnotatePod(ctx, kubeclient.Get(ctx), system.Namespace(), env.PodName, replayAnnotationPeriod)
 
 	// Context will be done if a TERM signal is issued.
 	<-ctx.Done()
@@ -147,6 +196,12 @@ func buildHandlerOptions(env envConfig) []handler.Option {
 	if env.TimeoutPerEvent > 0 {
 		opts = append(opts, handler.WithTimeoutPerEvent(env.TimeoutPerEvent))
 	}
+	if env.CircuitBreakerFailureThreshold > 0 {
+		opts = append(opts, handler.WithCircuitBreaker(circuitbreaker.Settings{
+			FailureThreshold: env.CircuitBreakerFailureThreshold,
+			OpenTimeout:      env.CircuitBreakerOpenTimeout,
+		}))
+	}
 	opts = append(opts, handler.WithPubsubReceiveSettings(rs))
 	// The default CeClient is good?
 	return opts
@@ -23,16 +23,19 @@ import (
 
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/clients"
 	"github.com/google/wire"
 )
 
-// InitializeSyncPool initializes the retry sync pool. Uses the given projectID to initialize the
-// retry pool's pubsub client and uses targetsVolumeOpts to initialize the targets volume watcher.
+// InitializeSyncPool initializes the retry sync pool. Uses the given projectID and
+// queueSettings to initialize the queue client and uses targetsVolumeOpts to initialize the
+// targets volume watcher.
 func InitializeSyncPool(
 	ctx context.Context,
 	projectID clients.ProjectID,
+	queueSettings queue.Settings,
 	podName metrics.PodName,
 	containerName metrics.ContainerName,
 	targetsVolumeOpts []volume.Option,
@@ -36,6 +36,7 @@ import (
 	staticpullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/static"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/topic"
 	"github.com/google/knative-gcp/pkg/reconciler/messaging/channel"
+	"github.com/google/knative-gcp/pkg/reconciler/replay"
 	"github.com/google/knative-gcp/pkg/reconciler/trigger"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
 	"knative.dev/pkg/injection"
@@ -67,6 +68,7 @@ func Controllers(
 	brokerController broker.Constructor,
 	deploymentController deployment.Constructor,
 	brokercellController brokercell.Constructor,
+	replayController replay.Constructor,
 ) []injection.ControllerConstructor {
 	return []injection.ControllerConstructor{
 		injection.ControllerConstructor(auditlogsController),
@@ -82,6 +84,7 @@ func Controllers(
 		injection.ControllerConstructor(brokerController),
 		injection.ControllerConstructor(deploymentController),
 		injection.ControllerConstructor(brokercellController),
+		injection.ControllerConstructor(replayController),
 	}
 }
 
@@ -34,6 +34,7 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/static"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/topic"
 	"github.com/google/knative-gcp/pkg/reconciler/messaging/channel"
+	"github.com/google/knative-gcp/pkg/reconciler/replay"
 	"github.com/google/knative-gcp/pkg/reconciler/trigger"
 	"github.com/google/wire"
 	"knative.dev/pkg/injection"
@@ -60,5 +61,6 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 		broker.NewConstructor,
 		deployment.NewConstructor,
 		brokercell.NewConstructor,
+		replay.NewConstructor,
 	))
 }
@@ -20,12 +20,15 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	pkgmetrics "knative.dev/pkg/metrics"
 	"knative.dev/pkg/tracing"
 
+	"github.com/google/knative-gcp/pkg/metrics"
 	. "github.com/google/knative-gcp/pkg/pubsub/publisher"
 	"github.com/google/knative-gcp/pkg/testing/testloggingutil"
 	tracingconfig "github.com/google/knative-gcp/pkg/tracing"
@@ -51,8 +54,20 @@ type envConfig struct {
 	// original config is stored in a ConfigMap inside the controller's namespace. Its value is
 	// copied here as a JSON string.
 	TracingConfigJson string `envconfig:"K_TRACING_CONFIG" required:"true"`
+
+	// MetricsConfigJson is a json string of metrics.ExporterOptions.
+	// This is used to configure the metrics exporter options, the config is
+	// stored in a config map inside the controllers namespace and copied here.
+	// Metrics are not exported if empty.
+	MetricsConfigJson string `envconfig:"K_METRICS_CONFIG" default:""`
+
+	// OrderingKeyAttribute is the event attribute used as the ordering key of
+	// the published messages. Ordering is disabled if empty.
+	OrderingKeyAttribute string `envconfig:"ORDERING_KEY_ATTRIBUTE" default:""`
 }
 
+const component = "publisher"
+
 func main() {
 	appcredentials.MustExistOrUnsetEnv()
 
@@ -93,6 +108,22 @@ func main() {
 		logger.Error("Failed to setup tracing", zap.Error(err), zap.Any("tracingConfig", tracingConfig))
 	}
 
+	if env.MetricsConfigJson != "" {
+		metricsConfig, err := pkgmetrics.JSONToOptions(env.MetricsConfigJson)
+		if err != nil {
+			logger.Error("Failed to process metrics options", zap.Error(err))
+		} else if err := pkgmetrics.UpdateExporter(ctx, *metricsConfig, logger.Sugar()); err != nil {
+			logger.Fatal("Failed to create the metrics exporter", zap.Error(err))
+		}
+		defer pkgmetrics.FlushExporter()
+	}
+
+	// The hostname of the pod is its name.
+	podName, err := os.Hostname()
+	if err != nil {
+		logger.Error("Failed to retrieve pod name", zap.Error(err))
+	}
+
 	logger.Info("Initializing publisher", zap.String("Project ID", projectID), zap.String("Topic ID", topicID))
 
 	publisher, err := InitializePublisher(
@@ -101,6 +132,9 @@ func main() {
 		clients.ProjectID(projectID),
 		TopicID(topicID),
 		env.AuthType,
+		metrics.PodName(podName),
+		metrics.ContainerName(component),
+		OrderingKeyAttribute(env.OrderingKeyAttribute),
 	)
 
 	if err != nil {
@@ -21,6 +21,7 @@ package main
 import (
 	"context"
 
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/pubsub/publisher"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	"github.com/google/knative-gcp/pkg/utils/clients"
@@ -34,6 +35,9 @@ func InitializePublisher(
 	projectID clients.ProjectID,
 	topicID publisher.TopicID,
 	authType authcheck.AuthType,
+	podName metrics.PodName,
+	containerName metrics.ContainerName,
+	orderingKeyAttribute publisher.OrderingKeyAttribute,
 ) (*publisher.Publisher, error) {
 	panic(wire.Build(
 		publisher.PublisherSet,
@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/pubsub/publisher"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	"github.com/google/knative-gcp/pkg/utils/clients"
@@ -14,13 +15,17 @@ import (
 
 // Injectors from wire.go:
 
-func InitializePublisher(ctx context.Context, port clients.Port, projectID clients.ProjectID, topicID publisher.TopicID, authType authcheck.AuthType) (*publisher.Publisher, error) {
+func InitializePublisher(ctx context.Context, port clients.Port, projectID clients.ProjectID, topicID publisher.TopicID, authType authcheck.AuthType, podName metrics.PodName, containerName metrics.ContainerName, orderingKeyAttribute publisher.OrderingKeyAttribute) (*publisher.Publisher, error) {
 	httpMessageReceiver := clients.NewHTTPMessageReceiver(port)
 	client, err := clients.NewPubsubClient(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 	topic := publisher.NewPubSubTopic(ctx, client, topicID)
-	publisherPublisher := publisher.NewPublisher(ctx, httpMessageReceiver, topic, authType)
+	publisherReporter, err := metrics.NewPublisherReporter(podName, containerName)
+	if err != nil {
+		return nil, err
+	}
+	publisherPublisher := publisher.NewPublisher(ctx, httpMessageReceiver, topic, authType, publisherReporter, orderingKeyAttribute)
 	return publisherPublisher, nil
 }
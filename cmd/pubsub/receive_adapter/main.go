@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"knative.dev/pkg/signals"
 	"knative.dev/pkg/tracing"
 
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	. "github.com/google/knative-gcp/pkg/pubsub/adapter"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	tracingconfig "github.com/google/knative-gcp/pkg/tracing"
@@ -64,10 +66,23 @@ type envConfig struct {
 	// Otherwise, only Sink is used (for either the sub.reply or sub.reply)
 	Transformer string `envconfig:"TRANSFORMER_URI"`
 
+	// Environment variable containing the dead letter sink URI. Messages that
+	// can't be converted to events are sent there instead of being dropped, and
+	// events the sink keeps rejecting instead of being retried.
+	DeadLetterSink string `envconfig:"DEAD_LETTER_SINK_URI"`
+
+	// Environment variable containing the number of attempts to deliver an event
+	// to the sink before sending it to the dead letter sink.
+	MaxDeliveryAttempts int `envconfig:"MAX_DELIVERY_ATTEMPTS"`
+
 	// Environment variable specifying the type of adapter to use.
 	// Used for CE conversion.
 	AdapterType string `envconfig:"ADAPTER_TYPE"`
 
+	// Environment variable containing the JSON encoded event mapping used by
+	// the pubsub_mapping adapter type.
+	EventMapping string `envconfig:"EVENT_MAPPING"`
+
 	// Topic is the environment variable containing the PubSub Topic being
 	// subscribed to's name. In the form that is unique within the project.
 	// E.g. 'laconia', not 'projects/my-gcp-project/topics/laconia'.
@@ -169,15 +184,26 @@ func main() {
 		logger.Error("Failed to convert base64 extensions to map: %v", zap.Error(err))
 	}
 
+	var eventMapping *gcpduckv1.EventMapping
+	if env.EventMapping != "" {
+		eventMapping = &gcpduckv1.EventMapping{}
+		if err := json.Unmarshal([]byte(env.EventMapping), eventMapping); err != nil {
+			logger.Fatal("Failed to parse the event mapping", zap.Error(err))
+		}
+	}
+
 	logger.Info("Initializing adapter", zap.String("projectID", projectID), zap.String("topicID", env.Topic), zap.String("subscriptionID", env.Subscription))
 
 	args := &AdapterArgs{
-		TopicID:        env.Topic,
-		ConverterType:  converters.ConverterType(env.AdapterType),
-		SinkURI:        env.Sink,
-		TransformerURI: env.Transformer,
-		Extensions:     extensions,
-		AuthType:       env.AuthType,
+		TopicID:             env.Topic,
+		ConverterType:       converters.ConverterType(env.AdapterType),
+		SinkURI:             env.Sink,
+		TransformerURI:      env.Transformer,
+		DeadLetterSinkURI:   env.DeadLetterSink,
+		MaxDeliveryAttempts: env.MaxDeliveryAttempts,
+		Extensions:          extensions,
+		EventMapping:        eventMapping,
+		AuthType:            env.AuthType,
 	}
 
 	adapter, err := InitializeAdapter(ctx,
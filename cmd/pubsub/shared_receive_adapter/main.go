@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/shared"
+	"github.com/google/knative-gcp/pkg/utils/appcredentials"
+	"github.com/google/knative-gcp/pkg/utils/authcheck"
+	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/utils/mainhelper"
+
+	"go.uber.org/zap"
+)
+
+const (
+	component       = "shared_receive_adapter"
+	metricNamespace = "source"
+
+	// TODO make this configurable
+	maxConnectionsPerHost = 1000
+)
+
+type envConfig struct {
+	// Environment variable containing the authType, which represents the authentication configuration mode the Pod is using.
+	AuthType authcheck.AuthType `envconfig:"K_GCP_AUTH_TYPE" default:""`
+
+	// SubscriptionsConfigDir is the directory the shared receive adapter ConfigMaps are projected into.
+	SubscriptionsConfigDir string `envconfig:"SUBSCRIPTIONS_CONFIG_DIR" default:"/var/run/cloud-run-events/pubsub"`
+}
+
+func main() {
+	appcredentials.MustExistOrUnsetEnv()
+
+	var env envConfig
+	ctx, res := mainhelper.Init(component, mainhelper.WithMetricNamespace(metricNamespace), mainhelper.WithEnv(&env))
+	defer res.Cleanup()
+	logger := res.Logger
+
+	configUpdateCh := make(chan struct{})
+	configDir, err := shared.NewConfigDir(env.SubscriptionsConfigDir, configUpdateCh)
+	if err != nil {
+		logger.Fatal("Failed to load the shared receive adapter config", zap.Error(err))
+	}
+
+	// A single probe checker runs the authentication check for all the subscriptions.
+	pc := authcheck.NewProbeChecker(logger.Desugar(), env.AuthType)
+	go pc.Start(ctx)
+
+	pool := shared.NewPool(
+		clients.NewHTTPClient(ctx, maxConnectionsPerHost),
+		converters.NewPubSubConverter())
+	defer pool.Close()
+
+	logger.Info("Starting the shared receive adapter")
+	runPool(ctx, pool, configDir, configUpdateCh)
+
+	// Wait a grace period for the adapters to shutdown.
+	time.Sleep(30 * time.Second)
+	logger.Info("Exiting...")
+}
+
+// runPool syncs the pool whenever the config changes, until the context is done.
+func runPool(ctx context.Context, pool *shared.Pool, configDir *shared.ConfigDir, configUpdateCh <-chan struct{}) {
+	for {
+		pool.Sync(ctx, configDir.Load())
+		select {
+		case <-ctx.Done():
+			return
+		case <-configUpdateCh:
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	"github.com/google/knative-gcp/pkg/apis/events"
 	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/intevents"
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
@@ -52,8 +53,9 @@ import (
 
 var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	// For group eventing.knative.dev.
-	brokerv1beta1.SchemeGroupVersion.WithKind("Broker"):  &brokerv1beta1.Broker{},
-	brokerv1beta1.SchemeGroupVersion.WithKind("Trigger"): &brokerv1beta1.Trigger{},
+	brokerv1beta1.SchemeGroupVersion.WithKind("Broker"):      &brokerv1beta1.Broker{},
+	brokerv1beta1.SchemeGroupVersion.WithKind("Trigger"):     &brokerv1beta1.Trigger{},
+	brokerv1beta1.SchemeGroupVersion.WithKind("EventSchema"): &brokerv1beta1.EventSchema{},
 
 	// For group messaging.cloud.google.com.
 	messagingv1beta1.SchemeGroupVersion.WithKind("Channel"): &messagingv1beta1.Channel{},
@@ -69,6 +71,7 @@ var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	eventsv1.SchemeGroupVersion.WithKind("CloudPubSubSource"):         &eventsv1.CloudPubSubSource{},
 	eventsv1.SchemeGroupVersion.WithKind("CloudAuditLogsSource"):      &eventsv1.CloudAuditLogsSource{},
 	eventsv1.SchemeGroupVersion.WithKind("CloudBuildSource"):          &eventsv1.CloudBuildSource{},
+	eventsv1alpha1.SchemeGroupVersion.WithKind("Replay"):              &eventsv1alpha1.Replay{},
 
 	// For group internal.events.cloud.google.com.
 	inteventsv1beta1.SchemeGroupVersion.WithKind("PullSubscription"): &inteventsv1beta1.PullSubscription{},
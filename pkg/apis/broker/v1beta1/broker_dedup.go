@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// DedupWindowAnnotation is the annotation key used to enable the deduplication of the events
+	// sent to a Broker. Events with the same source and id as an event accepted within the window
+	// are accepted without being published again. The value is a Go duration between
+	// MinDedupWindow and MaxDedupWindow, e.g. 10m.
+	DedupWindowAnnotation = "events.cloud.google.com/dedupWindow"
+
+	// MinDedupWindow is the minimum deduplication window.
+	MinDedupWindow = time.Second
+	// MaxDedupWindow is the maximum deduplication window.
+	MaxDedupWindow = 24 * time.Hour
+)
+
+// GetDedupWindow returns the deduplication window of the Broker, or zero if deduplication is not
+// enabled or the annotation is invalid.
+func (b *Broker) GetDedupWindow() time.Duration {
+	v, ok := b.GetAnnotations()[DedupWindowAnnotation]
+	if !ok {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < MinDedupWindow || d > MaxDedupWindow {
+		return 0
+	}
+	return d
+}
+
+func (b *Broker) validateDedupWindow(_ context.Context) *apis.FieldError {
+	v, ok := b.GetAnnotations()[DedupWindowAnnotation]
+	if !ok {
+		return nil
+	}
+	path := fmt.Sprintf("metadata.annotations[%s]", DedupWindowAnnotation)
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return apis.ErrInvalidValue(v, path)
+	}
+	if d < MinDedupWindow || d > MaxDedupWindow {
+		return apis.ErrOutOfBoundsValue(v, MinDedupWindow.String(), MaxDedupWindow.String(), path)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// IngressAuthAnnotation is the annotation key used to require the requests sent to a Broker
+	// to carry a bearer token, either a Kubernetes service account token or a Google-signed ID
+	// token. The value is IngressAuthRequired or IngressAuthDisabled, and overrides the
+	// ingress auth policy of the BrokerCell.
+	IngressAuthAnnotation = "events.cloud.google.com/ingressAuth"
+	// IngressAudienceAnnotation is the annotation key used to set the audience the tokens must be
+	// issued for. It defaults to the address of the Broker.
+	IngressAudienceAnnotation = "events.cloud.google.com/ingressAudience"
+	// IngressAllowedPrincipalsAnnotation is the annotation key used to restrict the principals
+	// allowed to send events to a Broker. The value is a comma separated list of token subjects,
+	// e.g. system:serviceaccount:my-namespace:my-sa, or of Google service account emails. A
+	// trailing * matches any suffix. It overrides the allowed principals of the BrokerCell.
+	IngressAllowedPrincipalsAnnotation = "events.cloud.google.com/ingressAllowedPrincipals"
+
+	IngressAuthRequired = "Required"
+	IngressAuthDisabled = "Disabled"
+)
+
+// GetIngressAuth returns the value of the ingress auth annotation of the Broker, or an empty
+// string if the Broker uses the policy of its BrokerCell.
+func (b *Broker) GetIngressAuth() string {
+	return b.GetAnnotations()[IngressAuthAnnotation]
+}
+
+// GetIngressAudience returns the audience the tokens sent to the Broker must be issued for, or an
+// empty string if it is the address of the Broker.
+func (b *Broker) GetIngressAudience() string {
+	return b.GetAnnotations()[IngressAudienceAnnotation]
+}
+
+// GetIngressAllowedPrincipals returns the principals allowed to send events to the Broker, or nil
+// if the Broker uses the allowed principals of its BrokerCell.
+func (b *Broker) GetIngressAllowedPrincipals() []string {
+	v, ok := b.GetAnnotations()[IngressAllowedPrincipalsAnnotation]
+	if !ok {
+		return nil
+	}
+	principals := []string{}
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			principals = append(principals, p)
+		}
+	}
+	return principals
+}
+
+func (b *Broker) validateIngressAuth(_ context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if v, ok := b.GetAnnotations()[IngressAuthAnnotation]; ok && v != IngressAuthRequired && v != IngressAuthDisabled {
+		errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", IngressAuthAnnotation)))
+	}
+	if v, ok := b.GetAnnotations()[IngressAudienceAnnotation]; ok && strings.TrimSpace(v) == "" {
+		errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", IngressAudienceAnnotation)))
+	}
+	return errs
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// OrderingKeyAnnotation is the annotation key used to enable ordered delivery for a Broker.
+	// The value is the name of the CloudEvent attribute, e.g. subject or a partitionkey
+	// extension, whose value is used as the Pub/Sub ordering key. Events with the same ordering
+	// key are delivered to each Trigger in the order they were accepted by the ingress.
+	OrderingKeyAnnotation = "events.cloud.google.com/orderingKey"
+)
+
+// attributeNameRegexp matches valid CloudEvent attribute names.
+var attributeNameRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// GetOrderingKeyAttribute returns the CloudEvent attribute used as the ordering key of the
+// Broker, or an empty string if ordered delivery is not enabled.
+func (b *Broker) GetOrderingKeyAttribute() string {
+	return b.GetAnnotations()[OrderingKeyAnnotation]
+}
+
+func (b *Broker) validateOrderingKey(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	path := fmt.Sprintf("metadata.annotations[%s]", OrderingKeyAnnotation)
+	if attr, ok := b.GetAnnotations()[OrderingKeyAnnotation]; ok && !attributeNameRegexp.MatchString(attr) {
+		errs = errs.Also(apis.ErrInvalidValue(attr, path))
+	}
+	if apis.IsInUpdate(ctx) {
+		// Pub/Sub subscriptions cannot enable or disable message ordering once created.
+		original := apis.GetBaseline(ctx).(*Broker)
+		if diff := cmp.Diff(original.GetOrderingKeyAttribute(), b.GetOrderingKeyAttribute()); diff != "" {
+			errs = errs.Also(&apis.FieldError{
+				Message: "Immutable fields changed (-old +new)",
+				Paths:   []string{path},
+				Details: diff,
+			})
+		}
+	}
+	return errs
+}
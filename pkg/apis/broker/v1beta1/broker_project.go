@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ProjectAnnotation is the annotation key of a Namespace used to create the Pub/Sub topics and
+// subscriptions of its Brokers, Triggers and Channels in a GCP project other than the project of
+// the BrokerCell. The value is a GCP project ID. It should be set before the first Broker is
+// created in the namespace: the resources already created in the previous project are not moved.
+const ProjectAnnotation = "events.cloud.google.com/project"
+
+// GetNamespaceProject returns the GCP project of the Pub/Sub resources of the Brokers in the
+// namespace, or an empty string if they are in the project of the BrokerCell.
+func GetNamespaceProject(ns *corev1.Namespace) string {
+	return ns.GetAnnotations()[ProjectAnnotation]
+}
+
+// ProjectID returns the GCP project the Pub/Sub resources of the Broker were created in, or an
+// empty string if they are not created yet. The project is recorded in the annotations of the
+// status, so that the resources are reconciled and deleted in the same project even if the
+// project annotation of the namespace changes.
+func (bs *BrokerStatus) ProjectID() string {
+	return bs.Annotations[ProjectAnnotation]
+}
+
+// SetProjectID records the GCP project the Pub/Sub resources of the Broker are created in.
+func (bs *BrokerStatus) SetProjectID(projectID string) {
+	if bs.Annotations == nil {
+		bs.Annotations = make(map[string]string)
+	}
+	bs.Annotations[ProjectAnnotation] = projectID
+}
+
+// ProjectID returns the GCP project the Pub/Sub resources of the Trigger were created in, or an
+// empty string if they are not created yet.
+func (ts *TriggerStatus) ProjectID() string {
+	return ts.Annotations[ProjectAnnotation]
+}
+
+// SetProjectID records the GCP project the Pub/Sub resources of the Trigger are created in.
+func (ts *TriggerStatus) SetProjectID(projectID string) {
+	if ts.Annotations == nil {
+		ts.Annotations = make(map[string]string)
+	}
+	ts.Annotations[ProjectAnnotation] = projectID
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// IngressEventsPerSecondAnnotation is the annotation key used to limit the number of events
+	// per second the ingress accepts for a Broker. The value is a positive integer.
+	IngressEventsPerSecondAnnotation = "events.cloud.google.com/ingressEventsPerSecond"
+
+	// IngressBytesPerSecondAnnotation is the annotation key used to limit the size of the data of
+	// the events per second the ingress accepts for a Broker. The value is a positive quantity,
+	// e.g. 1Mi.
+	IngressBytesPerSecondAnnotation = "events.cloud.google.com/ingressBytesPerSecond"
+)
+
+// GetIngressQuota returns the number of events and bytes per second the ingress accepts for the
+// Broker. A limit is zero if it is not set or the annotation is invalid.
+func (b *Broker) GetIngressQuota() (eventsPerSecond, bytesPerSecond int64) {
+	if v, ok := b.GetAnnotations()[IngressEventsPerSecondAnnotation]; ok {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			eventsPerSecond = n
+		}
+	}
+	if v, ok := b.GetAnnotations()[IngressBytesPerSecondAnnotation]; ok {
+		if q, err := resource.ParseQuantity(v); err == nil && q.Sign() > 0 {
+			bytesPerSecond = q.Value()
+		}
+	}
+	return eventsPerSecond, bytesPerSecond
+}
+
+func (b *Broker) validateIngressQuota(_ context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if v, ok := b.GetAnnotations()[IngressEventsPerSecondAnnotation]; ok {
+		if n, err := strconv.ParseInt(v, 10, 64); err != nil || n <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", IngressEventsPerSecondAnnotation)))
+		}
+	}
+	if v, ok := b.GetAnnotations()[IngressBytesPerSecondAnnotation]; ok {
+		if q, err := resource.ParseQuantity(v); err != nil || q.Sign() <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", IngressBytesPerSecondAnnotation)))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// MessageRetentionAnnotation is the annotation key used to retain the events accepted by a
+	// Broker so that they can be replayed to its Triggers. The value is a Go duration between
+	// MinMessageRetention and MaxMessageRetention, e.g. 24h.
+	MessageRetentionAnnotation = "events.cloud.google.com/messageRetention"
+
+	// MinMessageRetention is the minimum message retention supported by Pub/Sub.
+	MinMessageRetention = 10 * time.Minute
+	// MaxMessageRetention is the maximum message retention supported by Pub/Sub.
+	MaxMessageRetention = 7 * 24 * time.Hour
+)
+
+// GetMessageRetention returns how long the events accepted by the Broker are retained for
+// replay, or zero if retention is not enabled or the annotation is invalid.
+func (b *Broker) GetMessageRetention() time.Duration {
+	v, ok := b.GetAnnotations()[MessageRetentionAnnotation]
+	if !ok {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < MinMessageRetention || d > MaxMessageRetention {
+		return 0
+	}
+	return d
+}
+
+func (b *Broker) validateMessageRetention(_ context.Context) *apis.FieldError {
+	v, ok := b.GetAnnotations()[MessageRetentionAnnotation]
+	if !ok {
+		return nil
+	}
+	path := fmt.Sprintf("metadata.annotations[%s]", MessageRetentionAnnotation)
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return apis.ErrInvalidValue(v, path)
+	}
+	if d < MinMessageRetention || d > MaxMessageRetention {
+		return apis.ErrOutOfBoundsValue(v, MinMessageRetention.String(), MaxMessageRetention.String(), path)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// SchemaEnforcementAnnotation is the annotation key used to set how the
+	// ingress enforces the EventSchemas of the events sent to a Broker. The
+	// value is one of SchemaEnforcementOff, SchemaEnforcementWarn or
+	// SchemaEnforcementReject. Schemas are not enforced by default.
+	SchemaEnforcementAnnotation = "events.cloud.google.com/schemaEnforcement"
+
+	// SchemaEnforcementOff disables the validation of the events.
+	SchemaEnforcementOff = "off"
+	// SchemaEnforcementWarn logs and reports the events that don't match
+	// their schema, and accepts them.
+	SchemaEnforcementWarn = "warn"
+	// SchemaEnforcementReject rejects the events that don't match their
+	// schema.
+	SchemaEnforcementReject = "reject"
+)
+
+// GetSchemaEnforcement returns the schema enforcement of the Broker. It
+// returns SchemaEnforcementOff if the annotation is not set or invalid.
+func (b *Broker) GetSchemaEnforcement() string {
+	switch v := b.GetAnnotations()[SchemaEnforcementAnnotation]; v {
+	case SchemaEnforcementWarn, SchemaEnforcementReject:
+		return v
+	default:
+		return SchemaEnforcementOff
+	}
+}
+
+func (b *Broker) validateSchemaEnforcement(_ context.Context) *apis.FieldError {
+	v, ok := b.GetAnnotations()[SchemaEnforcementAnnotation]
+	if !ok {
+		return nil
+	}
+	switch v {
+	case SchemaEnforcementOff, SchemaEnforcementWarn, SchemaEnforcementReject:
+		return nil
+	default:
+		return apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", SchemaEnforcementAnnotation))
+	}
+}
@@ -29,7 +29,8 @@ func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
 	// We validate the GCP Broker's delivery spec. The eventing webhook will run
 	// the other usual validations.
 	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, b.ObjectMeta))
-	return ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery")
+	errs := ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery")
+	return errs.Also(b.validateOrderingKey(ctx)).Also(b.validateMessageRetention(ctx)).Also(b.validateIngressAuth(ctx)).Also(b.validateDedupWindow(ctx)).Also(b.validateSchemaEnforcement(ctx)).Also(b.validateIngressQuota(ctx))
 }
 
 func ValidateDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *apis.FieldError {
@@ -49,22 +50,42 @@ func ValidateDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.Deliver
 	return errs.Also(ValidateDeadLetterSink(ctx, spec.DeadLetterSink).ViaField("deadLetterSink"))
 }
 
+// PubSubDeadLetterSinkScheme is the URI scheme of dead letter sinks that are Pub/Sub topics.
+const PubSubDeadLetterSinkScheme = "pubsub"
+
+// IsPubSubDeadLetterSink returns true if the dead letter sink is an explicit Pub/Sub topic
+// reference, e.g. pubsub://my-topic. Any other dead letter sink is an addressable or an
+// HTTP(S) URI that events are POSTed to.
+func IsPubSubDeadLetterSink(sink *duckv1.Destination) bool {
+	return sink != nil && sink.Ref == nil && sink.URI != nil && sink.URI.Scheme == PubSubDeadLetterSinkScheme
+}
+
 func ValidateDeadLetterSink(ctx context.Context, sink *duckv1.Destination) *apis.FieldError {
 	if sink == nil {
 		return nil
 	}
+	if sink.Ref != nil {
+		// The addressable is resolved by the controller.
+		return sink.Validate(ctx)
+	}
 	if sink.URI == nil {
 		return apis.ErrMissingField("uri")
 	}
-	if scheme := sink.URI.Scheme; scheme != "pubsub" {
-		return apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub", "uri")
-	}
-	topicID := sink.URI.Host
-	if topicID == "" {
-		return apis.ErrInvalidValue("Dead letter topic must not be empty", "uri")
-	}
-	if len(topicID) > 255 {
-		return apis.ErrInvalidValue("Dead letter topic maximum length is 255 characters", "uri")
+	switch sink.URI.Scheme {
+	case PubSubDeadLetterSinkScheme:
+		topicID := sink.URI.Host
+		if topicID == "" {
+			return apis.ErrInvalidValue("Dead letter topic must not be empty", "uri")
+		}
+		if len(topicID) > 255 {
+			return apis.ErrInvalidValue("Dead letter topic maximum length is 255 characters", "uri")
+		}
+	case "http", "https":
+		if sink.URI.Host == "" {
+			return apis.ErrInvalidValue("Dead letter sink URI host must not be empty", "uri")
+		}
+	default:
+		return apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub, http or https", "uri")
 	}
 	return nil
 }
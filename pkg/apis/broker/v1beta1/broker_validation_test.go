@@ -20,14 +20,301 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+func TestBroker_ValidateOrderingKey(t *testing.T) {
+	withOrderingKey := func(attr string) *Broker {
+		return &Broker{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{OrderingKeyAnnotation: attr},
+		}}
+	}
+	tests := []struct {
+		name     string
+		broker   *Broker
+		original *Broker
+		wantErr  bool
+	}{{
+		name:   "valid ordering key",
+		broker: withOrderingKey("partitionkey"),
+	}, {
+		name:    "invalid ordering key",
+		broker:  withOrderingKey("Partition-Key"),
+		wantErr: true,
+	}, {
+		name:     "unchanged ordering key",
+		broker:   withOrderingKey("subject"),
+		original: withOrderingKey("subject"),
+	}, {
+		name:     "changed ordering key",
+		broker:   withOrderingKey("subject"),
+		original: withOrderingKey("partitionkey"),
+		wantErr:  true,
+	}, {
+		name:     "added ordering key",
+		broker:   withOrderingKey("subject"),
+		original: &Broker{},
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.original != nil {
+				ctx = apis.WithinUpdate(ctx, test.original)
+			}
+			if got := test.broker.Validate(ctx); (got != nil) != test.wantErr {
+				t.Errorf("Validate got=%v, wantErr=%v", got, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestBroker_ValidateMessageRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention string
+		want      time.Duration
+		wantErr   bool
+	}{{
+		name:      "valid retention",
+		retention: "24h",
+		want:      24 * time.Hour,
+	}, {
+		name:      "minimum retention",
+		retention: "10m",
+		want:      10 * time.Minute,
+	}, {
+		name:      "maximum retention",
+		retention: "168h",
+		want:      168 * time.Hour,
+	}, {
+		name:      "invalid retention",
+		retention: "1d",
+		wantErr:   true,
+	}, {
+		name:      "retention too short",
+		retention: "5m",
+		wantErr:   true,
+	}, {
+		name:      "retention too long",
+		retention: "169h",
+		wantErr:   true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{MessageRetentionAnnotation: test.retention},
+			}}
+			if got := b.Validate(context.Background()); (got != nil) != test.wantErr {
+				t.Errorf("Validate got=%v, wantErr=%v", got, test.wantErr)
+			}
+			if got := b.GetMessageRetention(); got != test.want {
+				t.Errorf("GetMessageRetention got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBroker_ValidateDedupWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  string
+		want    time.Duration
+		wantErr bool
+	}{{
+		name:   "valid window",
+		window: "10m",
+		want:   10 * time.Minute,
+	}, {
+		name:   "minimum window",
+		window: "1s",
+		want:   time.Second,
+	}, {
+		name:   "maximum window",
+		window: "24h",
+		want:   24 * time.Hour,
+	}, {
+		name:    "invalid window",
+		window:  "ten minutes",
+		wantErr: true,
+	}, {
+		name:    "window too short",
+		window:  "500ms",
+		wantErr: true,
+	}, {
+		name:    "window too long",
+		window:  "25h",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{DedupWindowAnnotation: test.window},
+			}}
+			if got := b.Validate(context.Background()); (got != nil) != test.wantErr {
+				t.Errorf("Validate got=%v, wantErr=%v", got, test.wantErr)
+			}
+			if got := b.GetDedupWindow(); got != test.want {
+				t.Errorf("GetDedupWindow got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBroker_ValidateIngressQuota(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantEvents  int64
+		wantBytes   int64
+		wantErr     bool
+	}{{
+		name:        "no quota",
+		annotations: map[string]string{},
+	}, {
+		name: "valid quota",
+		annotations: map[string]string{
+			IngressEventsPerSecondAnnotation: "100",
+			IngressBytesPerSecondAnnotation:  "1Mi",
+		},
+		wantEvents: 100,
+		wantBytes:  1 << 20,
+	}, {
+		name:        "invalid events per second",
+		annotations: map[string]string{IngressEventsPerSecondAnnotation: "lots"},
+		wantErr:     true,
+	}, {
+		name:        "zero events per second",
+		annotations: map[string]string{IngressEventsPerSecondAnnotation: "0"},
+		wantErr:     true,
+	}, {
+		name:        "invalid bytes per second",
+		annotations: map[string]string{IngressBytesPerSecondAnnotation: "1 megabyte"},
+		wantErr:     true,
+	}, {
+		name:        "negative bytes per second",
+		annotations: map[string]string{IngressBytesPerSecondAnnotation: "-1Ki"},
+		wantErr:     true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := b.Validate(context.Background()); (got != nil) != test.wantErr {
+				t.Errorf("Validate got=%v, wantErr=%v", got, test.wantErr)
+			}
+			events, bytes := b.GetIngressQuota()
+			if events != test.wantEvents || bytes != test.wantBytes {
+				t.Errorf("GetIngressQuota got=(%v, %v), want=(%v, %v)", events, bytes, test.wantEvents, test.wantBytes)
+			}
+		})
+	}
+}
+
+func TestBroker_ValidateSchemaEnforcement(t *testing.T) {
+	tests := []struct {
+		name        string
+		enforcement string
+		want        string
+		wantErr     bool
+	}{{
+		name:        "off",
+		enforcement: "off",
+		want:        SchemaEnforcementOff,
+	}, {
+		name:        "warn",
+		enforcement: "warn",
+		want:        SchemaEnforcementWarn,
+	}, {
+		name:        "reject",
+		enforcement: "reject",
+		want:        SchemaEnforcementReject,
+	}, {
+		name:        "invalid",
+		enforcement: "Reject",
+		want:        SchemaEnforcementOff,
+		wantErr:     true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{SchemaEnforcementAnnotation: test.enforcement},
+			}}
+			if got := b.Validate(context.Background()); (got != nil) != test.wantErr {
+				t.Errorf("Validate got=%v, wantErr=%v", got, test.wantErr)
+			}
+			if got := b.GetSchemaEnforcement(); got != test.want {
+				t.Errorf("GetSchemaEnforcement got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBroker_ValidateIngressAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		wantPrincipals []string
+		wantErr        bool
+	}{{
+		name: "no annotations",
+	}, {
+		name: "required with audience and principals",
+		annotations: map[string]string{
+			IngressAuthAnnotation:              IngressAuthRequired,
+			IngressAudienceAnnotation:          "my-broker",
+			IngressAllowedPrincipalsAnnotation: "system:serviceaccount:ns:sa, sa@project.iam.gserviceaccount.com,",
+		},
+		wantPrincipals: []string{"system:serviceaccount:ns:sa", "sa@project.iam.gserviceaccount.com"},
+	}, {
+		name: "disabled",
+		annotations: map[string]string{
+			IngressAuthAnnotation: IngressAuthDisabled,
+		},
+	}, {
+		name: "empty allowed principals",
+		annotations: map[string]string{
+			IngressAllowedPrincipalsAnnotation: "",
+		},
+		wantPrincipals: []string{},
+	}, {
+		name: "invalid mode",
+		annotations: map[string]string{
+			IngressAuthAnnotation: "true",
+		},
+		wantErr: true,
+	}, {
+		name: "empty audience",
+		annotations: map[string]string{
+			IngressAudienceAnnotation: " ",
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := b.Validate(context.Background()); (got != nil) != test.wantErr {
+				t.Errorf("Validate got=%v, wantErr=%v", got, test.wantErr)
+			}
+			if diff := cmp.Diff(test.wantPrincipals, b.GetIngressAllowedPrincipals()); diff != "" {
+				t.Errorf("GetIngressAllowedPrincipals (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
 func TestBroker_Validate(t *testing.T) {
 	bop := eventingduckv1beta1.BackoffPolicyExponential
 	bod := "PT1S"
@@ -94,14 +381,64 @@ func TestBroker_Validate(t *testing.T) {
 					BackoffPolicy: &bop,
 					DeadLetterSink: &duckv1.Destination{
 						URI: &apis.URL{
-							Scheme: "http",
+							Scheme: "ftp",
 							Host:   "test-topic-id",
 						},
 					},
 				},
 			},
 		},
-		want: apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub", "spec.delivery.deadLetterSink.uri"),
+		want: apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub, http or https", "spec.delivery.deadLetterSink.uri"),
+	}, {
+		name: "invalid empty dead letter sink host",
+		broker: Broker{
+			Spec: v1beta1.BrokerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &bod,
+					BackoffPolicy: &bop,
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{
+							Scheme: "https",
+						},
+					},
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("Dead letter sink URI host must not be empty", "spec.delivery.deadLetterSink.uri"),
+	}, {
+		name: "valid http dead letter sink",
+		broker: Broker{
+			Spec: v1beta1.BrokerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &bod,
+					BackoffPolicy: &bop,
+					Retry:         &retry,
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{
+							Scheme: "http",
+							Host:   "dead-letter.ns.svc.cluster.local",
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "valid addressable dead letter sink",
+		broker: Broker{
+			Spec: v1beta1.BrokerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &bod,
+					BackoffPolicy: &bop,
+					DeadLetterSink: &duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "serving.knative.dev/v1",
+							Kind:       "Service",
+							Name:       "dead-letter",
+						},
+					},
+				},
+			},
+		},
 	}, {
 		name: "invalid empty dead letter topic id",
 		broker: Broker{
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventSchema is the schema of the data of the events of a type sent to the
+// Brokers of its namespace. The Broker ingress validates the events against
+// it, depending on the schema enforcement of the Broker, see
+// SchemaEnforcementAnnotation.
+type EventSchema struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the schema.
+	Spec EventSchemaSpec `json:"spec,omitempty"`
+}
+
+var (
+	// Check that EventSchema can be validated and defaulted.
+	_ apis.Validatable = (*EventSchema)(nil)
+	_ apis.Defaultable = (*EventSchema)(nil)
+
+	// Check that EventSchema can return its spec untyped.
+	_ apis.HasSpec = (*EventSchema)(nil)
+
+	_ runtime.Object = (*EventSchema)(nil)
+)
+
+// EventSchemaSpec defines the schema of the events of a type.
+type EventSchemaSpec struct {
+	// Type is the CloudEvents type of the events the schema applies to.
+	Type string `json:"type"`
+
+	// Broker is the name of the Broker, in the namespace of the EventSchema,
+	// the schema applies to. If empty, the schema applies to all the Brokers
+	// of the namespace. The schema of a Broker takes precedence over the
+	// schema of its namespace for the same type.
+	// +optional
+	Broker string `json:"broker,omitempty"`
+
+	// JSONSchema is a JSON Schema (draft-07) document the data of the events
+	// must match.
+	// +optional
+	JSONSchema *runtime.RawExtension `json:"jsonSchema,omitempty"`
+
+	// Proto is the proto message the data of the events must be an encoding
+	// of.
+	// +optional
+	Proto *ProtoSchema `json:"proto,omitempty"`
+}
+
+// ProtoSchema is a proto message of a file descriptor set.
+type ProtoSchema struct {
+	// FileDescriptorSet is a serialized google.protobuf.FileDescriptorSet,
+	// including the imported files, e.g. generated with
+	// protoc --include_imports --descriptor_set_out.
+	FileDescriptorSet []byte `json:"fileDescriptorSet"`
+
+	// Message is the full name of the message, e.g. example.v1.Order.
+	Message string `json:"message"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventSchemaList is a collection of EventSchemas.
+type EventSchemaList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []EventSchema `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for EventSchemas.
+func (s *EventSchema) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("EventSchema")
+}
+
+// GetUntypedSpec returns the spec of the EventSchema.
+func (s *EventSchema) GetUntypedSpec() interface{} {
+	return s.Spec
+}
+
+// AppliesTo returns true if the schema applies to the events sent to the
+// Broker.
+func (s *EventSchema) AppliesTo(b *Broker) bool {
+	return s.Namespace == b.Namespace && (s.Spec.Broker == "" || s.Spec.Broker == b.Name)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/broker/eventschema"
+)
+
+// Validate verifies that the EventSchema is valid.
+func (s *EventSchema) Validate(ctx context.Context) *apis.FieldError {
+	return s.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate verifies that the EventSchemaSpec is valid and that its schemas
+// compile.
+func (ss *EventSchemaSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if ss.Type == "" {
+		errs = errs.Also(apis.ErrMissingField("type"))
+	}
+	if ss.JSONSchema == nil && ss.Proto == nil {
+		return errs.Also(apis.ErrMissingOneOf("jsonSchema", "proto"))
+	}
+	if ss.JSONSchema != nil {
+		if _, err := eventschema.New(ss.JSONSchema.Raw, nil, ""); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "jsonSchema"))
+		}
+	}
+	if ss.Proto != nil {
+		if len(ss.Proto.FileDescriptorSet) == 0 {
+			errs = errs.Also(apis.ErrMissingField("proto.fileDescriptorSet"))
+		}
+		if ss.Proto.Message == "" {
+			errs = errs.Also(apis.ErrMissingField("proto.message"))
+		}
+		if len(ss.Proto.FileDescriptorSet) > 0 && ss.Proto.Message != "" {
+			if _, err := eventschema.New(nil, ss.Proto.FileDescriptorSet, ss.Proto.Message); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(err.Error(), "proto"))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+)
+
+func TestEventSchema_Validate(t *testing.T) {
+	fds, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:        proto.String("order.proto"),
+			Package:     proto.String("example"),
+			MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Order")}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonSchema := &runtime.RawExtension{Raw: []byte(`{"type": "object", "required": ["id"]}`)}
+
+	tests := []struct {
+		name string
+		spec EventSchemaSpec
+		want *apis.FieldError
+		// wantPrefix and wantPath check the errors that wrap the errors of the protobuf
+		// module, whose messages are deliberately unstable.
+		wantPrefix string
+		wantPath   string
+	}{{
+		name: "json schema",
+		spec: EventSchemaSpec{Type: "example.order", JSONSchema: jsonSchema},
+	}, {
+		name: "proto",
+		spec: EventSchemaSpec{Type: "example.order", Broker: "default", Proto: &ProtoSchema{FileDescriptorSet: fds, Message: "example.Order"}},
+	}, {
+		name: "missing type",
+		spec: EventSchemaSpec{JSONSchema: jsonSchema},
+		want: apis.ErrMissingField("spec.type"),
+	}, {
+		name: "missing schema",
+		spec: EventSchemaSpec{Type: "example.order"},
+		want: apis.ErrMissingOneOf("spec.jsonSchema", "spec.proto"),
+	}, {
+		name: "invalid json schema",
+		spec: EventSchemaSpec{Type: "example.order", JSONSchema: &runtime.RawExtension{Raw: []byte(`{"required": "id"}`)}},
+		want: apis.ErrInvalidValue("invalid JSON Schema: /required: expected array, but got string", "spec.jsonSchema"),
+	}, {
+		name: "missing proto fields",
+		spec: EventSchemaSpec{Type: "example.order", Proto: &ProtoSchema{}},
+		want: apis.ErrMissingField("spec.proto.fileDescriptorSet", "spec.proto.message"),
+	}, {
+		name:       "unknown proto message",
+		spec:       EventSchemaSpec{Type: "example.order", Proto: &ProtoSchema{FileDescriptorSet: fds, Message: "example.Missing"}},
+		wantPrefix: `invalid value: invalid proto descriptor: message "example.Missing": `,
+		wantPath:   "spec.proto",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &EventSchema{Spec: test.spec}
+			got := s.Validate(context.Background())
+			if test.wantPath != "" {
+				if got == nil || !strings.HasPrefix(got.Error(), test.wantPrefix) || !strings.HasSuffix(got.Error(), ": "+test.wantPath) {
+					t.Errorf("Validate() = %v, want %s...: %s", got, test.wantPrefix, test.wantPath)
+				}
+				return
+			}
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestEventSchema_AppliesTo(t *testing.T) {
+	b := &Broker{}
+	b.Namespace, b.Name = "ns", "default"
+	tests := []struct {
+		name      string
+		namespace string
+		broker    string
+		want      bool
+	}{
+		{name: "namespace", namespace: "ns", want: true},
+		{name: "broker", namespace: "ns", broker: "default", want: true},
+		{name: "other broker", namespace: "ns", broker: "other"},
+		{name: "other namespace", namespace: "other"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &EventSchema{Spec: EventSchemaSpec{Broker: test.broker}}
+			s.Namespace = test.namespace
+			if got := s.AppliesTo(b); got != test.want {
+				t.Errorf("AppliesTo got=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
@@ -48,6 +48,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BrokerList{},
 		&Trigger{},
 		&TriggerList{},
+		&EventSchema{},
+		&EventSchemaList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -57,6 +57,8 @@ func TestAddKnownTypes(t *testing.T) {
 		"BrokerList",
 		"Trigger",
 		"TriggerList",
+		"EventSchema",
+		"EventSchemaList",
 	}
 	got := scheme.KnownTypes(schema.GroupVersion{Group: "eventing.knative.dev", Version: "v1beta1"})
 
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rickb777/date/period"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// BatchMaxEventsAnnotation is the annotation key used to enable batched delivery for a
+	// Trigger. The value is the maximum number of events sent to the subscriber in a single
+	// application/cloudevents-batch+json request.
+	BatchMaxEventsAnnotation = "events.cloud.google.com/batchMaxEvents"
+
+	// BatchMaxDelayAnnotation is the annotation key used to specify the maximum time, as an
+	// ISO-8601 duration, a batch waits for more events before it is sent. Only used together
+	// with BatchMaxEventsAnnotation.
+	BatchMaxDelayAnnotation = "events.cloud.google.com/batchMaxDelay"
+
+	// DefaultBatchMaxDelay is used when batched delivery is enabled without a maximum delay.
+	DefaultBatchMaxDelay = time.Second
+
+	// maxBatchMaxEvents and maxBatchMaxDelay bound the batch settings, so that batched events
+	// are not held by the fanout for longer than their delivery timeout.
+	maxBatchMaxEvents = 1000
+	maxBatchMaxDelay  = time.Minute
+)
+
+// GetBatching parses the batch settings from the Trigger's BatchMaxEventsAnnotation and
+// BatchMaxDelayAnnotation. It returns zero values if batched delivery is not enabled.
+func (t *Trigger) GetBatching() (int32, time.Duration, error) {
+	rawEvents, ok := t.GetAnnotations()[BatchMaxEventsAnnotation]
+	if !ok {
+		return 0, 0, nil
+	}
+	maxEvents, err := strconv.ParseInt(rawEvents, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse %s annotation: %w", BatchMaxEventsAnnotation, err)
+	}
+	maxDelay := DefaultBatchMaxDelay
+	if rawDelay, ok := t.GetAnnotations()[BatchMaxDelayAnnotation]; ok {
+		p, err := period.Parse(rawDelay)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse %s annotation: %w", BatchMaxDelayAnnotation, err)
+		}
+		maxDelay, _ = p.Duration()
+	}
+	return int32(maxEvents), maxDelay, nil
+}
+
+func (t *Trigger) validateBatching() *apis.FieldError {
+	_, hasDelay := t.GetAnnotations()[BatchMaxDelayAnnotation]
+	if _, ok := t.GetAnnotations()[BatchMaxEventsAnnotation]; !ok {
+		if hasDelay {
+			return apis.ErrGeneric(fmt.Sprintf("%s requires %s", BatchMaxDelayAnnotation, BatchMaxEventsAnnotation), BatchMaxDelayAnnotation)
+		}
+		return nil
+	}
+	maxEvents, maxDelay, err := t.GetBatching()
+	if err != nil {
+		return apis.ErrInvalidValue(err.Error(), BatchMaxEventsAnnotation)
+	}
+	var errs *apis.FieldError
+	if maxEvents < 1 || maxEvents > maxBatchMaxEvents {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(maxEvents, 1, maxBatchMaxEvents, BatchMaxEventsAnnotation))
+	}
+	if maxDelay <= 0 || maxDelay > maxBatchMaxDelay {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(maxDelay, 0, maxBatchMaxDelay, BatchMaxDelayAnnotation))
+	}
+	return errs
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// FiltersAnnotation is the annotation key used to specify advanced filter expressions for a
+	// Trigger. The value is a JSON list of SubscriptionsAPIFilter. An event must pass
+	// spec.filter.attributes and every filter in the list to be delivered.
+	FiltersAnnotation = "events.cloud.google.com/filters"
+)
+
+// SubscriptionsAPIFilter is a filter expression in the shape of the Knative "new trigger
+// filters". Exactly one of the fields must be set.
+type SubscriptionsAPIFilter struct {
+	// All evaluates to true if all the nested filters evaluate to true.
+	// +optional
+	All []SubscriptionsAPIFilter `json:"all,omitempty"`
+
+	// Any evaluates to true if at least one of the nested filters evaluates to true.
+	// +optional
+	Any []SubscriptionsAPIFilter `json:"any,omitempty"`
+
+	// Not evaluates to true if the nested filter evaluates to false.
+	// +optional
+	Not *SubscriptionsAPIFilter `json:"not,omitempty"`
+
+	// Exact evaluates to true if the values of all the given attributes exactly match the
+	// given values.
+	// +optional
+	Exact map[string]string `json:"exact,omitempty"`
+
+	// Prefix evaluates to true if the values of all the given attributes start with the given
+	// values.
+	// +optional
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Suffix evaluates to true if the values of all the given attributes end with the given
+	// values.
+	// +optional
+	Suffix map[string]string `json:"suffix,omitempty"`
+
+	// AnyOf evaluates to true if, for each of the given attributes, the attribute value equals
+	// any of the given values.
+	// +optional
+	AnyOf map[string][]string `json:"anyOf,omitempty"`
+}
+
+// GetFilters parses the advanced filters from the Trigger's FiltersAnnotation. It returns nil
+// if the annotation is not set.
+func (t *Trigger) GetFilters() ([]SubscriptionsAPIFilter, error) {
+	raw, ok := t.GetAnnotations()[FiltersAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var filters []SubscriptionsAPIFilter
+	if err := json.Unmarshal([]byte(raw), &filters); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", FiltersAnnotation, err)
+	}
+	return filters, nil
+}
+
+// Validate verifies that the filter has exactly one expression set and that the expression is
+// well formed.
+func (f *SubscriptionsAPIFilter) Validate() *apis.FieldError {
+	var set []string
+	if f.All != nil {
+		set = append(set, "all")
+	}
+	if f.Any != nil {
+		set = append(set, "any")
+	}
+	if f.Not != nil {
+		set = append(set, "not")
+	}
+	if f.Exact != nil {
+		set = append(set, "exact")
+	}
+	if f.Prefix != nil {
+		set = append(set, "prefix")
+	}
+	if f.Suffix != nil {
+		set = append(set, "suffix")
+	}
+	if f.AnyOf != nil {
+		set = append(set, "anyOf")
+	}
+	switch len(set) {
+	case 0:
+		return apis.ErrMissingOneOf("all", "any", "not", "exact", "prefix", "suffix", "anyOf")
+	case 1:
+	default:
+		return apis.ErrMultipleOneOf(set...)
+	}
+
+	var errs *apis.FieldError
+	switch {
+	case f.All != nil:
+		errs = validateFilterList(f.All).ViaField("all")
+	case f.Any != nil:
+		errs = validateFilterList(f.Any).ViaField("any")
+	case f.Not != nil:
+		errs = f.Not.Validate().ViaField("not")
+	case f.Exact != nil:
+		errs = validateFilterAttributes(f.Exact, false).ViaField("exact")
+	case f.Prefix != nil:
+		errs = validateFilterAttributes(f.Prefix, true).ViaField("prefix")
+	case f.Suffix != nil:
+		errs = validateFilterAttributes(f.Suffix, true).ViaField("suffix")
+	case f.AnyOf != nil:
+		if len(f.AnyOf) == 0 {
+			return apis.ErrMissingField("anyOf")
+		}
+		for k, v := range f.AnyOf {
+			if k == "" {
+				errs = errs.Also(apis.ErrInvalidKeyName(k, "anyOf", "attribute name must not be empty"))
+			}
+			if len(v) == 0 {
+				errs = errs.Also(apis.ErrMissingField(k).ViaField("anyOf"))
+			}
+		}
+	}
+	return errs
+}
+
+func validateFilterList(filters []SubscriptionsAPIFilter) *apis.FieldError {
+	if len(filters) == 0 {
+		return apis.ErrGeneric("expected at least one filter")
+	}
+	var errs *apis.FieldError
+	for i := range filters {
+		errs = errs.Also(filters[i].Validate().ViaIndex(i))
+	}
+	return errs
+}
+
+func validateFilterAttributes(attrs map[string]string, requireValue bool) *apis.FieldError {
+	if len(attrs) == 0 {
+		return apis.ErrGeneric("expected at least one attribute")
+	}
+	var errs *apis.FieldError
+	for k, v := range attrs {
+		if k == "" {
+			errs = errs.Also(apis.ErrInvalidKeyName(k, apis.CurrentField, "attribute name must not be empty"))
+		}
+		if requireValue && v == "" {
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		}
+	}
+	return errs
+}
@@ -29,11 +29,20 @@ var triggerCondSet = apis.NewLivingConditionSet(
 	eventingv1beta1.TriggerConditionSubscriberResolved,
 	TriggerConditionTopic,
 	TriggerConditionSubscription,
+	TriggerConditionDeadLetterSinkResolved,
 )
 
 const (
 	TriggerConditionTopic        apis.ConditionType = "TopicReady"
 	TriggerConditionSubscription apis.ConditionType = "SubscriptionReady"
+
+	// TriggerConditionDeadLetterSinkResolved reports whether the dead letter sink of the
+	// Broker's delivery spec could be resolved for this Trigger.
+	TriggerConditionDeadLetterSinkResolved apis.ConditionType = "DeadLetterSinkResolved"
+
+	// TriggerConditionSubscriberAvailable reports whether the circuit breaker of the Trigger's
+	// subscriber is open in the data plane. It is informational and does not affect readiness.
+	TriggerConditionSubscriberAvailable apis.ConditionType = "SubscriberAvailable"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -124,6 +133,29 @@ func (ts *TriggerStatus) MarkSubscriberResolvedUnknown(reason, messageFormat str
 	triggerCondSet.Manage(ts).MarkUnknown(eventingv1beta1.TriggerConditionSubscriberResolved, reason, messageFormat, messageA...)
 }
 
+func (ts *TriggerStatus) MarkDeadLetterSinkResolvedSucceeded() {
+	triggerCondSet.Manage(ts).MarkTrue(TriggerConditionDeadLetterSinkResolved)
+}
+
+func (ts *TriggerStatus) MarkDeadLetterSinkNotConfigured() {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionDeadLetterSinkResolved,
+		"DeadLetterSinkNotConfigured", "No dead letter sink is configured.")
+}
+
+func (ts *TriggerStatus) MarkDeadLetterSinkResolvedFailed(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionDeadLetterSinkResolved, reason, messageFormat, messageA...)
+}
+
+func (ts *TriggerStatus) MarkSubscriberUnavailable(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionSubscriberAvailable, reason, messageFormat, messageA...)
+}
+
+// ClearSubscriberAvailable removes the SubscriberAvailable condition once the subscriber's
+// circuit breakers are closed again.
+func (ts *TriggerStatus) ClearSubscriberAvailable() {
+	_ = triggerCondSet.Manage(ts).ClearCondition(TriggerConditionSubscriberAvailable)
+}
+
 func (ts *TriggerStatus) MarkDependencySucceeded() {
 	triggerCondSet.Manage(ts).MarkTrue(eventingv1beta1.TriggerConditionDependency)
 }
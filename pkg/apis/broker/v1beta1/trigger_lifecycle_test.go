@@ -147,6 +147,9 @@ func TestTriggerInitializeConditions(t *testing.T) {
 					Conditions: []apis.Condition{{
 						Type:   eventingv1beta1.TriggerConditionBroker,
 						Status: corev1.ConditionUnknown,
+					}, {
+						Type:   TriggerConditionDeadLetterSinkResolved,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.TriggerConditionDependency,
 						Status: corev1.ConditionUnknown,
@@ -184,6 +187,9 @@ func TestTriggerInitializeConditions(t *testing.T) {
 					Conditions: []apis.Condition{{
 						Type:   eventingv1beta1.TriggerConditionBroker,
 						Status: corev1.ConditionFalse,
+					}, {
+						Type:   TriggerConditionDeadLetterSinkResolved,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.TriggerConditionDependency,
 						Status: corev1.ConditionUnknown,
@@ -221,6 +227,9 @@ func TestTriggerInitializeConditions(t *testing.T) {
 					Conditions: []apis.Condition{{
 						Type:   eventingv1beta1.TriggerConditionBroker,
 						Status: corev1.ConditionTrue,
+					}, {
+						Type:   TriggerConditionDeadLetterSinkResolved,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.TriggerConditionDependency,
 						Status: corev1.ConditionUnknown,
@@ -264,7 +273,9 @@ func TestTriggerConditionStatus(t *testing.T) {
 		subscriptionStatus       corev1.ConditionStatus
 		subscriberResolvedStatus corev1.ConditionStatus
 		dependencyStatus         *duckv1.Source
-		wantConditionStatus      corev1.ConditionStatus
+		// deadLetterSinkStatus is not configured if empty.
+		deadLetterSinkStatus corev1.ConditionStatus
+		wantConditionStatus  corev1.ConditionStatus
 	}{{
 		name:                     "all happy",
 		brokerStatus:             TestHelper.ReadyBrokerStatus(),
@@ -345,6 +356,22 @@ func TestTriggerConditionStatus(t *testing.T) {
 		subscriberResolvedStatus: corev1.ConditionUnknown,
 		dependencyStatus:         TestHelper.ReadyDependencyStatus(),
 		wantConditionStatus:      corev1.ConditionUnknown,
+	}, {
+		name:                     "dead letter sink resolved",
+		brokerStatus:             TestHelper.ReadyBrokerStatus(),
+		subscriptionStatus:       corev1.ConditionTrue,
+		topicStatus:              corev1.ConditionTrue,
+		subscriberResolvedStatus: corev1.ConditionTrue,
+		deadLetterSinkStatus:     corev1.ConditionTrue,
+		wantConditionStatus:      corev1.ConditionTrue,
+	}, {
+		name:                     "failed to resolve dead letter sink",
+		brokerStatus:             TestHelper.ReadyBrokerStatus(),
+		subscriptionStatus:       corev1.ConditionTrue,
+		topicStatus:              corev1.ConditionTrue,
+		subscriberResolvedStatus: corev1.ConditionTrue,
+		deadLetterSinkStatus:     corev1.ConditionFalse,
+		wantConditionStatus:      corev1.ConditionFalse,
 	}, {
 		name:                     "dependency unconfigured",
 		brokerStatus:             TestHelper.ReadyBrokerStatus(),
@@ -405,6 +432,14 @@ func TestTriggerConditionStatus(t *testing.T) {
 			} else {
 				ts.MarkSubscriberResolvedUnknown("Status of Subscriber URI is unknown", "induced failure")
 			}
+			switch test.deadLetterSinkStatus {
+			case corev1.ConditionTrue:
+				ts.MarkDeadLetterSinkResolvedSucceeded()
+			case corev1.ConditionFalse:
+				ts.MarkDeadLetterSinkResolvedFailed("DeadLetterSinkResolveFailed", "dead letter sink not found")
+			default:
+				ts.MarkDeadLetterSinkNotConfigured()
+			}
 			if test.dependencyStatus == nil {
 				ts.MarkDependencySucceeded()
 			} else {
@@ -428,3 +463,31 @@ func TestTriggerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestTriggerSubscriberAvailable(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady("")
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDeadLetterSinkNotConfigured()
+	ts.MarkDependencySucceeded()
+
+	ts.MarkSubscriberUnavailable("CircuitBreakerOpen", "induced failure")
+	cond := ts.GetCondition(TriggerConditionSubscriberAvailable)
+	if cond == nil || cond.Status != corev1.ConditionFalse || cond.Severity != apis.ConditionSeverityInfo {
+		t.Errorf("unexpected SubscriberAvailable condition: %+v", cond)
+	}
+	if !ts.IsReady() {
+		t.Error("expected the Trigger to stay ready with an unavailable subscriber")
+	}
+
+	ts.ClearSubscriberAvailable()
+	if cond := ts.GetCondition(TriggerConditionSubscriberAvailable); cond != nil {
+		t.Errorf("expected no SubscriberAvailable condition, got %+v", cond)
+	}
+	if !ts.IsReady() {
+		t.Error("expected the Trigger to be ready")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// RateLimitAnnotation is the annotation key used to limit the rate, in events per second,
+	// at which each fanout and retry pod delivers events to the Trigger's subscriber.
+	RateLimitAnnotation = "events.cloud.google.com/rateLimit"
+
+	// MaxConcurrencyAnnotation is the annotation key used to limit the number of events each
+	// fanout and retry pod delivers to the Trigger's subscriber at the same time.
+	MaxConcurrencyAnnotation = "events.cloud.google.com/maxConcurrency"
+)
+
+// GetLimits parses the rate limit and maximum concurrency from the Trigger's
+// RateLimitAnnotation and MaxConcurrencyAnnotation. Zero values mean no limit.
+func (t *Trigger) GetLimits() (float64, int32, error) {
+	var rateLimit float64
+	if raw, ok := t.GetAnnotations()[RateLimitAnnotation]; ok {
+		var err error
+		if rateLimit, err = strconv.ParseFloat(raw, 64); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse %s annotation: %w", RateLimitAnnotation, err)
+		}
+	}
+	var maxConcurrency int64
+	if raw, ok := t.GetAnnotations()[MaxConcurrencyAnnotation]; ok {
+		var err error
+		if maxConcurrency, err = strconv.ParseInt(raw, 10, 32); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse %s annotation: %w", MaxConcurrencyAnnotation, err)
+		}
+	}
+	return rateLimit, int32(maxConcurrency), nil
+}
+
+func (t *Trigger) validateLimits() *apis.FieldError {
+	var errs *apis.FieldError
+	if raw, ok := t.GetAnnotations()[RateLimitAnnotation]; ok {
+		if rateLimit, err := strconv.ParseFloat(raw, 64); err != nil || !(rateLimit > 0) {
+			errs = errs.Also(apis.ErrInvalidValue(raw, RateLimitAnnotation))
+		}
+	}
+	if raw, ok := t.GetAnnotations()[MaxConcurrencyAnnotation]; ok {
+		if maxConcurrency, err := strconv.ParseInt(raw, 10, 32); err != nil || maxConcurrency < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(raw, MaxConcurrencyAnnotation))
+		}
+	}
+	return errs
+}
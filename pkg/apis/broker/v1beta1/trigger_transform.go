@@ -0,0 +1,211 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// TransformAnnotation is the annotation key used to transform the events before they are
+	// delivered to the Trigger's subscriber. The value is a JSON encoded EventTransform.
+	TransformAnnotation = "events.cloud.google.com/transform"
+
+	// brokerExtensionPrefix is the prefix of the extensions used internally by the broker, which
+	// cannot be transformed.
+	brokerExtensionPrefix = "kgcp"
+)
+
+var (
+	// extensionNameRegexp matches valid CloudEvents attribute names.
+	extensionNameRegexp = regexp.MustCompile(`^[a-z0-9]{1,20}$`)
+
+	// contextAttributes are the CloudEvents context attributes that are not extensions.
+	contextAttributes = map[string]bool{
+		"specversion":     true,
+		"id":              true,
+		"source":          true,
+		"type":            true,
+		"subject":         true,
+		"time":            true,
+		"datacontenttype": true,
+		"dataschema":      true,
+		"data":            true,
+		"data_base64":     true,
+	}
+)
+
+// EventTransform modifies the events delivered to a Trigger's subscriber. Transformations are
+// applied after the events passed the Trigger's filters.
+type EventTransform struct {
+	// Type replaces the type of the events.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Source replaces the source of the events.
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// SetExtensions sets the given extension attributes of the events.
+	// +optional
+	SetExtensions map[string]string `json:"setExtensions,omitempty"`
+
+	// RemoveExtensions removes the given extension attributes from the events.
+	// +optional
+	RemoveExtensions []string `json:"removeExtensions,omitempty"`
+
+	// Data transforms the data of application/json events. The data of other events is not
+	// modified.
+	// +optional
+	Data *DataTransform `json:"data,omitempty"`
+}
+
+// DataTransform modifies JSON event data. Values are addressed by JSON Pointers (RFC 6901), e.g.
+// "/user/email". The operations are applied in the order of the fields.
+type DataTransform struct {
+	// Select keeps only the values at the given pointers, in objects of the same shape as the
+	// original data. Missing values are ignored.
+	// +optional
+	Select []string `json:"select,omitempty"`
+
+	// Remove removes the values at the given pointers. Missing values are ignored.
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+
+	// Set sets the values at the given pointers to the given JSON values, creating the parent
+	// objects if they are missing.
+	// +optional
+	Set map[string]runtime.RawExtension `json:"set,omitempty"`
+}
+
+// GetTransform parses the transformation from the Trigger's TransformAnnotation. It returns nil
+// if the annotation is not set.
+func (t *Trigger) GetTransform() (*EventTransform, error) {
+	raw, ok := t.GetAnnotations()[TransformAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var transform EventTransform
+	if err := json.Unmarshal([]byte(raw), &transform); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", TransformAnnotation, err)
+	}
+	return &transform, nil
+}
+
+func (t *Trigger) validateTransform() *apis.FieldError {
+	transform, err := t.GetTransform()
+	if err != nil {
+		return apis.ErrInvalidValue(err.Error(), TransformAnnotation)
+	}
+	if transform == nil {
+		return nil
+	}
+	if errs := transform.Validate(); errs != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("invalid transform: %v", errs), TransformAnnotation)
+	}
+	return nil
+}
+
+// Validate verifies that the transformation is not empty and only modifies valid extensions and
+// JSON Pointers.
+func (t *EventTransform) Validate() *apis.FieldError {
+	if t.Type == "" && t.Source == "" && len(t.SetExtensions) == 0 && len(t.RemoveExtensions) == 0 && t.Data == nil {
+		return apis.ErrMissingOneOf("type", "source", "setExtensions", "removeExtensions", "data")
+	}
+	var errs *apis.FieldError
+	for name := range t.SetExtensions {
+		if err := validateExtensionName(name); err != "" {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "setExtensions", err))
+		}
+	}
+	for i, name := range t.RemoveExtensions {
+		if err := validateExtensionName(name); err != "" {
+			fe := apis.ErrInvalidArrayValue(name, "removeExtensions", i)
+			fe.Details = err
+			errs = errs.Also(fe)
+		}
+	}
+	if t.Data != nil {
+		errs = errs.Also(t.Data.Validate().ViaField("data"))
+	}
+	return errs
+}
+
+// Validate verifies that the data transformation is not empty and only uses valid JSON Pointers.
+func (d *DataTransform) Validate() *apis.FieldError {
+	if len(d.Select) == 0 && len(d.Remove) == 0 && len(d.Set) == 0 {
+		return apis.ErrMissingOneOf("select", "remove", "set")
+	}
+	var errs *apis.FieldError
+	for i, p := range d.Select {
+		if !isJSONPointer(p) || p == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(p, "select", i))
+		}
+	}
+	for i, p := range d.Remove {
+		if !isJSONPointer(p) || p == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(p, "remove", i))
+		}
+	}
+	for p, v := range d.Set {
+		if !isJSONPointer(p) {
+			errs = errs.Also(apis.ErrInvalidKeyName(p, "set", "must be a JSON Pointer"))
+		}
+		if len(v.Raw) > 0 && !json.Valid(v.Raw) {
+			errs = errs.Also(apis.ErrInvalidValue(string(v.Raw), p).ViaField("set"))
+		}
+	}
+	return errs
+}
+
+// validateExtensionName returns why the extension attribute cannot be transformed, or an empty
+// string if it can.
+func validateExtensionName(name string) string {
+	switch {
+	case !extensionNameRegexp.MatchString(name):
+		return "extension names must consist of 1 to 20 lower-case letters or digits"
+	case contextAttributes[name]:
+		return "context attributes cannot be set or removed as extensions"
+	case strings.HasPrefix(name, brokerExtensionPrefix):
+		return fmt.Sprintf("extensions starting with %q are reserved for the broker", brokerExtensionPrefix)
+	}
+	return ""
+}
+
+// isJSONPointer returns whether p is a valid JSON Pointer (RFC 6901). The empty pointer refers
+// to the whole document.
+func isJSONPointer(p string) bool {
+	if p == "" {
+		return true
+	}
+	if !strings.HasPrefix(p, "/") {
+		return false
+	}
+	// "~" must be escaped as "~0" or "~1".
+	for i := 0; i < len(p); i++ {
+		if p[i] == '~' && (i+1 == len(p) || (p[i+1] != '0' && p[i+1] != '1')) {
+			return false
+		}
+	}
+	return true
+}
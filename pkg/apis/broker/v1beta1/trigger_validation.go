@@ -18,13 +18,31 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
 
 	"knative.dev/pkg/apis"
 )
 
 // Validate the Trigger.
 func (t *Trigger) Validate(ctx context.Context) *apis.FieldError {
-	// The Google Cloud Broker doesn't have any custom validations. The
-	// eventing webhook will run the usual validations.
+	// The eventing webhook will run the usual validations. The Google Cloud
+	// Broker only validates its own annotations.
+	var errs *apis.FieldError
+	errs = errs.Also(t.validateFilters(), t.validateBatching(), t.validateLimits(), t.validateTransform())
+	return errs.ViaField("metadata", "annotations")
+}
+
+func (t *Trigger) validateFilters() *apis.FieldError {
+	filters, err := t.GetFilters()
+	if err != nil {
+		return apis.ErrInvalidValue(err.Error(), FiltersAnnotation)
+	}
+	var errs *apis.FieldError
+	for i := range filters {
+		errs = errs.Also(filters[i].Validate().ViaIndex(i))
+	}
+	if errs != nil {
+		return apis.ErrInvalidValue(fmt.Sprintf("invalid filters: %v", errs), FiltersAnnotation)
+	}
 	return nil
 }
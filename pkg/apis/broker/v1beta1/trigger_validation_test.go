@@ -19,6 +19,8 @@ package v1beta1
 import (
 	"context"
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestTrigger_Validate(t *testing.T) {
@@ -27,3 +29,206 @@ func TestTrigger_Validate(t *testing.T) {
 		t.Errorf("expected nil, got %v", err)
 	}
 }
+
+func TestTrigger_ValidateFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters string
+		wantErr bool
+	}{{
+		name:    "valid filters",
+		filters: `[{"prefix":{"type":"com.example."}},{"not":{"exact":{"source":"foo"}}},{"any":[{"suffix":{"subject":".png"}},{"anyOf":{"subject":["a","b"]}}]}]`,
+	}, {
+		name:    "invalid json",
+		filters: `{"prefix":`,
+		wantErr: true,
+	}, {
+		name:    "empty filter",
+		filters: `[{}]`,
+		wantErr: true,
+	}, {
+		name:    "multiple expressions",
+		filters: `[{"prefix":{"type":"a"},"suffix":{"type":"b"}}]`,
+		wantErr: true,
+	}, {
+		name:    "empty prefix value",
+		filters: `[{"prefix":{"type":""}}]`,
+		wantErr: true,
+	}, {
+		name:    "empty all",
+		filters: `[{"all":[]}]`,
+		wantErr: true,
+	}, {
+		name:    "nested invalid filter",
+		filters: `[{"not":{"anyOf":{"type":[]}}}]`,
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{FiltersAnnotation: test.filters},
+				},
+			}
+			err := trig.Validate(context.TODO())
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateBatching(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{{
+		name:        "max events only",
+		annotations: map[string]string{BatchMaxEventsAnnotation: "100"},
+	}, {
+		name:        "max events and delay",
+		annotations: map[string]string{BatchMaxEventsAnnotation: "10", BatchMaxDelayAnnotation: "PT0.5S"},
+	}, {
+		name:        "invalid max events",
+		annotations: map[string]string{BatchMaxEventsAnnotation: "ten"},
+		wantErr:     true,
+	}, {
+		name:        "zero max events",
+		annotations: map[string]string{BatchMaxEventsAnnotation: "0"},
+		wantErr:     true,
+	}, {
+		name:        "too many max events",
+		annotations: map[string]string{BatchMaxEventsAnnotation: "1001"},
+		wantErr:     true,
+	}, {
+		name:        "invalid max delay",
+		annotations: map[string]string{BatchMaxEventsAnnotation: "10", BatchMaxDelayAnnotation: "500ms"},
+		wantErr:     true,
+	}, {
+		name:        "max delay too long",
+		annotations: map[string]string{BatchMaxEventsAnnotation: "10", BatchMaxDelayAnnotation: "PT2M"},
+		wantErr:     true,
+	}, {
+		name:        "max delay without max events",
+		annotations: map[string]string{BatchMaxDelayAnnotation: "PT1S"},
+		wantErr:     true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: test.annotations,
+				},
+			}
+			err := trig.Validate(context.TODO())
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{{
+		name:        "rate limit and max concurrency",
+		annotations: map[string]string{RateLimitAnnotation: "0.5", MaxConcurrencyAnnotation: "10"},
+	}, {
+		name:        "invalid rate limit",
+		annotations: map[string]string{RateLimitAnnotation: "fast"},
+		wantErr:     true,
+	}, {
+		name:        "zero rate limit",
+		annotations: map[string]string{RateLimitAnnotation: "0"},
+		wantErr:     true,
+	}, {
+		name:        "NaN rate limit",
+		annotations: map[string]string{RateLimitAnnotation: "NaN"},
+		wantErr:     true,
+	}, {
+		name:        "invalid max concurrency",
+		annotations: map[string]string{MaxConcurrencyAnnotation: "1.5"},
+		wantErr:     true,
+	}, {
+		name:        "zero max concurrency",
+		annotations: map[string]string{MaxConcurrencyAnnotation: "0"},
+		wantErr:     true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: test.annotations,
+				},
+			}
+			err := trig.Validate(context.TODO())
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrigger_ValidateTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform string
+		wantErr   bool
+	}{{
+		name:      "valid transform",
+		transform: `{"type":"com.example.v2","source":"example","setExtensions":{"team":"a"},"removeExtensions":["traceparent"],"data":{"select":["/user"],"remove":["/user/email"],"set":{"/version":2,"/meta/tags":["a"]}}}`,
+	}, {
+		name:      "invalid json",
+		transform: `{"type":`,
+		wantErr:   true,
+	}, {
+		name:      "empty transform",
+		transform: `{}`,
+		wantErr:   true,
+	}, {
+		name:      "empty data transform",
+		transform: `{"data":{}}`,
+		wantErr:   true,
+	}, {
+		name:      "invalid extension name",
+		transform: `{"setExtensions":{"Team":"a"}}`,
+		wantErr:   true,
+	}, {
+		name:      "context attribute as extension",
+		transform: `{"removeExtensions":["subject"]}`,
+		wantErr:   true,
+	}, {
+		name:      "broker extension",
+		transform: `{"setExtensions":{"kgcphops":"10"}}`,
+		wantErr:   true,
+	}, {
+		name:      "invalid select pointer",
+		transform: `{"data":{"select":["user"]}}`,
+		wantErr:   true,
+	}, {
+		name:      "remove whole data",
+		transform: `{"data":{"remove":[""]}}`,
+		wantErr:   true,
+	}, {
+		name:      "invalid pointer escape",
+		transform: `{"data":{"set":{"/a~2":1}}}`,
+		wantErr:   true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trig := Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{TransformAnnotation: test.transform},
+				},
+			}
+			err := trig.Validate(context.TODO())
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
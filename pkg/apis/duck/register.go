@@ -29,6 +29,12 @@ const (
 	// ClusterNameAnnotation is the annotation for the cluster Name.
 	ClusterNameAnnotation = "cluster-name"
 
+	// ReceiveAdapterAnnotation is the annotation to choose the receive adapter of a resource.
+	ReceiveAdapterAnnotation = "events.cloud.google.com/receive-adapter"
+	// SharedReceiveAdapter is the ReceiveAdapterAnnotation value to receive messages in the shared
+	// receive adapter, instead of in a receive adapter Deployment dedicated to the resource.
+	SharedReceiveAdapter = "shared"
+
 	// AutoscalingMinScaleAnnotation is the annotation to specify the minimum number of pods to scale to.
 	AutoscalingMinScaleAnnotation = Autoscaling + "/minScale"
 	// AutoscalingMaxScaleAnnotation is the annotation to specify the maximum number of pods to scale to.
@@ -0,0 +1,264 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package duck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+// MaxSubscriptionFilterLength is the maximum length in bytes of a Pub/Sub subscription filter.
+const MaxSubscriptionFilterLength = 256
+
+// ValidateSubscriptionFilter checks that filter is an expression of the Pub/Sub filter language
+// that Pub/Sub accepts, see https://cloud.google.com/pubsub/docs/filtering.
+func ValidateSubscriptionFilter(filter string) *apis.FieldError {
+	if filter == "" {
+		return nil
+	}
+	if len(filter) > MaxSubscriptionFilterLength {
+		return apis.ErrInvalidValue(fmt.Sprintf("filter must be at most %d bytes long", MaxSubscriptionFilterLength), apis.CurrentField)
+	}
+	if err := ParseSubscriptionFilter(filter); err != nil {
+		return apis.ErrInvalidValue(err.Error(), apis.CurrentField)
+	}
+	return nil
+}
+
+// ParseSubscriptionFilter parses filter according to the grammar of the Pub/Sub filter
+// language:
+//
+//	expression := term { "AND" term } | term { "OR" term }
+//	term       := ( "NOT" | "-" ) term | "(" expression ")" | predicate
+//	predicate  := "attributes" ":" key
+//	            | "attributes" "." key ( "=" | "!=" ) string
+//	            | "hasPrefix" "(" "attributes" "." key "," string ")"
+//	key        := identifier | string
+//
+// AND and OR can't be mixed without parentheses.
+func ParseSubscriptionFilter(filter string) error {
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return err
+	}
+	p := &filterParser{tokens: tokens}
+	if err := p.expression(); err != nil {
+		return err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+	}
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenPunct
+)
+
+type filterToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t filterToken) String() string {
+	if t.kind == tokenEOF {
+		return "end of filter"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',' || c == ':' || c == '.' || c == '=' || c == '-':
+			tokens = append(tokens, filterToken{kind: tokenPunct, text: string(c), pos: i})
+			i++
+		case c == '!':
+			if !strings.HasPrefix(filter[i:], "!=") {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			tokens = append(tokens, filterToken{kind: tokenPunct, text: "!=", pos: i})
+			i += 2
+		case c == '"':
+			s, n, err := unquoteFilterString(filter[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at offset %d", err, i)
+			}
+			tokens = append(tokens, filterToken{kind: tokenString, text: s, pos: i})
+			i += n
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(filter) && (isIdentStart(filter[j]) || ('0' <= filter[j] && filter[j] <= '9')) {
+				j++
+			}
+			tokens = append(tokens, filterToken{kind: tokenIdent, text: filter[i:j], pos: i})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+		}
+	}
+	return append(tokens, filterToken{kind: tokenEOF, pos: len(filter)}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// unquoteFilterString returns the value of the string literal at the start of s, and the length
+// of the literal.
+func unquoteFilterString(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", 0, errors.New("unterminated string")
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+// QuoteSubscriptionFilterString returns s as a string literal of the Pub/Sub filter language.
+func QuoteSubscriptionFilterString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) expect(kind tokenKind, text string) error {
+	t := p.next()
+	if t.kind != kind || (text != "" && t.text != text) {
+		want := text
+		if want == "" {
+			want = "a string"
+		}
+		return fmt.Errorf("expected %q but got %s at offset %d", want, t, t.pos)
+	}
+	return nil
+}
+
+func (p *filterParser) expression() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	op := ""
+	for {
+		t := p.peek()
+		if t.kind != tokenIdent || (t.text != "AND" && t.text != "OR") {
+			return nil
+		}
+		if op != "" && op != t.text {
+			return fmt.Errorf("AND and OR must be separated by parentheses at offset %d", t.pos)
+		}
+		op = t.text
+		p.next()
+		if err := p.term(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *filterParser) term() error {
+	t := p.next()
+	switch {
+	case t.kind == tokenIdent && t.text == "NOT", t.kind == tokenPunct && t.text == "-":
+		return p.term()
+	case t.kind == tokenPunct && t.text == "(":
+		if err := p.expression(); err != nil {
+			return err
+		}
+		return p.expect(tokenPunct, ")")
+	case t.kind == tokenIdent && t.text == "attributes":
+		switch op := p.next(); {
+		case op.kind == tokenPunct && op.text == ":":
+			return p.key()
+		case op.kind == tokenPunct && op.text == ".":
+			if err := p.key(); err != nil {
+				return err
+			}
+			if cmp := p.next(); cmp.kind != tokenPunct || (cmp.text != "=" && cmp.text != "!=") {
+				return fmt.Errorf("expected \"=\" or \"!=\" but got %s at offset %d", cmp, cmp.pos)
+			}
+			return p.expect(tokenString, "")
+		default:
+			return fmt.Errorf("expected \":\" or \".\" but got %s at offset %d", op, op.pos)
+		}
+	case t.kind == tokenIdent && t.text == "hasPrefix":
+		for _, want := range []string{"(", "attributes", "."} {
+			kind := tokenPunct
+			if want == "attributes" {
+				kind = tokenIdent
+			}
+			if err := p.expect(kind, want); err != nil {
+				return err
+			}
+		}
+		if err := p.key(); err != nil {
+			return err
+		}
+		if err := p.expect(tokenPunct, ","); err != nil {
+			return err
+		}
+		if err := p.expect(tokenString, ""); err != nil {
+			return err
+		}
+		return p.expect(tokenPunct, ")")
+	default:
+		return fmt.Errorf("expected an attribute predicate but got %s at offset %d", t, t.pos)
+	}
+}
+
+func (p *filterParser) key() error {
+	t := p.next()
+	if (t.kind != tokenIdent && t.kind != tokenString) || t.text == "" {
+		return fmt.Errorf("expected an attribute key but got %s at offset %d", t, t.pos)
+	}
+	return nil
+}
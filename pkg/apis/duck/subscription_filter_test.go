@@ -0,0 +1,118 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package duck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSubscriptionFilter(t *testing.T) {
+	testCases := map[string]struct {
+		filter  string
+		wantErr bool
+	}{
+		"empty": {
+			filter: "",
+		},
+		"has attribute": {
+			filter: "attributes:domain",
+		},
+		"equals": {
+			filter: `attributes.domain = "com"`,
+		},
+		"not equals": {
+			filter: `attributes.domain != "com"`,
+		},
+		"quoted key": {
+			filter: `attributes."ce-type" = "com.example.created" AND attributes:"iana.org/language_tag"`,
+		},
+		"escaped value": {
+			filter: `attributes.name = "a \"quoted\" \\ value"`,
+		},
+		"has prefix": {
+			filter: `hasPrefix(attributes.domain, "co")`,
+		},
+		"negations": {
+			filter: `NOT attributes:domain AND -attributes:region`,
+		},
+		"parentheses": {
+			filter: `(attributes:domain OR attributes:region) AND NOT (attributes.a = "1" OR attributes.b = "2")`,
+		},
+		"mixed AND and OR": {
+			filter:  `attributes:domain AND attributes:region OR attributes:zone`,
+			wantErr: true,
+		},
+		"lower case operator": {
+			filter:  `attributes:domain and attributes:region`,
+			wantErr: true,
+		},
+		"unquoted value": {
+			filter:  `attributes.domain = com`,
+			wantErr: true,
+		},
+		"not an attribute": {
+			filter:  `data.domain = "com"`,
+			wantErr: true,
+		},
+		"unterminated string": {
+			filter:  `attributes.domain = "com`,
+			wantErr: true,
+		},
+		"unbalanced parentheses": {
+			filter:  `(attributes:domain`,
+			wantErr: true,
+		},
+		"trailing tokens": {
+			filter:  `attributes:domain attributes:region`,
+			wantErr: true,
+		},
+		"empty key": {
+			filter:  `attributes:""`,
+			wantErr: true,
+		},
+		"unsupported operator": {
+			filter:  `attributes.count > "1"`,
+			wantErr: true,
+		},
+		"too long": {
+			filter:  `attributes.domain = "` + strings.Repeat("a", MaxSubscriptionFilterLength) + `"`,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := ValidateSubscriptionFilter(tc.filter)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("ValidateSubscriptionFilter(%q) = %v, wantErr %v", tc.filter, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuoteSubscriptionFilterString(t *testing.T) {
+	for _, s := range []string{"", "com.example", `a "quoted" \ value`} {
+		filter := "attributes.key = " + QuoteSubscriptionFilterString(s)
+		if err := ParseSubscriptionFilter(filter); err != nil {
+			t.Errorf("ParseSubscriptionFilter(%q) = %v", filter, err)
+		}
+		tokens, err := tokenizeFilter(QuoteSubscriptionFilterString(s))
+		if err != nil || tokens[0].text != s {
+			t.Errorf("tokenizeFilter(QuoteSubscriptionFilterString(%q)) = %v, %v", s, tokens, err)
+		}
+	}
+}
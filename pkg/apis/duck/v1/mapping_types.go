@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// EventMapping declares how a Pub/Sub message is mapped to a CloudEvent. Each
+// value is a Go template over the message, e.g. `{{ .Attributes.eventType }}`
+// or `{{ .Data.kind }}`. The templates can use .ID, .PublishTime, .OrderingKey,
+// .Attributes, .Data (the JSON decoded payload, if any), .Project, .Topic and
+// .Subscription. Values that render to an empty string are left unset, or
+// default to the values of the `google.cloud.pubsub.topic.v1.messagePublished`
+// events.
+type EventMapping struct {
+	// Type is the template of the CloudEvent type.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Source is the template of the CloudEvent source.
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// Subject is the template of the CloudEvent subject.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// DataContentType is the template of the CloudEvent data content type.
+	// Defaults to `application/octet-stream`. The data is the message payload as is.
+	// +optional
+	DataContentType string `json:"dataContentType,omitempty"`
+
+	// Extensions are the templates of the CloudEvent extensions, keyed by the
+	// extension name. They override the extensions promoted from attributes.
+	// +optional
+	Extensions map[string]string `json:"extensions,omitempty"`
+
+	// AttributeKeys defines how the message attributes are promoted to
+	// CloudEvent extensions, whose names may only consist of lower-case letters
+	// and digits. Defaults to Sanitize.
+	// +optional
+	AttributeKeys AttributeKeyPolicy `json:"attributeKeys,omitempty"`
+}
+
+// AttributeKeyPolicy defines how message attributes are promoted to CloudEvent extensions.
+type AttributeKeyPolicy string
+
+const (
+	// AttributeKeysSanitize lower-cases attribute keys and removes their invalid
+	// characters. Attributes whose sanitized key is empty, is a CloudEvent
+	// context attribute or is already used are dropped.
+	AttributeKeysSanitize AttributeKeyPolicy = "Sanitize"
+	// AttributeKeysDrop drops the attributes whose keys aren't valid extension names.
+	AttributeKeysDrop AttributeKeyPolicy = "Drop"
+	// AttributeKeysIgnore doesn't promote attributes to extensions.
+	AttributeKeysIgnore AttributeKeyPolicy = "Ignore"
+)
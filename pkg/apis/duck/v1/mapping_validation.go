@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"knative.dev/pkg/apis"
+)
+
+var (
+	// CloudEvents v1.0 extension names must consist of lower-case letters or digits.
+	extensionNameRegex = regexp.MustCompile(`^[a-z0-9]+$`)
+
+	// The CloudEvents v1.0 context attributes, which can't be used as extension names.
+	contextAttributes = map[string]bool{
+		"id":              true,
+		"source":          true,
+		"specversion":     true,
+		"type":            true,
+		"datacontenttype": true,
+		"dataschema":      true,
+		"subject":         true,
+		"time":            true,
+		"data":            true,
+	}
+)
+
+// IsExtensionName returns true if name can be used as a CloudEvent extension name.
+func IsExtensionName(name string) bool {
+	return extensionNameRegex.MatchString(name) && !contextAttributes[name]
+}
+
+// ParseMappingTemplate parses a template of an EventMapping.
+func ParseMappingTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=zero").Parse(text)
+}
+
+// Validate checks that the templates parse and that the extension names are valid.
+func (m *EventMapping) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	for field, text := range map[string]string{
+		"type":            m.Type,
+		"source":          m.Source,
+		"subject":         m.Subject,
+		"dataContentType": m.DataContentType,
+	} {
+		if _, err := ParseMappingTemplate(field, text); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), field))
+		}
+	}
+	for name, text := range m.Extensions {
+		if !IsExtensionName(name) {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("invalid extension name %q, it must consist of lower-case letters and digits and not be a CloudEvent context attribute", name),
+				Paths:   []string{apis.CurrentField},
+			}).ViaFieldKey("extensions", name)
+			continue
+		}
+		if _, err := ParseMappingTemplate(name, text); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), apis.CurrentField).ViaFieldKey("extensions", name))
+		}
+	}
+	switch m.AttributeKeys {
+	case "", AttributeKeysSanitize, AttributeKeysDrop, AttributeKeysIgnore:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(m.AttributeKeys, "attributeKeys"))
+	}
+	return errs
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEventMapping_Validate(t *testing.T) {
+	testCases := map[string]struct {
+		mapping *EventMapping
+		wantErr bool
+	}{
+		"empty": {
+			mapping: &EventMapping{},
+		},
+		"valid": {
+			mapping: &EventMapping{
+				Type:            `{{ .Attributes.eventType }}`,
+				Source:          `//example.com/{{ .Attributes.origin }}`,
+				Subject:         `{{ .Data.name }}`,
+				DataContentType: `application/json`,
+				Extensions: map[string]string{
+					"region": `{{ or .Attributes.region "global" }}`,
+				},
+				AttributeKeys: AttributeKeysDrop,
+			},
+		},
+		"bad template": {
+			mapping: &EventMapping{
+				Type: `{{ .Attributes.eventType`,
+			},
+			wantErr: true,
+		},
+		"bad extension template": {
+			mapping: &EventMapping{
+				Extensions: map[string]string{
+					"region": `{{ if }}`,
+				},
+			},
+			wantErr: true,
+		},
+		"upper case extension name": {
+			mapping: &EventMapping{
+				Extensions: map[string]string{
+					"Region": `us`,
+				},
+			},
+			wantErr: true,
+		},
+		"context attribute extension name": {
+			mapping: &EventMapping{
+				Extensions: map[string]string{
+					"subject": `foo`,
+				},
+			},
+			wantErr: true,
+		},
+		"bad attribute keys policy": {
+			mapping: &EventMapping{
+				AttributeKeys: "Fail",
+			},
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.mapping.Validate(context.Background())
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventMapping_ValidatePaths(t *testing.T) {
+	m := &EventMapping{
+		Source: `{{ .Attributes.origin`,
+		Extensions: map[string]string{
+			"Bad": `x`,
+		},
+	}
+	err := m.Validate(context.Background())
+	if err == nil {
+		t.Fatal("Validate() = nil, want an error")
+	}
+	for _, want := range []string{"extensions[Bad]", "source"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want an error on %q", err, want)
+		}
+	}
+}
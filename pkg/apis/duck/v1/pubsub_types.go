@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -63,6 +64,13 @@ type PubSubSpec struct {
 	// If omitted, defaults to same as the cluster.
 	// +optional
 	Project string `json:"project,omitempty"`
+
+	// Delivery contains the retry and dead letter settings for the events.
+	// Retries are handled by the Pub/Sub subscription. Messages that can't be
+	// converted to events, and events that exhaust their delivery attempts, are
+	// sent to the dead letter sink.
+	// +optional
+	Delivery *eventingduckv1beta1.DeliverySpec `json:"delivery,omitempty"`
 }
 
 // PubSubStatus shows how we expect folks to embed Addressable in
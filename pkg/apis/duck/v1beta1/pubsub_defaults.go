@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	"github.com/google/knative-gcp/pkg/apis/duck"
 	"github.com/google/knative-gcp/pkg/logging"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -36,7 +37,9 @@ func (s *PubSubSpec) SetPubSubDefaults(ctx context.Context) {
 	if equality.Semantic.DeepEqual(s.Secret, &corev1.SecretKeySelector{}) {
 		s.Secret = nil
 	}
-	if s.ServiceAccountName == "" && s.Secret == nil {
+	// The shared receive adapter pulls messages with its own credentials.
+	shared := apis.ParentMeta(ctx).Annotations[duck.ReceiveAdapterAnnotation] == duck.SharedReceiveAdapter
+	if s.ServiceAccountName == "" && s.Secret == nil && !shared {
 		s.ServiceAccountName = ad.KSA(apis.ParentMeta(ctx).Namespace)
 		s.Secret = ad.Secret(apis.ParentMeta(ctx).Namespace)
 	}
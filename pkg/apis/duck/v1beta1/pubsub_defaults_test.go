@@ -21,9 +21,12 @@ import (
 	"testing"
 
 	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+	"github.com/google/knative-gcp/pkg/apis/duck"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
 )
 
 func TestPubSubSpec_SetPubSubDefaults(t *testing.T) {
@@ -120,6 +123,13 @@ func TestPubSubSpec_SetPubSubDefaults(t *testing.T) {
 			},
 			ctx: gcpauthtesthelper.ContextWithDefaults(),
 		},
+		"shared receive adapter": {
+			orig:     &PubSubSpec{},
+			expected: &PubSubSpec{},
+			ctx: apis.WithinParent(gcpauthtesthelper.ContextWithDefaults(), metav1.ObjectMeta{
+				Annotations: map[string]string{duck.ReceiveAdapterAnnotation: duck.SharedReceiveAdapter},
+			}),
+		},
 		"empty secret and non-empty serviceAccountName": {
 			orig: &PubSubSpec{
 				Secret:       &corev1.SecretKeySelector{},
@@ -25,10 +25,20 @@ import (
 	"strconv"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/rickb777/date/period"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+const (
+	// Pub/Sub only accepts a maximum number of delivery attempts within this range.
+	minDeliveryAttempts = 5
+	maxDeliveryAttempts = 100
 )
 
 var (
@@ -39,7 +49,17 @@ var (
 
 // ValidateAutoscalingAnnotations validates the autoscaling annotations.
 // The class ensures that we reconcile using the corresponding controller.
+// As the shared receive adapter is scaled as a whole, resources using it can't set a class.
 func ValidateAutoscalingAnnotations(ctx context.Context, annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	if receiveAdapter, ok := annotations[ReceiveAdapterAnnotation]; ok {
+		if receiveAdapter != SharedReceiveAdapter {
+			errs = errs.Also(apis.ErrInvalidValue(receiveAdapter, fmt.Sprintf("metadata.annotations[%s]", ReceiveAdapterAnnotation)))
+		} else if _, ok := annotations[AutoscalingClassAnnotation]; ok {
+			errs = errs.Also(apis.ErrMultipleOneOf(
+				fmt.Sprintf("metadata.annotations[%s]", ReceiveAdapterAnnotation),
+				fmt.Sprintf("metadata.annotations[%s]", AutoscalingClassAnnotation)))
+		}
+	}
 	if autoscalingClass, ok := annotations[AutoscalingClassAnnotation]; ok {
 		// Only supported autoscaling class is KEDA.
 		if autoscalingClass != KEDA {
@@ -131,6 +151,25 @@ func ValidateCredential(secret *corev1.SecretKeySelector, kServiceAccountName st
 	return nil
 }
 
+// ValidateReceiveAdapterCredential rejects the secret and service account of resources that use
+// the shared receive adapter, as it pulls messages with its own credentials rather than theirs.
+func ValidateReceiveAdapterCredential(annotations map[string]string, secret *corev1.SecretKeySelector, kServiceAccountName string) *apis.FieldError {
+	if annotations[ReceiveAdapterAnnotation] != SharedReceiveAdapter {
+		return nil
+	}
+	var errs *apis.FieldError
+	if secret != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("spec.secret"))
+	}
+	if kServiceAccountName != "" {
+		errs = errs.Also(apis.ErrDisallowedFields("spec.serviceAccountName"))
+	}
+	if errs != nil {
+		errs.Details = fmt.Sprintf("The shared receive adapter, chosen by metadata.annotations[%s], pulls messages with its own credentials", ReceiveAdapterAnnotation)
+	}
+	return errs
+}
+
 func validateSecret(secret *corev1.SecretKeySelector) *apis.FieldError {
 	var errs *apis.FieldError
 	if secret.Name == "" {
@@ -153,3 +192,35 @@ func validateK8sServiceAccount(kServiceAccountName string) *apis.FieldError {
 	}
 	return nil
 }
+
+// ValidateDelivery checks the delivery spec of a source or PullSubscription.
+// Unlike Brokers, the backoff settings are optional. Retries are bounded by the
+// dead letter policy of the Pub/Sub subscription, or by the receive adapter for
+// other dead letter sinks, so they require a dead letter sink.
+func ValidateDelivery(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *apis.FieldError {
+	if spec == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if spec.BackoffDelay != nil {
+		if _, err := period.Parse(*spec.BackoffDelay); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*spec.BackoffDelay, "backoffDelay"))
+		}
+	}
+	if spec.BackoffPolicy != nil {
+		switch *spec.BackoffPolicy {
+		case eventingduckv1beta1.BackoffPolicyLinear, eventingduckv1beta1.BackoffPolicyExponential:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(*spec.BackoffPolicy, "backoffPolicy"))
+		}
+	}
+	if spec.Retry != nil {
+		if spec.DeadLetterSink == nil {
+			errs = errs.Also(apis.ErrGeneric("need a DeadLetterSink when retry is defined", "deadLetterSink"))
+		}
+		if *spec.Retry < minDeliveryAttempts || *spec.Retry > maxDeliveryAttempts {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*spec.Retry, minDeliveryAttempts, maxDeliveryAttempts, "retry"))
+		}
+	}
+	return errs.Also(brokerv1beta1.ValidateDeadLetterSink(ctx, spec.DeadLetterSink).ViaField("deadLetterSink"))
+}
@@ -27,7 +27,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 func TestValidateAutoscalingAnnotations(t *testing.T) {
@@ -108,6 +110,30 @@ func TestValidateAutoscalingAnnotations(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"ok shared receive adapter": {
+			objMeta: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ReceiveAdapterAnnotation: SharedReceiveAdapter,
+				},
+			},
+			error: false,
+		},
+		"unsupported receive adapter": {
+			objMeta: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ReceiveAdapterAnnotation: "invalid",
+				},
+			},
+			error: true,
+		},
+		"shared receive adapter with keda scaling": {
+			objMeta: func() *v1.ObjectMeta {
+				obj := kedaScaling.DeepCopy()
+				obj.Annotations[ReceiveAdapterAnnotation] = SharedReceiveAdapter
+				return obj
+			}(),
+			error: true,
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -345,3 +371,120 @@ func TestValidateCredential(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateReceiveAdapterCredential(t *testing.T) {
+	shared := map[string]string{ReceiveAdapterAnnotation: SharedReceiveAdapter}
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		secret         *corev1.SecretKeySelector
+		serviceAccount string
+		wantErr        bool
+	}{{
+		name:           "dedicated receive adapter with a secret",
+		secret:         &gcpauthtesthelper.Secret,
+		serviceAccount: "",
+		wantErr:        false,
+	}, {
+		name:           "shared receive adapter without credentials",
+		annotations:    shared,
+		secret:         nil,
+		serviceAccount: "",
+		wantErr:        false,
+	}, {
+		name:           "shared receive adapter with a secret",
+		annotations:    shared,
+		secret:         &gcpauthtesthelper.Secret,
+		serviceAccount: "",
+		wantErr:        true,
+	}, {
+		name:           "shared receive adapter with a k8s service account",
+		annotations:    shared,
+		secret:         nil,
+		serviceAccount: "test123",
+		wantErr:        true,
+	}}
+
+	for _, tc := range testCases {
+		errs := ValidateReceiveAdapterCredential(tc.annotations, tc.secret, tc.serviceAccount)
+		got := errs != nil
+		if diff := cmp.Diff(tc.wantErr, got); diff != "" {
+			t.Errorf("%s: unexpected error (-want, +got) = %v", tc.name, diff)
+		}
+	}
+}
+
+func TestValidateDelivery(t *testing.T) {
+	retry := int32(10)
+	tooFewRetries := int32(1)
+	backoffDelay := "PT1S"
+	invalidBackoffDelay := "1s"
+	linear := eventingduckv1beta1.BackoffPolicyLinear
+	pubsubSink := &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter-topic"}}
+	httpSink := &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "dead-letter.example.com"}}
+
+	testCases := []struct {
+		name     string
+		delivery *eventingduckv1beta1.DeliverySpec
+		wantErr  bool
+	}{{
+		name:     "nil delivery",
+		delivery: nil,
+		wantErr:  false,
+	}, {
+		name: "valid backoff and http dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			BackoffDelay:   &backoffDelay,
+			BackoffPolicy:  &linear,
+			DeadLetterSink: httpSink,
+		},
+		wantErr: false,
+	}, {
+		name: "valid retry and pubsub dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			Retry:          &retry,
+			DeadLetterSink: pubsubSink,
+		},
+		wantErr: false,
+	}, {
+		name: "invalid backoff delay",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			BackoffDelay: &invalidBackoffDelay,
+		},
+		wantErr: true,
+	}, {
+		name: "retry without dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			Retry: &retry,
+		},
+		wantErr: true,
+	}, {
+		name: "retry with http dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			Retry:          &retry,
+			DeadLetterSink: httpSink,
+		},
+		wantErr: false,
+	}, {
+		name: "retry out of bounds",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			Retry:          &tooFewRetries,
+			DeadLetterSink: pubsubSink,
+		},
+		wantErr: true,
+	}, {
+		name: "invalid dead letter sink scheme",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "gs", Host: "bucket"}},
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range testCases {
+		errs := ValidateDelivery(context.Background(), tc.delivery)
+		got := errs != nil
+		if diff := cmp.Diff(tc.wantErr, got); diff != "" {
+			t.Errorf("%s: unexpected validation result (-want, +got) = %v", tc.name, diff)
+		}
+	}
+}
@@ -35,7 +35,7 @@ func (current *CloudAuditLogsSource) Validate(ctx context.Context) *apis.FieldEr
 		original := apis.GetBaseline(ctx).(*CloudAuditLogsSource)
 		err = err.Also(current.CheckImmutableFields(ctx, original))
 	}
-	return err
+	return err.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 }
 
 func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -61,6 +61,10 @@ func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.Fie
 		errs = errs.Also(err)
 	}
 
+	if err := duck.ValidateDelivery(ctx, current.Delivery); err != nil {
+		errs = errs.Also(err.ViaField("delivery"))
+	}
+
 	return errs
 }
 
@@ -74,7 +78,7 @@ func (current *CloudAuditLogsSource) CheckImmutableFields(ctx context.Context, o
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudAuditLogsSourceSpec{},
-			"Sink", "CloudEventOverrides")); diff != "" {
+			"Sink", "CloudEventOverrides", "Delivery")); diff != "" {
 		errs = errs.Also(
 			&apis.FieldError{
 				Message: "Immutable fields changed (-old +new)",
@@ -33,6 +33,7 @@ func (current *CloudBuildSource) Validate(ctx context.Context) *apis.FieldError
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
 
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -50,6 +51,10 @@ func (current *CloudBuildSourceSpec) Validate(ctx context.Context) *apis.FieldEr
 		errs = errs.Also(err)
 	}
 
+	if err := duck.ValidateDelivery(ctx, current.Delivery); err != nil {
+		errs = errs.Also(err.ViaField("delivery"))
+	}
+
 	return errs
 }
 
@@ -62,7 +67,7 @@ func (current *CloudBuildSource) CheckImmutableFields(ctx context.Context, origi
 	// Modification of Topic, Secret and Project are not allowed. Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudBuildSourceSpec{},
-			"Sink", "CloudEventOverrides")); diff != "" {
+			"Sink", "CloudEventOverrides", "Delivery")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
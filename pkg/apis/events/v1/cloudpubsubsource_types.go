@@ -89,6 +89,18 @@ type CloudPubSubSourceSpec struct {
 	// shorter than 10 minutes. Defaults to 7 days ('7d').
 	// +optional
 	RetentionDuration *string `json:"retentionDuration,omitempty"`
+
+	// Mapping declares how the Pub/Sub messages are mapped to CloudEvents.
+	// Without it, the messages are sent as
+	// `google.cloud.pubsub.topic.v1.messagePublished` events.
+	// +optional
+	Mapping *gcpduckv1.EventMapping `json:"mapping,omitempty"`
+
+	// Filter is an expression of the Pub/Sub filter language, e.g.
+	// `attributes.eventType = "created"`. Pub/Sub only delivers the messages
+	// that match it. Changing it recreates the Pub/Sub subscription.
+	// +optional
+	Filter string `json:"filter,omitempty"`
 }
 
 // GetAckDeadline parses AckDeadline and returns the default if an error occurs.
@@ -169,6 +181,16 @@ func (s *CloudPubSubSource) PubSubStatus() *gcpduckv1.PubSubStatus {
 	return &s.Status.PubSubStatus
 }
 
+// EventMapping returns the EventMapping of the Spec, if any.
+func (s *CloudPubSubSource) EventMapping() *gcpduckv1.EventMapping {
+	return s.Spec.Mapping
+}
+
+// SubscriptionFilter returns the Filter of the Spec.
+func (s *CloudPubSubSource) SubscriptionFilter() string {
+	return s.Spec.Filter
+}
+
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
 func (*CloudPubSubSource) GetConditionSet() apis.ConditionSet {
 	return pubSubCondSet
@@ -37,6 +37,7 @@ func (current *CloudPubSubSource) Validate(ctx context.Context) *apis.FieldError
 		original := apis.GetBaseline(ctx).(*CloudPubSubSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -77,6 +78,20 @@ func (current *CloudPubSubSourceSpec) Validate(ctx context.Context) *apis.FieldE
 		errs = errs.Also(err)
 	}
 
+	if err := duck.ValidateDelivery(ctx, current.Delivery); err != nil {
+		errs = errs.Also(err.ViaField("delivery"))
+	}
+
+	if current.Mapping != nil {
+		if err := current.Mapping.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaField("mapping"))
+		}
+	}
+
+	if err := duck.ValidateSubscriptionFilter(current.Filter); err != nil {
+		errs = errs.Also(err.ViaField("filter"))
+	}
+
 	return errs
 }
 
@@ -89,7 +104,7 @@ func (current *CloudPubSubSource) CheckImmutableFields(ctx context.Context, orig
 	// Modification of Topic, Secret, AckDeadline, RetainAckedMessages, RetentionDuration, ServiceAccountName and Project are not allowed.
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
-		cmpopts.IgnoreFields(CloudPubSubSourceSpec{}, "Sink", "CloudEventOverrides")); diff != "" {
+		cmpopts.IgnoreFields(CloudPubSubSourceSpec{}, "Sink", "CloudEventOverrides", "Delivery", "Mapping", "Filter")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
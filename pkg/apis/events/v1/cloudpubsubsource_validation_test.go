@@ -28,6 +28,7 @@ import (
 	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/ptr"
@@ -238,6 +239,66 @@ func TestCloudPubSubSourceCheckValidationFields(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"ok delivery": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1beta1.DeliverySpec{
+					Retry: ptr.Int32(5),
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter-topic"},
+					},
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad delivery, retry without dead letter sink": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1beta1.DeliverySpec{
+					Retry: ptr.Int32(5),
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"ok mapping": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mapping = &gcpduckv1.EventMapping{
+					Type:       "{{ .Attributes.eventType }}",
+					Extensions: map[string]string{"region": "{{ .Data.region }}"},
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"ok filter": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Filter = `attributes.eventType = "created" AND NOT attributes:test`
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad filter": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Filter = `attributes.eventType == "created"`
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad mapping, invalid template": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mapping = &gcpduckv1.EventMapping{
+					Type: "{{ .Attributes.eventType",
+				}
+				return *obj
+			}(),
+			error: true,
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -293,6 +354,39 @@ func TestCloudPubSubSourceCheckImmutableFields(t *testing.T) {
 			updatedAnnotation: map[string]string{},
 			allowed:           false,
 		},
+		"Delivery changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1beta1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter-topic"},
+					},
+				}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Mapping changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mapping = &gcpduckv1.EventMapping{
+					Subject: "{{ .Data.name }}",
+				}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Filter changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Filter = `attributes:eventType`
+				return *obj
+			}(),
+			allowed: true,
+		},
 		"Secret.Name changed": {
 			orig: &pubSubSourceSpec,
 			updated: CloudPubSubSourceSpec{
@@ -34,6 +34,7 @@ func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldEr
 		original := apis.GetBaseline(ctx).(*CloudSchedulerSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -66,6 +67,10 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 		errs = errs.Also(err)
 	}
 
+	if err := duck.ValidateDelivery(ctx, current.Delivery); err != nil {
+		errs = errs.Also(err.ViaField("delivery"))
+	}
+
 	return errs
 }
 
@@ -78,7 +83,7 @@ func (current *CloudSchedulerSource) CheckImmutableFields(ctx context.Context, o
 	// Modification of Location, Schedule, Data, Secret, ServiceAccountName, Project are not allowed.
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
-		cmpopts.IgnoreFields(CloudSchedulerSourceSpec{}, "Sink", "CloudEventOverrides")); diff != "" {
+		cmpopts.IgnoreFields(CloudSchedulerSourceSpec{}, "Sink", "CloudEventOverrides", "Delivery")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
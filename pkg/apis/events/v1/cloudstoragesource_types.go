@@ -71,6 +71,29 @@ type CloudStorageSourceSpec struct {
 	// ObjectNamePrefix limits the notifications to objects with this prefix
 	// +optional
 	ObjectNamePrefix string `json:"objectNamePrefix,omitempty"`
+
+	// Backfill makes the source emit finalized events for the objects already
+	// in the bucket, in addition to the notified ones.
+	// +optional
+	Backfill *CloudStorageBackfill `json:"backfill,omitempty"`
+}
+
+// CloudStorageBackfill selects the existing objects of the bucket to emit events for.
+type CloudStorageBackfill struct {
+	// ObjectNamePrefix limits the backfill to objects with this prefix. It must
+	// start with the ObjectNamePrefix of the source, which is the default.
+	// +optional
+	ObjectNamePrefix string `json:"objectNamePrefix,omitempty"`
+
+	// CreatedAfter limits the backfill to objects created at or after this time.
+	// +optional
+	CreatedAfter *metav1.Time `json:"createdAfter,omitempty"`
+
+	// CreatedBefore limits the backfill to objects created before this time.
+	// Defaults to the time the backfill starts, as the objects created later
+	// are notified.
+	// +optional
+	CreatedBefore *metav1.Time `json:"createdBefore,omitempty"`
 }
 
 const (
@@ -96,6 +119,35 @@ type CloudStorageSourceStatus struct {
 	// NotificationID is the ID that GCS identifies this notification as.
 	// +optional
 	NotificationID string `json:"notificationId,omitempty"`
+
+	// Backfill is the progress of the backfill, if any.
+	// +optional
+	Backfill *CloudStorageBackfillStatus `json:"backfill,omitempty"`
+}
+
+// CloudStorageBackfillStatus is the progress of a CloudStorageSource backfill.
+type CloudStorageBackfillStatus struct {
+	// StartTime is the time the backfill started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the backfill completed. It is unset while
+	// the backfill is in progress.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Cursor is the token of the next page of objects to list. The backfill
+	// resumes from it.
+	// +optional
+	Cursor string `json:"cursor,omitempty"`
+
+	// ObjectsListed is the number of objects listed so far.
+	// +optional
+	ObjectsListed int64 `json:"objectsListed,omitempty"`
+
+	// EventsPublished is the number of events published so far.
+	// +optional
+	EventsPublished int64 `json:"eventsPublished,omitempty"`
 }
 
 func (storage *CloudStorageSource) GetGroupVersionKind() schema.GroupVersionKind {
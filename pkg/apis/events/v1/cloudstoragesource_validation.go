@@ -18,14 +18,18 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 )
 
 func (current *CloudStorageSource) Validate(ctx context.Context) *apis.FieldError {
@@ -35,6 +39,7 @@ func (current *CloudStorageSource) Validate(ctx context.Context) *apis.FieldErro
 		original := apis.GetBaseline(ctx).(*CloudStorageSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -57,6 +62,34 @@ func (current *CloudStorageSourceSpec) Validate(ctx context.Context) *apis.Field
 		errs = errs.Also(err)
 	}
 
+	if err := duck.ValidateDelivery(ctx, current.Delivery); err != nil {
+		errs = errs.Also(err.ViaField("delivery"))
+	}
+
+	if current.Backfill != nil {
+		errs = errs.Also(current.Backfill.validate(current).ViaField("backfill"))
+	}
+
+	return errs
+}
+
+func (current *CloudStorageBackfill) validate(spec *CloudStorageSourceSpec) *apis.FieldError {
+	var errs *apis.FieldError
+
+	// Backfilled objects must be ones the notification would have reported.
+	if !strings.HasPrefix(current.ObjectNamePrefix, spec.ObjectNamePrefix) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("objectNamePrefix must start with the objectNamePrefix of the source %q", spec.ObjectNamePrefix), "objectNamePrefix"))
+	}
+
+	if current.CreatedAfter != nil && current.CreatedBefore != nil && !current.CreatedAfter.Before(current.CreatedBefore) {
+		errs = errs.Also(apis.ErrGeneric("createdBefore must be after createdAfter", "createdBefore"))
+	}
+
+	// The backfill only emits finalized events.
+	if len(spec.EventTypes) != 0 && !sets.NewString(spec.EventTypes...).Has(schemasv1.CloudStorageObjectFinalizedEventType) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("backfill requires the %s event type", schemasv1.CloudStorageObjectFinalizedEventType), apis.CurrentField))
+	}
+
 	return errs
 }
 
@@ -66,11 +99,11 @@ func (current *CloudStorageSource) CheckImmutableFields(ctx context.Context, ori
 	}
 
 	var errs *apis.FieldError
-	// Modification of EventType, Secret, ServiceAccountName, Project, Bucket, PayloadFormat, EventType, ObjectNamePrefix are not allowed.
+	// Modification of EventType, Secret, ServiceAccountName, Project, Bucket, PayloadFormat, EventType, ObjectNamePrefix, Backfill are not allowed.
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudStorageSourceSpec{},
-			"Sink", "CloudEventOverrides")); diff != "" {
+			"Sink", "CloudEventOverrides", "Delivery")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
@@ -19,6 +19,7 @@ package v1
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/apis/duck"
 	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
@@ -354,6 +355,46 @@ func TestSpecValidationFields(t *testing.T) {
 			}
 			return fe
 		}(),
+	}, {
+		name: "valid backfill",
+		spec: func() *CloudStorageSourceSpec {
+			spec := storageSourceSpec.DeepCopy()
+			spec.Backfill = &CloudStorageBackfill{
+				ObjectNamePrefix: "test-prefix/logs/",
+				CreatedAfter:     &v1.Time{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+				CreatedBefore:    &v1.Time{Time: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+			}
+			return spec
+		}(),
+		want: nil,
+	}, {
+		name: "backfill prefix outside of the source prefix",
+		spec: func() *CloudStorageSourceSpec {
+			spec := storageSourceSpec.DeepCopy()
+			spec.Backfill = &CloudStorageBackfill{ObjectNamePrefix: "other-prefix"}
+			return spec
+		}(),
+		want: apis.ErrGeneric(`objectNamePrefix must start with the objectNamePrefix of the source "test-prefix"`, "backfill.objectNamePrefix"),
+	}, {
+		name: "backfill with an empty time window",
+		spec: func() *CloudStorageSourceSpec {
+			spec := minimalCloudStorageSourceSpec.DeepCopy()
+			spec.Backfill = &CloudStorageBackfill{
+				CreatedAfter:  &v1.Time{Time: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+				CreatedBefore: &v1.Time{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			}
+			return spec
+		}(),
+		want: apis.ErrGeneric("createdBefore must be after createdAfter", "backfill.createdBefore"),
+	}, {
+		name: "backfill without the finalized event type",
+		spec: func() *CloudStorageSourceSpec {
+			spec := minimalCloudStorageSourceSpec.DeepCopy()
+			spec.EventTypes = []string{schemasv1.CloudStorageObjectDeletedEventType}
+			spec.Backfill = &CloudStorageBackfill{}
+			return spec
+		}(),
+		want: apis.ErrGeneric("backfill requires the google.cloud.storage.object.v1.finalized event type", "backfill"),
 	}}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -439,6 +480,17 @@ func TestCheckImmutableFields(t *testing.T) {
 			},
 			allowed: false,
 		},
+		"Backfill added": {
+			orig: &storageSourceSpec,
+			updated: CloudStorageSourceSpec{
+				Bucket:           storageSourceSpec.Bucket,
+				EventTypes:       storageSourceSpec.EventTypes,
+				ObjectNamePrefix: storageSourceSpec.ObjectNamePrefix,
+				PubSubSpec:       storageSourceSpec.PubSubSpec,
+				Backfill:         &CloudStorageBackfill{},
+			},
+			allowed: false,
+		},
 		"Secret.Name changed": {
 			orig: &storageSourceSpec,
 			updated: CloudStorageSourceSpec{
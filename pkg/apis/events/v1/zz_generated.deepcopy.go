@@ -21,6 +21,7 @@ limitations under the License.
 package v1
 
 import (
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -289,6 +290,11 @@ func (in *CloudPubSubSourceSpec) DeepCopyInto(out *CloudPubSubSourceSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Mapping != nil {
+		in, out := &in.Mapping, &out.Mapping
+		*out = new(duckv1.EventMapping)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -414,6 +420,54 @@ func (in *CloudSchedulerSourceStatus) DeepCopy() *CloudSchedulerSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudStorageBackfill) DeepCopyInto(out *CloudStorageBackfill) {
+	*out = *in
+	if in.CreatedAfter != nil {
+		in, out := &in.CreatedAfter, &out.CreatedAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.CreatedBefore != nil {
+		in, out := &in.CreatedBefore, &out.CreatedBefore
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudStorageBackfill.
+func (in *CloudStorageBackfill) DeepCopy() *CloudStorageBackfill {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudStorageBackfill)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudStorageBackfillStatus) DeepCopyInto(out *CloudStorageBackfillStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudStorageBackfillStatus.
+func (in *CloudStorageBackfillStatus) DeepCopy() *CloudStorageBackfillStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudStorageBackfillStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudStorageSource) DeepCopyInto(out *CloudStorageSource) {
 	*out = *in
@@ -484,6 +538,11 @@ func (in *CloudStorageSourceSpec) DeepCopyInto(out *CloudStorageSourceSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Backfill != nil {
+		in, out := &in.Backfill, &out.Backfill
+		*out = new(CloudStorageBackfill)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -501,6 +560,11 @@ func (in *CloudStorageSourceSpec) DeepCopy() *CloudStorageSourceSpec {
 func (in *CloudStorageSourceStatus) DeepCopyInto(out *CloudStorageSourceStatus) {
 	*out = *in
 	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	if in.Backfill != nil {
+		in, out := &in.Backfill, &out.Backfill
+		*out = new(CloudStorageBackfillStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+)
+
+// DefaultReplayTTLSecondsAfterFinished is how long a finished Replay is kept
+// unless its spec says otherwise.
+const DefaultReplayTTLSecondsAfterFinished int32 = 24 * 60 * 60
+
+// SetDefaults sets the default field values for a Replay.
+func (r *Replay) SetDefaults(ctx context.Context) {
+	r.Spec.SetDefaults(ctx)
+}
+
+// SetDefaults sets the default field values for a ReplaySpec.
+func (rs *ReplaySpec) SetDefaults(ctx context.Context) {
+	if rs.TTLSecondsAfterFinished == nil {
+		ttl := DefaultReplayTTLSecondsAfterFinished
+		rs.TTLSecondsAfterFinished = &ttl
+	}
+}
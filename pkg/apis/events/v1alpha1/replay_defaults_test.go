@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/ptr"
+)
+
+func TestReplay_SetDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ReplaySpec
+		want ReplaySpec
+	}{{
+		name: "TTL after finished defaulted",
+		spec: ReplaySpec{Trigger: "trigger"},
+		want: ReplaySpec{Trigger: "trigger", TTLSecondsAfterFinished: ptr.Int32(DefaultReplayTTLSecondsAfterFinished)},
+	}, {
+		name: "TTL after finished kept",
+		spec: ReplaySpec{Trigger: "trigger", TTLSecondsAfterFinished: ptr.Int32(0)},
+		want: ReplaySpec{Trigger: "trigger", TTLSecondsAfterFinished: ptr.Int32(0)},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := &Replay{Spec: test.spec}
+			got.SetDefaults(context.Background())
+			if diff := cmp.Diff(test.want, got.Spec); diff != "" {
+				t.Error("SetDefaults (-want, +got) =", diff)
+			}
+		})
+	}
+}
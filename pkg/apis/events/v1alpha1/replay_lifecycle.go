@@ -0,0 +1,120 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+var replayCondSet = apis.NewBatchConditionSet(
+	ReplayConditionSubscriptionSeeked,
+	ReplayConditionReplayed,
+)
+
+const (
+	// ReplayConditionSucceeded has status true when all subconditions below
+	// have been set to True.
+	ReplayConditionSucceeded apis.ConditionType = apis.ConditionSucceeded
+
+	// ReplayConditionSubscriptionSeeked reports whether the temporary
+	// subscription of the Replay has been created and seeked to the events
+	// retained by the Broker.
+	ReplayConditionSubscriptionSeeked apis.ConditionType = "SubscriptionSeeked"
+
+	// ReplayConditionReplayed reports whether all the events accepted between
+	// the start time and the end time have been replayed to the Trigger.
+	ReplayConditionReplayed apis.ConditionType = "Replayed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (rs *ReplayStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return replayCondSet.Manage(rs).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level Condition.
+func (rs *ReplayStatus) GetTopLevelCondition() *apis.Condition {
+	return replayCondSet.Manage(rs).GetTopLevelCondition()
+}
+
+// IsSucceeded returns true if all the events have been replayed.
+func (rs *ReplayStatus) IsSucceeded() bool {
+	return replayCondSet.Manage(rs).IsHappy()
+}
+
+// IsDone returns true if the replay succeeded or failed, and its events are no
+// longer delivered.
+func (rs *ReplayStatus) IsDone() bool {
+	c := rs.GetTopLevelCondition()
+	return c != nil && !c.IsUnknown()
+}
+
+// IsSeeked returns true if the subscription has been seeked, so that the
+// events are being replayed.
+func (rs *ReplayStatus) IsSeeked() bool {
+	return rs.GetCondition(ReplayConditionSubscriptionSeeked).IsTrue()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (rs *ReplayStatus) InitializeConditions() {
+	replayCondSet.Manage(rs).InitializeConditions()
+}
+
+// MarkSubscriptionSeeked records that the temporary subscription has been
+// seeked at the end time.
+func (rs *ReplayStatus) MarkSubscriptionSeeked(subscriptionID string, endTime metav1.Time) {
+	rs.SubscriptionID = subscriptionID
+	rs.EndTime = &endTime
+	replayCondSet.Manage(rs).MarkTrue(ReplayConditionSubscriptionSeeked)
+}
+
+func (rs *ReplayStatus) MarkSubscriptionNotSeeked(reason, format string, args ...interface{}) {
+	replayCondSet.Manage(rs).MarkUnknown(ReplayConditionSubscriptionSeeked, reason, format, args...)
+}
+
+func (rs *ReplayStatus) MarkSubscriptionSeekFailed(reason, format string, args ...interface{}) {
+	replayCondSet.Manage(rs).MarkFalse(ReplayConditionSubscriptionSeeked, reason, format, args...)
+}
+
+// MarkSubscriptionDeleted records that the temporary subscription has been
+// deleted once the replay is done.
+func (rs *ReplayStatus) MarkSubscriptionDeleted() {
+	rs.SubscriptionID = ""
+}
+
+// MarkReplaying records the progress of the replay.
+func (rs *ReplayStatus) MarkReplaying(replayedThrough *metav1.Time, replayedEvents int64) {
+	rs.ReplayedThrough = replayedThrough
+	rs.ReplayedEvents = replayedEvents
+	if replayedThrough == nil {
+		replayCondSet.Manage(rs).MarkUnknown(ReplayConditionReplayed, "Replaying", "No event has been replayed yet")
+		return
+	}
+	replayCondSet.Manage(rs).MarkUnknown(ReplayConditionReplayed, "Replaying", "Replayed %d events accepted through %s", replayedEvents, replayedThrough.UTC().Format(time.RFC3339))
+}
+
+// MarkReplayed records that all the events have been replayed.
+func (rs *ReplayStatus) MarkReplayed(completionTime metav1.Time) {
+	rs.CompletionTime = &completionTime
+	replayCondSet.Manage(rs).MarkTrue(ReplayConditionReplayed)
+}
+
+func (rs *ReplayStatus) MarkReplayFailed(reason, format string, args ...interface{}) {
+	replayCondSet.Manage(rs).MarkFalse(ReplayConditionReplayed, reason, format, args...)
+}
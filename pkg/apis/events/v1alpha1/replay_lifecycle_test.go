@@ -0,0 +1,127 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestReplayStatus(t *testing.T) {
+	endTime := metav1.NewTime(time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC))
+	through := metav1.NewTime(time.Date(2021, 3, 1, 18, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name          string
+		mark          func(*ReplayStatus)
+		wantSucceeded corev1.ConditionStatus
+		wantSeeked    bool
+		wantDone      bool
+	}{{
+		name:          "initialized",
+		mark:          func(*ReplayStatus) {},
+		wantSucceeded: corev1.ConditionUnknown,
+	}, {
+		name: "queued",
+		mark: func(rs *ReplayStatus) {
+			rs.MarkSubscriptionNotSeeked("Queued", "")
+		},
+		wantSucceeded: corev1.ConditionUnknown,
+	}, {
+		name: "seek failed",
+		mark: func(rs *ReplayStatus) {
+			rs.MarkSubscriptionSeekFailed("SeekFailed", "")
+		},
+		wantSucceeded: corev1.ConditionFalse,
+		wantDone:      true,
+	}, {
+		name: "replaying",
+		mark: func(rs *ReplayStatus) {
+			rs.MarkSubscriptionSeeked("sub", endTime)
+			rs.MarkReplaying(&through, 3)
+		},
+		wantSucceeded: corev1.ConditionUnknown,
+		wantSeeked:    true,
+	}, {
+		name: "replayed",
+		mark: func(rs *ReplayStatus) {
+			rs.MarkSubscriptionSeeked("sub", endTime)
+			rs.MarkReplaying(&through, 3)
+			rs.MarkReplayed(endTime)
+		},
+		wantSucceeded: corev1.ConditionTrue,
+		wantSeeked:    true,
+		wantDone:      true,
+	}, {
+		name: "replay failed",
+		mark: func(rs *ReplayStatus) {
+			rs.MarkReplayFailed("TriggerNotFound", "")
+		},
+		wantSucceeded: corev1.ConditionFalse,
+		wantDone:      true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rs := &ReplayStatus{}
+			rs.InitializeConditions()
+			test.mark(rs)
+			if got := rs.GetTopLevelCondition().Status; got != test.wantSucceeded {
+				t.Errorf("unexpected %s status, want %v, got %v", apis.ConditionSucceeded, test.wantSucceeded, got)
+			}
+			if got := rs.IsSucceeded(); got != (test.wantSucceeded == corev1.ConditionTrue) {
+				t.Errorf("unexpected IsSucceeded, got %v", got)
+			}
+			if got := rs.IsSeeked(); got != test.wantSeeked {
+				t.Errorf("unexpected IsSeeked, want %v, got %v", test.wantSeeked, got)
+			}
+			if got := rs.IsDone(); got != test.wantDone {
+				t.Errorf("unexpected IsDone, want %v, got %v", test.wantDone, got)
+			}
+		})
+	}
+}
+
+func TestReplayStatus_Progress(t *testing.T) {
+	endTime := metav1.NewTime(time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC))
+	through := metav1.NewTime(time.Date(2021, 3, 1, 18, 0, 0, 0, time.UTC))
+
+	rs := &ReplayStatus{}
+	rs.InitializeConditions()
+	rs.MarkSubscriptionSeeked("sub", endTime)
+	if rs.SubscriptionID != "sub" || !rs.EndTime.Equal(&endTime) {
+		t.Errorf("unexpected subscription, got %q seeked at %v", rs.SubscriptionID, rs.EndTime)
+	}
+	rs.MarkReplaying(&through, 3)
+	if !rs.ReplayedThrough.Equal(&through) || rs.ReplayedEvents != 3 {
+		t.Errorf("unexpected progress, got %d events through %v", rs.ReplayedEvents, rs.ReplayedThrough)
+	}
+	if got, want := rs.GetCondition(ReplayConditionReplayed).Message, "Replayed 3 events accepted through 2021-03-01T18:00:00Z"; got != want {
+		t.Errorf("unexpected message, want %q, got %q", want, got)
+	}
+	rs.MarkReplayed(endTime)
+	if !rs.CompletionTime.Equal(&endTime) {
+		t.Errorf("unexpected completion time, got %v", rs.CompletionTime)
+	}
+	rs.MarkSubscriptionDeleted()
+	if rs.SubscriptionID != "" {
+		t.Errorf("unexpected subscription after deletion, got %q", rs.SubscriptionID)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Replay redelivers the events accepted by a Broker since a point in time to
+// one of its Triggers. The Broker must retain its events, see
+// MessageRetentionAnnotation of Brokers.
+type Replay struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Replay.
+	Spec ReplaySpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the Replay. This data may be out of
+	// date.
+	// +optional
+	Status ReplayStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that Replay can be validated, can be defaulted, and has immutable fields.
+	_ apis.Validatable = (*Replay)(nil)
+	_ apis.Defaultable = (*Replay)(nil)
+
+	// Check that Replay can return its spec untyped.
+	_ apis.HasSpec = (*Replay)(nil)
+
+	_ runtime.Object = (*Replay)(nil)
+
+	// Check that we can create OwnerReferences to a Replay.
+	_ kmeta.OwnerRefable = (*Replay)(nil)
+
+	// Check that Replay implements the KRShaped duck type.
+	_ duckv1.KRShaped = (*Replay)(nil)
+)
+
+// ReplaySpec defines the desired state of a Replay.
+type ReplaySpec struct {
+	// Trigger is the name of the Trigger, in the namespace of the Replay, that
+	// the events are replayed to. Other Triggers of the Broker don't receive
+	// the replayed events.
+	Trigger string `json:"trigger"`
+
+	// StartTime is the time from which the events accepted by the Broker
+	// ingress are replayed. Events accepted after the Replay is created are not
+	// replayed.
+	StartTime metav1.Time `json:"startTime"`
+
+	// TTLSecondsAfterFinished is how long the Replay is kept after it
+	// completes or fails, before it is deleted. Defaults to one day.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// ReplayStatus represents the current state of a Replay.
+type ReplayStatus struct {
+	// inherits duck/v1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1.Status `json:",inline"`
+
+	// ProjectID is the project of the temporary Pub/Sub subscription, which is
+	// the project of the Broker.
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// SubscriptionID is the ID of the temporary Pub/Sub subscription the
+	// events are replayed from. It is seeked to a snapshot of the subscription
+	// retaining the events of the Broker, and deleted once the Replay is done.
+	// +optional
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+
+	// EndTime is the time the temporary subscription was seeked. Only events
+	// accepted before it are replayed.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// ReplayedThrough is the time the latest replayed event was accepted by
+	// the Broker ingress.
+	// +optional
+	ReplayedThrough *metav1.Time `json:"replayedThrough,omitempty"`
+
+	// ReplayedEvents is the number of events replayed to the Trigger.
+	// +optional
+	ReplayedEvents int64 `json:"replayedEvents,omitempty"`
+
+	// CompletionTime is the time the replay completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ReplayList is a collection of Replays.
+type ReplayList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Replay `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for Replays.
+func (r *Replay) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Replay")
+}
+
+// GetUntypedSpec returns the spec of the Replay.
+func (r *Replay) GetUntypedSpec() interface{} {
+	return r.Spec
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*Replay) GetConditionSet() apis.ConditionSet {
+	return replayCondSet
+}
+
+// GetStatus retrieves the status of the Replay. Implements the KRShaped interface.
+func (r *Replay) GetStatus() *duckv1.Status {
+	return &r.Status.Status
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"math"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+)
+
+// Validate verifies that the Replay is valid.
+func (r *Replay) Validate(ctx context.Context) *apis.FieldError {
+	errs := r.Spec.Validate(ctx).ViaField("spec")
+	if apis.IsInUpdate(ctx) {
+		original := apis.GetBaseline(ctx).(*Replay)
+		errs = errs.Also(r.CheckImmutableFields(ctx, original))
+	}
+	return errs
+}
+
+// Validate verifies that the ReplaySpec is valid.
+func (rs *ReplaySpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if rs.Trigger == "" {
+		errs = errs.Also(apis.ErrMissingField("trigger"))
+	}
+	if rs.StartTime.IsZero() {
+		errs = errs.Also(apis.ErrMissingField("startTime"))
+	}
+	if rs.TTLSecondsAfterFinished != nil && *rs.TTLSecondsAfterFinished < 0 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*rs.TTLSecondsAfterFinished, 0, math.MaxInt32, "ttlSecondsAfterFinished"))
+	}
+	return errs
+}
+
+// CheckImmutableFields checks that the spec of the Replay is not updated.
+func (r *Replay) CheckImmutableFields(ctx context.Context, original *Replay) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+	if diff := cmp.Diff(original.Spec, r.Spec); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec"},
+			Details: diff,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+func TestReplay_Validate(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name   string
+		replay Replay
+		want   *apis.FieldError
+	}{{
+		name: "valid",
+		replay: Replay{
+			Spec: ReplaySpec{Trigger: "trigger", StartTime: startTime},
+		},
+	}, {
+		name: "missing trigger and start time",
+		replay: Replay{
+			Spec: ReplaySpec{},
+		},
+		want: apis.ErrMissingField("spec.trigger").Also(apis.ErrMissingField("spec.startTime")),
+	}, {
+		name: "negative TTL after finished",
+		replay: Replay{
+			Spec: ReplaySpec{Trigger: "trigger", StartTime: startTime, TTLSecondsAfterFinished: ptr.Int32(-1)},
+		},
+		want: apis.ErrOutOfBoundsValue(-1, 0, math.MaxInt32, "spec.ttlSecondsAfterFinished"),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.replay.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Error("Validate (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestReplay_CheckImmutableFields(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC))
+	original := &Replay{
+		Spec: ReplaySpec{Trigger: "trigger", StartTime: startTime},
+	}
+	tests := []struct {
+		name    string
+		updated ReplaySpec
+		wantErr bool
+	}{{
+		name:    "unchanged",
+		updated: original.Spec,
+	}, {
+		name:    "trigger changed",
+		updated: ReplaySpec{Trigger: "other", StartTime: startTime},
+		wantErr: true,
+	}, {
+		name:    "start time changed",
+		updated: ReplaySpec{Trigger: "trigger", StartTime: metav1.NewTime(startTime.Add(time.Hour))},
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated := &Replay{Spec: test.updated}
+			ctx := apis.WithinUpdate(context.Background(), original)
+			if err := updated.Validate(ctx); (err != nil) != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
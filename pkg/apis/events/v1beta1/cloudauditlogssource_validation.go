@@ -35,7 +35,7 @@ func (current *CloudAuditLogsSource) Validate(ctx context.Context) *apis.FieldEr
 		original := apis.GetBaseline(ctx).(*CloudAuditLogsSource)
 		err = err.Also(current.CheckImmutableFields(ctx, original))
 	}
-	return err
+	return err.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 }
 
 func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.FieldError {
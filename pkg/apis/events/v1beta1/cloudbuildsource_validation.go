@@ -33,6 +33,7 @@ func (current *CloudBuildSource) Validate(ctx context.Context) *apis.FieldError
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
 
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -37,6 +37,7 @@ func (current *CloudPubSubSource) Validate(ctx context.Context) *apis.FieldError
 		original := apis.GetBaseline(ctx).(*CloudPubSubSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -34,6 +34,7 @@ func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldEr
 		original := apis.GetBaseline(ctx).(*CloudSchedulerSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
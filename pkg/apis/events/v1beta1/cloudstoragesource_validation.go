@@ -35,6 +35,7 @@ func (current *CloudStorageSource) Validate(ctx context.Context) *apis.FieldErro
 		original := apis.GetBaseline(ctx).(*CloudStorageSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
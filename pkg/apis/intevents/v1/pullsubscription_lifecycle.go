@@ -74,6 +74,27 @@ func (s *PullSubscriptionStatus) MarkNoTransformer(reason, messageFormat string,
 	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionTransformerProvided, reason, messageFormat, messageA...)
 }
 
+// MarkDeadLetterSink sets the condition that the dead letter sink has been resolved.
+func (s *PullSubscriptionStatus) MarkDeadLetterSink(uri *apis.URL) {
+	s.DeadLetterSinkURI = uri
+	pullSubscriptionCondSet.Manage(s).MarkTrue(PullSubscriptionConditionDeadLetterSinkProvided)
+}
+
+// MarkNoDeadLetterSink sets the condition that the dead letter sink could not be resolved. The
+// receive adapter would drop the messages meant for it, so the PullSubscription is not ready either.
+func (s *PullSubscriptionStatus) MarkNoDeadLetterSink(reason, messageFormat string, messageA ...interface{}) {
+	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionDeadLetterSinkProvided, reason, messageFormat, messageA...)
+	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionReady, reason, messageFormat, messageA...)
+}
+
+// ClearDeadLetterSink removes the dead letter sink condition and URI, when the receive adapter
+// doesn't handle the dead letter sink.
+func (s *PullSubscriptionStatus) ClearDeadLetterSink() {
+	s.DeadLetterSinkURI = nil
+	// The condition is not terminal, so clearing it can't fail.
+	_ = pullSubscriptionCondSet.Manage(s).ClearCondition(PullSubscriptionConditionDeadLetterSinkProvided)
+}
+
 // MarkSubscribed sets the condition that the subscription has been created.
 func (s *PullSubscriptionStatus) MarkSubscribed(subscriptionID string) {
 	s.SubscriptionID = subscriptionID
@@ -273,6 +273,32 @@ func TestPullSubscriptionStatusIsReady(t *testing.T) {
 			}(),
 			wantConditionStatus: corev1.ConditionTrue,
 			want:                true,
+		}, {
+			name: "mark sink and deployed and subscribed and dead letter sink",
+			s: func() *PullSubscriptionStatus {
+				s := &PullSubscriptionStatus{}
+				s.InitializeConditions()
+				s.MarkSink(apis.HTTP("example"))
+				s.PropagateDeploymentAvailability(availableDeployment)
+				s.MarkSubscribed("subID")
+				s.MarkDeadLetterSink(apis.HTTP("dead-letter"))
+				return s
+			}(),
+			wantConditionStatus: corev1.ConditionTrue,
+			want:                true,
+		}, {
+			name: "mark sink and deployed and subscribed, then no dead letter sink",
+			s: func() *PullSubscriptionStatus {
+				s := &PullSubscriptionStatus{}
+				s.InitializeConditions()
+				s.MarkSink(apis.HTTP("example"))
+				s.PropagateDeploymentAvailability(availableDeployment)
+				s.MarkSubscribed("subID")
+				s.MarkNoDeadLetterSink("Testing", "")
+				return s
+			}(),
+			wantConditionStatus: corev1.ConditionFalse,
+			want:                false,
 		}}
 
 	for _, test := range tests {
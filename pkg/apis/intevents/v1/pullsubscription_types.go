@@ -94,6 +94,17 @@ type PullSubscriptionSpec struct {
 	// PullSubscription uses.
 	// +optional
 	AdapterType string `json:"adapterType,omitempty"`
+
+	// Mapping declares how the Pub/Sub messages are mapped to CloudEvents. It
+	// takes precedence over AdapterType.
+	// +optional
+	Mapping *v1.EventMapping `json:"mapping,omitempty"`
+
+	// Filter is an expression of the Pub/Sub filter language, e.g.
+	// `attributes.eventType = "created"`. Pub/Sub only delivers the messages
+	// that match it. Changing it recreates the Pub/Sub subscription.
+	// +optional
+	Filter string `json:"filter,omitempty"`
 }
 
 // GetAckDeadline parses AckDeadline and returns the default if an error occurs.
@@ -137,6 +148,11 @@ const (
 	// PullSubscriptionConditionTransformerProvided has status True when the
 	// PullSubscription has been configured with a transformer target.
 	PullSubscriptionConditionTransformerProvided apis.ConditionType = "TransformerProvided"
+
+	// PullSubscriptionConditionDeadLetterSinkProvided has status True when the
+	// dead letter sink handled by the receive adapter has been resolved. It is
+	// only set for dead letter sinks that are not Pub/Sub topics.
+	PullSubscriptionConditionDeadLetterSinkProvided apis.ConditionType = "DeadLetterSinkProvided"
 )
 
 var pullSubscriptionCondSet = apis.NewLivingConditionSet(
@@ -154,6 +170,12 @@ type PullSubscriptionStatus struct {
 	// +optional
 	TransformerURI *apis.URL `json:"transformerUri,omitempty"`
 
+	// DeadLetterSinkURI is the current active dead letter sink URI that has
+	// been configured for the PullSubscription. It is only set for dead letter
+	// sinks that aren't Pub/Sub topics.
+	// +optional
+	DeadLetterSinkURI *apis.URL `json:"deadLetterSinkUri,omitempty"`
+
 	// SubscriptionID is the created subscription ID used by the PullSubscription.
 	// +optional
 	SubscriptionID string `json:"subscriptionId,omitempty"`
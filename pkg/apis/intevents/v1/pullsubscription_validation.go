@@ -38,6 +38,7 @@ func (current *PullSubscription) Validate(ctx context.Context) *apis.FieldError
 		original := apis.GetBaseline(ctx).(*PullSubscription)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -89,6 +90,20 @@ func (current *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldEr
 		}
 	}
 
+	if err := duck.ValidateDelivery(ctx, current.Delivery); err != nil {
+		errs = errs.Also(err.ViaField("delivery"))
+	}
+
+	if current.Mapping != nil {
+		if err := current.Mapping.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaField("mapping"))
+		}
+	}
+
+	if err := duck.ValidateSubscriptionFilter(current.Filter); err != nil {
+		errs = errs.Also(err.ViaField("filter"))
+	}
+
 	return errs
 }
 
@@ -114,7 +129,7 @@ func (current *PullSubscription) CheckImmutableFields(ctx context.Context, origi
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(PullSubscriptionSpec{},
-			"Sink", "Transformer", "CloudEventOverrides")); diff != "" {
+			"Sink", "Transformer", "CloudEventOverrides", "Delivery", "Mapping", "Filter")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
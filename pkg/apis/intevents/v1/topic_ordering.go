@@ -0,0 +1,48 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"regexp"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// OrderingKeyAnnotation is the annotation key used to enable ordered publishing for the
+	// publisher of a Topic. The value is the name of the CloudEvent attribute, e.g. subject or a
+	// partitionkey extension, whose value is used as the Pub/Sub ordering key. Events with the
+	// same ordering key are published in the order they were accepted by the publisher.
+	OrderingKeyAnnotation = "events.cloud.google.com/orderingKey"
+)
+
+// attributeNameRegexp matches valid CloudEvent attribute names.
+var attributeNameRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// GetOrderingKeyAttribute returns the CloudEvent attribute used as the ordering key of the
+// messages published by the Topic publisher, or an empty string if ordering is not enabled.
+func (t *Topic) GetOrderingKeyAttribute() string {
+	return t.GetAnnotations()[OrderingKeyAnnotation]
+}
+
+func (t *Topic) validateOrderingKey() *apis.FieldError {
+	if attr, ok := t.GetAnnotations()[OrderingKeyAnnotation]; ok && !attributeNameRegexp.MatchString(attr) {
+		return apis.ErrInvalidValue(attr, fmt.Sprintf("metadata.annotations[%s]", OrderingKeyAnnotation))
+	}
+	return nil
+}
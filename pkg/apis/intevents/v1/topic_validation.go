@@ -34,6 +34,7 @@ func (t *Topic) Validate(ctx context.Context) *apis.FieldError {
 	testloggingutil.LogBasedOnAnnotations(logging.FromContext(ctx), t.Annotations)
 
 	err := t.Spec.Validate(ctx).ViaField("spec")
+	err = err.Also(t.validateOrderingKey())
 
 	if apis.IsInUpdate(ctx) {
 		original := apis.GetBaseline(ctx).(*Topic)
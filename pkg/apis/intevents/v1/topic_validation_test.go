@@ -89,6 +89,20 @@ func TestTopicValidation(t *testing.T) {
 		want: []string{
 			"invalid value: invalid-propagation-policy: spec.propagationPolicy",
 		},
+	}, {
+		name: "invalid ordering key attribute",
+		cr: &Topic{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{OrderingKeyAnnotation: "Partition-Key"},
+			},
+			Spec: TopicSpec{
+				Topic:             "topic",
+				PropagationPolicy: TopicPolicyCreateNoDelete,
+			},
+		},
+		want: []string{
+			"invalid value: Partition-Key: metadata.annotations[events.cloud.google.com/orderingKey]",
+		},
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
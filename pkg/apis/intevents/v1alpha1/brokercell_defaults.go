@@ -65,7 +65,9 @@ func (bcs *BrokerCellSpec) SetDefaults(ctx context.Context) {
 	bcs.Components.Fanout.setAutoScalingDefaults()
 	// Ingress defaults
 	if bcs.Components.Ingress == nil {
-		bcs.Components.Ingress = makeComponent(cpuRequestIngress, cpuLimitIngress, memoryRequestIngress, memoryLimitIngress, avgCPUUtilizationIngress, avgMemoryUsageIngress)
+		bcs.Components.Ingress = &IngressParameters{
+			ComponentParameters: *makeComponent(cpuRequestIngress, cpuLimitIngress, memoryRequestIngress, memoryLimitIngress, avgCPUUtilizationIngress, avgMemoryUsageIngress),
+		}
 	}
 	bcs.Components.Ingress.setAutoScalingDefaults()
 	// Retry defaults
@@ -67,7 +67,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 				},
 			},
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						CPURequest:        fanoutSpecPrefix + customCPURequest,
 						CPULimit:          fanoutSpecPrefix + customCPULimit,
@@ -78,15 +78,17 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 						MaxReplicas:       ptr.Int32(int32(fanoutSpecShift * customMaxReplicas)),
 						MinReplicas:       ptr.Int32(int32(fanoutSpecShift * customMinReplicas)),
 					},
-					Ingress: &ComponentParameters{
-						CPURequest:        ingressSpecPrefix + customCPURequest,
-						CPULimit:          ingressSpecPrefix + customCPULimit,
-						MemoryRequest:     ingressSpecPrefix + customMemoryRequest,
-						MemoryLimit:       ingressSpecPrefix + customMemoryLimit,
-						AvgCPUUtilization: ptr.Int32(int32(ingressSpecShift * customAvgCPUUtilization)),
-						AvgMemoryUsage:    ptr.String(ingressSpecPrefix + customAvgMemoryUsage),
-						MaxReplicas:       ptr.Int32(int32(ingressSpecShift * customMaxReplicas)),
-						MinReplicas:       ptr.Int32(int32(ingressSpecShift * customMinReplicas)),
+					Ingress: &IngressParameters{
+						ComponentParameters: ComponentParameters{
+							CPURequest:        ingressSpecPrefix + customCPURequest,
+							CPULimit:          ingressSpecPrefix + customCPULimit,
+							MemoryRequest:     ingressSpecPrefix + customMemoryRequest,
+							MemoryLimit:       ingressSpecPrefix + customMemoryLimit,
+							AvgCPUUtilization: ptr.Int32(int32(ingressSpecShift * customAvgCPUUtilization)),
+							AvgMemoryUsage:    ptr.String(ingressSpecPrefix + customAvgMemoryUsage),
+							MaxReplicas:       ptr.Int32(int32(ingressSpecShift * customMaxReplicas)),
+							MinReplicas:       ptr.Int32(int32(ingressSpecShift * customMinReplicas)),
+						},
 					},
 					Retry: &ComponentParameters{
 						CPURequest:        retrySpecPrefix + customCPURequest,
@@ -108,7 +110,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 				},
 			},
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						CPURequest:        fanoutSpecPrefix + customCPURequest,
 						CPULimit:          fanoutSpecPrefix + customCPULimit,
@@ -119,15 +121,17 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 						MaxReplicas:       ptr.Int32(int32(fanoutSpecShift * customMaxReplicas)),
 						MinReplicas:       ptr.Int32(int32(fanoutSpecShift * customMinReplicas)),
 					},
-					Ingress: &ComponentParameters{
-						CPURequest:        ingressSpecPrefix + customCPURequest,
-						CPULimit:          ingressSpecPrefix + customCPULimit,
-						MemoryRequest:     ingressSpecPrefix + customMemoryRequest,
-						MemoryLimit:       ingressSpecPrefix + customMemoryLimit,
-						AvgCPUUtilization: ptr.Int32(int32(ingressSpecShift * customAvgCPUUtilization)),
-						AvgMemoryUsage:    ptr.String(ingressSpecPrefix + customAvgMemoryUsage),
-						MaxReplicas:       ptr.Int32(int32(ingressSpecShift * customMaxReplicas)),
-						MinReplicas:       ptr.Int32(int32(ingressSpecShift * customMinReplicas)),
+					Ingress: &IngressParameters{
+						ComponentParameters: ComponentParameters{
+							CPURequest:        ingressSpecPrefix + customCPURequest,
+							CPULimit:          ingressSpecPrefix + customCPULimit,
+							MemoryRequest:     ingressSpecPrefix + customMemoryRequest,
+							MemoryLimit:       ingressSpecPrefix + customMemoryLimit,
+							AvgCPUUtilization: ptr.Int32(int32(ingressSpecShift * customAvgCPUUtilization)),
+							AvgMemoryUsage:    ptr.String(ingressSpecPrefix + customAvgMemoryUsage),
+							MaxReplicas:       ptr.Int32(int32(ingressSpecShift * customMaxReplicas)),
+							MinReplicas:       ptr.Int32(int32(ingressSpecShift * customMinReplicas)),
+						},
 					},
 					Retry: &ComponentParameters{
 						CPURequest:        retrySpecPrefix + customCPURequest,
@@ -146,7 +150,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		name: "Defaulting for resource specification is not applied when some of the parameters are specified",
 		start: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						CPURequest: "10000",
 					},
@@ -155,7 +159,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		},
 		want: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: (&ComponentParameters{
 						CPURequest:        "10000",
 						CPULimit:          "",
@@ -164,7 +168,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 						AvgCPUUtilization: nil,
 						AvgMemoryUsage:    nil,
 					}).WithDefaultReplicas(),
-					Ingress: makeComponent(cpuRequestIngress, cpuLimitIngress, memoryRequestIngress, memoryLimitIngress, avgCPUUtilizationIngress, avgMemoryUsageIngress).WithDefaultReplicas(),
+					Ingress: &IngressParameters{ComponentParameters: *makeComponent(cpuRequestIngress, cpuLimitIngress, memoryRequestIngress, memoryLimitIngress, avgCPUUtilizationIngress, avgMemoryUsageIngress).WithDefaultReplicas()},
 					Retry:   makeComponent(cpuRequestRetry, cpuLimitRetry, memoryRequestRetry, memoryLimitRetry, avgCPUUtilizationRetry, avgMemoryUsageRetry).WithDefaultReplicas(),
 				},
 			},
@@ -173,7 +177,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		name: "Defaulting for resource specification is not applied when a target CPU or memory parameter is specified",
 		start: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: &ComponentParameters{
 						AvgCPUUtilization: ptr.Int32(95),
 					},
@@ -182,7 +186,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 		},
 		want: &BrokerCell{
 			Spec: BrokerCellSpec{
-				ComponentsParametersSpec{
+				Components: ComponentsParametersSpec{
 					Fanout: (&ComponentParameters{
 						AvgCPUUtilization: ptr.Int32(95),
 						AvgMemoryUsage:    nil,
@@ -191,7 +195,7 @@ func TestBrokerCell_SetDefaults(t *testing.T) {
 						MemoryRequest:     "",
 						MemoryLimit:       "",
 					}).WithDefaultReplicas(),
-					Ingress: makeComponent(cpuRequestIngress, cpuLimitIngress, memoryRequestIngress, memoryLimitIngress, avgCPUUtilizationIngress, avgMemoryUsageIngress).WithDefaultReplicas(),
+					Ingress: &IngressParameters{ComponentParameters: *makeComponent(cpuRequestIngress, cpuLimitIngress, memoryRequestIngress, memoryLimitIngress, avgCPUUtilizationIngress, avgMemoryUsageIngress).WithDefaultReplicas()},
 					Retry:   makeComponent(cpuRequestRetry, cpuLimitRetry, memoryRequestRetry, memoryLimitRetry, avgCPUUtilizationRetry, avgMemoryUsageRetry).WithDefaultReplicas(),
 				},
 			},
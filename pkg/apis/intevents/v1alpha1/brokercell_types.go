@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -111,11 +112,46 @@ type ComponentParameters struct {
 	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
 }
 
+// IngressParameters specifies the parameters of the ingress of a BrokerCell.
+type IngressParameters struct {
+	ComponentParameters `json:",inline"`
+
+	// Publish specifies how the ingress publishes events to Pub/Sub.
+	// +optional
+	Publish *PublishSettings `json:"publish,omitempty"`
+}
+
+// PublishSettings specifies how the ingress batches the events it publishes to Pub/Sub. The
+// Pub/Sub client defaults are used for the settings that are not set.
+type PublishSettings struct {
+	// DelayThreshold is the maximum time events are buffered before they are published, e.g. 10ms.
+	// +optional
+	DelayThreshold string `json:"delayThreshold,omitempty"`
+
+	// CountThreshold is the number of buffered events of a Broker that triggers a publish.
+	// +optional
+	CountThreshold *int32 `json:"countThreshold,omitempty"`
+
+	// ByteThreshold is the size of the buffered events of a Broker that triggers a publish, e.g. 1Mi.
+	// +optional
+	ByteThreshold string `json:"byteThreshold,omitempty"`
+
+	// BufferedByteLimit is the maximum size of the events of a Broker buffered by an ingress
+	// replica. Events over the limit are rejected with 429 Too Many Requests, e.g. 300Mi.
+	// +optional
+	BufferedByteLimit string `json:"bufferedByteLimit,omitempty"`
+
+	// Timeout is the maximum time the ingress waits for an event to be published before failing
+	// the request, e.g. 30s.
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+}
+
 // ComponentsParametersSpec specifies separate parameters for each component
 // of a BrokerCell.
 type ComponentsParametersSpec struct {
 	Fanout  *ComponentParameters `json:"fanout,omitempty"`
-	Ingress *ComponentParameters `json:"ingress,omitempty"`
+	Ingress *IngressParameters   `json:"ingress,omitempty"`
 	Retry   *ComponentParameters `json:"retry,omitempty"`
 }
 
@@ -124,6 +160,94 @@ type BrokerCellSpec struct {
 	// Components specifies parameters of each component (fanout, ingress,
 	// retry) of a BrokerCell.
 	Components ComponentsParametersSpec `json:"components,omitempty"`
+
+	// IngressAuth specifies how the ingress authenticates the requests sent
+	// to the Brokers of the BrokerCell. Brokers can override it with the
+	// events.cloud.google.com/ingressAuth and
+	// events.cloud.google.com/ingressAllowedPrincipals annotations.
+	// +optional
+	IngressAuth *IngressAuthPolicy `json:"ingressAuth,omitempty"`
+
+	// IngressQuotas limits the rate of the events the ingress accepts for
+	// the Brokers of each namespace. Brokers can set their own quota with the
+	// events.cloud.google.com/ingressEventsPerSecond and
+	// events.cloud.google.com/ingressBytesPerSecond annotations.
+	// +optional
+	IngressQuotas *IngressQuotas `json:"ingressQuotas,omitempty"`
+}
+
+// IngressAuthMode is whether the requests sent to a Broker must carry a
+// bearer token.
+type IngressAuthMode string
+
+const (
+	// IngressAuthRequired requires a Kubernetes service account token or a
+	// Google-signed ID token issued for the audience of the Broker.
+	IngressAuthRequired IngressAuthMode = "Required"
+	// IngressAuthDisabled accepts any request, it is the default.
+	IngressAuthDisabled IngressAuthMode = "Disabled"
+)
+
+// IngressAuthPolicy specifies how the ingress authenticates requests.
+type IngressAuthPolicy struct {
+	// Mode is either Required or Disabled. Defaults to Disabled.
+	// +optional
+	Mode IngressAuthMode `json:"mode,omitempty"`
+
+	// AllowedPrincipals are the token subjects, e.g.
+	// system:serviceaccount:my-namespace:my-sa, or the Google service account
+	// emails allowed to send events. A trailing * matches any suffix. Any
+	// authenticated principal is allowed if empty.
+	// +optional
+	AllowedPrincipals []string `json:"allowedPrincipals,omitempty"`
+}
+
+// IngressQuotas specifies the quotas of the namespaces using a BrokerCell.
+type IngressQuotas struct {
+	// Default is the quota of each namespace that is not in Namespaces. The
+	// namespaces are not limited if unset.
+	// +optional
+	Default *Quota `json:"default,omitempty"`
+
+	// Namespaces are the quotas of specific namespaces, keyed by namespace.
+	// +optional
+	Namespaces map[string]Quota `json:"namespaces,omitempty"`
+}
+
+// Quota limits the rate of the events accepted by the ingress. Limits that
+// are not set are not enforced.
+type Quota struct {
+	// EventsPerSecond is the maximum number of events accepted per second.
+	// +optional
+	EventsPerSecond int64 `json:"eventsPerSecond,omitempty"`
+
+	// BytesPerSecond is the maximum size of the data of the events accepted
+	// per second, as a quantity, e.g. 10Mi.
+	// +optional
+	BytesPerSecond string `json:"bytesPerSecond,omitempty"`
+}
+
+// ForNamespace returns the quota of the namespace, or nil if it is not limited.
+func (q *IngressQuotas) ForNamespace(ns string) *Quota {
+	if q == nil {
+		return nil
+	}
+	if quota, ok := q.Namespaces[ns]; ok {
+		return &quota
+	}
+	return q.Default
+}
+
+// Bytes returns BytesPerSecond in bytes, or zero if it is not set or invalid.
+func (q *Quota) Bytes() int64 {
+	if q.BytesPerSecond == "" {
+		return 0
+	}
+	v, err := resource.ParseQuantity(q.BytesPerSecond)
+	if err != nil {
+		return 0
+	}
+	return v.Value()
 }
 
 // BrokerCellStatus represents the current state of a BrokerCell.
@@ -64,3 +64,22 @@ func TestBrokerCell_GetStatus(t *testing.T) {
 		t.Errorf("GetStatus=%v, want=%v", got, want)
 	}
 }
+
+func TestIngressQuotas_ForNamespace(t *testing.T) {
+	quotas := &IngressQuotas{
+		Default: &Quota{EventsPerSecond: 100},
+		Namespaces: map[string]Quota{
+			"ns": {EventsPerSecond: 1000, BytesPerSecond: "1Ki"},
+		},
+	}
+	if got := quotas.ForNamespace("ns"); got == nil || got.EventsPerSecond != 1000 || got.Bytes() != 1024 {
+		t.Errorf("ForNamespace(ns) = %+v, want the quota of ns", got)
+	}
+	if got := quotas.ForNamespace("other"); got == nil || got.EventsPerSecond != 100 || got.Bytes() != 0 {
+		t.Errorf("ForNamespace(other) = %+v, want the default quota", got)
+	}
+	var unset *IngressQuotas
+	if got := unset.ForNamespace("ns"); got != nil {
+		t.Errorf("ForNamespace(ns) = %+v, want nil", got)
+	}
+}
@@ -19,7 +19,9 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"cloud.google.com/go/pubsub"
 	resourceutil "github.com/google/knative-gcp/pkg/utils/resource"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/pkg/apis"
@@ -38,13 +40,92 @@ func (bcs *BrokerCellSpec) Validate(ctx context.Context) *apis.FieldError {
 	}
 	if bcs.Components.Ingress != nil {
 		fieldErrors = bcs.Components.Ingress.ValidateResourceRequirementSpecification(fieldErrors, "components.ingress")
+		if bcs.Components.Ingress.Publish != nil {
+			fieldErrors = fieldErrors.Also(bcs.Components.Ingress.Publish.Validate(ctx).ViaField("components.ingress.publish"))
+		}
 	}
 	if bcs.Components.Retry != nil {
 		fieldErrors = bcs.Components.Retry.ValidateResourceRequirementSpecification(fieldErrors, "components.retry")
 	}
+	if bcs.IngressAuth != nil {
+		fieldErrors = fieldErrors.Also(bcs.IngressAuth.Validate(ctx).ViaField("ingressAuth"))
+	}
+	if bcs.IngressQuotas != nil {
+		fieldErrors = fieldErrors.Also(bcs.IngressQuotas.Validate(ctx).ViaField("ingressQuotas"))
+	}
 	return fieldErrors
 }
 
+func (q *IngressQuotas) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if q.Default != nil {
+		errs = errs.Also(q.Default.Validate(ctx).ViaField("default"))
+	}
+	for ns, quota := range q.Namespaces {
+		errs = errs.Also(quota.Validate(ctx).ViaFieldKey("namespaces", ns))
+	}
+	return errs
+}
+
+func (q *Quota) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if q.EventsPerSecond < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(q.EventsPerSecond, "eventsPerSecond"))
+	}
+	if q.BytesPerSecond != "" {
+		if v, err := resource.ParseQuantity(q.BytesPerSecond); err != nil || v.Sign() <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(q.BytesPerSecond, "bytesPerSecond"))
+		}
+	}
+	return errs
+}
+
+func (p *PublishSettings) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	for _, d := range []struct {
+		field string
+		value string
+	}{{"delayThreshold", p.DelayThreshold}, {"timeout", p.Timeout}} {
+		if d.value == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d.value); err != nil || v <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(d.value, d.field))
+		}
+	}
+	if p.CountThreshold != nil && (*p.CountThreshold < 1 || *p.CountThreshold > pubsub.MaxPublishRequestCount) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*p.CountThreshold, 1, pubsub.MaxPublishRequestCount, "countThreshold"))
+	}
+	if p.ByteThreshold != "" {
+		if v, err := resource.ParseQuantity(p.ByteThreshold); err != nil || v.Sign() <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(p.ByteThreshold, "byteThreshold"))
+		} else if v.Value() > pubsub.MaxPublishRequestBytes {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(p.ByteThreshold, 1, int64(pubsub.MaxPublishRequestBytes), "byteThreshold"))
+		}
+	}
+	if p.BufferedByteLimit != "" {
+		if v, err := resource.ParseQuantity(p.BufferedByteLimit); err != nil || v.Sign() <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(p.BufferedByteLimit, "bufferedByteLimit"))
+		}
+	}
+	return errs
+}
+
+func (p *IngressAuthPolicy) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	switch p.Mode {
+	case "", IngressAuthRequired, IngressAuthDisabled:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(p.Mode, "mode"))
+	}
+	for i, principal := range p.AllowedPrincipals {
+		if principal == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(principal, "allowedPrincipals", i))
+		}
+	}
+	return errs
+}
+
 func (componentParams *ComponentParameters) ValidateResourceRequirementSpecification(fieldErrors *apis.FieldError, componentPath string) *apis.FieldError {
 	fieldErrors = componentParams.ValidateQuantityFormats(fieldErrors, componentPath)
 	fieldErrors = componentParams.ValidateResourceSpecification(fieldErrors, componentPath)
@@ -180,6 +180,106 @@ func TestBrokerCell_Validate(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "Valid ingress auth policy",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.IngressAuth = &IngressAuthPolicy{
+						Mode:              IngressAuthRequired,
+						AllowedPrincipals: []string{"system:serviceaccount:ns:*"},
+					}
+					return spec
+				}()),
+			},
+			want: nil,
+		},
+		{
+			name: "Invalid ingress auth policy",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.IngressAuth = &IngressAuthPolicy{
+						Mode:              "Optional",
+						AllowedPrincipals: []string{""},
+					}
+					return spec
+				}()),
+			},
+			want: apis.ErrInvalidValue("Optional", "spec.ingressAuth.mode").Also(
+				apis.ErrInvalidArrayValue("", "spec.ingressAuth.allowedPrincipals", 0)),
+		},
+		{
+			name: "Valid ingress publish settings",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Components.Ingress.Publish = &PublishSettings{
+						DelayThreshold:    "50ms",
+						CountThreshold:    ptr.Int32(500),
+						ByteThreshold:     "5Mi",
+						BufferedByteLimit: "500Mi",
+						Timeout:           "1m",
+					}
+					return spec
+				}()),
+			},
+			want: nil,
+		},
+		{
+			name: "Invalid ingress publish settings",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.Components.Ingress.Publish = &PublishSettings{
+						DelayThreshold:    "soon",
+						CountThreshold:    ptr.Int32(0),
+						ByteThreshold:     "20Mi",
+						BufferedByteLimit: "-1",
+						Timeout:           "0s",
+					}
+					return spec
+				}()),
+			},
+			want: apis.ErrInvalidValue("soon", "spec.components.ingress.publish.delayThreshold").Also(
+				apis.ErrInvalidValue("0s", "spec.components.ingress.publish.timeout"),
+				apis.ErrOutOfBoundsValue(0, 1, 1000, "spec.components.ingress.publish.countThreshold"),
+				apis.ErrOutOfBoundsValue("20Mi", 1, 10000000, "spec.components.ingress.publish.byteThreshold"),
+				apis.ErrInvalidValue("-1", "spec.components.ingress.publish.bufferedByteLimit")),
+		},
+		{
+			name: "Valid ingress quotas",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.IngressQuotas = &IngressQuotas{
+						Default: &Quota{EventsPerSecond: 100},
+						Namespaces: map[string]Quota{
+							"ns": {EventsPerSecond: 1000, BytesPerSecond: "10Mi"},
+						},
+					}
+					return spec
+				}()),
+			},
+			want: nil,
+		},
+		{
+			name: "Invalid ingress quotas",
+			brokerCell: BrokerCell{
+				Spec: (func() BrokerCellSpec {
+					spec := MakeDefaultBrokerCellSpec()
+					spec.IngressQuotas = &IngressQuotas{
+						Default: &Quota{EventsPerSecond: -1},
+						Namespaces: map[string]Quota{
+							"ns": {BytesPerSecond: "invalid"},
+						},
+					}
+					return spec
+				}()),
+			},
+			want: apis.ErrInvalidValue(-1, "spec.ingressQuotas.default.eventsPerSecond").Also(
+				apis.ErrInvalidValue("invalid", "spec.ingressQuotas.namespaces[ns].bytesPerSecond")),
+		},
 	}
 
 	for _, test := range tests {
@@ -89,6 +89,16 @@ func (in *BrokerCellList) DeepCopyObject() runtime.Object {
 func (in *BrokerCellSpec) DeepCopyInto(out *BrokerCellSpec) {
 	*out = *in
 	in.Components.DeepCopyInto(&out.Components)
+	if in.IngressAuth != nil {
+		in, out := &in.IngressAuth, &out.IngressAuth
+		*out = new(IngressAuthPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngressQuotas != nil {
+		in, out := &in.IngressQuotas, &out.IngressQuotas
+		*out = new(IngressQuotas)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -165,7 +175,7 @@ func (in *ComponentsParametersSpec) DeepCopyInto(out *ComponentsParametersSpec)
 	}
 	if in.Ingress != nil {
 		in, out := &in.Ingress, &out.Ingress
-		*out = new(ComponentParameters)
+		*out = new(IngressParameters)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Retry != nil {
@@ -186,6 +196,114 @@ func (in *ComponentsParametersSpec) DeepCopy() *ComponentsParametersSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressAuthPolicy) DeepCopyInto(out *IngressAuthPolicy) {
+	*out = *in
+	if in.AllowedPrincipals != nil {
+		in, out := &in.AllowedPrincipals, &out.AllowedPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressAuthPolicy.
+func (in *IngressAuthPolicy) DeepCopy() *IngressAuthPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressAuthPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressParameters) DeepCopyInto(out *IngressParameters) {
+	*out = *in
+	in.ComponentParameters.DeepCopyInto(&out.ComponentParameters)
+	if in.Publish != nil {
+		in, out := &in.Publish, &out.Publish
+		*out = new(PublishSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressParameters.
+func (in *IngressParameters) DeepCopy() *IngressParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressQuotas) DeepCopyInto(out *IngressQuotas) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(Quota)
+		**out = **in
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make(map[string]Quota, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressQuotas.
+func (in *IngressQuotas) DeepCopy() *IngressQuotas {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressQuotas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublishSettings) DeepCopyInto(out *PublishSettings) {
+	*out = *in
+	if in.CountThreshold != nil {
+		in, out := &in.CountThreshold, &out.CountThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublishSettings.
+func (in *PublishSettings) DeepCopy() *PublishSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PublishSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Quota) DeepCopyInto(out *Quota) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quota.
+func (in *Quota) DeepCopy() *Quota {
+	if in == nil {
+		return nil
+	}
+	out := new(Quota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSpecification) DeepCopyInto(out *ResourceSpecification) {
 	*out = *in
@@ -38,6 +38,7 @@ func (current *PullSubscription) Validate(ctx context.Context) *apis.FieldError
 		original := apis.GetBaseline(ctx).(*PullSubscription)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = errs.Also(duck.ValidateReceiveAdapterCredential(current.Annotations, current.Spec.Secret, current.Spec.ServiceAccountName))
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
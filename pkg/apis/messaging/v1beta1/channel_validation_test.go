@@ -103,7 +103,7 @@ func TestChannelValidation(t *testing.T) {
 								BackoffDelay:  &backoffDelay,
 								BackoffPolicy: &backoffPolicy,
 								DeadLetterSink: &pkgduckv1.Destination{
-									URI: apis.HTTP("example.com"),
+									URI: &apis.URL{Scheme: "ftp", Host: "example.com"},
 								},
 							},
 						},
@@ -113,7 +113,7 @@ func TestChannelValidation(t *testing.T) {
 		},
 		want: func() *apis.FieldError {
 			var errs *apis.FieldError
-			fe := apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub", "uri")
+			fe := apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub, http or https", "uri")
 			errs = errs.Also(fe.ViaField("spec.delivery.subscriber[0].deadLetterSink"))
 			return errs
 		}(),
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// OpenCircuitsAnnotation is the annotation of broker data plane pods that lists, separated by
+// commas, the IDs of the targets whose circuit breaker is open or half-open in the pod. Target IDs
+// are the UIDs of Triggers and Channel subscribers.
+const OpenCircuitsAnnotation = "events.cloud.google.com/openCircuits"
+
+// AnnotatePod updates the OpenCircuitsAnnotation of the pod whenever the open circuit breakers
+// change, checking them every period, until the context is done.
+func (r *Registry) AnnotatePod(ctx context.Context, kubeClient kubernetes.Interface, namespace, name string, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	// Pods start without open circuit breakers.
+	var last string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		value := strings.Join(r.OpenTargetIDs(), ",")
+		if value == last {
+			continue
+		}
+		if err := patchOpenCircuits(ctx, kubeClient, namespace, name, value); err != nil {
+			logging.FromContext(ctx).Warn("Failed to annotate pod with open circuit breakers", zap.Error(err))
+			continue
+		}
+		last = value
+	}
+}
+
+func patchOpenCircuits(ctx context.Context, kubeClient kubernetes.Interface, namespace, name, value string) error {
+	// A nil value removes the annotation.
+	var annotation interface{}
+	if value != "" {
+		annotation = value
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				OpenCircuitsAnnotation: annotation,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := kubeClient.CoreV1().Pods(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error patching annotations of pod %v/%v: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// OpenCircuitIDs parses the OpenCircuitsAnnotation of a pod.
+func OpenCircuitIDs(annotations map[string]string) []string {
+	value := annotations[OpenCircuitsAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
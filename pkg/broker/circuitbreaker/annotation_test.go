@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestAnnotatePod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"},
+	})
+	r := NewRegistry(Settings{FailureThreshold: 1, OpenTimeout: time.Minute}, nil)
+	go r.AnnotatePod(ctx, kubeClient, "ns", "pod", time.Millisecond)
+
+	waitForOpenCircuits := func(want []string) {
+		t.Helper()
+		var got []string
+		for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+			pod, err := kubeClient.CoreV1().Pods("ns").Get(ctx, "pod", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got = OpenCircuitIDs(pod.Annotations); cmp.Equal(want, got) {
+				return
+			}
+		}
+		t.Errorf("open circuits annotation (-want,+got): %s", cmp.Diff(want, got))
+	}
+
+	for _, id := range []string{"uid-2", "uid-1"} {
+		r.Record(ctx, &config.Target{Id: id, Name: id}, false)
+	}
+	waitForOpenCircuits([]string{"uid-1", "uid-2"})
+	r.Record(ctx, &config.Target{Id: "uid-1", Name: "uid-1"}, true)
+	r.Record(ctx, &config.Target{Id: "uid-2", Name: "uid-2"}, true)
+	waitForOpenCircuits(nil)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package circuitbreaker implements per target circuit breakers for the broker data plane.
+package circuitbreaker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed lets every delivery to the target through.
+	Closed State = iota
+	// Open short-circuits every delivery to the target.
+	Open
+	// HalfOpen lets a single probe delivery to the target through, which closes the circuit
+	// breaker if it succeeds, or opens it again if it fails.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Settings configures the circuit breakers of a Registry.
+type Settings struct {
+	// FailureThreshold is the number of consecutive failed deliveries to a target that opens its
+	// circuit breaker. Circuit breakers are disabled if it is not positive.
+	FailureThreshold int
+	// OpenTimeout is the time an open circuit breaker waits before letting a probe delivery
+	// through.
+	OpenTimeout time.Duration
+}
+
+// Registry holds the circuit breaker of each target.
+type Registry struct {
+	settings Settings
+	// onStateChange is called with the context of the delivery that changed the state of a
+	// circuit breaker.
+	onStateChange func(context.Context, State)
+	now           func() time.Time
+
+	mux      sync.Mutex
+	breakers map[config.TargetKey]*breaker
+}
+
+type breaker struct {
+	id       string
+	state    State
+	failures int
+	// since is when the breaker opened, or when the current probe started if it is half-open.
+	since time.Time
+}
+
+// NewRegistry creates a Registry. onStateChange, if not nil, is called whenever a circuit breaker
+// changes state, e.g. to report metrics.
+func NewRegistry(settings Settings, onStateChange func(context.Context, State)) *Registry {
+	return &Registry{
+		settings:      settings,
+		onStateChange: onStateChange,
+		now:           time.Now,
+		breakers:      make(map[config.TargetKey]*breaker),
+	}
+}
+
+// Allow returns whether a delivery to the target may be attempted. The result of every allowed
+// delivery must be reported with Record.
+func (r *Registry) Allow(ctx context.Context, target *config.Target) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	b := r.breaker(target)
+	switch b.state {
+	case Open:
+		if r.now().Sub(b.since) < r.settings.OpenTimeout {
+			return false
+		}
+		r.setState(ctx, b, HalfOpen)
+		return true
+	case HalfOpen:
+		// Only one probe is let through at a time. Another probe is let through if the
+		// current one did not report its result in time.
+		if r.now().Sub(b.since) < r.settings.OpenTimeout {
+			return false
+		}
+		b.since = r.now()
+		return true
+	default:
+		return true
+	}
+}
+
+// Record records the result of a delivery to the target.
+func (r *Registry) Record(ctx context.Context, target *config.Target, success bool) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	b := r.breaker(target)
+	if success {
+		b.failures = 0
+		if b.state != Closed {
+			r.setState(ctx, b, Closed)
+		}
+		return
+	}
+	b.failures++
+	switch b.state {
+	case Closed:
+		if b.failures >= r.settings.FailureThreshold {
+			r.setState(ctx, b, Open)
+		}
+	case HalfOpen:
+		r.setState(ctx, b, Open)
+	}
+}
+
+// State returns the state of the circuit breaker of the target.
+func (r *Registry) State(target *config.Target) State {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if b, ok := r.breakers[*target.Key()]; ok {
+		return b.state
+	}
+	return Closed
+}
+
+// RetryAfter returns the time until the circuit breaker of the target lets a probe delivery
+// through, or zero if it is closed.
+func (r *Registry) RetryAfter(target *config.Target) time.Duration {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	b, ok := r.breakers[*target.Key()]
+	if !ok || b.state == Closed {
+		return 0
+	}
+	if d := r.settings.OpenTimeout - r.now().Sub(b.since); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// OpenTargetIDs returns the sorted IDs of the targets whose circuit breaker is open or half-open.
+func (r *Registry) OpenTargetIDs() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var ids []string
+	for _, b := range r.breakers {
+		if b.state != Closed && b.id != "" {
+			ids = append(ids, b.id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Forget removes the circuit breakers of the targets that are not in the given config.
+func (r *Registry) Forget(targets config.ReadonlyTargets) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for key := range r.breakers {
+		key := key
+		if _, ok := targets.GetTargetByKey(&key); !ok {
+			delete(r.breakers, key)
+		}
+	}
+}
+
+func (r *Registry) breaker(target *config.Target) *breaker {
+	key := *target.Key()
+	b, ok := r.breakers[key]
+	if !ok {
+		b = &breaker{}
+		r.breakers[key] = b
+	}
+	b.id = target.Id
+	return b
+}
+
+func (r *Registry) setState(ctx context.Context, b *breaker, state State) {
+	b.state = state
+	b.since = r.now()
+	if state == Closed {
+		b.failures = 0
+	}
+	if r.onStateChange != nil {
+		r.onStateChange(ctx, state)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	target := &config.Target{
+		Id:             "uid",
+		Name:           "target",
+		Namespace:      "ns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+	}
+	now := time.Now()
+	var changes []State
+	r := NewRegistry(Settings{FailureThreshold: 2, OpenTimeout: time.Minute}, func(_ context.Context, s State) {
+		changes = append(changes, s)
+	})
+	r.now = func() time.Time { return now }
+
+	expectState := func(want State, wantAllow bool) {
+		t.Helper()
+		if got := r.State(target); got != want {
+			t.Errorf("State got=%v, want=%v", got, want)
+		}
+		if got := r.Allow(ctx, target); got != wantAllow {
+			t.Errorf("Allow got=%v, want=%v", got, wantAllow)
+		}
+	}
+
+	expectState(Closed, true)
+	r.Record(ctx, target, false)
+	r.Record(ctx, target, true)
+	r.Record(ctx, target, false)
+	// Failures must be consecutive.
+	expectState(Closed, true)
+	r.Record(ctx, target, false)
+	expectState(Open, false)
+	if diff := cmp.Diff([]string{"uid"}, r.OpenTargetIDs()); diff != "" {
+		t.Errorf("OpenTargetIDs (-want,+got): %s", diff)
+	}
+	now = now.Add(20 * time.Second)
+	if got, want := r.RetryAfter(target), 40*time.Second; got != want {
+		t.Errorf("RetryAfter got=%v, want=%v", got, want)
+	}
+	now = now.Add(40 * time.Second)
+	if got := r.RetryAfter(target); got != 0 {
+		t.Errorf("RetryAfter after the open timeout got=%v, want=0", got)
+	}
+
+	// A single probe is let through after the open timeout.
+	expectState(Open, true)
+	expectState(HalfOpen, false)
+	// A failed probe opens the circuit breaker again.
+	r.Record(ctx, target, false)
+	expectState(Open, false)
+
+	// Another probe is let through if the current one does not report its result in time.
+	now = now.Add(time.Minute)
+	expectState(Open, true)
+	now = now.Add(time.Minute)
+	expectState(HalfOpen, true)
+	// A successful probe closes the circuit breaker.
+	r.Record(ctx, target, true)
+	expectState(Closed, true)
+	if ids := r.OpenTargetIDs(); len(ids) != 0 {
+		t.Errorf("OpenTargetIDs got=%v, want none", ids)
+	}
+
+	if diff := cmp.Diff([]State{Open, HalfOpen, Open, HalfOpen, Closed}, changes); diff != "" {
+		t.Errorf("state changes (-want,+got): %s", diff)
+	}
+
+	// Circuit breakers of deleted targets are forgotten.
+	r.Record(ctx, target, false)
+	r.Record(ctx, target, false)
+	r.Forget(memory.NewEmptyTargets())
+	if ids := r.OpenTargetIDs(); len(ids) != 0 {
+		t.Errorf("OpenTargetIDs got=%v after forgetting the target, want none", ids)
+	}
+}
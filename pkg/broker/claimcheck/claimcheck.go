@@ -0,0 +1,183 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claimcheck offloads the data of large events to Cloud Storage, so that events larger
+// than a Pub/Sub message can be routed through a broker. The ingress writes the data of such an
+// event to an object and replaces it with a reference to the object in the claimcheck extension.
+// The fanout and retry rehydrate the data before delivering the event to a subscriber.
+package claimcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	gstorage "github.com/google/knative-gcp/pkg/gclient/storage"
+)
+
+const (
+	// Extension is the CloudEvents extension holding the reference to the object of an offloaded
+	// event, e.g. gs://bucket/claimcheck/<broker id>/<uuid>.
+	Extension = "claimcheck"
+
+	// DefaultThreshold is the default data size in bytes above which the ingress offloads an
+	// event. It leaves room for the attributes of the event within the Pub/Sub message limit.
+	DefaultThreshold = 5000000
+
+	// DefaultRetention is the default age after which offloaded objects are deleted. It matches
+	// the maximum retention of Pub/Sub messages, so the data of an event is kept as long as the
+	// event can still be delivered.
+	DefaultRetention = 7 * 24 * time.Hour
+
+	scheme       = "gs://"
+	objectPrefix = "claimcheck/"
+)
+
+// ErrInvalidReference is returned when the claimcheck extension of an event does not reference
+// an object of the broker in the bucket of the store.
+var ErrInvalidReference = errors.New("invalid claim check reference")
+
+// Store offloads the data of events to the objects of a bucket, and rehydrates it.
+type Store struct {
+	client gstorage.Client
+	bucket string
+}
+
+// NewStore creates a Store that uses the objects of the given bucket.
+func NewStore(client gstorage.Client, bucket string) *Store {
+	return &Store{client: client, bucket: bucket}
+}
+
+// Offload writes the data of an event sent to the broker to a new object, then replaces the data
+// of the event with a reference to the object.
+func (s *Store) Offload(ctx context.Context, brokerID string, e *cev2.Event) error {
+	name := objectPrefix + brokerID + "/" + uuid.New().String()
+	w := s.client.Bucket(s.bucket).Object(name).NewWriter(ctx)
+	if _, err := w.Write(e.Data()); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write object %q: %w", name, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write object %q: %w", name, err)
+	}
+	e.DataEncoded = nil
+	e.SetExtension(Extension, scheme+s.bucket+"/"+name)
+	return nil
+}
+
+// Delete deletes the object referenced by an event offloaded by Offload. It is used when the
+// event is not published, so the object does not wait for the garbage collection.
+func (s *Store) Delete(ctx context.Context, brokerID string, e *cev2.Event) error {
+	name, err := s.objectName(brokerID, e)
+	if err != nil || name == "" {
+		return err
+	}
+	if err := s.client.Bucket(s.bucket).Object(name).Delete(ctx); err != nil && err != storage.ErrObjectNotExist {
+		return err
+	}
+	return nil
+}
+
+// Check returns ErrInvalidReference if the event has a claimcheck extension that does not
+// reference an object of the broker. The ingress uses it to prevent producers from reading
+// arbitrary objects through the rehydration.
+func (s *Store) Check(brokerID string, e *cev2.Event) error {
+	_, err := s.objectName(brokerID, e)
+	return err
+}
+
+// Rehydrate returns a copy of the event with the data read from the object it references, without
+// the claimcheck extension. It returns the event itself if it has no reference.
+func (s *Store) Rehydrate(ctx context.Context, brokerID string, e *cev2.Event) (*cev2.Event, error) {
+	name, err := s.objectName(brokerID, e)
+	if err != nil || name == "" {
+		return e, err
+	}
+	r, err := s.client.Bucket(s.bucket).Object(name).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %q: %w", name, err)
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %q: %w", name, err)
+	}
+	rehydrated := e.Clone()
+	rehydrated.SetExtension(Extension, nil)
+	rehydrated.DataEncoded = data
+	return &rehydrated, nil
+}
+
+// objectName returns the name of the object referenced by the event, or an empty name if the
+// event has no reference.
+func (s *Store) objectName(brokerID string, e *cev2.Event) (string, error) {
+	v, ok := e.Extensions()[Extension]
+	if !ok {
+		return "", nil
+	}
+	ref, ok := v.(string)
+	if !ok {
+		return "", ErrInvalidReference
+	}
+	prefix := scheme + s.bucket + "/" + objectPrefix + brokerID + "/"
+	id := strings.TrimPrefix(ref, prefix)
+	if brokerID == "" || id == ref || id == "" || strings.Contains(id, "/") {
+		return "", ErrInvalidReference
+	}
+	return objectPrefix + brokerID + "/" + id, nil
+}
+
+// EnsureLifecycle ensures that the bucket has a lifecycle rule deleting the objects older than
+// retention, rounded up to whole days. Cloud Storage deletes the expired objects itself, so the
+// ingress replicas don't have to list the bucket. The rule applies to all the objects of the
+// bucket, so the bucket should be dedicated to the offloaded data.
+func (s *Store) EnsureLifecycle(ctx context.Context, retention time.Duration) error {
+	days := int64((retention + 24*time.Hour - 1) / (24 * time.Hour))
+	if days < 1 {
+		days = 1
+	}
+	bucket := s.client.Bucket(s.bucket)
+	attrs, err := bucket.Attrs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the attributes of bucket %q: %w", s.bucket, err)
+	}
+	lifecycle := attrs.Lifecycle
+	for _, rule := range lifecycle.Rules {
+		if rule.Action.Type == storage.DeleteAction && reflect.DeepEqual(rule.Condition, storage.LifecycleCondition{AgeInDays: days}) {
+			return nil
+		}
+	}
+	lifecycle.Rules = append(lifecycle.Rules, storage.LifecycleRule{
+		Action:    storage.LifecycleAction{Type: storage.DeleteAction},
+		Condition: storage.LifecycleCondition{AgeInDays: days},
+	})
+	if _, err := bucket.Update(ctx, storage.BucketAttrsToUpdate{Lifecycle: &lifecycle}); err != nil {
+		return fmt.Errorf("failed to set the lifecycle of bucket %q: %w", s.bucket, err)
+	}
+	logging.FromContext(ctx).Infow("Added a lifecycle rule deleting the expired claim check objects",
+		zap.String("bucket", s.bucket), zap.Int64("ageInDays", days))
+	return nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claimcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	storagetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+)
+
+func newTestStore(t *testing.T, data storagetesting.TestBucketData) *Store {
+	t.Helper()
+	client, err := storagetesting.TestClientCreator(storagetesting.TestClientData{BucketData: data})(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(client, "bucket")
+}
+
+func newEvent(data string) *cev2.Event {
+	e := cev2.NewEvent()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetData(cev2.ApplicationJSON, []byte(data))
+	return &e
+}
+
+func TestOffloadAndRehydrate(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	objects := storagetesting.NewTestObjects()
+	s := newTestStore(t, storagetesting.TestBucketData{Objects: objects})
+
+	e := newEvent(`{"large":"document"}`)
+	want := e.Clone()
+	if err := s.Offload(ctx, "broker-uid", e); err != nil {
+		t.Fatalf("Offload() failed: %v", err)
+	}
+	if len(e.Data()) != 0 {
+		t.Errorf("Offload() left data %q", e.Data())
+	}
+	names := objects.Names()
+	if len(names) != 1 {
+		t.Fatalf("Offload() wrote objects %v, want one object", names)
+	}
+	if got, want := e.Extensions()[Extension], "gs://bucket/"+names[0]; got != want {
+		t.Errorf("Offload() reference = %v, want %v", got, want)
+	}
+	if err := s.Check("broker-uid", e); err != nil {
+		t.Errorf("Check() failed: %v", err)
+	}
+
+	got, err := s.Rehydrate(ctx, "broker-uid", e)
+	if err != nil {
+		t.Fatalf("Rehydrate() failed: %v", err)
+	}
+	if diff := cmp.Diff(want.Data(), got.Data()); diff != "" {
+		t.Errorf("Rehydrate() data (-want,+got): %v", diff)
+	}
+	if _, ok := got.Extensions()[Extension]; ok {
+		t.Errorf("Rehydrate() kept the %s extension", Extension)
+	}
+	if _, ok := e.Extensions()[Extension]; !ok {
+		t.Errorf("Rehydrate() modified the original event")
+	}
+
+	if err := s.Delete(ctx, "broker-uid", e); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if names := objects.Names(); len(names) != 0 {
+		t.Errorf("Delete() left objects %v", names)
+	}
+	if _, err := s.Rehydrate(ctx, "broker-uid", e); err == nil {
+		t.Errorf("Rehydrate() of a deleted object succeeded")
+	}
+}
+
+func TestOffloadWriteError(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	s := newTestStore(t, storagetesting.TestBucketData{
+		Objects:        storagetesting.NewTestObjects(),
+		WriteObjectErr: errors.New("write failed"),
+	})
+	e := newEvent(`{}`)
+	if err := s.Offload(ctx, "broker-uid", e); err == nil {
+		t.Fatal("Offload() succeeded, want error")
+	}
+	if string(e.Data()) != `{}` {
+		t.Errorf("Offload() modified the data of the event to %q", e.Data())
+	}
+	if _, ok := e.Extensions()[Extension]; ok {
+		t.Errorf("Offload() set the %s extension", Extension)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	objects := storagetesting.NewTestObjects()
+	objects.Put("secret", storagetesting.TestObject{Data: []byte("secret")})
+	objects.Put("claimcheck/other-uid/id", storagetesting.TestObject{Data: []byte("other")})
+	s := newTestStore(t, storagetesting.TestBucketData{Objects: objects})
+
+	tests := []struct {
+		name string
+		ref  interface{}
+	}{
+		{name: "other bucket", ref: "gs://other/claimcheck/broker-uid/id"},
+		{name: "other broker", ref: "gs://bucket/claimcheck/other-uid/id"},
+		{name: "outside prefix", ref: "gs://bucket/secret"},
+		{name: "nested object", ref: "gs://bucket/claimcheck/broker-uid/../../secret"},
+		{name: "empty id", ref: "gs://bucket/claimcheck/broker-uid/"},
+		{name: "not a string", ref: 42},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newEvent("")
+			e.SetExtension(Extension, tc.ref)
+			if err := s.Check("broker-uid", e); err != ErrInvalidReference {
+				t.Errorf("Check() = %v, want %v", err, ErrInvalidReference)
+			}
+			if _, err := s.Rehydrate(ctx, "broker-uid", e); err != ErrInvalidReference {
+				t.Errorf("Rehydrate() = %v, want %v", err, ErrInvalidReference)
+			}
+		})
+	}
+
+	t.Run("no reference", func(t *testing.T) {
+		e := newEvent(`{}`)
+		if err := s.Check("broker-uid", e); err != nil {
+			t.Errorf("Check() failed: %v", err)
+		}
+		got, err := s.Rehydrate(ctx, "broker-uid", e)
+		if err != nil {
+			t.Fatalf("Rehydrate() failed: %v", err)
+		}
+		if got != e {
+			t.Errorf("Rehydrate() did not return the event itself")
+		}
+	})
+}
+
+func TestEnsureLifecycle(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	other := storage.LifecycleRule{
+		Action:    storage.LifecycleAction{Type: storage.SetStorageClassAction, StorageClass: "NEARLINE"},
+		Condition: storage.LifecycleCondition{AgeInDays: 1},
+	}
+	expiry := storage.LifecycleRule{
+		Action:    storage.LifecycleAction{Type: storage.DeleteAction},
+		Condition: storage.LifecycleCondition{AgeInDays: 2},
+	}
+	attrs := &storage.BucketAttrs{Lifecycle: storage.Lifecycle{Rules: []storage.LifecycleRule{other}}}
+	s := newTestStore(t, storagetesting.TestBucketData{Attrs: attrs})
+
+	// The retention is rounded up to whole days, and the existing rules are kept.
+	if err := s.EnsureLifecycle(ctx, 36*time.Hour); err != nil {
+		t.Fatalf("EnsureLifecycle() failed: %v", err)
+	}
+	want := []storage.LifecycleRule{other, expiry}
+	if diff := cmp.Diff(want, attrs.Lifecycle.Rules); diff != "" {
+		t.Errorf("EnsureLifecycle() rules (-want,+got): %v", diff)
+	}
+
+	// The rule is not added twice.
+	s = newTestStore(t, storagetesting.TestBucketData{Attrs: attrs, UpdateErr: errors.New("update failed")})
+	if err := s.EnsureLifecycle(ctx, 48*time.Hour); err != nil {
+		t.Errorf("EnsureLifecycle() with an existing rule failed: %v", err)
+	}
+	if err := s.EnsureLifecycle(ctx, 72*time.Hour); err == nil {
+		t.Errorf("EnsureLifecycle() succeeded, want error")
+	}
+
+	s = newTestStore(t, storagetesting.TestBucketData{AttrsError: errors.New("attrs failed")})
+	if err := s.EnsureLifecycle(ctx, time.Hour); err == nil {
+		t.Errorf("EnsureLifecycle() succeeded, want error")
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package config
 
+import "google.golang.org/protobuf/types/known/durationpb"
+
 // ReadonlyTargets provides "read" functions for CellTenants and targets.
 type ReadonlyTargets interface {
 	// RangeAllTargets ranges over all targets.
@@ -48,6 +50,22 @@ type CellTenantMutation interface {
 	SetDecoupleQueue(q *Queue) CellTenantMutation
 	// SetState sets the CellTenant's state.
 	SetState(s State) CellTenantMutation
+	// SetOrderingKeyAttribute sets the CellTenant's ordering key attribute.
+	SetOrderingKeyAttribute(attr string) CellTenantMutation
+	// SetIngressAuth sets how the ingress authenticates the requests sent to the CellTenant.
+	SetIngressAuth(auth *IngressAuth) CellTenantMutation
+	// SetDedupWindow sets the window within which the ingress deduplicates the events sent to
+	// the CellTenant.
+	SetDedupWindow(window *durationpb.Duration) CellTenantMutation
+	// SetSchemaEnforcement sets how the ingress enforces the schemas of the events sent to the
+	// CellTenant.
+	SetSchemaEnforcement(enforcement SchemaEnforcement) CellTenantMutation
+	// SetEventSchemas sets the schemas of the events sent to the CellTenant, keyed by event type.
+	SetEventSchemas(schemas map[string]*EventSchema) CellTenantMutation
+	// SetQuota sets the quota of the CellTenant.
+	SetQuota(quota *Quota) CellTenantMutation
+	// SetNamespaceQuota sets the quota shared by the CellTenants of the CellTenant's namespace.
+	SetNamespaceQuota(quota *Quota) CellTenantMutation
 	// UpsertTargets upserts Targets to the CellTenant.
 	// The targets' namespace, CellTenantType, and CellTenantName will be set to the CellTenant's
 	// value.
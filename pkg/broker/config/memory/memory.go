@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 var _ config.CellTenantMutation = (*cellTenantMutation)(nil)
@@ -54,6 +55,48 @@ func (m *cellTenantMutation) SetState(s config.State) config.CellTenantMutation
 	return m
 }
 
+func (m *cellTenantMutation) SetOrderingKeyAttribute(attr string) config.CellTenantMutation {
+	m.delete = false
+	m.b.OrderingKeyAttribute = attr
+	return m
+}
+
+func (m *cellTenantMutation) SetIngressAuth(auth *config.IngressAuth) config.CellTenantMutation {
+	m.delete = false
+	m.b.IngressAuth = auth
+	return m
+}
+
+func (m *cellTenantMutation) SetDedupWindow(window *durationpb.Duration) config.CellTenantMutation {
+	m.delete = false
+	m.b.DedupWindow = window
+	return m
+}
+
+func (m *cellTenantMutation) SetSchemaEnforcement(enforcement config.SchemaEnforcement) config.CellTenantMutation {
+	m.delete = false
+	m.b.SchemaEnforcement = enforcement
+	return m
+}
+
+func (m *cellTenantMutation) SetEventSchemas(schemas map[string]*config.EventSchema) config.CellTenantMutation {
+	m.delete = false
+	m.b.EventSchemas = schemas
+	return m
+}
+
+func (m *cellTenantMutation) SetQuota(quota *config.Quota) config.CellTenantMutation {
+	m.delete = false
+	m.b.Quota = quota
+	return m
+}
+
+func (m *cellTenantMutation) SetNamespaceQuota(quota *config.Quota) config.CellTenantMutation {
+	m.delete = false
+	m.b.NamespaceQuota = quota
+	return m
+}
+
 func (m *cellTenantMutation) UpsertTargets(targets ...*config.Target) config.CellTenantMutation {
 	m.delete = false
 	if m.b.Targets == nil {
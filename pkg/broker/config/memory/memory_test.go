@@ -18,9 +18,11 @@ package memory
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestNewEmptyTargets(t *testing.T) {
@@ -96,6 +98,59 @@ func TestMutateBroker(t *testing.T) {
 		assertBroker(t, wantBroker, targets)
 	})
 
+	t.Run("set broker ordering key attribute", func(t *testing.T) {
+		wantBroker.OrderingKeyAttribute = "subject"
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetOrderingKeyAttribute("subject")
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
+	t.Run("set broker ingress auth", func(t *testing.T) {
+		wantBroker.IngressAuth = &config.IngressAuth{
+			Audience:          "external.broker.example.com",
+			AllowedPrincipals: []string{"system:serviceaccount:ns:sa"},
+		}
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetIngressAuth(&config.IngressAuth{
+				Audience:          "external.broker.example.com",
+				AllowedPrincipals: []string{"system:serviceaccount:ns:sa"},
+			})
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
+	t.Run("set broker dedup window", func(t *testing.T) {
+		wantBroker.DedupWindow = durationpb.New(10 * time.Minute)
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetDedupWindow(durationpb.New(10 * time.Minute))
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
+	t.Run("set broker event schemas", func(t *testing.T) {
+		schemas := map[string]*config.EventSchema{
+			"example.order": {Name: "order", JsonSchema: []byte(`{"type": "object"}`)},
+		}
+		wantBroker.SchemaEnforcement = config.SchemaEnforcement_SCHEMA_ENFORCEMENT_REJECT
+		wantBroker.EventSchemas = schemas
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetSchemaEnforcement(config.SchemaEnforcement_SCHEMA_ENFORCEMENT_REJECT)
+			m.SetEventSchemas(schemas)
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
+	t.Run("set broker quotas", func(t *testing.T) {
+		wantBroker.Quota = &config.Quota{EventsPerSecond: 100}
+		wantBroker.NamespaceQuota = &config.Quota{EventsPerSecond: 1000, BytesPerSecond: 1 << 20}
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetQuota(&config.Quota{EventsPerSecond: 100})
+			m.SetNamespaceQuota(&config.Quota{EventsPerSecond: 1000, BytesPerSecond: 1 << 20})
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
 	t1 := &config.Target{
 		Id:             "uid-1",
 		Address:        "consumer1.example.com",
@@ -176,6 +231,18 @@ func TestMutateBroker(t *testing.T) {
 			m.Delete()
 			// Then make some changes which should "recreate" the broker.
 			m.SetID("b-uid").SetAddress("external.broker.example.com").SetState(config.State_READY)
+			m.SetOrderingKeyAttribute("subject")
+			m.SetIngressAuth(&config.IngressAuth{
+				Audience:          "external.broker.example.com",
+				AllowedPrincipals: []string{"system:serviceaccount:ns:sa"},
+			})
+			m.SetDedupWindow(durationpb.New(10 * time.Minute))
+			m.SetSchemaEnforcement(config.SchemaEnforcement_SCHEMA_ENFORCEMENT_REJECT)
+			m.SetEventSchemas(map[string]*config.EventSchema{
+				"example.order": {Name: "order", JsonSchema: []byte(`{"type": "object"}`)},
+			})
+			m.SetQuota(&config.Quota{EventsPerSecond: 100})
+			m.SetNamespaceQuota(&config.Quota{EventsPerSecond: 1000, BytesPerSecond: 1 << 20})
 			m.SetDecoupleQueue(&config.Queue{
 				Topic:        "topic",
 				Subscription: "sub",
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// schemaURL identifies the compiled document. It is never fetched.
+const schemaURL = "eventschema:///schema.json"
+
+// errRemoteRef is returned for the references to other documents, which are not fetched so that
+// schemas can't make the ingress read files or send requests.
+var errRemoteRef = errors.New("only references within the schema are supported")
+
+// jsonSchema is a compiled JSON Schema. Schemas are draft-07 unless they declare another draft
+// with $schema. Patterns are Go regular expressions (RE2) and formats are asserted.
+type jsonSchema struct {
+	schema *jsonschema.Schema
+}
+
+func compileJSONSchema(doc []byte) (*jsonSchema, error) {
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft7
+	c.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("%s: %w", url, errRemoteRef)
+	}
+	if err := c.AddResource(schemaURL, bytes.NewReader(doc)); err != nil {
+		return nil, compileError(err)
+	}
+	s, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, compileError(err)
+	}
+	return &jsonSchema{schema: s}, nil
+}
+
+// compileError returns the violations of the meta-schema, or the cause of the error.
+func compileError(err error) error {
+	var verr *jsonschema.ValidationError
+	if errors.As(err, &verr) {
+		return errors.New(strings.Join(violations(verr), "; "))
+	}
+	var serr *jsonschema.SchemaError
+	if errors.As(err, &serr) && serr.Err != nil {
+		return serr.Err
+	}
+	return err
+}
+
+// decodeJSON decodes JSON data, keeping numbers exact.
+func decodeJSON(data []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return v, nil
+}
+
+// validate returns the violations of the schema by v, a value decoded by decodeJSON.
+func (s *jsonSchema) validate(v interface{}) []string {
+	err := s.schema.Validate(v)
+	if err == nil {
+		return nil
+	}
+	var verr *jsonschema.ValidationError
+	if errors.As(err, &verr) {
+		return violations(verr)
+	}
+	return []string{err.Error()}
+}
+
+// violations returns the leaves of the tree of errors, prefixed by the JSON pointer of the
+// invalid value, e.g. "/quantity: must be >= 1 but found 0".
+func violations(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		loc := err.InstanceLocation
+		if loc == "" {
+			loc = "/"
+		}
+		return []string{loc + ": " + err.Message}
+	}
+	var out []string
+	for _, c := range err.Causes {
+		out = append(out, violations(c)...)
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		data   string
+		want   []string
+	}{{
+		name:   "true schema",
+		schema: `true`,
+		data:   `{"a": 1}`,
+	}, {
+		name:   "false schema",
+		schema: `false`,
+		data:   `{"a": 1}`,
+		want:   []string{"/: not allowed"},
+	}, {
+		name:   "type",
+		schema: `{"type": "object"}`,
+		data:   `[1]`,
+		want:   []string{"/: expected object, but got array"},
+	}, {
+		name:   "integral number is an integer",
+		schema: `{"type": "integer"}`,
+		data:   `2.0`,
+	}, {
+		name:   "required and properties",
+		schema: `{"type": "object", "required": ["id", "amount"], "properties": {"id": {"type": "string"}, "amount": {"type": "number", "minimum": 0}}}`,
+		data:   `{"amount": -1}`,
+		want: []string{
+			"/: missing properties: 'id'",
+			"/amount: must be >= 0 but found -1",
+		},
+	}, {
+		name:   "additional properties",
+		schema: `{"properties": {"id": {}}, "additionalProperties": false}`,
+		data:   `{"id": 1, "extra": 2}`,
+		want:   []string{"/: additionalProperties 'extra' not allowed"},
+	}, {
+		name:   "items",
+		schema: `{"type": "array", "items": {"type": "string"}, "maxItems": 2}`,
+		data:   `["a", 1, "c"]`,
+		want: []string{
+			"/: maximum 2 items required, but found 3 items",
+			"/1: expected string, but got number",
+		},
+	}, {
+		name:   "pattern",
+		schema: `{"type": "string", "pattern": "^[a-z]+$"}`,
+		data:   `"Ab"`,
+		want:   []string{"/: does not match pattern '^[a-z]+$'"},
+	}, {
+		name:   "if then else",
+		schema: `{"if": {"properties": {"kind": {"const": "refund"}}}, "then": {"required": ["reason"]}, "else": {"required": ["amount"]}}`,
+		data:   `{"kind": "refund"}`,
+		want:   []string{"/: missing properties: 'reason'"},
+	}, {
+		name:   "pattern properties",
+		schema: `{"patternProperties": {"^x-": {"type": "string"}}}`,
+		data:   `{"x-trace": 1, "other": 1}`,
+		want:   []string{"/x-trace: expected string, but got number"},
+	}, {
+		name:   "dependencies",
+		schema: `{"dependencies": {"card": ["billingAddress"], "refund": {"required": ["reason"]}}}`,
+		data:   `{"card": "4111"}`,
+		want:   []string{"/: property 'billingAddress' is required, if 'card' property exists"},
+	}, {
+		name:   "ref",
+		schema: `{"definitions": {"id": {"type": "string"}}, "properties": {"a/b": {"$ref": "#/definitions/id"}}}`,
+		data:   `{"a/b": 1}`,
+		want:   []string{"/a~1b: expected string, but got number"},
+	}, {
+		name:   "recursive ref",
+		schema: `{"type": "object", "properties": {"child": {"$ref": "#"}, "name": {"type": "string"}}}`,
+		data:   `{"child": {"child": {"name": 1}}}`,
+		want:   []string{"/child/child/name: expected string, but got number"},
+	}, {
+		name:   "format",
+		schema: `{"format": "date-time"}`,
+		data:   `"yesterday"`,
+		want:   []string{"/: 'yesterday' is not valid 'date-time'"},
+	}, {
+		name: "large integers are exact",
+		// Both numbers round to the same float64.
+		schema: `{"type": "integer", "minimum": 9007199254740993}`,
+		data:   `9007199254740992`,
+		want:   []string{"/: must be >= 9.007199254740992e+15 but found 9007199254740992"},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := compileJSONSchema([]byte(tc.schema))
+			if err != nil {
+				t.Fatalf("compileJSONSchema() failed: %v", err)
+			}
+			v, err := decodeJSON([]byte(tc.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, s.validate(v)); diff != "" {
+				t.Errorf("unexpected errors (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCompileJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{{
+		name:   "invalid JSON",
+		schema: `{`,
+	}, {
+		name:    "not a schema",
+		schema:  `"string"`,
+		wantErr: "/: expected object or boolean, but got string",
+	}, {
+		name:    "unknown type",
+		schema:  `{"properties": {"a": {"type": "text"}}}`,
+		wantErr: "/properties/a/type: ",
+	}, {
+		name:    "invalid pattern",
+		schema:  `{"pattern": "("}`,
+		wantErr: "/pattern: '(' is not valid 'regex'",
+	}, {
+		name:    "negative count",
+		schema:  `{"minLength": -1}`,
+		wantErr: "/minLength: must be >= 0 but found -1",
+	}, {
+		name:    "remote ref",
+		schema:  `{"$ref": "https://example.com/schema.json"}`,
+		wantErr: "https://example.com/schema.json: only references within the schema are supported",
+	}, {
+		name:    "file ref",
+		schema:  `{"$ref": "file:///etc/passwd"}`,
+		wantErr: "only references within the schema are supported",
+	}, {
+		name:    "missing ref",
+		schema:  `{"$ref": "#/definitions/missing"}`,
+		wantErr: "/definitions/missing",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := compileJSONSchema([]byte(tc.schema))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("compileJSONSchema() error = %v, want an error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	for _, data := range []string{`{"a": 1} {"a": 2}`, `{"a":`, ``} {
+		if _, err := decodeJSON([]byte(data)); err == nil {
+			t.Errorf("decodeJSON(%q) succeeded, want an error", data)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventschema validates the data of events against the schemas registered for their type
+// with EventSchemas. Schemas are JSON Schema documents, proto message descriptors, or both.
+package eventschema
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ValidationError is returned when the data of an event doesn't match its schema.
+type ValidationError struct {
+	// Errors are the violations of the schema.
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return "event data does not match the schema: " + strings.Join(e.Errors, "; ")
+}
+
+// Schema validates the data of the events of a type.
+type Schema struct {
+	json  *jsonSchema
+	proto protoreflect.MessageDescriptor
+}
+
+// New compiles a Schema. jsonSchema is a JSON Schema document. fileDescriptorSet is a serialized
+// google.protobuf.FileDescriptorSet, including the imported files, which defines message, the
+// full name of the proto message of the event data. At least one of them must be set.
+func New(jsonSchema, fileDescriptorSet []byte, message string) (*Schema, error) {
+	if len(jsonSchema) == 0 && len(fileDescriptorSet) == 0 {
+		return nil, errors.New("a JSON Schema or a proto descriptor is required")
+	}
+	s := &Schema{}
+	if len(jsonSchema) > 0 {
+		compiled, err := compileJSONSchema(jsonSchema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON Schema: %w", err)
+		}
+		s.json = compiled
+	}
+	if len(fileDescriptorSet) > 0 {
+		md, err := compileProto(fileDescriptorSet, message)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proto descriptor: %w", err)
+		}
+		s.proto = md
+	}
+	return s, nil
+}
+
+func compileProto(fileDescriptorSet []byte, message string) (protoreflect.MessageDescriptor, error) {
+	if message == "" {
+		return nil, errors.New("the message name is required")
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(fileDescriptorSet, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse the file descriptor set: %w", err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, err
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(message))
+	if err != nil {
+		return nil, fmt.Errorf("message %q: %w", message, err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a message", message)
+	}
+	return md, nil
+}
+
+// Validate validates the data of the event. JSON data is validated against the JSON Schema and
+// the proto descriptor, protobuf data against the proto descriptor. It returns a
+// *ValidationError if the data doesn't match the schema or can't be validated against it.
+func (s *Schema) Validate(event *cev2.Event) error {
+	contentType := event.DataContentType()
+	if contentType == "" {
+		contentType = cev2.ApplicationJSON
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return &ValidationError{Errors: []string{fmt.Sprintf("invalid datacontenttype %q", contentType)}}
+	}
+	data := event.Data()
+	switch {
+	case isJSON(mediaType):
+		return s.validateJSON(data)
+	case mediaType == "application/protobuf" || mediaType == "application/x-protobuf":
+		if s.proto == nil {
+			return &ValidationError{Errors: []string{fmt.Sprintf("datacontenttype %q cannot be validated against a JSON Schema", contentType)}}
+		}
+		if err := proto.Unmarshal(data, dynamicpb.NewMessage(s.proto)); err != nil {
+			return &ValidationError{Errors: []string{fmt.Sprintf("data is not a valid %s: %v", s.proto.FullName(), err)}}
+		}
+		return nil
+	default:
+		return &ValidationError{Errors: []string{fmt.Sprintf("datacontenttype %q is not supported by the schema", contentType)}}
+	}
+}
+
+func (s *Schema) validateJSON(data []byte) error {
+	if s.json != nil {
+		// Events without data are validated as null.
+		var v interface{}
+		if len(data) > 0 {
+			var err error
+			if v, err = decodeJSON(data); err != nil {
+				return &ValidationError{Errors: []string{fmt.Sprintf("data is not valid JSON: %v", err)}}
+			}
+		}
+		if errs := s.json.validate(v); len(errs) > 0 {
+			return &ValidationError{Errors: errs}
+		}
+	}
+	if s.proto != nil && len(data) > 0 {
+		// Unknown fields are accepted, as they are in the protobuf encoding, so that producers can
+		// add fields before the schema is updated.
+		opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+		if err := opts.Unmarshal(data, dynamicpb.NewMessage(s.proto)); err != nil {
+			return &ValidationError{Errors: []string{fmt.Sprintf("data is not a valid %s: %v", s.proto.FullName(), err)}}
+		}
+	}
+	return nil
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == cev2.ApplicationJSON || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const orderJSONSchema = `{
+	"type": "object",
+	"required": ["id"],
+	"properties": {
+		"id": {"type": "string"},
+		"quantity": {"type": "integer", "minimum": 1}
+	}
+}`
+
+// orderDescriptorSet returns a file descriptor set with the proto2 message
+//
+//	message Order { required string id = 1; optional int32 quantity = 2; }
+func orderDescriptorSet(t *testing.T) []byte {
+	t.Helper()
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("order.proto"),
+			Package: proto.String("test"),
+			Syntax:  proto.String("proto2"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Order"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:     proto.String("id"),
+					JsonName: proto.String("id"),
+					Number:   proto.Int32(1),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				}, {
+					Name:     proto.String("quantity"),
+					JsonName: proto.String("quantity"),
+					Number:   proto.Int32(2),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+				}},
+			}},
+		}},
+	}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestNew(t *testing.T) {
+	fds := orderDescriptorSet(t)
+	tests := []struct {
+		name              string
+		jsonSchema        []byte
+		fileDescriptorSet []byte
+		message           string
+		wantErr           string
+	}{{
+		name:       "json schema",
+		jsonSchema: []byte(orderJSONSchema),
+	}, {
+		name:              "proto",
+		fileDescriptorSet: fds,
+		message:           "test.Order",
+	}, {
+		name:    "no schema",
+		wantErr: "a JSON Schema or a proto descriptor is required",
+	}, {
+		name:       "invalid json schema",
+		jsonSchema: []byte(`{"type": 1}`),
+		wantErr:    "invalid JSON Schema",
+	}, {
+		name:              "missing message",
+		fileDescriptorSet: fds,
+		wantErr:           "the message name is required",
+	}, {
+		name:              "unknown message",
+		fileDescriptorSet: fds,
+		message:           "test.Missing",
+		wantErr:           `message "test.Missing"`,
+	}, {
+		name:              "not a message",
+		fileDescriptorSet: fds,
+		message:           "test.Order.id",
+		wantErr:           `"test.Order.id" is not a message`,
+	}, {
+		name:              "invalid descriptor set",
+		fileDescriptorSet: []byte("garbage"),
+		message:           "test.Order",
+		wantErr:           "failed to parse the file descriptor set",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.jsonSchema, tc.fileDescriptorSet, tc.message)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("New() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("New() error = %v, want an error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	fds := orderDescriptorSet(t)
+	var order []byte
+	order = protowire.AppendTag(order, 1, protowire.BytesType)
+	order = protowire.AppendString(order, "o-1")
+	var orderWithoutID []byte
+	orderWithoutID = protowire.AppendTag(orderWithoutID, 2, protowire.VarintType)
+	orderWithoutID = protowire.AppendVarint(orderWithoutID, 3)
+
+	jsonOnly, err := New([]byte(orderJSONSchema), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	protoOnly, err := New(nil, fds, "test.Order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	both, err := New([]byte(orderJSONSchema), fds, "test.Order")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := func(contentType string, data []byte) *cev2.Event {
+		e := cev2.NewEvent()
+		e.SetID("id")
+		e.SetSource("source")
+		e.SetType("type")
+		if data != nil {
+			e.SetData(contentType, data)
+		} else if contentType != "" {
+			e.SetDataContentType(contentType)
+		}
+		return &e
+	}
+
+	tests := []struct {
+		name   string
+		schema *Schema
+		event  *cev2.Event
+		// wantErrors are the prefixes of the expected errors.
+		wantErrors []string
+	}{{
+		name:   "valid json",
+		schema: jsonOnly,
+		event:  event(cev2.ApplicationJSON, []byte(`{"id": "o-1", "quantity": 2}`)),
+	}, {
+		name:       "invalid json",
+		schema:     jsonOnly,
+		event:      event(cev2.ApplicationJSON, []byte(`{"quantity": 0}`)),
+		wantErrors: []string{"/: missing properties: 'id'", "/quantity: must be >= 1 but found 0"},
+	}, {
+		name:   "json with parameters and suffix",
+		schema: jsonOnly,
+		event:  event("application/cloudevents+json; charset=utf-8", []byte(`{"id": "o-1"}`)),
+	}, {
+		name:   "default content type is json",
+		schema: jsonOnly,
+		event: func() *cev2.Event {
+			e := event("", nil)
+			e.DataEncoded = []byte(`{"id": "o-1"}`)
+			return e
+		}(),
+	}, {
+		name:       "missing data is null",
+		schema:     jsonOnly,
+		event:      event("", nil),
+		wantErrors: []string{"/: expected object, but got null"},
+	}, {
+		name:       "malformed json",
+		schema:     jsonOnly,
+		event:      event(cev2.ApplicationJSON, []byte(`{"id":`)),
+		wantErrors: []string{"data is not valid JSON: unexpected EOF"},
+	}, {
+		name:       "protobuf against json schema",
+		schema:     jsonOnly,
+		event:      event("application/protobuf", order),
+		wantErrors: []string{`datacontenttype "application/protobuf" cannot be validated against a JSON Schema`},
+	}, {
+		name:       "unsupported content type",
+		schema:     both,
+		event:      event("text/plain", []byte("hello")),
+		wantErrors: []string{`datacontenttype "text/plain" is not supported by the schema`},
+	}, {
+		name:   "valid protobuf",
+		schema: protoOnly,
+		event:  event("application/x-protobuf", order),
+	}, {
+		name:       "protobuf missing a required field",
+		schema:     protoOnly,
+		event:      event("application/protobuf", orderWithoutID),
+		wantErrors: []string{"data is not a valid test.Order: proto:"},
+	}, {
+		name:   "valid proto json with unknown fields",
+		schema: protoOnly,
+		event:  event(cev2.ApplicationJSON, []byte(`{"id": "o-1", "extra": true}`)),
+	}, {
+		name:       "invalid proto json",
+		schema:     protoOnly,
+		event:      event(cev2.ApplicationJSON, []byte(`{"id": "o-1", "quantity": "many"}`)),
+		wantErrors: []string{"data is not a valid test.Order: proto:"},
+	}, {
+		name:       "json validated against both",
+		schema:     both,
+		event:      event(cev2.ApplicationJSON, []byte(`{"id": "o-1", "quantity": 2.5}`)),
+		wantErrors: []string{"/quantity: expected integer, but got number"},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schema.Validate(tc.event)
+			if tc.wantErrors == nil {
+				if err != nil {
+					t.Errorf("Validate() failed: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want a *ValidationError", err)
+			}
+			if len(verr.Errors) != len(tc.wantErrors) {
+				t.Fatalf("Validate() errors = %q, want %q", verr.Errors, tc.wantErrors)
+			}
+			for i, want := range tc.wantErrors {
+				if !strings.HasPrefix(verr.Errors[i], want) {
+					t.Errorf("Validate() errors = %q, want %q", verr.Errors, tc.wantErrors)
+				}
+			}
+		})
+	}
+}
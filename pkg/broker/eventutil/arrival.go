@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/knative-gcp/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// ArrivalTimeAttribute is the time the event was accepted by the broker ingress. It matches the
+// attribute Knative Eventing uses for the arrival time of events.
+const ArrivalTimeAttribute = "knativearrivaltime"
+
+// GetArrivalTime returns the time the event was accepted by the broker ingress. If there is no
+// existing value or an invalid one, false will be returned.
+func GetArrivalTime(ctx context.Context, event *event.Event) (time.Time, bool) {
+	raw, ok := event.Extensions()[ArrivalTimeAttribute]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := cetypes.ToTime(raw)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to convert existing arrival time value into time, regarding it as there is no arrival time.",
+			zap.String("event.id", event.ID()),
+			zap.Any(ArrivalTimeAttribute, raw),
+			zap.Error(err),
+		)
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/knative-gcp/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// AttemptsAttribute is the number of failed delivery attempts of an event from the retry
+	// queue. It is short for the same reason as HopsAttribute.
+	AttemptsAttribute = "kgcpattempts"
+
+	// RetryTimeAttribute is the time before which an event from the retry queue should not be
+	// delivered, so that the retry pool can apply the backoff policy of the target.
+	RetryTimeAttribute = "kgcpretryat"
+)
+
+// GetDeliveryAttempts returns the number of failed delivery attempts recorded on the event.
+// If there is no existing value or an invalid one, 0 will be returned.
+func GetDeliveryAttempts(ctx context.Context, event *event.Event) int32 {
+	raw, ok := event.Extensions()[AttemptsAttribute]
+	if !ok {
+		return 0
+	}
+	attempts, err := cetypes.ToInteger(raw)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to convert existing delivery attempts value into integer, regarding it as there is no attempts value.",
+			zap.String("event.id", event.ID()),
+			zap.Any(AttemptsAttribute, raw),
+			zap.Error(err),
+		)
+		return 0
+	}
+	return attempts
+}
+
+// SetDeliveryAttempts sets the number of failed delivery attempts on the event.
+func SetDeliveryAttempts(event *event.Event, attempts int32) {
+	event.SetExtension(AttemptsAttribute, attempts)
+}
+
+// GetRetryTime returns the time before which the event should not be redelivered. If there is
+// no existing value or an invalid one, false will be returned.
+func GetRetryTime(ctx context.Context, event *event.Event) (time.Time, bool) {
+	raw, ok := event.Extensions()[RetryTimeAttribute]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := cetypes.ToTime(raw)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to convert existing retry time value into time, regarding it as there is no retry time.",
+			zap.String("event.id", event.ID()),
+			zap.Any(RetryTimeAttribute, raw),
+			zap.Error(err),
+		)
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// SetRetryTime sets the time before which the event should not be redelivered.
+func SetRetryTime(event *event.Event, t time.Time) {
+	event.SetExtension(RetryTimeAttribute, t)
+}
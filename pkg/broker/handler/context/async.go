@@ -0,0 +1,60 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+)
+
+type asyncResultKey struct{}
+
+// AsyncResult holds the result of the processing of an event that continues after the processor
+// chain returned, e.g. while the event waits for its batch to be sent. It lets a processor that
+// processes several targets of an event move on to the next target.
+type AsyncResult struct {
+	wait func() error
+}
+
+// WithAsyncResult sets in the context where a processor may defer its result.
+func WithAsyncResult(ctx context.Context, r *AsyncResult) context.Context {
+	return context.WithValue(ctx, asyncResultKey{}, r)
+}
+
+// DeferResult defers the result of the processing of the event to wait, which blocks until the
+// processing is done. It returns false if the context doesn't accept a deferred result, in which
+// case the caller must wait itself.
+func DeferResult(ctx context.Context, wait func() error) bool {
+	r, ok := ctx.Value(asyncResultKey{}).(*AsyncResult)
+	if !ok || r == nil {
+		return false
+	}
+	r.wait = wait
+	return true
+}
+
+// Pending returns whether a result was deferred.
+func (r *AsyncResult) Pending() bool {
+	return r.wait != nil
+}
+
+// Wait blocks until the deferred result, if any, is available and returns it.
+func (r *AsyncResult) Wait() error {
+	if r.wait == nil {
+		return nil
+	}
+	return r.wait()
+}
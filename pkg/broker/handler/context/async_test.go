@@ -0,0 +1,48 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAsyncResult(t *testing.T) {
+	if DeferResult(context.Background(), func() error { return nil }) {
+		t.Error("DeferResult() succeeded without an AsyncResult in the context")
+	}
+
+	var r AsyncResult
+	if r.Pending() {
+		t.Error("AsyncResult is pending before a result was deferred")
+	}
+	if err := r.Wait(); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+
+	wantErr := errors.New("batch failed")
+	if !DeferResult(WithAsyncResult(context.Background(), &r), func() error { return wantErr }) {
+		t.Fatal("DeferResult() failed with an AsyncResult in the context")
+	}
+	if !r.Pending() {
+		t.Error("AsyncResult is not pending after a result was deferred")
+	}
+	if err := r.Wait(); err != wantErr {
+		t.Errorf("Wait() = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type batchKey struct{}
+
+// BatchEntry is an event of a batch along with the context it was received in.
+type BatchEntry struct {
+	Ctx   context.Context
+	Event *event.Event
+}
+
+// WithBatch sets a batch of events to process together in the context.
+func WithBatch(ctx context.Context, batch []BatchEntry) context.Context {
+	return context.WithValue(ctx, batchKey{}, batch)
+}
+
+// GetBatch gets the batch of events to process together from the context.
+func GetBatch(ctx context.Context) ([]BatchEntry, bool) {
+	batch, ok := ctx.Value(batchKey{}).([]BatchEntry)
+	return batch, ok
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBatch(t *testing.T) {
+	if _, ok := GetBatch(context.Background()); ok {
+		t.Error("GetBatch got a batch from an empty context")
+	}
+	e := event.New()
+	e.SetID("id")
+	wantBatch := []BatchEntry{{Ctx: context.Background(), Event: &e}}
+	ctx := WithBatch(context.Background(), wantBatch)
+	gotBatch, ok := GetBatch(ctx)
+	if !ok {
+		t.Fatal("GetBatch got no batch")
+	}
+	if diff := cmp.Diff(wantBatch[0].Event, gotBatch[0].Event); len(gotBatch) != 1 || diff != "" {
+		t.Errorf("GetBatch unexpected batch (-want,+got): %s", diff)
+	}
+}
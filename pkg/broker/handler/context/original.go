@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type originalEventKey struct{}
+
+// WithOriginalEvent sets the event as it was received, before it was transformed for the target,
+// in the context.
+func WithOriginalEvent(ctx context.Context, e *event.Event) context.Context {
+	return context.WithValue(ctx, originalEventKey{}, e)
+}
+
+// GetOriginalEvent gets the event as it was received, before it was transformed for the target,
+// from the context.
+func GetOriginalEvent(ctx context.Context) (*event.Event, bool) {
+	e, ok := ctx.Value(originalEventKey{}).(*event.Event)
+	return e, ok
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOriginalEvent(t *testing.T) {
+	if _, ok := GetOriginalEvent(context.Background()); ok {
+		t.Error("GetOriginalEvent got an event from an empty context")
+	}
+	want := event.New()
+	want.SetID("id")
+	ctx := WithOriginalEvent(context.Background(), &want)
+	got, ok := GetOriginalEvent(ctx)
+	if !ok {
+		t.Fatal("GetOriginalEvent got no event")
+	}
+	if diff := cmp.Diff(&want, got); diff != "" {
+		t.Errorf("GetOriginalEvent unexpected event (-want,+got): %s", diff)
+	}
+}
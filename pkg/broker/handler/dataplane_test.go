@@ -0,0 +1,171 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/ingress"
+	"github.com/google/knative-gcp/pkg/broker/queue"
+	memoryqueue "github.com/google/knative-gcp/pkg/broker/queue/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+// TestDataPlaneWithMemoryQueue runs the ingress decouple sink, fanout and retry in process with an in-memory
+// decouple queue. The subscriber fails the first delivery so that the event goes through the
+// retry queue.
+func TestDataPlaneWithMemoryQueue(t *testing.T) {
+	queueClient := memoryqueue.NewClient()
+	for topic, sub := range map[string]string{"decouple-topic": "decouple-sub", "retry-topic": "retry-sub"} {
+		queueClient.CreateTopic(topic)
+		if err := queueClient.CreateSubscription(sub, topic); err != nil {
+			t.Fatalf("failed to create subscription %q: %v", sub, err)
+		}
+	}
+	testDataPlane(t, func() queue.Client { return queueClient })
+}
+
+// TestDataPlaneWithBoltQueue runs the ingress decouple sink, fanout and retry in process with a
+// bolt queue database file. The pools create the decouple and retry queues.
+func TestDataPlaneWithBoltQueue(t *testing.T) {
+	c, err := queue.NewBoltClient(filepath.Join(t.TempDir(), "queue.db"))
+	if err != nil {
+		t.Fatalf("failed to create bolt queue client: %v", err)
+	}
+	defer c.Close()
+	c.PollInterval = 10 * time.Millisecond
+	testDataPlane(t, func() queue.Client { return c })
+}
+
+// testDataPlane delivers an event through the ingress decouple sink, fanout and retry. Each
+// component uses the queue client returned by newQueueClient.
+func testDataPlane(t *testing.T, newQueueClient func() queue.Client) {
+	reportertest.ResetDeliveryMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan *event.Event, 2)
+	var mu sync.Mutex
+	attempts := 0
+	subscriber := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+		if err != nil {
+			t.Errorf("subscriber received an invalid event: %v", err)
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		attempts++
+		fail := attempts == 1
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(nethttp.StatusInternalServerError)
+			return
+		}
+		received <- e
+		w.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer subscriber.Close()
+
+	targets := memory.NewEmptyTargets()
+	brokerKey := config.TestOnlyBrokerKey("ns", "broker")
+	targets.MutateCellTenant(brokerKey, func(m config.CellTenantMutation) {
+		m.SetDecoupleQueue(&config.Queue{
+			Topic:        "decouple-topic",
+			Subscription: "decouple-sub",
+			State:        config.State_READY,
+		})
+		m.SetState(config.State_READY)
+		m.UpsertTargets(&config.Target{
+			Name:           "trigger",
+			Namespace:      "ns",
+			CellTenantType: config.CellTenantType_BROKER,
+			CellTenantName: "broker",
+			Address:        subscriber.URL,
+			RetryQueue: &config.Queue{
+				Topic:        "retry-topic",
+				Subscription: "retry-sub",
+			},
+			State: config.State_READY,
+		})
+	})
+
+	deliveryReporter, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatalf("failed to create delivery reporter: %v", err)
+	}
+	fanoutQueueClient := newQueueClient()
+	fanoutRetryClient, err := NewRetryClient(fanoutQueueClient, DefaultCEClientOpts...)
+	if err != nil {
+		t.Fatalf("failed to create retry client: %v", err)
+	}
+	fanoutPool, err := NewFanoutPool(targets, fanoutQueueClient, DefaultHTTPClient, fanoutRetryClient, deliveryReporter)
+	if err != nil {
+		t.Fatalf("failed to create fanout pool: %v", err)
+	}
+	retryQueueClient := newQueueClient()
+	retryRetryClient, err := NewRetryClient(retryQueueClient, DefaultCEClientOpts...)
+	if err != nil {
+		t.Fatalf("failed to create retry client: %v", err)
+	}
+	retryPool, err := NewRetryPool(targets, retryQueueClient, DefaultHTTPClient, retryRetryClient, deliveryReporter)
+	if err != nil {
+		t.Fatalf("failed to create retry pool: %v", err)
+	}
+	for _, p := range []SyncPool{fanoutPool, retryPool} {
+		if err := p.SyncOnce(ctx); err != nil {
+			t.Fatalf("failed to sync pool: %v", err)
+		}
+	}
+
+	sink := ingress.NewMultiTopicDecoupleSink(ctx, targets, newQueueClient(), pubsub.DefaultPublishSettings, nil)
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	if err := sink.Send(ctx, brokerKey, e); err != nil {
+		t.Fatalf("failed to send event to the decouple sink: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if got.ID() != e.ID() {
+			t.Errorf("subscriber received event %q, want %q", got.ID(), e.ID())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the event to be delivered")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 2 {
+		t.Errorf("subscriber got %d delivery attempts, want 2", attempts)
+	}
+}
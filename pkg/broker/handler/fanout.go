@@ -23,17 +23,21 @@ import (
 	"sync"
 	"time"
 
-	"cloud.google.com/go/pubsub"
 	ceclient "github.com/cloudevents/sdk-go/v2/client"
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/zap"
 
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/batch"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/fanout"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/ratelimit"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/transform"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -52,8 +56,8 @@ type FanoutPool struct {
 	targets config.ReadonlyTargets
 	pool    *syncMapBrokerKey
 
-	// Pubsub client used to pull events from decoupling topics.
-	pubsubClient *pubsub.Client
+	// Queue client used to pull events from decoupling topics.
+	queueClient queue.Client
 	// For sending retry events. We only need a shared client.
 	// And we can set retry topic dynamically.
 	deliverRetryClient ceclient.Client
@@ -61,6 +65,12 @@ type FanoutPool struct {
 	// And we can set target address dynamically.
 	deliverClient *http.Client
 	statsReporter *metrics.DeliveryReporter
+	// circuitBreakers is nil if circuit breakers are disabled.
+	circuitBreakers *circuitbreaker.Registry
+	// filters caches the compiled filters of the targets for all the handlers.
+	filters filter.Cache
+	// limiters holds the rate limiters of the targets for all the handlers.
+	limiters ratelimit.Limiters
 }
 
 type fanoutHandlerCache struct {
@@ -80,7 +90,8 @@ func (hc *fanoutHandlerCache) shouldRenew(b *config.CellTenant) bool {
 		return true
 	}
 	if b.DecoupleQueue.Topic != hc.b.DecoupleQueue.Topic ||
-		b.DecoupleQueue.Subscription != hc.b.DecoupleQueue.Subscription {
+		b.DecoupleQueue.Subscription != hc.b.DecoupleQueue.Subscription ||
+		b.DecoupleQueue.ProjectId != hc.b.DecoupleQueue.ProjectId {
 		return true
 	}
 	return false
@@ -89,7 +100,7 @@ func (hc *fanoutHandlerCache) shouldRenew(b *config.CellTenant) bool {
 // NewFanoutPool creates a new fanout handler pool.
 func NewFanoutPool(
 	targets config.ReadonlyTargets,
-	pubsubClient *pubsub.Client,
+	queueClient queue.Client,
 	deliverClient *http.Client,
 	retryClient RetryClient,
 	statsReporter *metrics.DeliveryReporter,
@@ -116,20 +127,35 @@ func NewFanoutPool(
 		targets:            targets,
 		options:            options,
 		pool:               &syncMapBrokerKey{},
-		pubsubClient:       pubsubClient,
+		queueClient:        queueClient,
 		deliverClient:      deliverClient,
 		deliverRetryClient: retryClient,
 		statsReporter:      statsReporter,
 	}
+	if options.CircuitBreaker.FailureThreshold > 0 {
+		p.circuitBreakers = circuitbreaker.NewRegistry(options.CircuitBreaker, func(ctx context.Context, s circuitbreaker.State) {
+			statsReporter.ReportCircuitBreakerState(ctx, int64(s))
+		})
+	}
 	return p, nil
 }
 
+// CircuitBreakers returns the circuit breakers of the targets, or nil if they are disabled.
+func (p *FanoutPool) CircuitBreakers() *circuitbreaker.Registry {
+	return p.circuitBreakers
+}
+
 // SyncOnce syncs once the handler pool based on the targets config.
 func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 	ctx, err := p.statsReporter.AddTags(ctx)
 	if err != nil {
 		logging.FromContext(ctx).Error("failed to add tags to context", zap.Error(err))
 	}
+	if p.circuitBreakers != nil {
+		p.circuitBreakers.Forget(p.targets)
+	}
+	p.filters.Prune(p.targets)
+	p.limiters.Prune(p.targets)
 
 	p.pool.Range(func(key config.CellTenantKey, value *fanoutHandlerCache) bool {
 		if _, ok := p.targets.GetCellTenantByKey(&key); !ok {
@@ -156,14 +182,27 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 			return true
 		}
 
-		sub := p.pubsubClient.Subscription(b.DecoupleQueue.Subscription)
-		sub.ReceiveSettings = p.options.PubsubReceiveSettings
+		client, err := p.queueClient.InProject(b.DecoupleQueue.ProjectId)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to create the queue client of the broker project", zap.Stringer("broker", b.Key()), zap.String("project", b.DecoupleQueue.ProjectId), zap.Error(err))
+			return true
+		}
+		if pr, ok := client.(queue.Provisioner); ok {
+			if err := pr.EnsureSubscription(b.DecoupleQueue.Subscription, b.DecoupleQueue.Topic); err != nil {
+				logging.FromContext(ctx).Error("failed to create the decouple queue of the broker", zap.Stringer("broker", b.Key()), zap.Error(err))
+				return true
+			}
+		}
+		sub := client.Subscription(b.DecoupleQueue.Subscription, p.options.PubsubReceiveSettings)
 
 		h := NewHandler(
 			sub,
 			processors.ChainProcessors(
 				&fanout.Processor{MaxConcurrency: p.options.MaxConcurrencyPerEvent, Targets: p.targets},
-				&filter.Processor{Targets: p.targets},
+				&filter.Processor{Targets: p.targets, Filters: &p.filters},
+				&ratelimit.Processor{Targets: p.targets, RetryClient: p.deliverRetryClient, Limiters: &p.limiters},
+				&transform.Processor{Targets: p.targets, ClaimCheck: p.options.ClaimCheck},
+				&batch.Processor{Targets: p.targets},
 				&deliver.Processor{
 					DeliverClient:      p.deliverClient,
 					Targets:            p.targets,
@@ -171,6 +210,8 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 					DeliverRetryClient: p.deliverRetryClient,
 					DeliverTimeout:     p.options.DeliveryTimeout,
 					StatsReporter:      p.statsReporter,
+					CircuitBreakers:    p.circuitBreakers,
+					ClaimCheck:         p.options.ClaimCheck,
 				},
 			),
 			p.options.TimeoutPerEvent,
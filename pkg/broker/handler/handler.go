@@ -22,21 +22,22 @@ import (
 	"sync/atomic"
 	"time"
 
-	"cloud.google.com/go/pubsub"
-	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"go.uber.org/zap"
 )
 
-// Handler pulls Pubsub messages as events and processes them
+// Handler pulls queue messages as events and processes them
 // with chain of processors.
 type Handler struct {
-	// Subscription is the pubsub subscription that messages will be
+	// Subscription is the queue subscription that messages will be
 	// received from.
-	Subscription *pubsub.Subscription
+	Subscription queue.Subscription
 
 	// Processor is the processor to process events.
 	Processor processors.Interface
@@ -44,16 +45,49 @@ type Handler struct {
 	// Timeout is the timeout for processing each individual event.
 	Timeout time.Duration
 
+	// MaxRetryHold is the maximum time an event scheduled for a later
+	// delivery attempt is held before it is processed. The Pub/Sub client keeps
+	// extending the ack deadline of held messages, up to the MaxExtension of
+	// the receive settings, which should cover MaxRetryHold and Timeout. The
+	// hold doesn't count toward Timeout. Events scheduled further in the future
+	// are rescheduled, or nacked if Reschedule is nil, after being held for
+	// MaxRetryHold.
+	// If zero, events are processed as soon as they are received.
+	MaxRetryHold time.Duration
+
+	// MaxHeldMessages is the maximum number of messages held at once. The
+	// messages that are not due when MaxHeldMessages are already held are
+	// nacked, so that the retry policy of the subscription delays their
+	// redelivery. If zero, the held messages are not limited.
+	MaxHeldMessages int
+
+	// MaxConcurrency is the maximum number of events processed at once. Unlike
+	// the flow control of the subscription, it doesn't count the held messages,
+	// so that the events that are due are not blocked behind the events held
+	// until a later retry time. If zero, it is not limited.
+	MaxConcurrency int
+
+	// Reschedule republishes an event that is still not due after being held
+	// for MaxRetryHold, so that its message is acked rather than nacked.
+	// Nacked messages count toward the delivery attempts of the dead letter
+	// policy of the subscription.
+	Reschedule func(ctx context.Context, e *event.Event) error
+
 	// cancel is function to stop pulling messages.
 	cancel context.CancelFunc
 
 	// alive is a bool indicator that the handler is still alive.
 	alive atomic.Value
+
+	// held is the number of messages being held.
+	held int32
+	// processing limits the events processed at once to MaxConcurrency.
+	processing chan struct{}
 }
 
 // NewHandler creates a new Handler.
 func NewHandler(
-	sub *pubsub.Subscription,
+	sub queue.Subscription,
 	processor processors.Interface,
 	timeout time.Duration,
 ) *Handler {
@@ -69,6 +103,9 @@ func NewHandler(
 func (h *Handler) Start(ctx context.Context, done func(error)) {
 	ctx, h.cancel = context.WithCancel(ctx)
 	h.alive.Store(true)
+	if h.MaxConcurrency > 0 {
+		h.processing = make(chan struct{}, h.MaxConcurrency)
+	}
 
 	go func() {
 		// For any reason if inbound is closed, mark alive as false.
@@ -88,9 +125,9 @@ func (h *Handler) IsAlive() bool {
 }
 
 // receive converts message to events and invoke processor chain.
-func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
+func (h *Handler) receive(ctx context.Context, msg *queue.Message) {
 	ctx = metrics.StartEventProcessing(ctx)
-	event, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg))
+	event, err := queue.ToEvent(ctx, msg)
 	if isNonRetryable(err) {
 		logEventConversionError(ctx, msg, err, "failed to convert received message to an event, check the msg format")
 		// Ack the message so it won't be retried.
@@ -104,6 +141,26 @@ func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
 		return
 	}
 
+	if h.MaxRetryHold != 0 {
+		if due, held := h.holdUntilRetryTime(ctx, event); !due {
+			if held {
+				h.reschedule(ctx, msg, event)
+			} else {
+				msg.Nack()
+			}
+			return
+		}
+	}
+	if h.processing != nil {
+		select {
+		case h.processing <- struct{}{}:
+			defer func() { <-h.processing }()
+		case <-ctx.Done():
+			msg.Nack()
+			return
+		}
+	}
+
 	if h.Timeout != 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
@@ -118,6 +175,53 @@ func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
 	msg.Ack()
 }
 
+// holdUntilRetryTime blocks until the retry time of the event, if any, for at most MaxRetryHold.
+// It returns whether the event is due for delivery, and whether it was held: an event that is not
+// due isn't held if MaxHeldMessages are already held.
+func (h *Handler) holdUntilRetryTime(ctx context.Context, e *event.Event) (due, held bool) {
+	retryTime, ok := eventutil.GetRetryTime(ctx, e)
+	if !ok {
+		return true, false
+	}
+	d := time.Until(retryTime)
+	if d <= 0 {
+		return true, false
+	}
+	if h.MaxHeldMessages > 0 {
+		defer atomic.AddInt32(&h.held, -1)
+		if atomic.AddInt32(&h.held, 1) > int32(h.MaxHeldMessages) {
+			return false, false
+		}
+	}
+	hold := d
+	if hold > h.MaxRetryHold {
+		hold = h.MaxRetryHold
+	}
+	timer := time.NewTimer(hold)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, true
+	case <-timer.C:
+		return d <= h.MaxRetryHold, true
+	}
+}
+
+// reschedule republishes an event that is not due yet and acks its message, or nacks the message
+// if the event can't be rescheduled.
+func (h *Handler) reschedule(ctx context.Context, msg *queue.Message, e *event.Event) {
+	if h.Reschedule == nil || ctx.Err() != nil {
+		msg.Nack()
+		return
+	}
+	if err := h.Reschedule(ctx, e); err != nil {
+		logging.FromContext(ctx).Error("failed to reschedule event", zap.String("eventID", e.ID()), zap.Error(err))
+		msg.Nack()
+		return
+	}
+	msg.Ack()
+}
+
 func isNonRetryable(err error) bool {
 	// The following errors can be returned by ToEvent and are not retryable.
 	// TODO Should binding.ToEvent consolidate them and return the generic ErrCannotConvertToEvent?
@@ -125,13 +229,16 @@ func isNonRetryable(err error) bool {
 }
 
 // Log the full message in debug level and a truncated version as an error in case the message is too big (can be as big as 10MB),
-func logEventConversionError(ctx context.Context, pm *pubsub.Message, err error, msg string) {
+func logEventConversionError(ctx context.Context, pm *queue.Message, err error, msg string) {
 	maxLen := 2000
 	truncated := pm
 	if len(pm.Data) > maxLen {
-		copy := *pm
-		copy.Data = copy.Data[:maxLen]
-		truncated = &copy
+		truncated = &queue.Message{
+			ID:          pm.ID,
+			Data:        pm.Data[:maxLen],
+			Attributes:  pm.Attributes,
+			OrderingKey: pm.OrderingKey,
+		}
 	}
 	logging.FromContext(ctx).Debug(msg, zap.Any("message", pm), zap.Error(err))
 	logging.FromContext(ctx).Error(msg, zap.Any("message-truncated", truncated), zap.Error(err))
@@ -33,7 +33,9 @@ import (
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
 
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	kgcptesting "github.com/google/knative-gcp/pkg/testing"
 )
 
@@ -88,7 +90,8 @@ func TestHandler(t *testing.T) {
 
 	eventCh := make(chan *event.Event)
 	processor := &processors.FakeProcessor{PrevEventsCh: eventCh}
-	h := NewHandler(sub, processor, time.Second)
+	h := NewHandler(queue.NewPubsubClient(c).Subscription(sub.ID(), pubsub.DefaultReceiveSettings), processor, time.Second)
+	h.MaxRetryHold = time.Second
 	h.Start(ctx, func(err error) {})
 	defer h.Stop()
 	if !h.IsAlive() {
@@ -128,6 +131,22 @@ func TestHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("hold event until retry time", func(t *testing.T) {
+		retryEvent := testEvent.Clone()
+		retryTime := time.Now().Add(500 * time.Millisecond)
+		eventutil.SetRetryTime(&retryEvent, retryTime)
+		if err := p.Send(ctx, binding.ToMessage(&retryEvent)); err != nil {
+			t.Fatalf("failed to seed event to pubsub: %v", err)
+		}
+		gotEvent := nextEventWithTimeout(eventCh)
+		if gotEvent == nil {
+			t.Fatal("processor didn't receive the held event")
+		}
+		if now := time.Now(); now.Before(retryTime) {
+			t.Errorf("event processed at %v, before its retry time %v", now, retryTime)
+		}
+	})
+
 	t.Run("message is not an event", func(t *testing.T) {
 		res := topic.Publish(context.Background(), &pubsub.Message{ID: "testid"})
 		if _, err := res.Get(context.Background()); err != nil {
@@ -160,6 +179,134 @@ func TestHandler(t *testing.T) {
 	})
 }
 
+func TestHandlerReschedulesEventsNotDue(t *testing.T) {
+	ctx := context.Background()
+	c, close := testPubsubClient(ctx, t, testProjectID)
+	defer close()
+
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	p, err := cepubsub.New(context.Background(),
+		cepubsub.WithClient(c),
+		cepubsub.WithProjectID(testProjectID),
+		cepubsub.WithTopicID(testTopic),
+	)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents pubsub protocol: %v", err)
+	}
+
+	eventCh := make(chan *event.Event)
+	rescheduled := make(chan *event.Event, 10)
+	h := NewHandler(queue.NewPubsubClient(c).Subscription(sub.ID(), pubsub.DefaultReceiveSettings), &processors.FakeProcessor{PrevEventsCh: eventCh}, time.Second)
+	h.MaxRetryHold = 100 * time.Millisecond
+	h.Reschedule = func(ctx context.Context, e *event.Event) error {
+		rescheduled <- e
+		return nil
+	}
+	h.Start(ctx, func(err error) {})
+	defer h.Stop()
+
+	testEvent := event.New()
+	testEvent.SetID("id")
+	testEvent.SetSource("source")
+	testEvent.SetType("type")
+	eventutil.SetRetryTime(&testEvent, time.Now().Add(time.Hour))
+	if err := p.Send(ctx, binding.ToMessage(&testEvent)); err != nil {
+		t.Fatalf("failed to seed event to pubsub: %v", err)
+	}
+
+	if got := nextEventWithTimeout(rescheduled); got == nil || got.ID() != testEvent.ID() {
+		t.Fatalf("rescheduled event = %v, want %v", got, testEvent)
+	}
+	if got := nextEventWithTimeout(eventCh); got != nil {
+		t.Errorf("processor received event %v before its retry time", got)
+	}
+	// The message is acked, so it is not redelivered and rescheduled again.
+	select {
+	case got := <-rescheduled:
+		t.Errorf("event rescheduled again: %v", got)
+	default:
+	}
+}
+
+// TestHandlerHeldEventsDontBlockDueEvents checks that an event held until a retry time beyond
+// the longest hold doesn't take the place of the events that are due.
+func TestHandlerHeldEventsDontBlockDueEvents(t *testing.T) {
+	ctx := context.Background()
+	c, close := testPubsubClient(ctx, t, testProjectID)
+	defer close()
+
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	p, err := cepubsub.New(context.Background(),
+		cepubsub.WithClient(c),
+		cepubsub.WithProjectID(testProjectID),
+		cepubsub.WithTopicID(testTopic),
+	)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents pubsub protocol: %v", err)
+	}
+
+	// The retry subscription is limited to one outstanding message.
+	rs := pubsub.DefaultReceiveSettings
+	rs.MaxOutstandingMessages = 1
+	rs, concurrency := retryReceiveSettings(rs)
+	eventCh := make(chan *event.Event)
+	rescheduled := make(chan *event.Event, 10)
+	h := NewHandler(queue.NewPubsubClient(c).Subscription(sub.ID(), rs), &processors.FakeProcessor{PrevEventsCh: eventCh}, time.Second)
+	h.MaxRetryHold = time.Hour
+	h.MaxHeldMessages = maxHeldMessages
+	h.MaxConcurrency = concurrency
+	h.Reschedule = func(ctx context.Context, e *event.Event) error {
+		rescheduled <- e
+		return nil
+	}
+	h.Start(ctx, func(err error) {})
+	defer h.Stop()
+
+	held := event.New()
+	held.SetID("held")
+	held.SetSource("source")
+	held.SetType("type")
+	eventutil.SetRetryTime(&held, time.Now().Add(2*time.Hour))
+	if err := p.Send(ctx, binding.ToMessage(&held)); err != nil {
+		t.Fatalf("failed to seed event to pubsub: %v", err)
+	}
+	for _, id := range []string{"due-1", "due-2"} {
+		due := event.New()
+		due.SetID(id)
+		due.SetSource("source")
+		due.SetType("type")
+		if err := p.Send(ctx, binding.ToMessage(&due)); err != nil {
+			t.Fatalf("failed to seed event to pubsub: %v", err)
+		}
+		if got := nextEventWithTimeout(eventCh); got == nil || got.ID() != id {
+			t.Fatalf("processed event = %v, want %q", got, id)
+		}
+	}
+	select {
+	case got := <-rescheduled:
+		t.Errorf("held event rescheduled before the end of the hold: %v", got)
+	default:
+	}
+}
+
 type BenchProcessor struct {
 	processors.BaseProcessor
 
@@ -199,7 +346,7 @@ func runBench(b *testing.B, eventSize int) {
 	processor := &BenchProcessor{
 		processed: semaphore.NewWeighted(maxMsgs),
 	}
-	h := NewHandler(sub, processor, time.Second)
+	h := NewHandler(queue.NewPubsubClient(c).Subscription(sub.ID(), pubsub.DefaultReceiveSettings), processor, time.Second)
 	h.Start(ctx, func(err error) {})
 	defer h.Stop()
 	if !h.IsAlive() {
@@ -21,6 +21,9 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 )
 
 var (
@@ -34,6 +37,21 @@ var (
 	// timeouts.
 	// TODO: consider allow changing this value?
 	maxTimeout = 10 * time.Minute
+
+	// minRetryHold is the minimum time the retry pool holds an event until its
+	// retry time, even if the MaxExtension of the receive settings doesn't
+	// cover the hold and the timeout.
+	minRetryHold = time.Minute
+
+	// maxHeldMessages is the maximum number of messages each retry handler
+	// holds until their retry time. The flow control of the retry subscriptions
+	// is raised by as many messages, so that held messages don't take the place
+	// of the messages that are due.
+	maxHeldMessages = 1000
+
+	// limitExceededRetryDelay is the delay before the retry pool redelivers an
+	// event that exceeded the limits of its target.
+	limitExceededRetryDelay = time.Second
 )
 
 // Options holds all the options for create handler pool.
@@ -50,6 +68,12 @@ type Options struct {
 	DeliveryTimeout time.Duration
 	// PubsubReceiveSettings is the pubsub receive settings.
 	PubsubReceiveSettings pubsub.ReceiveSettings
+	// CircuitBreaker is the settings of the circuit breakers of the targets.
+	// Circuit breakers are disabled by default.
+	CircuitBreaker circuitbreaker.Settings
+	// ClaimCheck rehydrates the data of the events offloaded to Cloud Storage by the ingress.
+	// If nil, events are delivered with their claim check reference.
+	ClaimCheck *claimcheck.Store
 }
 
 // NewOptions creates a Options.
@@ -107,3 +131,17 @@ func WithDeliveryTimeout(t time.Duration) Option {
 		o.DeliveryTimeout = t
 	}
 }
+
+// WithCircuitBreaker sets the CircuitBreaker settings.
+func WithCircuitBreaker(s circuitbreaker.Settings) Option {
+	return func(o *Options) {
+		o.CircuitBreaker = s
+	}
+}
+
+// WithClaimCheck sets the ClaimCheck store.
+func WithClaimCheck(s *claimcheck.Store) Option {
+	return func(o *Options) {
+		o.ClaimCheck = s
+	}
+}
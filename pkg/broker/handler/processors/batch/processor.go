@@ -0,0 +1,176 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+// Processor groups the events of targets with batched delivery. Once a batch is full, or its
+// maximum delay has passed, the batch is passed to the next processor in its own context, which
+// carries the values of its first event and expires with the earliest deadline of its events,
+// along with the first event. Events whose context is done by then are left out of the batch.
+// The result of an event is the result of its batch, so that the events of a batch are acked or
+// nacked together. If the context accepts a deferred result, processing an event returns as soon
+// as it is added to its batch and the result is deferred, otherwise it blocks until the batch has
+// been processed. Events of other targets are passed to the next processor as is.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	mux     sync.Mutex
+	batches map[config.TargetKey]*batch
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// batch is a batch of events being accumulated for a target.
+type batch struct {
+	entries []handlerctx.BatchEntry
+	timer   *time.Timer
+	// done is closed once the batch has been processed, err is then the result.
+	done chan struct{}
+	err  error
+}
+
+// Process adds the event to the batch of its target and defers, or waits for, the result of the
+// batch.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok || target.BatchMaxEvents <= 0 {
+		return p.Next().Process(ctx, e)
+	}
+
+	b := p.add(ctx, tk, target, e)
+	wait := func() error {
+		select {
+		case <-b.done:
+			return b.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if handlerctx.DeferResult(ctx, wait) {
+		return nil
+	}
+	return wait()
+}
+
+// add adds the event to the current batch of the target, starting a new batch if there is none.
+// The batch is sent as soon as it is full.
+func (p *Processor) add(ctx context.Context, tk *config.TargetKey, target *config.Target, e *event.Event) *batch {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.batches == nil {
+		p.batches = make(map[config.TargetKey]*batch)
+	}
+	key := *tk
+	b, ok := p.batches[key]
+	if !ok {
+		b = &batch{done: make(chan struct{})}
+		b.timer = time.AfterFunc(target.BatchMaxDelay.AsDuration(), func() {
+			p.flush(key, b)
+		})
+		p.batches[key] = b
+	}
+	b.entries = append(b.entries, handlerctx.BatchEntry{Ctx: ctx, Event: e})
+	if len(b.entries) >= int(target.BatchMaxEvents) {
+		b.timer.Stop()
+		delete(p.batches, key)
+		go p.send(b)
+	}
+	return b
+}
+
+// flush sends the batch once its maximum delay has passed, unless it was already sent because
+// it was full.
+func (p *Processor) flush(key config.TargetKey, b *batch) {
+	p.mux.Lock()
+	if p.batches[key] != b {
+		p.mux.Unlock()
+		return
+	}
+	delete(p.batches, key)
+	p.mux.Unlock()
+	p.send(b)
+}
+
+// send passes the entries of the batch whose context isn't done yet to the next processor.
+func (p *Processor) send(b *batch) {
+	defer close(b.done)
+	entries := make([]handlerctx.BatchEntry, 0, len(b.entries))
+	var deadline time.Time
+	for _, entry := range b.entries {
+		if entry.Ctx.Err() != nil {
+			continue
+		}
+		entries = append(entries, entry)
+		if d, ok := entry.Ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	first := entries[0]
+	// The batch outlives the processing of its first event, so it must not be canceled with it.
+	var ctx context.Context = detached{first.Ctx}
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	// The entries wait for the result of the batch already.
+	ctx = handlerctx.WithAsyncResult(ctx, nil)
+	b.err = p.Next().Process(handlerctx.WithBatch(ctx, entries), first.Event)
+}
+
+// detached is a context with the values of its parent that is never canceled.
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+// recordBatches records the IDs of the events of every batch it processes.
+type recordBatches struct {
+	processors.BaseProcessor
+	err error
+
+	mux     sync.Mutex
+	batches [][]string
+}
+
+func (p *recordBatches) Process(ctx context.Context, e *event.Event) error {
+	ids := []string{e.ID()}
+	if batch, ok := handlerctx.GetBatch(ctx); ok {
+		ids = nil
+		for _, entry := range batch {
+			ids = append(ids, entry.Event.ID())
+		}
+	}
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.batches = append(p.batches, ids)
+	return p.err
+}
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrTargetKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrTargetKeyNotPresent)
+	}
+}
+
+func TestBatchProcessor(t *testing.T) {
+	cases := []struct {
+		name        string
+		maxEvents   int32
+		maxDelay    time.Duration
+		events      int
+		err         error
+		wantBatches [][]string
+	}{{
+		name:        "batching disabled",
+		events:      2,
+		wantBatches: [][]string{{"0"}, {"1"}},
+	}, {
+		name:        "full batches",
+		maxEvents:   2,
+		maxDelay:    time.Hour,
+		events:      4,
+		wantBatches: [][]string{{"0", "1"}, {"2", "3"}},
+	}, {
+		name:        "batch sent after max delay",
+		maxEvents:   10,
+		maxDelay:    500 * time.Millisecond,
+		events:      3,
+		wantBatches: [][]string{{"0", "1", "2"}},
+	}, {
+		name:        "batch error",
+		maxEvents:   2,
+		maxDelay:    time.Hour,
+		events:      2,
+		err:         errors.New("batch error"),
+		wantBatches: [][]string{{"0", "1"}},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, testTargets := newTestTargets(tc.maxEvents, tc.maxDelay)
+			next := &recordBatches{err: tc.err}
+			p := &Processor{Targets: testTargets}
+			p.WithNext(next)
+
+			// Events are added one at a time so that batches are deterministic, each event
+			// is processed in its own goroutine as it blocks until its batch is processed.
+			errs := make(chan error, tc.events)
+			for i := 0; i < tc.events; i++ {
+				e := event.New()
+				e.SetID(fmt.Sprint(i))
+				e.SetSource("source")
+				e.SetType("type")
+				go func() {
+					errs <- p.Process(ctx, &e)
+				}()
+				waitForEntries(t, p, i, tc.maxEvents)
+			}
+			for i := 0; i < tc.events; i++ {
+				if err := <-errs; err != tc.err {
+					t.Errorf("Process error got=%v, want=%v", err, tc.err)
+				}
+			}
+
+			next.mux.Lock()
+			defer next.mux.Unlock()
+			if diff := cmp.Diff(tc.wantBatches, next.batches); diff != "" {
+				t.Errorf("unexpected batches (-want,+got): %s", diff)
+			}
+		})
+	}
+}
+
+// waitForEntries waits until the i-th event was added to its batch.
+func waitForEntries(t *testing.T, p *Processor, i int, maxEvents int32) {
+	if maxEvents <= 0 {
+		time.Sleep(10 * time.Millisecond)
+		return
+	}
+	want := i%int(maxEvents) + 1
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		p.mux.Lock()
+		n := 0
+		for _, b := range p.batches {
+			n = len(b.entries)
+		}
+		p.mux.Unlock()
+		// A full batch is removed from the pending batches.
+		if n == want || (n == 0 && want == int(maxEvents)) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for event %d to be added to its batch", i)
+}
+
+func newTestTargets(maxEvents int32, maxDelay time.Duration) (context.Context, config.Targets) {
+	testTarget := &config.Target{
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		BatchMaxEvents: maxEvents,
+	}
+	if maxEvents > 0 {
+		testTarget.BatchMaxDelay = durationpb.New(maxDelay)
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(testTarget.Key().ParentKey(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(testTarget)
+	})
+	ctx := handlerctx.WithTargetKey(context.Background(), testTarget.Key())
+	return ctx, testTargets
+}
+
+func TestBatchProcessorDefersResult(t *testing.T) {
+	ctx, testTargets := newTestTargets(2, time.Hour)
+	next := &recordBatches{}
+	p := &Processor{Targets: testTargets}
+	p.WithNext(next)
+
+	// The first event returns as soon as it is added to its batch, the second one fills the
+	// batch.
+	results := make([]*handlerctx.AsyncResult, 2)
+	for i := range results {
+		e := event.New()
+		e.SetID(fmt.Sprint(i))
+		results[i] = &handlerctx.AsyncResult{}
+		if err := p.Process(handlerctx.WithAsyncResult(ctx, results[i]), &e); err != nil {
+			t.Fatalf("Process error got=%v, want=nil", err)
+		}
+		if !results[i].Pending() {
+			t.Fatalf("result of event %d wasn't deferred", i)
+		}
+	}
+	for i, r := range results {
+		if err := r.Wait(); err != nil {
+			t.Errorf("result of event %d got=%v, want=nil", i, err)
+		}
+	}
+
+	next.mux.Lock()
+	defer next.mux.Unlock()
+	if diff := cmp.Diff([][]string{{"0", "1"}}, next.batches); diff != "" {
+		t.Errorf("unexpected batches (-want,+got): %s", diff)
+	}
+}
+
+// recordContext records the context the batch is processed with, and its error once released.
+type recordContext struct {
+	processors.BaseProcessor
+	ctx     chan context.Context
+	release chan struct{}
+	err     chan error
+}
+
+func (p *recordContext) Process(ctx context.Context, e *event.Event) error {
+	p.ctx <- ctx
+	<-p.release
+	p.err <- ctx.Err()
+	return nil
+}
+
+func TestBatchProcessorOwnContext(t *testing.T) {
+	ctx, testTargets := newTestTargets(2, time.Hour)
+	next := &recordContext{
+		ctx:     make(chan context.Context, 1),
+		release: make(chan struct{}),
+		err:     make(chan error, 1),
+	}
+	p := &Processor{Targets: testTargets}
+	p.WithNext(next)
+
+	// The first event is timed out before the batch is sent, so it is left out of the batch.
+	timedOutCtx, cancelTimedOut := context.WithCancel(ctx)
+	timedOut := &handlerctx.AsyncResult{}
+	e := event.New()
+	e.SetID("0")
+	if err := p.Process(handlerctx.WithAsyncResult(timedOutCtx, timedOut), &e); err != nil {
+		t.Fatalf("Process error got=%v, want=nil", err)
+	}
+	cancelTimedOut()
+
+	deadline := time.Now().Add(time.Hour)
+	liveCtx, cancelLive := context.WithDeadline(ctx, deadline)
+	defer cancelLive()
+	live := &handlerctx.AsyncResult{}
+	e1 := event.New()
+	e1.SetID("1")
+	if err := p.Process(handlerctx.WithAsyncResult(liveCtx, live), &e1); err != nil {
+		t.Fatalf("Process error got=%v, want=nil", err)
+	}
+
+	batchCtx := <-next.ctx
+	// The batch isn't canceled with the context of its first event.
+	cancelLive()
+	close(next.release)
+	if err := timedOut.Wait(); err != context.Canceled {
+		t.Errorf("result of the timed out event got=%v, want=%v", err, context.Canceled)
+	}
+	if err := <-next.err; err != nil {
+		t.Errorf("batch context error got=%v, want=nil", err)
+	}
+	if d, ok := batchCtx.Deadline(); !ok || !d.Equal(deadline) {
+		t.Errorf("batch deadline got=%v, want=%v", d, deadline)
+	}
+	batch, _ := handlerctx.GetBatch(batchCtx)
+	if len(batch) != 1 || batch[0].Event.ID() != "1" {
+		t.Errorf("batch got=%v, want only event 1", batch)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	storagetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func TestDeliverRehydratesOffloadedData(t *testing.T) {
+	const (
+		reference = "gs://bucket/claimcheck/broker-uid/id"
+		data      = `{"large":"document"}`
+	)
+	cases := []struct {
+		name           string
+		stored         bool
+		attempts       int32
+		targetCode     int
+		retryOnFailure bool
+		wantDelivered  bool
+		wantRetried    bool
+		wantDeadLetter bool
+	}{{
+		name:          "rehydrated for the subscriber",
+		stored:        true,
+		targetCode:    http.StatusOK,
+		wantDelivered: true,
+	}, {
+		name:           "retry topic gets the reference",
+		stored:         true,
+		targetCode:     http.StatusInternalServerError,
+		retryOnFailure: true,
+		wantDelivered:  true,
+		wantRetried:    true,
+	}, {
+		name:           "missing data is retried",
+		targetCode:     http.StatusOK,
+		retryOnFailure: true,
+		wantRetried:    true,
+	}, {
+		name:           "rehydrated for the dead letter sink",
+		stored:         true,
+		attempts:       4,
+		targetCode:     http.StatusInternalServerError,
+		wantDelivered:  true,
+		wantDeadLetter: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetHandler := &deadLetterHandler{t: t, responseCode: tc.targetCode}
+			targetSvr := httptest.NewServer(targetHandler)
+			defer targetSvr.Close()
+			dlHandler := &deadLetterHandler{t: t, responseCode: http.StatusOK}
+			dlSvr := httptest.NewServer(dlHandler)
+			defer dlSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			objects := storagetesting.NewTestObjects()
+			if tc.stored {
+				objects.Put("claimcheck/broker-uid/id", storagetesting.TestObject{Data: []byte(data)})
+			}
+			client, err := storagetesting.TestClientCreator(storagetesting.TestClientData{
+				BucketData: storagetesting.TestBucketData{Objects: objects},
+			})(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+			}
+			if !tc.retryOnFailure {
+				target.DeadLetterAddress = dlSvr.URL
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.SetID("broker-uid").UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				RetryOnFailure:     tc.retryOnFailure,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+				ClaimCheck:         claimcheck.NewStore(client, "bucket"),
+			}
+
+			origin := newSampleEvent()
+			origin.SetDataContentType(event.ApplicationJSON)
+			origin.SetExtension(claimcheck.Extension, reference)
+			if tc.attempts > 0 {
+				eventutil.SetDeliveryAttempts(origin, tc.attempts)
+			}
+			if err := p.Process(ctx, origin); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+
+			checkRehydrated := func(kind string, events []*event.Event, want bool) {
+				t.Helper()
+				if !want {
+					if len(events) != 0 {
+						t.Errorf("got %d events sent to the %s, want 0", len(events), kind)
+					}
+					return
+				}
+				if len(events) != 1 {
+					t.Fatalf("got %d events sent to the %s, want 1", len(events), kind)
+				}
+				if got := string(events[0].Data()); got != data {
+					t.Errorf("%s event data got=%q, want=%q", kind, got, data)
+				}
+				if _, ok := events[0].Extensions()[claimcheck.Extension]; ok {
+					t.Errorf("%s event has the %s extension", kind, claimcheck.Extension)
+				}
+			}
+			checkRehydrated("subscriber", targetHandler.events, tc.wantDelivered)
+			checkRehydrated("dead letter sink", dlHandler.events, tc.wantDeadLetter)
+
+			msgs := srv.Messages()
+			if !tc.wantRetried {
+				if len(msgs) != 0 {
+					t.Errorf("got %d messages in the retry topic, want 0", len(msgs))
+				}
+				return
+			}
+			if len(msgs) != 1 {
+				t.Fatalf("got %d messages in the retry topic, want 1", len(msgs))
+			}
+			if got := msgs[0].Attributes["ce-"+claimcheck.Extension]; got != reference {
+				t.Errorf("retried event reference got=%q, want=%q", got, reference)
+			}
+			if len(msgs[0].Data) != 0 {
+				t.Errorf("retried event has data %q", msgs[0].Data)
+			}
+		})
+	}
+}
@@ -17,11 +17,17 @@ limitations under the License.
 package deliver
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,16 +39,54 @@ import (
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.opencensus.io/trace"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
-const defaultEventHopsLimit int32 = 255
+const (
+	defaultEventHopsLimit int32 = 255
+
+	// defaultMaxDeliveryAttempts is used for targets with a dead letter address but no maximum
+	// delivery attempts. It matches the Pub/Sub default for dead letter policies.
+	defaultMaxDeliveryAttempts int32 = 5
+
+	// maxRetryBackoff caps the delay between delivery attempts, so that exponential backoff
+	// cannot overflow.
+	maxRetryBackoff = 24 * time.Hour
+
+	// maxErrorDataSize is the maximum number of bytes of the subscriber response body that is
+	// attached to dead lettered events.
+	maxErrorDataSize = 1024
+
+	// Extensions attached to events sent to a dead letter address. They match the extensions
+	// Knative Eventing uses for dead lettered events.
+	ErrorDestExtension = "knativeerrordest"
+	ErrorCodeExtension = "knativeerrorcode"
+	ErrorDataExtension = "knativeerrordata"
+)
+
+// ErrCircuitOpen is returned when the delivery of an event is short-circuited because the circuit
+// breaker of its target is open, and the event cannot be sent to the retry topic of the target.
+var ErrCircuitOpen = errors.New("target circuit breaker is open")
+
+// subscriberError is returned when the subscriber responds with a non-2xx status code.
+type subscriberError struct {
+	code int
+	body []byte
+}
+
+func (e *subscriberError) Error() string {
+	return fmt.Sprintf("event delivery failed: HTTP status code %d", e.code)
+}
 
 // Processor delivers events based on the broker/target in the context.
 type Processor struct {
@@ -68,6 +112,15 @@ type Processor struct {
 
 	// StatsReporter is used to report delivery metrics.
 	StatsReporter *metrics.DeliveryReporter
+
+	// CircuitBreakers if set, short-circuits the delivery of events to targets
+	// whose subscriber keeps failing.
+	CircuitBreakers *circuitbreaker.Registry
+
+	// ClaimCheck if set, rehydrates the data that the ingress offloaded to Cloud
+	// Storage before events are sent to subscribers and dead letter sinks. Events
+	// sent to the retry and dead letter topics keep their reference.
+	ClaimCheck *claimcheck.Store
 }
 
 var _ processors.Interface = (*Processor)(nil)
@@ -103,6 +156,10 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 		return nil
 	}
 
+	if batch, ok := handlerctx.GetBatch(ctx); ok {
+		return p.processBatch(ctx, target, broker, batch)
+	}
+
 	// Hops is a broker local counter so remove any hops value before forwarding.
 	// Do not modify the original event as we need to send the original
 	// event to retry queue on failure.
@@ -128,8 +185,33 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 		defer cancel()
 	}
 
-	if err := p.deliver(dctx, target, broker, eventutil.NewImmutableEventMessage(e), hops); err != nil {
+	if target.Address != "" {
+		if !p.allow(ctx, target) {
+			return p.shortCircuit(ctx, target, broker, e)
+		}
+		p.StatsReporter.ReportEventDeliveryAttempt(ctx, p.deliveryAttempt(ctx, e))
+	}
+
+	// The retry topic gets the event as it was received, so only the delivered event is
+	// rehydrated. Failing to rehydrate it is handled like a failed delivery.
+	delivered, err := p.rehydrate(dctx, broker, e)
+	if err == nil {
+		err = p.deliver(dctx, target, broker, eventutil.NewImmutableEventMessage(delivered), hops)
+	}
+	if err != nil {
 		if !p.RetryOnFailure {
+			if target.BackoffDelay == nil && target.DeadLetterAddress == "" {
+				// Let the retry subscription redeliver the event.
+				return err
+			}
+			return p.retryOrDeadLetter(ctx, target, broker, e, err)
+		}
+
+		if broker.OrderingKeyAttribute != "" {
+			// Enqueueing the event for retry would let later events with the same ordering key
+			// overtake it. Fail the event instead, so that Pub/Sub redelivers it along with the
+			// events after it, in order.
+			logging.FromContext(ctx).Warn("ordered target delivery failed", zap.Stringer("target", tk), zap.Error(err))
 			return err
 		}
 
@@ -138,13 +220,128 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 			[]trace.Attribute{trace.StringAttribute("error_message", err.Error())},
 			"enqueueing for retry",
 		)
-
-		return p.sendToRetryTopic(ctx, target, e)
+		return p.enqueueForRetry(ctx, target, e)
 	}
 	// For post-delivery processing.
 	return p.Next().Process(ctx, e)
 }
 
+// processBatch delivers a batch of events to the target in a single
+// application/cloudevents-batch+json request. Replies to batches are ignored. Metrics are
+// reported for every event of the batch, in the context it was received in.
+func (p *Processor) processBatch(ctx context.Context, target *config.Target, broker *config.CellTenant, batch []handlerctx.BatchEntry) error {
+	if target.Address != "" && !p.allow(ctx, target) {
+		var errs error
+		for _, entry := range batch {
+			errs = multierr.Append(errs, p.shortCircuit(entry.Ctx, target, broker, entry.Event))
+		}
+		return errs
+	}
+	for _, entry := range batch {
+		p.StatsReporter.FinishEventProcessing(entry.Ctx)
+		p.StatsReporter.ReportEventDeliveryAttempt(entry.Ctx, p.deliveryAttempt(entry.Ctx, entry.Event))
+	}
+
+	dctx := ctx
+	if p.DeliverTimeout > 0 {
+		var cancel context.CancelFunc
+		dctx, cancel = context.WithTimeout(dctx, p.DeliverTimeout)
+		defer cancel()
+	}
+
+	if err := p.sendBatchToSubscriber(dctx, target, broker, batch); err != nil {
+		if !p.RetryOnFailure || broker.OrderingKeyAttribute != "" {
+			// Fail the whole batch, so that Pub/Sub redelivers all of its events.
+			return err
+		}
+
+		logging.FromContext(ctx).Warn("target batch delivery failed", zap.String("target", target.Name), zap.Int("events", len(batch)), zap.Error(err))
+		trace.FromContext(ctx).Annotate(
+			[]trace.Attribute{trace.StringAttribute("error_message", err.Error())},
+			"enqueueing batch for retry",
+		)
+		// Events are retried one at a time. If any of them cannot be enqueued, the whole batch
+		// is redelivered.
+		var errs error
+		for _, entry := range batch {
+			errs = multierr.Append(errs, p.enqueueForRetry(entry.Ctx, target, entry.Event))
+		}
+		return errs
+	}
+	// For post-delivery processing.
+	for _, entry := range batch {
+		if err := p.Next().Process(entry.Ctx, entry.Event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendBatchToSubscriber sends the events of the batch, rehydrated and without their broker local
+// extensions, to the subscriber in a single request.
+func (p *Processor) sendBatchToSubscriber(ctx context.Context, target *config.Target, broker *config.CellTenant, batch []handlerctx.BatchEntry) error {
+	if target.Address == "" {
+		return fmt.Errorf("trigger %s/%s has no subscriber address", target.Namespace, target.Name)
+	}
+	events := make([]event.Event, 0, len(batch))
+	for _, entry := range batch {
+		rehydrated, err := p.rehydrate(ctx, broker, entry.Event)
+		if err != nil {
+			return err
+		}
+		e := rehydrated.Clone()
+		for _, ext := range []string{eventutil.HopsAttribute, eventutil.AttemptsAttribute, eventutil.RetryTimeAttribute} {
+			e.SetExtension(ext, nil)
+		}
+		events = append(events, e)
+	}
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to encode batch: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", event.ApplicationCloudEventsBatchJSON)
+
+	startTime := time.Now()
+	resp, err := p.DeliverClient.Do(req)
+	p.recordSubscriberResponse(ctx, target, resp)
+	if err != nil {
+		var result *url.Error
+		if errors.As(err, &result) && result.Timeout() {
+			// If the delivery is cancelled because of timeout, report event dispatch time without resp status code.
+			for _, entry := range batch {
+				p.StatsReporter.ReportEventDispatchTime(entry.Ctx, time.Since(startTime))
+			}
+		}
+		return fmt.Errorf("failed to send batch to subscriber: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Warn("failed to close response body", zap.Error(err))
+		}
+	}()
+
+	// Report the dispatch time of every event, so that the event count matches the number of
+	// events rather than requests.
+	dispatchTime := time.Since(startTime)
+	for _, entry := range batch {
+		cctx, err := metrics.AddRespStatusCodeTags(entry.Ctx, resp.StatusCode)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to add status code tags to context", zap.Error(err))
+		}
+		p.StatsReporter.ReportEventDispatchTime(cctx, dispatchTime)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorDataSize))
+		return &subscriberError{code: resp.StatusCode, body: body}
+	}
+	return nil
+}
+
 // deliver delivers msg to target and sends the target's reply to the broker ingress.
 func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *config.CellTenant, msg binding.Message, hops int32) error {
 	// Channels can have a reply address without a subscriber. So default the replyMessage to the
@@ -179,7 +376,11 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 		return nil
 	}
 
-	var transformers []binding.Transformer
+	transformers := []binding.Transformer{
+		// Channels without a subscriber forward the original event, remove the retry state from it.
+		transformer.DeleteExtension(eventutil.AttemptsAttribute),
+		transformer.DeleteExtension(eventutil.RetryTimeAttribute),
+	}
 	if target.CellTenantType == config.CellTenantType_BROKER {
 		// Hops only exist for the Broker. Nothing else uses them.
 		// Attach the previous hops for the reply.
@@ -205,11 +406,14 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 
 func (p *Processor) sendToSubscriber(ctx context.Context, target *config.Target, msg binding.Message, hops int32) (*cehttp.Message, func(), error) {
 	transformers := []binding.Transformer{
-		// Remove hops from forwarded event.
+		// Remove hops and retry state from forwarded event.
 		transformer.DeleteExtension(eventutil.HopsAttribute),
+		transformer.DeleteExtension(eventutil.AttemptsAttribute),
+		transformer.DeleteExtension(eventutil.RetryTimeAttribute),
 	}
 	startTime := time.Now()
 	resp, err := p.sendMsg(ctx, target.Address, msg, transformers...)
+	p.recordSubscriberResponse(ctx, target, resp)
 	if err != nil {
 		var result *url.Error
 		if errors.As(err, &result) && result.Timeout() {
@@ -234,7 +438,9 @@ func (p *Processor) sendToSubscriber(ctx context.Context, target *config.Target,
 	p.StatsReporter.ReportEventDispatchTime(cctx, time.Since(startTime))
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, closeBody, fmt.Errorf("event delivery failed: HTTP status code %d", resp.StatusCode)
+		// Keep the beginning of the response body in case the event is dead lettered.
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorDataSize))
+		return nil, closeBody, &subscriberError{code: resp.StatusCode, body: body}
 	}
 
 	// Pre-check the reply response header, if it's not in structured mode/batched mode or binary mode,
@@ -311,10 +517,231 @@ func (p *Processor) sendMsg(ctx context.Context, address string, msg binding.Mes
 	return p.DeliverClient.Do(req)
 }
 
+// allow returns whether the circuit breaker of the target lets the delivery through.
+func (p *Processor) allow(ctx context.Context, target *config.Target) bool {
+	return p.CircuitBreakers == nil || p.CircuitBreakers.Allow(ctx, target)
+}
+
+// recordSubscriberResponse records the response of the subscriber, or its absence, in the circuit
+// breaker of the target. Only connection errors, timeouts, 5xx and 429 responses count as
+// failures, any other response shows that the subscriber is available.
+func (p *Processor) recordSubscriberResponse(ctx context.Context, target *config.Target, resp *http.Response) {
+	if p.CircuitBreakers == nil {
+		return
+	}
+	failed := resp == nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	p.CircuitBreakers.Record(ctx, target, !failed)
+}
+
+// rehydrate returns the event with the data offloaded to Cloud Storage by the ingress, or the
+// event itself if its data was not offloaded.
+func (p *Processor) rehydrate(ctx context.Context, broker *config.CellTenant, e *event.Event) (*event.Event, error) {
+	if p.ClaimCheck == nil {
+		return e, nil
+	}
+	return p.ClaimCheck.Rehydrate(ctx, broker.Id, e)
+}
+
+// shortCircuit handles an event that is not delivered because the circuit breaker of its target is
+// open. Events from the decouple queue are sent straight to the retry topic, unless the broker is
+// ordered. Events from the retry queue are republished to the retry topic, scheduled for when the
+// circuit breaker lets a probe delivery through, with their delivery attempts unchanged. They are
+// not nacked, so that they don't count toward the delivery attempts of the dead letter policy of
+// the retry subscription.
+func (p *Processor) shortCircuit(ctx context.Context, target *config.Target, broker *config.CellTenant, e *event.Event) error {
+	logging.FromContext(ctx).Debug("target circuit breaker is open, skipping delivery", zap.String("target", target.Name))
+	trace.FromContext(ctx).Annotate(nil, "target circuit breaker is open")
+	if broker.OrderingKeyAttribute != "" || p.DeliverRetryClient == nil {
+		return ErrCircuitOpen
+	}
+	if p.RetryOnFailure {
+		return p.enqueueForRetry(ctx, target, e)
+	}
+	retryEvent := originalEvent(ctx, e).Clone()
+	eventutil.SetRetryTime(&retryEvent, time.Now().Add(p.CircuitBreakers.RetryAfter(target)))
+	return p.sendToRetryTopic(ctx, target, &retryEvent)
+}
+
+// enqueueForRetry sends the event to the retry topic of the target after a failed delivery from
+// the decouple queue, scheduled for the first retry if the target has a backoff delay.
+func (p *Processor) enqueueForRetry(ctx context.Context, target *config.Target, e *event.Event) error {
+	e = originalEvent(ctx, e)
+	if target.BackoffDelay != nil {
+		retryEvent := e.Clone()
+		eventutil.SetRetryTime(&retryEvent, time.Now().Add(retryBackoff(target, 1)))
+		return p.sendToRetryTopic(ctx, target, &retryEvent)
+	}
+	return p.sendToRetryTopic(ctx, target, e)
+}
+
 func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target, event *event.Event) error {
-	pctx := cecontext.WithTopic(ctx, target.RetryQueue.Topic)
+	pctx := queue.WithProject(cecontext.WithTopic(ctx, target.RetryQueue.Topic), target.RetryQueue.ProjectId)
 	if err := p.DeliverRetryClient.Send(pctx, *event); err != nil {
 		return fmt.Errorf("failed to send event to retry topic: %w", err)
 	}
 	return nil
 }
+
+// deliveryAttempt returns the attempt number of the delivery of the event, starting at 1 for the
+// first delivery from the decouple queue. Events redelivered by the retry subscription without
+// the retry pool tracking their attempts are all reported as the first retry.
+func (p *Processor) deliveryAttempt(ctx context.Context, e *event.Event) int64 {
+	if p.RetryOnFailure {
+		return 1
+	}
+	return int64(eventutil.GetDeliveryAttempts(ctx, e)) + 2
+}
+
+// retryBackoff returns the delay before the given retry of an event to the target, starting at 1
+// for the first retry. Linear backoff waits backoff_delay times the retry number, exponential
+// backoff doubles the delay on every retry.
+func retryBackoff(target *config.Target, retry int32) time.Duration {
+	delay := target.BackoffDelay.AsDuration()
+	if delay <= 0 || retry <= 0 {
+		return 0
+	}
+	var backoff time.Duration
+	if target.BackoffPolicy == config.BackoffPolicy_LINEAR {
+		backoff = delay * time.Duration(retry)
+	} else {
+		backoff = delay
+		for i := int32(1); i < retry && backoff < maxRetryBackoff; i++ {
+			backoff *= 2
+		}
+	}
+	if backoff <= 0 || backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return backoff
+}
+
+// retryOrDeadLetter handles a failed delivery of an event from the retry queue of a target with a
+// backoff delay or a dead letter address. The delivery attempts are tracked in the event, and the
+// event is republished to the retry topic, scheduled according to the backoff policy of the
+// target, until it exhausts its delivery attempts. It is then sent to the dead letter sink, or
+// dropped if there is none.
+func (p *Processor) retryOrDeadLetter(ctx context.Context, target *config.Target, broker *config.CellTenant, e *event.Event, deliveryErr error) error {
+	e = originalEvent(ctx, e)
+	attempts := eventutil.GetDeliveryAttempts(ctx, e) + 1
+	maxAttempts := target.MaxDeliveryAttempts
+	if maxAttempts <= 0 && (target.DeadLetterAddress != "" || target.DeadLetterTopic != "") {
+		maxAttempts = defaultMaxDeliveryAttempts
+	}
+	if maxAttempts <= 0 || attempts < maxAttempts {
+		logging.FromContext(ctx).Debug("target delivery failed, enqueueing for retry",
+			zap.String("target", target.Name),
+			zap.Int32("attempts", attempts),
+			zap.Error(deliveryErr),
+		)
+		retryEvent := e.Clone()
+		eventutil.SetDeliveryAttempts(&retryEvent, attempts)
+		if target.BackoffDelay != nil {
+			eventutil.SetRetryTime(&retryEvent, time.Now().Add(retryBackoff(target, attempts+1)))
+		}
+		return p.sendToRetryTopic(ctx, target, &retryEvent)
+	}
+
+	switch {
+	case target.DeadLetterAddress != "":
+		logging.FromContext(ctx).Warn("target delivery attempts exhausted, sending to dead letter sink",
+			zap.String("target", target.Name),
+			zap.Int32("attempts", attempts),
+			zap.Error(deliveryErr),
+		)
+		trace.FromContext(ctx).Annotate(
+			[]trace.Attribute{trace.StringAttribute("error_message", deliveryErr.Error())},
+			"sending to dead letter sink",
+		)
+		return p.sendToDeadLetter(ctx, target, broker, e, deliveryErr)
+	case target.DeadLetterTopic != "":
+		logging.FromContext(ctx).Warn("target delivery attempts exhausted, sending to dead letter topic",
+			zap.String("target", target.Name),
+			zap.Int32("attempts", attempts),
+			zap.Error(deliveryErr),
+		)
+		trace.FromContext(ctx).Annotate(
+			[]trace.Attribute{trace.StringAttribute("error_message", deliveryErr.Error())},
+			"sending to dead letter topic",
+		)
+		return p.sendToDeadLetterTopic(ctx, target, e, deliveryErr)
+	default:
+		logging.FromContext(ctx).Warn("target delivery attempts exhausted, dropping event",
+			zap.String("target", target.Name),
+			zap.Int32("attempts", attempts),
+			zap.Error(deliveryErr),
+		)
+		trace.FromContext(ctx).Annotate(
+			append(
+				ceclient.EventTraceAttributes(e),
+				trace.StringAttribute("error_message", deliveryErr.Error()),
+			),
+			"event dropped: delivery attempts exhausted",
+		)
+		return nil
+	}
+}
+
+// originalEvent returns the event as it was received, if it was transformed for the target. The
+// retry topic and the dead letter sink always get the original event, so that it is filtered and
+// transformed again on redelivery.
+func originalEvent(ctx context.Context, e *event.Event) *event.Event {
+	if original, ok := handlerctx.GetOriginalEvent(ctx); ok {
+		return original
+	}
+	return e
+}
+
+// deadLetterEvent returns a copy of the event with the extensions describing the failed delivery,
+// and without the broker local ones.
+func deadLetterEvent(target *config.Target, e *event.Event, deliveryErr error) event.Event {
+	dlEvent := e.Clone()
+	dest := target.Address
+	if dest == "" {
+		dest = target.ReplyAddress
+	}
+	dlEvent.SetExtension(ErrorDestExtension, dest)
+	var se *subscriberError
+	if errors.As(deliveryErr, &se) {
+		dlEvent.SetExtension(ErrorCodeExtension, strconv.Itoa(se.code))
+		if len(se.body) > 0 {
+			dlEvent.SetExtension(ErrorDataExtension, base64.StdEncoding.EncodeToString(se.body))
+		}
+	}
+	for _, ext := range []string{eventutil.HopsAttribute, eventutil.AttemptsAttribute, eventutil.RetryTimeAttribute} {
+		dlEvent.SetExtension(ext, nil)
+	}
+	return dlEvent
+}
+
+// sendToDeadLetter sends the original event, rehydrated, to the target's dead letter address,
+// along with the extensions describing the failed delivery.
+func (p *Processor) sendToDeadLetter(ctx context.Context, target *config.Target, broker *config.CellTenant, e *event.Event, deliveryErr error) error {
+	e, err := p.rehydrate(ctx, broker, e)
+	if err != nil {
+		return fmt.Errorf("failed to send event to dead letter sink: %w", err)
+	}
+	dlEvent := deadLetterEvent(target, e, deliveryErr)
+	resp, err := p.sendMsg(ctx, target.DeadLetterAddress, binding.ToMessage(&dlEvent))
+	if err != nil {
+		return fmt.Errorf("failed to send event to dead letter sink: %w", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		logging.FromContext(ctx).Warn("failed to close dead letter sink response body", zap.Error(err))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send event to dead letter sink: HTTP status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendToDeadLetterTopic publishes the original event to the target's dead letter topic, along with
+// the extensions describing the failed delivery.
+func (p *Processor) sendToDeadLetterTopic(ctx context.Context, target *config.Target, e *event.Event, deliveryErr error) error {
+	dlEvent := deadLetterEvent(target, e, deliveryErr)
+	// The dead letter topic lives in the project of the Broker, like the retry topic.
+	pctx := queue.WithProject(cecontext.WithTopic(ctx, target.DeadLetterTopic), target.RetryQueue.GetProjectId())
+	if err := p.DeliverRetryClient.Send(pctx, dlEvent); err != nil {
+		return fmt.Errorf("failed to send event to dead letter topic: %w", err)
+	}
+	return nil
+}
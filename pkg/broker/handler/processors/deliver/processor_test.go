@@ -19,12 +19,17 @@ package deliver
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -42,9 +47,13 @@ import (
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"knative.dev/pkg/logging"
 	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
 
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
@@ -371,6 +380,7 @@ func TestDeliverFailure(t *testing.T) {
 	cases := []struct {
 		name                string
 		withRetry           bool
+		ordered             bool
 		targetHandler       *targetWithFailureHandler
 		replyHandler        statusCodeReplyHandler
 		expectedReplyEvents int
@@ -391,6 +401,12 @@ func TestDeliverFailure(t *testing.T) {
 		withRetry:     true,
 		failRetry:     true,
 		wantErr:       true,
+	}, {
+		name:          "ordered delivery error is not retried",
+		targetHandler: &targetWithFailureHandler{respCode: http.StatusInternalServerError},
+		withRetry:     true,
+		ordered:       true,
+		wantErr:       true,
 	}, {
 		name:          "delivery timeout no retry",
 		targetHandler: &targetWithFailureHandler{delay: time.Second, respCode: http.StatusOK},
@@ -507,6 +523,9 @@ func TestDeliverFailure(t *testing.T) {
 			testTargets := memory.NewEmptyTargets()
 			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
 				bm.SetAddress(broker.Address)
+				if tc.ordered {
+					bm.SetOrderingKeyAttribute("subject")
+				}
 				bm.UpsertTargets(target)
 			})
 			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
@@ -537,6 +556,450 @@ func TestDeliverFailure(t *testing.T) {
 	}
 }
 
+// deadLetterHandler records the events sent to a dead letter sink.
+type deadLetterHandler struct {
+	t            *testing.T
+	responseCode int
+	events       []*event.Event
+}
+
+func (h *deadLetterHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+	if err != nil {
+		h.t.Errorf("Failed to convert dead letter request to event: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.events = append(h.events, e)
+	w.WriteHeader(h.responseCode)
+}
+
+func TestDeliverDeadLetter(t *testing.T) {
+	cases := []struct {
+		name             string
+		attempts         int32
+		maxAttempts      int32
+		deadLetterCode   int
+		wantRetryAttempt int32
+		wantDeadLetter   bool
+		wantErr          bool
+	}{{
+		name:             "first failure is retried",
+		maxAttempts:      2,
+		deadLetterCode:   http.StatusOK,
+		wantRetryAttempt: 1,
+	}, {
+		name:             "default max attempts",
+		attempts:         3,
+		deadLetterCode:   http.StatusOK,
+		wantRetryAttempt: 4,
+	}, {
+		name:           "exhausted attempts are dead lettered",
+		attempts:       1,
+		maxAttempts:    2,
+		deadLetterCode: http.StatusOK,
+		wantDeadLetter: true,
+	}, {
+		name:           "dead letter sink failure",
+		attempts:       4,
+		deadLetterCode: http.StatusInternalServerError,
+		wantDeadLetter: true,
+		wantErr:        true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(&targetWithFailureHandler{
+				t:        t,
+				respCode: http.StatusInternalServerError,
+				respBody: "boom",
+			})
+			defer targetSvr.Close()
+			dlHandler := &deadLetterHandler{t: t, responseCode: tc.deadLetterCode}
+			dlSvr := httptest.NewServer(dlHandler)
+			defer dlSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:           "ns",
+				Name:                "target",
+				CellTenantType:      config.CellTenantType_BROKER,
+				CellTenantName:      "broker",
+				Address:             targetSvr.URL,
+				DeadLetterAddress:   dlSvr.URL,
+				MaxDeliveryAttempts: tc.maxAttempts,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+			}
+
+			origin := newSampleEvent()
+			if tc.attempts > 0 {
+				eventutil.SetDeliveryAttempts(origin, tc.attempts)
+			}
+			err = p.Process(ctx, origin)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("processing got error=%v, want=%v", err, tc.wantErr)
+			}
+
+			msgs := srv.Messages()
+			if tc.wantRetryAttempt > 0 {
+				if len(msgs) != 1 {
+					t.Fatalf("got %d messages in the retry topic, want 1", len(msgs))
+				}
+				if got := msgs[0].Attributes["ce-"+eventutil.AttemptsAttribute]; got != fmt.Sprint(tc.wantRetryAttempt) {
+					t.Errorf("retried event attempts got=%q, want=%d", got, tc.wantRetryAttempt)
+				}
+			} else if len(msgs) != 0 {
+				t.Errorf("got %d messages in the retry topic, want 0", len(msgs))
+			}
+
+			if !tc.wantDeadLetter {
+				if len(dlHandler.events) != 0 {
+					t.Errorf("got %d dead lettered events, want 0", len(dlHandler.events))
+				}
+				return
+			}
+			if len(dlHandler.events) != 1 {
+				t.Fatalf("got %d dead lettered events, want 1", len(dlHandler.events))
+			}
+			dl := dlHandler.events[0]
+			if dl.ID() != origin.ID() {
+				t.Errorf("dead lettered event id got=%q, want=%q", dl.ID(), origin.ID())
+			}
+			wantExtensions := map[string]interface{}{
+				ErrorDestExtension: targetSvr.URL,
+				ErrorCodeExtension: "500",
+				ErrorDataExtension: base64.StdEncoding.EncodeToString([]byte("boom")),
+			}
+			if diff := cmp.Diff(wantExtensions, dl.Extensions()); diff != "" {
+				t.Errorf("dead lettered event extensions (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+// TestDeliverUnresolvedDeadLetterSink checks that the events of a target whose dead letter sink
+// couldn't be resolved are redelivered by the retry subscription instead of being dropped. The
+// BrokerCell reconciler leaves the delivery policy out of the config of such targets.
+func TestDeliverUnresolvedDeadLetterSink(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetSvr := httptest.NewServer(&targetWithFailureHandler{
+		t:        t,
+		respCode: http.StatusInternalServerError,
+		respBody: "boom",
+	})
+	defer targetSvr.Close()
+
+	srv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+	ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+	if err != nil {
+		t.Fatalf("failed to create pubsub protocol: %v", err)
+	}
+	deliverRetryClient, err := ceclient.New(ps)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		DeliverRetryClient: deliverRetryClient,
+		StatsReporter:      r,
+	}
+
+	origin := newSampleEvent()
+	// Well past the default max delivery attempts.
+	eventutil.SetDeliveryAttempts(origin, 100)
+	if err := p.Process(ctx, origin); err == nil {
+		t.Error("processing got nil error, want the event to be redelivered")
+	}
+	if msgs := srv.Messages(); len(msgs) != 0 {
+		t.Errorf("got %d messages in the retry topic, want 0", len(msgs))
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy config.BackoffPolicy
+		delay  time.Duration
+		retry  int32
+		want   time.Duration
+	}{{
+		name:   "exponential first retry",
+		policy: config.BackoffPolicy_EXPONENTIAL,
+		delay:  time.Second,
+		retry:  1,
+		want:   time.Second,
+	}, {
+		name:   "exponential fourth retry",
+		policy: config.BackoffPolicy_EXPONENTIAL,
+		delay:  time.Second,
+		retry:  4,
+		want:   8 * time.Second,
+	}, {
+		name:  "unknown policy is exponential",
+		delay: time.Second,
+		retry: 3,
+		want:  4 * time.Second,
+	}, {
+		name:   "linear",
+		policy: config.BackoffPolicy_LINEAR,
+		delay:  2 * time.Second,
+		retry:  3,
+		want:   6 * time.Second,
+	}, {
+		name:   "exponential is capped",
+		policy: config.BackoffPolicy_EXPONENTIAL,
+		delay:  time.Second,
+		retry:  100,
+		want:   maxRetryBackoff,
+	}, {
+		name:   "linear is capped",
+		policy: config.BackoffPolicy_LINEAR,
+		delay:  time.Hour,
+		retry:  100,
+		want:   maxRetryBackoff,
+	}, {
+		name:   "no delay",
+		policy: config.BackoffPolicy_EXPONENTIAL,
+		retry:  3,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := &config.Target{
+				BackoffPolicy: tc.policy,
+				BackoffDelay:  durationpb.New(tc.delay),
+			}
+			if got := retryBackoff(target, tc.retry); got != tc.want {
+				t.Errorf("retryBackoff got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeliverRetryBackoff(t *testing.T) {
+	cases := []struct {
+		name             string
+		retryOnFailure   bool
+		policy           config.BackoffPolicy
+		attempts         int32
+		maxAttempts      int32
+		deadLetterTopic  string
+		wantRetryAttempt int32
+		wantBackoff      time.Duration
+		wantDeadLetter   bool
+	}{{
+		name:           "first failure is scheduled for retry",
+		retryOnFailure: true,
+		policy:         config.BackoffPolicy_EXPONENTIAL,
+		wantBackoff:    10 * time.Second,
+	}, {
+		name:             "exponential retry",
+		policy:           config.BackoffPolicy_EXPONENTIAL,
+		attempts:         2,
+		wantRetryAttempt: 3,
+		wantBackoff:      80 * time.Second,
+	}, {
+		name:             "linear retry",
+		policy:           config.BackoffPolicy_LINEAR,
+		attempts:         2,
+		wantRetryAttempt: 3,
+		wantBackoff:      40 * time.Second,
+	}, {
+		name:        "exhausted attempts without dead letter sink are dropped",
+		attempts:    2,
+		maxAttempts: 3,
+	}, {
+		name:            "exhausted attempts are sent to the dead letter topic",
+		attempts:        2,
+		maxAttempts:     3,
+		deadLetterTopic: "test-dead-letter-topic",
+		wantDeadLetter:  true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(&targetWithFailureHandler{
+				t:        t,
+				respCode: http.StatusInternalServerError,
+			})
+			defer targetSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			for _, topic := range []string{"test-retry-topic", "test-dead-letter-topic"} {
+				if _, err := c.CreateTopic(ctx, topic); err != nil {
+					t.Fatalf("failed to create test pubsub topc: %v", err)
+				}
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:           "ns",
+				Name:                "target",
+				CellTenantType:      config.CellTenantType_BROKER,
+				CellTenantName:      "broker",
+				Address:             targetSvr.URL,
+				MaxDeliveryAttempts: tc.maxAttempts,
+				BackoffPolicy:       tc.policy,
+				BackoffDelay:        durationpb.New(10 * time.Second),
+				DeadLetterTopic:     tc.deadLetterTopic,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				RetryOnFailure:     tc.retryOnFailure,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+			}
+
+			origin := newSampleEvent()
+			if tc.attempts > 0 {
+				eventutil.SetDeliveryAttempts(origin, tc.attempts)
+			}
+			start := time.Now()
+			if err := p.Process(ctx, origin); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+
+			msgs := srv.Messages()
+			if tc.wantBackoff == 0 && !tc.wantDeadLetter {
+				if len(msgs) != 0 {
+					t.Errorf("got %d published messages, want 0", len(msgs))
+				}
+				return
+			}
+			if len(msgs) != 1 {
+				t.Fatalf("got %d published messages, want 1", len(msgs))
+			}
+			attrs := msgs[0].Attributes
+			if tc.wantDeadLetter {
+				if got := attrs["ce-"+ErrorDestExtension]; got != targetSvr.URL {
+					t.Errorf("dead lettered event %s got=%q, want=%q", ErrorDestExtension, got, targetSvr.URL)
+				}
+				for _, ext := range []string{eventutil.AttemptsAttribute, eventutil.RetryTimeAttribute} {
+					if _, ok := attrs["ce-"+ext]; ok {
+						t.Errorf("dead lettered event has unexpected extension %q", ext)
+					}
+				}
+				return
+			}
+
+			if tc.wantRetryAttempt > 0 {
+				if got := attrs["ce-"+eventutil.AttemptsAttribute]; got != fmt.Sprint(tc.wantRetryAttempt) {
+					t.Errorf("retried event attempts got=%q, want=%d", got, tc.wantRetryAttempt)
+				}
+			}
+			retryAt, err := time.Parse(time.RFC3339Nano, attrs["ce-"+eventutil.RetryTimeAttribute])
+			if err != nil {
+				t.Fatalf("failed to parse retry time of the retried event: %v", err)
+			}
+			if min, max := start.Add(tc.wantBackoff), time.Now().Add(tc.wantBackoff); retryAt.Before(min.Truncate(time.Millisecond)) || retryAt.After(max) {
+				t.Errorf("retry time got=%v, want between %v and %v", retryAt, min, max)
+			}
+		})
+	}
+}
+
 type NoReplyHandler struct{}
 
 func (NoReplyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -872,3 +1335,360 @@ func newSampleEvent() *event.Event {
 	sampleEvent.SetTime(time.Now())
 	return &sampleEvent
 }
+
+// batchHandler records the batches of events sent to a subscriber.
+type batchHandler struct {
+	t        *testing.T
+	respCode int
+	batches  [][]event.Event
+}
+
+func (h *batchHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if got := req.Header.Get("Content-Type"); got != event.ApplicationCloudEventsBatchJSON {
+		h.t.Errorf("batch Content-Type got=%q, want=%q", got, event.ApplicationCloudEventsBatchJSON)
+	}
+	var batch []event.Event
+	if err := json.NewDecoder(req.Body).Decode(&batch); err != nil {
+		h.t.Errorf("Failed to decode batch: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.batches = append(h.batches, batch)
+	w.WriteHeader(h.respCode)
+}
+
+func TestDeliverBatch(t *testing.T) {
+	cases := []struct {
+		name           string
+		respCode       int
+		retryOnFailure bool
+		wantRetried    int
+		wantErr        bool
+	}{{
+		name:     "batch delivered",
+		respCode: http.StatusAccepted,
+	}, {
+		name:     "batch failure without retry",
+		respCode: http.StatusInternalServerError,
+		wantErr:  true,
+	}, {
+		name:           "batch failure enqueues events for retry",
+		respCode:       http.StatusInternalServerError,
+		retryOnFailure: true,
+		wantRetried:    2,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			handler := &batchHandler{t: t, respCode: tc.respCode}
+			targetSvr := httptest.NewServer(handler)
+			defer targetSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				BatchMaxEvents: 2,
+				BatchMaxDelay:  durationpb.New(time.Second),
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, err = r.AddTags(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				RetryOnFailure:     tc.retryOnFailure,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+			}
+
+			var batch []handlerctx.BatchEntry
+			for _, id := range []string{"1", "2"} {
+				e := newSampleEvent()
+				e.SetID(id)
+				eventutil.UpdateRemainingHops(ctx, e, 5)
+				batch = append(batch, handlerctx.BatchEntry{Ctx: ctx, Event: e})
+			}
+			err = p.Process(handlerctx.WithBatch(ctx, batch), batch[0].Event)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("processing got error=%v, want=%v", err, tc.wantErr)
+			}
+
+			if len(handler.batches) != 1 {
+				t.Fatalf("got %d batches, want 1", len(handler.batches))
+			}
+			var gotIDs []string
+			for _, e := range handler.batches[0] {
+				gotIDs = append(gotIDs, e.ID())
+				if _, ok := e.Extensions()[eventutil.HopsAttribute]; ok {
+					t.Errorf("batched event %s has the %s extension", e.ID(), eventutil.HopsAttribute)
+				}
+			}
+			if diff := cmp.Diff([]string{"1", "2"}, gotIDs); diff != "" {
+				t.Errorf("unexpected batched events (-want,+got): %s", diff)
+			}
+			if got := len(srv.Messages()); got != tc.wantRetried {
+				t.Errorf("got %d retried events, want %d", got, tc.wantRetried)
+			}
+			// Metrics count events rather than requests.
+			metricstest.CheckCountData(t, "event_count", map[string]string{
+				metricskey.LabelResponseCode:      strconv.Itoa(tc.respCode),
+				metricskey.LabelResponseCodeClass: fmt.Sprintf("%dxx", tc.respCode/100),
+				metricskey.PodName:                "pod",
+				metricskey.ContainerName:          "container",
+			}, 2)
+		})
+	}
+}
+
+// countingHandler counts the requests to a subscriber that always responds with respCode.
+type countingHandler struct {
+	respCode int
+	requests int32
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	atomic.AddInt32(&h.requests, 1)
+	w.WriteHeader(h.respCode)
+}
+
+func TestDeliverCircuitBreaker(t *testing.T) {
+	cases := []struct {
+		name           string
+		retryOnFailure bool
+		ordered        bool
+		wantErr        error
+		wantRetried    int
+		wantRetryTime  bool
+	}{{
+		name:           "fanout enqueues short-circuited events for retry",
+		retryOnFailure: true,
+		wantRetried:    3,
+	}, {
+		name:          "retry reschedules short-circuited events",
+		wantRetried:   1,
+		wantRetryTime: true,
+	}, {
+		name:           "ordered fanout fails short-circuited events",
+		retryOnFailure: true,
+		ordered:        true,
+		wantErr:        ErrCircuitOpen,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			handler := &countingHandler{respCode: http.StatusServiceUnavailable}
+			targetSvr := httptest.NewServer(handler)
+			defer targetSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			if tc.ordered {
+				broker.OrderingKeyAttribute = "subject"
+			}
+			target := &config.Target{
+				Id:             "target-id",
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.SetOrderingKeyAttribute(broker.OrderingKeyAttribute)
+				bm.UpsertTargets(target)
+			})
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, err = r.AddTags(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			breakers := circuitbreaker.NewRegistry(circuitbreaker.Settings{
+				FailureThreshold: 2,
+				OpenTimeout:      time.Hour,
+			}, nil)
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				RetryOnFailure:     tc.retryOnFailure,
+				DeliverRetryClient: deliverRetryClient,
+				StatsReporter:      r,
+				CircuitBreakers:    breakers,
+			}
+
+			// The first two failed deliveries open the circuit breaker.
+			for i := 0; i < 2; i++ {
+				e := newSampleEvent()
+				eventutil.UpdateRemainingHops(ctx, e, 5)
+				// Only the fanout of unordered brokers enqueues failed events for retry.
+				if err := p.Process(ctx, e); err != nil && tc.retryOnFailure && !tc.ordered {
+					t.Errorf("unexpected error from failed delivery: %v", err)
+				}
+			}
+			if got := breakers.State(target); got != circuitbreaker.Open {
+				t.Fatalf("circuit breaker state got=%v, want=%v", got, circuitbreaker.Open)
+			}
+
+			e := newSampleEvent()
+			eventutil.UpdateRemainingHops(ctx, e, 5)
+			if err := p.Process(ctx, e); !errors.Is(err, tc.wantErr) {
+				t.Errorf("processing short-circuited event got error=%v, want=%v", err, tc.wantErr)
+			}
+			if got := atomic.LoadInt32(&handler.requests); got != 2 {
+				t.Errorf("subscriber got %d requests, want 2", got)
+			}
+			msgs := srv.Messages()
+			if got := len(msgs); got != tc.wantRetried {
+				t.Errorf("got %d retried events, want %d", got, tc.wantRetried)
+			}
+			if tc.wantRetryTime {
+				last := msgs[len(msgs)-1]
+				if _, ok := last.Attributes["ce-"+eventutil.RetryTimeAttribute]; !ok {
+					t.Errorf("rescheduled event has no %s attribute", eventutil.RetryTimeAttribute)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliverTransformedEventFailure(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetSvr := httptest.NewServer(&countingHandler{respCode: http.StatusInternalServerError})
+	defer targetSvr.Close()
+
+	srv, c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+	if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+		t.Fatalf("failed to create test pubsub topc: %v", err)
+	}
+	ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+	if err != nil {
+		t.Fatalf("failed to create pubsub protocol: %v", err)
+	}
+	deliverRetryClient, err := ceclient.New(ps)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		Transform:      &config.Transform{Type: "transformed"},
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = r.AddTags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: deliverRetryClient,
+		StatsReporter:      r,
+	}
+
+	original := newSampleEvent()
+	transformed := original.Clone()
+	transformed.SetType("transformed")
+	if err := p.Process(handlerctx.WithOriginalEvent(ctx, original), &transformed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("got %d retried events, want 1", len(msgs))
+	}
+	if got := msgs[0].Attributes["ce-type"]; got != original.Type() {
+		t.Errorf("retried event type got=%q, want=%q", got, original.Type())
+	}
+}
@@ -35,6 +35,9 @@ import (
 type fanoutResult struct {
 	targetKey *config.TargetKey
 	err       error
+	// async is the result deferred by the processing of the target, e.g. while the event waits
+	// for its batch to be sent.
+	async *handlerctx.AsyncResult
 }
 
 // Processor fanouts an event based on the broker key in the context.
@@ -99,9 +102,12 @@ func (p *Processor) fanoutEvent(ctx context.Context, event *event.Event, tc <-ch
 				)
 			}
 			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			async := &handlerctx.AsyncResult{}
+			ctx = handlerctx.WithAsyncResult(ctx, async)
 			out <- &fanoutResult{
 				targetKey: target.Key(),
 				err:       p.Next().Process(ctx, event),
+				async:     async,
 			}
 		}
 	}()
@@ -116,14 +122,26 @@ func (p *Processor) mergeResults(ctx context.Context, resChs []<-chan *fanoutRes
 	var wg sync.WaitGroup
 	var errs, passes int32
 
+	tally := func(fr *fanoutResult, err error) {
+		if err != nil {
+			logging.FromContext(ctx).Error("error processing event for fanout target", zap.Stringer("target", fr.targetKey))
+			atomic.AddInt32(&errs, 1)
+		} else {
+			atomic.AddInt32(&passes, 1)
+		}
+	}
 	count := func(c <-chan *fanoutResult) {
 		for fr := range c {
-			if fr.err != nil {
-				logging.FromContext(ctx).Error("error processing event for fanout target", zap.Stringer("target", fr.targetKey))
-				atomic.AddInt32(&errs, 1)
-			} else {
-				atomic.AddInt32(&passes, 1)
+			if fr.err == nil && fr.async.Pending() {
+				// Wait for the deferred result without holding up the next targets.
+				wg.Add(1)
+				go func(fr *fanoutResult) {
+					tally(fr, fr.async.Wait())
+					wg.Done()
+				}(fr)
+				continue
 			}
+			tally(fr, fr.err)
 		}
 		wg.Done()
 	}
@@ -18,9 +18,11 @@ package fanout
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/cloudevents/sdk-go/v2/event"
@@ -155,6 +157,42 @@ func TestFanoutPartialFailure(t *testing.T) {
 	close(ch)
 }
 
+// deferFirstTarget defers the result of target-0 until target-1 was processed.
+type deferFirstTarget struct {
+	processors.BaseProcessor
+	released chan struct{}
+}
+
+func (p *deferFirstTarget) Process(ctx context.Context, e *event.Event) error {
+	tk, _ := handlerctx.GetTargetKey(ctx)
+	if strings.HasSuffix(tk.String(), "//target-1") {
+		close(p.released)
+		return nil
+	}
+	handlerctx.DeferResult(ctx, func() error {
+		<-p.released
+		return errors.New("deferred error")
+	})
+	return nil
+}
+
+func TestFanoutDeferredResult(t *testing.T) {
+	bk := config.TestOnlyBrokerKey("ns", "broker")
+	testTargets := newTestTargets(bk, 2)
+
+	// With a single worker, target-1 is only processed if target-0 doesn't block it.
+	p := &Processor{MaxConcurrency: 1, Targets: testTargets}
+	p.WithNext(&deferFirstTarget{released: make(chan struct{})})
+
+	e := event.New()
+	e.SetID("id")
+	ctx := handlerctx.WithBrokerKey(context.Background(), bk)
+	err := p.Process(ctx, &e)
+	if want := "event fanout passed 1 targets, failed 1 targets"; err == nil || err.Error() != want {
+		t.Errorf("Process error got=%v, want=%v", err, want)
+	}
+}
+
 func newTestTargets(key *config.CellTenantKey, num int) config.ReadonlyTargets {
 	targets := memory.NewEmptyTargets()
 	targets.MutateCellTenant(key, func(bm config.CellTenantMutation) {
@@ -0,0 +1,241 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// expression is a compiled filter expression evaluated against the attributes of an event.
+type expression func(ctx context.Context, attrs map[string]interface{}) bool
+
+// Matcher is the compiled form of all the filters of a target.
+type Matcher struct {
+	attrs map[string]string
+	exprs []expression
+}
+
+// Compile compiles the filter attributes and the advanced filters of the target into a Matcher.
+func Compile(target *config.Target) (*Matcher, error) {
+	m := &Matcher{attrs: target.FilterAttributes}
+	for i, f := range target.Filters {
+		e, err := compileFilter(f)
+		if err != nil {
+			return nil, fmt.Errorf("filters[%d]: %w", i, err)
+		}
+		m.exprs = append(m.exprs, e)
+	}
+	return m, nil
+}
+
+// Match returns true if the event passes all the compiled filters.
+func (m *Matcher) Match(ctx context.Context, event *event.Event) bool {
+	if len(m.attrs) == 0 && len(m.exprs) == 0 {
+		return true
+	}
+	ce := eventAttributes(event)
+	if !passAttributes(ctx, m.attrs, ce) {
+		return false
+	}
+	for _, e := range m.exprs {
+		if !e(ctx, ce) {
+			return false
+		}
+	}
+	return true
+}
+
+func compileFilter(f *config.Filter) (expression, error) {
+	switch e := f.GetExpression().(type) {
+	case *config.Filter_Exact:
+		return compileAttributes(e.Exact, func(v, want string) bool { return v == want })
+	case *config.Filter_Prefix:
+		return compileAttributes(e.Prefix, strings.HasPrefix)
+	case *config.Filter_Suffix:
+		return compileAttributes(e.Suffix, strings.HasSuffix)
+	case *config.Filter_AnyOf:
+		return compileAnyOf(e.AnyOf)
+	case *config.Filter_All:
+		exprs, err := compileFilters(e.All)
+		if err != nil {
+			return nil, fmt.Errorf("all: %w", err)
+		}
+		return func(ctx context.Context, attrs map[string]interface{}) bool {
+			for _, e := range exprs {
+				if !e(ctx, attrs) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case *config.Filter_Any:
+		exprs, err := compileFilters(e.Any)
+		if err != nil {
+			return nil, fmt.Errorf("any: %w", err)
+		}
+		return func(ctx context.Context, attrs map[string]interface{}) bool {
+			for _, e := range exprs {
+				if e(ctx, attrs) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case *config.Filter_Not:
+		if e.Not == nil {
+			return nil, errors.New("not: missing filter")
+		}
+		expr, err := compileFilter(e.Not)
+		if err != nil {
+			return nil, fmt.Errorf("not: %w", err)
+		}
+		return func(ctx context.Context, attrs map[string]interface{}) bool {
+			return !expr(ctx, attrs)
+		}, nil
+	default:
+		return nil, errors.New("filter has no expression")
+	}
+}
+
+func compileFilters(l *config.FilterList) ([]expression, error) {
+	if len(l.GetFilters()) == 0 {
+		return nil, errors.New("expected at least one filter")
+	}
+	exprs := make([]expression, 0, len(l.GetFilters()))
+	for i, f := range l.GetFilters() {
+		e, err := compileFilter(f)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		exprs = append(exprs, e)
+	}
+	return exprs, nil
+}
+
+func compileAttributes(f *config.AttributesFilter, match func(v, want string) bool) (expression, error) {
+	if len(f.GetAttributes()) == 0 {
+		return nil, errors.New("expected at least one attribute")
+	}
+	attrs := f.GetAttributes()
+	return func(ctx context.Context, ce map[string]interface{}) bool {
+		for k, want := range attrs {
+			v, ok := attributeString(ce, k)
+			if !ok || !match(v, want) {
+				trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match filter value %q", k, want)
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func compileAnyOf(f *config.AnyOfFilter) (expression, error) {
+	if f.GetAttribute() == "" {
+		return nil, errors.New("anyOf: missing attribute")
+	}
+	if len(f.GetValues()) == 0 {
+		return nil, errors.New("anyOf: expected at least one value")
+	}
+	attr := f.GetAttribute()
+	values := make(map[string]struct{}, len(f.GetValues()))
+	for _, v := range f.GetValues() {
+		values[v] = struct{}{}
+	}
+	return func(ctx context.Context, ce map[string]interface{}) bool {
+		v, ok := attributeString(ce, attr)
+		if !ok {
+			return false
+		}
+		_, ok = values[v]
+		return ok
+	}, nil
+}
+
+// attributeString returns the canonical string form of the attribute, if it exists in the event.
+func attributeString(ce map[string]interface{}, name string) (string, bool) {
+	v, ok := ce[name]
+	if !ok {
+		return "", false
+	}
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	s, err := types.Format(v)
+	if err != nil {
+		return fmt.Sprint(v), true
+	}
+	return s, true
+}
+
+// Cache caches the compiled Matcher of each target, so that filters are only compiled again when
+// the target changes in the targets config. The zero value is ready to use.
+type Cache struct {
+	m sync.Map
+}
+
+type cacheEntry struct {
+	target  *config.Target
+	matcher *Matcher
+}
+
+// matchNothing is used for targets whose filters fail to compile.
+var matchNothing = &Matcher{exprs: []expression{
+	func(context.Context, map[string]interface{}) bool { return false },
+}}
+
+// Get returns the compiled Matcher for the target. Every reload of the targets config produces
+// new Target objects, so an entry is compiled again whenever the cached Target is replaced. If the
+// filters fail to compile, the returned Matcher rejects all events.
+func (c *Cache) Get(ctx context.Context, target *config.Target) *Matcher {
+	key := *target.Key()
+	if v, ok := c.m.Load(key); ok {
+		if e := v.(*cacheEntry); e.target == target {
+			return e.matcher
+		}
+	}
+	m, err := Compile(target)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to compile target filters", zap.Stringer("target", &key), zap.Error(err))
+		m = matchNothing
+	}
+	c.m.Store(key, &cacheEntry{target: target, matcher: m})
+	return m
+}
+
+// Prune evicts the entries of the targets that were deleted or replaced in targets. It should be
+// called when the targets config is synced.
+func (c *Cache) Prune(targets config.ReadonlyTargets) {
+	c.m.Range(func(k, v interface{}) bool {
+		key := k.(config.TargetKey)
+		if t, ok := targets.GetTargetByKey(&key); !ok || t != v.(*cacheEntry).target {
+			c.m.Delete(key)
+		}
+		return true
+	})
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+func exactFilter(attrs map[string]string) *config.Filter {
+	return &config.Filter{Expression: &config.Filter_Exact{Exact: &config.AttributesFilter{Attributes: attrs}}}
+}
+
+func prefixFilter(attrs map[string]string) *config.Filter {
+	return &config.Filter{Expression: &config.Filter_Prefix{Prefix: &config.AttributesFilter{Attributes: attrs}}}
+}
+
+func suffixFilter(attrs map[string]string) *config.Filter {
+	return &config.Filter{Expression: &config.Filter_Suffix{Suffix: &config.AttributesFilter{Attributes: attrs}}}
+}
+
+func anyOfFilter(attr string, values ...string) *config.Filter {
+	return &config.Filter{Expression: &config.Filter_AnyOf{AnyOf: &config.AnyOfFilter{Attribute: attr, Values: values}}}
+}
+
+func allFilter(filters ...*config.Filter) *config.Filter {
+	return &config.Filter{Expression: &config.Filter_All{All: &config.FilterList{Filters: filters}}}
+}
+
+func anyFilter(filters ...*config.Filter) *config.Filter {
+	return &config.Filter{Expression: &config.Filter_Any{Any: &config.FilterList{Filters: filters}}}
+}
+
+func notFilter(f *config.Filter) *config.Filter {
+	return &config.Filter{Expression: &config.Filter_Not{Not: f}}
+}
+
+func TestMatcher(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("//storage.googleapis.com/projects/_/buckets/my-bucket")
+	e.SetType("google.cloud.storage.object.v1.finalized")
+	e.SetSubject("objects/image.png")
+	e.SetExtension("count", 3)
+
+	cases := []struct {
+		name       string
+		attrs      map[string]string
+		filters    []*config.Filter
+		shouldPass bool
+	}{{
+		name:       "no filters pass",
+		shouldPass: true,
+	}, {
+		name:       "exact pass",
+		filters:    []*config.Filter{exactFilter(map[string]string{"type": "google.cloud.storage.object.v1.finalized"})},
+		shouldPass: true,
+	}, {
+		name:    "exact not pass",
+		filters: []*config.Filter{exactFilter(map[string]string{"type": "google.cloud.storage.object.v1.deleted"})},
+	}, {
+		name:    "exact missing attribute not pass",
+		filters: []*config.Filter{exactFilter(map[string]string{"missing": ""})},
+	}, {
+		name:       "prefix pass",
+		filters:    []*config.Filter{prefixFilter(map[string]string{"type": "google.cloud.storage."})},
+		shouldPass: true,
+	}, {
+		name:    "prefix not pass",
+		filters: []*config.Filter{prefixFilter(map[string]string{"type": "google.cloud.pubsub."})},
+	}, {
+		name:       "suffix pass",
+		filters:    []*config.Filter{suffixFilter(map[string]string{"subject": ".png"})},
+		shouldPass: true,
+	}, {
+		name:    "suffix not pass",
+		filters: []*config.Filter{suffixFilter(map[string]string{"subject": ".jpg"})},
+	}, {
+		name:       "anyOf pass",
+		filters:    []*config.Filter{anyOfFilter("subject", "objects/image.jpg", "objects/image.png")},
+		shouldPass: true,
+	}, {
+		name:    "anyOf not pass",
+		filters: []*config.Filter{anyOfFilter("subject", "objects/image.jpg", "objects/image.gif")},
+	}, {
+		name:       "non-string extension pass",
+		filters:    []*config.Filter{exactFilter(map[string]string{"count": "3"})},
+		shouldPass: true,
+	}, {
+		name:       "not pass",
+		filters:    []*config.Filter{notFilter(suffixFilter(map[string]string{"subject": ".jpg"}))},
+		shouldPass: true,
+	}, {
+		name:    "not not pass",
+		filters: []*config.Filter{notFilter(suffixFilter(map[string]string{"subject": ".png"}))},
+	}, {
+		name: "all pass",
+		filters: []*config.Filter{allFilter(
+			prefixFilter(map[string]string{"type": "google.cloud.storage."}),
+			suffixFilter(map[string]string{"subject": ".png"}),
+		)},
+		shouldPass: true,
+	}, {
+		name: "all not pass",
+		filters: []*config.Filter{allFilter(
+			prefixFilter(map[string]string{"type": "google.cloud.storage."}),
+			suffixFilter(map[string]string{"subject": ".jpg"}),
+		)},
+	}, {
+		name: "any pass",
+		filters: []*config.Filter{anyFilter(
+			prefixFilter(map[string]string{"type": "google.cloud.pubsub."}),
+			suffixFilter(map[string]string{"subject": ".png"}),
+		)},
+		shouldPass: true,
+	}, {
+		name: "any not pass",
+		filters: []*config.Filter{anyFilter(
+			prefixFilter(map[string]string{"type": "google.cloud.pubsub."}),
+			suffixFilter(map[string]string{"subject": ".jpg"}),
+		)},
+	}, {
+		name:       "filter attributes and filters pass",
+		attrs:      map[string]string{"id": "id"},
+		filters:    []*config.Filter{suffixFilter(map[string]string{"subject": ".png"})},
+		shouldPass: true,
+	}, {
+		name:    "filter attributes not pass",
+		attrs:   map[string]string{"id": "other"},
+		filters: []*config.Filter{suffixFilter(map[string]string{"subject": ".png"})},
+	}, {
+		name: "multiple filters not pass",
+		filters: []*config.Filter{
+			suffixFilter(map[string]string{"subject": ".png"}),
+			exactFilter(map[string]string{"source": "other"}),
+		},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := Compile(&config.Target{FilterAttributes: tc.attrs, Filters: tc.filters})
+			if err != nil {
+				t.Fatalf("unexpected error compiling filters: %v", err)
+			}
+			if got := m.Match(context.Background(), &e); got != tc.shouldPass {
+				t.Errorf("Match got=%v, want=%v", got, tc.shouldPass)
+			}
+		})
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	cases := []struct {
+		name    string
+		filters []*config.Filter
+	}{{
+		name:    "empty filter",
+		filters: []*config.Filter{{}},
+	}, {
+		name:    "empty exact",
+		filters: []*config.Filter{exactFilter(nil)},
+	}, {
+		name:    "empty all",
+		filters: []*config.Filter{allFilter()},
+	}, {
+		name:    "empty anyOf values",
+		filters: []*config.Filter{anyOfFilter("type")},
+	}, {
+		name:    "nested empty filter",
+		filters: []*config.Filter{anyFilter(exactFilter(map[string]string{"type": "foo"}), notFilter(&config.Filter{}))},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Compile(&config.Target{Filters: tc.filters}); err == nil {
+				t.Error("expected error compiling filters, got nil")
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	e := event.New()
+	e.SetType("foo.bar")
+
+	var c Cache
+	target := &config.Target{
+		Name:           "target",
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		Filters:        []*config.Filter{prefixFilter(map[string]string{"type": "foo."})},
+	}
+	m := c.Get(ctx, target)
+	if !m.Match(ctx, &e) {
+		t.Error("expected event to match")
+	}
+	if got := c.Get(ctx, target); got != m {
+		t.Error("expected compiled filters to be cached")
+	}
+
+	// A new Target with the same key replaces the cached filters.
+	updated := &config.Target{
+		Name:           "target",
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		Filters:        []*config.Filter{prefixFilter(map[string]string{"type": "bar."})},
+	}
+	if c.Get(ctx, updated).Match(ctx, &e) {
+		t.Error("expected event not to match updated filters")
+	}
+
+	// Filters that fail to compile match nothing.
+	invalid := &config.Target{
+		Name:           "target",
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		Filters:        []*config.Filter{{}},
+	}
+	if c.Get(ctx, invalid).Match(ctx, &e) {
+		t.Error("expected event not to match invalid filters")
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	ctx := context.Background()
+	targets := memory.NewEmptyTargets()
+	broker := config.TestOnlyBrokerKey("ns", "broker")
+	kept := &config.Target{Name: "kept", Filters: []*config.Filter{prefixFilter(map[string]string{"type": "foo."})}}
+	deleted := &config.Target{Name: "deleted", Filters: []*config.Filter{prefixFilter(map[string]string{"type": "bar."})}}
+	targets.MutateCellTenant(broker, func(m config.CellTenantMutation) {
+		m.UpsertTargets(kept, deleted)
+	})
+	key := func(name string) *config.TargetKey {
+		return (&config.Target{Namespace: "ns", CellTenantType: config.CellTenantType_BROKER, CellTenantName: "broker", Name: name}).Key()
+	}
+	get := func(name string) (*config.Target, bool) {
+		return targets.GetTargetByKey(key(name))
+	}
+	cached := func(c *Cache, name string) bool {
+		_, ok := c.m.Load(*key(name))
+		return ok
+	}
+
+	var c Cache
+	for _, name := range []string{"kept", "deleted"} {
+		target, _ := get(name)
+		c.Get(ctx, target)
+	}
+	c.Prune(targets)
+	if !cached(&c, "kept") || !cached(&c, "deleted") {
+		t.Fatal("Prune() evicted the filters of targets in the config")
+	}
+
+	targets.MutateCellTenant(broker, func(m config.CellTenantMutation) {
+		m.DeleteTargets(deleted)
+	})
+	// Reloading the config replaces the Target, which is compiled again.
+	target, _ := get("kept")
+	c.Get(ctx, target)
+	c.Prune(targets)
+	if cached(&c, "deleted") {
+		t.Error("the filters of the deleted target were not evicted")
+	}
+	if !cached(&c, "kept") {
+		t.Error("the filters of the target in the config were evicted")
+	}
+}
@@ -39,6 +39,12 @@ type Processor struct {
 
 	// Targets is the targets from config.
 	Targets config.ReadonlyTargets
+
+	// Filters caches the compiled filters of the targets. The pools share it between their
+	// handlers and prune it when they sync. If nil, the processor uses its own cache.
+	Filters *Cache
+
+	filters Cache
 }
 
 var _ processors.Interface = (*Processor)(nil)
@@ -64,11 +70,11 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 	ctx, span := startSpan(ctx, trigger, event)
 	defer span.End()
 
-	if target.FilterAttributes == nil {
-		return p.Next().Process(ctx, event)
+	filters := p.Filters
+	if filters == nil {
+		filters = &p.filters
 	}
-
-	if PassFilter(ctx, target.FilterAttributes, event) {
+	if filters.Get(ctx, target).Match(ctx, event) {
 		return p.Next().Process(ctx, event)
 	}
 	logging.FromContext(ctx).Debug("event does not pass filter for target", zap.Any("target", target))
@@ -96,6 +102,17 @@ func startSpan(ctx context.Context, trigger types.NamespacedName, event *event.E
 // PassFilter checks given event against attributes available in the attrs map to determine
 // if the event should pass or not.
 func PassFilter(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	return passAttributes(ctx, attrs, eventAttributes(event))
+}
+
+// PassTarget checks given event against all the filters of the target. The compiled filters are
+// looked up in, or added to, the given cache.
+func PassTarget(ctx context.Context, cache *Cache, target *config.Target, event *event.Event) bool {
+	return cache.Get(ctx, target).Match(ctx, event)
+}
+
+// eventAttributes returns the context attributes and extensions of the event keyed by name.
+func eventAttributes(event *event.Event) map[string]interface{} {
 	// Set standard context attributes. The attributes available may not be
 	// exactly the same as the attributes defined in the current version of the
 	// CloudEvents spec.
@@ -116,7 +133,10 @@ func PassFilter(ctx context.Context, attrs map[string]string, event *event.Event
 	for k, v := range ext {
 		ce[k] = v
 	}
+	return ce
+}
 
+func passAttributes(ctx context.Context, attrs map[string]string, ce map[string]interface{}) bool {
 	for k, v := range attrs {
 		var value interface{}
 		value, ok := ce[k]
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/knative-gcp/pkg/logging"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/queue"
+)
+
+// ErrLimitExceeded is returned when an event exceeds the rate limit or the maximum concurrency
+// of its target, and cannot be sent to the retry topic of the target.
+var ErrLimitExceeded = errors.New("target rate limit or maximum concurrency exceeded")
+
+// Processor enforces the rate limit and maximum concurrency of targets. Events within the limits
+// of their target are passed to the next processor. Events that exceed them are sent to the retry
+// topic of the target if RetryClient is set, or fail with ErrLimitExceeded otherwise, so that
+// they are redelivered later instead of overwhelming the subscriber.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// RetryClient is the cloudevents client to send events to the retry topic. If nil, events
+	// that exceed the limits of their target fail instead.
+	RetryClient ceclient.Client
+
+	// RetryDelay is the delay before the events sent to the retry topic are redelivered. It is
+	// set by the retry pool, whose events come from the retry topic, so that they are held
+	// rather than redelivered right away. The delivery attempts of the events are unchanged.
+	RetryDelay time.Duration
+
+	// Limiters holds the limiters of the targets. The pools share it between their handlers,
+	// if nil the processor uses its own.
+	Limiters *Limiters
+	limiters Limiters
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process passes the event to the next processor if it is within the limits of its target.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok || (target.RateLimit <= 0 && target.MaxConcurrency <= 0) {
+		return p.Next().Process(ctx, e)
+	}
+
+	limiters := p.Limiters
+	if limiters == nil {
+		limiters = &p.limiters
+	}
+	l := limiters.get(target)
+	if !l.acquire() {
+		return p.limitExceeded(ctx, target, e)
+	}
+	defer l.release()
+	return p.Next().Process(ctx, e)
+}
+
+// limitExceeded sends the event to the retry topic of the target, unless there is no retry client
+// or the target belongs to an ordered broker.
+func (p *Processor) limitExceeded(ctx context.Context, target *config.Target, e *event.Event) error {
+	logging.FromContext(ctx).Debug("target limit exceeded",
+		zap.String("target", target.Name),
+		zap.Float64("rateLimit", target.RateLimit),
+		zap.Int32("maxConcurrency", target.MaxConcurrency),
+	)
+	if p.RetryClient == nil || target.RetryQueue == nil || p.ordered(ctx) {
+		return ErrLimitExceeded
+	}
+	trace.FromContext(ctx).Annotate(nil, "target limit exceeded: enqueueing for retry")
+	if p.RetryDelay > 0 {
+		retryEvent := e.Clone()
+		eventutil.SetRetryTime(&retryEvent, time.Now().Add(p.RetryDelay))
+		e = &retryEvent
+	}
+	pctx := queue.WithProject(cecontext.WithTopic(ctx, target.RetryQueue.Topic), target.RetryQueue.ProjectId)
+	if err := p.RetryClient.Send(pctx, *e); err != nil {
+		return fmt.Errorf("failed to send event to retry topic: %w", err)
+	}
+	return nil
+}
+
+// ordered returns whether the broker in the context delivers events in order, in which case
+// events must not be enqueued for retry, as later events would overtake them.
+func (p *Processor) ordered(ctx context.Context) bool {
+	bk, err := handlerctx.GetBrokerKey(ctx)
+	if err != nil {
+		return false
+	}
+	broker, ok := p.Targets.GetCellTenantByKey(bk)
+	return ok && broker.OrderingKeyAttribute != ""
+}
+
+// Limiters holds the limiter of each target. Limiters are kept across reloads of the targets
+// config, and only replaced when the limits of the target change.
+type Limiters struct {
+	// m holds the *limiter of each target key.
+	m sync.Map
+}
+
+// get returns the limiter of the target.
+func (ls *Limiters) get(target *config.Target) *limiter {
+	key := *target.Key()
+	if v, ok := ls.m.Load(key); ok {
+		if l := v.(*limiter); l.rateLimit == target.RateLimit && l.maxConcurrency == target.MaxConcurrency {
+			return l
+		}
+	}
+	l := newLimiter(target.RateLimit, target.MaxConcurrency)
+	ls.m.Store(key, l)
+	return l
+}
+
+// Prune removes the limiters of the targets that are no longer in the config, or no longer
+// limited.
+func (ls *Limiters) Prune(targets config.ReadonlyTargets) {
+	ls.m.Range(func(k, _ interface{}) bool {
+		key := k.(config.TargetKey)
+		if t, ok := targets.GetTargetByKey(&key); !ok || (t.RateLimit <= 0 && t.MaxConcurrency <= 0) {
+			ls.m.Delete(key)
+		}
+		return true
+	})
+}
+
+// limiter is a token bucket limiting the rate of events, along with a semaphore limiting the
+// number of events in flight.
+type limiter struct {
+	rateLimit      float64
+	maxConcurrency int32
+
+	// tokens is nil if the rate is not limited.
+	tokens *rate.Limiter
+	// inflight is nil if the concurrency is not limited.
+	inflight chan struct{}
+}
+
+func newLimiter(rateLimit float64, maxConcurrency int32) *limiter {
+	l := &limiter{rateLimit: rateLimit, maxConcurrency: maxConcurrency}
+	if rateLimit > 0 {
+		// Allow bursts of up to one second worth of events.
+		burst := int(math.Ceil(rateLimit))
+		if burst < 1 {
+			burst = 1
+		}
+		l.tokens = rate.NewLimiter(rate.Limit(rateLimit), burst)
+	}
+	if maxConcurrency > 0 {
+		l.inflight = make(chan struct{}, maxConcurrency)
+	}
+	return l
+}
+
+// acquire returns whether an event can be delivered without exceeding the limits. If so, release
+// must be called once the delivery is done.
+func (l *limiter) acquire() bool {
+	if l.inflight != nil {
+		select {
+		case l.inflight <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	if l.tokens != nil && !l.tokens.Allow() {
+		l.release()
+		return false
+	}
+	return true
+}
+
+func (l *limiter) release() {
+	if l.inflight != nil {
+		<-l.inflight
+	}
+}
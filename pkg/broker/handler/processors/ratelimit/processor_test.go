@@ -0,0 +1,250 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+// fakeRetryClient records the topics events are sent to.
+type fakeRetryClient struct {
+	ceclient.Client
+
+	mux    sync.Mutex
+	topics []string
+	events []event.Event
+}
+
+func (c *fakeRetryClient) Send(ctx context.Context, e event.Event) protocol.Result {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.topics = append(c.topics, cecontext.TopicFrom(ctx))
+	c.events = append(c.events, e)
+	return nil
+}
+
+// blockingProcessor blocks the first event until unblock is closed.
+type blockingProcessor struct {
+	processors.BaseProcessor
+	started chan struct{}
+	unblock chan struct{}
+	once    sync.Once
+}
+
+func (p *blockingProcessor) Process(_ context.Context, _ *event.Event) error {
+	block := false
+	p.once.Do(func() {
+		block = true
+		close(p.started)
+	})
+	if block {
+		<-p.unblock
+	}
+	return nil
+}
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrTargetKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrTargetKeyNotPresent)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	cases := []struct {
+		name        string
+		rateLimit   float64
+		withRetry   bool
+		ordered     bool
+		wantErrs    []error
+		wantRetried int
+	}{{
+		name:     "no limit",
+		wantErrs: []error{nil, nil, nil},
+	}, {
+		name:      "events above the rate fail",
+		rateLimit: 2,
+		wantErrs:  []error{nil, nil, ErrLimitExceeded},
+	}, {
+		name:        "events above the rate are enqueued for retry",
+		rateLimit:   1,
+		withRetry:   true,
+		wantErrs:    []error{nil, nil, nil},
+		wantRetried: 2,
+	}, {
+		name:      "events of ordered brokers are not enqueued for retry",
+		rateLimit: 1,
+		withRetry: true,
+		ordered:   true,
+		wantErrs:  []error{nil, ErrLimitExceeded, ErrLimitExceeded},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, testTargets := newTestTargets(tc.rateLimit, 0, tc.ordered)
+			retryClient := &fakeRetryClient{}
+			p := &Processor{Targets: testTargets}
+			if tc.withRetry {
+				p.RetryClient = retryClient
+			}
+			for i, wantErr := range tc.wantErrs {
+				e := event.New()
+				if err := p.Process(ctx, &e); err != wantErr {
+					t.Errorf("Process event %d error got=%v, want=%v", i, err, wantErr)
+				}
+			}
+			if got := len(retryClient.topics); got != tc.wantRetried {
+				t.Errorf("got %d retried events, want %d", got, tc.wantRetried)
+			}
+			for _, topic := range retryClient.topics {
+				if topic != "retry-topic" {
+					t.Errorf("retried event topic got=%q, want=%q", topic, "retry-topic")
+				}
+			}
+		})
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	ctx, testTargets := newTestTargets(0, 1, false)
+	next := &blockingProcessor{started: make(chan struct{}), unblock: make(chan struct{})}
+	p := &Processor{Targets: testTargets}
+	p.WithNext(next)
+
+	errs := make(chan error)
+	go func() {
+		e := event.New()
+		errs <- p.Process(ctx, &e)
+	}()
+	<-next.started
+
+	e := event.New()
+	if err := p.Process(ctx, &e); err != ErrLimitExceeded {
+		t.Errorf("Process error while an event is in flight got=%v, want=%v", err, ErrLimitExceeded)
+	}
+	close(next.unblock)
+	if err := <-errs; err != nil {
+		t.Errorf("Process error of the first event got=%v, want=nil", err)
+	}
+	if err := p.Process(ctx, &e); err != nil {
+		t.Errorf("Process error once no event is in flight got=%v, want=nil", err)
+	}
+}
+
+func TestLimiterUpdatedWithTarget(t *testing.T) {
+	var ls Limiters
+	target := &config.Target{Name: "target", CellTenantName: "broker", Namespace: "ns", RateLimit: 1}
+	l := ls.get(target)
+	if got := ls.get(target); got != l {
+		t.Error("limiter was replaced although the target limits did not change")
+	}
+	updated := &config.Target{Name: "target", CellTenantName: "broker", Namespace: "ns", RateLimit: 2}
+	if got := ls.get(updated); got == l {
+		t.Error("limiter was not replaced after the target limits changed")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	ctx, testTargets := newTestTargets(1, 0, false)
+	retryClient := &fakeRetryClient{}
+	p := &Processor{Targets: testTargets, RetryClient: retryClient, RetryDelay: time.Minute}
+
+	e := event.New()
+	e.SetID("id")
+	eventutil.SetDeliveryAttempts(&e, 3)
+	for i := 0; i < 2; i++ {
+		if err := p.Process(ctx, &e); err != nil {
+			t.Fatalf("Process event %d error got=%v, want=nil", i, err)
+		}
+	}
+	if len(retryClient.events) != 1 {
+		t.Fatalf("got %d retried events, want 1", len(retryClient.events))
+	}
+	retried := retryClient.events[0]
+	if retryTime, ok := eventutil.GetRetryTime(ctx, &retried); !ok || time.Until(retryTime) < 50*time.Second {
+		t.Errorf("retried event retry time got=%v, want about a minute from now", retryTime)
+	}
+	if got := eventutil.GetDeliveryAttempts(ctx, &retried); got != 3 {
+		t.Errorf("retried event delivery attempts got=%d, want=3", got)
+	}
+	if _, ok := eventutil.GetRetryTime(ctx, &e); ok {
+		t.Error("retry time was set on the processed event")
+	}
+}
+
+func TestLimitersPrune(t *testing.T) {
+	_, testTargets := newTestTargets(1, 0, false)
+	var ls Limiters
+	var target *config.Target
+	testTargets.RangeAllTargets(func(t *config.Target) bool {
+		target = t
+		return false
+	})
+	l := ls.get(target)
+	ls.Prune(testTargets)
+	if got := ls.get(target); got != l {
+		t.Error("limiter of an existing target was pruned")
+	}
+
+	testTargets.MutateCellTenant(target.Key().ParentKey(), func(bm config.CellTenantMutation) {
+		bm.DeleteTargets(target)
+	})
+	ls.Prune(testTargets)
+	if _, ok := ls.m.Load(*target.Key()); ok {
+		t.Error("limiter of a deleted target was not pruned")
+	}
+}
+
+func newTestTargets(rateLimit float64, maxConcurrency int32, ordered bool) (context.Context, config.Targets) {
+	testTarget := &config.Target{
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		RateLimit:      rateLimit,
+		MaxConcurrency: maxConcurrency,
+		RetryQueue: &config.Queue{
+			Topic: "retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(testTarget.Key().ParentKey(), func(bm config.CellTenantMutation) {
+		if ordered {
+			bm.SetOrderingKeyAttribute("subject")
+		}
+		bm.UpsertTargets(testTarget)
+	})
+	ctx := handlerctx.WithBrokerKey(context.Background(), testTarget.Key().ParentKey())
+	ctx = handlerctx.WithTargetKey(ctx, testTarget.Key())
+	return ctx, testTargets
+}
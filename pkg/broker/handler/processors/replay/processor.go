@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/knative-gcp/pkg/logging"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/replay"
+)
+
+// Processor passes the events received from the temporary subscription of a replay to the next
+// processor if they were accepted by the ingress within the time window of the replay of their
+// target. Other events are acked without being delivered, as the subscription is only used by
+// this replay.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// Tracker records the progress of the replays.
+	Tracker *replay.Tracker
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process passes the event to the next processor if it is within the replay window of its target.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok || target.Replay == nil {
+		// If the target or its replay no longer exists, then there is nothing to process.
+		logging.FromContext(ctx).Debug("replay no longer exist in the config", zap.Stringer("target", tk))
+		return nil
+	}
+	r := target.Replay
+
+	arrival, ok := eventutil.GetArrivalTime(ctx, e)
+	if !ok {
+		// Events without an arrival time cannot be placed in the window. Replay them, as the
+		// subscription was seeked to the start of the window.
+		logging.FromContext(ctx).Debug("replayed event has no arrival time", zap.String("event.id", e.ID()))
+	} else if arrival.Before(r.StartTime.AsTime()) {
+		p.Tracker.Skip(r.Id)
+		return nil
+	} else if !arrival.Before(r.EndTime.AsTime()) {
+		// The event was accepted after the replay was started, and was delivered to the target
+		// from the decouple queue already.
+		return nil
+	}
+
+	trace.FromContext(ctx).Annotate(ceclient.EventTraceAttributes(e), "replaying event")
+	if err := p.Next().Process(ctx, e); err != nil {
+		return err
+	}
+	p.Tracker.Record(r.Id, arrival)
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/replay"
+)
+
+// recordingProcessor records the IDs of the events it processes, and fails if err is set.
+type recordingProcessor struct {
+	processors.BaseProcessor
+	ids []string
+	err error
+}
+
+func (p *recordingProcessor) Process(_ context.Context, e *event.Event) error {
+	p.ids = append(p.ids, e.ID())
+	return p.err
+}
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrTargetKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrTargetKeyNotPresent)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	testTarget := &config.Target{
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		Replay: &config.Replay{
+			Id:        "replay-uid",
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(end),
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(testTarget.Key().ParentKey(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(testTarget)
+	})
+	ctx := handlerctx.WithTargetKey(context.Background(), testTarget.Key())
+
+	next := &recordingProcessor{}
+	tracker := replay.NewTracker(time.Hour)
+	p := &Processor{Targets: testTargets, Tracker: tracker}
+	p.WithNext(next)
+
+	newEvent := func(id string, arrival time.Time) *event.Event {
+		e := event.New()
+		e.SetID(id)
+		e.SetType("type")
+		e.SetSource("source")
+		if !arrival.IsZero() {
+			e.SetExtension(eventutil.ArrivalTimeAttribute, cetypes.Timestamp{Time: arrival})
+		}
+		return &e
+	}
+	for _, e := range []*event.Event{
+		newEvent("before", start.Add(-time.Minute)),
+		newEvent("first", start),
+		newEvent("last", end.Add(-time.Second)),
+		newEvent("after", end),
+		newEvent("no-arrival-time", time.Time{}),
+	} {
+		if err := p.Process(ctx, e); err != nil {
+			t.Fatalf("Process(%s) failed: %v", e.ID(), err)
+		}
+	}
+	if diff := cmp.Diff([]string{"first", "last", "no-arrival-time"}, next.ids); diff != "" {
+		t.Errorf("unexpected replayed events (-want,+got): %s", diff)
+	}
+
+	next.err = errors.New("delivery failed")
+	if err := p.Process(ctx, newEvent("failed", start)); err != next.err {
+		t.Errorf("Process error got=%v, want=%v", err, next.err)
+	}
+
+	replayedThrough := end.Add(-time.Second)
+	if diff := cmp.Diff(map[string]replay.Progress{
+		"replay-uid": {ReplayedThrough: &replayedThrough, Events: 3},
+	}, tracker.Progress()); diff != "" {
+		t.Errorf("unexpected progress (-want,+got): %s", diff)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/knative-gcp/pkg/logging"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+// Processor transforms events according to the transform of their target before passing them to
+// the next processor. The event as it was before the transformation is kept in the context, see
+// handlerctx.GetOriginalEvent, so that it is the one sent to the retry topic or the dead letter
+// sink if the delivery fails.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// ClaimCheck if set, rehydrates the data that the ingress offloaded to Cloud Storage before
+	// the data of events is transformed.
+	ClaimCheck *claimcheck.Store
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process transforms the event if its target has a transform, and passes it to the next processor.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok || target.Transform == nil {
+		return p.Next().Process(ctx, e)
+	}
+
+	in, err := p.rehydrate(ctx, target, e)
+	if err != nil {
+		return err
+	}
+	transformed, err := apply(target.Transform, in)
+	if err != nil {
+		// The transform of an event fails the same way every time, so redelivering the event
+		// would not help. Drop it for this target instead.
+		logging.FromContext(ctx).Warn("failed to transform event, dropping it",
+			zap.String("target", target.Name), zap.String("event.id", e.ID()), zap.Error(err))
+		trace.FromContext(ctx).Annotate(
+			ceclient.EventTraceAttributes(e),
+			"event dropped: transform failed",
+		)
+		return nil
+	}
+	return p.Next().Process(handlerctx.WithOriginalEvent(ctx, e), transformed)
+}
+
+// rehydrate returns the event with the data offloaded to Cloud Storage by the ingress if the
+// transform of the target modifies the data, or the event itself otherwise.
+func (p *Processor) rehydrate(ctx context.Context, target *config.Target, e *event.Event) (*event.Event, error) {
+	if p.ClaimCheck == nil || target.Transform.Data == nil {
+		return e, nil
+	}
+	bk, err := handlerctx.GetBrokerKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	broker, ok := p.Targets.GetCellTenantByKey(bk)
+	if !ok {
+		// The deliver processor drops the events of brokers that no longer exist.
+		return e, nil
+	}
+	return p.ClaimCheck.Rehydrate(ctx, broker.Id, e)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	storagetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+)
+
+// recordingProcessor records the events it processes, and the original events in their context.
+type recordingProcessor struct {
+	processors.BaseProcessor
+	events    []*event.Event
+	originals []*event.Event
+}
+
+func (p *recordingProcessor) Process(ctx context.Context, e *event.Event) error {
+	p.events = append(p.events, e)
+	original, _ := handlerctx.GetOriginalEvent(ctx)
+	p.originals = append(p.originals, original)
+	return nil
+}
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrTargetKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrTargetKeyNotPresent)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	cases := []struct {
+		name         string
+		transform    *config.Transform
+		data         string
+		wantType     string
+		wantOriginal bool
+		wantDropped  bool
+	}{{
+		name:     "no transform",
+		wantType: "type",
+	}, {
+		name:         "transformed",
+		transform:    &config.Transform{Type: "transformed"},
+		wantType:     "transformed",
+		wantOriginal: true,
+	}, {
+		name:        "transform failure drops the event",
+		transform:   &config.Transform{Data: &config.DataTransform{Remove: []string{"/a"}}},
+		data:        `{"a":`,
+		wantDropped: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			testTarget := &config.Target{
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Namespace:      "ns",
+				Transform:      tc.transform,
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(testTarget.Key().ParentKey(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(testTarget)
+			})
+			ctx := handlerctx.WithTargetKey(context.Background(), testTarget.Key())
+
+			next := &recordingProcessor{}
+			p := &Processor{Targets: testTargets}
+			p.WithNext(next)
+
+			e := event.New()
+			e.SetID("id")
+			e.SetType("type")
+			e.SetSource("source")
+			if tc.data != "" {
+				if err := e.SetData(event.ApplicationJSON, []byte(tc.data)); err != nil {
+					t.Fatalf("failed to set data: %v", err)
+				}
+			}
+			if err := p.Process(ctx, &e); err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+
+			if tc.wantDropped {
+				if len(next.events) != 0 {
+					t.Errorf("expected the event to be dropped, got %v", next.events)
+				}
+				return
+			}
+			if len(next.events) != 1 {
+				t.Fatalf("got %d events, want 1", len(next.events))
+			}
+			if got := next.events[0].Type(); got != tc.wantType {
+				t.Errorf("event type got=%q, want=%q", got, tc.wantType)
+			}
+			if got := next.originals[0] == &e; got != tc.wantOriginal {
+				t.Errorf("original event in context got=%v, want=%v", got, tc.wantOriginal)
+			}
+			if e.Type() != "type" {
+				t.Errorf("the original event was modified: %v", e)
+			}
+		})
+	}
+}
+
+func TestProcessRehydratesOffloadedData(t *testing.T) {
+	testTarget := &config.Target{
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Namespace:      "ns",
+		Transform:      &config.Transform{Data: &config.DataTransform{Remove: []string{"/a"}}},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(testTarget.Key().ParentKey(), func(bm config.CellTenantMutation) {
+		bm.SetID("broker-uid").UpsertTargets(testTarget)
+	})
+	ctx := handlerctx.WithBrokerKey(context.Background(), testTarget.Key().ParentKey())
+	ctx = handlerctx.WithTargetKey(ctx, testTarget.Key())
+
+	objects := storagetesting.NewTestObjects()
+	objects.Put("claimcheck/broker-uid/id", storagetesting.TestObject{Data: []byte(`{"a":1,"b":2}`)})
+	client, err := storagetesting.TestClientCreator(storagetesting.TestClientData{
+		BucketData: storagetesting.TestBucketData{Objects: objects},
+	})(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := &recordingProcessor{}
+	p := &Processor{Targets: testTargets, ClaimCheck: claimcheck.NewStore(client, "bucket")}
+	p.WithNext(next)
+
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	e.SetDataContentType(event.ApplicationJSON)
+	e.SetExtension(claimcheck.Extension, "gs://bucket/claimcheck/broker-uid/id")
+	if err := p.Process(ctx, &e); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	if len(next.events) != 1 {
+		t.Fatalf("got %d events, want 1", len(next.events))
+	}
+	if got, want := string(next.events[0].Data()), `{"b":2}`; got != want {
+		t.Errorf("event data got=%q, want=%q", got, want)
+	}
+	if next.originals[0] != &e {
+		t.Errorf("the original event is not in the context")
+	}
+	if _, ok := e.Extensions()[claimcheck.Extension]; !ok {
+		t.Errorf("the original event was modified: %v", e)
+	}
+}
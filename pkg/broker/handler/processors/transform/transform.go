@@ -0,0 +1,203 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// apply returns a copy of the event transformed according to t. The data of the event is only
+// transformed if it is application/json.
+func apply(t *config.Transform, e *event.Event) (*event.Event, error) {
+	out := e.Clone()
+	if t.Type != "" {
+		out.SetType(t.Type)
+	}
+	if t.Source != "" {
+		out.SetSource(t.Source)
+	}
+	for _, name := range t.RemoveExtensions {
+		out.SetExtension(name, nil)
+	}
+	for name, value := range t.SetExtensions {
+		out.SetExtension(name, value)
+	}
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("transformed event is invalid: %w", err)
+	}
+	if t.Data != nil && out.DataMediaType() == event.ApplicationJSON && len(out.Data()) > 0 {
+		data, err := applyData(t.Data, out.Data())
+		if err != nil {
+			return nil, err
+		}
+		out.DataEncoded = data
+	}
+	return &out, nil
+}
+
+// applyData returns the JSON data transformed according to t.
+func applyData(t *config.DataTransform, data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode data: %w", err)
+	}
+	if len(t.Select) > 0 {
+		var selected interface{}
+		for _, p := range t.Select {
+			value, ok := getValue(doc, parsePointer(p))
+			if !ok {
+				continue
+			}
+			var err error
+			if selected, err = setValue(selected, parsePointer(p), value); err != nil {
+				return nil, fmt.Errorf("failed to select %q: %w", p, err)
+			}
+		}
+		doc = selected
+	}
+	for _, p := range t.Remove {
+		doc = removeValue(doc, parsePointer(p))
+	}
+	for p, raw := range t.Set {
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("failed to decode the value set at %q: %w", p, err)
+		}
+		var err error
+		if doc, err = setValue(doc, parsePointer(p), value); err != nil {
+			return nil, fmt.Errorf("failed to set %q: %w", p, err)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens. The empty
+// pointer has no tokens and refers to the whole document.
+func parsePointer(p string) []string {
+	if p == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// getValue returns the value referenced by the tokens in node, and whether it exists.
+func getValue(node interface{}, tokens []string) (interface{}, bool) {
+	if len(tokens) == 0 {
+		return node, true
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, false
+		}
+		return getValue(child, tokens[1:])
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(n))
+		if err != nil {
+			return nil, false
+		}
+		return getValue(n[i], tokens[1:])
+	default:
+		return nil, false
+	}
+}
+
+// setValue sets the value referenced by the tokens in node, creating the missing parent objects,
+// and returns the updated node. The "-" token appends the value to an array.
+func setValue(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	switch n := node.(type) {
+	case nil:
+		child, err := setValue(nil, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{tokens[0]: child}, nil
+	case map[string]interface{}:
+		child, err := setValue(n[tokens[0]], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = child
+		return n, nil
+	case []interface{}:
+		if tokens[0] == "-" && len(tokens) == 1 {
+			return append(n, value), nil
+		}
+		i, err := arrayIndex(tokens[0], len(n))
+		if err != nil {
+			return nil, err
+		}
+		child, err := setValue(n[i], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = child
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%q is not an object or array", tokens[0])
+	}
+}
+
+// removeValue removes the value referenced by the tokens from node, if it exists, and returns the
+// updated node.
+func removeValue(node interface{}, tokens []string) interface{} {
+	if len(tokens) == 0 {
+		return node
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			delete(n, tokens[0])
+		} else if child, ok := n[tokens[0]]; ok {
+			n[tokens[0]] = removeValue(child, tokens[1:])
+		}
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(n))
+		if err != nil {
+			return n
+		}
+		if len(tokens) == 1 {
+			return append(n[:i], n[i+1:]...)
+		}
+		n[i] = removeValue(n[i], tokens[1:])
+	}
+	return node
+}
+
+// arrayIndex parses the token as an index of an array of the given length.
+func arrayIndex(token string, length int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= length || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return i, nil
+}
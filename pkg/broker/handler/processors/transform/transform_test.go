@@ -0,0 +1,159 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestApply(t *testing.T) {
+	transform := &config.Transform{
+		Type:             "com.example.v2",
+		Source:           "transformed",
+		SetExtensions:    map[string]string{"team": "a"},
+		RemoveExtensions: []string{"secret"},
+	}
+	e := event.New()
+	e.SetID("id")
+	e.SetType("com.example.v1")
+	e.SetSource("original")
+	e.SetExtension("secret", "s")
+	e.SetExtension("kept", "k")
+
+	got, err := apply(transform, &e)
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	want := event.New()
+	want.SetID("id")
+	want.SetType("com.example.v2")
+	want.SetSource("transformed")
+	want.SetExtension("team", "a")
+	want.SetExtension("kept", "k")
+	if diff := cmp.Diff(want.String(), got.String()); diff != "" {
+		t.Errorf("unexpected transformed event (-want,+got): %s", diff)
+	}
+	if e.Type() != "com.example.v1" || e.Extensions()["secret"] != "s" {
+		t.Errorf("apply modified the original event: %v", e)
+	}
+}
+
+func TestApplyData(t *testing.T) {
+	cases := []struct {
+		name        string
+		transform   *config.DataTransform
+		contentType string
+		data        string
+		want        string
+		wantErr     bool
+	}{{
+		name:      "select",
+		transform: &config.DataTransform{Select: []string{"/user/name", "/items/1", "/missing"}},
+		data:      `{"user":{"name":"n","email":"e"},"items":["a","b"],"other":1}`,
+		want:      `{"items":{"1":"b"},"user":{"name":"n"}}`,
+	}, {
+		name:      "remove",
+		transform: &config.DataTransform{Remove: []string{"/user/email", "/items/0", "/missing/field", "/a~1b"}},
+		data:      `{"user":{"name":"n","email":"e"},"items":["a","b"],"a/b":1}`,
+		want:      `{"items":["b"],"user":{"name":"n"}}`,
+	}, {
+		name: "set",
+		transform: &config.DataTransform{Set: map[string]string{
+			"/version":   `2`,
+			"/meta/tags": `["x"]`,
+			"/items/-":   `"c"`,
+			"/user/name": `null`,
+			"/a~0b":      `true`,
+		}},
+		data: `{"user":{"name":"n"},"items":["a","b"]}`,
+		want: `{"a~b":true,"items":["a","b","c"],"meta":{"tags":["x"]},"user":{"name":null},"version":2}`,
+	}, {
+		name:      "select then remove then set",
+		transform: &config.DataTransform{Select: []string{"/user"}, Remove: []string{"/user/email"}, Set: map[string]string{"/user/verified": `true`}},
+		data:      `{"user":{"name":"n","email":"e"},"other":1}`,
+		want:      `{"user":{"name":"n","verified":true}}`,
+	}, {
+		name:        "data of other content types is not transformed",
+		transform:   &config.DataTransform{Remove: []string{"/user"}},
+		contentType: "text/plain",
+		data:        `{"user":"n"}`,
+		want:        `{"user":"n"}`,
+	}, {
+		name:      "invalid json",
+		transform: &config.DataTransform{Remove: []string{"/user"}},
+		data:      `{"user":`,
+		wantErr:   true,
+	}, {
+		name:      "set in a scalar",
+		transform: &config.DataTransform{Set: map[string]string{"/user/name": `"n"`}},
+		data:      `{"user":"u"}`,
+		wantErr:   true,
+	}, {
+		name:      "set out of array bounds",
+		transform: &config.DataTransform{Set: map[string]string{"/items/5": `"n"`}},
+		data:      `{"items":[]}`,
+		wantErr:   true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = event.ApplicationJSON
+			}
+			e := event.New()
+			e.SetID("id")
+			e.SetType("type")
+			e.SetSource("source")
+			if err := e.SetData(contentType, []byte(tc.data)); err != nil {
+				t.Fatalf("failed to set data: %v", err)
+			}
+			got, err := apply(&config.Transform{Data: tc.transform}, &e)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("apply got error=%v, want=%v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, string(got.Data())); diff != "" {
+				t.Errorf("unexpected data (-want,+got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	cases := map[string][]string{
+		"":       nil,
+		"/":      {""},
+		"/a/b":   {"a", "b"},
+		"/a~1b":  {"a/b"},
+		"/a~0b":  {"a~b"},
+		"/a~01":  {"a~1"},
+		"/0/-/c": {"0", "-", "c"},
+	}
+	for p, want := range cases {
+		if diff := cmp.Diff(want, parsePointer(p)); diff != "" {
+			t.Errorf("parsePointer(%q) unexpected tokens (-want,+got): %s", p, diff)
+		}
+	}
+}
@@ -17,14 +17,11 @@ limitations under the License.
 package handler
 
 import (
-	"context"
 	"net/http"
 	"time"
 
-	"cloud.google.com/go/pubsub"
-	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	ceclient "github.com/cloudevents/sdk-go/v2/client"
-	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/wire"
 	"go.opencensus.io/plugin/ochttp"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
@@ -55,7 +52,7 @@ var (
 	ProviderSet = wire.NewSet(
 		NewFanoutPool,
 		NewRetryPool,
-		clients.NewPubsubClient,
+		queue.NewClient,
 		NewRetryClient,
 		wire.Value(DefaultHTTPClient),
 		wire.Value(DefaultCEClientOpts),
@@ -64,12 +61,7 @@ var (
 
 type RetryClient ceclient.Client
 
-// NewRetryClient provides a retry CE client from a PubSub client and list of CE client options.
-func NewRetryClient(ctx context.Context, client *pubsub.Client, opts ...ceclient.Option) (RetryClient, error) {
-	rps, err := cepubsub.New(ctx, cepubsub.WithClient(client))
-	if err != nil {
-		return nil, err
-	}
-
-	return ceclient.NewObserved(rps, opts...)
+// NewRetryClient provides a retry CE client from a queue client and list of CE client options.
+func NewRetryClient(client queue.Client, opts ...ceclient.Option) (RetryClient, error) {
+	return ceclient.NewObserved(queue.NewSender(client), opts...)
 }
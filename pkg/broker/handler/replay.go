@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/logging"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/ratelimit"
+	replayprocessor "github.com/google/knative-gcp/pkg/broker/handler/processors/replay"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/transform"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// replayIdleTimeout is how long the handler of a replay receives no event to replay before the
+// replay is reported done by the pod.
+const replayIdleTimeout = time.Minute
+
+type replayHandlerCache struct {
+	Handler
+	r *config.Replay
+}
+
+// If the replay of the target has changed, we need to renew the handler.
+func (hc *replayHandlerCache) shouldRenew(r *config.Replay) bool {
+	return !hc.IsAlive() || r.Id != hc.r.Id || r.Subscription != hc.r.Subscription
+}
+
+// syncReplays starts a handler for each replay in the targets config, pulling the retained events
+// from the temporary subscription of the replay. It stops the handlers of the replays that are no
+// longer in the config.
+func (p *RetryPool) syncReplays(ctx context.Context) {
+	for key, hc := range p.replays {
+		key := key
+		if t, ok := p.targets.GetTargetByKey(&key); !ok || t.Replay == nil {
+			hc.Stop()
+			delete(p.replays, key)
+		}
+	}
+
+	ids := make(map[string]bool)
+	p.targets.RangeAllTargets(func(t *config.Target) bool {
+		if t.Replay == nil {
+			return true
+		}
+		ids[t.Replay.Id] = true
+		if hc, ok := p.replays[*t.Key()]; ok {
+			if !hc.shouldRenew(t.Replay) {
+				return true
+			}
+			hc.Stop()
+			delete(p.replays, *t.Key())
+		}
+		if t.State != config.State_READY {
+			return true
+		}
+
+		// The temporary subscription lives in the project of the Broker, like the retry queue.
+		client, err := p.queueClient.InProject(t.RetryQueue.GetProjectId())
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to create the queue client of the trigger project", zap.Stringer("trigger", t.Key()), zap.String("project", t.RetryQueue.GetProjectId()), zap.Error(err))
+			return true
+		}
+		sub := client.Subscription(t.Replay.Subscription, p.options.PubsubReceiveSettings)
+		h := NewHandler(
+			sub,
+			processors.ChainProcessors(
+				&replayprocessor.Processor{Targets: p.targets, Tracker: p.replayTracker},
+				&filter.Processor{Targets: p.targets, Filters: &p.filters},
+				// Replayed events that exceed the limits of the target are redelivered by the
+				// retention subscription, so that the replay slows down instead of flooding the
+				// retry topic.
+				&ratelimit.Processor{Targets: p.targets, Limiters: &p.limiters},
+				&transform.Processor{Targets: p.targets, ClaimCheck: p.options.ClaimCheck},
+				// Replayed events that fail to be delivered are handed over to the retry topic
+				// of the target, like events from the decouple queue.
+				&deliver.Processor{
+					DeliverClient:      p.deliverClient,
+					Targets:            p.targets,
+					RetryOnFailure:     true,
+					DeliverRetryClient: p.deliverRetryClient,
+					StatsReporter:      p.statsReporter,
+					CircuitBreakers:    p.circuitBreakers,
+					ClaimCheck:         p.options.ClaimCheck,
+				},
+			),
+			p.options.TimeoutPerEvent,
+		)
+		hc := &replayHandlerCache{
+			Handler: *h,
+			r:       t.Replay,
+		}
+
+		ctx, err := metrics.AddTargetTags(ctx, t)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to add target tags to context", zap.Error(err))
+		}
+		ctx = handlerctx.WithBrokerKey(ctx, t.Key().ParentKey())
+		ctx = handlerctx.WithTargetKey(ctx, t.Key())
+		p.replayTracker.Start(t.Replay.Id)
+		hc.Start(ctx, func(err error) {
+			if err != nil {
+				logging.FromContext(ctx).Error("replay handler for trigger has stopped with error", zap.Stringer("trigger", t.Key()), zap.Error(err))
+			} else {
+				logging.FromContext(ctx).Info("replay handler for trigger has stopped", zap.Stringer("trigger", t.Key()))
+			}
+		})
+		p.replays[*t.Key()] = hc
+		return true
+	})
+	p.replayTracker.Forget(ids)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/broker/queue"
+	memoryqueue "github.com/google/knative-gcp/pkg/broker/queue/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+// TestRetryPoolReplay replays the events of an in-memory retention subscription to a target, and
+// checks that only the events within the replay window are delivered.
+func TestRetryPoolReplay(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan string, 10)
+	subscriber := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+		if err != nil {
+			t.Errorf("subscriber received an invalid event: %v", err)
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		received <- e.ID()
+		w.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer subscriber.Close()
+
+	queueClient := memoryqueue.NewClient()
+	queueClient.CreateTopic("retry-topic")
+	queueClient.CreateTopic("decouple-topic")
+	for sub, topic := range map[string]string{"retry-sub": "retry-topic", "retention-sub": "decouple-topic"} {
+		if err := queueClient.CreateSubscription(sub, topic); err != nil {
+			t.Fatalf("failed to create subscription %q: %v", sub, err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	end := time.Now()
+	topic := queueClient.Topic("decouple-topic", pubsub.DefaultPublishSettings, false)
+	for id, arrival := range map[string]time.Time{
+		"before": start.Add(-time.Minute),
+		"within": start.Add(time.Minute),
+		"after":  end.Add(time.Minute),
+	} {
+		e := event.New()
+		e.SetID(id)
+		e.SetSource("source")
+		e.SetType("type")
+		e.SetExtension(eventutil.ArrivalTimeAttribute, cetypes.Timestamp{Time: arrival})
+		msg, err := queue.NewEventMessage(ctx, binding.ToMessage(&e))
+		if err != nil {
+			t.Fatalf("failed to create message: %v", err)
+		}
+		if _, err := topic.Publish(ctx, msg); err != nil {
+			t.Fatalf("failed to publish event: %v", err)
+		}
+	}
+
+	targets := memory.NewEmptyTargets()
+	brokerKey := config.TestOnlyBrokerKey("ns", "broker")
+	target := &config.Target{
+		Name:           "trigger",
+		Namespace:      "ns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        subscriber.URL,
+		RetryQueue: &config.Queue{
+			Topic:        "retry-topic",
+			Subscription: "retry-sub",
+		},
+		State: config.State_READY,
+		Replay: &config.Replay{
+			Id:           "replay-uid",
+			Subscription: "retention-sub",
+			StartTime:    timestamppb.New(start),
+			EndTime:      timestamppb.New(end),
+		},
+	}
+	targets.MutateCellTenant(brokerKey, func(m config.CellTenantMutation) {
+		m.SetState(config.State_READY)
+		m.UpsertTargets(target)
+	})
+
+	retryClient, err := NewRetryClient(queueClient, DefaultCEClientOpts...)
+	if err != nil {
+		t.Fatalf("failed to create retry client: %v", err)
+	}
+	deliveryReporter, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatalf("failed to create delivery reporter: %v", err)
+	}
+	p, err := NewRetryPool(targets, queueClient, DefaultHTTPClient, retryClient, deliveryReporter)
+	if err != nil {
+		t.Fatalf("failed to create retry pool: %v", err)
+	}
+	if err := p.SyncOnce(ctx); err != nil {
+		t.Fatalf("failed to sync pool: %v", err)
+	}
+
+	select {
+	case id := <-received:
+		if id != "within" {
+			t.Errorf("subscriber received event %q, want %q", id, "within")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the event to be replayed")
+	}
+	for start := time.Now(); p.ReplayTracker().Progress()["replay-uid"].Events == 0; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("timed out waiting for the replay progress")
+		}
+	}
+
+	// Removing the replay from the config stops its handler.
+	// The running handler still reads the target, so the update is made on a copy.
+	targets.MutateCellTenant(brokerKey, func(m config.CellTenantMutation) {
+		t2 := proto.Clone(target).(*config.Target)
+		t2.Replay = nil
+		m.UpsertTargets(t2)
+	})
+	if err := p.SyncOnce(ctx); err != nil {
+		t.Fatalf("failed to sync pool: %v", err)
+	}
+	if len(p.replays) != 0 {
+		t.Errorf("got %d replay handlers, want 0", len(p.replays))
+	}
+	if progress := p.ReplayTracker().Progress(); len(progress) != 0 {
+		t.Errorf("got progress %v for removed replay", progress)
+	}
+	select {
+	case id := <-received:
+		t.Errorf("subscriber received unexpected event %q", id)
+	default:
+	}
+}
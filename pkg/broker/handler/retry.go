@@ -18,19 +18,29 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
+	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/zap"
 
-	"cloud.google.com/go/pubsub"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
 
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/ratelimit"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/transform"
+	"github.com/google/knative-gcp/pkg/broker/queue"
+	"github.com/google/knative-gcp/pkg/broker/replay"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -42,12 +52,24 @@ type RetryPool struct {
 	options *Options
 	targets config.ReadonlyTargets
 	pool    *syncMapTargetKey
-	// Pubsub client used to pull events from decoupling topics.
-	pubsubClient *pubsub.Client
+	// Queue client used to pull events from retry topics.
+	queueClient queue.Client
 	// For initial events delivery. We only need a shared client.
 	// And we can set target address dynamically.
 	deliverClient *http.Client
-	statsReporter *metrics.DeliveryReporter
+	// For republishing events to the retry topic when the target has a
+	// non-Pub/Sub dead letter sink.
+	deliverRetryClient ceclient.Client
+	statsReporter      *metrics.DeliveryReporter
+	// circuitBreakers is nil if circuit breakers are disabled.
+	circuitBreakers *circuitbreaker.Registry
+	// filters caches the compiled filters of the targets for all the handlers.
+	filters filter.Cache
+	// limiters holds the rate limiters of the targets for all the handlers.
+	limiters ratelimit.Limiters
+	// replays holds the handlers of the replays in progress. It is only accessed by SyncOnce.
+	replays       map[config.TargetKey]*replayHandlerCache
+	replayTracker *replay.Tracker
 }
 
 type retryHandlerCache struct {
@@ -67,7 +89,8 @@ func (hc *retryHandlerCache) shouldRenew(t *config.Target) bool {
 		return true
 	}
 	if t.RetryQueue.Topic != hc.t.RetryQueue.Topic ||
-		t.RetryQueue.Subscription != hc.t.RetryQueue.Subscription {
+		t.RetryQueue.Subscription != hc.t.RetryQueue.Subscription ||
+		t.RetryQueue.ProjectId != hc.t.RetryQueue.ProjectId {
 		return true
 	}
 	return false
@@ -76,8 +99,9 @@ func (hc *retryHandlerCache) shouldRenew(t *config.Target) bool {
 // NewRetryPool creates a new retry handler pool.
 func NewRetryPool(
 	targets config.ReadonlyTargets,
-	pubsubClient *pubsub.Client,
+	queueClient queue.Client,
 	deliverClient *http.Client,
+	retryClient RetryClient,
 	statsReporter *metrics.DeliveryReporter,
 	opts ...Option) (*RetryPool, error) {
 	options, err := NewOptions(opts...)
@@ -86,22 +110,45 @@ func NewRetryPool(
 	}
 
 	p := &RetryPool{
-		targets:       targets,
-		options:       options,
-		pool:          &syncMapTargetKey{},
-		pubsubClient:  pubsubClient,
-		deliverClient: deliverClient,
-		statsReporter: statsReporter,
+		targets:            targets,
+		options:            options,
+		pool:               &syncMapTargetKey{},
+		queueClient:        queueClient,
+		deliverClient:      deliverClient,
+		deliverRetryClient: retryClient,
+		statsReporter:      statsReporter,
+		replays:            make(map[config.TargetKey]*replayHandlerCache),
+		replayTracker:      replay.NewTracker(replayIdleTimeout),
+	}
+	if options.CircuitBreaker.FailureThreshold > 0 {
+		p.circuitBreakers = circuitbreaker.NewRegistry(options.CircuitBreaker, func(ctx context.Context, s circuitbreaker.State) {
+			statsReporter.ReportCircuitBreakerState(ctx, int64(s))
+		})
 	}
 	return p, nil
 }
 
+// CircuitBreakers returns the circuit breakers of the targets, or nil if they are disabled.
+func (p *RetryPool) CircuitBreakers() *circuitbreaker.Registry {
+	return p.circuitBreakers
+}
+
+// ReplayTracker returns the tracker of the progress of the replays in the pool.
+func (p *RetryPool) ReplayTracker() *replay.Tracker {
+	return p.replayTracker
+}
+
 // SyncOnce syncs once the handler pool based on the targets config.
 func (p *RetryPool) SyncOnce(ctx context.Context) error {
 	ctx, err := p.statsReporter.AddTags(ctx)
 	if err != nil {
 		logging.FromContext(ctx).Error("failed to add tags to context", zap.Error(err))
 	}
+	if p.circuitBreakers != nil {
+		p.circuitBreakers.Forget(p.targets)
+	}
+	p.filters.Prune(p.targets)
+	p.limiters.Prune(p.targets)
 
 	p.pool.Range(func(key config.TargetKey, value *retryHandlerCache) bool {
 		// Each target represents a trigger.
@@ -129,21 +176,51 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 			return true
 		}
 
-		sub := p.pubsubClient.Subscription(t.RetryQueue.Subscription)
-		sub.ReceiveSettings = p.options.PubsubReceiveSettings
+		client, err := p.queueClient.InProject(t.RetryQueue.ProjectId)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to create the queue client of the trigger project", zap.Stringer("trigger", t.Key()), zap.String("project", t.RetryQueue.ProjectId), zap.Error(err))
+			return true
+		}
+		if pr, ok := client.(queue.Provisioner); ok {
+			if err := pr.EnsureSubscription(t.RetryQueue.Subscription, t.RetryQueue.Topic); err != nil {
+				logging.FromContext(ctx).Error("failed to create the retry queue of the trigger", zap.Stringer("trigger", t.Key()), zap.Error(err))
+				return true
+			}
+		}
+		rs, concurrency := retryReceiveSettings(p.options.PubsubReceiveSettings)
+		sub := client.Subscription(t.RetryQueue.Subscription, rs)
 
 		h := NewHandler(
 			sub,
 			processors.ChainProcessors(
-				&filter.Processor{Targets: p.targets},
+				&filter.Processor{Targets: p.targets, Filters: &p.filters},
+				// Events that exceed the limits of the target are republished to the retry
+				// topic rather than nacked, so that they don't count toward the delivery
+				// attempts of the dead letter policy of the subscription.
+				&ratelimit.Processor{
+					Targets:     p.targets,
+					RetryClient: p.deliverRetryClient,
+					RetryDelay:  limitExceededRetryDelay,
+					Limiters:    &p.limiters,
+				},
+				&transform.Processor{Targets: p.targets, ClaimCheck: p.options.ClaimCheck},
 				&deliver.Processor{
-					DeliverClient: p.deliverClient,
-					Targets:       p.targets,
-					StatsReporter: p.statsReporter,
+					DeliverClient:      p.deliverClient,
+					Targets:            p.targets,
+					DeliverRetryClient: p.deliverRetryClient,
+					StatsReporter:      p.statsReporter,
+					CircuitBreakers:    p.circuitBreakers,
+					ClaimCheck:         p.options.ClaimCheck,
 				},
 			),
 			p.options.TimeoutPerEvent,
 		)
+		// Hold events until the retry time set by the deliver processor. Events that are
+		// not due after the hold are republished, so that they are not nacked.
+		h.MaxRetryHold = retryHold(rs.MaxExtension, p.options.TimeoutPerEvent)
+		h.MaxHeldMessages = maxHeldMessages
+		h.MaxConcurrency = concurrency
+		h.Reschedule = p.rescheduler(t)
 		hc := &retryHandlerCache{
 			Handler: *h,
 			t:       t,
@@ -171,9 +248,52 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		return true
 	})
 
+	p.syncReplays(ctx)
 	return nil
 }
 
+// retryHold returns the maximum time the handlers hold an event until its retry time. The Pub/Sub
+// client extends the ack deadline of a message for at most maxExtension, which must cover the
+// hold and the timeout to deliver the event, so longer backoffs are only republished once per
+// hold.
+func retryHold(maxExtension, timeout time.Duration) time.Duration {
+	if maxExtension <= 0 {
+		maxExtension = pubsub.DefaultReceiveSettings.MaxExtension
+	}
+	if hold := maxExtension - timeout; hold > minRetryHold {
+		return hold
+	}
+	return minRetryHold
+}
+
+// retryReceiveSettings returns the receive settings of the retry subscriptions, and the maximum
+// number of events the retry handlers process at once. The flow control of the subscriptions is
+// raised by maxHeldMessages, and the original limit is applied to the processing instead, so that
+// the messages held until their retry time don't block the messages that are due.
+func retryReceiveSettings(rs pubsub.ReceiveSettings) (pubsub.ReceiveSettings, int) {
+	if rs.MaxOutstandingMessages == 0 {
+		rs.MaxOutstandingMessages = pubsub.DefaultReceiveSettings.MaxOutstandingMessages
+	}
+	if rs.MaxOutstandingMessages < 0 {
+		return rs, 0
+	}
+	concurrency := rs.MaxOutstandingMessages
+	rs.MaxOutstandingMessages += maxHeldMessages
+	return rs, concurrency
+}
+
+// rescheduler returns a function that republishes the events of the target to its retry topic
+// unchanged, so that they are held again until their retry time when they are redelivered.
+func (p *RetryPool) rescheduler(t *config.Target) func(ctx context.Context, e *event.Event) error {
+	return func(ctx context.Context, e *event.Event) error {
+		pctx := queue.WithProject(cecontext.WithTopic(ctx, t.RetryQueue.Topic), t.RetryQueue.ProjectId)
+		if err := p.deliverRetryClient.Send(pctx, *e); err != nil {
+			return fmt.Errorf("failed to send event to retry topic: %w", err)
+		}
+		return nil
+	}
+}
+
 // syncMapTargetKey is a typed version of sync.Map.
 type syncMapTargetKey struct {
 	m sync.Map
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"cloud.google.com/go/pubsub"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
@@ -51,7 +52,7 @@ func TestRetryWatchAndSync(t *testing.T) {
 	defer helper.Close()
 
 	signal := make(chan struct{})
-	syncPool, err := InitializeTestRetryPool(helper.Targets, retryPod, retryContainer, helper.PubsubClient)
+	syncPool, err := InitializeTestRetryPool(ctx, helper.Targets, retryPod, retryContainer, helper.PubsubClient)
 	if err != nil {
 		t.Errorf("unexpected error from getting sync pool: %v", err)
 	}
@@ -148,7 +149,7 @@ func TestRetrySyncPoolE2E(t *testing.T) {
 	expectMetrics.AddTrigger(t, trigger(t3), wantRetryTags())
 
 	signal := make(chan struct{})
-	syncPool, err := InitializeTestRetryPool(helper.Targets, retryPod, retryContainer, helper.PubsubClient)
+	syncPool, err := InitializeTestRetryPool(ctx, helper.Targets, retryPod, retryContainer, helper.PubsubClient)
 	if err != nil {
 		t.Errorf("unexpected error from getting sync pool: %v", err)
 	}
@@ -360,3 +361,36 @@ func wantRetryTags() map[string]string {
 		"container_name": retryContainer,
 	}
 }
+
+func TestRetryHold(t *testing.T) {
+	for _, tc := range []struct {
+		maxExtension time.Duration
+		timeout      time.Duration
+		want         time.Duration
+	}{
+		{maxExtension: 60 * time.Minute, timeout: maxTimeout, want: 50 * time.Minute},
+		{timeout: time.Minute, want: 59 * time.Minute},
+		{maxExtension: 10 * time.Minute, timeout: maxTimeout, want: minRetryHold},
+	} {
+		if got := retryHold(tc.maxExtension, tc.timeout); got != tc.want {
+			t.Errorf("retryHold(%v, %v) = %v, want %v", tc.maxExtension, tc.timeout, got, tc.want)
+		}
+	}
+}
+
+func TestRetryReceiveSettings(t *testing.T) {
+	for _, tc := range []struct {
+		maxOutstanding     int
+		wantMaxOutstanding int
+		wantConcurrency    int
+	}{
+		{maxOutstanding: 10, wantMaxOutstanding: 10 + maxHeldMessages, wantConcurrency: 10},
+		{maxOutstanding: 0, wantMaxOutstanding: pubsub.DefaultReceiveSettings.MaxOutstandingMessages + maxHeldMessages, wantConcurrency: pubsub.DefaultReceiveSettings.MaxOutstandingMessages},
+		{maxOutstanding: -1, wantMaxOutstanding: -1, wantConcurrency: 0},
+	} {
+		rs, concurrency := retryReceiveSettings(pubsub.ReceiveSettings{MaxOutstandingMessages: tc.maxOutstanding})
+		if rs.MaxOutstandingMessages != tc.wantMaxOutstanding || concurrency != tc.wantConcurrency {
+			t.Errorf("retryReceiveSettings(%d) = %d, %d, want %d, %d", tc.maxOutstanding, rs.MaxOutstandingMessages, concurrency, tc.wantMaxOutstanding, tc.wantConcurrency)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/wire"
 )
@@ -37,6 +38,7 @@ func InitializeTestFanoutPool(
 ) (*FanoutPool, error) {
 	panic(wire.Build(
 		NewFanoutPool,
+		queue.NewPubsubClient,
 		NewRetryClient,
 		metrics.NewDeliveryReporter,
 		wire.Value(DefaultHTTPClient),
@@ -45,6 +47,7 @@ func InitializeTestFanoutPool(
 }
 
 func InitializeTestRetryPool(
+	ctx context.Context,
 	targets config.ReadonlyTargets,
 	podName metrics.PodName,
 	containerName metrics.ContainerName,
@@ -53,7 +56,10 @@ func InitializeTestRetryPool(
 ) (*RetryPool, error) {
 	panic(wire.Build(
 		NewRetryPool,
+		queue.NewPubsubClient,
+		NewRetryClient,
 		metrics.NewDeliveryReporter,
 		wire.Value(DefaultHTTPClient),
+		wire.Value(DefaultCEClientOpts),
 	))
 }
@@ -9,6 +9,7 @@ import (
 	"cloud.google.com/go/pubsub"
 	"context"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -17,7 +18,8 @@ import (
 func InitializeTestFanoutPool(ctx context.Context, podName metrics.PodName, containerName metrics.ContainerName, targets config.ReadonlyTargets, pubsubClient *pubsub.Client, opts ...Option) (*FanoutPool, error) {
 	client := _wireClientValue
 	v := _wireValue
-	retryClient, err := NewRetryClient(ctx, pubsubClient, v...)
+	queueClient := queue.NewPubsubClient(pubsubClient)
+	retryClient, err := NewRetryClient(queueClient, v...)
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +27,7 @@ func InitializeTestFanoutPool(ctx context.Context, podName metrics.PodName, cont
 	if err != nil {
 		return nil, err
 	}
-	fanoutPool, err := NewFanoutPool(targets, pubsubClient, client, retryClient, deliveryReporter, opts...)
+	fanoutPool, err := NewFanoutPool(targets, queueClient, client, retryClient, deliveryReporter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -37,13 +39,19 @@ var (
 	_wireValue       = DefaultCEClientOpts
 )
 
-func InitializeTestRetryPool(targets config.ReadonlyTargets, podName metrics.PodName, containerName metrics.ContainerName, pubsubClient *pubsub.Client, opts ...Option) (*RetryPool, error) {
+func InitializeTestRetryPool(ctx context.Context, targets config.ReadonlyTargets, podName metrics.PodName, containerName metrics.ContainerName, pubsubClient *pubsub.Client, opts ...Option) (*RetryPool, error) {
 	client := _wireHttpClientValue
+	v := _wireOptionValue
+	queueClient := queue.NewPubsubClient(pubsubClient)
+	retryClient, err := NewRetryClient(queueClient, v...)
+	if err != nil {
+		return nil, err
+	}
 	deliveryReporter, err := metrics.NewDeliveryReporter(podName, containerName)
 	if err != nil {
 		return nil, err
 	}
-	retryPool, err := NewRetryPool(targets, pubsubClient, client, deliveryReporter, opts...)
+	retryPool, err := NewRetryPool(targets, queueClient, client, retryClient, deliveryReporter, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -52,4 +60,5 @@ func InitializeTestRetryPool(targets config.ReadonlyTargets, podName metrics.Pod
 
 var (
 	_wireHttpClientValue = DefaultHTTPClient
+	_wireOptionValue     = DefaultCEClientOpts
 )
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// GoogleIssuer is the issuer of Google-signed ID tokens. Some tokens omit the scheme.
+	GoogleIssuer         = "https://accounts.google.com"
+	googleIssuerNoScheme = "accounts.google.com"
+	googleKeysURL        = "https://www.googleapis.com/oauth2/v3/certs"
+
+	// The Kubernetes API server serves the OpenID configuration of the service account token
+	// issuer, and its keys, to the service accounts bound to the
+	// system:service-account-issuer-discovery ClusterRole.
+	kubernetesConfigPath = "/.well-known/openid-configuration"
+	kubernetesKeysPath   = "/openid/v1/jwks"
+
+	kubernetesDiscoveryInterval = time.Minute
+	fetchTimeout                = 10 * time.Second
+)
+
+// AddGoogleIssuer trusts the Google-signed ID tokens, fetching the Google keys with the client.
+func AddGoogleIssuer(v *Verifier, client *http.Client) {
+	keys := NewKeySet(HTTPFetchFunc(client, googleKeysURL))
+	v.AddIssuer(GoogleIssuer, keys)
+	v.AddIssuer(googleIssuerNoScheme, keys)
+}
+
+// HTTPFetchFunc returns a FetchFunc that GETs the keys from the URL.
+func HTTPFetchFunc(client *http.Client, url string) FetchFunc {
+	return func(ctx context.Context) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+	}
+}
+
+// AddKubernetesIssuer discovers the issuer of the service account tokens of the cluster through
+// the Kubernetes API server, and trusts its tokens. It retries until the discovery succeeds or
+// the context is done.
+func AddKubernetesIssuer(ctx context.Context, v *Verifier, client rest.Interface) {
+	wait.PollImmediateUntil(kubernetesDiscoveryInterval, func() (bool, error) {
+		issuer, err := discoverKubernetesIssuer(ctx, client)
+		if err != nil {
+			logging.FromContext(ctx).Warn("Failed to discover the Kubernetes service account token issuer", zap.Error(err))
+			return false, nil
+		}
+		v.AddIssuer(issuer, NewKeySet(func(ctx context.Context) ([]byte, error) {
+			ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+			defer cancel()
+			return client.Get().AbsPath(kubernetesKeysPath).DoRaw(ctx)
+		}))
+		logging.FromContext(ctx).Info("Trusting Kubernetes service account tokens", zap.String("issuer", issuer))
+		return true, nil
+	}, ctx.Done())
+}
+
+func discoverKubernetesIssuer(ctx context.Context, client rest.Interface) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	b, err := client.Get().AbsPath(kubernetesConfigPath).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	var config struct {
+		Issuer string `json:"issuer"`
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return "", fmt.Errorf("failed to parse OpenID configuration: %w", err)
+	}
+	if config.Issuer == "" {
+		return "", fmt.Errorf("OpenID configuration has no issuer")
+	}
+	return config.Issuer, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	authtesting "github.com/google/knative-gcp/pkg/broker/ingress/auth/testing"
+)
+
+func TestAddKubernetesIssuer(t *testing.T) {
+	signer := authtesting.NewRSASigner(t, "sa-key")
+	keys := authtesting.JWKS(t, signer)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case kubernetesConfigPath:
+			w.Write([]byte(`{"issuer": "https://kubernetes.default.svc", "jwks_uri": "https://10.0.0.1/openid/v1/jwks"}`))
+		case kubernetesKeysPath:
+			w.Write(keys)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVerifier()
+	AddKubernetesIssuer(context.Background(), v, client.Discovery().RESTClient())
+
+	token := signer.Sign(t, map[string]interface{}{
+		"iss": "https://kubernetes.default.svc",
+		"sub": "system:serviceaccount:ns:sa",
+		"aud": testAudience,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	c, err := v.Verify(context.Background(), token, testAudience)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if c.Subject != "system:serviceaccount:ns:sa" {
+		t.Errorf("unexpected subject %q", c.Subject)
+	}
+}
+
+func TestHTTPFetchFunc(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/certs" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"keys": []}`))
+	}))
+	defer srv.Close()
+
+	b, err := HTTPFetchFunc(srv.Client(), srv.URL+"/certs")(context.Background())
+	if err != nil || string(b) != `{"keys": []}` {
+		t.Errorf("fetch = %q, %v", b, err)
+	}
+	if _, err := HTTPFetchFunc(srv.Client(), srv.URL+"/missing")(context.Background()); err == nil {
+		t.Error("fetch want error for a missing key set")
+	}
+}
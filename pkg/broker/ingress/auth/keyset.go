@@ -0,0 +1,152 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+const (
+	// defaultMaxAge is how long fetched keys are used before they are fetched again.
+	defaultMaxAge = time.Hour
+	// defaultMinRefreshInterval limits how often keys are fetched when a token is signed by an
+	// unknown key, so that tokens with made-up key IDs cannot flood the issuer.
+	defaultMinRefreshInterval = 30 * time.Second
+)
+
+// FetchFunc fetches a JSON Web Key Set.
+type FetchFunc func(ctx context.Context) ([]byte, error)
+
+// KeySet is a locally cached JSON Web Key Set, the public keys of a token issuer.
+type KeySet struct {
+	fetch FetchFunc
+
+	maxAge             time.Duration
+	minRefreshInterval time.Duration
+	now                func() time.Time
+
+	// mux guards the fields below, and serializes fetches.
+	mux       sync.Mutex
+	keys      map[string]*jose.JSONWebKey
+	fetchedAt time.Time
+	// attemptedAt is the time of the last fetch attempt, successful or not.
+	attemptedAt time.Time
+}
+
+// NewKeySet creates a KeySet that fetches keys on demand.
+func NewKeySet(fetch FetchFunc) *KeySet {
+	return &KeySet{
+		fetch:              fetch,
+		maxAge:             defaultMaxAge,
+		minRefreshInterval: defaultMinRefreshInterval,
+		now:                time.Now,
+	}
+}
+
+// Key returns the public key with the given key ID. Keys are fetched if they are older than
+// maxAge, or if the key ID is unknown, at most once per minRefreshInterval.
+func (s *KeySet) Key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	now := s.now()
+	key, ok := s.keys[kid]
+	stale := now.Sub(s.fetchedAt) >= s.maxAge
+	if ok && !stale {
+		return key, nil
+	}
+	if now.Sub(s.attemptedAt) >= s.minRefreshInterval {
+		s.attemptedAt = now
+		if err := s.refresh(ctx); err != nil {
+			if !ok {
+				return nil, err
+			}
+			// Keep using the cached key until the issuer is reachable again.
+			return key, nil
+		}
+		key, ok = s.keys[kid]
+	}
+	if !ok {
+		return nil, fmt.Errorf("key %q: %w", kid, ErrUnknownKey)
+	}
+	return key, nil
+}
+
+func (s *KeySet) refresh(ctx context.Context) error {
+	b, err := s.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch keys: %w", err)
+	}
+	keys, err := parseKeySet(b)
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	s.fetchedAt = s.now()
+	return nil
+}
+
+// parseKeySet parses the RSA and P-256 signing keys of a JSON Web Key Set, keyed by key ID.
+// Other keys are ignored.
+func parseKeySet(b []byte) (map[string]*jose.JSONWebKey, error) {
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse keys: %w", err)
+	}
+	keys := make(map[string]*jose.JSONWebKey, len(set.Keys))
+	for _, raw := range set.Keys {
+		// Keys of other types or curves may not be supported by go-jose, so they are skipped
+		// before they are parsed.
+		var params struct {
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			Use string `json:"use"`
+		}
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse keys: %w", err)
+		}
+		if (params.Use != "" && params.Use != "sig") || !(params.Kty == "RSA" || (params.Kty == "EC" && params.Crv == "P-256")) {
+			continue
+		}
+		var k jose.JSONWebKey
+		if err := k.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("failed to parse key: %w", err)
+		}
+		switch pub := k.Key.(type) {
+		case *rsa.PublicKey:
+		case *ecdsa.PublicKey:
+			if pub.Curve != elliptic.P256() {
+				continue
+			}
+		default:
+			// Private keys are not expected in a key set.
+			continue
+		}
+		keys[k.KeyID] = &k
+	}
+	return keys, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authtesting "github.com/google/knative-gcp/pkg/broker/ingress/auth/testing"
+)
+
+func TestKeySet(t *testing.T) {
+	now := time.Unix(1614600000, 0)
+	old := authtesting.NewRSASigner(t, "old")
+	rotated := authtesting.NewECSigner(t, "rotated")
+
+	fetches := 0
+	published := authtesting.JWKS(t, old)
+	var fetchErr error
+	s := NewKeySet(func(context.Context) ([]byte, error) {
+		fetches++
+		return published, fetchErr
+	})
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := s.Key(ctx, "old"); err != nil {
+		t.Fatalf("Key(old) error = %v", err)
+	}
+	if _, err := s.Key(ctx, "old"); err != nil || fetches != 1 {
+		t.Fatalf("Key(old) error = %v, fetches = %d, want cached key", err, fetches)
+	}
+
+	// Unknown keys are fetched at most once per minRefreshInterval.
+	published = authtesting.JWKS(t, old, rotated)
+	now = now.Add(defaultMinRefreshInterval / 2)
+	if _, err := s.Key(ctx, "rotated"); !errors.Is(err, ErrUnknownKey) || fetches != 1 {
+		t.Fatalf("Key(rotated) error = %v, fetches = %d, want unknown key without fetch", err, fetches)
+	}
+	now = now.Add(defaultMinRefreshInterval)
+	if _, err := s.Key(ctx, "rotated"); err != nil || fetches != 2 {
+		t.Fatalf("Key(rotated) error = %v, fetches = %d, want fetched key", err, fetches)
+	}
+
+	// Stale keys are used while the keys cannot be fetched.
+	fetchErr = errors.New("unavailable")
+	now = now.Add(defaultMaxAge)
+	if _, err := s.Key(ctx, "old"); err != nil || fetches != 3 {
+		t.Fatalf("Key(old) error = %v, fetches = %d, want stale key", err, fetches)
+	}
+	now = now.Add(defaultMinRefreshInterval)
+	if _, err := s.Key(ctx, "missing"); err == nil || errors.Is(err, ErrUnknownKey) || fetches != 4 {
+		t.Fatalf("Key(missing) error = %v, fetches = %d, want fetch error", err, fetches)
+	}
+
+	// Keys removed from the key set are forgotten.
+	fetchErr = nil
+	published = authtesting.JWKS(t, rotated)
+	now = now.Add(defaultMaxAge)
+	if _, err := s.Key(ctx, "old"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(old) error = %v, want unknown key", err)
+	}
+}
+
+func TestParseKeySet(t *testing.T) {
+	keys, err := parseKeySet([]byte(`{"keys": [
+		{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "EC", "kid": "p384", "crv": "P-384", "x": "AQAB", "y": "AQAB"}
+	]}`))
+	if err != nil {
+		t.Fatalf("parseKeySet() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("parseKeySet() = %v, want unsupported keys to be ignored", keys)
+	}
+	if _, err := parseKeySet([]byte(`{"keys": [{"kty": "EC", "kid": "bad", "crv": "P-256", "x": "AQAB", "y": "AQAB"}]}`)); err == nil {
+		t.Error("parseKeySet() want error for a point that is not on the curve")
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing provides helpers to sign tokens in tests.
+package testing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// Signer signs tokens with an RSA or a P-256 key.
+type Signer struct {
+	kid string
+	alg jose.SignatureAlgorithm
+	key crypto.Signer
+}
+
+// NewRSASigner creates a Signer that signs RS256 tokens with a new RSA key.
+func NewRSASigner(t testing.TB, kid string) *Signer {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Signer{kid: kid, alg: jose.RS256, key: k}
+}
+
+// NewECSigner creates a Signer that signs ES256 tokens with a new P-256 key.
+func NewECSigner(t testing.TB, kid string) *Signer {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Signer{kid: kid, alg: jose.ES256, key: k}
+}
+
+// WithKid returns a Signer with the same key, that claims to sign with the given key ID.
+func (s *Signer) WithKid(kid string) *Signer {
+	return &Signer{kid: kid, alg: s.alg, key: s.key}
+}
+
+// JWKS returns the JSON Web Key Set of the public keys of the signers.
+func JWKS(t testing.TB, signers ...*Signer) []byte {
+	var set jose.JSONWebKeySet
+	for _, s := range signers {
+		set.Keys = append(set.Keys, jose.JSONWebKey{Key: s.key.Public(), KeyID: s.kid, Use: "sig"})
+	}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Sign returns a token with the claims.
+func (s *Signer) Sign(t testing.TB, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: s.alg,
+		Key:       jose.JSONWebKey{Key: s.key, KeyID: s.kid},
+	}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth verifies the bearer tokens of the requests sent to Brokers. Tokens are JSON Web
+// Tokens signed with RS256 or ES256 by a trusted issuer, whose keys are cached locally. Tokens and
+// keys are parsed and verified with go-jose.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// leeway is the clock skew tolerated when checking the validity period of tokens.
+const leeway = time.Minute
+
+var (
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrUntrustedIssuer  = errors.New("untrusted issuer")
+	ErrUnknownKey       = errors.New("unknown key")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpiredToken     = errors.New("expired token")
+	ErrTokenNotValidYet = errors.New("token not valid yet")
+	ErrWrongAudience    = errors.New("wrong audience")
+)
+
+// Claims are the claims of a verified token.
+type Claims struct {
+	Issuer   string
+	Subject  string
+	Audience []string
+	// Email is the verified email of the principal, if any. Google-signed ID tokens of service
+	// accounts carry one.
+	Email     string
+	ExpiresAt time.Time
+}
+
+// IsAllowed returns true if the subject or the email of the token matches one of the principals.
+// A principal with a trailing * matches any suffix. Any token is allowed if principals is empty.
+func (c *Claims) IsAllowed(principals []string) bool {
+	if len(principals) == 0 {
+		return true
+	}
+	for _, p := range principals {
+		if matchPrincipal(p, c.Subject) || (c.Email != "" && matchPrincipal(p, c.Email)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPrincipal(pattern, principal string) bool {
+	if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+		return strings.HasPrefix(principal, prefix)
+	}
+	return pattern == principal
+}
+
+// Verifier verifies the tokens signed by trusted issuers.
+type Verifier struct {
+	mux     sync.RWMutex
+	issuers map[string]*KeySet
+
+	now func() time.Time
+}
+
+// NewVerifier creates a Verifier that trusts no issuer.
+func NewVerifier() *Verifier {
+	return &Verifier{
+		issuers: make(map[string]*KeySet),
+		now:     time.Now,
+	}
+}
+
+// AddIssuer trusts the tokens of the issuer signed with its keys.
+func (v *Verifier) AddIssuer(issuer string, keys *KeySet) {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	v.issuers[issuer] = keys
+}
+
+func (v *Verifier) keySet(issuer string) (*KeySet, bool) {
+	v.mux.RLock()
+	defer v.mux.RUnlock()
+	keys, ok := v.issuers[issuer]
+	return keys, ok
+}
+
+// emailClaims are the claims of Google-signed ID tokens that are not registered JWT claims.
+type emailClaims struct {
+	Email         string          `json:"email"`
+	EmailVerified json.RawMessage `json:"email_verified"`
+}
+
+// Verify verifies that the token is signed by a trusted issuer, is valid now and is issued for
+// the audience, and returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token, aud string) (*Claims, error) {
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedToken, err)
+	}
+	if len(tok.Headers) != 1 {
+		return nil, fmt.Errorf("%w: %d signatures", ErrMalformedToken, len(tok.Headers))
+	}
+	h := tok.Headers[0]
+	if alg := jose.SignatureAlgorithm(h.Algorithm); alg != jose.RS256 && alg != jose.ES256 {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, h.Algorithm)
+	}
+
+	// The issuer selects the keys the token is verified with, so it is read before the signature
+	// is verified.
+	var unverified jwt.Claims
+	if err := tok.UnsafeClaimsWithoutVerification(&unverified); err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", ErrMalformedToken, err)
+	}
+	keys, ok := v.keySet(unverified.Issuer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUntrustedIssuer, unverified.Issuer)
+	}
+	key, err := keys.Key(ctx, h.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	var c jwt.Claims
+	var e emailClaims
+	if err := tok.Claims(key, &c, &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	if c.Expiry == nil {
+		return nil, fmt.Errorf("%w: missing exp claim", ErrMalformedToken)
+	}
+	switch err := c.ValidateWithLeeway(jwt.Expected{Audience: jwt.Audience{aud}, Time: v.now()}, leeway); err {
+	case nil:
+	case jwt.ErrExpired:
+		return nil, ErrExpiredToken
+	case jwt.ErrNotValidYet, jwt.ErrIssuedInTheFuture:
+		return nil, ErrTokenNotValidYet
+	case jwt.ErrInvalidAudience:
+		return nil, fmt.Errorf("%w: %q", ErrWrongAudience, []string(c.Audience))
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrMalformedToken, err)
+	}
+
+	claims := &Claims{
+		Issuer:    c.Issuer,
+		Subject:   c.Subject,
+		Audience:  c.Audience,
+		ExpiresAt: c.Expiry.Time(),
+	}
+	if emailVerified(e.EmailVerified) {
+		claims.Email = e.Email
+	}
+	return claims, nil
+}
+
+// emailVerified parses the email_verified claim, which Google sets to a boolean, and some
+// issuers to a string.
+func emailVerified(raw json.RawMessage) bool {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s == "true"
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authtesting "github.com/google/knative-gcp/pkg/broker/ingress/auth/testing"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "http://ingress.example.com/ns/broker"
+)
+
+func staticFetch(b []byte) FetchFunc {
+	return func(context.Context) ([]byte, error) { return b, nil }
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1614600000, 0)
+	rsaSigner := authtesting.NewRSASigner(t, "rsa")
+	ecSigner := authtesting.NewECSigner(t, "ec")
+	untrusted := authtesting.NewRSASigner(t, "rsa")
+
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss": testIssuer,
+			"sub": "system:serviceaccount:ns:sa",
+			"aud": []string{testAudience},
+			"exp": now.Add(time.Hour).Unix(),
+			"iat": now.Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		want      *Claims
+		wantError error
+	}{{
+		name:  "valid RS256 token",
+		token: rsaSigner.Sign(t, claims(nil)),
+		want: &Claims{
+			Issuer:    testIssuer,
+			Subject:   "system:serviceaccount:ns:sa",
+			Audience:  []string{testAudience},
+			ExpiresAt: now.Add(time.Hour),
+		},
+	}, {
+		name: "valid ES256 token with a string audience and a verified email",
+		token: ecSigner.Sign(t, claims(map[string]interface{}{
+			"aud":            testAudience,
+			"email":          "sa@project.iam.gserviceaccount.com",
+			"email_verified": true,
+		})),
+		want: &Claims{
+			Issuer:    testIssuer,
+			Subject:   "system:serviceaccount:ns:sa",
+			Audience:  []string{testAudience},
+			Email:     "sa@project.iam.gserviceaccount.com",
+			ExpiresAt: now.Add(time.Hour),
+		},
+	}, {
+		name: "unverified email is ignored",
+		token: rsaSigner.Sign(t, claims(map[string]interface{}{
+			"email":          "sa@project.iam.gserviceaccount.com",
+			"email_verified": false,
+		})),
+		want: &Claims{
+			Issuer:    testIssuer,
+			Subject:   "system:serviceaccount:ns:sa",
+			Audience:  []string{testAudience},
+			ExpiresAt: now.Add(time.Hour),
+		},
+	}, {
+		name:      "not a token",
+		token:     "not-a-token",
+		wantError: ErrMalformedToken,
+	}, {
+		name:      "untrusted issuer",
+		token:     rsaSigner.Sign(t, claims(map[string]interface{}{"iss": "https://other.example.com"})),
+		wantError: ErrUntrustedIssuer,
+	}, {
+		name:      "unknown key",
+		token:     rsaSigner.WithKid("other").Sign(t, claims(nil)),
+		wantError: ErrUnknownKey,
+	}, {
+		name:      "signed by another key",
+		token:     untrusted.Sign(t, claims(nil)),
+		wantError: ErrInvalidSignature,
+	}, {
+		name:      "algorithm of another key type",
+		token:     rsaSigner.WithKid("ec").Sign(t, claims(nil)),
+		wantError: ErrInvalidSignature,
+	}, {
+		name:      "expired",
+		token:     rsaSigner.Sign(t, claims(map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()})),
+		wantError: ErrExpiredToken,
+	}, {
+		name:  "expired within leeway",
+		token: rsaSigner.Sign(t, claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})),
+		want: &Claims{
+			Issuer:    testIssuer,
+			Subject:   "system:serviceaccount:ns:sa",
+			Audience:  []string{testAudience},
+			ExpiresAt: now.Add(-30 * time.Second),
+		},
+	}, {
+		name:      "missing expiration",
+		token:     rsaSigner.Sign(t, claims(map[string]interface{}{"exp": nil})),
+		wantError: ErrMalformedToken,
+	}, {
+		name:      "not valid yet",
+		token:     rsaSigner.Sign(t, claims(map[string]interface{}{"nbf": now.Add(10 * time.Minute).Unix()})),
+		wantError: ErrTokenNotValidYet,
+	}, {
+		name:      "wrong audience",
+		token:     rsaSigner.Sign(t, claims(map[string]interface{}{"aud": "http://ingress.example.com/ns/other"})),
+		wantError: ErrWrongAudience,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewVerifier()
+			v.now = func() time.Time { return now }
+			v.AddIssuer(testIssuer, NewKeySet(staticFetch(authtesting.JWKS(t, rsaSigner, ecSigner))))
+			got, err := v.Verify(context.Background(), test.token, testAudience)
+			if !errors.Is(err, test.wantError) {
+				t.Fatalf("Verify() error = %v, want %v", err, test.wantError)
+			}
+			if test.want == nil {
+				return
+			}
+			if got.Issuer != test.want.Issuer || got.Subject != test.want.Subject || got.Email != test.want.Email ||
+				len(got.Audience) != 1 || got.Audience[0] != test.want.Audience[0] || !got.ExpiresAt.Equal(test.want.ExpiresAt) {
+				t.Errorf("Verify() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestClaimsIsAllowed(t *testing.T) {
+	c := &Claims{Subject: "system:serviceaccount:ns:sa", Email: "sa@project.iam.gserviceaccount.com"}
+	tests := []struct {
+		principals []string
+		want       bool
+	}{
+		{principals: nil, want: true},
+		{principals: []string{"system:serviceaccount:ns:sa"}, want: true},
+		{principals: []string{"other", "sa@project.iam.gserviceaccount.com"}, want: true},
+		{principals: []string{"system:serviceaccount:ns:*"}, want: true},
+		{principals: []string{"system:serviceaccount:other:*"}, want: false},
+		{principals: []string{"system:serviceaccount:ns:s"}, want: false},
+	}
+	for _, test := range tests {
+		if got := c.IsAllowed(test.principals); got != test.want {
+			t.Errorf("IsAllowed(%q) = %v, want %v", test.principals, got, test.want)
+		}
+	}
+}
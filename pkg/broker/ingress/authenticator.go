@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/ingress/auth"
+)
+
+// Reasons of the requests rejected by the Authenticator, reported by the rejected_request_count
+// metric.
+const (
+	reasonMissingToken     = "missing_token"
+	reasonMalformedToken   = "malformed_token"
+	reasonUntrustedIssuer  = "untrusted_issuer"
+	reasonUnknownKey       = "unknown_key"
+	reasonInvalidSignature = "invalid_signature"
+	reasonExpiredToken     = "expired_token"
+	reasonInvalidToken     = "invalid_token"
+	reasonWrongAudience    = "wrong_audience"
+	reasonForbidden        = "forbidden"
+)
+
+// AuthError is returned by the Authenticator when a request is rejected.
+type AuthError struct {
+	// StatusCode is 401 if the request is not authenticated, and 403 if its principal is not
+	// allowed to send events to the Broker.
+	StatusCode int
+	// Reason is a short snake_case reason, used as a metric tag.
+	Reason string
+	Err    error
+}
+
+func (e *AuthError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// Authenticator authenticates the requests sent to the Brokers that require it, by verifying
+// their bearer token.
+type Authenticator struct {
+	// brokerConfig holds configurations for all brokers. It's a view of a configmap populated by
+	// the broker controller.
+	brokerConfig config.ReadonlyTargets
+	verifier     *auth.Verifier
+}
+
+// NewAuthenticator creates a new Authenticator.
+func NewAuthenticator(brokerConfig config.ReadonlyTargets, verifier *auth.Verifier) *Authenticator {
+	return &Authenticator{
+		brokerConfig: brokerConfig,
+		verifier:     verifier,
+	}
+}
+
+// Authenticate returns an AuthError if the broker requires authentication and the Authorization
+// header value is not a bearer token issued for the broker to an allowed principal.
+func (a *Authenticator) Authenticate(ctx context.Context, broker *config.CellTenantKey, authorization string) *AuthError {
+	brokerConfig, ok := a.brokerConfig.GetCellTenantByKey(broker)
+	if !ok || brokerConfig.IngressAuth == nil {
+		// Unknown brokers are rejected when the event is sent to the decouple sink.
+		return nil
+	}
+	token, ok := bearerToken(authorization)
+	if !ok {
+		return &AuthError{
+			StatusCode: nethttp.StatusUnauthorized,
+			Reason:     reasonMissingToken,
+			Err:        errors.New("missing bearer token"),
+		}
+	}
+	claims, err := a.verifier.Verify(ctx, token, brokerConfig.IngressAuth.Audience)
+	if err != nil {
+		return &AuthError{
+			StatusCode: nethttp.StatusUnauthorized,
+			Reason:     verifyErrorReason(err),
+			Err:        err,
+		}
+	}
+	if !claims.IsAllowed(brokerConfig.IngressAuth.AllowedPrincipals) {
+		return &AuthError{
+			StatusCode: nethttp.StatusForbidden,
+			Reason:     reasonForbidden,
+			Err:        fmt.Errorf("principal %q is not allowed to send events to %s", claims.Subject, broker),
+		}
+	}
+	return nil
+}
+
+// bearerToken returns the token of a bearer Authorization header value.
+func bearerToken(authorization string) (string, bool) {
+	const prefix = "bearer "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(authorization[len(prefix):])
+	return token, token != ""
+}
+
+func verifyErrorReason(err error) string {
+	switch {
+	case errors.Is(err, auth.ErrMalformedToken):
+		return reasonMalformedToken
+	case errors.Is(err, auth.ErrUntrustedIssuer):
+		return reasonUntrustedIssuer
+	case errors.Is(err, auth.ErrUnknownKey):
+		return reasonUnknownKey
+	case errors.Is(err, auth.ErrInvalidSignature):
+		return reasonInvalidSignature
+	case errors.Is(err, auth.ErrExpiredToken):
+		return reasonExpiredToken
+	case errors.Is(err, auth.ErrWrongAudience):
+		return reasonWrongAudience
+	default:
+		return reasonInvalidToken
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	nethttp "net/http"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	kntracing "knative.dev/eventing/pkg/tracing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/tracing"
+	"github.com/google/knative-gcp/pkg/utils/eventbatch"
+)
+
+// serveBatch sends the events of a batch request to the decouple sink, and responds with the
+// result of each event. Events are sent concurrently, except that events with the same ordering
+// key are sent one after the other, in the order of the batch.
+func (h *Handler) serveBatch(ctx context.Context, response nethttp.ResponseWriter, request *nethttp.Request, broker *config.CellTenantKey) {
+	batch, err := eventbatch.Read(response, request, h.maxRequestBytes())
+	if err != nil {
+		httpStatus := eventbatch.ReadErrorCode(err)
+		nethttp.Error(response, err.Error(), httpStatus)
+		h.reportMetrics(ctx, "_invalid_cloud_event_", httpStatus)
+		return
+	}
+
+	span := trace.FromContext(ctx)
+	span.SetName(broker.SpanMessagingDestination())
+	if span.IsRecordingEvents() {
+		span.AddAttributes(
+			kntracing.MessagingSystemAttribute,
+			tracing.PubSubProtocolAttribute,
+			broker.SpanMessagingDestinationAttribute(),
+		)
+	}
+
+	events, results := eventbatch.Decode(batch)
+	for i, event := range events {
+		if event == nil {
+			logging.FromContext(ctx).Debug("Invalid event in batch", zap.Int("index", i), zap.String("error", results[i].Error))
+			h.reportMetrics(ctx, "_invalid_cloud_event_", nethttp.StatusBadRequest)
+		}
+	}
+	eventbatch.Send(events, results,
+		func(event *cev2.Event) string {
+			return orderingKey(h.brokerConfig, broker, event)
+		},
+		func(event *cev2.Event, result *eventbatch.Result) {
+			result.Code, result.Error = h.send(ctx, broker, event)
+		},
+		func(event *cev2.Event, statusCode int) {
+			h.reportMetrics(ctx, event.Type(), statusCode)
+		})
+
+	if err := eventbatch.WriteResponse(response, results); err != nil {
+		logging.FromContext(ctx).Warn("Failed to write batch response", zap.Error(err))
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+	"github.com/google/knative-gcp/pkg/utils/eventbatch"
+)
+
+var batchBrokerConfig = &config.TargetsConfig{
+	CellTenants: map[string]*config.CellTenant{
+		"ns1/broker1": {
+			Id:        "b-uid-1",
+			Type:      config.CellTenantType_BROKER,
+			Name:      "broker1",
+			Namespace: "ns1",
+		},
+		"ns1/ordered": {
+			Id:                   "b-uid-2",
+			Type:                 config.CellTenantType_BROKER,
+			Name:                 "ordered",
+			Namespace:            "ns1",
+			OrderingKeyAttribute: "subject",
+		},
+	},
+}
+
+// fakeBatchDecoupleSink records the IDs of the events sent to it, and rejects the events with the
+// configured results.
+type fakeBatchDecoupleSink struct {
+	results map[string]protocol.Result
+	mux     sync.Mutex
+	sent    []string
+	// untimed are the IDs of the events sent without a time.
+	untimed []string
+}
+
+func (m *fakeBatchDecoupleSink) Send(_ context.Context, _ *config.CellTenantKey, event cev2.Event) protocol.Result {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.sent = append(m.sent, event.ID())
+	if event.Time().IsZero() {
+		m.untimed = append(m.untimed, event.ID())
+	}
+	return m.results[event.ID()]
+}
+
+func batchEvent(id, subject string) map[string]interface{} {
+	e := map[string]interface{}{
+		"specversion": "1.0",
+		"id":          id,
+		"source":      "test-source",
+		"type":        eventType,
+	}
+	if subject != "" {
+		e["subject"] = subject
+	}
+	return e
+}
+
+func TestServeBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        interface{}
+		results     map[string]protocol.Result
+		wantCode    int
+		wantBody    *eventbatch.Response
+		wantSent    []string
+		// wantEventCount is the number of events reported by event type and response code.
+		wantEventCount map[string]int64
+	}{{
+		name:     "all accepted",
+		path:     "/ns1/broker1",
+		body:     []interface{}{batchEvent("1", ""), batchEvent("2", ""), batchEvent("3", "")},
+		wantCode: nethttp.StatusAccepted,
+		wantBody: &eventbatch.Response{Accepted: 3, Results: []eventbatch.Result{
+			{ID: "1", Source: "test-source", Code: nethttp.StatusAccepted},
+			{ID: "2", Source: "test-source", Code: nethttp.StatusAccepted},
+			{ID: "3", Source: "test-source", Code: nethttp.StatusAccepted},
+		}},
+		wantSent:       []string{"1", "2", "3"},
+		wantEventCount: map[string]int64{eventType + "/202": 3},
+	}, {
+		name:        "content type with charset",
+		path:        "/ns1/broker1",
+		contentType: "application/cloudevents-batch+json; charset=UTF-8",
+		body:        []interface{}{batchEvent("1", "")},
+		wantCode:    nethttp.StatusAccepted,
+		wantBody: &eventbatch.Response{Accepted: 1, Results: []eventbatch.Result{
+			{ID: "1", Source: "test-source", Code: nethttp.StatusAccepted},
+		}},
+		wantSent:       []string{"1"},
+		wantEventCount: map[string]int64{eventType + "/202": 1},
+	}, {
+		name:     "empty batch",
+		path:     "/ns1/broker1",
+		body:     []interface{}{},
+		wantCode: nethttp.StatusAccepted,
+		wantBody: &eventbatch.Response{Results: []eventbatch.Result{}},
+	}, {
+		name:     "invalid event",
+		path:     "/ns1/broker1",
+		body:     []interface{}{batchEvent("1", ""), map[string]interface{}{"specversion": "1.0", "id": "2"}},
+		wantCode: nethttp.StatusMultiStatus,
+		wantBody: &eventbatch.Response{Accepted: 1, Rejected: 1, Results: []eventbatch.Result{
+			{ID: "1", Source: "test-source", Code: nethttp.StatusAccepted},
+			{Code: nethttp.StatusBadRequest},
+		}},
+		wantSent: []string{"1"},
+		wantEventCount: map[string]int64{
+			eventType + "/202":          1,
+			"_invalid_cloud_event_/400": 1,
+		},
+	}, {
+		name:     "all rejected",
+		path:     "/ns1/broker1",
+		body:     []interface{}{batchEvent("1", ""), batchEvent("2", "")},
+		results:  map[string]protocol.Result{"1": ErrNotFound, "2": ErrNotFound},
+		wantCode: nethttp.StatusNotFound,
+		wantBody: &eventbatch.Response{Rejected: 2, Results: []eventbatch.Result{
+			{ID: "1", Source: "test-source", Code: nethttp.StatusNotFound, Error: "Failed to publish to PubSub"},
+			{ID: "2", Source: "test-source", Code: nethttp.StatusNotFound, Error: "Failed to publish to PubSub"},
+		}},
+		wantSent:       []string{"1", "2"},
+		wantEventCount: map[string]int64{eventType + "/404": 2},
+	}, {
+		name: "ordered events after a rejected event are not sent",
+		path: "/ns1/ordered",
+		body: []interface{}{
+			batchEvent("1", "a"), batchEvent("2", "a"), batchEvent("3", "a"), batchEvent("4", "b"),
+		},
+		results:  map[string]protocol.Result{"2": ErrNotReady},
+		wantCode: nethttp.StatusMultiStatus,
+		wantBody: &eventbatch.Response{Accepted: 2, Rejected: 2, Results: []eventbatch.Result{
+			{ID: "1", Source: "test-source", Code: nethttp.StatusAccepted},
+			{ID: "2", Source: "test-source", Code: nethttp.StatusServiceUnavailable, Error: "Failed to publish to PubSub"},
+			{ID: "3", Source: "test-source", Code: nethttp.StatusServiceUnavailable, Error: "Not sent because a previous event with the same ordering key was rejected"},
+			{ID: "4", Source: "test-source", Code: nethttp.StatusAccepted},
+		}},
+		wantSent: []string{"1", "2", "4"},
+		wantEventCount: map[string]int64{
+			eventType + "/202": 2,
+			eventType + "/503": 2,
+		},
+	}, {
+		name:           "malformed batch",
+		path:           "/ns1/broker1",
+		body:           map[string]string{"specversion": "1.0"},
+		wantCode:       nethttp.StatusBadRequest,
+		wantEventCount: map[string]int64{"_invalid_cloud_event_/400": 1},
+	}, {
+		name:           "too many events",
+		path:           "/ns1/broker1",
+		body:           make([]interface{}, eventbatch.MaxEvents+1),
+		wantCode:       nethttp.StatusRequestEntityTooLarge,
+		wantEventCount: map[string]int64{"_invalid_cloud_event_/413": 1},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink := &fakeBatchDecoupleSink{results: tc.results}
+			h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(batchBrokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+			body, err := json.Marshal(tc.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(nethttp.MethodPost, tc.path, bytes.NewReader(body))
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = cev2.ApplicationCloudEventsBatchJSON
+			}
+			req.Header.Set("Content-Type", contentType)
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+
+			if res.Code != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, tc.wantCode)
+			}
+			if tc.wantBody != nil {
+				var got eventbatch.Response
+				if err := json.Unmarshal(res.Body.Bytes(), &got); err != nil {
+					t.Fatalf("Failed to decode response %q: %v", res.Body.String(), err)
+				}
+				// Errors of invalid events come from the CloudEvents SDK.
+				for i := range got.Results {
+					if got.Results[i].Code == nethttp.StatusBadRequest && got.Results[i].Error != "" {
+						got.Results[i].Error = ""
+					}
+				}
+				if diff := cmp.Diff(tc.wantBody, &got); diff != "" {
+					t.Errorf("Unexpected response (-want, +got): %s", diff)
+				}
+			}
+
+			sort.Strings(sink.sent)
+			if diff := cmp.Diff(tc.wantSent, sink.sent); diff != "" {
+				t.Errorf("Unexpected sent events (-want, +got): %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.wantEventCount, eventCountByCode()); diff != "" {
+				t.Errorf("Unexpected event_count (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+// eventCountByCode returns the number of events reported by the event_count metric, by event type
+// and response code.
+func eventCountByCode() map[string]int64 {
+	metricstest.EnsureRecorded()
+	var counts map[string]int64
+	for _, m := range metricstest.GetMetric("event_count") {
+		for _, v := range m.Values {
+			if counts == nil {
+				counts = make(map[string]int64)
+			}
+			counts[v.Tags[metricskey.LabelEventType]+"/"+v.Tags[metricskey.LabelResponseCode]] += *v.Int64
+		}
+	}
+	return counts
+}
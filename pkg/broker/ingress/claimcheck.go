@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// claimCheckDeleteTimeout is the timeout to delete the offloaded data of an event that was not
+// published.
+const claimCheckDeleteTimeout = 5 * time.Second
+
+// ClaimCheck configures the offload of the data of large events to Cloud Storage.
+type ClaimCheck struct {
+	Store *claimcheck.Store
+	// Threshold is the data size in bytes above which events are offloaded.
+	Threshold int
+	// MaxRequestBytes is the maximum size in bytes of a request. It replaces the limit imposed
+	// by the Pub/Sub message size, since large events are not published as is.
+	MaxRequestBytes int64
+}
+
+// maxRequestBytes returns the maximum size in bytes of a request.
+func (h *Handler) maxRequestBytes() int64 {
+	if h.claimCheck != nil && h.claimCheck.MaxRequestBytes > 0 {
+		return h.claimCheck.MaxRequestBytes
+	}
+	return maxRequestBodyBytes
+}
+
+// offload offloads the data of the event to Cloud Storage if it is larger than the threshold. It
+// returns the id of the broker if the event was offloaded, so that the object can be deleted if
+// the event is not published. It returns claimcheck.ErrInvalidReference if the event already
+// references an object that does not belong to the broker.
+func (h *Handler) offload(ctx context.Context, broker *config.CellTenantKey, event *cev2.Event) (string, error) {
+	if h.claimCheck == nil {
+		return "", nil
+	}
+	tenant, ok := h.brokerConfig.GetCellTenantByKey(broker)
+	if !ok {
+		// The decouple sink rejects the events sent to unknown brokers.
+		return "", nil
+	}
+	if err := h.claimCheck.Store.Check(tenant.Id, event); err != nil {
+		return "", err
+	}
+	if len(event.Data()) <= h.claimCheck.Threshold {
+		return "", nil
+	}
+	if err := h.claimCheck.Store.Offload(ctx, tenant.Id, event); err != nil {
+		return "", err
+	}
+	logging.FromContext(ctx).Debug("Offloaded event data", zap.String("id", event.ID()), zap.Any("reference", event.Extensions()[claimcheck.Extension]))
+	return tenant.Id, nil
+}
+
+// deleteOffloaded deletes the object of an event offloaded by offload that was not published. The
+// object is deleted even if the request context is done, which may be why the publish failed.
+func (h *Handler) deleteOffloaded(ctx context.Context, brokerID string, event *cev2.Event) {
+	deleteCtx, cancel := context.WithTimeout(context.Background(), claimCheckDeleteTimeout)
+	defer cancel()
+	if err := h.claimCheck.Store.Delete(deleteCtx, brokerID, event); err != nil {
+		logging.FromContext(ctx).Warn("Failed to delete offloaded event data", zap.String("id", event.ID()), zap.Error(err))
+	}
+}
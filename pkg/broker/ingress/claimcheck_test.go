@@ -0,0 +1,204 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	storagetesting "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+// fakeRecordingDecoupleSink records the events sent to it, and returns the configured result.
+type fakeRecordingDecoupleSink struct {
+	result protocol.Result
+	sent   []cev2.Event
+	// onSend is called when an event is sent, if set.
+	onSend func()
+}
+
+func (m *fakeRecordingDecoupleSink) Send(_ context.Context, _ *config.CellTenantKey, event cev2.Event) protocol.Result {
+	m.sent = append(m.sent, event)
+	if m.onSend != nil {
+		m.onSend()
+	}
+	if m.result == nil {
+		return protocol.ResultACK
+	}
+	return m.result
+}
+
+func TestHandlerOffloadsLargeEvents(t *testing.T) {
+	large := `"` + strings.Repeat("x", maxRequestBodyBytes) + `"`
+	tests := []struct {
+		name          string
+		data          string
+		reference     string
+		writeErr      error
+		result        protocol.Result
+		cancelRequest bool
+		wantCode      int
+		wantOffloaded bool
+		wantObjects   int
+	}{{
+		name:     "small event",
+		data:     `"small"`,
+		wantCode: nethttp.StatusAccepted,
+	}, {
+		name:          "large event",
+		data:          large,
+		wantCode:      nethttp.StatusAccepted,
+		wantOffloaded: true,
+		wantObjects:   1,
+	}, {
+		name:     "write error",
+		data:     large,
+		writeErr: errors.New("write failed"),
+		wantCode: nethttp.StatusInternalServerError,
+	}, {
+		name:          "publish error",
+		data:          large,
+		result:        errors.New("publish failed"),
+		wantCode:      nethttp.StatusInternalServerError,
+		wantOffloaded: true,
+	}, {
+		name:          "publish error after the request is cancelled",
+		data:          large,
+		result:        context.Canceled,
+		cancelRequest: true,
+		wantCode:      nethttp.StatusInternalServerError,
+		wantOffloaded: true,
+	}, {
+		name:          "duplicate event",
+		data:          large,
+		result:        protocol.NewReceipt(true, "%w", ErrDuplicate),
+		wantCode:      nethttp.StatusAccepted,
+		wantOffloaded: true,
+	}, {
+		name:      "reference to another broker",
+		data:      `"small"`,
+		reference: "gs://bucket/claimcheck/b-uid-2/id",
+		wantCode:  nethttp.StatusBadRequest,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			objects := storagetesting.NewTestObjects()
+			client, err := storagetesting.TestClientCreator(storagetesting.TestClientData{
+				BucketData: storagetesting.TestBucketData{Objects: objects, WriteObjectErr: tc.writeErr},
+			})(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sink := &fakeRecordingDecoupleSink{result: tc.result}
+			if tc.cancelRequest {
+				sink.onSend = cancel
+			}
+			h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(brokerConfig), &ClaimCheck{
+				Store:           claimcheck.NewStore(client, "bucket"),
+				Threshold:       100,
+				MaxRequestBytes: 2 * maxRequestBodyBytes,
+			}, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+			event := createTestEvent("test-event")
+			if err := event.SetData(cev2.ApplicationJSON, []byte(tc.data)); err != nil {
+				t.Fatal(err)
+			}
+			if tc.reference != "" {
+				event.SetExtension(claimcheck.Extension, tc.reference)
+			}
+			req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", nil).WithContext(ctx)
+			if err := http.WriteRequest(context.Background(), binding.ToMessage(event), req); err != nil {
+				t.Fatal(err)
+			}
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+
+			if res.Code != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, tc.wantCode)
+			}
+			if got := len(objects.Names()); got != tc.wantObjects {
+				t.Errorf("got %d objects, want %d", got, tc.wantObjects)
+			}
+			if len(sink.sent) == 0 {
+				return
+			}
+			sent := sink.sent[0]
+			_, offloaded := sent.Extensions()[claimcheck.Extension]
+			if offloaded != tc.wantOffloaded {
+				t.Errorf("event offloaded = %v, want %v", offloaded, tc.wantOffloaded)
+			}
+			if wantData := tc.data; offloaded {
+				if len(sent.Data()) != 0 {
+					t.Errorf("offloaded event has data of size %d", len(sent.Data()))
+				}
+			} else if !bytes.Equal(sent.Data(), []byte(wantData)) {
+				t.Errorf("event data = %q, want %q", sent.Data(), wantData)
+			}
+		})
+	}
+}
+
+func TestHandlerRejectsLargeEventsWithoutClaimCheck(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &fakeRecordingDecoupleSink{}
+	h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(brokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+	event := createTestEvent("test-event")
+	if err := event.SetData(cev2.ApplicationJSON, []byte(`"`+strings.Repeat("x", maxRequestBodyBytes)+`"`)); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", nil)
+	if err := http.WriteRequest(context.Background(), binding.ToMessage(event), req); err != nil {
+		t.Fatal(err)
+	}
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+
+	if res.Code != nethttp.StatusRequestEntityTooLarge {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, nethttp.StatusRequestEntityTooLarge)
+	}
+	if len(sink.sent) != 0 {
+		t.Errorf("event sent to the decouple sink")
+	}
+}
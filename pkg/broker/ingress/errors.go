@@ -28,3 +28,11 @@ var ErrIncomplete = errors.New("incomplete config")
 
 // ErrNotReady is the error when a broker is not ready.
 var ErrNotReady = errors.New("not ready")
+
+// ErrDuplicate is the error wrapped by the acknowledgement of a duplicate event, which is
+// accepted without being published again.
+var ErrDuplicate = errors.New("duplicate event")
+
+// ErrDuplicatePending is the error when an event is a duplicate of an event that is still being
+// published. It is rejected, so that it is sent again if the first event fails to be published.
+var ErrDuplicatePending = errors.New("duplicate of an event being published")
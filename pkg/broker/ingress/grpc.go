@@ -0,0 +1,200 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/ingress/ingresspb"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// startGRPC blocks to receive events over gRPC, until ctx is done.
+func (h *Handler) startGRPC(ctx context.Context) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", h.streaming.GRPCPort))
+	if err != nil {
+		return err
+	}
+	// Each message holds a single event, so it gets the size limit of a request.
+	srv := grpc.NewServer(grpc.MaxRecvMsgSize(int(h.maxRequestBytes())))
+	ingresspb.RegisterIngressServer(srv, &grpcServer{h: h})
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+	return srv.Serve(lis)
+}
+
+// grpcServer implements the gRPC Ingress service.
+type grpcServer struct {
+	ingresspb.UnimplementedIngressServer
+	h *Handler
+}
+
+// Publish implements ingresspb.IngressServer. Each event goes through the same steps as the event
+// of a request served by Handler.ServeHTTP. The token in the authorization metadata of the stream
+// is verified for each event, so that the events sent after it expires are rejected.
+func (s *grpcServer) Publish(stream ingresspb.Ingress_PublishServer) error {
+	ctx := logging.WithLogger(stream.Context(), s.h.logger)
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			authorization = v[0]
+		}
+	}
+	sender := newStreamSender(s.h, func(id string, statusCode int, message string) {
+		if err := stream.Send(&ingresspb.PublishResponse{Id: id, StatusCode: int32(statusCode), Message: message}); err != nil {
+			logging.FromContext(ctx).Debug("Failed to send publish response", zap.Error(err))
+		}
+	})
+	defer sender.wait()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		id := req.GetEvent().GetId()
+		broker, err := config.CellTenantKeyFromPersistenceString(req.GetBroker())
+		if err != nil {
+			sender.reply(id, nethttp.StatusNotFound, err.Error())
+			continue
+		}
+		brokerCtx := withBroker(ctx, broker)
+		if authErr := s.h.authenticate(brokerCtx, broker, authorization); authErr != nil {
+			sender.reply(id, authErr.StatusCode, authErr.Error())
+			continue
+		}
+		e, err := eventFromProto(req.GetEvent())
+		if err != nil {
+			statusCode, message := s.h.rejectInvalidEvent(brokerCtx, err)
+			sender.reply(id, statusCode, message)
+			continue
+		}
+		sender.send(brokerCtx, broker, e)
+	}
+}
+
+// eventFromProto converts an event in the CloudEvents protobuf format to an event.
+func eventFromProto(pe *ingresspb.CloudEvent) (*cev2.Event, error) {
+	if pe == nil {
+		return nil, errors.New("missing event")
+	}
+	var e cev2.Event
+	switch pe.GetSpecVersion() {
+	case event.CloudEventsVersionV1:
+		e = cev2.NewEvent(event.CloudEventsVersionV1)
+	case event.CloudEventsVersionV03:
+		e = cev2.NewEvent(event.CloudEventsVersionV03)
+	default:
+		return nil, fmt.Errorf("unsupported spec version %q", pe.GetSpecVersion())
+	}
+	e.SetID(pe.GetId())
+	e.SetSource(pe.GetSource())
+	e.SetType(pe.GetType())
+	for name, attr := range pe.GetAttributes() {
+		value, err := attributeValue(attr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute %q: %w", name, err)
+		}
+		if err := setAttribute(&e, name, value); err != nil {
+			return nil, fmt.Errorf("invalid attribute %q: %w", name, err)
+		}
+	}
+	switch data := pe.GetData().(type) {
+	case *ingresspb.CloudEvent_BinaryData:
+		e.DataEncoded = data.BinaryData
+	case *ingresspb.CloudEvent_TextData:
+		e.DataEncoded = []byte(data.TextData)
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func attributeValue(attr *ingresspb.CloudEvent_CloudEventAttributeValue) (interface{}, error) {
+	switch v := attr.GetAttr().(type) {
+	case *ingresspb.CloudEvent_CloudEventAttributeValue_CeBoolean:
+		return v.CeBoolean, nil
+	case *ingresspb.CloudEvent_CloudEventAttributeValue_CeInteger:
+		return v.CeInteger, nil
+	case *ingresspb.CloudEvent_CloudEventAttributeValue_CeString:
+		return v.CeString, nil
+	case *ingresspb.CloudEvent_CloudEventAttributeValue_CeBytes:
+		return v.CeBytes, nil
+	case *ingresspb.CloudEvent_CloudEventAttributeValue_CeUri:
+		u, err := types.ToURL(v.CeUri)
+		if err != nil {
+			return nil, err
+		}
+		return types.URI{URL: *u}, nil
+	case *ingresspb.CloudEvent_CloudEventAttributeValue_CeUriRef:
+		u, err := types.ToURL(v.CeUriRef)
+		if err != nil {
+			return nil, err
+		}
+		return types.URIRef{URL: *u}, nil
+	case *ingresspb.CloudEvent_CloudEventAttributeValue_CeTimestamp:
+		if err := v.CeTimestamp.CheckValid(); err != nil {
+			return nil, err
+		}
+		return v.CeTimestamp.AsTime(), nil
+	default:
+		return nil, errors.New("missing value")
+	}
+}
+
+// setAttribute sets an optional or extension attribute of the event.
+func setAttribute(e *cev2.Event, name string, value interface{}) error {
+	switch name {
+	case "datacontenttype":
+		s, err := types.ToString(value)
+		e.SetDataContentType(s)
+		return err
+	case "dataschema":
+		s, err := types.ToString(value)
+		e.SetDataSchema(s)
+		return err
+	case "subject":
+		s, err := types.ToString(value)
+		e.SetSubject(s)
+		return err
+	case "time":
+		t, err := types.ToTime(value)
+		e.SetTime(t)
+		return err
+	default:
+		return e.Context.SetExtension(name, value)
+	}
+}
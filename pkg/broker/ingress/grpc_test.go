@@ -0,0 +1,224 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"io"
+	"net"
+	nethttp "net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	authtesting "github.com/google/knative-gcp/pkg/broker/ingress/auth/testing"
+	"github.com/google/knative-gcp/pkg/broker/ingress/ingresspb"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func protoEvent(id string) *ingresspb.CloudEvent {
+	return &ingresspb.CloudEvent{
+		Id:          id,
+		Source:      "test-source",
+		SpecVersion: cev2.VersionV1,
+		Type:        eventType,
+		Data:        &ingresspb.CloudEvent_TextData{TextData: `{"key":"value"}`},
+	}
+}
+
+// startTestGRPC serves the gRPC Ingress service of the handler until the test ends, and opens a
+// Publish stream with ctx.
+func startTestGRPC(ctx context.Context, t *testing.T, h *Handler) ingresspb.Ingress_PublishClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	ingresspb.RegisterIngressServer(srv, &grpcServer{h: h})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	stream, err := ingresspb.NewIngressClient(conn).Publish(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stream
+}
+
+func TestGRPCPublish(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &fakeBatchDecoupleSink{results: map[string]protocol.Result{"2": ErrNotReady}}
+	h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(batchBrokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream := startTestGRPC(ctx, t, h)
+
+	invalid := protoEvent("3")
+	invalid.Source = ""
+	requests := []*ingresspb.PublishRequest{
+		{Broker: "/ns1/broker1", Event: protoEvent("1")},
+		{Broker: "/ns1/broker1", Event: protoEvent("2")},
+		{Broker: "/ns1/broker1", Event: invalid},
+		{Broker: "/ns1", Event: protoEvent("4")},
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]int32)
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[res.GetId()] = res.GetStatusCode()
+	}
+	want := map[string]int32{
+		"1": nethttp.StatusAccepted,
+		"2": nethttp.StatusServiceUnavailable,
+		"3": nethttp.StatusBadRequest,
+		"4": nethttp.StatusNotFound,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected status codes (-want, +got): %s", diff)
+	}
+
+	sort.Strings(sink.sent)
+	if diff := cmp.Diff([]string{"1", "2"}, sink.sent); diff != "" {
+		t.Errorf("Unexpected sent events (-want, +got): %s", diff)
+	}
+	if len(sink.untimed) > 0 {
+		t.Errorf("Events sent without a time: %v", sink.untimed)
+	}
+	wantEventCount := map[string]int64{
+		eventType + "/202":          1,
+		eventType + "/503":          1,
+		"_invalid_cloud_event_/400": 1,
+	}
+	if diff := cmp.Diff(wantEventCount, eventCountByCode()); diff != "" {
+		t.Errorf("Unexpected event_count (-want, +got): %s", diff)
+	}
+}
+
+func TestGRPCPublishRejectsEventsAfterTokenExpiry(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	signer := authtesting.NewRSASigner(t, "key")
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &fakeBatchDecoupleSink{}
+	h := NewHandler(context.Background(), nil, sink, statsReporter, "", newTestAuthenticator(t, signer), memory.NewTargets(authBrokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+	// The token expires, past the tolerated clock skew, a few seconds after the stream is opened.
+	exp := time.Now().Add(-time.Minute + 3*time.Second)
+	token := signer.Sign(t, testClaims("system:serviceaccount:ns1:sender", testAudience, exp))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	stream := startTestGRPC(ctx, t, h)
+
+	publish := func(id string) int32 {
+		if err := stream.Send(&ingresspb.PublishRequest{Broker: "/ns1/broker1", Event: protoEvent(id)}); err != nil {
+			t.Fatal(err)
+		}
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.GetStatusCode()
+	}
+	if got := publish("1"); got != nethttp.StatusAccepted {
+		t.Errorf("Unexpected status code of the event sent before the token expiry: %d", got)
+	}
+	time.Sleep(time.Until(exp.Add(time.Minute + time.Second)))
+	if got := publish("2"); got != nethttp.StatusUnauthorized {
+		t.Errorf("Unexpected status code of the event sent after the token expiry: %d", got)
+	}
+	if diff := cmp.Diff([]string{"1"}, sink.sent); diff != "" {
+		t.Errorf("Unexpected sent events (-want, +got): %s", diff)
+	}
+}
+
+func TestEventFromProto(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	pe := protoEvent("1")
+	pe.Attributes = map[string]*ingresspb.CloudEvent_CloudEventAttributeValue{
+		"subject": {Attr: &ingresspb.CloudEvent_CloudEventAttributeValue_CeString{CeString: "subject"}},
+		"time":    {Attr: &ingresspb.CloudEvent_CloudEventAttributeValue_CeTimestamp{CeTimestamp: timestamppb.New(ts)}},
+		"datacontenttype": {
+			Attr: &ingresspb.CloudEvent_CloudEventAttributeValue_CeString{CeString: cev2.ApplicationJSON},
+		},
+		"count": {Attr: &ingresspb.CloudEvent_CloudEventAttributeValue_CeInteger{CeInteger: 3}},
+	}
+	e, err := eventFromProto(pe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := cev2.NewEvent()
+	want.SetID("1")
+	want.SetSource("test-source")
+	want.SetType(eventType)
+	want.SetSubject("subject")
+	want.SetTime(ts)
+	want.SetExtension("count", 3)
+	want.SetDataContentType(cev2.ApplicationJSON)
+	want.DataEncoded = []byte(`{"key":"value"}`)
+	if diff := cmp.Diff(want.String(), e.String()); diff != "" {
+		t.Errorf("Unexpected event (-want, +got): %s", diff)
+	}
+
+	for name, pe := range map[string]*ingresspb.CloudEvent{
+		"missing event":       nil,
+		"unsupported version": {Id: "1", Source: "test-source", SpecVersion: "2.0", Type: eventType},
+		"missing type":        {Id: "1", Source: "test-source", SpecVersion: cev2.VersionV1},
+		"missing attr value":  {Id: "1", Source: "test-source", SpecVersion: cev2.VersionV1, Type: eventType, Attributes: map[string]*ingresspb.CloudEvent_CloudEventAttributeValue{"ext": {}}},
+		"invalid time value":  {Id: "1", Source: "test-source", SpecVersion: cev2.VersionV1, Type: eventType, Attributes: map[string]*ingresspb.CloudEvent_CloudEventAttributeValue{"time": {Attr: &ingresspb.CloudEvent_CloudEventAttributeValue_CeBoolean{CeBoolean: true}}}},
+	} {
+		if _, err := eventFromProto(pe); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -22,7 +22,11 @@ import (
 	nethttp "net/http"
 	"time"
 
+	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/broker/claimcheck"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
@@ -33,25 +37,28 @@ import (
 	"github.com/google/wire"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/support/bundler"
 	grpccode "google.golang.org/grpc/codes"
 	grpcstatus "google.golang.org/grpc/status"
 	"knative.dev/eventing/pkg/kncloudevents"
 	kntracing "knative.dev/eventing/pkg/tracing"
-	"knative.dev/pkg/metrics/metricskey"
 
 	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/tracing"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	"github.com/google/knative-gcp/pkg/utils/clients"
+	"github.com/google/knative-gcp/pkg/utils/eventbatch"
 )
 
 const (
-	// TODO(liu-cong) configurable timeout
-	decoupleSinkTimeout = 30 * time.Second
+	// defaultDecoupleSinkTimeout is how long the handler waits for an event to be published if the
+	// publish settings don't set a timeout.
+	defaultDecoupleSinkTimeout = 30 * time.Second
 
-	// Limit for request payload in bytes (10Mb -- corresponds to message size limit on PubSub as of 09/2020)
+	// Limit for request payload in bytes (10Mb -- corresponds to message size limit on PubSub as of 09/2020),
+	// unless large events are offloaded to Cloud Storage.
 	maxRequestBodyBytes = 10000000
 
 	// EventArrivalTime is used to access the metadata stored on a
@@ -66,15 +73,18 @@ const (
 Please refer to "Configure the Authentication Mechanism for GCP" at https://github.com/google/knative-gcp/blob/main/docs/install/install-gcp-broker.md`
 )
 
-// HandlerSet provides a handler with a real HTTPMessageReceiver and pubsub MultiTopicDecoupleSink.
+// HandlerSet provides a handler with a real HTTPMessageReceiver and a MultiTopicDecoupleSink backed by
+// the queue backend of the queue.Settings.
 var HandlerSet wire.ProviderSet = wire.NewSet(
 	NewHandler,
 	clients.NewHTTPMessageReceiverWithChecker,
 	wire.Bind(new(HttpMessageReceiver), new(*kncloudevents.HTTPMessageReceiver)),
 	NewMultiTopicDecoupleSink,
 	wire.Bind(new(DecoupleSink), new(*multiTopicDecoupleSink)),
-	clients.NewPubsubClient,
+	queue.NewClient,
 	metrics.NewIngressReporter,
+	NewAuthenticator,
+	NewQuotas,
 )
 
 // DecoupleSink is an interface to send events to a decoupling sink (e.g., pubsub).
@@ -83,6 +93,12 @@ type DecoupleSink interface {
 	Send(ctx context.Context, broker *config.CellTenantKey, event cev2.Event) protocol.Result
 }
 
+// TargetsReloadedNotifiee is implemented by the DecoupleSinks that keep state about the targets.
+type TargetsReloadedNotifiee interface {
+	// TargetsReloaded is called after the targets config is reloaded.
+	TargetsReloaded()
+}
+
 // HttpMessageReceiver is an interface to listen on http requests.
 type HttpMessageReceiver interface {
 	StartListen(ctx context.Context, handler nethttp.Handler) error
@@ -97,44 +113,99 @@ type Handler struct {
 	logger   *zap.Logger
 	reporter *metrics.IngressReporter
 	authType authcheck.AuthType
+	// authenticator authenticates the requests sent to brokers that require it. If nil, any
+	// request is accepted.
+	authenticator *Authenticator
+	// brokerConfig holds configurations for all brokers. It's a view of a configmap populated by
+	// the broker controller.
+	brokerConfig config.ReadonlyTargets
+	// schemas caches the compiled event schemas of the brokers.
+	schemas schemaCache
+	// claimCheck offloads the data of large events to Cloud Storage. If nil, events are not
+	// offloaded.
+	claimCheck *ClaimCheck
+	// decoupleSinkTimeout is how long the handler waits for an event to be published.
+	decoupleSinkTimeout time.Duration
+	// streaming configures the streaming protocols served in addition to HTTP.
+	streaming StreamingOptions
+	// quotas enforces the ingress quotas of the brokers and of their namespaces. If nil, events
+	// are not limited.
+	quotas *Quotas
 }
 
 // NewHandler creates a new ingress handler.
-func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, reporter *metrics.IngressReporter, authType authcheck.AuthType) *Handler {
+func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, reporter *metrics.IngressReporter, authType authcheck.AuthType, authenticator *Authenticator, brokerConfig config.ReadonlyTargets, claimCheck *ClaimCheck, quotas *Quotas, publishSettings pubsub.PublishSettings, streaming StreamingOptions) *Handler {
+	timeout := publishSettings.Timeout
+	if timeout <= 0 {
+		timeout = defaultDecoupleSinkTimeout
+	}
 	return &Handler{
-		httpReceiver: httpReceiver,
-		decouple:     decouple,
-		reporter:     reporter,
-		logger:       logging.FromContext(ctx),
-		authType:     authType,
+		httpReceiver:  httpReceiver,
+		decouple:      decouple,
+		reporter:      reporter,
+		logger:        logging.FromContext(ctx),
+		authType:      authType,
+		authenticator: authenticator,
+		brokerConfig:  brokerConfig,
+		claimCheck:    claimCheck,
+		quotas:        quotas,
+
+		decoupleSinkTimeout: timeout,
+		streaming:           streaming,
 	}
 }
 
-// Start blocks to receive events over HTTP.
+// TargetsReloaded releases the state kept about the brokers and targets that were removed from
+// the targets config. It should be called after the targets config is reloaded.
+func (h *Handler) TargetsReloaded() {
+	if n, ok := h.decouple.(TargetsReloadedNotifiee); ok {
+		n.TargetsReloaded()
+	}
+	h.quotas.TargetsReloaded()
+}
+
+// Start blocks to receive events over HTTP, and over gRPC if a gRPC port is set.
 func (h *Handler) Start(ctx context.Context) error {
-	return h.httpReceiver.StartListen(ctx, h)
+	if h.streaming.GRPCPort == 0 {
+		return h.httpReceiver.StartListen(ctx, h)
+	}
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return h.startGRPC(ctx)
+	})
+	g.Go(func() error {
+		return h.httpReceiver.StartListen(ctx, h)
+	})
+	return g.Wait()
 }
 
 // ServeHTTP implements net/http Handler interface method.
-// 1. Performs basic validation of the request.
-// 2. Parse request URL to get namespace and broker.
-// 3. Convert request to event.
-// 4. Send event to decouple sink.
+//  1. Performs basic validation of the request.
+//  2. Parse request URL to get namespace and broker.
+//  3. Authenticate the request if the broker requires it.
+//  4. Convert request to event, or to events if it is a batch request. WebSocket handshakes are
+//     served by serveWebSocket, which sends each event of the connection through the next steps.
+//  5. Validate the events against their schema if the broker enforces schemas.
+//  6. Check the events against the quotas of the broker and of its namespace.
+//  7. Offload the data of large events to Cloud Storage if claim check is enabled.
+//  8. Send events to decouple sink.
 func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
 	ctx := request.Context()
 	ctx = logging.WithLogger(ctx, h.logger)
 	ctx = tracing.WithLogging(ctx, trace.FromContext(ctx))
 	logging.FromContext(ctx).Debug("Serving http", zap.Any("headers", request.Header))
-	if request.Method != nethttp.MethodPost {
+	webSocket := h.streaming.WebSocket && isWebSocketRequest(request)
+	if request.Method != nethttp.MethodPost && !webSocket {
 		response.WriteHeader(nethttp.StatusMethodNotAllowed)
 		return
 	}
 
-	if request.ContentLength > maxRequestBodyBytes {
+	maxBytes := h.maxRequestBytes()
+	if request.ContentLength > maxBytes {
 		response.WriteHeader(nethttp.StatusRequestEntityTooLarge)
 		return
 	}
-	request.Body = nethttp.MaxBytesReader(nil, request.Body, maxRequestBodyBytes)
+	request.Body = nethttp.MaxBytesReader(nil, request.Body, maxBytes)
 
 	broker, err := config.CellTenantKeyFromPersistenceString(request.URL.Path)
 	if err != nil {
@@ -142,8 +213,25 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 		nethttp.Error(response, err.Error(), nethttp.StatusNotFound)
 		return
 	}
-	ctx = logging.With(ctx, zap.Stringer("broker", broker))
-	ctx = metricskey.WithResource(ctx, broker.MetricsResource())
+	ctx = withBroker(ctx, broker)
+
+	if err := h.authenticate(ctx, broker, request.Header.Get("Authorization")); err != nil {
+		if err.StatusCode == nethttp.StatusUnauthorized {
+			response.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		}
+		nethttp.Error(response, err.Error(), err.StatusCode)
+		return
+	}
+
+	if webSocket {
+		h.serveWebSocket(ctx, response, request, broker)
+		return
+	}
+
+	if eventbatch.IsRequest(request) {
+		h.serveBatch(ctx, response, request, broker)
+		return
+	}
 
 	event, err := h.toEvent(ctx, request)
 	if err != nil {
@@ -156,10 +244,35 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 		return
 	}
 
-	event.SetExtension(EventArrivalTime, cev2.Timestamp{Time: time.Now()})
-
 	span := trace.FromContext(ctx)
 	span.SetName(broker.SpanMessagingDestination())
+	addSpanAttributes(span, broker, event)
+
+	if statusCode, errMsg := h.send(ctx, broker, event); statusCode != nethttp.StatusAccepted {
+		nethttp.Error(response, errMsg, statusCode)
+		return
+	}
+	// According to the data plane spec (https://github.com/knative/eventing/blob/master/docs/spec/data-plane.md), a
+	// non-callable SINK (which broker is) MUST respond with 202 Accepted if the request is accepted.
+	response.WriteHeader(nethttp.StatusAccepted)
+}
+
+// authenticate authenticates the request if the broker requires it, and reports the rejected
+// requests.
+func (h *Handler) authenticate(ctx context.Context, broker *config.CellTenantKey, authorization string) *AuthError {
+	if h.authenticator == nil {
+		return nil
+	}
+	err := h.authenticator.Authenticate(ctx, broker, authorization)
+	if err != nil {
+		logging.FromContext(ctx).Debug("Rejected request", zap.String("reason", err.Reason), zap.Error(err))
+		h.reporter.ReportRejectedRequest(ctx, err.Reason)
+	}
+	return err
+}
+
+// addSpanAttributes adds the attributes of the event sent to the broker to the span.
+func addSpanAttributes(span *trace.Span, broker *config.CellTenantKey, event *cev2.Event) {
 	if span.IsRecordingEvents() {
 		span.AddAttributes(
 			append(
@@ -171,16 +284,45 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 			)...,
 		)
 	}
+}
+
+// send sends the event to the decouple sink and reports its metrics. It returns the status code
+// of the event, and an error message if the event is not accepted.
+func (h *Handler) send(ctx context.Context, broker *config.CellTenantKey, event *cev2.Event) (int, string) {
+	event.SetExtension(EventArrivalTime, cev2.Timestamp{Time: time.Now()})
 
 	// Optimistically set status code to StatusAccepted. It will be updated if there is an error.
-	// According to the data plane spec (https://github.com/knative/eventing/blob/master/docs/spec/data-plane.md), a
-	// non-callable SINK (which broker is) MUST respond with 202 Accepted if the request is accepted.
 	statusCode := nethttp.StatusAccepted
-	ctx, cancel := context.WithTimeout(ctx, decoupleSinkTimeout)
+	ctx, cancel := context.WithTimeout(ctx, h.decoupleSinkTimeout)
 	defer cancel()
 	defer func() { h.reportMetrics(ctx, event.Type(), statusCode) }()
+	if err := h.validateSchema(ctx, broker, event); err != nil {
+		statusCode = nethttp.StatusBadRequest
+		return statusCode, err.Error()
+	}
+	release, quotaErr := h.quotas.Acquire(ctx, broker, int64(len(event.Data())))
+	if quotaErr != nil {
+		logging.FromContext(ctx).Debug("Rejected event", zap.String("reason", quotaErr.Reason), zap.Error(quotaErr))
+		statusCode = nethttp.StatusTooManyRequests
+		h.reporter.ReportRejectedRequest(ctx, quotaErr.Reason)
+		return statusCode, quotaErr.Error()
+	}
+	defer release()
+	offloadedFor, err := h.offload(ctx, broker, event)
+	if err != nil {
+		statusCode = nethttp.StatusInternalServerError
+		if errors.Is(err, claimcheck.ErrInvalidReference) {
+			statusCode = nethttp.StatusBadRequest
+			return statusCode, err.Error()
+		}
+		logging.FromContext(ctx).Error("Error offloading event data", zap.Error(err))
+		return statusCode, "Failed to offload event data"
+	}
 	if res := h.decouple.Send(ctx, broker, *event); !cev2.IsACK(res) {
 		logging.FromContext(ctx).Error("Error publishing to PubSub", zap.Error(res))
+		if offloadedFor != "" {
+			h.deleteOffloaded(ctx, offloadedFor, event)
+		}
 		statusCode = nethttp.StatusInternalServerError
 
 		switch {
@@ -188,17 +330,26 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 			statusCode = nethttp.StatusNotFound
 		case errors.Is(res, ErrNotReady):
 			statusCode = nethttp.StatusServiceUnavailable
+		case errors.Is(res, ErrDuplicatePending):
+			// The producer retries, and the retry is accepted once the first event is published,
+			// or published if it fails.
+			statusCode = nethttp.StatusServiceUnavailable
+			return statusCode, "Duplicate of an event being published"
 		case errors.Is(res, bundler.ErrOverflow):
 			statusCode = nethttp.StatusTooManyRequests
+			h.reporter.ReportRejectedRequest(ctx, reasonPublisherOverflow)
+			return statusCode, reasonPublisherOverflow + ": Failed to publish to PubSub because the publisher is overloaded"
 		case grpcstatus.Code(res) == grpccode.PermissionDenied:
-			nethttp.Error(response, deniedErrMsg, statusCode)
-			return
+			return statusCode, deniedErrMsg
+		}
+		return statusCode, "Failed to publish to PubSub"
+	} else if errors.Is(res, ErrDuplicate) {
+		h.reporter.ReportDuplicateEvent(ctx, event.Type())
+		if offloadedFor != "" {
+			h.deleteOffloaded(ctx, offloadedFor, event)
 		}
-		nethttp.Error(response, "Failed to publish to PubSub", statusCode)
-		return
 	}
-
-	response.WriteHeader(statusCode)
+	return statusCode, ""
 }
 
 // toEvent converts an http request to an event.
@@ -40,6 +40,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 	kgcptesting "github.com/google/knative-gcp/pkg/testing"
@@ -331,7 +332,7 @@ func TestHandler(t *testing.T) {
 
 			decouple := tc.decouple
 			if decouple == nil {
-				decouple = NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), createPubsubClient(ctx, t, psSrv), pubsub.DefaultPublishSettings)
+				decouple = NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), queue.NewPubsubClient(createPubsubClient(ctx, t, psSrv)), pubsub.DefaultPublishSettings, nil)
 			}
 
 			url := createAndStartIngress(ctx, t, psSrv, decouple)
@@ -409,12 +410,12 @@ func runIngressHandlerBenchmark(b *testing.B, eventSize int, targetCounts int) {
 	setBrokerConfigTargets(targetCounts)
 	defer restoreBrokerConfigTargets()
 
-	decouple := NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), psClient, pubsub.DefaultPublishSettings)
+	decouple := NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, nil)
 	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
 	if err != nil {
 		b.Fatal(err)
 	}
-	h := NewHandler(ctx, nil, decouple, statsReporter, "")
+	h := NewHandler(ctx, nil, decouple, statsReporter, "", nil, memory.NewTargets(brokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
 
 	if _, err := psClient.CreateTopic(ctx, topicID); err != nil {
 		b.Fatal(err)
@@ -522,7 +523,7 @@ func createAndStartIngress(ctx context.Context, t testing.TB, psSrv *pstest.Serv
 	if err != nil {
 		t.Fatal(err)
 	}
-	h := NewHandler(ctx, receiver, decouple, statsReporter, "")
+	h := NewHandler(ctx, receiver, decouple, statsReporter, "", nil, memory.NewTargets(brokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -629,3 +630,102 @@ func (recv *testHttpMessageReceiver) StartListen(ctx context.Context, handler ne
 	}
 	return nil
 }
+
+type fakeDuplicateDecoupleSink struct{}
+
+func (m *fakeDuplicateDecoupleSink) Send(_ context.Context, _ *config.CellTenantKey, _ cev2.Event) protocol.Result {
+	return protocol.NewReceipt(true, "%w", ErrDuplicate)
+}
+
+func TestHandlerReportsDuplicateEvent(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(context.Background(), nil, &fakeDuplicateDecoupleSink{}, statsReporter, "", nil, memory.NewTargets(brokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+	req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", nil)
+	http.WriteRequest(context.Background(), binding.ToMessage(createTestEvent("test-event")), req)
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+
+	if res.Code != nethttp.StatusAccepted {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, nethttp.StatusAccepted)
+	}
+	metricstest.CheckCountData(t, "duplicate_event_count", map[string]string{
+		metricskey.LabelEventType: eventType,
+		metricskey.PodName:        pod,
+		metricskey.ContainerName:  container,
+	}, 1)
+}
+
+func TestHandlerRejectsDuplicateOfPendingEvent(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &fakeBatchDecoupleSink{results: map[string]protocol.Result{"test-event": ErrDuplicatePending}}
+	h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(brokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+	req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", nil)
+	http.WriteRequest(context.Background(), binding.ToMessage(createTestEvent("test-event")), req)
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+
+	// The producer retries, and the retry is accepted once the first event is published.
+	if res.Code != nethttp.StatusServiceUnavailable {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, nethttp.StatusServiceUnavailable)
+	}
+}
+
+// fakeDeadlineDecoupleSink records the deadline of the context of the events sent to it.
+type fakeDeadlineDecoupleSink struct {
+	timeout time.Duration
+}
+
+func (m *fakeDeadlineDecoupleSink) Send(ctx context.Context, _ *config.CellTenantKey, _ cev2.Event) protocol.Result {
+	if deadline, ok := ctx.Deadline(); ok {
+		m.timeout = time.Until(deadline)
+	}
+	return protocol.ResultACK
+}
+
+func TestHandlerPublishTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		wantTimeout time.Duration
+	}{{
+		name:        "default timeout",
+		wantTimeout: defaultDecoupleSinkTimeout,
+	}, {
+		name:        "publish settings timeout",
+		timeout:     time.Minute,
+		wantTimeout: time.Minute,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink := &fakeDeadlineDecoupleSink{}
+			h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(brokerConfig), nil, nil, pubsub.PublishSettings{Timeout: tc.timeout}, StreamingOptions{})
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", nil)
+			http.WriteRequest(context.Background(), binding.ToMessage(createTestEvent("test-event")), req)
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+
+			if res.Code != nethttp.StatusAccepted {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, nethttp.StatusAccepted)
+			}
+			if sink.timeout > tc.wantTimeout || sink.timeout < tc.wantTimeout-5*time.Second {
+				t.Errorf("got publish timeout %v, want %v", sink.timeout, tc.wantTimeout)
+			}
+		})
+	}
+}
@@ -21,58 +21,77 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
-	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/queue"
 	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/utils/dedup"
+	"github.com/google/knative-gcp/pkg/utils/eventbatch"
 )
 
-const projectEnvKey = "PROJECT_ID"
+const (
+	projectEnvKey = "PROJECT_ID"
+
+	// dedupPendingTTL bounds the time an event is pending in the dedup store, so that its
+	// duplicates are not rejected for the whole dedup window if the ingress stops before the
+	// publish completes.
+	dedupPendingTTL = time.Minute
+	// dedupStoreTimeout is the timeout to update the dedup store after an event is published.
+	dedupStoreTimeout = 5 * time.Second
+)
 
 // NewMultiTopicDecoupleSink creates a new multiTopicDecoupleSink.
 func NewMultiTopicDecoupleSink(
 	ctx context.Context,
 	brokerConfig config.ReadonlyTargets,
-	client *pubsub.Client,
-	publishSettings pubsub.PublishSettings) *multiTopicDecoupleSink {
+	client queue.Client,
+	publishSettings pubsub.PublishSettings,
+	dedupStore dedup.Store) *multiTopicDecoupleSink {
 
 	return &multiTopicDecoupleSink{
-		pubsub:          client,
+		queue:           client,
 		publishSettings: publishSettings,
 		brokerConfig:    brokerConfig,
+		dedupStore:      dedupStore,
 		// TODO(#1118): remove Topic when broker config is removed
-		topics: make(map[config.CellTenantKey]*pubsub.Topic),
+		topics: make(map[config.CellTenantKey]*brokerTopic),
 		// TODO(#1804): remove this field when enabling the feature by default.
 		enableEventFiltering: enableEventFilterFunc(),
 	}
 }
 
-// multiTopicDecoupleSink implements DecoupleSink and routes events to pubsub topics corresponding
+// multiTopicDecoupleSink implements DecoupleSink and routes events to queue topics corresponding
 // to the broker to which the events are sent.
 type multiTopicDecoupleSink struct {
-	// pubsub talks to pubsub.
-	pubsub          *pubsub.Client
+	// queue talks to the decouple queue backend, Pub/Sub in production.
+	queue           queue.Client
 	publishSettings pubsub.PublishSettings
 	// map from brokers to topics
-	topics    map[config.CellTenantKey]*pubsub.Topic
+	topics    map[config.CellTenantKey]*brokerTopic
 	topicsMut sync.RWMutex
 	// brokerConfig holds configurations for all brokers. It's a view of a configmap populated by
 	// the broker controller.
 	brokerConfig config.ReadonlyTargets
 	// TODO(#1804): remove this field when enabling the feature by default.
 	enableEventFiltering bool
+	// filters caches the compiled filters of the targets.
+	filters filter.Cache
+	// dedupStore records the events accepted by the brokers that deduplicate events. If nil,
+	// events are not deduplicated.
+	dedupStore dedup.Store
 }
 
-// Send sends incoming event to its corresponding pubsub topic based on which broker it belongs to.
+// Send sends incoming event to its corresponding decouple topic based on which broker it belongs to.
 func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker *config.CellTenantKey, event cev2.Event) protocol.Result {
 	topic, err := m.getTopicForBroker(ctx, broker)
 	if err != nil {
@@ -93,19 +112,90 @@ func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker *config.CellTe
 		return nil
 	}
 
-	dt := extensions.FromSpanContext(trace.FromContext(ctx).SpanContext())
-	msg := new(pubsub.Message)
-	if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(&event), msg, dt.WriteTransformer()); err != nil {
-		return err
+	dedupKey, dedupEntry := m.checkDuplicate(ctx, broker, &event)
+	switch dedupEntry.State {
+	case dedup.Published:
+		logging.FromContext(ctx).Debug("Accepting duplicate event without publishing it", zap.String("Eventid", event.ID()))
+		return protocol.NewReceipt(true, "%w", ErrDuplicate)
+	case dedup.Pending:
+		logging.FromContext(ctx).Debug("Rejecting duplicate of an event being published", zap.String("Eventid", event.ID()))
+		return ErrDuplicatePending
 	}
 
-	_, err = topic.Publish(ctx, msg).Get(ctx)
+	dt := extensions.FromSpanContext(trace.FromContext(ctx).SpanContext())
+	msg, err := queue.NewEventMessage(ctx, binding.ToMessage(&event), dt.WriteTransformer())
+	var messageID string
+	if err == nil {
+		msg.OrderingKey = orderingKey(m.brokerConfig, broker, &event)
+		messageID, err = topic.Publish(ctx, msg)
+	}
+	if dedupKey != "" {
+		m.settleDuplicate(ctx, broker, dedupKey, dedupEntry.Token, &event, messageID, err)
+	}
 	return err
 }
 
+// checkDuplicate records the event as pending in the dedup store if the broker deduplicates
+// events. It returns the key of the event in the store, and its entry: Added if the event is not
+// a duplicate of an event accepted within the broker's dedup window. Events are not
+// deduplicated if the store fails, so that they are never lost.
+func (m *multiTopicDecoupleSink) checkDuplicate(ctx context.Context, broker *config.CellTenantKey, event *cev2.Event) (string, dedup.Entry) {
+	if m.dedupStore == nil {
+		return "", dedup.Entry{State: dedup.Added}
+	}
+	brokerConfig, ok := m.brokerConfig.GetCellTenantByKey(broker)
+	if !ok || brokerConfig.DedupWindow == nil {
+		return "", dedup.Entry{State: dedup.Added}
+	}
+	ttl := brokerConfig.DedupWindow.AsDuration()
+	if ttl > dedupPendingTTL {
+		ttl = dedupPendingTTL
+	}
+	key := dedup.Key(brokerConfig.Id, event.Source(), event.ID())
+	entry, err := m.dedupStore.Add(ctx, key, ttl)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to add event to dedup store", zap.String("Eventid", event.ID()), zap.Error(err))
+		return "", dedup.Entry{State: dedup.Added}
+	}
+	return key, entry
+}
+
+// settleDuplicate marks the event as published in the dedup store, so that its duplicates are
+// accepted for the broker's dedup window, or removes it if it failed to be published, so that it
+// is not considered a duplicate when it is sent again. The store is updated even if the request
+// context is done, which may be why the publish failed. token is the token of the Added entry.
+func (m *multiTopicDecoupleSink) settleDuplicate(ctx context.Context, broker *config.CellTenantKey, key, token string, event *cev2.Event, messageID string, publishErr error) {
+	storeCtx, cancel := context.WithTimeout(context.Background(), dedupStoreTimeout)
+	defer cancel()
+	if publishErr != nil {
+		if err := m.dedupStore.Remove(storeCtx, key, token); err != nil {
+			logging.FromContext(ctx).Warn("Failed to remove event from dedup store", zap.String("Eventid", event.ID()), zap.Error(err))
+		}
+		return
+	}
+	brokerConfig, ok := m.brokerConfig.GetCellTenantByKey(broker)
+	if !ok || brokerConfig.DedupWindow == nil {
+		return
+	}
+	if err := m.dedupStore.MarkPublished(storeCtx, key, messageID, brokerConfig.DedupWindow.AsDuration()); err != nil {
+		logging.FromContext(ctx).Warn("Failed to mark event as published in dedup store", zap.String("Eventid", event.ID()), zap.Error(err))
+	}
+}
+
+// orderingKey returns the ordering key of the event, which is the value of the broker's
+// ordering key attribute. If the broker does not enable ordered delivery, or the event does not
+// have the attribute, it returns an empty string and the event is not ordered.
+func orderingKey(targets config.ReadonlyTargets, broker *config.CellTenantKey, event *cev2.Event) string {
+	brokerConfig, ok := targets.GetCellTenantByKey(broker)
+	if !ok {
+		return ""
+	}
+	return eventbatch.OrderingKey(event, brokerConfig.OrderingKeyAttribute)
+}
+
 // eventFilterFunc is used to see if a target is interested in an event.
 // It is used as a vaiable to allow stubbing out in unit tests.
-var eventFilterFunc = filter.PassFilter
+var eventFilterFunc = filter.PassTarget
 
 // enableEventFilterFunc is a temporary function to control enabling and
 // disabling trigger-less event filtering in ingress.
@@ -122,7 +212,7 @@ func isEventFilteringEnabled() bool {
 func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, event *cev2.Event) bool {
 	hasTrigger := false
 	m.brokerConfig.RangeAllTargets(func(target *config.Target) bool {
-		if eventFilterFunc(ctx, target.FilterAttributes, event) {
+		if eventFilterFunc(ctx, &m.filters, target, event) {
 			hasTrigger = true
 			return false
 		}
@@ -133,16 +223,22 @@ func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, event *cev2.Eve
 	return hasTrigger
 }
 
+// TargetsReloaded evicts the compiled filters of the targets that were deleted or replaced when
+// the targets config was reloaded.
+func (m *multiTopicDecoupleSink) TargetsReloaded() {
+	m.filters.Prune(m.brokerConfig)
+}
+
 // getTopicForBroker finds the corresponding decouple topic for the broker from the mounted broker configmap volume.
-func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (*pubsub.Topic, error) {
-	topicID, err := m.getTopicIDForBroker(ctx, broker)
+func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (queue.Topic, error) {
+	decoupleQueue, err := m.getQueueForBroker(ctx, broker)
 	if err != nil {
 		return nil, err
 	}
 
 	if topic, ok := m.getExistingTopic(broker); ok {
-		// Check that the broker's topic ID hasn't changed.
-		if topic.ID() == topicID {
+		// Check that the broker's topic ID, project and ordering haven't changed.
+		if topic.matches(decoupleQueue, m.isOrdered(broker)) {
 			return topic, nil
 		}
 	}
@@ -151,52 +247,78 @@ func (m *multiTopicDecoupleSink) getTopicForBroker(ctx context.Context, broker *
 	return m.updateTopicForBroker(ctx, broker)
 }
 
-func (m *multiTopicDecoupleSink) updateTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (*pubsub.Topic, error) {
+func (m *multiTopicDecoupleSink) updateTopicForBroker(ctx context.Context, broker *config.CellTenantKey) (queue.Topic, error) {
 	m.topicsMut.Lock()
 	defer m.topicsMut.Unlock()
-	// Fetch latest decouple topic ID under lock.
-	topicID, err := m.getTopicIDForBroker(ctx, broker)
+	// Fetch latest decouple queue under lock.
+	decoupleQueue, err := m.getQueueForBroker(ctx, broker)
 	if err != nil {
 		return nil, err
 	}
 
+	ordered := m.isOrdered(broker)
 	if topic, ok := m.topics[*broker]; ok {
-		if topic.ID() == topicID {
+		if topic.matches(decoupleQueue, ordered) {
 			// Topic already updated.
 			return topic, nil
 		}
 		// Stop old topic.
 		m.topics[*broker].Stop()
+		delete(m.topics, *broker)
+	}
+	client, err := m.queue.InProject(decoupleQueue.ProjectId)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create the queue client of the project", zap.String("project", decoupleQueue.ProjectId), zap.Error(err))
+		return nil, fmt.Errorf("client of project %q: %w", decoupleQueue.ProjectId, err)
+	}
+	topic := &brokerTopic{
+		Topic:   client.Topic(decoupleQueue.Topic, m.publishSettings, ordered),
+		project: decoupleQueue.ProjectId,
 	}
-	topic := m.pubsub.Topic(topicID)
-	topic.PublishSettings = m.publishSettings
 	m.topics[*broker] = topic
 	return topic, nil
 }
 
-func (m *multiTopicDecoupleSink) getTopicIDForBroker(ctx context.Context, broker *config.CellTenantKey) (string, error) {
+func (m *multiTopicDecoupleSink) getQueueForBroker(ctx context.Context, broker *config.CellTenantKey) (*config.Queue, error) {
 	brokerConfig, ok := m.brokerConfig.GetCellTenantByKey(broker)
 	if !ok {
 		// There is an propagation delay between the controller reconciles the broker config and
 		// the config being pushed to the configmap volume in the ingress pod. So sometimes we return
 		// an error even if the request is valid.
 		logging.FromContext(ctx).Warn("config is not found for")
-		return "", fmt.Errorf("%q: %w", broker, ErrNotFound)
+		return nil, fmt.Errorf("%q: %w", broker, ErrNotFound)
 	}
 	if brokerConfig.DecoupleQueue == nil || brokerConfig.DecoupleQueue.Topic == "" {
 		logging.FromContext(ctx).Error("DecoupleQueue or topic missing for broker, this should NOT happen.", zap.Any("brokerConfig", brokerConfig))
-		return "", fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
+		return nil, fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
 	}
 	if brokerConfig.DecoupleQueue.State != config.State_READY {
 		logging.FromContext(ctx).Debug("decouple queue is not ready")
-		return "", fmt.Errorf("%q: %w", broker, ErrNotReady)
+		return nil, fmt.Errorf("%q: %w", broker, ErrNotReady)
 	}
-	return brokerConfig.DecoupleQueue.Topic, nil
+	return brokerConfig.DecoupleQueue, nil
+}
+
+// isOrdered returns true if the broker enables ordered delivery.
+func (m *multiTopicDecoupleSink) isOrdered(broker *config.CellTenantKey) bool {
+	brokerConfig, ok := m.brokerConfig.GetCellTenantByKey(broker)
+	return ok && brokerConfig.OrderingKeyAttribute != ""
 }
 
-func (m *multiTopicDecoupleSink) getExistingTopic(broker *config.CellTenantKey) (*pubsub.Topic, bool) {
+func (m *multiTopicDecoupleSink) getExistingTopic(broker *config.CellTenantKey) (*brokerTopic, bool) {
 	m.topicsMut.RLock()
 	defer m.topicsMut.RUnlock()
 	topic, ok := m.topics[*broker]
 	return topic, ok
 }
+
+// brokerTopic is the decouple topic of a broker in the project of the broker.
+type brokerTopic struct {
+	queue.Topic
+	project string
+}
+
+// matches returns true if the topic is the given decouple queue with the given ordering.
+func (t *brokerTopic) matches(q *config.Queue, ordered bool) bool {
+	return t.ID() == q.Topic && t.project == q.ProjectId && t.Ordered() == ordered
+}
@@ -18,8 +18,10 @@ package ingress
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
@@ -28,9 +30,16 @@ import (
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/broker/queue"
+	"github.com/google/knative-gcp/pkg/utils/dedup"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
 	logtest "knative.dev/pkg/logging/testing"
 )
 
@@ -206,7 +215,7 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, pubsub.DefaultPublishSettings)
+				sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, nil)
 				// Send events
 				event := createTestEvent(uuid.New().String())
 				err = sink.Send(context.Background(), testCase.broker, *event)
@@ -417,7 +426,7 @@ func TestMultiTopicDecoupleSinkWithoutIngressFiltering(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, pubsub.DefaultPublishSettings)
+				sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, nil)
 				// Send events
 				event := createTestEvent(uuid.New().String())
 				err = sink.Send(context.Background(), testCase.broker, *event)
@@ -556,6 +565,39 @@ func TestHasTrigger(t *testing.T) {
 			},
 			hasTrigger: false,
 		},
+		{
+			name: "broker with target with matching advanced filter",
+			brokerTargets: map[string]*config.Target{
+				"target_1": {
+					CellTenantType: config.CellTenantType_BROKER,
+					Filters: []*config.Filter{{
+						Expression: &config.Filter_Prefix{Prefix: &config.AttributesFilter{
+							Attributes: map[string]string{"source": "test-"},
+						}},
+					}},
+				},
+			},
+			hasTrigger: true,
+		},
+		{
+			name: "broker with target with matching filter attributes and non-matching advanced filter",
+			brokerTargets: map[string]*config.Target{
+				"target_1": {
+					CellTenantType: config.CellTenantType_BROKER,
+					FilterAttributes: map[string]string{
+						"type": eventType,
+					},
+					Filters: []*config.Filter{{
+						Expression: &config.Filter_Not{Not: &config.Filter{
+							Expression: &config.Filter_Exact{Exact: &config.AttributesFilter{
+								Attributes: map[string]string{"source": "test-source"},
+							}},
+						}},
+					}},
+				},
+			},
+			hasTrigger: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -575,7 +617,7 @@ func TestHasTrigger(t *testing.T) {
 			}
 
 			brokerConfig := memory.NewTargets(testBrokerConfig)
-			sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, pubsub.DefaultPublishSettings)
+			sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, nil)
 
 			event := createTestEvent(uuid.New().String())
 
@@ -587,6 +629,41 @@ func TestHasTrigger(t *testing.T) {
 	}
 }
 
+func TestMultiTopicDecoupleSinkTargetsReloaded(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	brokerConfig := memory.NewEmptyTargets()
+	broker := config.TestOnlyBrokerKey("test_ns_1", "test_broker_1")
+	brokerConfig.MutateCellTenant(broker, func(m config.CellTenantMutation) {
+		m.UpsertTargets(&config.Target{
+			Name:             "non_matching_target",
+			FilterAttributes: map[string]string{"source": "some-random-source"},
+		})
+	})
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, nil, pubsub.DefaultPublishSettings, nil)
+	event := createTestEvent(uuid.New().String())
+
+	var target *config.Target
+	brokerConfig.RangeAllTargets(func(t *config.Target) bool {
+		target = t
+		return false
+	})
+	sink.hasTrigger(ctx, event)
+	matcher := sink.filters.Get(ctx, target)
+
+	brokerConfig.MutateCellTenant(broker, func(m config.CellTenantMutation) {
+		m.DeleteTargets(target)
+	})
+	// Checking events doesn't prune the filters.
+	sink.hasTrigger(ctx, event)
+	if got := sink.filters.Get(ctx, target); got != matcher {
+		t.Error("the filters of the deleted target were evicted before the targets config was reloaded")
+	}
+	sink.TargetsReloaded()
+	if got := sink.filters.Get(ctx, target); got == matcher {
+		t.Error("the filters of the deleted target were not evicted after the targets config was reloaded")
+	}
+}
+
 func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 	// TODO(#1804): remove this mock when enabling the feature by default.
 	origEnableEventFilterFunc := enableEventFilterFunc
@@ -598,7 +675,7 @@ func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(ctx context.Context, cache *filter.Cache, target *config.Target, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
@@ -632,7 +709,7 @@ func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 		}
 	}
 
-	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, pubsub.DefaultPublishSettings)
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, nil)
 	// Send event.
 	event := createTestEvent(uuid.New().String())
 
@@ -654,7 +731,7 @@ func TestMultiTopicDecoupleSinkSendDoesNotChecksFilterWhenFeatureDisabled(t *tes
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(ctx context.Context, cache *filter.Cache, target *config.Target, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
@@ -688,7 +765,7 @@ func TestMultiTopicDecoupleSinkSendDoesNotChecksFilterWhenFeatureDisabled(t *tes
 		}
 	}
 
-	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, pubsub.DefaultPublishSettings)
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, nil)
 	// Send event.
 	event := createTestEvent(uuid.New().String())
 
@@ -739,7 +816,7 @@ func TestMultiTopicDecoupleSinkUsesPublishSettings(t *testing.T) {
 	publishSettings := pubsub.DefaultPublishSettings
 	// This is a purposely smaller than the event's data to cause an error.
 	publishSettings.BufferedByteLimit = len(ce.Data()) - 1
-	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, publishSettings)
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, queue.NewPubsubClient(psClient), publishSettings, nil)
 	// Send event.
 
 	namespace := config.TestOnlyBrokerKey("test_ns_1", "test_broker_1")
@@ -751,3 +828,187 @@ func TestMultiTopicDecoupleSinkUsesPublishSettings(t *testing.T) {
 		t.Fatalf("Unexpected error, expected %q, actually %q", want, got)
 	}
 }
+
+func TestMultiTopicDecoupleSinkOrderingKey(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+
+	testBrokerConfig := &config.TargetsConfig{
+		CellTenants: map[string]*config.CellTenant{
+			"test_ns_1/test_broker_1": {
+				Type:                 config.CellTenantType_BROKER,
+				DecoupleQueue:        &config.Queue{Topic: "test_topic_1", State: config.State_READY},
+				OrderingKeyAttribute: "partitionkey",
+			},
+		},
+	}
+	if _, err := psClient.CreateTopic(ctx, "test_topic_1"); err != nil {
+		t.Fatal(err)
+	}
+	sink := NewMultiTopicDecoupleSink(ctx, memory.NewTargets(testBrokerConfig), queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, nil)
+	broker := config.TestOnlyBrokerKey("test_ns_1", "test_broker_1")
+
+	ordered := createTestEvent("ordered")
+	ordered.SetExtension("partitionkey", "key-1")
+	if err := sink.Send(ctx, broker, *ordered); err != nil {
+		t.Fatalf("Failed to send ordered event: %v", err)
+	}
+	unordered := createTestEvent("unordered")
+	if err := sink.Send(ctx, broker, *unordered); err != nil {
+		t.Fatalf("Failed to send unordered event: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, msg := range psSrv.Messages() {
+		got[msg.Attributes["ce-id"]] = msg.OrderingKey
+	}
+	want := map[string]string{
+		"ordered":   "key-1",
+		"unordered": "",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected ordering keys (-want +got): %s", diff)
+	}
+}
+
+func TestMultiTopicDecoupleSinkProject(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+	conn, err := grpc.Dial(psSrv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	tenantClient, err := pubsub.NewClient(ctx, "tenant-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tenantClient.CreateTopic(ctx, "test_topic_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	brokerConfig := memory.NewTargets(&config.TargetsConfig{
+		CellTenants: map[string]*config.CellTenant{
+			"test_ns_1/test_broker_1": {
+				Type:          config.CellTenantType_BROKER,
+				DecoupleQueue: &config.Queue{Topic: "test_topic_1", State: config.State_READY, ProjectId: "tenant-project"},
+			},
+		},
+	})
+	client := queue.NewPubsubClientWithOptions(psClient, option.WithGRPCConn(conn))
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, client, pubsub.DefaultPublishSettings, nil)
+	broker := config.TestOnlyBrokerKey("test_ns_1", "test_broker_1")
+
+	if err := sink.Send(ctx, broker, *createTestEvent("tenant")); err != nil {
+		t.Fatalf("Failed to send event to the topic of the tenant project: %v", err)
+	}
+
+	// The topic doesn't exist in the project of the cell, so the sink must switch projects.
+	brokerConfig.MutateCellTenant(broker, func(m config.CellTenantMutation) {
+		m.SetDecoupleQueue(&config.Queue{Topic: "test_topic_1", State: config.State_READY})
+	})
+	if err := sink.Send(ctx, broker, *createTestEvent("cell")); err == nil {
+		t.Error("Sending an event to the topic missing from the project of the cell succeeded, want error")
+	}
+}
+
+func TestMultiTopicDecoupleSinkDedup(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+
+	testBrokerConfig := &config.TargetsConfig{
+		CellTenants: map[string]*config.CellTenant{
+			"test_ns_1/dedup": {
+				Id:            "b-uid-1",
+				Type:          config.CellTenantType_BROKER,
+				DecoupleQueue: &config.Queue{Topic: "test_topic_1", State: config.State_READY},
+				DedupWindow:   durationpb.New(time.Minute),
+			},
+			"test_ns_1/no_dedup": {
+				Id:            "b-uid-2",
+				Type:          config.CellTenantType_BROKER,
+				DecoupleQueue: &config.Queue{Topic: "test_topic_1", State: config.State_READY},
+			},
+			"test_ns_1/missing_topic": {
+				Id:            "b-uid-3",
+				Type:          config.CellTenantType_BROKER,
+				DecoupleQueue: &config.Queue{Topic: "missing_topic", State: config.State_READY},
+				DedupWindow:   durationpb.New(time.Minute),
+			},
+		},
+	}
+	if _, err := psClient.CreateTopic(ctx, "test_topic_1"); err != nil {
+		t.Fatal(err)
+	}
+	dedupStore := dedup.NewMemoryStore(10)
+	sink := NewMultiTopicDecoupleSink(ctx, memory.NewTargets(testBrokerConfig), queue.NewPubsubClient(psClient), pubsub.DefaultPublishSettings, dedupStore)
+
+	dedupBroker := config.TestOnlyBrokerKey("test_ns_1", "dedup")
+	event := createTestEvent("event")
+
+	// An event that fails to be published, e.g. because the request is cancelled, is released
+	// from the store.
+	cancelled := createTestEvent("cancelled")
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if res := sink.Send(cancelledCtx, dedupBroker, *cancelled); protocol.IsACK(res) {
+		t.Fatalf("Send(cancelled) got %v, want an error", res)
+	}
+	if res := sink.Send(ctx, dedupBroker, *cancelled); res != nil {
+		t.Errorf("Send(cancelled event again) got %v, want nil", res)
+	}
+
+	// Duplicates of an event being published are rejected, so that they are sent again if the
+	// publish fails.
+	pending := createTestEvent("pending")
+	if _, err := dedupStore.Add(ctx, dedup.Key("b-uid-1", pending.Source(), pending.ID()), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if res := sink.Send(ctx, dedupBroker, *pending); protocol.IsACK(res) || !errors.Is(res, ErrDuplicatePending) {
+		t.Errorf("Send(duplicate of pending event) got %v, want %v", res, ErrDuplicatePending)
+	}
+
+	if res := sink.Send(ctx, dedupBroker, *event); res != nil {
+		t.Fatalf("Failed to send event: %v", res)
+	}
+	res := sink.Send(ctx, dedupBroker, *event)
+	if !protocol.IsACK(res) || !errors.Is(res, ErrDuplicate) {
+		t.Errorf("Send(duplicate) got %v, want an ACK of a duplicate", res)
+	}
+	otherSource := createTestEvent("event")
+	otherSource.SetSource("other-source")
+	if res := sink.Send(ctx, dedupBroker, *otherSource); res != nil {
+		t.Errorf("Send(other source) got %v, want nil", res)
+	}
+
+	noDedupBroker := config.TestOnlyBrokerKey("test_ns_1", "no_dedup")
+	for i := 0; i < 2; i++ {
+		if res := sink.Send(ctx, noDedupBroker, *event); res != nil {
+			t.Errorf("Send(no dedup) got %v, want nil", res)
+		}
+	}
+	published := 0
+	for _, m := range psSrv.Messages() {
+		// The cancelled event may or may not be published the first time.
+		if m.Attributes["ce-id"] != "cancelled" {
+			published++
+		}
+	}
+	if want := 4; published != want {
+		t.Errorf("Unexpected number of published messages, got: %d, want: %d", published, want)
+	}
+
+	// Rejected events are not duplicates when they are sent again.
+	missingTopicBroker := config.TestOnlyBrokerKey("test_ns_1", "missing_topic")
+	for i := 0; i < 2; i++ {
+		if res := sink.Send(ctx, missingTopicBroker, *event); protocol.IsACK(res) {
+			t.Errorf("Send(missing topic) got %v, want an error", res)
+		}
+	}
+}
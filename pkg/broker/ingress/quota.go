@@ -0,0 +1,367 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"golang.org/x/time/rate"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// Reasons of the requests rejected by the Quotas, reported by the rejected_request_count metric
+// and in the body of the 429 responses.
+const (
+	reasonNamespaceEventsQuota = "namespace_events_quota"
+	reasonNamespaceBytesQuota  = "namespace_bytes_quota"
+	reasonBrokerEventsQuota    = "broker_events_quota"
+	reasonBrokerBytesQuota     = "broker_bytes_quota"
+	reasonFairShare            = "fair_share"
+	reasonPublisherOverflow    = "publisher_overflow"
+)
+
+// Scopes and types of the quotas, reported by the quota_utilization metric.
+const (
+	quotaScopeNamespace = "namespace"
+	quotaScopeBroker    = "broker"
+	quotaTypeEvents     = "events"
+	quotaTypeBytes      = "bytes"
+)
+
+// publisherPressure is the fraction of the publisher's buffer of a topic in use above which each
+// broker publishing to the topic is limited to its fair share of the buffer.
+const publisherPressure = 0.8
+
+// QuotaError is returned by Quotas when an event is rejected.
+type QuotaError struct {
+	// Reason is a short snake_case reason, used as a metric tag.
+	Reason string
+	Err    error
+}
+
+func (e *QuotaError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *QuotaError) Unwrap() error {
+	return e.Err
+}
+
+// Quotas enforces the quotas of the brokers and of their namespaces, and shares the publisher's
+// buffer of each decouple topic fairly between the brokers publishing to it when it is close to
+// overflowing. Quotas are enforced by each ingress replica independently.
+type Quotas struct {
+	brokerConfig config.ReadonlyTargets
+	reporter     *metrics.IngressReporter
+	// bufferedByteLimit is the size of the events the publisher buffers for each topic before it
+	// overflows.
+	bufferedByteLimit int64
+	now               func() time.Time
+
+	mu         sync.Mutex
+	namespaces limiters
+	brokers    limiters
+	// inflight is the size of the events being published, by decouple topic.
+	inflight map[string]*topicInflight
+}
+
+// topicInflight is the size of the events being published to a decouple topic.
+type topicInflight struct {
+	total int64
+	// brokers is the size of the events being published by each broker publishing to the topic.
+	brokers map[string]int64
+}
+
+// NewQuotas creates Quotas for the brokers in brokerConfig. The buffered byte limit of the publish
+// settings, which applies to each topic, is the budget shared fairly by the brokers of a topic
+// under pressure.
+func NewQuotas(brokerConfig config.ReadonlyTargets, reporter *metrics.IngressReporter, publishSettings pubsub.PublishSettings) *Quotas {
+	return &Quotas{
+		brokerConfig:      brokerConfig,
+		reporter:          reporter,
+		bufferedByteLimit: int64(publishSettings.BufferedByteLimit),
+		now:               time.Now,
+		namespaces:        make(limiters),
+		brokers:           make(limiters),
+		inflight:          make(map[string]*topicInflight),
+	}
+}
+
+// Acquire checks an event of the given size sent to the broker against the quotas of the broker
+// and of its namespace, and against the fair share of the broker if the publisher's buffer of its
+// topic is under pressure. If the event is accepted, release must be called once the event is published.
+func (q *Quotas) Acquire(ctx context.Context, broker *config.CellTenantKey, size int64) (release func(), err *QuotaError) {
+	noop := func() {}
+	if q == nil {
+		return noop, nil
+	}
+	b, ok := q.brokerConfig.GetCellTenantByKey(broker)
+	if !ok {
+		// The decouple sink rejects the events sent to unknown brokers.
+		return noop, nil
+	}
+	ns := b.Namespace
+	now := q.now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	nsLimiter := q.namespaces.get(ns, b.NamespaceQuota)
+	brokerLimiter := q.brokers.get(broker.PersistenceString(), b.Quota)
+
+	nsTaken, quotaType := nsLimiter.take(size, now)
+	if quotaType != "" {
+		q.report(ctx, quotaScopeNamespace, nsLimiter, now)
+		reason := reasonNamespaceEventsQuota
+		if quotaType == quotaTypeBytes {
+			reason = reasonNamespaceBytesQuota
+		}
+		return noop, quotaError(reason, "namespace %q exceeded its %s", ns, nsLimiter.describe(quotaType))
+	}
+	brokerTaken, quotaType := brokerLimiter.take(size, now)
+	if quotaType != "" {
+		nsTaken.cancel(now)
+		q.report(ctx, quotaScopeBroker, brokerLimiter, now)
+		reason := reasonBrokerEventsQuota
+		if quotaType == quotaTypeBytes {
+			reason = reasonBrokerBytesQuota
+		}
+		return noop, quotaError(reason, "broker %q exceeded its %s", broker.PersistenceString(), brokerLimiter.describe(quotaType))
+	}
+	var topic string
+	if b.DecoupleQueue != nil {
+		topic = b.DecoupleQueue.Topic
+	}
+	brokerName := broker.PersistenceString()
+	if !q.withinFairShare(topic, brokerName, size) {
+		nsTaken.cancel(now)
+		brokerTaken.cancel(now)
+		return noop, quotaError(reasonFairShare, "broker %q exceeded its fair share of the publisher", brokerName)
+	}
+	q.report(ctx, quotaScopeNamespace, nsLimiter, now)
+	q.report(ctx, quotaScopeBroker, brokerLimiter, now)
+
+	t := q.inflight[topic]
+	if t == nil {
+		t = &topicInflight{brokers: make(map[string]int64)}
+		q.inflight[topic] = t
+	}
+	t.total += size
+	t.brokers[brokerName] += size
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			t.total -= size
+			if t.brokers[brokerName] -= size; t.brokers[brokerName] <= 0 {
+				delete(t.brokers, brokerName)
+			}
+			if len(t.brokers) == 0 {
+				delete(q.inflight, topic)
+			}
+		})
+	}, nil
+}
+
+// TargetsReloaded forgets the limiters of the brokers and namespaces that no longer have a quota
+// in the targets config. It should be called after the targets config is reloaded.
+func (q *Quotas) TargetsReloaded() {
+	if q == nil {
+		return
+	}
+	namespaces := make(map[string]*config.Quota)
+	brokers := make(map[string]*config.Quota)
+	q.brokerConfig.RangeCellTenants(func(b *config.CellTenant) bool {
+		if b.NamespaceQuota != nil {
+			namespaces[b.Namespace] = b.NamespaceQuota
+		}
+		if b.Quota != nil {
+			brokers[b.Key().PersistenceString()] = b.Quota
+		}
+		return true
+	})
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.namespaces.prune(namespaces)
+	q.brokers.prune(brokers)
+}
+
+// limiters holds the quota limiters of namespaces or brokers.
+type limiters map[string]*quotaLimiter
+
+// prune forgets the limiters whose key has no quota, or a different quota, in quotas.
+func (m limiters) prune(quotas map[string]*config.Quota) {
+	for key, l := range m {
+		if quota, ok := quotas[key]; !ok || !l.matches(quota) {
+			delete(m, key)
+		}
+	}
+}
+
+// get returns the limiter of the key, creating it if the quota changed. It returns nil and forgets
+// the key if there is no quota.
+func (m limiters) get(key string, quota *config.Quota) *quotaLimiter {
+	if quota == nil {
+		delete(m, key)
+		return nil
+	}
+	l := m[key]
+	if l == nil || !l.matches(quota) {
+		l = newQuotaLimiter(quota)
+		m[key] = l
+	}
+	return l
+}
+
+// withinFairShare returns whether the broker can publish an event of the given size to its
+// decouple topic. The publisher buffers the events of each topic up to the buffered byte limit.
+// When the size of the events being published to the topic is above publisherPressure of the
+// limit, each broker publishing to the topic is limited to an equal share of the limit, so a
+// broker that is alone on its topic is limited to the whole limit.
+func (q *Quotas) withinFairShare(topic, broker string, size int64) bool {
+	t := q.inflight[topic]
+	if q.bufferedByteLimit <= 0 || t == nil || float64(t.total+size) <= publisherPressure*float64(q.bufferedByteLimit) {
+		return true
+	}
+	active := int64(len(t.brokers))
+	if _, ok := t.brokers[broker]; !ok {
+		active++
+	}
+	return t.brokers[broker]+size <= q.bufferedByteLimit/active
+}
+
+func (q *Quotas) report(ctx context.Context, scope string, l *quotaLimiter, now time.Time) {
+	if l == nil || q.reporter == nil {
+		return
+	}
+	if l.events != nil {
+		q.reporter.ReportQuotaUtilization(ctx, scope, quotaTypeEvents, utilization(l.events, now))
+	}
+	if l.bytes != nil {
+		q.reporter.ReportQuotaUtilization(ctx, scope, quotaTypeBytes, utilization(l.bytes, now))
+	}
+}
+
+func quotaError(reason, format string, args ...interface{}) *QuotaError {
+	return &QuotaError{
+		Reason: reason,
+		Err:    fmt.Errorf("%s: "+format, append([]interface{}{reason}, args...)...),
+	}
+}
+
+// quotaLimiter enforces a quota with a rate limiter per limit. Each limiter allows bursts of one
+// second worth of its quota.
+type quotaLimiter struct {
+	eventsPerSecond int64
+	bytesPerSecond  int64
+	events          *rate.Limiter
+	bytes           *rate.Limiter
+}
+
+func newQuotaLimiter(quota *config.Quota) *quotaLimiter {
+	l := &quotaLimiter{
+		eventsPerSecond: quota.EventsPerSecond,
+		bytesPerSecond:  quota.BytesPerSecond,
+	}
+	if quota.EventsPerSecond > 0 {
+		l.events = rate.NewLimiter(rate.Limit(quota.EventsPerSecond), int(quota.EventsPerSecond))
+	}
+	if quota.BytesPerSecond > 0 {
+		l.bytes = rate.NewLimiter(rate.Limit(quota.BytesPerSecond), int(quota.BytesPerSecond))
+	}
+	return l
+}
+
+func (l *quotaLimiter) matches(quota *config.Quota) bool {
+	return l.eventsPerSecond == quota.EventsPerSecond && l.bytesPerSecond == quota.BytesPerSecond
+}
+
+// take takes an event of the given size from the limiters. It returns the tokens taken, and the
+// quota type that is exceeded, or an empty string if the event is within the quota. Nothing is
+// taken if the event is rejected. An event larger than one second of the bytes quota is allowed
+// when the limiter is full, and takes all its tokens.
+func (l *quotaLimiter) take(size int64, now time.Time) (quotaReservation, string) {
+	if l == nil {
+		return nil, ""
+	}
+	var taken quotaReservation
+	if l.events != nil {
+		if !taken.reserve(l.events, 1, now) {
+			return nil, quotaTypeEvents
+		}
+	}
+	if l.bytes != nil {
+		n := int(size)
+		if n > l.bytes.Burst() {
+			n = l.bytes.Burst()
+		}
+		if !taken.reserve(l.bytes, n, now) {
+			taken.cancel(now)
+			return nil, quotaTypeBytes
+		}
+	}
+	return taken, ""
+}
+
+func (l *quotaLimiter) describe(quotaType string) string {
+	if quotaType == quotaTypeEvents {
+		return fmt.Sprintf("quota of %d events per second", l.eventsPerSecond)
+	}
+	return fmt.Sprintf("quota of %d bytes per second", l.bytesPerSecond)
+}
+
+// quotaReservation holds the tokens taken from the limiters of a quota for an event, so that they
+// are given back if the event is rejected by another quota.
+type quotaReservation []*rate.Reservation
+
+// reserve takes n tokens from the limiter if they are available now.
+func (r *quotaReservation) reserve(l *rate.Limiter, n int, now time.Time) bool {
+	res := l.ReserveN(now, n)
+	if !res.OK() {
+		return false
+	}
+	if res.DelayFrom(now) > 0 {
+		res.CancelAt(now)
+		return false
+	}
+	*r = append(*r, res)
+	return true
+}
+
+// cancel gives the tokens back to the limiters.
+func (r quotaReservation) cancel(now time.Time) {
+	for _, res := range r {
+		res.CancelAt(now)
+	}
+}
+
+// utilization returns the fraction of the burst of the limiter in use, between 0 and 1. The
+// limiter doesn't expose its tokens, so the fraction is computed from the delay of a reservation
+// of the whole burst, which is then cancelled.
+func utilization(l *rate.Limiter, now time.Time) float64 {
+	res := l.ReserveN(now, l.Burst())
+	defer res.CancelAt(now)
+	return math.Max(0, math.Min(1, res.DelayFrom(now).Seconds()*float64(l.Limit())/float64(l.Burst())))
+}
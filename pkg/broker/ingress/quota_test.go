@@ -0,0 +1,328 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"math"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func quotaBrokerConfig(brokerQuota, namespaceQuota *config.Quota) *config.TargetsConfig {
+	return &config.TargetsConfig{
+		CellTenants: map[string]*config.CellTenant{
+			"ns1/broker1": {
+				Id:             "b-uid-1",
+				Type:           config.CellTenantType_BROKER,
+				Name:           "broker1",
+				Namespace:      "ns1",
+				DecoupleQueue:  &config.Queue{Topic: topicID, State: config.State_READY},
+				Quota:          brokerQuota,
+				NamespaceQuota: namespaceQuota,
+			},
+			"ns1/broker2": {
+				Id:             "b-uid-2",
+				Type:           config.CellTenantType_BROKER,
+				Name:           "broker2",
+				Namespace:      "ns1",
+				DecoupleQueue:  &config.Queue{Topic: topicID, State: config.State_READY},
+				NamespaceQuota: namespaceQuota,
+			},
+			"ns2/broker1": {
+				Id:            "b-uid-3",
+				Type:          config.CellTenantType_BROKER,
+				Name:          "broker1",
+				Namespace:     "ns2",
+				DecoupleQueue: &config.Queue{Topic: topicID, State: config.State_READY},
+			},
+		},
+	}
+}
+
+func quotaBrokerKey(t *testing.T, path string) *config.CellTenantKey {
+	t.Helper()
+	key, err := config.CellTenantKeyFromPersistenceString(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newTestQuotas(t *testing.T, targets *config.TargetsConfig, bufferedByteLimit int) (*Quotas, *time.Time) {
+	t.Helper()
+	reportertest.ResetIngressMetrics()
+	reporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := pubsub.DefaultPublishSettings
+	settings.BufferedByteLimit = bufferedByteLimit
+	q := NewQuotas(memory.NewTargets(targets), reporter, settings)
+	now := time.Unix(0, 0)
+	q.now = func() time.Time { return now }
+	return q, &now
+}
+
+func TestQuotas(t *testing.T) {
+	broker1 := quotaBrokerKey(t, "/ns1/broker1")
+	broker2 := quotaBrokerKey(t, "/ns1/broker2")
+	other := quotaBrokerKey(t, "/ns2/broker1")
+	tests := []struct {
+		name           string
+		brokerQuota    *config.Quota
+		namespaceQuota *config.Quota
+		// sends are the brokers of the events of the given size sent in order, all at the same time.
+		sends      []*config.CellTenantKey
+		size       int64
+		wantReason []string
+	}{{
+		name:       "no quotas",
+		sends:      []*config.CellTenantKey{broker1, broker1, broker1},
+		size:       100,
+		wantReason: []string{"", "", ""},
+	}, {
+		name:        "broker events quota",
+		brokerQuota: &config.Quota{EventsPerSecond: 2},
+		sends:       []*config.CellTenantKey{broker1, broker1, broker1, broker2},
+		wantReason:  []string{"", "", reasonBrokerEventsQuota, ""},
+	}, {
+		name:        "broker bytes quota",
+		brokerQuota: &config.Quota{BytesPerSecond: 250},
+		sends:       []*config.CellTenantKey{broker1, broker1, broker1},
+		size:        100,
+		wantReason:  []string{"", "", reasonBrokerBytesQuota},
+	}, {
+		name:           "namespace events quota",
+		namespaceQuota: &config.Quota{EventsPerSecond: 2},
+		sends:          []*config.CellTenantKey{broker1, broker2, broker2, other},
+		wantReason:     []string{"", "", reasonNamespaceEventsQuota, ""},
+	}, {
+		name:           "namespace bytes quota",
+		namespaceQuota: &config.Quota{BytesPerSecond: 150},
+		sends:          []*config.CellTenantKey{broker1, broker2, other},
+		size:           100,
+		wantReason:     []string{"", reasonNamespaceBytesQuota, ""},
+	}, {
+		name:           "rejected by the broker quota is refunded to the namespace",
+		brokerQuota:    &config.Quota{EventsPerSecond: 1},
+		namespaceQuota: &config.Quota{EventsPerSecond: 2},
+		sends:          []*config.CellTenantKey{broker1, broker1, broker2, broker2},
+		wantReason:     []string{"", reasonBrokerEventsQuota, "", reasonNamespaceEventsQuota},
+	}, {
+		name:        "event larger than the bytes quota",
+		brokerQuota: &config.Quota{BytesPerSecond: 50},
+		sends:       []*config.CellTenantKey{broker1, broker1},
+		size:        100,
+		wantReason:  []string{"", reasonBrokerBytesQuota},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, _ := newTestQuotas(t, quotaBrokerConfig(tc.brokerQuota, tc.namespaceQuota), 0)
+			for i, broker := range tc.sends {
+				release, err := q.Acquire(context.Background(), broker, tc.size)
+				release()
+				var reason string
+				if err != nil {
+					reason = err.Reason
+					if !strings.HasPrefix(err.Error(), reason+": ") {
+						t.Errorf("send %d: error %q does not start with its reason", i, err.Error())
+					}
+				}
+				if reason != tc.wantReason[i] {
+					t.Errorf("send %d: got reason %q, want %q", i, reason, tc.wantReason[i])
+				}
+			}
+		})
+	}
+}
+
+func TestQuotasRefill(t *testing.T) {
+	q, now := newTestQuotas(t, quotaBrokerConfig(&config.Quota{EventsPerSecond: 10}, nil), 0)
+	broker := quotaBrokerKey(t, "/ns1/broker1")
+	for i := 0; i < 10; i++ {
+		if _, err := q.Acquire(context.Background(), broker, 0); err != nil {
+			t.Fatalf("send %d: unexpected error: %v", i, err)
+		}
+	}
+	if _, err := q.Acquire(context.Background(), broker, 0); err == nil {
+		t.Fatal("expected the quota to be exceeded")
+	}
+	*now = now.Add(100 * time.Millisecond)
+	if _, err := q.Acquire(context.Background(), broker, 0); err != nil {
+		t.Fatalf("unexpected error after refill: %v", err)
+	}
+	if _, err := q.Acquire(context.Background(), broker, 0); err == nil {
+		t.Fatal("expected the quota to be exceeded")
+	}
+}
+
+func TestQuotasTargetsReloaded(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	targets := memory.NewTargets(quotaBrokerConfig(&config.Quota{EventsPerSecond: 1}, &config.Quota{EventsPerSecond: 10}))
+	targets.MutateCellTenant(config.TestOnlyBrokerKey("ns2", "broker1"), func(m config.CellTenantMutation) {
+		m.SetQuota(&config.Quota{EventsPerSecond: 1})
+		m.SetNamespaceQuota(&config.Quota{EventsPerSecond: 10})
+	})
+	q := NewQuotas(targets, nil, pubsub.DefaultPublishSettings)
+	for _, path := range []string{"/ns1/broker1", "/ns2/broker1"} {
+		if _, err := q.Acquire(context.Background(), quotaBrokerKey(t, path), 0); err != nil {
+			t.Fatalf("unexpected error for %s: %v", path, err)
+		}
+	}
+
+	// The limiters of the deleted broker and of its namespace are forgotten, and the limiter of a
+	// changed quota is recreated on the next event.
+	targets.MutateCellTenant(config.TestOnlyBrokerKey("ns2", "broker1"), func(m config.CellTenantMutation) {
+		m.Delete()
+	})
+	targets.MutateCellTenant(config.TestOnlyBrokerKey("ns1", "broker1"), func(m config.CellTenantMutation) {
+		m.SetQuota(&config.Quota{EventsPerSecond: 2})
+	})
+	q.TargetsReloaded()
+	if _, ok := q.namespaces["ns1"]; !ok || len(q.namespaces) != 1 {
+		t.Errorf("got namespace limiters %v, want only ns1", q.namespaces)
+	}
+	if len(q.brokers) != 0 {
+		t.Errorf("got broker limiters %v, want none", q.brokers)
+	}
+
+	// Nil Quotas, when the ingress enforces no quotas, are a no-op.
+	var nilQuotas *Quotas
+	nilQuotas.TargetsReloaded()
+}
+
+func TestQuotasFairShare(t *testing.T) {
+	targets := quotaBrokerConfig(nil, nil)
+	targets.CellTenants["ns3/broker1"] = &config.CellTenant{
+		Id:            "b-uid-4",
+		Type:          config.CellTenantType_BROKER,
+		Name:          "broker1",
+		Namespace:     "ns3",
+		DecoupleQueue: &config.Queue{Topic: "other_topic", State: config.State_READY},
+	}
+	q, _ := newTestQuotas(t, targets, 1000)
+	// The noisy and quiet brokers publish to the same topic.
+	noisy := quotaBrokerKey(t, "/ns1/broker1")
+	quiet := quotaBrokerKey(t, "/ns2/broker1")
+	alone := quotaBrokerKey(t, "/ns3/broker1")
+
+	// The noisy broker can use the whole buffer of the topic while it is the only one publishing.
+	releaseNoisy, err := q.Acquire(context.Background(), noisy, 700)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	releaseQuiet, err := q.Acquire(context.Background(), quiet, 200)
+	if err != nil {
+		t.Fatalf("unexpected error for the quiet broker: %v", err)
+	}
+	// Under pressure, the noisy broker is above its share of half of the buffer.
+	if _, err := q.Acquire(context.Background(), noisy, 50); err == nil || err.Reason != reasonFairShare {
+		t.Errorf("got error %v, want reason %q", err, reasonFairShare)
+	}
+	// The quiet broker is still within its share.
+	release, err := q.Acquire(context.Background(), quiet, 50)
+	if err != nil {
+		t.Errorf("unexpected error for the quiet broker: %v", err)
+	}
+	release()
+
+	// The buffer of each topic is separate, and a broker alone on its topic can use all of it.
+	releaseAlone, err := q.Acquire(context.Background(), alone, 900)
+	if err != nil {
+		t.Errorf("unexpected error for the broker of another topic: %v", err)
+	}
+	if _, err := q.Acquire(context.Background(), alone, 150); err == nil || err.Reason != reasonFairShare {
+		t.Errorf("got error %v above the buffer of the topic, want reason %q", err, reasonFairShare)
+	}
+	releaseAlone()
+
+	releaseQuiet()
+	// Once the pressure is gone, the noisy broker can publish again.
+	releaseNoisy()
+	releaseNoisy()
+	if _, err := q.Acquire(context.Background(), noisy, 700); err != nil {
+		t.Errorf("unexpected error once the pressure is gone: %v", err)
+	}
+}
+
+func TestQuotaUtilization(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newQuotaLimiter(&config.Quota{EventsPerSecond: 4})
+	for i := 0; i < 3; i++ {
+		if _, quotaType := l.take(0, now); quotaType != "" {
+			t.Fatalf("send %d: exceeded the %s quota", i, quotaType)
+		}
+	}
+	if got, want := utilization(l.events, now), 0.75; math.Abs(got-want) > 1e-9 {
+		t.Errorf("utilization() = %v, want %v", got, want)
+	}
+	// Computing the utilization takes no tokens.
+	if _, quotaType := l.take(0, now); quotaType != "" {
+		t.Errorf("exceeded the %s quota after computing the utilization", quotaType)
+	}
+	if got, want := utilization(l.events, now), 1.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("utilization() = %v, want %v", got, want)
+	}
+}
+
+func TestHandlerRejectsEventsOverQuota(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := memory.NewTargets(quotaBrokerConfig(&config.Quota{EventsPerSecond: 1}, nil))
+	quotas := NewQuotas(targets, statsReporter, pubsub.DefaultPublishSettings)
+	sink := &fakeRecordingDecoupleSink{}
+	h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, targets, nil, quotas, pubsub.PublishSettings{}, StreamingOptions{})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", nil)
+		if err := http.WriteRequest(context.Background(), binding.ToMessage(createTestEvent("test-event")), req); err != nil {
+			t.Fatal(err)
+		}
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		return res
+	}
+	if res := send(); res.Code != nethttp.StatusAccepted {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, nethttp.StatusAccepted)
+	}
+	res := send()
+	if res.Code != nethttp.StatusTooManyRequests {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, nethttp.StatusTooManyRequests)
+	}
+	if !strings.HasPrefix(res.Body.String(), reasonBrokerEventsQuota) {
+		t.Errorf("got body %q, want the reason %q", res.Body.String(), reasonBrokerEventsQuota)
+	}
+	if len(sink.sent) != 1 {
+		t.Errorf("got %d published events, want 1", len(sink.sent))
+	}
+}
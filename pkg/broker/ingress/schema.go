@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventschema"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// validateSchema validates the data of the event against the schema of its type, if the broker
+// enforces schemas. It returns an error if the event must be rejected. Events that don't match
+// the schema of a broker in warn mode are logged and reported, and accepted.
+func (h *Handler) validateSchema(ctx context.Context, broker *config.CellTenantKey, event *cev2.Event) error {
+	tenant, ok := h.brokerConfig.GetCellTenantByKey(broker)
+	if !ok || tenant.SchemaEnforcement == config.SchemaEnforcement_SCHEMA_ENFORCEMENT_OFF {
+		return nil
+	}
+	s, ok := tenant.EventSchemas[event.Type()]
+	if !ok {
+		return nil
+	}
+	schema, err := h.schemas.get(h.brokerConfig, *broker, event.Type(), s)
+	if err != nil {
+		// The webhook rejects invalid schemas, so this should not happen. Accept the events
+		// rather than rejecting all the events of the type.
+		logging.FromContext(ctx).Error("Failed to compile event schema", zap.String("schema", s.Name), zap.Error(err))
+		return nil
+	}
+	err = schema.Validate(event)
+	if err == nil {
+		return nil
+	}
+	enforcement := strings.ToLower(strings.TrimPrefix(tenant.SchemaEnforcement.String(), "SCHEMA_ENFORCEMENT_"))
+	h.reporter.ReportSchemaViolation(ctx, event.Type(), enforcement)
+	if tenant.SchemaEnforcement == config.SchemaEnforcement_SCHEMA_ENFORCEMENT_WARN {
+		logging.FromContext(ctx).Warn("Event does not match its schema", zap.String("schema", s.Name), zap.String("type", event.Type()), zap.String("id", event.ID()), zap.Error(err))
+		return nil
+	}
+	logging.FromContext(ctx).Debug("Rejected event that does not match its schema", zap.String("schema", s.Name), zap.String("type", event.Type()), zap.String("id", event.ID()), zap.Error(err))
+	return err
+}
+
+// schemaCache caches the compiled schemas of the brokers, so that schemas are only compiled again
+// when they change in the targets config. The zero value is ready to use.
+type schemaCache struct {
+	m sync.Map
+}
+
+type schemaCacheKey struct {
+	broker    config.CellTenantKey
+	eventType string
+}
+
+type schemaCacheEntry struct {
+	config *config.EventSchema
+	schema *eventschema.Schema
+	err    error
+}
+
+// get returns the compiled schema of the event type of the broker. Every reload of the targets
+// config produces new EventSchema objects, so an entry is compiled again whenever the cached
+// EventSchema is replaced. Compiling therefore means that the targets config changed, and the
+// entries of the schemas that are no longer in targets are evicted then.
+func (c *schemaCache) get(targets config.ReadonlyTargets, broker config.CellTenantKey, eventType string, s *config.EventSchema) (*eventschema.Schema, error) {
+	key := schemaCacheKey{broker: broker, eventType: eventType}
+	if v, ok := c.m.Load(key); ok {
+		if e := v.(*schemaCacheEntry); e.config == s {
+			return e.schema, e.err
+		}
+	}
+	schema, err := eventschema.New(s.JsonSchema, s.FileDescriptorSet, s.Message)
+	c.prune(targets)
+	c.m.Store(key, &schemaCacheEntry{config: s, schema: schema, err: err})
+	return schema, err
+}
+
+// prune evicts the entries of the brokers and event types that no longer have the cached schema.
+func (c *schemaCache) prune(targets config.ReadonlyTargets) {
+	c.m.Range(func(k, v interface{}) bool {
+		key := k.(schemaCacheKey)
+		tenant, ok := targets.GetCellTenantByKey(&key.broker)
+		if !ok || tenant.EventSchemas[key.eventType] != v.(*schemaCacheEntry).config {
+			c.m.Delete(key)
+		}
+		return true
+	})
+}
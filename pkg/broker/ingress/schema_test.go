@@ -0,0 +1,209 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+var testEventSchemas = map[string]*config.EventSchema{
+	eventType: {
+		Name:       "job-executed",
+		JsonSchema: []byte(`{"type": "object", "required": ["jobName"], "properties": {"jobName": {"type": "string"}}}`),
+	},
+}
+
+var schemaBrokerConfig = &config.TargetsConfig{
+	CellTenants: map[string]*config.CellTenant{
+		"ns1/off": {
+			Id:           "b-uid-1",
+			Type:         config.CellTenantType_BROKER,
+			Name:         "off",
+			Namespace:    "ns1",
+			EventSchemas: testEventSchemas,
+		},
+		"ns1/warn": {
+			Id:                "b-uid-2",
+			Type:              config.CellTenantType_BROKER,
+			Name:              "warn",
+			Namespace:         "ns1",
+			SchemaEnforcement: config.SchemaEnforcement_SCHEMA_ENFORCEMENT_WARN,
+			EventSchemas:      testEventSchemas,
+		},
+		"ns1/reject": {
+			Id:                "b-uid-3",
+			Type:              config.CellTenantType_BROKER,
+			Name:              "reject",
+			Namespace:         "ns1",
+			SchemaEnforcement: config.SchemaEnforcement_SCHEMA_ENFORCEMENT_REJECT,
+			EventSchemas:      testEventSchemas,
+		},
+	},
+}
+
+func TestHandlerValidatesEventSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		eventType     string
+		data          string
+		wantCode      int
+		wantSent      bool
+		wantViolation string
+	}{{
+		name:     "enforcement off",
+		path:     "/ns1/off",
+		data:     `{"jobName": 1}`,
+		wantCode: nethttp.StatusAccepted,
+		wantSent: true,
+	}, {
+		name:     "warn with a valid event",
+		path:     "/ns1/warn",
+		data:     `{"jobName": "job"}`,
+		wantCode: nethttp.StatusAccepted,
+		wantSent: true,
+	}, {
+		name:          "warn with an invalid event",
+		path:          "/ns1/warn",
+		data:          `{"jobName": 1}`,
+		wantCode:      nethttp.StatusAccepted,
+		wantSent:      true,
+		wantViolation: "warn",
+	}, {
+		name:     "reject with a valid event",
+		path:     "/ns1/reject",
+		data:     `{"jobName": "job"}`,
+		wantCode: nethttp.StatusAccepted,
+		wantSent: true,
+	}, {
+		name:          "reject with an invalid event",
+		path:          "/ns1/reject",
+		data:          `{}`,
+		wantCode:      nethttp.StatusBadRequest,
+		wantViolation: "reject",
+	}, {
+		name:      "reject with a type without schema",
+		path:      "/ns1/reject",
+		eventType: "google.cloud.pubsub.topic.v1.messagePublished",
+		data:      `{}`,
+		wantCode:  nethttp.StatusAccepted,
+		wantSent:  true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink := &fakeBatchDecoupleSink{}
+			h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(schemaBrokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{})
+
+			event := createTestEvent("test-event")
+			if tc.eventType != "" {
+				event.SetType(tc.eventType)
+			}
+			if err := event.SetData(cev2.ApplicationJSON, []byte(tc.data)); err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(nethttp.MethodPost, tc.path, nil)
+			if err := http.WriteRequest(context.Background(), binding.ToMessage(event), req); err != nil {
+				t.Fatal(err)
+			}
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+
+			if res.Code != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.Code, tc.wantCode)
+			}
+			if tc.wantCode == nethttp.StatusBadRequest && !strings.Contains(res.Body.String(), "/: missing properties: 'jobName'") {
+				t.Errorf("response body %q doesn't contain the validation errors", res.Body.String())
+			}
+			if sent := len(sink.sent) > 0; sent != tc.wantSent {
+				t.Errorf("event sent = %v, want %v", sent, tc.wantSent)
+			}
+			if tc.wantViolation == "" {
+				metricstest.AssertNoMetric(t, "schema_violation_count")
+				return
+			}
+			metricstest.CheckCountData(t, "schema_violation_count", map[string]string{
+				metricskey.LabelEventType: eventType,
+				"schema_enforcement":      tc.wantViolation,
+				metricskey.PodName:        pod,
+				metricskey.ContainerName:  container,
+			}, 1)
+		})
+	}
+}
+
+func TestSchemaCache(t *testing.T) {
+	var c schemaCache
+	targets := memory.NewTargets(schemaBrokerConfig)
+	broker := schemaBrokerConfig.CellTenants["ns1/reject"].Key()
+	s := &config.EventSchema{JsonSchema: []byte(`{"type": "object"}`)}
+	first, err := c.get(targets, *broker, eventType, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := c.get(targets, *broker, eventType, s); again != first {
+		t.Error("schema compiled again for the same config")
+	}
+	updated := &config.EventSchema{JsonSchema: []byte(`{"type": "array"}`)}
+	if compiled, _ := c.get(targets, *broker, eventType, updated); compiled == first {
+		t.Error("schema not compiled again after the config changed")
+	}
+	if _, err := c.get(targets, *broker, eventType, &config.EventSchema{JsonSchema: []byte(`{`)}); err == nil {
+		t.Error("expected an error for an invalid schema")
+	}
+}
+
+func TestSchemaCacheEvictsDeletedBrokers(t *testing.T) {
+	var c schemaCache
+	targets := memory.NewTargets(schemaBrokerConfig)
+	warn := schemaBrokerConfig.CellTenants["ns1/warn"].Key()
+	reject := schemaBrokerConfig.CellTenants["ns1/reject"].Key()
+	if _, err := c.get(targets, *warn, eventType, testEventSchemas[eventType]); err != nil {
+		t.Fatal(err)
+	}
+	targets.MutateCellTenant(warn, func(m config.CellTenantMutation) { m.Delete() })
+	tenant, _ := targets.GetCellTenantByKey(reject)
+	if _, err := c.get(targets, *reject, eventType, tenant.EventSchemas[eventType]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.m.Load(schemaCacheKey{broker: *warn, eventType: eventType}); ok {
+		t.Error("the schema of the deleted broker was not evicted")
+	}
+	if _, ok := c.m.Load(schemaCacheKey{broker: *reject, eventType: eventType}); !ok {
+		t.Error("the schema of the broker was not cached")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	nethttp "net/http"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics/metricskey"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// maxStreamInflight is the maximum number of events of a stream being published at once.
+const maxStreamInflight = 100
+
+// StreamingOptions configures the streaming protocols the ingress serves in addition to HTTP.
+type StreamingOptions struct {
+	// WebSocket accepts WebSocket connections on the broker paths of the HTTP port.
+	WebSocket bool
+	// GRPCPort is the port of the gRPC Ingress service. The service is disabled if 0.
+	GRPCPort int
+}
+
+// withBroker returns a context with the logger and the metrics resource of the broker.
+func withBroker(ctx context.Context, broker *config.CellTenantKey) context.Context {
+	ctx = logging.With(ctx, zap.Stringer("broker", broker))
+	return metricskey.WithResource(ctx, broker.MetricsResource())
+}
+
+// sendStreamed sends an event received on a stream to the broker, in a span of its own. ctx must
+// be a context returned by withBroker. Like transformer.AddTimeNow does for the events of
+// requests, it sets the time of the event to now if it has none.
+func (h *Handler) sendStreamed(ctx context.Context, broker *config.CellTenantKey, event *cev2.Event) (int, string) {
+	if event.Time().IsZero() {
+		event.SetTime(time.Now())
+	}
+	ctx, span := trace.StartSpan(ctx, broker.SpanMessagingDestination(), trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	addSpanAttributes(span, broker, event)
+	return h.send(ctx, broker, event)
+}
+
+// rejectInvalidEvent reports an event of a stream that cannot be converted to a valid event, as
+// ServeHTTP does for a request.
+func (h *Handler) rejectInvalidEvent(ctx context.Context, err error) (int, string) {
+	h.reportMetrics(ctx, "_invalid_cloud_event_", nethttp.StatusBadRequest)
+	return nethttp.StatusBadRequest, err.Error()
+}
+
+// streamSender sends the events of a stream concurrently, up to maxStreamInflight at once, and
+// serializes their responses.
+type streamSender struct {
+	h        *Handler
+	inflight chan struct{}
+	wg       sync.WaitGroup
+	// mu serializes the calls to respond.
+	mu      sync.Mutex
+	respond func(id string, statusCode int, message string)
+}
+
+func newStreamSender(h *Handler, respond func(id string, statusCode int, message string)) *streamSender {
+	return &streamSender{
+		h:        h,
+		inflight: make(chan struct{}, maxStreamInflight),
+		respond:  respond,
+	}
+}
+
+// send sends the event to the broker in the background, blocking while maxStreamInflight events
+// are being sent. ctx must be a context returned by withBroker.
+func (s *streamSender) send(ctx context.Context, broker *config.CellTenantKey, event *cev2.Event) {
+	s.inflight <- struct{}{}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.inflight }()
+		statusCode, message := s.h.sendStreamed(ctx, broker, event)
+		s.reply(event.ID(), statusCode, message)
+	}()
+}
+
+// reply sends the response of an event.
+func (s *streamSender) reply(id string, statusCode int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.respond(id, statusCode, message)
+}
+
+// wait waits for the events being sent.
+func (s *streamSender) wait() {
+	s.wg.Wait()
+}
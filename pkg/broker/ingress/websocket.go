@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	nethttp "net/http"
+	"strings"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// webSocketResponse is the response sent on a WebSocket connection for each event received on it.
+type webSocketResponse struct {
+	ID         string `json:"id"`
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message,omitempty"`
+}
+
+// isWebSocketRequest returns true if the request is a WebSocket handshake.
+func isWebSocketRequest(request *nethttp.Request) bool {
+	return strings.EqualFold(request.Header.Get("Upgrade"), "websocket")
+}
+
+// serveWebSocket upgrades the request to a WebSocket connection on which each message is an event
+// in the structured JSON format. Each event goes through the same steps as the event of a request
+// served by ServeHTTP, and its status code is sent back as a webSocketResponse. The Authorization
+// header of the handshake is verified again for each event, so that the events sent after its
+// token expires are rejected. ctx must be a context returned by withBroker.
+func (h *Handler) serveWebSocket(ctx context.Context, response nethttp.ResponseWriter, request *nethttp.Request, broker *config.CellTenantKey) {
+	authorization := request.Header.Get("Authorization")
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		ws.MaxPayloadBytes = int(h.maxRequestBytes())
+		sender := newStreamSender(h, func(id string, statusCode int, message string) {
+			resp := webSocketResponse{ID: id, StatusCode: statusCode, Message: message}
+			if err := websocket.JSON.Send(ws, resp); err != nil {
+				logging.FromContext(ctx).Debug("Failed to send WebSocket response", zap.Error(err))
+			}
+		})
+		defer sender.wait()
+		for {
+			var msg []byte
+			if err := websocket.Message.Receive(ws, &msg); err != nil {
+				if !errors.Is(err, io.EOF) {
+					logging.FromContext(ctx).Debug("Failed to receive WebSocket message", zap.Error(err))
+				}
+				return
+			}
+			var event cev2.Event
+			err := json.Unmarshal(msg, &event)
+			if err == nil {
+				err = event.Validate()
+			}
+			if err != nil {
+				var id string
+				if event.Context != nil {
+					id = event.ID()
+				}
+				statusCode, message := h.rejectInvalidEvent(ctx, err)
+				sender.reply(id, statusCode, message)
+				continue
+			}
+			if authErr := h.authenticate(ctx, broker, authorization); authErr != nil {
+				sender.reply(event.ID(), authErr.StatusCode, authErr.Error())
+				continue
+			}
+			sender.send(ctx, broker, &event)
+		}
+	}}.ServeHTTP(response, request)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/websocket"
+
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func TestServeWebSocket(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		webSocket bool
+		wantErr   bool
+		// wantCodes is the status code of each event sent on the connection, by event ID.
+		wantCodes      map[string]int
+		wantSent       []string
+		wantEventCount map[string]int64
+	}{{
+		name:      "events",
+		path:      "/ns1/broker1",
+		webSocket: true,
+		wantCodes: map[string]int{
+			"1": nethttp.StatusAccepted,
+			"2": nethttp.StatusServiceUnavailable,
+			"3": nethttp.StatusBadRequest,
+		},
+		wantSent: []string{"1", "2"},
+		wantEventCount: map[string]int64{
+			eventType + "/202":          1,
+			eventType + "/503":          1,
+			"_invalid_cloud_event_/400": 1,
+		},
+	}, {
+		name:      "malformed path",
+		path:      "/ns1",
+		webSocket: true,
+		wantErr:   true,
+	}, {
+		name:    "websocket disabled",
+		path:    "/ns1/broker1",
+		wantErr: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink := &fakeBatchDecoupleSink{results: map[string]protocol.Result{"2": ErrNotReady}}
+			h := NewHandler(context.Background(), nil, sink, statsReporter, "", nil, memory.NewTargets(batchBrokerConfig), nil, nil, pubsub.PublishSettings{}, StreamingOptions{WebSocket: tc.webSocket})
+			server := httptest.NewServer(h)
+			defer server.Close()
+
+			url := "ws" + strings.TrimPrefix(server.URL, "http") + tc.path
+			ws, err := websocket.Dial(url, "", server.URL)
+			if tc.wantErr {
+				if err == nil {
+					ws.Close()
+					t.Fatal("Expected the handshake to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ws.Close()
+
+			invalid := batchEvent("3", "")
+			delete(invalid, "source")
+			for _, e := range []interface{}{batchEvent("1", ""), batchEvent("2", ""), invalid} {
+				if err := websocket.JSON.Send(ws, e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := make(map[string]int)
+			for range tc.wantCodes {
+				var res webSocketResponse
+				if err := websocket.JSON.Receive(ws, &res); err != nil {
+					t.Fatal(err)
+				}
+				got[res.ID] = res.StatusCode
+			}
+			if diff := cmp.Diff(tc.wantCodes, got); diff != "" {
+				t.Errorf("Unexpected status codes (-want, +got): %s", diff)
+			}
+
+			sort.Strings(sink.sent)
+			if diff := cmp.Diff(tc.wantSent, sink.sent); diff != "" {
+				t.Errorf("Unexpected sent events (-want, +got): %s", diff)
+			}
+			if len(sink.untimed) > 0 {
+				t.Errorf("Events sent without a time: %v", sink.untimed)
+			}
+			if diff := cmp.Diff(tc.wantEventCount, eventCountByCode()); diff != "" {
+				t.Errorf("Unexpected event_count (-want, +got): %s", diff)
+			}
+		})
+	}
+}
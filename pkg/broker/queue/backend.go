@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/knative-gcp/pkg/utils/clients"
+)
+
+// Backend is a queue backend of the data plane.
+type Backend string
+
+const (
+	// PubsubBackend is the Pub/Sub backend.
+	PubsubBackend Backend = "pubsub"
+	// BoltBackend is the backend of BoltClient.
+	BoltBackend Backend = "bolt"
+)
+
+// Settings selects the queue backend of a data plane component.
+type Settings struct {
+	// Backend is the queue backend, Pub/Sub if empty.
+	Backend Backend
+	// BoltPath is the path of the database file of the bolt backend.
+	BoltPath string
+}
+
+// NewClient returns the Client of the backend selected by the settings. The Pub/Sub client is only
+// created for the Pub/Sub backend, so that the other backends don't need GCP credentials.
+func NewClient(ctx context.Context, projectID clients.ProjectID, settings Settings) (Client, error) {
+	switch settings.Backend {
+	case "", PubsubBackend:
+		client, err := clients.NewPubsubClient(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		return NewPubsubClient(client), nil
+	case BoltBackend:
+		if settings.BoltPath == "" {
+			return nil, fmt.Errorf("the database path of the %s queue backend is not set", BoltBackend)
+		}
+		return NewBoltClient(settings.BoltPath)
+	default:
+		return nil, fmt.Errorf("unknown queue backend %q", settings.Backend)
+	}
+}
@@ -0,0 +1,470 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	bbolt "go.etcd.io/bbolt"
+)
+
+const (
+	// DefaultBoltPollInterval is how often a subscription is polled when it has no message to
+	// deliver.
+	DefaultBoltPollInterval = 100 * time.Millisecond
+	// DefaultBoltAckDeadline is how long a received message is leased to its receiver.
+	DefaultBoltAckDeadline = 10 * time.Minute
+	// DefaultBoltRedeliveryDelay is the delay before a nacked message is redelivered.
+	DefaultBoltRedeliveryDelay = time.Second
+	// DefaultBoltLockTimeout is how long NewBoltClient waits for the lock of the database file.
+	DefaultBoltLockTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNotFound is the error when a topic or a subscription doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrOrderingDisabled is the error when a message with an ordering key is published to a
+	// topic that doesn't preserve the order of messages.
+	ErrOrderingDisabled = errors.New("ordering key is set on a topic without message ordering")
+
+	boltTopicsBucket        = []byte("topics")
+	boltSubscriptionsBucket = []byte("subscriptions")
+	boltMessagesBucket      = []byte("messages")
+	boltScheduleBucket      = []byte("schedule")
+	boltOrderingKeysBucket  = []byte("orderingKeys")
+	boltTopicKey            = []byte("topic")
+)
+
+// BoltClient is a Client backed by a bbolt database file. The file is opened by NewBoltClient and
+// locked until Close, so the ingress, fanout and retry must share a single client, e.g. when they
+// run in the same process. Messages are kept in the file until they are acked, so they survive
+// restarts.
+//
+// Each subscription indexes its messages by the time they can be delivered, so receiving a message
+// doesn't scan the messages that are leased or waiting for their redelivery. A message with an
+// ordering key is only indexed once the earlier messages with the same key are acked.
+//
+// The control plane only creates Pub/Sub topics and subscriptions, so the fanout and retry pools
+// create those of the targets config with EnsureSubscription. Messages published to a topic before
+// its subscription is created are not delivered to it, like with Pub/Sub.
+type BoltClient struct {
+	db *bbolt.DB
+
+	// PollInterval is how often a subscription is polled when it has no message to deliver.
+	PollInterval time.Duration
+	// AckDeadline is how long a received message is leased to its receiver. A message that is
+	// neither acked nor nacked by then, e.g. because its receiver crashed, is redelivered.
+	AckDeadline time.Duration
+	// RedeliveryDelay is the delay before a nacked message is redelivered.
+	RedeliveryDelay time.Duration
+}
+
+var (
+	_ Client      = (*BoltClient)(nil)
+	_ Provisioner = (*BoltClient)(nil)
+)
+
+// NewBoltClient returns a BoltClient that stores messages in the database file at path. The file
+// is created if it doesn't exist. It fails if another process doesn't release the lock of the file
+// within DefaultBoltLockTimeout.
+func NewBoltClient(path string) (*BoltClient, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: DefaultBoltLockTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue database %q: %w", path, err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltTopicsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(boltSubscriptionsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltClient{
+		db:              db,
+		PollInterval:    DefaultBoltPollInterval,
+		AckDeadline:     DefaultBoltAckDeadline,
+		RedeliveryDelay: DefaultBoltRedeliveryDelay,
+	}, nil
+}
+
+// Close closes the database file. The topics and subscriptions of the client fail afterwards.
+func (c *BoltClient) Close() error {
+	return c.db.Close()
+}
+
+// EnsureSubscription implements Provisioner.
+func (c *BoltClient) EnsureSubscription(id, topic string) error {
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		subs := tx.Bucket(boltSubscriptionsBucket)
+		if sub := subs.Bucket([]byte(id)); sub != nil {
+			if t := string(sub.Get(boltTopicKey)); t != topic {
+				return fmt.Errorf("subscription %q is a subscription of topic %q", id, t)
+			}
+			return nil
+		}
+		t, err := tx.Bucket(boltTopicsBucket).CreateBucketIfNotExists([]byte(topic))
+		if err != nil {
+			return err
+		}
+		if err := t.Put([]byte(id), nil); err != nil {
+			return err
+		}
+		sub, err := subs.CreateBucket([]byte(id))
+		if err != nil {
+			return err
+		}
+		if err := sub.Put(boltTopicKey, []byte(topic)); err != nil {
+			return err
+		}
+		for _, name := range [][]byte{boltMessagesBucket, boltScheduleBucket, boltOrderingKeysBucket} {
+			if _, err := sub.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Topic implements Client.
+func (c *BoltClient) Topic(id string, _ pubsub.PublishSettings, ordered bool) Topic {
+	return &boltTopic{client: c, id: id, ordered: ordered}
+}
+
+// Subscription implements Client.
+func (c *BoltClient) Subscription(id string, settings pubsub.ReceiveSettings) Subscription {
+	return &boltSubscription{client: c, id: id, settings: settings}
+}
+
+// InProject implements Client. The bolt backend has no projects, so it returns itself.
+func (c *BoltClient) InProject(string) (Client, error) {
+	return c, nil
+}
+
+// boltMessage is a message stored in the messages bucket of a subscription, keyed by its sequence
+// number in the subscription.
+type boltMessage struct {
+	ID          string            `json:"id"`
+	Data        []byte            `json:"data,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+	// NotBefore is the time before which the message is not delivered, in Unix nanoseconds. It
+	// is the end of the lease of a received message, or the redelivery time of a nacked one.
+	NotBefore int64 `json:"notBefore,omitempty"`
+}
+
+// boltSubscriptionBuckets are the buckets of a subscription.
+type boltSubscriptionBuckets struct {
+	// messages holds the messages, keyed by their sequence number.
+	messages *bbolt.Bucket
+	// schedule indexes the messages that can be delivered once their NotBefore time is reached,
+	// keyed by their NotBefore time followed by their sequence number.
+	schedule *bbolt.Bucket
+	// orderingKeys holds a bucket of the sequence numbers of the messages of each ordering key.
+	// Only the first message of an ordering key is in the schedule.
+	orderingKeys *bbolt.Bucket
+}
+
+func boltSubscriptionBucketsOf(tx *bbolt.Tx, subID []byte) *boltSubscriptionBuckets {
+	sub := tx.Bucket(boltSubscriptionsBucket).Bucket(subID)
+	if sub == nil {
+		return nil
+	}
+	return &boltSubscriptionBuckets{
+		messages:     sub.Bucket(boltMessagesBucket),
+		schedule:     sub.Bucket(boltScheduleBucket),
+		orderingKeys: sub.Bucket(boltOrderingKeysBucket),
+	}
+}
+
+// add adds a new message to the subscription.
+func (b *boltSubscriptionBuckets) add(orderingKey string, value []byte) error {
+	seq, err := b.messages.NextSequence()
+	if err != nil {
+		return err
+	}
+	key := boltSequenceKey(seq)
+	if err := b.messages.Put(key, value); err != nil {
+		return err
+	}
+	if orderingKey != "" {
+		keyMsgs, err := b.orderingKeys.CreateBucketIfNotExists([]byte(orderingKey))
+		if err != nil {
+			return err
+		}
+		first, _ := keyMsgs.Cursor().First()
+		if err := keyMsgs.Put(key, nil); err != nil {
+			return err
+		}
+		if first != nil {
+			// The message is scheduled once the earlier messages of its ordering key are acked.
+			return nil
+		}
+	}
+	return b.schedule.Put(boltScheduleKey(0, key), nil)
+}
+
+// reschedule sets the NotBefore time of the message with the given key.
+func (b *boltSubscriptionBuckets) reschedule(key []byte, bm *boltMessage, notBefore int64) error {
+	if err := b.schedule.Delete(boltScheduleKey(bm.NotBefore, key)); err != nil {
+		return err
+	}
+	bm.NotBefore = notBefore
+	value, err := json.Marshal(bm)
+	if err != nil {
+		return err
+	}
+	if err := b.messages.Put(key, value); err != nil {
+		return err
+	}
+	return b.schedule.Put(boltScheduleKey(notBefore, key), nil)
+}
+
+// remove removes the message with the given key, and schedules the next message of its ordering
+// key.
+func (b *boltSubscriptionBuckets) remove(key []byte, bm *boltMessage) error {
+	if err := b.schedule.Delete(boltScheduleKey(bm.NotBefore, key)); err != nil {
+		return err
+	}
+	if err := b.messages.Delete(key); err != nil {
+		return err
+	}
+	if bm.OrderingKey == "" {
+		return nil
+	}
+	keyMsgs := b.orderingKeys.Bucket([]byte(bm.OrderingKey))
+	if keyMsgs == nil {
+		return nil
+	}
+	if err := keyMsgs.Delete(key); err != nil {
+		return err
+	}
+	next, _ := keyMsgs.Cursor().First()
+	if next == nil {
+		return b.orderingKeys.DeleteBucket([]byte(bm.OrderingKey))
+	}
+	nm, err := b.get(next)
+	if err != nil || nm == nil {
+		return err
+	}
+	return b.schedule.Put(boltScheduleKey(nm.NotBefore, next), nil)
+}
+
+// get returns the message with the given key, or nil if it doesn't exist.
+func (b *boltSubscriptionBuckets) get(key []byte) (*boltMessage, error) {
+	v := b.messages.Get(key)
+	if v == nil {
+		return nil, nil
+	}
+	var bm boltMessage
+	if err := json.Unmarshal(v, &bm); err != nil {
+		return nil, fmt.Errorf("failed to decode message %x: %w", key, err)
+	}
+	return &bm, nil
+}
+
+func (c *BoltClient) publish(topicID string, msg *Message) (string, error) {
+	var id string
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		topics := tx.Bucket(boltTopicsBucket)
+		t := topics.Bucket([]byte(topicID))
+		if t == nil {
+			return fmt.Errorf("topic %q: %w", topicID, ErrNotFound)
+		}
+		seq, err := topics.NextSequence()
+		if err != nil {
+			return err
+		}
+		id = strconv.FormatUint(seq, 10)
+		value, err := json.Marshal(&boltMessage{
+			ID:          id,
+			Data:        msg.Data,
+			Attributes:  msg.Attributes,
+			OrderingKey: msg.OrderingKey,
+		})
+		if err != nil {
+			return err
+		}
+		return t.ForEach(func(subID, _ []byte) error {
+			if b := boltSubscriptionBucketsOf(tx, subID); b != nil {
+				return b.add(msg.OrderingKey, value)
+			}
+			return nil
+		})
+	})
+	return id, err
+}
+
+// lease returns the first message of the subscription that can be delivered and leases it until
+// the ack deadline, or nil if there is none. A message with an ordering key can't be delivered
+// while an earlier message with the same key is in the subscription.
+func (c *BoltClient) lease(subID string) (key []byte, m *boltMessage, err error) {
+	err = c.db.Update(func(tx *bbolt.Tx) error {
+		b := boltSubscriptionBucketsOf(tx, []byte(subID))
+		if b == nil {
+			return fmt.Errorf("subscription %q: %w", subID, ErrNotFound)
+		}
+		now := time.Now()
+		k, _ := b.schedule.Cursor().First()
+		if k == nil || int64(binary.BigEndian.Uint64(k)) > now.UnixNano() {
+			return nil
+		}
+		// The key is only valid during the transaction.
+		key = append([]byte(nil), k[8:]...)
+		bm, err := b.get(key)
+		if err != nil {
+			return fmt.Errorf("subscription %q: %w", subID, err)
+		}
+		if bm == nil {
+			// The index is out of sync with the messages, drop the entry.
+			return b.schedule.Delete(k)
+		}
+		m = bm
+		return b.reschedule(key, bm, now.Add(c.AckDeadline).UnixNano())
+	})
+	return key, m, err
+}
+
+// done deletes an acked message, or schedules the redelivery of a nacked message. A nacked message
+// is left alone if its lease expired and it was received again.
+func (c *BoltClient) done(subID string, key []byte, lease int64, ack bool) error {
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		b := boltSubscriptionBucketsOf(tx, []byte(subID))
+		if b == nil {
+			return nil
+		}
+		bm, err := b.get(key)
+		if err != nil || bm == nil {
+			return err
+		}
+		if ack {
+			return b.remove(key, bm)
+		}
+		if bm.NotBefore != lease {
+			return nil
+		}
+		return b.reschedule(key, bm, time.Now().Add(c.RedeliveryDelay).UnixNano())
+	})
+}
+
+func boltSequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// boltScheduleKey returns the key of a message in the schedule bucket, which sorts the messages by
+// their NotBefore time and then by their order in the subscription.
+func boltScheduleKey(notBefore int64, key []byte) []byte {
+	k := make([]byte, 8, 8+len(key))
+	binary.BigEndian.PutUint64(k, uint64(notBefore))
+	return append(k, key...)
+}
+
+type boltTopic struct {
+	client  *BoltClient
+	id      string
+	ordered bool
+}
+
+func (t *boltTopic) ID() string {
+	return t.id
+}
+
+func (t *boltTopic) Ordered() bool {
+	return t.ordered
+}
+
+func (t *boltTopic) Publish(ctx context.Context, msg *Message) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if msg.OrderingKey != "" && !t.ordered {
+		return "", ErrOrderingDisabled
+	}
+	return t.client.publish(t.id, msg)
+}
+
+// Stop is a no-op since messages are published synchronously.
+func (t *boltTopic) Stop() {}
+
+type boltSubscription struct {
+	client   *BoltClient
+	id       string
+	settings pubsub.ReceiveSettings
+}
+
+func (s *boltSubscription) ID() string {
+	return s.id
+}
+
+// Receive implements Subscription. At most MaxOutstandingMessages messages of the receive settings
+// are processed at the same time.
+func (s *boltSubscription) Receive(ctx context.Context, f func(context.Context, *Message)) error {
+	maxOutstanding := s.settings.MaxOutstandingMessages
+	if maxOutstanding <= 0 {
+		maxOutstanding = pubsub.DefaultReceiveSettings.MaxOutstandingMessages
+	}
+	sem := make(chan struct{}, maxOutstanding)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+		key, m, err := s.client.lease(s.id)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			<-sem
+			select {
+			case <-time.After(s.client.PollInterval):
+				continue
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			f(ctx, NewReceivedMessage(&Message{
+				ID:          m.ID,
+				Data:        m.Data,
+				Attributes:  m.Attributes,
+				OrderingKey: m.OrderingKey,
+			}, func(ack bool) {
+				// A message whose ack is lost is redelivered once its lease expires.
+				_ = s.client.done(s.id, key, m.NotBefore, ack)
+			}))
+		}()
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/queue"
+)
+
+func newTestBoltClient(t *testing.T, path string) *queue.BoltClient {
+	t.Helper()
+	c, err := queue.NewBoltClient(path)
+	if err != nil {
+		t.Fatalf("NewBoltClient() = %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	c.PollInterval = time.Millisecond
+	c.RedeliveryDelay = time.Millisecond
+	return c
+}
+
+// receiveBoltData receives messages from the subscription until n messages are acked, and
+// returns the data of the messages in the order they were received. ack decides whether each
+// message is acked.
+func receiveBoltData(t *testing.T, c queue.Client, subID string, n int, ack func(*queue.Message) bool) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var got []string
+	acked := 0
+	settings := pubsub.DefaultReceiveSettings
+	settings.MaxOutstandingMessages = 1
+	err := c.Subscription(subID, settings).Receive(ctx, func(_ context.Context, m *queue.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, string(m.Data))
+		if !ack(m) {
+			m.Nack()
+			return
+		}
+		m.Ack()
+		if acked++; acked == n {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	if acked != n {
+		t.Fatalf("acked %d messages, want %d", acked, n)
+	}
+	return got
+}
+
+func ackAllBolt(*queue.Message) bool { return true }
+
+func TestBoltPublishReceive(t *testing.T) {
+	ctx := context.Background()
+	c := newTestBoltClient(t, filepath.Join(t.TempDir(), "queue.db"))
+	for _, sub := range []string{"sub1", "sub2"} {
+		if err := c.EnsureSubscription(sub, "topic"); err != nil {
+			t.Fatalf("EnsureSubscription(%q) = %v", sub, err)
+		}
+	}
+	if err := c.EnsureSubscription("sub1", "topic"); err != nil {
+		t.Errorf("EnsureSubscription() of an existing subscription = %v", err)
+	}
+	if err := c.EnsureSubscription("sub1", "other"); err == nil {
+		t.Error("EnsureSubscription() of a subscription of another topic succeeded")
+	}
+
+	topic := c.Topic("topic", pubsub.DefaultPublishSettings, false)
+	for _, data := range []string{"a", "b"} {
+		if _, err := topic.Publish(ctx, &queue.Message{Data: []byte(data), Attributes: map[string]string{"k": "v"}}); err != nil {
+			t.Fatalf("Publish() = %v", err)
+		}
+	}
+
+	for _, sub := range []string{"sub1", "sub2"} {
+		got := receiveBoltData(t, c, sub, 2, func(m *queue.Message) bool {
+			if diff := cmp.Diff(map[string]string{"k": "v"}, m.Attributes); diff != "" {
+				t.Errorf("unexpected attributes (-want, +got) = %v", diff)
+			}
+			return true
+		})
+		if diff := cmp.Diff([]string{"a", "b"}, got); diff != "" {
+			t.Errorf("unexpected messages of %q (-want, +got) = %v", sub, diff)
+		}
+	}
+}
+
+func TestBoltPublishErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestBoltClient(t, filepath.Join(t.TempDir(), "queue.db"))
+	if err := c.EnsureSubscription("sub", "unordered"); err != nil {
+		t.Fatalf("EnsureSubscription() = %v", err)
+	}
+
+	if _, err := c.Topic("missing", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{}); !errors.Is(err, queue.ErrNotFound) {
+		t.Errorf("Publish() to a missing topic = %v, want %v", err, queue.ErrNotFound)
+	}
+	if _, err := c.Topic("unordered", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{OrderingKey: "key"}); !errors.Is(err, queue.ErrOrderingDisabled) {
+		t.Errorf("Publish() with an ordering key = %v, want %v", err, queue.ErrOrderingDisabled)
+	}
+	if err := c.Subscription("missing", pubsub.DefaultReceiveSettings).Receive(ctx, func(context.Context, *queue.Message) {}); !errors.Is(err, queue.ErrNotFound) {
+		t.Errorf("Receive() of a missing subscription = %v, want %v", err, queue.ErrNotFound)
+	}
+}
+
+func TestBoltNackRedelivery(t *testing.T) {
+	ctx := context.Background()
+	c := newTestBoltClient(t, filepath.Join(t.TempDir(), "queue.db"))
+	if err := c.EnsureSubscription("sub", "topic"); err != nil {
+		t.Fatalf("EnsureSubscription() = %v", err)
+	}
+	if _, err := c.Topic("topic", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{Data: []byte("a")}); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+
+	attempts := 0
+	got := receiveBoltData(t, c, "sub", 1, func(*queue.Message) bool {
+		attempts++
+		return attempts == 3
+	})
+	if diff := cmp.Diff([]string{"a", "a", "a"}, got); diff != "" {
+		t.Errorf("unexpected messages (-want, +got) = %v", diff)
+	}
+}
+
+func TestBoltOrderedDelivery(t *testing.T) {
+	ctx := context.Background()
+	c := newTestBoltClient(t, filepath.Join(t.TempDir(), "queue.db"))
+	if err := c.EnsureSubscription("sub", "topic"); err != nil {
+		t.Fatalf("EnsureSubscription() = %v", err)
+	}
+	topic := c.Topic("topic", pubsub.DefaultPublishSettings, true)
+	for _, data := range []string{"1", "2", "3"} {
+		if _, err := topic.Publish(ctx, &queue.Message{Data: []byte(data), OrderingKey: "key"}); err != nil {
+			t.Fatalf("Publish() = %v", err)
+		}
+	}
+
+	// Receive concurrently to check that messages with the same key are not processed at the
+	// same time.
+	rctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	var got []string
+	outstanding := 0
+	nacked := false
+	err := c.Subscription("sub", pubsub.DefaultReceiveSettings).Receive(rctx, func(_ context.Context, m *queue.Message) {
+		mu.Lock()
+		outstanding++
+		if outstanding > 1 {
+			t.Error("messages with the same ordering key are processed concurrently")
+		}
+		got = append(got, string(m.Data))
+		nack := !nacked
+		nacked = true
+		done := len(got) == 4
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		outstanding--
+		mu.Unlock()
+		if nack {
+			m.Nack()
+			return
+		}
+		m.Ack()
+		if done {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	if diff := cmp.Diff([]string{"1", "1", "2", "3"}, got); diff != "" {
+		t.Errorf("unexpected messages (-want, +got) = %v", diff)
+	}
+}
+
+func TestBoltScheduledMessagesDontBlockDueMessages(t *testing.T) {
+	ctx := context.Background()
+	c := newTestBoltClient(t, filepath.Join(t.TempDir(), "queue.db"))
+	c.RedeliveryDelay = time.Hour
+	if err := c.EnsureSubscription("sub", "topic"); err != nil {
+		t.Fatalf("EnsureSubscription() = %v", err)
+	}
+	topic := c.Topic("topic", pubsub.DefaultPublishSettings, true)
+	for _, m := range []*queue.Message{
+		{Data: []byte("nacked")},
+		{Data: []byte("key1"), OrderingKey: "key1"},
+		{Data: []byte("key2"), OrderingKey: "key2"},
+		{Data: []byte("key1 blocked"), OrderingKey: "key1"},
+		{Data: []byte("last")},
+	} {
+		if _, err := topic.Publish(ctx, m); err != nil {
+			t.Fatalf("Publish() = %v", err)
+		}
+	}
+
+	// The nacked message is not redelivered before the delay, and the second message of key1 is
+	// only delivered once the first one is acked.
+	got := receiveBoltData(t, c, "sub", 4, func(m *queue.Message) bool {
+		return string(m.Data) != "nacked"
+	})
+	if diff := cmp.Diff([]string{"nacked", "key1", "key2", "key1 blocked", "last"}, got); diff != "" {
+		t.Errorf("unexpected messages (-want, +got) = %v", diff)
+	}
+}
+
+func TestBoltRedeliversUnackedMessages(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "queue.db")
+	c := newTestBoltClient(t, path)
+	c.AckDeadline = 50 * time.Millisecond
+	if err := c.EnsureSubscription("sub", "topic"); err != nil {
+		t.Fatalf("EnsureSubscription() = %v", err)
+	}
+	if _, err := c.Topic("topic", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{Data: []byte("a")}); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+
+	// The first receiver stops without acking the message, as if its process crashed.
+	rctx, cancel := context.WithCancel(ctx)
+	err := c.Subscription("sub", pubsub.DefaultReceiveSettings).Receive(rctx, func(context.Context, *queue.Message) {
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	// The message is kept in the file, and redelivered to a new client once its lease expires.
+	got := receiveBoltData(t, newTestBoltClient(t, path), "sub", 1, ackAllBolt)
+	if diff := cmp.Diff([]string{"a"}, got); diff != "" {
+		t.Errorf("unexpected messages (-want, +got) = %v", diff)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	ctx := context.Background()
+	if _, err := queue.NewClient(ctx, "", queue.Settings{Backend: queue.BoltBackend}); err == nil {
+		t.Error("NewClient() of the bolt backend without a path succeeded")
+	}
+	if _, err := queue.NewClient(ctx, "", queue.Settings{Backend: "unknown"}); err == nil {
+		t.Error("NewClient() of an unknown backend succeeded")
+	}
+	c, err := queue.NewClient(ctx, "", queue.Settings{Backend: queue.BoltBackend, BoltPath: filepath.Join(t.TempDir(), "queue.db")})
+	if err != nil {
+		t.Fatalf("NewClient() of the bolt backend = %v", err)
+	}
+	if _, ok := c.(*queue.BoltClient); !ok {
+		t.Errorf("NewClient() of the bolt backend = %T, want *queue.BoltClient", c)
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package memory provides an in-process queue backend for local development and hermetic tests.
+// Messages are only shared by the components that use the same Client, so the backend cannot
+// decouple data plane components running in different processes.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/broker/queue"
+)
+
+// DefaultRedeliveryDelay is the delay before a nacked message is redelivered.
+const DefaultRedeliveryDelay = 100 * time.Millisecond
+
+var (
+	// ErrNotFound is the error when a topic or a subscription doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrOrderingDisabled is the error when a message with an ordering key is published to a
+	// topic that doesn't preserve the order of messages.
+	ErrOrderingDisabled = errors.New("ordering key is set on a topic without message ordering")
+)
+
+// Client is an in-memory queue.Client. Like Pub/Sub, messages published to a topic are delivered
+// to each subscription of the topic that exists at the time of publishing.
+type Client struct {
+	// RedeliveryDelay is the delay before a nacked message is redelivered.
+	RedeliveryDelay time.Duration
+
+	mu     sync.Mutex
+	topics map[string]map[string]*subscription
+	subs   map[string]*subscription
+	lastID int64
+}
+
+var _ queue.Client = (*Client)(nil)
+
+// NewClient creates a new Client with no topics.
+func NewClient() *Client {
+	return &Client{
+		RedeliveryDelay: DefaultRedeliveryDelay,
+		topics:          make(map[string]map[string]*subscription),
+		subs:            make(map[string]*subscription),
+	}
+}
+
+// CreateTopic creates a topic if it doesn't exist.
+func (c *Client) CreateTopic(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.topics[id]; !ok {
+		c.topics[id] = make(map[string]*subscription)
+	}
+}
+
+// DeleteTopic deletes a topic. Its subscriptions keep their pending messages.
+func (c *Client) DeleteTopic(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.topics, id)
+}
+
+// CreateSubscription creates a subscription to a topic. It returns ErrNotFound if the topic
+// doesn't exist.
+func (c *Client) CreateSubscription(id, topic string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subs, ok := c.topics[topic]
+	if !ok {
+		return fmt.Errorf("topic %q: %w", topic, ErrNotFound)
+	}
+	if _, ok := c.subs[id]; ok {
+		return fmt.Errorf("subscription %q already exists", id)
+	}
+	s := newSubscription()
+	subs[id] = s
+	c.subs[id] = s
+	return nil
+}
+
+// DeleteSubscription deletes a subscription and stops its receivers.
+func (c *Client) DeleteSubscription(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.subs[id]
+	if !ok {
+		return
+	}
+	delete(c.subs, id)
+	for _, subs := range c.topics {
+		delete(subs, id)
+	}
+	s.delete()
+}
+
+// Topic implements queue.Client.
+func (c *Client) Topic(id string, _ pubsub.PublishSettings, ordered bool) queue.Topic {
+	return &topic{client: c, id: id, ordered: ordered}
+}
+
+// Subscription implements queue.Client.
+func (c *Client) Subscription(id string, settings pubsub.ReceiveSettings) queue.Subscription {
+	return &subscriptionHandle{client: c, id: id, settings: settings}
+}
+
+// InProject implements queue.Client. The in-memory backend has no projects, so it returns itself.
+func (c *Client) InProject(string) (queue.Client, error) {
+	return c, nil
+}
+
+func (c *Client) publish(topicID string, msg *queue.Message) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subs, ok := c.topics[topicID]
+	if !ok {
+		return "", fmt.Errorf("topic %q: %w", topicID, ErrNotFound)
+	}
+	c.lastID++
+	id := strconv.FormatInt(c.lastID, 10)
+	for _, s := range subs {
+		s.push(&message{
+			id:          id,
+			data:        msg.Data,
+			attributes:  copyAttributes(msg.Attributes),
+			orderingKey: msg.OrderingKey,
+		})
+	}
+	return id, nil
+}
+
+func (c *Client) subscription(id string) (*subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.subs[id]
+	if !ok {
+		return nil, fmt.Errorf("subscription %q: %w", id, ErrNotFound)
+	}
+	return s, nil
+}
+
+func copyAttributes(attrs map[string]string) map[string]string {
+	if attrs == nil {
+		return nil
+	}
+	c := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		c[k] = v
+	}
+	return c
+}
+
+type topic struct {
+	client  *Client
+	id      string
+	ordered bool
+}
+
+func (t *topic) ID() string {
+	return t.id
+}
+
+func (t *topic) Ordered() bool {
+	return t.ordered
+}
+
+func (t *topic) Publish(ctx context.Context, msg *queue.Message) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if msg.OrderingKey != "" && !t.ordered {
+		return "", ErrOrderingDisabled
+	}
+	return t.client.publish(t.id, msg)
+}
+
+// Stop is a no-op since messages are published synchronously.
+func (t *topic) Stop() {}
+
+type subscriptionHandle struct {
+	client   *Client
+	id       string
+	settings pubsub.ReceiveSettings
+}
+
+func (h *subscriptionHandle) ID() string {
+	return h.id
+}
+
+// Receive implements queue.Subscription. At most MaxOutstandingMessages messages of the receive
+// settings are processed at the same time.
+func (h *subscriptionHandle) Receive(ctx context.Context, f func(context.Context, *queue.Message)) error {
+	s, err := h.client.subscription(h.id)
+	if err != nil {
+		return err
+	}
+	maxOutstanding := h.settings.MaxOutstandingMessages
+	if maxOutstanding <= 0 {
+		maxOutstanding = pubsub.DefaultReceiveSettings.MaxOutstandingMessages
+	}
+	sem := make(chan struct{}, maxOutstanding)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+		m, err := s.next(ctx)
+		if err != nil {
+			return fmt.Errorf("subscription %q: %w", h.id, err)
+		}
+		if m == nil {
+			return nil
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			f(ctx, queue.NewReceivedMessage(&queue.Message{
+				ID:          m.id,
+				Data:        m.data,
+				Attributes:  copyAttributes(m.attributes),
+				OrderingKey: m.orderingKey,
+			}, func(ack bool) {
+				s.done(m, ack, h.client.RedeliveryDelay)
+			}))
+		}()
+	}
+}
+
+type message struct {
+	id          string
+	data        []byte
+	attributes  map[string]string
+	orderingKey string
+}
+
+// subscription holds the pending messages of a subscription. A message with an ordering key is
+// not delivered while an earlier message with the same key is outstanding.
+type subscription struct {
+	mu      sync.Mutex
+	pending []*message
+	// outstandingKeys are the ordering keys of the messages being processed or waiting for
+	// redelivery.
+	outstandingKeys map[string]bool
+	// changed is closed and replaced when messages can be delivered or the subscription is
+	// deleted.
+	changed chan struct{}
+	deleted bool
+}
+
+func newSubscription() *subscription {
+	return &subscription{
+		outstandingKeys: make(map[string]bool),
+		changed:         make(chan struct{}),
+	}
+}
+
+func (s *subscription) push(m *message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, m)
+	s.notifyLocked()
+}
+
+func (s *subscription) delete() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = true
+	s.notifyLocked()
+}
+
+func (s *subscription) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// next blocks until a message can be delivered. It returns nil if ctx is done.
+func (s *subscription) next(ctx context.Context) (*message, error) {
+	for {
+		s.mu.Lock()
+		if s.deleted {
+			s.mu.Unlock()
+			return nil, ErrNotFound
+		}
+		for i, m := range s.pending {
+			if m.orderingKey != "" {
+				if s.outstandingKeys[m.orderingKey] {
+					continue
+				}
+				s.outstandingKeys[m.orderingKey] = true
+			}
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			s.mu.Unlock()
+			return m, nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, nil
+		}
+	}
+}
+
+// done acks or nacks a delivered message. A nacked message is redelivered after the delay, and
+// before the later messages with the same ordering key.
+func (s *subscription) done(m *message, ack bool, redeliveryDelay time.Duration) {
+	if ack {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if m.orderingKey != "" {
+			delete(s.outstandingKeys, m.orderingKey)
+			s.notifyLocked()
+		}
+		return
+	}
+	time.AfterFunc(redeliveryDelay, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.pending = append([]*message{m}, s.pending...)
+		if m.orderingKey != "" {
+			delete(s.outstandingKeys, m.orderingKey)
+		}
+		s.notifyLocked()
+	})
+}
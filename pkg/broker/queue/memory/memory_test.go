@@ -0,0 +1,225 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/queue"
+)
+
+const (
+	testTopic = "topic"
+	testSub   = "sub"
+)
+
+// receiveData receives messages from the subscription until n messages are acked, and returns
+// the data of the messages in the order they were received. ack decides whether each message is
+// acked.
+func receiveData(t *testing.T, c *Client, subID string, n int, ack func(*queue.Message) bool) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var got []string
+	acked := 0
+	settings := pubsub.DefaultReceiveSettings
+	settings.MaxOutstandingMessages = 1
+	err := c.Subscription(subID, settings).Receive(ctx, func(_ context.Context, m *queue.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, string(m.Data))
+		if !ack(m) {
+			m.Nack()
+			return
+		}
+		m.Ack()
+		if acked++; acked == n {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	if acked != n {
+		t.Fatalf("acked %d messages, want %d", acked, n)
+	}
+	return got
+}
+
+func ackAll(*queue.Message) bool { return true }
+
+func TestPublishReceive(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient()
+	c.CreateTopic(testTopic)
+	for _, sub := range []string{"sub1", "sub2"} {
+		if err := c.CreateSubscription(sub, testTopic); err != nil {
+			t.Fatalf("CreateSubscription(%q) = %v", sub, err)
+		}
+	}
+
+	topic := c.Topic(testTopic, pubsub.DefaultPublishSettings, false)
+	for _, data := range []string{"a", "b"} {
+		if _, err := topic.Publish(ctx, &queue.Message{Data: []byte(data), Attributes: map[string]string{"k": "v"}}); err != nil {
+			t.Fatalf("Publish() = %v", err)
+		}
+	}
+
+	for _, sub := range []string{"sub1", "sub2"} {
+		got := receiveData(t, c, sub, 2, func(m *queue.Message) bool {
+			if diff := cmp.Diff(map[string]string{"k": "v"}, m.Attributes); diff != "" {
+				t.Errorf("unexpected attributes (-want, +got) = %v", diff)
+			}
+			return true
+		})
+		if diff := cmp.Diff([]string{"a", "b"}, got); diff != "" {
+			t.Errorf("unexpected messages of %q (-want, +got) = %v", sub, diff)
+		}
+	}
+}
+
+func TestPublishErrors(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient()
+	c.CreateTopic("unordered")
+
+	if _, err := c.Topic("missing", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Publish() to a missing topic = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := c.Topic("unordered", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{OrderingKey: "key"}); !errors.Is(err, ErrOrderingDisabled) {
+		t.Errorf("Publish() with an ordering key = %v, want %v", err, ErrOrderingDisabled)
+	}
+	if err := c.CreateSubscription(testSub, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CreateSubscription() to a missing topic = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestNackRedelivery(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient()
+	c.RedeliveryDelay = time.Millisecond
+	c.CreateTopic(testTopic)
+	if err := c.CreateSubscription(testSub, testTopic); err != nil {
+		t.Fatalf("CreateSubscription() = %v", err)
+	}
+	if _, err := c.Topic(testTopic, pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{Data: []byte("a")}); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+
+	attempts := 0
+	got := receiveData(t, c, testSub, 1, func(*queue.Message) bool {
+		attempts++
+		return attempts == 3
+	})
+	if diff := cmp.Diff([]string{"a", "a", "a"}, got); diff != "" {
+		t.Errorf("unexpected messages (-want, +got) = %v", diff)
+	}
+}
+
+func TestOrderedDelivery(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient()
+	c.RedeliveryDelay = time.Millisecond
+	c.CreateTopic(testTopic)
+	if err := c.CreateSubscription(testSub, testTopic); err != nil {
+		t.Fatalf("CreateSubscription() = %v", err)
+	}
+	topic := c.Topic(testTopic, pubsub.DefaultPublishSettings, true)
+	for _, data := range []string{"1", "2", "3"} {
+		if _, err := topic.Publish(ctx, &queue.Message{Data: []byte(data), OrderingKey: "key"}); err != nil {
+			t.Fatalf("Publish() = %v", err)
+		}
+	}
+
+	// Receive concurrently to check that messages with the same key are not processed at the
+	// same time.
+	rctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	var got []string
+	outstanding := 0
+	nacked := false
+	err := c.Subscription(testSub, pubsub.DefaultReceiveSettings).Receive(rctx, func(_ context.Context, m *queue.Message) {
+		mu.Lock()
+		outstanding++
+		if outstanding > 1 {
+			t.Error("messages with the same ordering key are processed concurrently")
+		}
+		got = append(got, string(m.Data))
+		nack := !nacked
+		nacked = true
+		done := len(got) == 4
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		outstanding--
+		mu.Unlock()
+		if nack {
+			m.Nack()
+			return
+		}
+		m.Ack()
+		if done {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	if diff := cmp.Diff([]string{"1", "1", "2", "3"}, got); diff != "" {
+		t.Errorf("unexpected messages (-want, +got) = %v", diff)
+	}
+}
+
+func TestReceiveDeletedSubscription(t *testing.T) {
+	c := NewClient()
+	c.CreateTopic(testTopic)
+	if err := c.CreateSubscription(testSub, testTopic); err != nil {
+		t.Fatalf("CreateSubscription() = %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Subscription(testSub, pubsub.DefaultReceiveSettings).Receive(context.Background(), func(context.Context, *queue.Message) {})
+	}()
+	// Give the receiver time to start waiting for messages.
+	time.Sleep(10 * time.Millisecond)
+	c.DeleteSubscription(testSub)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Receive() = %v, want %v", err, ErrNotFound)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Receive() didn't return after the subscription was deleted")
+	}
+
+	if err := c.Subscription(testSub, pubsub.DefaultReceiveSettings).Receive(context.Background(), func(context.Context, *queue.Message) {}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Receive() of a missing subscription = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/option"
+)
+
+// NewPubsubClient returns a Client backed by Pub/Sub.
+func NewPubsubClient(client *pubsub.Client) Client {
+	return NewPubsubClientWithOptions(client)
+}
+
+// NewPubsubClientWithOptions returns a Client backed by Pub/Sub. The Pub/Sub clients of other
+// projects are created with the given options.
+func NewPubsubClientWithOptions(client *pubsub.Client, opts ...option.ClientOption) Client {
+	return &pubsubClient{
+		client: client,
+		projects: &projectClients{
+			opts:    opts,
+			clients: make(map[string]Client),
+		},
+	}
+}
+
+type pubsubClient struct {
+	client *pubsub.Client
+
+	// projects is shared by the clients of all projects.
+	projects *projectClients
+}
+
+// projectClients caches a Pub/Sub client per project, so that the topics and subscriptions of
+// all Brokers in a project share the connections of one client.
+type projectClients struct {
+	opts []option.ClientOption
+
+	clients map[string]Client
+	mut     sync.Mutex
+}
+
+func (c *pubsubClient) InProject(project string) (Client, error) {
+	if project == "" {
+		return c, nil
+	}
+	c.projects.mut.Lock()
+	defer c.projects.mut.Unlock()
+	if client, ok := c.projects.clients[project]; ok {
+		return client, nil
+	}
+	// The client lives as long as the process, so it is not bound to a request context.
+	client, err := pubsub.NewClient(context.Background(), project, c.projects.opts...)
+	if err != nil {
+		return nil, err
+	}
+	pc := &pubsubClient{client: client, projects: c.projects}
+	c.projects.clients[project] = pc
+	return pc, nil
+}
+
+func (c *pubsubClient) Topic(id string, settings pubsub.PublishSettings, ordered bool) Topic {
+	topic := c.client.Topic(id)
+	topic.PublishSettings = settings
+	topic.EnableMessageOrdering = ordered
+	return &pubsubTopic{topic: topic}
+}
+
+func (c *pubsubClient) Subscription(id string, settings pubsub.ReceiveSettings) Subscription {
+	sub := c.client.Subscription(id)
+	sub.ReceiveSettings = settings
+	return &pubsubSubscription{sub: sub}
+}
+
+type pubsubTopic struct {
+	topic *pubsub.Topic
+}
+
+func (t *pubsubTopic) ID() string {
+	return t.topic.ID()
+}
+
+func (t *pubsubTopic) Ordered() bool {
+	return t.topic.EnableMessageOrdering
+}
+
+func (t *pubsubTopic) Publish(ctx context.Context, msg *Message) (string, error) {
+	id, err := t.topic.Publish(ctx, &pubsub.Message{
+		Data:        msg.Data,
+		Attributes:  msg.Attributes,
+		OrderingKey: msg.OrderingKey,
+	}).Get(ctx)
+	if err != nil && msg.OrderingKey != "" {
+		// A failed publish pauses the ordering key so that later messages are not published out
+		// of order. The message is rejected and will be retried by the caller, so resume the key.
+		t.topic.ResumePublish(msg.OrderingKey)
+	}
+	return id, err
+}
+
+func (t *pubsubTopic) Stop() {
+	t.topic.Stop()
+}
+
+type pubsubSubscription struct {
+	sub *pubsub.Subscription
+}
+
+func (s *pubsubSubscription) ID() string {
+	return s.sub.ID()
+}
+
+func (s *pubsubSubscription) Receive(ctx context.Context, f func(context.Context, *Message)) error {
+	return s.sub.Receive(ctx, func(ctx context.Context, pm *pubsub.Message) {
+		f(ctx, NewReceivedMessage(&Message{
+			ID:          pm.ID,
+			Data:        pm.Data,
+			Attributes:  pm.Attributes,
+			OrderingKey: pm.OrderingKey,
+		}, func(ack bool) {
+			if ack {
+				pm.Ack()
+			} else {
+				pm.Nack()
+			}
+		}))
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+
+	"github.com/google/knative-gcp/pkg/broker/queue"
+)
+
+func TestPubsubClientInProject(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	srv := pstest.NewServer()
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial test pubsub server: %v", err)
+	}
+	defer conn.Close()
+	opts := []option.ClientOption{option.WithGRPCConn(conn)}
+
+	psclient, err := pubsub.NewClient(ctx, "cell-project", opts...)
+	if err != nil {
+		t.Fatalf("failed to create pubsub client: %v", err)
+	}
+	tenant, err := pubsub.NewClient(ctx, "tenant-project", opts...)
+	if err != nil {
+		t.Fatalf("failed to create pubsub client: %v", err)
+	}
+	topic, err := tenant.CreateTopic(ctx, "topic")
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	if _, err := tenant.CreateSubscription(ctx, "sub", pubsub.SubscriptionConfig{Topic: topic}); err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	client := queue.NewPubsubClientWithOptions(psclient, opts...)
+	if got, err := client.InProject(""); err != nil || got != client {
+		t.Errorf("InProject(\"\") = %v, %v, want the client itself", got, err)
+	}
+	inProject, err := client.InProject("tenant-project")
+	if err != nil {
+		t.Fatalf("InProject() = %v", err)
+	}
+	if again, err := client.InProject("tenant-project"); err != nil || again != inProject {
+		t.Errorf("InProject() = %v, %v, want the cached client", again, err)
+	}
+
+	if _, err := client.Topic("topic", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{Data: []byte("data")}); err == nil {
+		t.Error("Publish() to the topic of another project succeeded, want error")
+	}
+	if _, err := inProject.Topic("topic", pubsub.DefaultPublishSettings, false).Publish(ctx, &queue.Message{Data: []byte("data")}); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+
+	var got string
+	err = inProject.Subscription("sub", pubsub.DefaultReceiveSettings).Receive(ctx, func(ctx context.Context, m *queue.Message) {
+		m.Ack()
+		got = string(m.Data)
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	if got != "data" {
+		t.Errorf("received data got=%q, want=%q", got, "data")
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package queue abstracts the queues that decouple the broker data plane components. Events are
+// published by the ingress to the decouple topic of a broker and pulled by fanout, and failed
+// deliveries are published to the retry topic of a trigger and pulled by retry.
+//
+// Pub/Sub is the production backend. Other backends, such as the bbolt database file of
+// BoltClient or the in-memory queue in the memory package, allow the data plane to run without a
+// GCP project.
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// Client opens the topics and subscriptions of a queue backend. Topics and subscriptions are
+// created by the control plane, so opening one does not create it.
+type Client interface {
+	// Topic returns the topic with the given ID. If ordered is true, messages published with the
+	// same ordering key are delivered in the order they are published. Backends that are not
+	// Pub/Sub may ignore the publish settings.
+	Topic(id string, settings pubsub.PublishSettings, ordered bool) Topic
+
+	// Subscription returns the subscription with the given ID. Backends that are not Pub/Sub may
+	// only honor the flow control of the receive settings.
+	Subscription(id string, settings pubsub.ReceiveSettings) Subscription
+
+	// InProject returns a Client for the topics and subscriptions of the given project. An empty
+	// project is the project of the Client itself. Backends without projects return themselves.
+	InProject(project string) (Client, error)
+}
+
+// Provisioner is implemented by the backends whose topics and subscriptions are not created by
+// the control plane. The fanout and retry pools create the queues of the targets config with it
+// before they receive from them.
+type Provisioner interface {
+	// EnsureSubscription creates the subscription with the given ID to the topic, and the topic,
+	// if they don't exist.
+	EnsureSubscription(id, topic string) error
+}
+
+// Topic publishes messages to a queue.
+type Topic interface {
+	// ID returns the ID of the topic.
+	ID() string
+
+	// Ordered returns true if the topic preserves the order of messages with the same ordering
+	// key.
+	Ordered() bool
+
+	// Publish publishes the message and blocks until the backend accepted it. It returns the ID
+	// assigned to the message by the backend.
+	Publish(ctx context.Context, msg *Message) (string, error)
+
+	// Stop sends all remaining messages and stops the topic.
+	Stop()
+}
+
+// Subscription receives messages from a queue.
+type Subscription interface {
+	// ID returns the ID of the subscription.
+	ID() string
+
+	// Receive calls f with the received messages, concurrently, until ctx is done or a
+	// non-retryable error occurs. f must call Ack or Nack on each message. Nacked messages are
+	// redelivered.
+	Receive(ctx context.Context, f func(context.Context, *Message)) error
+}
+
+// Message is a message of a queue. Events are encoded in messages with the CloudEvents Pub/Sub
+// protocol binding regardless of the backend.
+type Message struct {
+	// ID is the ID assigned to the message by the backend. It is only set on received messages.
+	ID string
+
+	// Data is the payload of the message.
+	Data []byte
+
+	// Attributes are the attributes of the message.
+	Attributes map[string]string
+
+	// OrderingKey identifies the messages that must be delivered in order. It is only
+	// honored by ordered topics.
+	OrderingKey string
+
+	// done acknowledges the message if its argument is true, and nacks it otherwise.
+	done func(ack bool)
+}
+
+// NewReceivedMessage returns the message with Ack and Nack calling done. done is called at most
+// once.
+func NewReceivedMessage(m *Message, done func(ack bool)) *Message {
+	var once sync.Once
+	m.done = func(ack bool) {
+		once.Do(func() { done(ack) })
+	}
+	return m
+}
+
+// Ack acknowledges the message so that it is not redelivered.
+func (m *Message) Ack() {
+	if m.done != nil {
+		m.done(true)
+	}
+}
+
+// Nack negatively acknowledges the message so that it is redelivered.
+func (m *Message) Nack() {
+	if m.done != nil {
+		m.done(false)
+	}
+}
+
+// ToEvent decodes the event of the message.
+func ToEvent(ctx context.Context, m *Message) (*event.Event, error) {
+	return binding.ToEvent(ctx, cepubsub.NewMessage(&pubsub.Message{
+		ID:          m.ID,
+		Data:        m.Data,
+		Attributes:  m.Attributes,
+		OrderingKey: m.OrderingKey,
+	}))
+}
+
+// NewEventMessage encodes the event of the binding message into a queue message.
+func NewEventMessage(ctx context.Context, in binding.Message, transformers ...binding.Transformer) (*Message, error) {
+	var pm pubsub.Message
+	if err := cepubsub.WritePubSubMessage(ctx, in, &pm, transformers...); err != nil {
+		return nil, err
+	}
+	return &Message{
+		Data:       pm.Data,
+		Attributes: pm.Attributes,
+	}, nil
+}
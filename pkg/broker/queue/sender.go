@@ -0,0 +1,98 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// NewSender returns a CloudEvents protocol sender that publishes events to the topic set on the
+// context with cecontext.WithTopic, in the project set on the context with WithProject.
+func NewSender(client Client) protocol.Sender {
+	return &sender{
+		client: client,
+		topics: make(map[topicKey]Topic),
+	}
+}
+
+type projectKey struct{}
+
+// WithProject returns a context that makes the sender publish to the topic in the given project.
+// An empty project is the project of the Client of the sender.
+func WithProject(ctx context.Context, project string) context.Context {
+	return context.WithValue(ctx, projectKey{}, project)
+}
+
+// ProjectFrom returns the project set on the context with WithProject, or an empty string.
+func ProjectFrom(ctx context.Context) string {
+	project, _ := ctx.Value(projectKey{}).(string)
+	return project
+}
+
+type topicKey struct {
+	project string
+	id      string
+}
+
+type sender struct {
+	client Client
+
+	topics    map[topicKey]Topic
+	topicsMut sync.Mutex
+}
+
+// Send implements protocol.Sender.
+func (s *sender) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() { _ = in.Finish(err) }()
+
+	topicID := cecontext.TopicFrom(ctx)
+	if topicID == "" {
+		return errors.New("topic is not set on the context")
+	}
+	msg, err := NewEventMessage(ctx, in, transformers...)
+	if err != nil {
+		return err
+	}
+	topic, err := s.topic(topicKey{project: ProjectFrom(ctx), id: topicID})
+	if err != nil {
+		return err
+	}
+	_, err = topic.Publish(ctx, msg)
+	return err
+}
+
+func (s *sender) topic(key topicKey) (Topic, error) {
+	s.topicsMut.Lock()
+	defer s.topicsMut.Unlock()
+	topic, ok := s.topics[key]
+	if !ok {
+		client, err := s.client.InProject(key.project)
+		if err != nil {
+			return nil, err
+		}
+		topic = client.Topic(key.id, pubsub.DefaultPublishSettings, false)
+		s.topics[key] = topic
+	}
+	return topic, nil
+}
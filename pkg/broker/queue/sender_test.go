@@ -0,0 +1,82 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/queue"
+	"github.com/google/knative-gcp/pkg/broker/queue/memory"
+)
+
+func TestSender(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := memory.NewClient()
+	client.CreateTopic("topic")
+	if err := client.CreateSubscription("sub", "topic"); err != nil {
+		t.Fatalf("CreateSubscription() = %v", err)
+	}
+	c, err := ceclient.New(queue.NewSender(client))
+	if err != nil {
+		t.Fatalf("failed to create cloudevents client: %v", err)
+	}
+
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetExtension("ext", "value")
+	if err := e.SetData("application/json", map[string]string{"foo": "bar"}); err != nil {
+		t.Fatalf("failed to set data: %v", err)
+	}
+
+	if res := c.Send(ctx, e); protocol.IsACK(res) {
+		t.Error("Send() without a topic succeeded, want error")
+	}
+	if res := c.Send(cecontext.WithTopic(ctx, "missing"), e); protocol.IsACK(res) {
+		t.Error("Send() to a missing topic succeeded, want error")
+	}
+	if res := c.Send(cecontext.WithTopic(ctx, "topic"), e); !protocol.IsACK(res) {
+		t.Fatalf("Send() = %v", res)
+	}
+
+	var got *event.Event
+	err = client.Subscription("sub", pubsub.DefaultReceiveSettings).Receive(ctx, func(ctx context.Context, m *queue.Message) {
+		m.Ack()
+		var err error
+		if got, err = queue.ToEvent(ctx, m); err != nil {
+			t.Errorf("ToEvent() = %v", err)
+		}
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("Receive() = %v", err)
+	}
+	if diff := cmp.Diff(&e, got); diff != "" {
+		t.Errorf("unexpected event (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// ProgressAnnotation is the annotation of broker retry pods that holds the progress of the
+// replays in the pod, as a JSON object of Progress keyed by replay ID. Replay IDs are the UIDs of
+// Replays.
+const ProgressAnnotation = "events.cloud.google.com/replayProgress"
+
+// AnnotatePod updates the ProgressAnnotation of the pod whenever the progress of the replays
+// changes, checking it every period, until the context is done.
+func (t *Tracker) AnnotatePod(ctx context.Context, kubeClient kubernetes.Interface, namespace, name string, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	// Pods start without replays.
+	var last string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var value string
+		if progress := t.Progress(); len(progress) > 0 {
+			b, err := json.Marshal(progress)
+			if err != nil {
+				logging.FromContext(ctx).Error("Failed to marshal replay progress", zap.Error(err))
+				continue
+			}
+			value = string(b)
+		}
+		if value == last {
+			continue
+		}
+		if err := patchProgress(ctx, kubeClient, namespace, name, value); err != nil {
+			logging.FromContext(ctx).Warn("Failed to annotate pod with replay progress", zap.Error(err))
+			continue
+		}
+		last = value
+	}
+}
+
+func patchProgress(ctx context.Context, kubeClient kubernetes.Interface, namespace, name, value string) error {
+	// A nil value removes the annotation.
+	var annotation interface{}
+	if value != "" {
+		annotation = value
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				ProgressAnnotation: annotation,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := kubeClient.CoreV1().Pods(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error patching annotations of pod %v/%v: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// ParseProgress parses the ProgressAnnotation of a pod.
+func ParseProgress(annotations map[string]string) (map[string]Progress, error) {
+	value := annotations[ProgressAnnotation]
+	if value == "" {
+		return nil, nil
+	}
+	var progress map[string]Progress
+	if err := json.Unmarshal([]byte(value), &progress); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", ProgressAnnotation, err)
+	}
+	return progress, nil
+}
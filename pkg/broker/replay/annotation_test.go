@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAnnotatePod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"},
+	})
+	tracker := NewTracker(time.Hour)
+	go tracker.AnnotatePod(ctx, kubeClient, "ns", "pod", time.Millisecond)
+
+	waitForProgress := func(want map[string]Progress) {
+		t.Helper()
+		var got map[string]Progress
+		for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+			pod, err := kubeClient.CoreV1().Pods("ns").Get(ctx, "pod", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err = ParseProgress(pod.Annotations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cmp.Equal(want, got) {
+				return
+			}
+		}
+		t.Errorf("replay progress annotation (-want,+got): %s", cmp.Diff(want, got))
+	}
+
+	arrival := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	tracker.Record("uid-1", arrival)
+	waitForProgress(map[string]Progress{"uid-1": {ReplayedThrough: &arrival, Events: 1}})
+	tracker.Forget(nil)
+	waitForProgress(nil)
+}
+
+func TestParseProgress(t *testing.T) {
+	if _, err := ParseProgress(map[string]string{ProgressAnnotation: "{"}); err == nil {
+		t.Error("expected error parsing invalid annotation")
+	}
+	got, err := ParseProgress(map[string]string{ProgressAnnotation: `{"uid-1":{"events":3,"done":true}}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]Progress{"uid-1": {Events: 3, Done: true}}, got); diff != "" {
+		t.Errorf("unexpected progress (-want,+got): %s", diff)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replay tracks the progress of the replays of retained events in the broker data plane.
+package replay
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress is the progress of a replay in a pod.
+type Progress struct {
+	// ReplayedThrough is the arrival time of the latest replayed event.
+	ReplayedThrough *time.Time `json:"replayedThrough,omitempty"`
+	// Events is the number of replayed events.
+	Events int64 `json:"events"`
+	// Done is true once the pod has not received any event to replay for the idle timeout, i.e.
+	// the subscription has no more events to replay to the pod.
+	Done bool `json:"done,omitempty"`
+}
+
+// Tracker tracks the progress of the replays in a pod. Replays are identified by the IDs of the
+// config.Replay.
+type Tracker struct {
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mux     sync.Mutex
+	replays map[string]*replay
+}
+
+type replay struct {
+	progress Progress
+	// lastActive is when the pod last received an event to replay, or started the replay.
+	lastActive time.Time
+}
+
+// NewTracker creates a Tracker. Replays that receive no event to replay for idleTimeout are done.
+func NewTracker(idleTimeout time.Duration) *Tracker {
+	return &Tracker{
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		replays:     make(map[string]*replay),
+	}
+}
+
+// Start starts tracking the replay, if it is not tracked yet.
+func (t *Tracker) Start(id string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if _, ok := t.replays[id]; !ok {
+		t.replays[id] = &replay{lastActive: t.now()}
+	}
+}
+
+// Record records that an event accepted by the ingress at the arrival time was replayed. A zero
+// arrival time is for events without one.
+func (t *Tracker) Record(id string, arrival time.Time) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	r := t.replay(id)
+	r.progress.Events++
+	if !arrival.IsZero() && (r.progress.ReplayedThrough == nil || arrival.After(*r.progress.ReplayedThrough)) {
+		r.progress.ReplayedThrough = &arrival
+	}
+}
+
+// Skip records that an event accepted by the ingress before the start of the replay was received.
+// It is not replayed, but it shows the replay is still in progress.
+func (t *Tracker) Skip(id string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.replay(id)
+}
+
+// Forget stops tracking the replays that are not in ids.
+func (t *Tracker) Forget(ids map[string]bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	for id := range t.replays {
+		if !ids[id] {
+			delete(t.replays, id)
+		}
+	}
+}
+
+// Progress returns the progress of the tracked replays, keyed by their ID.
+func (t *Tracker) Progress() map[string]Progress {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	out := make(map[string]Progress, len(t.replays))
+	now := t.now()
+	for id, r := range t.replays {
+		p := r.progress
+		p.Done = now.Sub(r.lastActive) >= t.idleTimeout
+		out[id] = p
+	}
+	return out
+}
+
+// replay returns the tracked replay, marking it active.
+func (t *Tracker) replay(id string) *replay {
+	r, ok := t.replays[id]
+	if !ok {
+		r = &replay{}
+		t.replays[id] = r
+	}
+	r.lastActive = t.now()
+	return r
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+	arrival1 := now.Add(-2 * time.Hour)
+	arrival2 := now.Add(-time.Hour)
+
+	tracker.Start("r1")
+	tracker.Start("r2")
+	tracker.Record("r1", arrival2)
+	tracker.Record("r1", arrival1)
+	if diff := cmp.Diff(map[string]Progress{
+		"r1": {ReplayedThrough: &arrival2, Events: 2},
+		"r2": {},
+	}, tracker.Progress()); diff != "" {
+		t.Errorf("unexpected progress (-want,+got): %s", diff)
+	}
+
+	now = now.Add(30 * time.Second)
+	tracker.Skip("r2")
+	now = now.Add(30 * time.Second)
+	if diff := cmp.Diff(map[string]Progress{
+		"r1": {ReplayedThrough: &arrival2, Events: 2, Done: true},
+		"r2": {},
+	}, tracker.Progress()); diff != "" {
+		t.Errorf("unexpected progress after idle timeout (-want,+got): %s", diff)
+	}
+
+	// Starting a tracked replay again doesn't reset its progress.
+	tracker.Start("r1")
+	tracker.Forget(map[string]bool{"r1": true})
+	if diff := cmp.Diff(map[string]Progress{
+		"r1": {ReplayedThrough: &arrival2, Events: 2, Done: true},
+	}, tracker.Progress()); diff != "" {
+		t.Errorf("unexpected progress after forget (-want,+got): %s", diff)
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	eventingv1beta1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/broker/v1beta1"
 	eventsv1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/events/v1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/events/v1alpha1"
 	eventsv1beta1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/events/v1beta1"
 	internalv1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/intevents/v1"
 	internalv1alpha1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/intevents/v1alpha1"
@@ -36,6 +37,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	EventingV1beta1() eventingv1beta1.EventingV1beta1Interface
+	EventsV1alpha1() eventsv1alpha1.EventsV1alpha1Interface
 	EventsV1beta1() eventsv1beta1.EventsV1beta1Interface
 	EventsV1() eventsv1.EventsV1Interface
 	InternalV1alpha1() internalv1alpha1.InternalV1alpha1Interface
@@ -49,6 +51,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	eventingV1beta1  *eventingv1beta1.EventingV1beta1Client
+	eventsV1alpha1   *eventsv1alpha1.EventsV1alpha1Client
 	eventsV1beta1    *eventsv1beta1.EventsV1beta1Client
 	eventsV1         *eventsv1.EventsV1Client
 	internalV1alpha1 *internalv1alpha1.InternalV1alpha1Client
@@ -62,6 +65,11 @@ func (c *Clientset) EventingV1beta1() eventingv1beta1.EventingV1beta1Interface {
 	return c.eventingV1beta1
 }
 
+// EventsV1alpha1 retrieves the EventsV1alpha1Client
+func (c *Clientset) EventsV1alpha1() eventsv1alpha1.EventsV1alpha1Interface {
+	return c.eventsV1alpha1
+}
+
 // EventsV1beta1 retrieves the EventsV1beta1Client
 func (c *Clientset) EventsV1beta1() eventsv1beta1.EventsV1beta1Interface {
 	return c.eventsV1beta1
@@ -117,6 +125,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.eventsV1alpha1, err = eventsv1alpha1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 	cs.eventsV1beta1, err = eventsv1beta1.NewForConfig(&configShallowCopy)
 	if err != nil {
 		return nil, err
@@ -154,6 +166,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.eventingV1beta1 = eventingv1beta1.NewForConfigOrDie(c)
+	cs.eventsV1alpha1 = eventsv1alpha1.NewForConfigOrDie(c)
 	cs.eventsV1beta1 = eventsv1beta1.NewForConfigOrDie(c)
 	cs.eventsV1 = eventsv1.NewForConfigOrDie(c)
 	cs.internalV1alpha1 = internalv1alpha1.NewForConfigOrDie(c)
@@ -169,6 +182,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.eventingV1beta1 = eventingv1beta1.New(c)
+	cs.eventsV1alpha1 = eventsv1alpha1.New(c)
 	cs.eventsV1beta1 = eventsv1beta1.New(c)
 	cs.eventsV1 = eventsv1.New(c)
 	cs.internalV1alpha1 = internalv1alpha1.New(c)
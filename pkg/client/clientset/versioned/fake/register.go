@@ -21,6 +21,7 @@ package fake
 import (
 	eventingv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
 	internalv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	internalv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
@@ -38,6 +39,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 
 var localSchemeBuilder = runtime.SchemeBuilder{
 	eventingv1beta1.AddToScheme,
+	eventsv1alpha1.AddToScheme,
 	eventsv1beta1.AddToScheme,
 	eventsv1.AddToScheme,
 	internalv1alpha1.AddToScheme,
@@ -49,14 +51,14 @@ var localSchemeBuilder = runtime.SchemeBuilder{
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
 // of clientsets, like in:
 //
-//   import (
-//     "k8s.io/client-go/kubernetes"
-//     clientsetscheme "k8s.io/client-go/kubernetes/scheme"
-//     aggregatorclientsetscheme "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/scheme"
-//   )
+//	import (
+//	  "k8s.io/client-go/kubernetes"
+//	  clientsetscheme "k8s.io/client-go/kubernetes/scheme"
+//	  aggregatorclientsetscheme "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/scheme"
+//	)
 //
-//   kclientset, _ := kubernetes.NewForConfig(c)
-//   _ = aggregatorclientsetscheme.AddToScheme(clientsetscheme.Scheme)
+//	kclientset, _ := kubernetes.NewForConfig(c)
+//	_ = aggregatorclientsetscheme.AddToScheme(clientsetscheme.Scheme)
 //
 // After this, RawExtensions in Kubernetes types will serialize kube-aggregator types
 // correctly.
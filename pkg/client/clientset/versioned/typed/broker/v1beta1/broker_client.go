@@ -27,6 +27,7 @@ import (
 type EventingV1beta1Interface interface {
 	RESTClient() rest.Interface
 	BrokersGetter
+	EventSchemasGetter
 	TriggersGetter
 }
 
@@ -39,6 +40,10 @@ func (c *EventingV1beta1Client) Brokers(namespace string) BrokerInterface {
 	return newBrokers(c, namespace)
 }
 
+func (c *EventingV1beta1Client) EventSchemas(namespace string) EventSchemaInterface {
+	return newEventSchemas(c, namespace)
+}
+
 func (c *EventingV1beta1Client) Triggers(namespace string) TriggerInterface {
 	return newTriggers(c, namespace)
 }
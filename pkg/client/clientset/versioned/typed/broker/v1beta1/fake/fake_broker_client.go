@@ -32,6 +32,10 @@ func (c *FakeEventingV1beta1) Brokers(namespace string) v1beta1.BrokerInterface
 	return &FakeBrokers{c, namespace}
 }
 
+func (c *FakeEventingV1beta1) EventSchemas(namespace string) v1beta1.EventSchemaInterface {
+	return &FakeEventSchemas{c, namespace}
+}
+
 func (c *FakeEventingV1beta1) Triggers(namespace string) v1beta1.TriggerInterface {
 	return &FakeTriggers{c, namespace}
 }
@@ -20,4 +20,6 @@ package v1beta1
 
 type BrokerExpansion interface{}
 
+type EventSchemaExpansion interface{}
+
 type TriggerExpansion interface{}
@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// Brokers returns a BrokerInformer.
 	Brokers() BrokerInformer
+	// EventSchemas returns a EventSchemaInformer.
+	EventSchemas() EventSchemaInformer
 	// Triggers returns a TriggerInformer.
 	Triggers() TriggerInformer
 }
@@ -46,6 +48,11 @@ func (v *version) Brokers() BrokerInformer {
 	return &brokerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// EventSchemas returns a EventSchemaInformer.
+func (v *version) EventSchemas() EventSchemaInformer {
+	return &eventSchemaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Triggers returns a TriggerInformer.
 func (v *version) Triggers() TriggerInformer {
 	return &triggerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
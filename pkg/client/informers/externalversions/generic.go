@@ -23,6 +23,7 @@ import (
 
 	v1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	v1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
@@ -61,6 +62,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=eventing.knative.dev, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("brokers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1beta1().Brokers().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("eventschemas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1beta1().EventSchemas().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("triggers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1beta1().Triggers().Informer()}, nil
 
@@ -76,6 +79,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1.SchemeGroupVersion.WithResource("cloudstoragesources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudStorageSources().Informer()}, nil
 
+		// Group=events.cloud.google.com, Version=v1alpha1
+	case eventsv1alpha1.SchemeGroupVersion.WithResource("replays"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1alpha1().Replays().Informer()}, nil
+
 		// Group=events.cloud.google.com, Version=v1beta1
 	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudauditlogssources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudAuditLogsSources().Informer()}, nil
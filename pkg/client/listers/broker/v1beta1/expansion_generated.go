@@ -26,6 +26,14 @@ type BrokerListerExpansion interface{}
 // BrokerNamespaceLister.
 type BrokerNamespaceListerExpansion interface{}
 
+// EventSchemaListerExpansion allows custom methods to be added to
+// EventSchemaLister.
+type EventSchemaListerExpansion interface{}
+
+// EventSchemaNamespaceListerExpansion allows custom methods to be added to
+// EventSchemaNamespaceLister.
+type EventSchemaNamespaceListerExpansion interface{}
+
 // TriggerListerExpansion allows custom methods to be added to
 // TriggerLister.
 type TriggerListerExpansion interface{}
@@ -1,5 +1,5 @@
 /*
-Copyright 2020 Google LLC
+Copyright 2021 Google LLC
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -18,42 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
-// CloudAuditLogsSourceListerExpansion allows custom methods to be added to
-// CloudAuditLogsSourceLister.
-type CloudAuditLogsSourceListerExpansion interface{}
+// ReplayListerExpansion allows custom methods to be added to
+// ReplayLister.
+type ReplayListerExpansion interface{}
 
-// CloudAuditLogsSourceNamespaceListerExpansion allows custom methods to be added to
-// CloudAuditLogsSourceNamespaceLister.
-type CloudAuditLogsSourceNamespaceListerExpansion interface{}
-
-// CloudBuildSourceListerExpansion allows custom methods to be added to
-// CloudBuildSourceLister.
-type CloudBuildSourceListerExpansion interface{}
-
-// CloudBuildSourceNamespaceListerExpansion allows custom methods to be added to
-// CloudBuildSourceNamespaceLister.
-type CloudBuildSourceNamespaceListerExpansion interface{}
-
-// CloudPubSubSourceListerExpansion allows custom methods to be added to
-// CloudPubSubSourceLister.
-type CloudPubSubSourceListerExpansion interface{}
-
-// CloudPubSubSourceNamespaceListerExpansion allows custom methods to be added to
-// CloudPubSubSourceNamespaceLister.
-type CloudPubSubSourceNamespaceListerExpansion interface{}
-
-// CloudSchedulerSourceListerExpansion allows custom methods to be added to
-// CloudSchedulerSourceLister.
-type CloudSchedulerSourceListerExpansion interface{}
-
-// CloudSchedulerSourceNamespaceListerExpansion allows custom methods to be added to
-// CloudSchedulerSourceNamespaceLister.
-type CloudSchedulerSourceNamespaceListerExpansion interface{}
-
-// CloudStorageSourceListerExpansion allows custom methods to be added to
-// CloudStorageSourceLister.
-type CloudStorageSourceListerExpansion interface{}
-
-// CloudStorageSourceNamespaceListerExpansion allows custom methods to be added to
-// CloudStorageSourceNamespaceLister.
-type CloudStorageSourceNamespaceListerExpansion interface{}
+// ReplayNamespaceListerExpansion allows custom methods to be added to
+// ReplayNamespaceLister.
+type ReplayNamespaceListerExpansion interface{}
@@ -29,3 +29,17 @@ type PubSubable interface {
 	// PubSubStatus returns the PubSubStatus portion of the Status.
 	PubSubStatus() *duckv1.PubSubStatus
 }
+
+// EventMappable is implemented by the PubSubables that can declare how their
+// Pub/Sub messages are mapped to CloudEvents.
+type EventMappable interface {
+	// EventMapping returns the EventMapping of the Spec, if any.
+	EventMapping() *duckv1.EventMapping
+}
+
+// SubscriptionFilterable is implemented by the PubSubables that can filter
+// the messages of their Pub/Sub subscription.
+type SubscriptionFilterable interface {
+	// SubscriptionFilter returns the Pub/Sub filter of the Spec, if any.
+	SubscriptionFilter() string
+}
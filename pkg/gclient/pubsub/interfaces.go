@@ -68,4 +68,15 @@ type Topic interface {
 	ID() string
 	// String see https://godoc.org/cloud.google.com/go/pubsub#Topic.String
 	String() string
+	// Publish see https://godoc.org/cloud.google.com/go/pubsub#Topic.Publish
+	Publish(ctx context.Context, msg *pubsub.Message) PublishResult
+	// Stop see https://godoc.org/cloud.google.com/go/pubsub#Topic.Stop
+	Stop()
+}
+
+// PublishResult matches the interface exposed by pubsub.PublishResult
+// see https://godoc.org/cloud.google.com/go/pubsub#PublishResult
+type PublishResult interface {
+	// Get see https://godoc.org/cloud.google.com/go/pubsub#PublishResult.Get
+	Get(ctx context.Context) (string, error)
 }
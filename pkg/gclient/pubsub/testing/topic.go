@@ -18,6 +18,7 @@ package testing
 
 import (
 	"context"
+	"sync"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/gclient/iam"
@@ -36,9 +37,31 @@ type testTopic struct {
 
 // TestTopicData is the data used to configure the test Topic.
 type TestTopicData struct {
-	ExistsErr error
-	Exists    bool
-	DeleteErr error
+	ExistsErr  error
+	Exists     bool
+	DeleteErr  error
+	PublishErr error
+	// Published records the published messages, if set.
+	Published *TestMessages
+}
+
+// TestMessages records the messages published to test topics.
+type TestMessages struct {
+	mux      sync.Mutex
+	messages []*pubsub.Message
+}
+
+// Messages returns the recorded messages.
+func (m *TestMessages) Messages() []*pubsub.Message {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return append([]*pubsub.Message(nil), m.messages...)
+}
+
+func (m *TestMessages) add(msg *pubsub.Message) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.messages = append(m.messages, msg)
 }
 
 // Verify that it satisfies the pubsub.Topic interface.
@@ -64,3 +87,26 @@ func (t *testTopic) ID() string {
 func (t *testTopic) String() string {
 	return t.topicString
 }
+
+func (t *testTopic) Publish(ctx context.Context, msg *pubsub.Message) gpubsub.PublishResult {
+	if t.data.PublishErr != nil {
+		return &testPublishResult{err: t.data.PublishErr}
+	}
+	if t.data.Published != nil {
+		t.data.Published.add(msg)
+	}
+	return &testPublishResult{id: msg.ID}
+}
+
+func (t *testTopic) Stop() {
+}
+
+// testPublishResult is the result of a test publish.
+type testPublishResult struct {
+	id  string
+	err error
+}
+
+func (r *testPublishResult) Get(ctx context.Context) (string, error) {
+	return r.id, r.err
+}
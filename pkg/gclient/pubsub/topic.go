@@ -52,3 +52,13 @@ func (t *pubsubTopic) ID() string {
 func (t *pubsubTopic) String() string {
 	return t.topic.String()
 }
+
+// Publish implements pubsub.Topic.Publish
+func (t *pubsubTopic) Publish(ctx context.Context, msg *pubsub.Message) PublishResult {
+	return t.topic.Publish(ctx, msg)
+}
+
+// Stop implements pubsub.Topic.Stop
+func (t *pubsubTopic) Stop() {
+	t.topic.Stop()
+}
@@ -45,3 +45,15 @@ func (b *storageBucket) DeleteNotification(ctx context.Context, id string) error
 func (b *storageBucket) Attrs(ctx context.Context) (attrs *storage.BucketAttrs, err error) {
 	return b.handle.Attrs(ctx)
 }
+
+func (b *storageBucket) Update(ctx context.Context, uattrs storage.BucketAttrsToUpdate) (*storage.BucketAttrs, error) {
+	return b.handle.Update(ctx, uattrs)
+}
+
+func (b *storageBucket) Object(name string) Object {
+	return &storageObject{handle: b.handle.Object(name)}
+}
+
+func (b *storageBucket) Objects(ctx context.Context, q *storage.Query) ObjectIterator {
+	return b.handle.Objects(ctx, q)
+}
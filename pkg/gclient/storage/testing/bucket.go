@@ -25,6 +25,7 @@ import (
 
 // testBucket is a test Storage bucket.
 type testBucket struct {
+	name string
 	data TestBucketData
 }
 
@@ -37,6 +38,14 @@ type TestBucketData struct {
 	DeleteErr          error
 	Attrs              *BucketAttrs
 	AttrsError         error
+	// UpdateErr is returned by Update. Otherwise, Update applies the lifecycle to Attrs.
+	UpdateErr error
+	// Objects holds the objects of the bucket. Object writes fail if it is nil.
+	Objects         *TestObjects
+	ListObjectsErr  error
+	WriteObjectErr  error
+	ReadObjectErr   error
+	DeleteObjectErr error
 }
 
 // Verify that it satisfies the storage.Bucket interface.
@@ -65,3 +74,31 @@ func (b *testBucket) DeleteNotification(ctx context.Context, id string) error {
 func (b *testBucket) Attrs(ctx context.Context) (*BucketAttrs, error) {
 	return b.data.Attrs, b.data.AttrsError
 }
+
+// Update implements bucket.Update
+func (b *testBucket) Update(ctx context.Context, uattrs BucketAttrsToUpdate) (*BucketAttrs, error) {
+	if b.data.UpdateErr != nil {
+		return nil, b.data.UpdateErr
+	}
+	if uattrs.Lifecycle != nil {
+		b.data.Attrs.Lifecycle = *uattrs.Lifecycle
+	}
+	return b.data.Attrs, nil
+}
+
+// Object implements bucket.Object
+func (b *testBucket) Object(name string) storage.Object {
+	return &testObject{name: name, data: b.data}
+}
+
+// Objects implements bucket.Objects
+func (b *testBucket) Objects(ctx context.Context, q *Query) storage.ObjectIterator {
+	if b.data.ListObjectsErr != nil {
+		return newTestObjectIterator(nil, b.data.ListObjectsErr)
+	}
+	prefix := ""
+	if q != nil {
+		prefix = q.Prefix
+	}
+	return newTestObjectIterator(b.data.Objects.list(b.name, prefix), nil)
+}
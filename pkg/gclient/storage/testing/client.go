@@ -67,5 +67,5 @@ func (c *testClient) Close() error {
 
 // Bucket implements client.Bucket
 func (c *testClient) Bucket(name string) storage.Bucket {
-	return &testBucket{data: c.data.BucketData}
+	return &testBucket{name: name, data: c.data.BucketData}
 }
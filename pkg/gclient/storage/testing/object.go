@@ -0,0 +1,229 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+
+	"github.com/google/knative-gcp/pkg/gclient/storage"
+)
+
+// TestObjects is an in-memory store of the objects of a test bucket. It is shared by all the
+// handles of the bucket and is safe for concurrent use.
+type TestObjects struct {
+	mux     sync.Mutex
+	objects map[string]TestObject
+}
+
+// TestObject is an object stored in TestObjects.
+type TestObject struct {
+	Data        []byte
+	ContentType string
+	Created     time.Time
+}
+
+// NewTestObjects creates an empty TestObjects.
+func NewTestObjects() *TestObjects {
+	return &TestObjects{objects: make(map[string]TestObject)}
+}
+
+// Put stores an object.
+func (o *TestObjects) Put(name string, obj TestObject) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.objects[name] = obj
+}
+
+// Get returns the object with the given name, if any.
+func (o *TestObjects) Get(name string) (TestObject, bool) {
+	if o == nil {
+		return TestObject{}, false
+	}
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	obj, ok := o.objects[name]
+	return obj, ok
+}
+
+// Names returns the sorted names of the stored objects.
+func (o *TestObjects) Names() []string {
+	var names []string
+	for _, attrs := range o.list("", "") {
+		names = append(names, attrs.Name)
+	}
+	return names
+}
+
+func (o *TestObjects) delete(name string) bool {
+	if o == nil {
+		return false
+	}
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	_, ok := o.objects[name]
+	delete(o.objects, name)
+	return ok
+}
+
+func (o *TestObjects) list(bucket, prefix string) []*ObjectAttrs {
+	if o == nil {
+		return nil
+	}
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	var attrs []*ObjectAttrs
+	for name, obj := range o.objects {
+		if strings.HasPrefix(name, prefix) {
+			attrs = append(attrs, &ObjectAttrs{Bucket: bucket, Name: name, ContentType: obj.ContentType, Size: int64(len(obj.Data)), Created: obj.Created})
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Name < attrs[j].Name })
+	return attrs
+}
+
+// testObject is a test Storage object.
+type testObject struct {
+	name string
+	data TestBucketData
+}
+
+// Verify that it satisfies the storage.Object interface.
+var _ storage.Object = &testObject{}
+
+// NewWriter implements object.NewWriter
+func (o *testObject) NewWriter(ctx context.Context) io.WriteCloser {
+	return &testObjectWriter{object: o}
+}
+
+// NewReader implements object.NewReader
+func (o *testObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	if o.data.ReadObjectErr != nil {
+		return nil, o.data.ReadObjectErr
+	}
+	obj, ok := o.data.Objects.Get(o.name)
+	if !ok {
+		return nil, ErrObjectNotExist
+	}
+	return ioutil.NopCloser(bytes.NewReader(obj.Data)), nil
+}
+
+// Delete implements object.Delete
+func (o *testObject) Delete(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if o.data.DeleteObjectErr != nil {
+		return o.data.DeleteObjectErr
+	}
+	if !o.data.Objects.delete(o.name) {
+		return ErrObjectNotExist
+	}
+	return nil
+}
+
+// testObjectWriter stores the written data in the bucket objects when closed.
+type testObjectWriter struct {
+	object *testObject
+	buf    bytes.Buffer
+}
+
+func (w *testObjectWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *testObjectWriter) Close() error {
+	if w.object.data.WriteObjectErr != nil {
+		return w.object.data.WriteObjectErr
+	}
+	if w.object.data.Objects == nil {
+		return errors.New("test bucket has no object store")
+	}
+	w.object.data.Objects.Put(w.object.name, TestObject{Data: w.buf.Bytes(), Created: time.Now()})
+	return nil
+}
+
+// testObjectIterator is a test Storage object iterator. Its page tokens are
+// the indexes of the objects that start the pages.
+type testObjectIterator struct {
+	attrs    []*ObjectAttrs
+	buf      []*ObjectAttrs
+	err      error
+	pageInfo *iterator.PageInfo
+	nextFunc func() error
+}
+
+// Verify that it satisfies the storage.ObjectIterator interface.
+var _ storage.ObjectIterator = &testObjectIterator{}
+
+func newTestObjectIterator(attrs []*ObjectAttrs, err error) *testObjectIterator {
+	it := &testObjectIterator{attrs: attrs, err: err}
+	it.pageInfo, it.nextFunc = iterator.NewPageInfo(
+		it.fetch,
+		func() int { return len(it.buf) },
+		func() interface{} { b := it.buf; it.buf = nil; return b })
+	return it
+}
+
+// Next implements objectIterator.Next
+func (it *testObjectIterator) Next() (*ObjectAttrs, error) {
+	if err := it.nextFunc(); err != nil {
+		return nil, err
+	}
+	attrs := it.buf[0]
+	it.buf = it.buf[1:]
+	return attrs, nil
+}
+
+// PageInfo implements objectIterator.PageInfo
+func (it *testObjectIterator) PageInfo() *iterator.PageInfo {
+	return it.pageInfo
+}
+
+func (it *testObjectIterator) fetch(pageSize int, pageToken string) (string, error) {
+	if it.err != nil {
+		return "", it.err
+	}
+	start := 0
+	if pageToken != "" {
+		var err error
+		if start, err = strconv.Atoi(pageToken); err != nil || start < 0 || start > len(it.attrs) {
+			return "", fmt.Errorf("invalid page token %q", pageToken)
+		}
+	}
+	end := len(it.attrs)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+	it.buf = append(it.buf, it.attrs[start:end]...)
+	if end == len(it.attrs) {
+		return "", nil
+	}
+	return strconv.Itoa(end), nil
+}
@@ -45,6 +45,8 @@ type DeliveryReporter struct {
 	containerName         ContainerName
 	dispatchTimeInMsecM   *stats.Float64Measure
 	processingTimeInMsecM *stats.Float64Measure
+	deliveryAttemptM      *stats.Int64Measure
+	circuitBreakerStateM  *stats.Int64Measure
 }
 
 func (r *DeliveryReporter) register() error {
@@ -86,6 +88,28 @@ func (r *DeliveryReporter) register() error {
 				ContainerNameKey,
 			},
 		},
+		&view.View{
+			Name:        r.deliveryAttemptM.Name(),
+			Description: r.deliveryAttemptM.Description(),
+			Measure:     r.deliveryAttemptM,
+			Aggregation: view.Distribution(1, 2, 3, 4, 5, 10, 20, 50, 100),
+			TagKeys: []tag.Key{
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.circuitBreakerStateM.Name(),
+			Description: r.circuitBreakerStateM.Description(),
+			Measure:     r.circuitBreakerStateM,
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
 	)
 }
 
@@ -108,6 +132,20 @@ func NewDeliveryReporter(podName PodName, containerName ContainerName) (*Deliver
 			"The time spent processing an event before it is dispatched to a Trigger subscriber",
 			stats.UnitMilliseconds,
 		),
+		// deliveryAttemptM records the attempt number of each dispatch of an
+		// event to a Trigger subscriber, starting at 1 for the first attempt.
+		deliveryAttemptM: stats.Int64(
+			"event_delivery_attempts",
+			"The delivery attempt number of events dispatched to a Trigger subscriber",
+			stats.UnitDimensionless,
+		),
+		// circuitBreakerStateM records the state of the circuit breaker of a
+		// Trigger subscriber: 0 for closed, 1 for open and 2 for half-open.
+		circuitBreakerStateM: stats.Int64(
+			"circuit_breaker_state",
+			"The state of the circuit breaker of a Trigger subscriber, 0 closed, 1 open, 2 half-open",
+			stats.UnitDimensionless,
+		),
 	}
 
 	if err := r.register(); err != nil {
@@ -123,6 +161,17 @@ func (r *DeliveryReporter) ReportEventDispatchTime(ctx context.Context, d time.D
 	metrics.Record(ctx, r.dispatchTimeInMsecM.M(float64(d/time.Millisecond)), stats.WithAttachments(attachments))
 }
 
+// ReportEventDeliveryAttempt captures the attempt number of an event dispatch.
+func (r *DeliveryReporter) ReportEventDeliveryAttempt(ctx context.Context, attempt int64) {
+	attachments := getSpanContextAttachments(ctx)
+	metrics.Record(ctx, r.deliveryAttemptM.M(attempt), stats.WithAttachments(attachments))
+}
+
+// ReportCircuitBreakerState captures the state of the circuit breaker of a target.
+func (r *DeliveryReporter) ReportCircuitBreakerState(ctx context.Context, state int64) {
+	metrics.Record(ctx, r.circuitBreakerStateM.M(state))
+}
+
 // StartEventProcessing records the start of event processing for delivery within the given context.
 func StartEventProcessing(ctx context.Context) context.Context {
 	return context.WithValue(ctx, startDeliveryProcessingTime, time.Now())
@@ -130,6 +130,86 @@ func TestReportEventProcessingTime(t *testing.T) {
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
 }
 
+func TestReportEventDeliveryAttempt(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType: "testeventtype",
+		metricskey.PodName:         "testpod",
+		metricskey.ContainerName:   "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace:      "testns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "testbroker",
+		Name:           "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportEventDeliveryAttempt(ctx, 1)
+		return nil
+	})
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportEventDeliveryAttempt(ctx, 3)
+		return nil
+	})
+	metricstest.CheckDistributionData(t, "event_delivery_attempts", wantTags, 2, 1.0, 3.0)
+}
+
+func TestReportCircuitBreakerState(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType: "testeventtype",
+		metricskey.PodName:         "testpod",
+		metricskey.ContainerName:   "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace:      "testns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "testbroker",
+		Name:           "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportCircuitBreakerState(ctx, 1)
+		return nil
+	})
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportCircuitBreakerState(ctx, 2)
+		return nil
+	})
+	metricstest.CheckLastValueData(t, "circuit_breaker_state", wantTags, 2)
+}
+
 func TestMetricsWithEmptySourceAndTypeFilter(t *testing.T) {
 	reportertest.ResetDeliveryMetrics()
 
@@ -55,6 +55,52 @@ func (r *IngressReporter) register() error {
 			Aggregation: view.Count(),
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Name:        r.rejectedRequestCountM.Name(),
+			Description: r.rejectedRequestCountM.Description(),
+			Measure:     r.rejectedRequestCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				ReasonKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.duplicateEventCountM.Name(),
+			Description: r.duplicateEventCountM.Description(),
+			Measure:     r.duplicateEventCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				EventTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.schemaViolationCountM.Name(),
+			Description: r.schemaViolationCountM.Description(),
+			Measure:     r.schemaViolationCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				EventTypeKey,
+				SchemaEnforcementKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.quotaUtilizationM.Name(),
+			Description: r.quotaUtilizationM.Description(),
+			Measure:     r.quotaUtilizationM,
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				QuotaScopeKey,
+				QuotaTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
 	)
 }
 
@@ -68,6 +114,35 @@ func NewIngressReporter(podName PodName, containerName ContainerName) (*IngressR
 			"Number of events received by a Broker",
 			stats.UnitDimensionless,
 		),
+		// rejectedRequestCountM counts the requests rejected because they
+		// are not authenticated or not authorized, or because they exceed
+		// a quota.
+		rejectedRequestCountM: stats.Int64(
+			"rejected_request_count",
+			"Number of requests rejected by the authentication or the quotas of a Broker",
+			stats.UnitDimensionless,
+		),
+		// duplicateEventCountM counts the events accepted without being
+		// published because they are duplicates.
+		duplicateEventCountM: stats.Int64(
+			"duplicate_event_count",
+			"Number of duplicate events received by a Broker",
+			stats.UnitDimensionless,
+		),
+		// schemaViolationCountM counts the events whose data doesn't
+		// match the schema of their type.
+		schemaViolationCountM: stats.Int64(
+			"schema_violation_count",
+			"Number of events received by a Broker that don't match their schema",
+			stats.UnitDimensionless,
+		),
+		// quotaUtilizationM is the fraction of a quota used over the last
+		// second, between 0 and 1.
+		quotaUtilizationM: stats.Float64(
+			"quota_utilization",
+			"Fraction of the ingress quota of a Broker or of its namespace in use",
+			stats.UnitDimensionless,
+		),
 	}
 	if err := r.register(); err != nil {
 		return nil, fmt.Errorf("failed to register ingress stats: %w", err)
@@ -80,6 +155,11 @@ type IngressReporter struct {
 	podName       PodName
 	containerName ContainerName
 	eventCountM   *stats.Int64Measure
+
+	rejectedRequestCountM *stats.Int64Measure
+	duplicateEventCountM  *stats.Int64Measure
+	schemaViolationCountM *stats.Int64Measure
+	quotaUtilizationM     *stats.Float64Measure
 }
 
 func (r *IngressReporter) ReportEventCount(ctx context.Context, args IngressReportArgs) error {
@@ -98,3 +178,56 @@ func (r *IngressReporter) ReportEventCount(ctx context.Context, args IngressRepo
 	)
 	return nil
 }
+
+// ReportRejectedRequest captures a request rejected by the authentication or the quotas of a
+// Broker, tagged by the reason it was rejected.
+func (r *IngressReporter) ReportRejectedRequest(ctx context.Context, reason string) {
+	metrics.Record(
+		ctx, r.rejectedRequestCountM.M(1),
+		stats.WithTags(
+			tag.Insert(PodNameKey, string(r.podName)),
+			tag.Insert(ContainerNameKey, string(r.containerName)),
+			tag.Insert(ReasonKey, reason),
+		),
+	)
+}
+
+// ReportDuplicateEvent captures a duplicate event accepted without being published again.
+func (r *IngressReporter) ReportDuplicateEvent(ctx context.Context, eventType string) {
+	metrics.Record(
+		ctx, r.duplicateEventCountM.M(1),
+		stats.WithTags(
+			tag.Insert(PodNameKey, string(r.podName)),
+			tag.Insert(ContainerNameKey, string(r.containerName)),
+			tag.Insert(EventTypeKey, EventTypeMetricValue(eventType)),
+		),
+	)
+}
+
+// ReportSchemaViolation captures an event whose data doesn't match the schema of its type, tagged
+// by the schema enforcement of the Broker.
+func (r *IngressReporter) ReportSchemaViolation(ctx context.Context, eventType, enforcement string) {
+	metrics.Record(
+		ctx, r.schemaViolationCountM.M(1),
+		stats.WithTags(
+			tag.Insert(PodNameKey, string(r.podName)),
+			tag.Insert(ContainerNameKey, string(r.containerName)),
+			tag.Insert(EventTypeKey, EventTypeMetricValue(eventType)),
+			tag.Insert(SchemaEnforcementKey, enforcement),
+		),
+	)
+}
+
+// ReportQuotaUtilization captures the fraction of a quota in use, tagged by the scope of the quota
+// (namespace or broker) and by what it limits (events or bytes).
+func (r *IngressReporter) ReportQuotaUtilization(ctx context.Context, scope, quotaType string, utilization float64) {
+	metrics.Record(
+		ctx, r.quotaUtilizationM.M(utilization),
+		stats.WithTags(
+			tag.Insert(PodNameKey, string(r.podName)),
+			tag.Insert(ContainerNameKey, string(r.containerName)),
+			tag.Insert(QuotaScopeKey, scope),
+			tag.Insert(QuotaTypeKey, quotaType),
+		),
+	)
+}
@@ -86,3 +86,80 @@ func TestStatsReporterWithCustomEventType(t *testing.T) {
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
 }
+
+func TestReportRejectedRequest(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	wantTags := map[string]string{
+		"reason":                 "expired_token",
+		metricskey.ContainerName: "testcontainer",
+		metricskey.PodName:       "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReportRejectedRequest(context.Background(), "expired_token")
+	r.ReportRejectedRequest(context.Background(), "expired_token")
+	metricstest.CheckCountData(t, "rejected_request_count", wantTags, 2)
+}
+
+func TestReportDuplicateEvent(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelEventType: "google.cloud.scheduler.job.v1.executed",
+		metricskey.ContainerName:  "testcontainer",
+		metricskey.PodName:        "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReportDuplicateEvent(context.Background(), "google.cloud.scheduler.job.v1.executed")
+	r.ReportDuplicateEvent(context.Background(), "google.cloud.scheduler.job.v1.executed")
+	metricstest.CheckCountData(t, "duplicate_event_count", wantTags, 2)
+}
+
+func TestReportSchemaViolation(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelEventType: "google.cloud.scheduler.job.v1.executed",
+		"schema_enforcement":      "warn",
+		metricskey.ContainerName:  "testcontainer",
+		metricskey.PodName:        "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReportSchemaViolation(context.Background(), "google.cloud.scheduler.job.v1.executed", "warn")
+	metricstest.CheckCountData(t, "schema_violation_count", wantTags, 1)
+}
+
+func TestReportQuotaUtilization(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	wantTags := map[string]string{
+		"quota_scope":            "namespace",
+		"quota_type":             "events",
+		metricskey.ContainerName: "testcontainer",
+		metricskey.PodName:       "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReportQuotaUtilization(context.Background(), "namespace", "events", 0.25)
+	r.ReportQuotaUtilization(context.Background(), "namespace", "events", 0.5)
+	metricstest.CheckLastValueData(t, "quota_utilization", wantTags, 0.5)
+}
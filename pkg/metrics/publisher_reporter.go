@@ -0,0 +1,117 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+type PublisherReportArgs struct {
+	EventType    string
+	ResponseCode int
+}
+
+func (r *PublisherReporter) register() error {
+	tagKeys := []tag.Key{
+		EventTypeKey,
+		ResponseCodeKey,
+		ResponseCodeClassKey,
+		PodNameKey,
+		ContainerNameKey,
+	}
+
+	return metrics.RegisterResourceView(
+		&view.View{
+			Name:        r.eventCountM.Name(),
+			Description: r.eventCountM.Description(),
+			Measure:     r.eventCountM,
+			Aggregation: view.Count(),
+			TagKeys:     tagKeys,
+		},
+		&view.View{
+			Name:        r.publishTimeInMsecM.Name(),
+			Description: r.publishTimeInMsecM.Description(),
+			Measure:     r.publishTimeInMsecM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000
+			TagKeys:     tagKeys,
+		},
+	)
+}
+
+// NewPublisherReporter creates a new PublisherReporter.
+func NewPublisherReporter(podName PodName, containerName ContainerName) (*PublisherReporter, error) {
+	r := &PublisherReporter{
+		podName:       podName,
+		containerName: containerName,
+		eventCountM: stats.Int64(
+			"event_count",
+			"Number of events received by a Topic publisher",
+			stats.UnitDimensionless,
+		),
+		// publishTimeInMsecM records the time spent publishing an event to
+		// Pub/Sub, in milliseconds.
+		publishTimeInMsecM: stats.Float64(
+			"event_dispatch_latencies",
+			"The time spent publishing an event to a Pub/Sub topic",
+			stats.UnitMilliseconds,
+		),
+	}
+	if err := r.register(); err != nil {
+		return nil, fmt.Errorf("failed to register publisher stats: %w", err)
+	}
+	return r, nil
+}
+
+// PublisherReporter reports the metrics of the publisher of a Topic.
+type PublisherReporter struct {
+	podName            PodName
+	containerName      ContainerName
+	eventCountM        *stats.Int64Measure
+	publishTimeInMsecM *stats.Float64Measure
+}
+
+// ReportEventCount captures an event received by the publisher, tagged by the status code it was
+// responded with.
+func (r *PublisherReporter) ReportEventCount(ctx context.Context, args PublisherReportArgs) {
+	metrics.Record(ctx, r.eventCountM.M(1), r.tags(args))
+}
+
+// ReportPublishTime captures the time spent publishing an event, tagged by the status code it was
+// responded with.
+func (r *PublisherReporter) ReportPublishTime(ctx context.Context, args PublisherReportArgs, d time.Duration) {
+	attachments := getSpanContextAttachments(ctx)
+	// convert time.Duration in nanoseconds to milliseconds.
+	metrics.Record(ctx, r.publishTimeInMsecM.M(float64(d/time.Millisecond)), stats.WithAttachments(attachments), r.tags(args))
+}
+
+func (r *PublisherReporter) tags(args PublisherReportArgs) stats.Options {
+	return stats.WithTags(
+		tag.Insert(PodNameKey, string(r.podName)),
+		tag.Insert(ContainerNameKey, string(r.containerName)),
+		tag.Insert(EventTypeKey, EventTypeMetricValue(args.EventType)),
+		tag.Insert(ResponseCodeKey, strconv.Itoa(args.ResponseCode)),
+		tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(args.ResponseCode)),
+	)
+}
@@ -27,6 +27,11 @@ const (
 	defaultEventType  = "custom"
 	labelResourceKind = "resource_kind"
 	labelResourceName = "resource_name"
+	labelReason       = "reason"
+
+	labelSchemaEnforcement = "schema_enforcement"
+	labelQuotaScope        = "quota_scope"
+	labelQuotaType         = "quota_type"
 )
 
 type PodName string
@@ -52,6 +57,13 @@ var (
 
 	PodNameKey       = tag.MustNewKey(metricskey.PodName)
 	ContainerNameKey = tag.MustNewKey(metricskey.ContainerName)
+
+	ReasonKey = tag.MustNewKey(labelReason)
+
+	SchemaEnforcementKey = tag.MustNewKey(labelSchemaEnforcement)
+
+	QuotaScopeKey = tag.MustNewKey(labelQuotaScope)
+	QuotaTypeKey  = tag.MustNewKey(labelQuotaType)
 )
 
 var (
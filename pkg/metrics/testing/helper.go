@@ -8,12 +8,17 @@ import (
 
 func ResetIngressMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
-	metricstest.Unregister("event_count", "event_dispatch_latencies")
+	metricstest.Unregister("event_count", "event_dispatch_latencies", "rejected_request_count", "duplicate_event_count", "schema_violation_count", "quota_utilization")
 }
 
 func ResetDeliveryMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
-	metricstest.Unregister("event_count", "event_dispatch_latencies", "event_processing_latencies")
+	metricstest.Unregister("event_count", "event_dispatch_latencies", "event_processing_latencies", "event_delivery_attempts", "circuit_breaker_state")
+}
+
+func ResetPublisherMetrics() {
+	// OpenCensus metrics carry global state that need to be reset between unit tests.
+	metricstest.Unregister("event_count", "event_dispatch_latencies")
 }
 
 func ResetBrokerCellMetrics() {
@@ -18,6 +18,7 @@ package adapter
 
 import (
 	"context"
+	"fmt"
 	nethttp "net/http"
 
 	"go.uber.org/zap"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	kntracing "knative.dev/eventing/pkg/tracing"
 
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	"github.com/google/knative-gcp/pkg/apis/messaging"
 	"github.com/google/knative-gcp/pkg/logging"
 	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
@@ -40,6 +42,19 @@ import (
 	"github.com/google/knative-gcp/pkg/utils/clients"
 )
 
+const (
+	// ConversionErrorExtension is the CloudEvent extension holding the conversion error of
+	// messages sent to the dead letter sink.
+	ConversionErrorExtension = "conversionerror"
+	// DeliveryErrorExtension is the CloudEvent extension holding the last delivery error of
+	// events sent to the dead letter sink.
+	DeliveryErrorExtension = "deliveryerror"
+
+	// defaultMaxDeliveryAttempts is used for dead letter sinks without a maximum number of
+	// delivery attempts, like the Pub/Sub default for dead letter policies.
+	defaultMaxDeliveryAttempts = 5
+)
+
 // AdapterArgs has a bundle of arguments needed to create an Adapter.
 type AdapterArgs struct {
 	// TopicID is the id of the Pub/Sub topic.
@@ -52,12 +67,25 @@ type AdapterArgs struct {
 	// Used for channels.
 	TransformerURI string
 
+	// DeadLetterSinkURI is the URI where to send the messages that can't be
+	// converted to events, and the events the sink keeps rejecting. If empty,
+	// the former are dropped and the latter retried.
+	DeadLetterSinkURI string
+
+	// MaxDeliveryAttempts is the number of attempts to deliver an event to the
+	// sink before sending it to DeadLetterSinkURI. If zero, defaultMaxDeliveryAttempts
+	// is used.
+	MaxDeliveryAttempts int
+
 	// Extensions is the converted ExtensionsBased64 value.
 	Extensions map[string]string
 
 	// ConverterType use to select which converter to use.
 	ConverterType converters.ConverterType
 
+	// EventMapping is the mapping used by the CloudPubSubMapping converter.
+	EventMapping *gcpduckv1.EventMapping
+
 	// AuthType is the authentication configuration mode the Pod uses.
 	AuthType authcheck.AuthType
 }
@@ -89,6 +117,9 @@ type Adapter struct {
 	// args holds a set of arguments used to configure the Adapter.
 	args *AdapterArgs
 
+	// attempts counts the failed deliveries of the messages, for the dead letter sink.
+	attempts *deliveryAttempts
+
 	// cancel is function to stop pulling messages.
 	cancel context.CancelFunc
 
@@ -116,6 +147,7 @@ func NewAdapter(
 		converter:      converter,
 		reporter:       reporter,
 		args:           args,
+		attempts:       newDeliveryAttempts(),
 		logger:         logging.FromContext(ctx),
 	}
 }
@@ -123,14 +155,31 @@ func NewAdapter(
 func (a *Adapter) Start(ctx context.Context) error {
 	ctx, a.cancel = context.WithCancel(ctx)
 
+	// Initialize probe checker to run authentication check.
+	pc := authcheck.NewProbeChecker(logging.FromContext(ctx), a.args.AuthType)
+	go pc.Start(ctx)
+	return a.receiveMessages(ctx)
+}
+
+// Receive pulls messages until the context is done or Stop is called. Unlike Start, it
+// doesn't run the authentication probe checker, so that many adapters can share a Pod.
+func (a *Adapter) Receive(ctx context.Context) error {
+	ctx, a.cancel = context.WithCancel(ctx)
+	return a.receiveMessages(ctx)
+}
+
+func (a *Adapter) receiveMessages(ctx context.Context) error {
 	// Augment context so that we can use it to create CE attributes.
 	ctx = WithProjectKey(ctx, a.projectID)
 	ctx = WithTopicKey(ctx, a.args.TopicID)
 	ctx = WithSubscriptionKey(ctx, a.subscription.ID())
-
-	// Initialize probe checker to run authentication check.
-	pc := authcheck.NewProbeChecker(logging.FromContext(ctx), a.args.AuthType)
-	go pc.Start(ctx)
+	if a.args.EventMapping != nil {
+		m, err := converters.NewEventMapping(a.args.EventMapping)
+		if err != nil {
+			return fmt.Errorf("invalid event mapping: %w", err)
+		}
+		ctx = converters.WithEventMapping(ctx, m)
+	}
 	return a.subscription.Receive(ctx, a.receive)
 }
 
@@ -145,8 +194,7 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 	event, err := a.converter.Convert(ctx, msg, a.args.ConverterType)
 	if err != nil {
 		a.logger.Debug("Failed to convert received message to an event, check the msg format: %v", zap.Error(err))
-		// Ack the message so it won't be retried, we consider all errors to be non-retryable.
-		msg.Ack()
+		a.deadLetter(ctx, msg, err)
 		return
 	}
 
@@ -223,7 +271,7 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 	response, err := a.sendMsg(ctx, a.args.SinkURI, (*binding.EventMessage)(event))
 	if err != nil {
 		a.logger.Error("Failed to send message to sink", zap.String("address", a.args.SinkURI), zap.Error(err))
-		msg.Nack()
+		a.retryOrDeadLetter(ctx, msg, event, err)
 		return
 	}
 
@@ -237,13 +285,92 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 
 	if response.StatusCode/100 != 2 {
 		a.logger.Error("Event delivery failed", zap.Int("StatusCode", response.StatusCode))
+		a.retryOrDeadLetter(ctx, msg, event, fmt.Errorf("HTTP status code %d", response.StatusCode))
+		return
+	}
+
+	a.ack(msg)
+}
+
+// retryOrDeadLetter handles a failed delivery of an event to the sink. Subscriptions with a Pub/Sub
+// dead letter policy, or without a dead letter sink, leave the retries to Pub/Sub. Otherwise the
+// adapter counts the delivery attempts, and sends the event to the dead letter sink once they are
+// exhausted.
+func (a *Adapter) retryOrDeadLetter(ctx context.Context, msg *pubsub.Message, event *cev2.Event, deliveryErr error) {
+	if msg.DeliveryAttempt != nil || a.args.DeadLetterSinkURI == "" {
+		msg.Nack()
+		return
+	}
+	maxAttempts := a.args.MaxDeliveryAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxDeliveryAttempts
+	}
+	if attempts := a.attempts.inc(msg.ID); attempts < maxAttempts {
 		msg.Nack()
 		return
 	}
+	a.logger.Warn("Event delivery attempts exhausted, sending to dead letter sink", zap.String("id", msg.ID), zap.Error(deliveryErr))
+	event.SetExtension(DeliveryErrorExtension, deliveryErr.Error())
+	a.sendToDeadLetterSink(ctx, msg, event)
+}
 
+// ack acks the message, and stops counting its delivery attempts.
+func (a *Adapter) ack(msg *pubsub.Message) {
+	a.attempts.forget(msg.ID)
 	msg.Ack()
 }
 
+// deadLetter handles a message that can't be converted to an event, we consider all
+// conversion errors to be non-retryable.
+func (a *Adapter) deadLetter(ctx context.Context, msg *pubsub.Message, convErr error) {
+	// The delivery attempt is only set if the subscription has a dead letter policy. Nack the
+	// message so that Pub/Sub forwards it to the dead letter topic once it runs out of attempts.
+	if msg.DeliveryAttempt != nil {
+		msg.Nack()
+		return
+	}
+	if a.args.DeadLetterSinkURI == "" {
+		// Ack the message so it won't be retried.
+		msg.Ack()
+		return
+	}
+
+	// Wrap the raw message, which doesn't depend on the message format.
+	event, err := a.converter.Convert(ctx, msg, converters.CloudPubSub)
+	if err != nil {
+		a.logger.Error("Failed to wrap unconvertible message for the dead letter sink", zap.Error(err))
+		msg.Nack()
+		return
+	}
+	event.SetExtension(ConversionErrorExtension, convErr.Error())
+	a.sendToDeadLetterSink(ctx, msg, event)
+}
+
+// sendToDeadLetterSink sends the event to the dead letter sink, and acks the message once the sink
+// accepts it.
+func (a *Adapter) sendToDeadLetterSink(ctx context.Context, msg *pubsub.Message, event *cev2.Event) {
+	response, err := a.sendMsg(ctx, a.args.DeadLetterSinkURI, (*binding.EventMessage)(event))
+	if err != nil {
+		a.logger.Error("Failed to send message to dead letter sink", zap.String("address", a.args.DeadLetterSinkURI), zap.Error(err))
+		msg.Nack()
+		return
+	}
+
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			a.logger.Warn("Failed to close response body", zap.Error(err))
+		}
+	}()
+
+	if response.StatusCode/100 != 2 {
+		a.logger.Error("Dead letter delivery failed", zap.Int("StatusCode", response.StatusCode))
+		msg.Nack()
+		return
+	}
+
+	a.ack(msg)
+}
+
 func (a *Adapter) sendMsg(ctx context.Context, address string, msg binding.Message) (*nethttp.Response, error) {
 	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, address, nil)
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
 	"github.com/google/knative-gcp/pkg/utils/clients"
 	"golang.org/x/sync/errgroup"
 	logtest "knative.dev/pkg/logging/testing"
@@ -283,6 +285,164 @@ func TestAdapter(t *testing.T) {
 	}
 }
 
+func TestAdapterDeadLetterSink(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+
+	deadLetterClient, err := cehttp.New()
+	if err != nil {
+		t.Fatalf("failed to create dead letter sink cloudevents client: %v", err)
+	}
+	deadLetterSvr := httptest.NewServer(deadLetterClient)
+	defer deadLetterSvr.Close()
+
+	c, close := testPubsubClient(ctx, t, testProjectID)
+	defer close()
+
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	// A storage converter can't convert a message without the storage attributes.
+	adapter := NewAdapter(ctx,
+		clients.ProjectID(testProjectID),
+		Namespace(testNamespace),
+		Name(testName),
+		ResourceGroup(testResourceGroup),
+		sub,
+		http.DefaultClient,
+		converters.NewPubSubConverter(),
+		&statsReporterRecorder{},
+		&AdapterArgs{
+			TopicID:           testTopic,
+			SinkURI:           "http://sink.invalid",
+			DeadLetterSinkURI: deadLetterSvr.URL,
+			ConverterType:     converters.CloudStorage,
+		})
+
+	rctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	group, _ := errgroup.WithContext(rctx)
+	group.Go(func() error { return adapter.Start(rctx) })
+	defer adapter.Stop()
+
+	if _, err := topic.Publish(rctx, &pubsub.Message{Data: []byte("poison")}).Get(rctx); err != nil {
+		t.Fatalf("failed to publish message: %v", err)
+	}
+
+	msg, err := deadLetterClient.Receive(rctx)
+	if err != nil {
+		t.Fatalf("unexpected error from dead letter sink when receiving event: %v", err)
+	}
+	defer msg.Finish(nil)
+	gotEvent, err := binding.ToEvent(rctx, msg)
+	if err != nil {
+		t.Fatalf("dead letter sink received message that cannot be converted to an event: %v", err)
+	}
+	if got, want := gotEvent.Type(), schemasv1.CloudPubSubMessagePublishedEventType; got != want {
+		t.Errorf("unexpected event type, want %q, got %q", want, got)
+	}
+	if got, want := gotEvent.Extensions()[ConversionErrorExtension], "received event did not have bucketId"; got != want {
+		t.Errorf("unexpected %s extension, want %q, got %q", ConversionErrorExtension, want, got)
+	}
+
+	cancel()
+	if err := group.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdapterDeadLetterSinkAfterDeliveryAttempts(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+
+	var sinkAttempts int32
+	sinkSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sinkAttempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer sinkSvr.Close()
+
+	deadLetterClient, err := cehttp.New()
+	if err != nil {
+		t.Fatalf("failed to create dead letter sink cloudevents client: %v", err)
+	}
+	deadLetterSvr := httptest.NewServer(deadLetterClient)
+	defer deadLetterSvr.Close()
+
+	c, close := testPubsubClient(ctx, t, testProjectID)
+	defer close()
+
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	adapter := NewAdapter(ctx,
+		clients.ProjectID(testProjectID),
+		Namespace(testNamespace),
+		Name(testName),
+		ResourceGroup(testResourceGroup),
+		sub,
+		http.DefaultClient,
+		converters.NewPubSubConverter(),
+		&statsReporterRecorder{},
+		&AdapterArgs{
+			TopicID:             testTopic,
+			SinkURI:             sinkSvr.URL,
+			DeadLetterSinkURI:   deadLetterSvr.URL,
+			MaxDeliveryAttempts: 3,
+			ConverterType:       converters.CloudPubSub,
+		})
+
+	rctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	group, _ := errgroup.WithContext(rctx)
+	group.Go(func() error { return adapter.Start(rctx) })
+	defer adapter.Stop()
+
+	if _, err := topic.Publish(rctx, &pubsub.Message{Data: []byte("rejected")}).Get(rctx); err != nil {
+		t.Fatalf("failed to publish message: %v", err)
+	}
+
+	msg, err := deadLetterClient.Receive(rctx)
+	if err != nil {
+		t.Fatalf("unexpected error from dead letter sink when receiving event: %v", err)
+	}
+	defer msg.Finish(nil)
+	gotEvent, err := binding.ToEvent(rctx, msg)
+	if err != nil {
+		t.Fatalf("dead letter sink received message that cannot be converted to an event: %v", err)
+	}
+	if got, want := gotEvent.Type(), schemasv1.CloudPubSubMessagePublishedEventType; got != want {
+		t.Errorf("unexpected event type, want %q, got %q", want, got)
+	}
+	if got, want := gotEvent.Extensions()[DeliveryErrorExtension], "HTTP status code 500"; got != want {
+		t.Errorf("unexpected %s extension, want %q, got %q", DeliveryErrorExtension, want, got)
+	}
+	if got, want := atomic.LoadInt32(&sinkAttempts), int32(3); got != want {
+		t.Errorf("unexpected delivery attempts to the sink, want %d, got %d", want, got)
+	}
+
+	cancel()
+	if err := group.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func newSampleEvent() *event.Event {
 	sampleEvent := event.New()
 	sampleEvent.SetID("id")
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import "sync"
+
+// maxTrackedMessages bounds the number of messages whose delivery attempts are counted. Once
+// reached, the counts are reset, which only delays the dead lettering of the messages in flight.
+const maxTrackedMessages = 10000
+
+// deliveryAttempts counts the failed deliveries of messages, by message ID. Pub/Sub only counts
+// them for subscriptions with a dead letter policy, which requires a dead letter topic. The counts
+// are kept in memory, so they are per replica and lost when the adapter restarts.
+type deliveryAttempts struct {
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func newDeliveryAttempts() *deliveryAttempts {
+	return &deliveryAttempts{attempts: make(map[string]int)}
+}
+
+// inc records a failed delivery of the message and returns its number of failed deliveries.
+func (d *deliveryAttempts) inc(id string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.attempts[id]; !ok && len(d.attempts) >= maxTrackedMessages {
+		d.attempts = make(map[string]int)
+	}
+	d.attempts[id]++
+	return d.attempts[id]
+}
+
+// forget stops counting the delivery attempts of the message, once it is acked.
+func (d *deliveryAttempts) forget(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.attempts, id)
+}
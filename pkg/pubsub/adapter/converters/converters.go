@@ -50,6 +50,9 @@ const (
 	CloudScheduler ConverterType = "scheduler"
 	CloudBuild     ConverterType = "build"
 	PubSubPull     ConverterType = "pubsub_pull"
+	// CloudPubSubMapping maps the messages according to the EventMapping in
+	// the context, see WithEventMapping.
+	CloudPubSubMapping ConverterType = "pubsub_mapping"
 )
 
 type converterFn func(context.Context, *pubsub.Message) (*cev2.Event, error)
@@ -71,12 +74,13 @@ type PubSubConverter struct {
 func NewPubSubConverter() Converter {
 	return &PubSubConverter{
 		converters: map[ConverterType]converterFn{
-			CloudPubSub:    convertCloudPubSub,
-			CloudAuditLogs: convertCloudAuditLogs,
-			CloudStorage:   convertCloudStorage,
-			CloudScheduler: convertCloudScheduler,
-			CloudBuild:     convertCloudBuild,
-			PubSubPull:     convertPubSubPull,
+			CloudPubSub:        convertCloudPubSub,
+			CloudAuditLogs:     convertCloudAuditLogs,
+			CloudStorage:       convertCloudStorage,
+			CloudScheduler:     convertCloudScheduler,
+			CloudBuild:         convertCloudBuild,
+			PubSubPull:         convertPubSubPull,
+			CloudPubSubMapping: convertPubSubMapping,
 		},
 	}
 }
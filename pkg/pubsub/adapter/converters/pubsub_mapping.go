@@ -0,0 +1,281 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+// noValue is what text/template renders for missing JSON fields.
+const noValue = "<no value>"
+
+// EventMapping is a gcpduckv1.EventMapping with parsed templates.
+type EventMapping struct {
+	eventType       *template.Template
+	source          *template.Template
+	subject         *template.Template
+	dataContentType *template.Template
+	extensions      map[string]*template.Template
+	attributeKeys   gcpduckv1.AttributeKeyPolicy
+}
+
+// NewEventMapping parses the templates of m.
+func NewEventMapping(m *gcpduckv1.EventMapping) (*EventMapping, error) {
+	em := &EventMapping{
+		extensions:    make(map[string]*template.Template, len(m.Extensions)),
+		attributeKeys: m.AttributeKeys,
+	}
+	var err error
+	for _, t := range []struct {
+		name string
+		text string
+		tmpl **template.Template
+	}{
+		{"type", m.Type, &em.eventType},
+		{"source", m.Source, &em.source},
+		{"subject", m.Subject, &em.subject},
+		{"dataContentType", m.DataContentType, &em.dataContentType},
+	} {
+		if *t.tmpl, err = gcpduckv1.ParseMappingTemplate(t.name, t.text); err != nil {
+			return nil, err
+		}
+	}
+	for name, text := range m.Extensions {
+		if em.extensions[name], err = gcpduckv1.ParseMappingTemplate(name, text); err != nil {
+			return nil, err
+		}
+	}
+	return em, nil
+}
+
+// The key used to store/retrieve the EventMapping in the context.
+type eventMappingKey struct{}
+
+// WithEventMapping sets the EventMapping used by the CloudPubSubMapping converter
+// in the context.
+func WithEventMapping(ctx context.Context, m *EventMapping) context.Context {
+	return context.WithValue(ctx, eventMappingKey{}, m)
+}
+
+// getEventMapping gets the EventMapping from the context, or an empty one if
+// there is none.
+func getEventMapping(ctx context.Context) *EventMapping {
+	if m, ok := ctx.Value(eventMappingKey{}).(*EventMapping); ok && m != nil {
+		return m
+	}
+	return &EventMapping{}
+}
+
+// mappingInput is what the templates of an EventMapping are executed on.
+type mappingInput struct {
+	ID           string
+	PublishTime  time.Time
+	OrderingKey  string
+	Attributes   map[string]string
+	Data         interface{}
+	Project      string
+	Topic        string
+	Subscription string
+}
+
+func render(t *template.Template, in *mappingInput) (string, error) {
+	if t == nil {
+		return "", nil
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, in); err != nil {
+		return "", err
+	}
+	s := strings.TrimSpace(b.String())
+	if s == noValue {
+		return "", nil
+	}
+	return s, nil
+}
+
+// decodeData decodes a JSON payload. Numbers are kept as json.Number, so that large integers, like
+// IDs, render as they were published rather than rounded in floating point notation.
+func decodeData(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	// Like json.Unmarshal, reject anything after the JSON value.
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return v, nil
+}
+
+func convertPubSubMapping(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	project, err := GetProjectKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	topic, err := GetTopicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := GetSubscriptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := getEventMapping(ctx)
+
+	in := &mappingInput{
+		ID:           msg.ID,
+		PublishTime:  msg.PublishTime,
+		OrderingKey:  msg.OrderingKey,
+		Attributes:   msg.Attributes,
+		Project:      project,
+		Topic:        topic,
+		Subscription: subscription,
+	}
+	if in.Attributes == nil {
+		in.Attributes = map[string]string{}
+	}
+	// The payload is only exposed to the templates if it is JSON.
+	if in.Data, err = decodeData(msg.Data); err != nil {
+		in.Data = nil
+	}
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+
+	var dataContentType string
+	attrs := []struct {
+		name string
+		tmpl *template.Template
+		def  string
+		set  func(string)
+	}{
+		{"type", m.eventType, schemasv1.CloudPubSubMessagePublishedEventType, event.SetType},
+		{"source", m.source, schemasv1.CloudPubSubEventSource(project, topic), event.SetSource},
+		{"subject", m.subject, "", event.SetSubject},
+		// We do not know the content type of the payload unless it's mapped,
+		// thus we default to this generic one.
+		{"dataContentType", m.dataContentType, "application/octet-stream", func(v string) { dataContentType = v }},
+	}
+	for _, a := range attrs {
+		v, err := render(a.tmpl, in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map %s: %w", a.name, err)
+		}
+		if v == "" {
+			v = a.def
+		}
+		if v != "" {
+			a.set(v)
+		}
+	}
+
+	promoteAttributes(&event, msg.Attributes, m.attributeKeys)
+
+	for name, tmpl := range m.extensions {
+		v, err := render(tmpl, in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map extension %q: %w", name, err)
+		}
+		if v == "" {
+			event.SetExtension(name, nil)
+			continue
+		}
+		event.SetExtension(name, v)
+	}
+
+	if err := event.SetData(dataContentType, msg.Data); err != nil {
+		return nil, err
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// promoteAttributes promotes the Pub/Sub attributes to CloudEvent extensions
+// according to the policy. Attributes whose lower cased keys are valid extension
+// names are promoted as is. With the Sanitize policy, the other keys are lower
+// cased and stripped of the characters that aren't letters or digits, unless
+// that clashes with another attribute. With the Drop policy they are skipped,
+// and with the Ignore policy no attribute is promoted.
+func promoteAttributes(event *cev2.Event, attributes map[string]string, policy gcpduckv1.AttributeKeyPolicy) {
+	if policy == gcpduckv1.AttributeKeysIgnore || len(attributes) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var invalid []string
+	for _, k := range keys {
+		if name := strings.ToLower(k); gcpduckv1.IsExtensionName(name) {
+			event.SetExtension(name, attributes[k])
+		} else {
+			invalid = append(invalid, k)
+		}
+	}
+	if policy == gcpduckv1.AttributeKeysDrop {
+		return
+	}
+	promoted := event.Extensions()
+	for _, k := range invalid {
+		name := sanitizeKey(k)
+		if !gcpduckv1.IsExtensionName(name) {
+			continue
+		}
+		if _, ok := promoted[name]; ok {
+			continue
+		}
+		event.SetExtension(name, attributes[k])
+	}
+}
+
+// sanitizeKey lower cases key and strips the characters that aren't allowed in
+// CloudEvent extension names.
+func sanitizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return -1
+		}
+	}, key)
+}
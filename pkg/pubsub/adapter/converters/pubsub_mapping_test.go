@@ -0,0 +1,226 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+func TestConvertPubSubMapping(t *testing.T) {
+	publishTime := time.Unix(1610000000, 0).UTC()
+
+	tests := []struct {
+		name        string
+		mapping     *gcpduckv1.EventMapping
+		message     *pubsub.Message
+		wantEventFn func() *cev2.Event
+		wantErr     bool
+	}{{
+		name: "no mapping",
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte("test data"),
+			Attributes: map[string]string{
+				"attribute1": "value1",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := pubSubMapping(publishTime, []byte("test data"))
+			e.SetExtension("attribute1", "value1")
+			return e
+		},
+	}, {
+		name: "mapped from attributes and data",
+		mapping: &gcpduckv1.EventMapping{
+			Type:            `com.example.{{ .Attributes.kind }}`,
+			Source:          `//example.com/{{ .Project }}/{{ .Topic }}`,
+			Subject:         `{{ .Data.name }}`,
+			DataContentType: `application/json`,
+			Extensions: map[string]string{
+				"region":  `{{ or .Attributes.region "global" }}`,
+				"ordered": `{{ .OrderingKey }}`,
+			},
+			AttributeKeys: gcpduckv1.AttributeKeysIgnore,
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte(`{"name":"foo"}`),
+			Attributes: map[string]string{
+				"kind": "created",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := pubSubMapping(publishTime, []byte(`{"name":"foo"}`))
+			e.SetType("com.example.created")
+			e.SetSource("//example.com/testproject/testtopic")
+			e.SetSubject("foo")
+			e.SetDataContentType("application/json")
+			e.SetExtension("region", "global")
+			return e
+		},
+	}, {
+		name: "missing data field",
+		mapping: &gcpduckv1.EventMapping{
+			Subject: `{{ .Data.name }}`,
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte(`{}`),
+		},
+		wantEventFn: func() *cev2.Event {
+			return pubSubMapping(publishTime, []byte(`{}`))
+		},
+	}, {
+		name: "data field of a non JSON payload",
+		mapping: &gcpduckv1.EventMapping{
+			Subject: `{{ .Data.name }}`,
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte("test data"),
+		},
+		wantErr: true,
+	}, {
+		name: "data field of a JSON payload with trailing data",
+		mapping: &gcpduckv1.EventMapping{
+			Subject: `{{ .Data.name }}`,
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte(`{"name":"foo"} trailing`),
+		},
+		wantErr: true,
+	}, {
+		name: "large integer data field",
+		mapping: &gcpduckv1.EventMapping{
+			Subject: `{{ .Data.id }}`,
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte(`{"id":12345678901234567890}`),
+		},
+		wantEventFn: func() *cev2.Event {
+			e := pubSubMapping(publishTime, []byte(`{"id":12345678901234567890}`))
+			e.SetSubject("12345678901234567890")
+			return e
+		},
+	}, {
+		name: "sanitized attribute keys",
+		mapping: &gcpduckv1.EventMapping{
+			Extensions: map[string]string{
+				"attribute3": `mapped`,
+			},
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte("test data"),
+			Attributes: map[string]string{
+				"Attribute1":   "value1",
+				"attribute-2":  "value2",
+				"attribute_2":  "value3",
+				"attribute2":   "value4",
+				"attribute-3":  "value5",
+				"type":         "value6",
+				"goog-reserve": "value7",
+				"---":          "value8",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := pubSubMapping(publishTime, []byte("test data"))
+			e.SetExtension("attribute1", "value1")
+			e.SetExtension("attribute2", "value4")
+			e.SetExtension("attribute3", "mapped")
+			e.SetExtension("googreserve", "value7")
+			return e
+		},
+	}, {
+		name: "dropped attribute keys",
+		mapping: &gcpduckv1.EventMapping{
+			AttributeKeys: gcpduckv1.AttributeKeysDrop,
+		},
+		message: &pubsub.Message{
+			ID:          "id",
+			PublishTime: publishTime,
+			Data:        []byte("test data"),
+			Attributes: map[string]string{
+				"Attribute1":  "value1",
+				"attribute-2": "value2",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := pubSubMapping(publishTime, []byte("test data"))
+			e.SetExtension("attribute1", "value1")
+			return e
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithProjectKey(context.Background(), "testproject")
+			ctx = WithTopicKey(ctx, "testtopic")
+			ctx = WithSubscriptionKey(ctx, "testsubscription")
+			if test.mapping != nil {
+				m, err := NewEventMapping(test.mapping)
+				if err != nil {
+					t.Fatalf("NewEventMapping() = %v", err)
+				}
+				ctx = WithEventMapping(ctx, m)
+			}
+
+			gotEvent, err := NewPubSubConverter().Convert(ctx, test.message, CloudPubSubMapping)
+			if err != nil {
+				if !test.wantErr {
+					t.Errorf("converters.convertPubSubMapping got error %v want error=%v", err, test.wantErr)
+				}
+				return
+			}
+			if test.wantErr {
+				t.Fatalf("converters.convertPubSubMapping got event %v, want error", gotEvent)
+			}
+			if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+				t.Errorf("converters.convertPubSubMapping got unexpected cloudevents.Event (-want +got) %s", diff)
+			}
+		})
+	}
+}
+
+func pubSubMapping(publishTime time.Time, data []byte) *cev2.Event {
+	e := cev2.NewEvent(cev2.VersionV1)
+	e.SetID("id")
+	e.SetTime(publishTime)
+	e.SetSource(schemasv1.CloudPubSubEventSource("testproject", "testtopic"))
+	e.SetType(schemasv1.CloudPubSubMessagePublishedEventType)
+	e.SetData("application/octet-stream", data)
+	return &e
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shared contains the shared receive adapter, which runs the receive
+// loops of many PullSubscriptions in a single Deployment.
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+const (
+	// ConfigMapPrefix is the prefix of the names of the ConfigMaps, in the system
+	// namespace, that hold the subscriptions served by the shared receive adapter.
+	ConfigMapPrefix = "shared-receive-adapter-subscriptions-"
+	// ConfigMapShards is the number of ConfigMaps the subscriptions are spread
+	// over, so that each of them stays below the 1 MiB size limit of ConfigMaps.
+	ConfigMapShards = 16
+	// ConfigMapKey is the ConfigMap key holding the serialized Config of the
+	// subscriptions of a shard.
+	ConfigMapKey = "subscriptions"
+	// DeploymentName is the name of the shared receive adapter Deployment in the
+	// system namespace.
+	DeploymentName = "shared-receive-adapter"
+)
+
+// SubscriptionConfig holds everything needed to pull the messages of a
+// PullSubscription and deliver them as events.
+type SubscriptionConfig struct {
+	// Namespace and Name identify the resource in metrics and traces. For Channels,
+	// Name is the Channel's name rather than the PullSubscription's.
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	ResourceGroup string `json:"resourceGroup"`
+
+	ProjectID      string `json:"projectID"`
+	TopicID        string `json:"topicID"`
+	SubscriptionID string `json:"subscriptionID"`
+
+	SinkURI             string            `json:"sinkURI"`
+	TransformerURI      string            `json:"transformerURI,omitempty"`
+	DeadLetterSinkURI   string            `json:"deadLetterSinkURI,omitempty"`
+	MaxDeliveryAttempts int               `json:"maxDeliveryAttempts,omitempty"`
+	AdapterType         string            `json:"adapterType,omitempty"`
+	Extensions          map[string]string `json:"extensions,omitempty"`
+
+	EventMapping *duckv1.EventMapping `json:"eventMapping,omitempty"`
+}
+
+// Config is the set of subscriptions served by the shared receive adapter,
+// keyed by the namespace/name of their PullSubscription.
+type Config struct {
+	Subscriptions map[string]*SubscriptionConfig `json:"subscriptions,omitempty"`
+}
+
+// Key returns the key of a PullSubscription in Config.Subscriptions.
+func Key(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// ConfigMapName returns the name of the ConfigMap holding the subscription with
+// the given key.
+func ConfigMapName(key string) string {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return ConfigMapPrefix + strconv.Itoa(int(h.Sum32()%ConfigMapShards))
+}
+
+// ParseConfig deserializes a Config. Empty data is an empty Config.
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if len(data) == 0 {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shared receive adapter config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Bytes serializes the Config.
+func (c *Config) Bytes() ([]byte, error) {
+	return json.Marshal(c)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"math"
+	nethttp "net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/utils/clients"
+)
+
+// Pool runs one adapter per subscription of the shared receive adapter Config.
+type Pool struct {
+	outbound   *nethttp.Client
+	converter  converters.Converter
+	clientOpts []option.ClientOption
+	// newClient creates the Pub/Sub client of a project.
+	newClient func(ctx context.Context, projectID string, opts ...option.ClientOption) (*pubsub.Client, error)
+
+	mut      sync.Mutex
+	adapters map[string]*runningAdapter
+
+	// clientsMut guards clients. The adapters create their clients while Sync holds mut.
+	clientsMut sync.Mutex
+	clients    map[string]*pubsub.Client
+}
+
+// restartBackoff is the delay before starting again an adapter that failed to
+// start, or that stopped on its own, e.g. because its subscription didn't exist
+// yet.
+var restartBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Steps:    math.MaxInt32,
+	Cap:      time.Minute,
+}
+
+type runningAdapter struct {
+	config *SubscriptionConfig
+	cancel context.CancelFunc
+	// done is closed once the adapter is stopped and won't be restarted.
+	done chan struct{}
+}
+
+// NewPool creates a Pool that sends events with the given HTTP client and
+// converter. The client options are used to create the Pub/Sub clients.
+func NewPool(outbound *nethttp.Client, converter converters.Converter, clientOpts ...option.ClientOption) *Pool {
+	return &Pool{
+		outbound:   outbound,
+		converter:  converter,
+		clientOpts: clientOpts,
+		newClient:  pubsub.NewClient,
+		clients:    make(map[string]*pubsub.Client),
+		adapters:   make(map[string]*runningAdapter),
+	}
+}
+
+// Sync starts an adapter for every new or updated subscription of the Config,
+// and stops the adapters of removed or updated subscriptions. The adapter of an
+// updated subscription is started once the previous one is stopped, so that
+// they don't receive from the subscription at the same time.
+func (p *Pool) Sync(ctx context.Context, cfg *Config) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	stopped := make(map[string]*runningAdapter)
+	for key, ra := range p.adapters {
+		if sub, ok := cfg.Subscriptions[key]; !ok || !reflect.DeepEqual(sub, ra.config) {
+			ra.cancel()
+			delete(p.adapters, key)
+			stopped[key] = ra
+		}
+	}
+
+	for key, sub := range cfg.Subscriptions {
+		if _, ok := p.adapters[key]; ok {
+			continue
+		}
+		p.adapters[key] = p.start(ctx, sub, stopped[key])
+	}
+}
+
+// Close stops all the adapters, waits for them to stop and closes the Pub/Sub
+// clients.
+func (p *Pool) Close() {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	for _, ra := range p.adapters {
+		ra.cancel()
+	}
+	for key, ra := range p.adapters {
+		<-ra.done
+		delete(p.adapters, key)
+	}
+	p.clientsMut.Lock()
+	defer p.clientsMut.Unlock()
+	for project, client := range p.clients {
+		client.Close()
+		delete(p.clients, project)
+	}
+}
+
+// start runs an adapter for the subscription once prev, the previous adapter of
+// the subscription if any, is stopped. The adapter is started again with
+// restartBackoff if it fails to start or stops on its own.
+func (p *Pool) start(ctx context.Context, sub *SubscriptionConfig, prev *runningAdapter) *runningAdapter {
+	logger := logging.FromContext(ctx).With(
+		zap.String("namespace", sub.Namespace),
+		zap.String("name", sub.Name),
+		zap.String("subscriptionID", sub.SubscriptionID))
+	ctx, cancel := context.WithCancel(logging.WithLogger(ctx, logger))
+
+	ra := &runningAdapter{
+		config: sub,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(ra.done)
+		if prev != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-prev.done:
+			}
+		}
+		backoff := restartBackoff
+		var a *adapter.Adapter
+		for {
+			started := time.Now()
+			var err error
+			if a == nil {
+				a, err = p.newAdapter(ctx, sub)
+			}
+			if a != nil {
+				logger.Info("Starting adapter")
+				err = a.Receive(ctx)
+			}
+			if ctx.Err() != nil {
+				logger.Info("Adapter has stopped")
+				return
+			}
+			if time.Since(started) > restartBackoff.Cap {
+				// The adapter ran fine for a while, so it doesn't keep failing.
+				backoff = restartBackoff
+			}
+			delay := backoff.Step()
+			if a == nil {
+				logger.Error("Failed to start adapter, retrying", zap.Error(err), zap.Duration("delay", delay))
+			} else {
+				logger.Error("Adapter has stopped on its own, restarting it", zap.Error(err), zap.Duration("delay", delay))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}()
+	return ra
+}
+
+func (p *Pool) newAdapter(ctx context.Context, sub *SubscriptionConfig) (*adapter.Adapter, error) {
+	client, err := p.client(ctx, sub.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	reporter, err := adapter.NewStatsReporter(adapter.Name(sub.Name), adapter.Namespace(sub.Namespace), adapter.ResourceGroup(sub.ResourceGroup))
+	if err != nil {
+		return nil, err
+	}
+	return adapter.NewAdapter(ctx,
+		clients.ProjectID(sub.ProjectID),
+		adapter.Namespace(sub.Namespace),
+		adapter.Name(sub.Name),
+		adapter.ResourceGroup(sub.ResourceGroup),
+		client.Subscription(sub.SubscriptionID),
+		p.outbound,
+		p.converter,
+		reporter,
+		&adapter.AdapterArgs{
+			TopicID:             sub.TopicID,
+			SinkURI:             sub.SinkURI,
+			TransformerURI:      sub.TransformerURI,
+			DeadLetterSinkURI:   sub.DeadLetterSinkURI,
+			MaxDeliveryAttempts: sub.MaxDeliveryAttempts,
+			Extensions:          sub.Extensions,
+			ConverterType:       converters.ConverterType(sub.AdapterType),
+			EventMapping:        sub.EventMapping,
+		}), nil
+}
+
+func (p *Pool) client(ctx context.Context, projectID string) (*pubsub.Client, error) {
+	p.clientsMut.Lock()
+	defer p.clientsMut.Unlock()
+	if client, ok := p.clients[projectID]; ok {
+		return client, nil
+	}
+	client, err := p.newClient(ctx, projectID, p.clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[projectID] = client
+	return client, nil
+}
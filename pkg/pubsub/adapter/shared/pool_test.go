@@ -0,0 +1,315 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+const testProject = "test-project"
+
+func TestPoolSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logtest.TestLogger(t).Desugar()))
+	defer cancel()
+
+	srv := pstest.NewServer()
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial test pubsub connection: %v", err)
+	}
+	defer conn.Close()
+	psClient, err := pubsub.NewClient(ctx, testProject, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("failed to create test pubsub client: %v", err)
+	}
+	topic, err := psClient.CreateTopic(ctx, "topic")
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	for _, id := range []string{"sub1", "sub2"} {
+		if _, err := psClient.CreateSubscription(ctx, id, pubsub.SubscriptionConfig{Topic: topic}); err != nil {
+			t.Fatalf("failed to create subscription: %v", err)
+		}
+	}
+
+	received := make(chan string, 10)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		msg := cehttp.NewMessageFromHttpRequest(req)
+		defer msg.Finish(nil)
+		received <- req.URL.Path + ":" + msg.ReadEncoding().String()
+	}))
+	defer sink.Close()
+
+	p := NewPool(http.DefaultClient, converters.NewPubSubConverter(), option.WithGRPCConn(conn))
+	defer p.Close()
+
+	subscription := func(id string) *SubscriptionConfig {
+		return &SubscriptionConfig{
+			Namespace:      "ns",
+			Name:           id,
+			ResourceGroup:  "pullsubscriptions.internal.events.cloud.google.com",
+			ProjectID:      testProject,
+			TopicID:        "topic",
+			SubscriptionID: id,
+			SinkURI:        sink.URL + "/" + id,
+			AdapterType:    string(converters.CloudPubSub),
+		}
+	}
+	p.Sync(ctx, &Config{Subscriptions: map[string]*SubscriptionConfig{
+		Key("ns", "ps1"): subscription("sub1"),
+		Key("ns", "ps2"): subscription("sub2"),
+	}})
+
+	topic.Publish(ctx, &pubsub.Message{Data: []byte("hello")})
+	got := map[string]bool{}
+	for len(got) < 2 {
+		select {
+		case r := <-received:
+			got[r] = true
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+	for _, want := range []string{"/sub1:binary", "/sub2:binary"} {
+		if !got[want] {
+			t.Errorf("missing delivery %q, got %v", want, got)
+		}
+	}
+
+	ra := p.adapters[Key("ns", "ps2")]
+	p.Sync(ctx, &Config{Subscriptions: map[string]*SubscriptionConfig{
+		Key("ns", "ps1"): subscription("sub1"),
+	}})
+	if _, ok := p.adapters[Key("ns", "ps2")]; ok {
+		t.Error("adapter of removed subscription is still running")
+	}
+	select {
+	case <-ra.done:
+	case <-time.After(10 * time.Second):
+		t.Error("timed out waiting for the removed adapter to stop")
+	}
+	if _, ok := p.adapters[Key("ns", "ps1")]; !ok {
+		t.Error("adapter of unchanged subscription was stopped")
+	}
+}
+
+func TestPoolRestartsStoppedAdapters(t *testing.T) {
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logtest.TestLogger(t).Desugar()))
+	defer cancel()
+
+	savedBackoff := restartBackoff
+	restartBackoff.Duration = 10 * time.Millisecond
+	defer func() { restartBackoff = savedBackoff }()
+
+	srv := pstest.NewServer()
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial test pubsub connection: %v", err)
+	}
+	defer conn.Close()
+	psClient, err := pubsub.NewClient(ctx, testProject, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("failed to create test pubsub client: %v", err)
+	}
+	topic, err := psClient.CreateTopic(ctx, "topic")
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+
+	received := make(chan string, 10)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received <- req.URL.Path
+	}))
+	defer sink.Close()
+
+	p := NewPool(http.DefaultClient, converters.NewPubSubConverter(), option.WithGRPCConn(conn))
+	defer p.Close()
+
+	// The adapter stops on its own as the subscription doesn't exist yet.
+	p.Sync(ctx, &Config{Subscriptions: map[string]*SubscriptionConfig{
+		Key("ns", "ps"): {
+			Namespace:      "ns",
+			Name:           "ps",
+			ResourceGroup:  "pullsubscriptions.internal.events.cloud.google.com",
+			ProjectID:      testProject,
+			TopicID:        "topic",
+			SubscriptionID: "sub",
+			SinkURI:        sink.URL + "/sub",
+			AdapterType:    string(converters.CloudPubSub),
+		},
+	}})
+	time.Sleep(100 * time.Millisecond)
+	if _, err := psClient.CreateSubscription(ctx, "sub", pubsub.SubscriptionConfig{Topic: topic}); err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	topic.Publish(ctx, &pubsub.Message{Data: []byte("hello")})
+	select {
+	case got := <-received:
+		if got != "/sub" {
+			t.Errorf("unexpected delivery %q", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the restarted adapter to deliver the event")
+	}
+}
+
+func TestPoolRetriesFailedStarts(t *testing.T) {
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logtest.TestLogger(t).Desugar()))
+	defer cancel()
+
+	savedBackoff := restartBackoff
+	restartBackoff.Duration = 10 * time.Millisecond
+	defer func() { restartBackoff = savedBackoff }()
+
+	conn, topic := newTestTopic(ctx, t, "sub")
+	received := make(chan string, 10)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received <- req.URL.Path
+	}))
+	defer sink.Close()
+
+	p := NewPool(http.DefaultClient, converters.NewPubSubConverter(), option.WithGRPCConn(conn))
+	defer p.Close()
+	failures := 3
+	p.newClient = func(ctx context.Context, projectID string, opts ...option.ClientOption) (*pubsub.Client, error) {
+		if failures > 0 {
+			failures--
+			return nil, errors.New("client creation failed")
+		}
+		return pubsub.NewClient(ctx, projectID, opts...)
+	}
+
+	p.Sync(ctx, &Config{Subscriptions: map[string]*SubscriptionConfig{
+		Key("ns", "ps"): testSubscription(sink.URL, "sub"),
+	}})
+
+	topic.Publish(ctx, &pubsub.Message{Data: []byte("hello")})
+	select {
+	case got := <-received:
+		if got != "/sub" {
+			t.Errorf("unexpected delivery %q", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the retried adapter to deliver the event")
+	}
+}
+
+func TestPoolStartsUpdatedAdapterOnceStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logtest.TestLogger(t).Desugar()))
+	defer cancel()
+
+	conn, topic := newTestTopic(ctx, t, "sub")
+	received := make(chan string, 10)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received <- req.URL.Path
+	}))
+	defer sink.Close()
+
+	p := NewPool(http.DefaultClient, converters.NewPubSubConverter(), option.WithGRPCConn(conn))
+	defer p.Close()
+
+	// The previous adapter of the subscription is still stopping.
+	cancelled := make(chan struct{})
+	prev := &runningAdapter{
+		config: testSubscription(sink.URL, "old"),
+		cancel: func() { close(cancelled) },
+		done:   make(chan struct{}),
+	}
+	p.adapters[Key("ns", "ps")] = prev
+
+	p.Sync(ctx, &Config{Subscriptions: map[string]*SubscriptionConfig{
+		Key("ns", "ps"): testSubscription(sink.URL, "sub"),
+	}})
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("the previous adapter wasn't cancelled")
+	}
+
+	topic.Publish(ctx, &pubsub.Message{Data: []byte("hello")})
+	select {
+	case got := <-received:
+		t.Fatalf("the updated adapter delivered %q before the previous one stopped", got)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	close(prev.done)
+	select {
+	case got := <-received:
+		if got != "/sub" {
+			t.Errorf("unexpected delivery %q", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the updated adapter to deliver the event")
+	}
+}
+
+// newTestTopic creates a topic with a subscription of the given ID on a
+// pstest server, and returns a connection to the server.
+func newTestTopic(ctx context.Context, t *testing.T, subscriptionID string) (*grpc.ClientConn, *pubsub.Topic) {
+	t.Helper()
+	srv := pstest.NewServer()
+	t.Cleanup(func() { srv.Close() })
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial test pubsub connection: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	psClient, err := pubsub.NewClient(ctx, testProject, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("failed to create test pubsub client: %v", err)
+	}
+	topic, err := psClient.CreateTopic(ctx, "topic")
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	if _, err := psClient.CreateSubscription(ctx, subscriptionID, pubsub.SubscriptionConfig{Topic: topic}); err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	return conn, topic
+}
+
+func testSubscription(sinkURL, id string) *SubscriptionConfig {
+	return &SubscriptionConfig{
+		Namespace:      "ns",
+		Name:           "ps",
+		ResourceGroup:  "pullsubscriptions.internal.events.cloud.google.com",
+		ProjectID:      testProject,
+		TopicID:        "topic",
+		SubscriptionID: id,
+		SinkURI:        sinkURL + "/" + id,
+		AdapterType:    string(converters.CloudPubSub),
+	}
+}
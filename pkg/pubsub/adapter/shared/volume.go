@@ -0,0 +1,137 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ConfigDir holds the Config loaded from the files of the ConfigMap shards
+// projected into a directory. It watches the directory and reloads the Config
+// whenever it changes.
+type ConfigDir struct {
+	path       string
+	notifyChan chan<- struct{}
+
+	mut    sync.RWMutex
+	config *Config
+}
+
+// NewConfigDir loads the Config from the shard files in the given directory
+// and starts watching it. The notify channel, if not nil, is signaled after
+// every reload. Missing files are empty shards, as the controller only creates
+// the ConfigMap of a shard once a PullSubscription of the shard opts into the
+// shared receive adapter.
+func NewConfigDir(path string, notifyChan chan<- struct{}) (*ConfigDir, error) {
+	d := &ConfigDir{
+		path:       path,
+		notifyChan: notifyChan,
+	}
+	if err := d.sync(); err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.watchWith(watcher); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Load returns the last loaded Config.
+func (d *ConfigDir) Load() *Config {
+	d.mut.RLock()
+	defer d.mut.RUnlock()
+	return d.config
+}
+
+func (d *ConfigDir) watchWith(watcher *fsnotify.Watcher) error {
+	if err := watcher.Add(d.path); err != nil {
+		return err
+	}
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case _, ok := <-watcher.Events:
+				if !ok {
+					// 'Events' channel is closed.
+					return
+				}
+				// The kubelet updates projected volumes by replacing the
+				// symlink of their data directory, so any change of the
+				// directory reloads all the shards.
+				if err := d.sync(); err != nil {
+					log.Printf("error syncing config: %v\n", err)
+				} else if d.notifyChan != nil {
+					d.notifyChan <- struct{}{}
+				}
+			case err, ok := <-watcher.Errors:
+				if ok {
+					log.Printf("watcher error: %v\n", err)
+				}
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (d *ConfigDir) sync() error {
+	files, err := ioutil.ReadDir(d.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config dir: %w", err)
+	}
+	cfg := &Config{}
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name(), ConfigMapPrefix) {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(d.path, f.Name()))
+		if os.IsNotExist(err) {
+			// The shard was removed since the directory was read.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		shard, err := ParseConfig(b)
+		if err != nil {
+			return err
+		}
+		for key, sub := range shard.Subscriptions {
+			if cfg.Subscriptions == nil {
+				cfg.Subscriptions = make(map[string]*SubscriptionConfig)
+			}
+			cfg.Subscriptions[key] = sub
+		}
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	d.config = cfg
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfigDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configtest-*")
+	if err != nil {
+		t.Fatalf("unexpected error from creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ch := make(chan struct{}, 10)
+	d, err := NewConfigDir(dir, ch)
+	if err != nil {
+		t.Fatalf("unexpected error from NewConfigDir: %v", err)
+	}
+	if diff := cmp.Diff(&Config{}, d.Load()); diff != "" {
+		t.Errorf("config of an empty dir (-want,+got): %v", diff)
+	}
+
+	subscription := func(name string) *SubscriptionConfig {
+		return &SubscriptionConfig{
+			Namespace:      "ns1",
+			Name:           name,
+			ResourceGroup:  "storages.events.cloud.google.com",
+			ProjectID:      "project",
+			TopicID:        "topic1",
+			SubscriptionID: name,
+			SinkURI:        "http://sink1.ns1.svc.cluster.local",
+			AdapterType:    "storage",
+			Extensions:     map[string]string{"foo": "bar"},
+		}
+	}
+	shards := map[string]*Config{
+		ConfigMapPrefix + "0": {Subscriptions: map[string]*SubscriptionConfig{Key("ns1", "ps1"): subscription("sub1")}},
+		ConfigMapPrefix + "1": {Subscriptions: map[string]*SubscriptionConfig{Key("ns1", "ps2"): subscription("sub2")}},
+	}
+	for name, shard := range shards {
+		b, err := shard.Bytes()
+		if err != nil {
+			t.Fatalf("unexpected error from Bytes: %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatalf("unexpected error from writing config file: %v", err)
+		}
+	}
+	// Files of the projected volume other than the shards are ignored.
+	if err := ioutil.WriteFile(filepath.Join(dir, "..data"), []byte("not json"), 0644); err != nil {
+		t.Fatalf("unexpected error from writing file: %v", err)
+	}
+
+	want := &Config{
+		Subscriptions: map[string]*SubscriptionConfig{
+			Key("ns1", "ps1"): subscription("sub1"),
+			Key("ns1", "ps2"): subscription("sub2"),
+		},
+	}
+	deadline := time.After(5 * time.Second)
+	for !cmp.Equal(want, d.Load()) {
+		select {
+		case <-ch:
+		case <-deadline:
+			t.Fatalf("Timeout waiting for the updated config (-want,+got): %v", cmp.Diff(want, d.Load()))
+		}
+	}
+}
+
+func TestConfigMapName(t *testing.T) {
+	names := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		name := ConfigMapName(Key("ns", strconv.Itoa(i)))
+		if !strings.HasPrefix(name, ConfigMapPrefix) {
+			t.Fatalf("ConfigMapName got %q, want prefix %q", name, ConfigMapPrefix)
+		}
+		names[name] = true
+	}
+	if len(names) != ConfigMapShards {
+		t.Errorf("subscriptions spread over %d ConfigMaps, want %d", len(names), ConfigMapShards)
+	}
+	if ConfigMapName(Key("ns", "ps")) != ConfigMapName(Key("ns", "ps")) {
+		t.Error("ConfigMapName is not stable")
+	}
+}
+
+func TestParseConfigError(t *testing.T) {
+	if _, err := ParseConfig([]byte("not json")); err == nil {
+		t.Error("ParseConfig got nil error, want error")
+	}
+}
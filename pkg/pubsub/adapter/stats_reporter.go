@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 
 	"go.opencensus.io/stats/view"
 	"knative.dev/pkg/metrics"
@@ -65,6 +66,13 @@ type StatsReporter interface {
 var _ StatsReporter = (*reporter)(nil)
 var emptyContext = context.Background()
 
+// The views are registered once per process, as the shared receive adapter
+// creates a reporter for each of its subscriptions.
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
 // reporter holds cached metric objects to report metrics.
 type reporter struct {
 	name          string
@@ -79,7 +87,10 @@ func NewStatsReporter(name Name, namespace Namespace, resourceGroup ResourceGrou
 		namespace:     string(namespace),
 		resourceGroup: string(resourceGroup),
 	}
-	if err := r.register(); err != nil {
+	registerOnce.Do(func() {
+		registerErr = r.register()
+	})
+	if err := registerErr; err != nil {
 		return nil, fmt.Errorf("failed to register stats: %w", err)
 	}
 	return r, nil
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publisher
+
+import (
+	"context"
+	nethttp "net/http"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/metrics"
+	"github.com/google/knative-gcp/pkg/utils/eventbatch"
+)
+
+// serveBatch publishes the events of a batch request, and responds with the result of each event.
+// Events are published concurrently, except that events with the same ordering key are published
+// one after the other, in the order of the batch.
+func (p *Publisher) serveBatch(ctx context.Context, response nethttp.ResponseWriter, request *nethttp.Request) {
+	batch, err := eventbatch.Read(response, request, maxRequestBodyBytes)
+	if err != nil {
+		statusCode := eventbatch.ReadErrorCode(err)
+		nethttp.Error(response, err.Error(), statusCode)
+		p.reporter.ReportEventCount(ctx, metrics.PublisherReportArgs{
+			EventType:    "_invalid_cloud_event_",
+			ResponseCode: statusCode,
+		})
+		return
+	}
+
+	events, results := eventbatch.Decode(batch)
+	for i, event := range events {
+		if event == nil {
+			p.logger.Debug("Invalid event in batch", zap.Int("index", i), zap.String("error", results[i].Error))
+			p.reporter.ReportEventCount(ctx, metrics.PublisherReportArgs{
+				EventType:    "_invalid_cloud_event_",
+				ResponseCode: nethttp.StatusBadRequest,
+			})
+		}
+	}
+	eventbatch.Send(events, results,
+		func(event *cev2.Event) string {
+			return eventbatch.OrderingKey(event, p.orderingKeyAttribute)
+		},
+		func(event *cev2.Event, result *eventbatch.Result) {
+			result.MessageID, result.Code, result.Error = p.publish(ctx, event)
+		},
+		func(event *cev2.Event, statusCode int) {
+			p.reporter.ReportEventCount(ctx, metrics.PublisherReportArgs{
+				EventType:    event.Type(),
+				ResponseCode: statusCode,
+			})
+		})
+
+	if err := eventbatch.WriteResponse(response, results); err != nil {
+		p.logger.Warn("Failed to write batch response", zap.Error(err))
+	}
+}
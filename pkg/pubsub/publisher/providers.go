@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/clients"
 	"github.com/google/wire"
 	"knative.dev/eventing/pkg/kncloudevents"
@@ -11,10 +12,15 @@ import (
 
 type TopicID string
 
+// OrderingKeyAttribute is the event attribute used as the ordering key of the published messages.
+// Ordering is disabled if empty.
+type OrderingKeyAttribute string
+
 // PublisherSet provides a handler with a real HTTPMessageReceiver and a PubSub client.
 var PublisherSet wire.ProviderSet = wire.NewSet(
 	NewPublisher,
 	clients.NewHTTPMessageReceiver,
+	metrics.NewPublisherReporter,
 	clients.NewPubsubClient,
 	NewPubSubTopic,
 	wire.Bind(new(HttpMessageReceiver), new(*kncloudevents.HTTPMessageReceiver)),
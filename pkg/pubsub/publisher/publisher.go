@@ -18,6 +18,7 @@ package publisher
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	nethttp "net/http"
@@ -28,7 +29,10 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding/transformer"
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
+	"github.com/google/knative-gcp/pkg/utils/dedup"
+	"github.com/google/knative-gcp/pkg/utils/eventbatch"
 
 	"go.uber.org/zap"
 
@@ -42,6 +46,14 @@ import (
 
 const (
 	sinkTimeout = 30 * time.Second
+
+	// maxRequestBodyBytes is the maximum size of a batch request, which is also the maximum size
+	// of a Pub/Sub publish request.
+	maxRequestBodyBytes = 10000000
+
+	// dedupWindow is how long an event sent again is responded with the message ID it was
+	// published with, instead of being published again.
+	dedupWindow = 10 * time.Minute
 )
 
 // PubSubPublisher is an interface to publish events to a pubsub topic.
@@ -54,24 +66,40 @@ type HttpMessageReceiver interface {
 	StartListen(ctx context.Context, handler nethttp.Handler) error
 }
 
+// publishResponse is the body of the response to an accepted event.
+type publishResponse struct {
+	MessageID string `json:"messageId"`
+}
+
 // Publisher receives HTTP events and sends them to Pubsub.
 type Publisher struct {
 	// inbound is an HTTP server to receive events.
 	inbound HttpMessageReceiver
 	// topic is the topic to publish events to.
 	topic *pubsub.Topic
-
-	logger *zap.Logger
+	// orderingKeyAttribute is the event attribute used as the ordering key of the messages, if
+	// not empty.
+	orderingKeyAttribute string
+	// dedupStore holds the message IDs of the recently published events, so that an event sent
+	// again, e.g. when a producer retries after a failed response, is not published twice.
+	dedupStore dedup.Store
+
+	logger   *zap.Logger
+	reporter *metrics.PublisherReporter
 	// AuthType is the authentication configuration mode the Pod uses.
 	authType authcheck.AuthType
 }
 
 // NewPublisher creates a new publisher.
-func NewPublisher(ctx context.Context, inbound HttpMessageReceiver, topic *pubsub.Topic, authType authcheck.AuthType) *Publisher {
+func NewPublisher(ctx context.Context, inbound HttpMessageReceiver, topic *pubsub.Topic, authType authcheck.AuthType, reporter *metrics.PublisherReporter, orderingKeyAttribute OrderingKeyAttribute) *Publisher {
+	topic.EnableMessageOrdering = orderingKeyAttribute != ""
 	return &Publisher{
-		inbound: inbound,
-		topic:   topic,
-		logger:  logging.FromContext(ctx),
+		inbound:              inbound,
+		topic:                topic,
+		orderingKeyAttribute: string(orderingKeyAttribute),
+		dedupStore:           dedup.NewMemoryStore(dedup.DefaultMemoryStoreSize),
+		logger:               logging.FromContext(ctx),
+		reporter:             reporter,
 		// AuthType is the authentication configuration mode the Pod uses.
 		authType: authType,
 	}
@@ -84,8 +112,8 @@ func (p *Publisher) Start(ctx context.Context) error {
 
 // ServeHTTP implements net/http Publisher interface method.
 // 1. Performs basic validation of the request.
-// 2. Converts the request to an event.
-// 3. Sends the event to pubsub.
+// 2. Converts the request to an event, or to events if it is a batch request.
+// 3. Sends the events to pubsub, and responds with their message IDs.
 func (p *Publisher) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
 	ctx := request.Context()
 	p.logger.Debug("Serving http", zap.Any("headers", request.Header))
@@ -94,37 +122,112 @@ func (p *Publisher) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, sinkTimeout)
+	defer cancel()
+
+	if eventbatch.IsRequest(request) {
+		p.serveBatch(ctx, response, request)
+		return
+	}
+
 	event, err := p.toEvent(request)
 	if err != nil {
 		nethttp.Error(response, err.Error(), nethttp.StatusBadRequest)
+		p.reporter.ReportEventCount(ctx, metrics.PublisherReportArgs{
+			EventType:    "_invalid_cloud_event_",
+			ResponseCode: nethttp.StatusBadRequest,
+		})
 		return
 	}
 
-	// Optimistically set status code to StatusAccepted. It will be updated if there is an error.
+	messageID, statusCode, errMsg := p.publish(ctx, event)
+	if statusCode != nethttp.StatusAccepted {
+		nethttp.Error(response, errMsg, statusCode)
+		return
+	}
 	// According to the data plane spec (https://github.com/knative/eventing/blob/master/docs/spec/data-plane.md), a
 	// non-callable Sink (which Publisher is) MUST respond with 202 Accepted if the request is accepted.
-	statusCode := nethttp.StatusAccepted
-	ctx, cancel := context.WithTimeout(ctx, sinkTimeout)
-	defer cancel()
-	if res := p.Publish(ctx, event); !cev2.IsACK(res) {
-		msg := fmt.Sprintf("Error publishing to PubSub. event: %+v, err: %v.", event, res)
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(statusCode)
+	if err := json.NewEncoder(response).Encode(publishResponse{MessageID: messageID}); err != nil {
+		p.logger.Warn("Failed to write response", zap.Error(err))
+	}
+}
+
+// publish publishes the event unless it was published within dedupWindow, and reports its
+// metrics. It returns the message ID of the event, its status code, and an error message if the
+// event is not accepted.
+func (p *Publisher) publish(ctx context.Context, event *cev2.Event) (string, int, string) {
+	args := metrics.PublisherReportArgs{EventType: event.Type(), ResponseCode: nethttp.StatusAccepted}
+	defer func() { p.reporter.ReportEventCount(ctx, args) }()
+
+	key := dedup.Key(p.topic.ID(), event.Source(), event.ID())
+	// The event is pending in the store for as long as it can take to publish it.
+	entry, err := p.dedupStore.Add(ctx, key, sinkTimeout)
+	if err != nil {
+		// Events are not deduplicated if the store fails, so that they are never lost.
+		p.logger.Warn("Failed to add event to dedup store", zap.String("id", event.ID()), zap.Error(err))
+		key = ""
+	}
+	switch entry.State {
+	case dedup.Published:
+		p.logger.Debug("Event already published", zap.String("source", event.Source()), zap.String("id", event.ID()))
+		return entry.MessageID, args.ResponseCode, ""
+	case dedup.Pending:
+		args.ResponseCode = nethttp.StatusServiceUnavailable
+		return "", args.ResponseCode, "Duplicate of an event being published"
+	}
+
+	start := time.Now()
+	id, err := p.Publish(ctx, event)
+	if err != nil {
+		args.ResponseCode = nethttp.StatusInternalServerError
+	}
+	p.reporter.ReportPublishTime(ctx, args, time.Since(start))
+	if key != "" {
+		p.settleDuplicate(key, entry.Token, event, id, err)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Error publishing to PubSub. event: %+v, err: %v.", event, err)
 		p.logger.Error(msg)
-		statusCode = nethttp.StatusInternalServerError
-		nethttp.Error(response, msg, statusCode)
+		return "", args.ResponseCode, msg
+	}
+	return id, args.ResponseCode, ""
+}
+
+// settleDuplicate marks the event as published in the dedup store, or removes it if it failed to
+// be published, so that it is not considered a duplicate when it is sent again. token is the
+// token of the Added entry.
+func (p *Publisher) settleDuplicate(key, token string, event *cev2.Event, messageID string, publishErr error) {
+	// The store is updated even if the request context is done, which may be why the publish
+	// failed.
+	ctx := context.Background()
+	if publishErr != nil {
+		if err := p.dedupStore.Remove(ctx, key, token); err != nil {
+			p.logger.Warn("Failed to remove event from dedup store", zap.String("id", event.ID()), zap.Error(err))
+		}
 		return
 	}
-	response.WriteHeader(statusCode)
+	if err := p.dedupStore.MarkPublished(ctx, key, messageID, dedupWindow); err != nil {
+		p.logger.Warn("Failed to mark event as published in dedup store", zap.String("id", event.ID()), zap.Error(err))
+	}
 }
 
-// Publish publishes an incoming event to a pubsub topic.
-func (p *Publisher) Publish(ctx context.Context, event *cev2.Event) protocol.Result {
+// Publish publishes an incoming event to a pubsub topic, and returns its message ID.
+func (p *Publisher) Publish(ctx context.Context, event *cev2.Event) (string, error) {
 	dt := extensions.FromSpanContext(trace.FromContext(ctx).SpanContext())
 	msg := new(pubsub.Message)
 	if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(event), msg, dt.WriteTransformer()); err != nil {
-		return err
+		return "", err
+	}
+	msg.OrderingKey = eventbatch.OrderingKey(event, p.orderingKeyAttribute)
+	id, err := p.topic.Publish(ctx, msg).Get(ctx)
+	if err != nil && msg.OrderingKey != "" {
+		// A failed publish pauses the ordering key so that later messages are not published out
+		// of order. The event is rejected and will be sent again by the producer, so resume the key.
+		p.topic.ResumePublish(msg.OrderingKey)
 	}
-	_, err := p.topic.Publish(ctx, msg).Get(ctx)
-	return err
+	return id, err
 }
 
 // toEvent converts an http request to an event.
@@ -0,0 +1,216 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"knative.dev/pkg/logging"
+	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
+
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+	"github.com/google/knative-gcp/pkg/utils/eventbatch"
+)
+
+const (
+	testProject = "test-project"
+	testTopic   = "test-topic"
+)
+
+func testPublisher(t *testing.T, orderingKeyAttribute string) (*Publisher, *pstest.Server) {
+	t.Helper()
+	ctx := logging.WithLogger(context.Background(), logtest.TestLogger(t))
+	srv := pstest.NewServer()
+	t.Cleanup(func() { srv.Close() })
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	c, err := pubsub.NewClient(ctx, testProject, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(topic.Stop)
+
+	reportertest.ResetPublisherMetrics()
+	reporter, err := metrics.NewPublisherReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPublisher(ctx, nil, topic, "", reporter, OrderingKeyAttribute(orderingKeyAttribute)), srv
+}
+
+func testEvent(id, subject string) map[string]interface{} {
+	e := map[string]interface{}{
+		"specversion": "1.0",
+		"id":          id,
+		"source":      "test-source",
+		"type":        "test-type",
+		"data":        map[string]string{"key": "value"},
+	}
+	if subject != "" {
+		e["subject"] = subject
+	}
+	return e
+}
+
+func serve(t *testing.T, p *Publisher, contentType string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(nethttp.MethodPost, "/", bytes.NewReader(b))
+	req.Header.Set("Content-Type", contentType)
+	res := httptest.NewRecorder()
+	p.ServeHTTP(res, req)
+	return res
+}
+
+func TestPublishEvent(t *testing.T) {
+	p, srv := testPublisher(t, "")
+
+	var ids []string
+	// The event is sent twice, as by a producer that retries, and only published once.
+	for i := 0; i < 2; i++ {
+		res := serve(t, p, cev2.ApplicationCloudEventsJSON, testEvent("1", ""))
+		if res.Code != nethttp.StatusAccepted {
+			t.Fatalf("Unexpected status code %d: %s", res.Code, res.Body.String())
+		}
+		var body publishResponse
+		if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, body.MessageID)
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("Expected 1 published message, got %d", len(msgs))
+	}
+	if diff := cmp.Diff([]string{msgs[0].ID, msgs[0].ID}, ids); diff != "" {
+		t.Errorf("Unexpected message IDs (-want, +got): %s", diff)
+	}
+	if got := msgs[0].Attributes["ce-id"]; got != "1" {
+		t.Errorf("Unexpected ce-id attribute %q", got)
+	}
+	metricstest.CheckCountData(t, "event_count", map[string]string{
+		metricskey.LabelEventType:         "custom",
+		metricskey.LabelResponseCode:      "202",
+		metricskey.LabelResponseCodeClass: "2xx",
+		metricskey.ContainerName:          "testcontainer",
+		metricskey.PodName:                "testpod",
+	}, 2)
+}
+
+func TestPublishInvalidRequest(t *testing.T) {
+	p, srv := testPublisher(t, "")
+
+	req := httptest.NewRequest(nethttp.MethodGet, "/", nil)
+	res := httptest.NewRecorder()
+	p.ServeHTTP(res, req)
+	if res.Code != nethttp.StatusMethodNotAllowed {
+		t.Errorf("Unexpected status code %d", res.Code)
+	}
+
+	res = serve(t, p, "application/json", map[string]string{"key": "value"})
+	if res.Code != nethttp.StatusBadRequest {
+		t.Errorf("Unexpected status code %d", res.Code)
+	}
+	if len(srv.Messages()) != 0 {
+		t.Errorf("Expected no published message, got %d", len(srv.Messages()))
+	}
+}
+
+func TestPublishBatch(t *testing.T) {
+	p, srv := testPublisher(t, "subject")
+
+	invalid := testEvent("4", "")
+	delete(invalid, "source")
+	res := serve(t, p, cev2.ApplicationCloudEventsBatchJSON, []interface{}{
+		testEvent("1", "a"), testEvent("2", "a"), testEvent("3", "b"), invalid,
+	})
+	if res.Code != nethttp.StatusMultiStatus {
+		t.Fatalf("Unexpected status code %d: %s", res.Code, res.Body.String())
+	}
+	var got eventbatch.Response
+	if err := json.Unmarshal(res.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := make(map[string]*pstest.Message)
+	for _, m := range srv.Messages() {
+		msgs[m.Attributes["ce-id"]] = m
+	}
+	want := eventbatch.Response{Accepted: 3, Rejected: 1}
+	for _, id := range []string{"1", "2", "3"} {
+		m, ok := msgs[id]
+		if !ok {
+			t.Fatalf("Event %s was not published", id)
+		}
+		want.Results = append(want.Results, eventbatch.Result{ID: id, Source: "test-source", MessageID: m.ID, Code: nethttp.StatusAccepted})
+	}
+	want.Results = append(want.Results, eventbatch.Result{Code: nethttp.StatusBadRequest})
+	// The error of the invalid event comes from the CloudEvents SDK.
+	got.Results[3].Error = ""
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected response (-want, +got): %s", diff)
+	}
+
+	wantKeys := map[string]string{"1": "a", "2": "a", "3": "b"}
+	for id, key := range wantKeys {
+		if msgs[id].OrderingKey != key {
+			t.Errorf("Unexpected ordering key of event %s, got %q, want %q", id, msgs[id].OrderingKey, key)
+		}
+	}
+}
+
+func TestPublishFailure(t *testing.T) {
+	p, srv := testPublisher(t, "")
+	// Publishing fails once the topic is deleted.
+	if err := p.topic.Delete(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	res := serve(t, p, cev2.ApplicationCloudEventsJSON, testEvent("1", ""))
+	if res.Code != nethttp.StatusInternalServerError {
+		t.Errorf("Unexpected status code %d: %s", res.Code, res.Body.String())
+	}
+	if len(srv.Messages()) != 0 {
+		t.Errorf("Expected no published message, got %d", len(srv.Messages()))
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/reconciler/celltenant"
 
@@ -55,9 +56,10 @@ const (
 	testNS     = "testnamespace"
 	brokerName = "test-broker"
 
-	testProject = "test-project-id"
-	testUID     = "abc123"
-	systemNS    = "knative-testing"
+	testProject   = "test-project-id"
+	tenantProject = "tenant-project-id"
+	testUID       = "abc123"
+	systemNS      = "knative-testing"
 
 	brokerFinalizerName = "brokers.eventing.knative.dev"
 	testClusterRegion   = "us-east1"
@@ -153,6 +155,35 @@ func TestAllCases(t *testing.T) {
 			NoTopicsExist(),
 			NoSubscriptionsExist(),
 		},
+	}, {
+		Name: "Broker is being deleted, topic and sub exist in the project recorded in its status",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithInitBrokerConditions,
+				WithBrokerProjectID(testProject),
+				WithBrokerDeletionTimestamp,
+				WithBrokerSetDefaults,
+			),
+			NewNamespace(testNS,
+				WithNamespaceAnnotations(map[string]string{brokerv1beta1.ProjectAnnotation: tenantProject})),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerFinalizedEvent,
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub("cre-bkr_testnamespace_test-broker_abc123", "cre-bkr_testnamespace_test-broker_abc123"),
+			},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			NoTopicsExist(),
+			NoSubscriptionsExist(),
+		},
 	}, {
 		Name: "Create broker with ready brokercell, broker is created",
 		Key:  testKey,
@@ -172,12 +203,143 @@ func TestAllCases(t *testing.T) {
 				WithBrokerUID(testUID),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
+				WithBrokerProjectID(testProject),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Create ordered broker, decouple subscription enables message ordering",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerOrderingKey("subject"),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerOrderingKey("subject"),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerProjectID(testProject),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionHasMessageOrdering("cre-bkr_testnamespace_test-broker_abc123", true),
+			SubscriptionHasRetryPolicy("cre-bkr_testnamespace_test-broker_abc123", &pubsub.RetryPolicy{
+				MinimumBackoff: 5 * time.Second,
+				MaximumBackoff: 5 * time.Second,
+			}),
+		},
+	}, {
+		Name: "Create broker in a namespace annotated with a project, topic and subscription are created in the project",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithBrokerCellSetDefaults),
+			NewNamespace(testNS,
+				WithNamespaceAnnotations(map[string]string{brokerv1beta1.ProjectAnnotation: tenantProject})),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerProjectID(tenantProject),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExistsInProject("cre-bkr_testnamespace_test-broker_abc123", tenantProject),
+			SubscriptionExistsInProject("cre-bkr_testnamespace_test-broker_abc123", tenantProject),
+			NoTopicsExist(),
+		},
+	}, {
+		Name: "Create broker with message retention, retention subscription is created",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerMessageRetention("24h"),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerMessageRetention("24h"),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerProjectID(testProject),
 				WithBrokerSetDefaults,
 			),
 		}},
 		WantEvents: []string{
 			brokerFinalizerUpdatedEvent,
 			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr-rtn_testnamespace_test-broker_abc123"`),
 			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
 			brokerReconciledEvent,
 		},
@@ -190,6 +352,8 @@ func TestAllCases(t *testing.T) {
 		PostConditions: []func(*testing.T, *TableRow){
 			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
 			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr-rtn_testnamespace_test-broker_abc123"),
+			SubscriptionRetainsAckedMessages("cre-bkr-rtn_testnamespace_test-broker_abc123", 24*time.Hour),
 		},
 	}, {
 		Name: "Create broker with unready brokercell, broker is created",
@@ -213,6 +377,7 @@ func TestAllCases(t *testing.T) {
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
+				WithBrokerProjectID(testProject),
 				WithBrokerSetDefaults,
 			),
 		}},
@@ -287,6 +452,7 @@ func TestAllCases(t *testing.T) {
 					WithBrokerDeliverySpec(brokerDeliverySpec),
 					WithBrokerReadyURI(brokerAddress),
 					WithBrokerBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
+					WithBrokerProjectID(testProject),
 					WithBrokerSetDefaults,
 				),
 			},
@@ -333,6 +499,7 @@ func TestAllCases(t *testing.T) {
 				WithBrokerUID(testUID),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
+				WithBrokerProjectID(testProject),
 				WithBrokerSetDefaults,
 			),
 		}},
@@ -378,6 +545,7 @@ func TestAllCases(t *testing.T) {
 				WithBrokerUID(testUID),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
+				WithBrokerProjectID(testProject),
 				WithBrokerSetDefaults,
 			),
 		}},
@@ -419,6 +587,7 @@ func TestAllCases(t *testing.T) {
 				WithBrokerUID(testUID),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
 				WithBrokerReadyURI(brokerAddress),
+				WithBrokerProjectID(testProject),
 				WithBrokerSetDefaults,
 				WithBrokerTopicUnknown("FinalizeTopicPubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
 				WithBrokerSubscriptionUnknown("FinalizeSubscriptionPubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
@@ -466,6 +635,7 @@ func TestAllCases(t *testing.T) {
 		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
 		// will create one in demand
 		testPSClient := psclient
+		celltenant.CreatePubsubClientFn = GetTestClientCreateFunc(srv.Addr)
 		if maxTime, ok := testData["maxPSClientCreateTime"]; ok {
 			// Overwrite the createPubsubClientFn to one that failed when called more than maxTime times.
 			// maxTime=0 is used to inject error
@@ -481,6 +651,7 @@ func TestAllCases(t *testing.T) {
 				BrokerCellLister:   listers.GetBrokerCellLister(),
 				ProjectID:          testProject,
 				PubsubClient:       testPSClient,
+				Projects:           celltenant.NewNamespaceProjects(listers.GetNamespaceLister()),
 				DataresidencyStore: drStore,
 				ClusterRegion:      testClusterRegion,
 			},
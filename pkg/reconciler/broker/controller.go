@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/google/knative-gcp/pkg/logging"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
@@ -91,6 +92,7 @@ func newController(ctx context.Context, cmw configmap.Watcher, brds *brokerdeliv
 			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
 			BrokerCellLister:   bcInformer.Lister(),
 			PubsubClient:       client,
+			Projects:           celltenant.NewNamespaceProjects(namespaceinformer.Get(ctx).Lister()),
 			DataresidencyStore: drs,
 		},
 	}
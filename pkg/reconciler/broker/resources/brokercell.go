@@ -18,10 +18,14 @@ package resources
 
 import (
 	"github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"knative.dev/pkg/system"
 
 	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 )
 
 // Right now we only support one brokercell in the system namespace in the cluster.
@@ -40,3 +44,11 @@ func CreateBrokerCell(_ *v1beta1.Broker) *inteventsv1alpha1.BrokerCell {
 		},
 	}
 }
+
+// ListBrokerCellPods lists the data plane pods of the BrokerCell. The pods report their state to the
+// control plane in their annotations, such as their open circuit breakers and replay progress.
+func ListBrokerCellPods(podLister corev1listers.PodLister) ([]*corev1.Pod, error) {
+	// TODO(#866) Get brokercell based on the label (or annotation) on the broker.
+	selector := labels.SelectorFromSet(brokercellresources.CommonLabels(DefaultBrokerCellName))
+	return podLister.Pods(system.Namespace()).List(selector)
+}
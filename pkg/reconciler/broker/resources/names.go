@@ -18,6 +18,7 @@ package resources
 
 import (
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	"github.com/google/knative-gcp/pkg/utils/naming"
 )
 
@@ -47,6 +48,22 @@ func GenerateDecouplingSubscriptionName(b *brokerv1beta1.Broker) string {
 	return naming.TruncatedPubsubResourceName("cre-bkr", b.Namespace, b.Name, b.UID)
 }
 
+// GenerateRetentionSubscriptionName generates a deterministic subscription
+// name for the subscription retaining the events of a Broker for replay. If the
+// subscription name would be longer than allowed by PubSub, the Broker name is
+// truncated to fit.
+func GenerateRetentionSubscriptionName(b *brokerv1beta1.Broker) string {
+	return naming.TruncatedPubsubResourceName("cre-bkr-rtn", b.Namespace, b.Name, b.UID)
+}
+
+// GenerateReplaySubscriptionName generates a deterministic subscription name
+// for the temporary subscription a Replay is replayed from. The snapshot the
+// subscription is seeked to has the same name. If the subscription name would
+// be longer than allowed by PubSub, the Replay name is truncated to fit.
+func GenerateReplaySubscriptionName(rp *eventsv1alpha1.Replay) string {
+	return naming.TruncatedPubsubResourceName("cre-rpl", rp.Namespace, rp.Name, rp.UID)
+}
+
 // GenerateRetryTopicName generates a deterministic topic name for a Trigger.
 // If the topic name would be longer than allowed by PubSub, the Trigger name is
 // truncated to fit.
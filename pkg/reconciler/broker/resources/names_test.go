@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	"github.com/google/knative-gcp/pkg/utils/naming"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -118,6 +119,64 @@ func TestGenerateDecouplingSubscriptionName(t *testing.T) {
 	}
 }
 
+func TestGenerateRetentionSubscriptionName(t *testing.T) {
+	testCases := []struct {
+		ns   string
+		n    string
+		uid  string
+		want string
+	}{{
+		ns:   "default",
+		n:    "default",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-bkr-rtn_default_default_%s", testUID),
+	}, {
+		ns:   maxNamespace,
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-bkr-rtn_%s_%s_%s", maxNamespace, strings.Repeat("n", truncatedNameMaxForBkrTgr-4), testUID),
+	}}
+
+	for _, tc := range testCases {
+		got := GenerateRetentionSubscriptionName(broker(tc.ns, tc.n, tc.uid))
+		if len(got) > naming.PubsubMax {
+			t.Errorf("name length %d is greater than %d", len(got), naming.PubsubMax)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected (-want, +got) = %v", diff)
+		}
+	}
+}
+
+func TestGenerateReplaySubscriptionName(t *testing.T) {
+	testCases := []struct {
+		ns   string
+		n    string
+		uid  string
+		want string
+	}{{
+		ns:   "default",
+		n:    "default",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-rpl_default_default_%s", testUID),
+	}, {
+		ns:   maxNamespace,
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-rpl_%s_%s_%s", maxNamespace, strings.Repeat("n", truncatedNameMaxForBkrTgr), testUID),
+	}}
+
+	for _, tc := range testCases {
+		got := GenerateReplaySubscriptionName(replay(tc.ns, tc.n, tc.uid))
+		if len(got) > naming.PubsubMax {
+			t.Errorf("name length %d is greater than %d", len(got), naming.PubsubMax)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected (-want, +got) = %v", diff)
+		}
+	}
+}
+
 func TestGenerateRetryTopicName(t *testing.T) {
 	testCases := []struct {
 		ns   string
@@ -206,6 +265,16 @@ func broker(ns, n, uid string) *brokerv1beta1.Broker {
 	}
 }
 
+func replay(ns, n, uid string) *eventsv1alpha1.Replay {
+	return &eventsv1alpha1.Replay{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      n,
+			UID:       types.UID(uid),
+		},
+	}
+}
+
 func trigger(ns, n, uid string) *brokerv1beta1.Trigger {
 	return &brokerv1beta1.Trigger{
 		ObjectMeta: metav1.ObjectMeta{
@@ -19,16 +19,24 @@ package brokercell
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 
 	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/rickb777/date/period"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/apis/eventing"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
@@ -40,7 +48,8 @@ import (
 )
 
 const (
-	configFailed = "BrokerTargetsConfigFailed"
+	configFailed                = "BrokerTargetsConfigFailed"
+	deadLetterSinkResolveFailed = "DeadLetterSinkResolveFailed"
 )
 
 func (r *Reconciler) reconcileConfig(ctx context.Context, bc *intv1alpha1.BrokerCell) error {
@@ -94,13 +103,278 @@ func (r *Reconciler) addBrokersAndTriggersToTargets(ctx context.Context, bc *int
 			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list triggers for broker %v: %v", broker.Name, err)
 			return err
 		}
-		addBrokerAndTriggersToConfig(ctx, broker, triggers, targets)
+		replays, err := r.replayLister.Replays(broker.Namespace).List(labels.Everything())
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list replays", zap.String("Broker", broker.Name), zap.Error(err))
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list replays for broker %v: %v", broker.Name, err)
+			return err
+		}
+		schemas, err := r.eventSchemaLister.EventSchemas(broker.Namespace).List(labels.Everything())
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list event schemas", zap.String("Broker", broker.Name), zap.Error(err))
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list event schemas for broker %v: %v", broker.Name, err)
+			return err
+		}
+		deadLetterAddress, err := r.deadLetterAddress(ctx, broker)
+		if err != nil {
+			// The other Brokers are still configured. The Triggers of the Broker report the
+			// failure in their DeadLetterSinkResolved condition, and their events are redelivered
+			// by Pub/Sub until the sink resolves, see setDeliveryPolicy.
+			logging.FromContext(ctx).Warn("Failed to resolve the Broker's dead letter sink", zap.String("namespace", broker.Namespace), zap.String("broker", broker.Name), zap.Error(err))
+			r.Recorder.Eventf(bc, corev1.EventTypeWarning, deadLetterSinkResolveFailed, "Failed to resolve the dead letter sink of Broker %s/%s: %v", broker.Namespace, broker.Name, err)
+		}
+		projectID, err := r.tenantProject(broker.Namespace, broker.Status.ProjectID())
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get the project of the broker", zap.String("Broker", broker.Name), zap.Error(err))
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to get the project of broker %v: %v", broker.Name, err)
+			return err
+		}
+		addBrokerAndTriggersToConfig(ctx, broker, triggers, brokerSettings{
+			replays:           replaysToConfig(replays),
+			schemas:           eventSchemasToConfig(broker, schemas),
+			ingressAuth:       ingressAuthToConfig(bc, broker),
+			quota:             quotaToConfig(broker),
+			namespaceQuota:    namespaceQuotaToConfig(bc, broker.Namespace),
+			deadLetterAddress: deadLetterAddress,
+			projectID:         projectID,
+		}, targets)
 	}
 	return nil
 }
 
+// eventSchemasToConfig converts the EventSchemas that apply to the Broker to their targets config
+// representation, keyed by event type. The schema of the Broker takes precedence over the schema
+// of its namespace for the same type. If several schemas of the same precedence are defined for a
+// type, the first by name is used so that the config is stable across reconciliations.
+func eventSchemasToConfig(b *brokerv1beta1.Broker, schemas []*brokerv1beta1.EventSchema) map[string]*config.EventSchema {
+	byType := make(map[string]*brokerv1beta1.EventSchema)
+	for _, s := range schemas {
+		if !s.AppliesTo(b) {
+			continue
+		}
+		if current, ok := byType[s.Spec.Type]; ok && !takesPrecedence(s, current) {
+			continue
+		}
+		byType[s.Spec.Type] = s
+	}
+	if len(byType) == 0 {
+		return nil
+	}
+	out := make(map[string]*config.EventSchema, len(byType))
+	for eventType, s := range byType {
+		schema := &config.EventSchema{Name: s.Name}
+		if s.Spec.JSONSchema != nil {
+			schema.JsonSchema = s.Spec.JSONSchema.Raw
+		}
+		if s.Spec.Proto != nil {
+			schema.FileDescriptorSet = s.Spec.Proto.FileDescriptorSet
+			schema.Message = s.Spec.Proto.Message
+		}
+		out[eventType] = schema
+	}
+	return out
+}
+
+// takesPrecedence returns true if the EventSchema s takes precedence over other, for the same type.
+func takesPrecedence(s, other *brokerv1beta1.EventSchema) bool {
+	if (s.Spec.Broker != "") != (other.Spec.Broker != "") {
+		return s.Spec.Broker != ""
+	}
+	return s.Name < other.Name
+}
+
+// schemaEnforcementToConfig converts the schema enforcement of the Broker to its targets config
+// representation.
+func schemaEnforcementToConfig(b *brokerv1beta1.Broker) config.SchemaEnforcement {
+	switch b.GetSchemaEnforcement() {
+	case brokerv1beta1.SchemaEnforcementWarn:
+		return config.SchemaEnforcement_SCHEMA_ENFORCEMENT_WARN
+	case brokerv1beta1.SchemaEnforcementReject:
+		return config.SchemaEnforcement_SCHEMA_ENFORCEMENT_REJECT
+	default:
+		return config.SchemaEnforcement_SCHEMA_ENFORCEMENT_OFF
+	}
+}
+
+// replaysToConfig converts the Replays that are in progress to their targets config
+// representation, keyed by the name of their Trigger. Replays whose subscription has not been
+// seeked yet, or that are done or being deleted, are left out.
+func replaysToConfig(replays []*eventsv1alpha1.Replay) map[string]*config.Replay {
+	out := make(map[string]*config.Replay)
+	for _, r := range replays {
+		if !r.Status.IsSeeked() || r.Status.IsDone() || r.Status.EndTime == nil || !r.DeletionTimestamp.IsZero() {
+			continue
+		}
+		out[r.Spec.Trigger] = &config.Replay{
+			Id:           string(r.UID),
+			Subscription: r.Status.SubscriptionID,
+			StartTime:    timestamppb.New(r.Spec.StartTime.Time),
+			EndTime:      timestamppb.New(r.Status.EndTime.Time),
+		}
+	}
+	return out
+}
+
+// ingressAuthToConfig returns how the ingress authenticates the requests sent to the Broker, or
+// nil if any request is accepted. The annotations of the Broker override the ingress auth policy
+// of the BrokerCell.
+func ingressAuthToConfig(bc *intv1alpha1.BrokerCell, b *brokerv1beta1.Broker) *config.IngressAuth {
+	var policy intv1alpha1.IngressAuthPolicy
+	if bc.Spec.IngressAuth != nil {
+		policy = *bc.Spec.IngressAuth
+	}
+	mode := string(policy.Mode)
+	if m := b.GetIngressAuth(); m != "" {
+		mode = m
+	}
+	if mode != brokerv1beta1.IngressAuthRequired {
+		return nil
+	}
+	audience := b.GetIngressAudience()
+	if audience == "" {
+		audience = b.Status.Address.URL.String()
+	}
+	principals := policy.AllowedPrincipals
+	if p := b.GetIngressAllowedPrincipals(); p != nil {
+		principals = p
+	}
+	return &config.IngressAuth{
+		Audience:          audience,
+		AllowedPrincipals: principals,
+	}
+}
+
+// quotaToConfig returns the quota of the Broker set by its annotations, or nil if the Broker is
+// not limited.
+func quotaToConfig(b *brokerv1beta1.Broker) *config.Quota {
+	events, bytes := b.GetIngressQuota()
+	if events == 0 && bytes == 0 {
+		return nil
+	}
+	return &config.Quota{EventsPerSecond: events, BytesPerSecond: bytes}
+}
+
+// namespaceQuotaToConfig returns the quota the BrokerCell sets on the namespace, or nil if the
+// namespace is not limited.
+func namespaceQuotaToConfig(bc *intv1alpha1.BrokerCell, ns string) *config.Quota {
+	q := bc.Spec.IngressQuotas.ForNamespace(ns)
+	if q == nil || (q.EventsPerSecond == 0 && q.Bytes() == 0) {
+		return nil
+	}
+	return &config.Quota{EventsPerSecond: q.EventsPerSecond, BytesPerSecond: q.Bytes()}
+}
+
+// tenantProject returns the GCP project of the Pub/Sub resources of a cell tenant in the namespace,
+// or an empty string if they are in the project of the BrokerCell. statusProjectID is the project
+// recorded in the status of the tenant when its resources were created, if any; otherwise the
+// project annotated on the namespace is used, as the tenant reconciler does.
+func (r *Reconciler) tenantProject(namespace, statusProjectID string) (string, error) {
+	if r.namespaceLister == nil {
+		return "", nil
+	}
+	projectID := statusProjectID
+	if projectID == "" {
+		ns, err := r.namespaceLister.Get(namespace)
+		if apierrs.IsNotFound(err) {
+			// The namespace is being deleted along with its tenants.
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		projectID = brokerv1beta1.GetNamespaceProject(ns)
+	}
+	if projectID == r.projectID {
+		return "", nil
+	}
+	return projectID, nil
+}
+
+// deadLetterAddress resolves the URI of the Broker's dead letter sink, if it is not a Pub/Sub
+// topic. Pub/Sub topic dead letter sinks are handled by the retry subscriptions, so the retry
+// pool does not need their address.
+func (r *Reconciler) deadLetterAddress(ctx context.Context, b *brokerv1beta1.Broker) (string, error) {
+	if b.Spec.Delivery == nil || b.Spec.Delivery.DeadLetterSink == nil || brokerv1beta1.IsPubSubDeadLetterSink(b.Spec.Delivery.DeadLetterSink) {
+		return "", nil
+	}
+	dls := b.Spec.Delivery.DeadLetterSink.DeepCopy()
+	if dls.Ref == nil {
+		return dls.URI.String(), nil
+	}
+	if dls.Ref.Namespace == "" {
+		dls.Ref.Namespace = b.Namespace
+	}
+	uri, err := r.uriResolver.URIFromDestinationV1(ctx, *dls, b)
+	if err != nil {
+		return "", err
+	}
+	return uri.String(), nil
+}
+
+// setDeliveryPolicy sets the retry and dead letter policy of the delivery spec on the target, so
+// that the retry pool can apply them. deadLetterAddress is the resolved address of the dead
+// letter sink, if it is not a Pub/Sub topic. If it couldn't be resolved, no policy is set: the
+// retry pool then leaves the failed events to the retry subscription, which redelivers them
+// until the sink resolves, rather than dropping them once their attempts are exhausted.
+func setDeliveryPolicy(ctx context.Context, target *config.Target, spec *eventingduckv1beta1.DeliverySpec, deadLetterAddress string) {
+	target.DeadLetterAddress = deadLetterAddress
+	if spec == nil {
+		return
+	}
+	if spec.DeadLetterSink != nil && !brokerv1beta1.IsPubSubDeadLetterSink(spec.DeadLetterSink) && deadLetterAddress == "" {
+		return
+	}
+	if spec.Retry != nil {
+		target.MaxDeliveryAttempts = *spec.Retry
+	}
+	if brokerv1beta1.IsPubSubDeadLetterSink(spec.DeadLetterSink) {
+		target.DeadLetterTopic = spec.DeadLetterSink.URI.Host
+	}
+	if spec.BackoffDelay == nil {
+		return
+	}
+	p, err := period.Parse(*spec.BackoffDelay)
+	if err != nil {
+		// Leave the backoff to the retry subscription.
+		logging.FromContext(ctx).Error("Unable to parse DeliverySpec.BackoffDelay",
+			zap.Error(err), zap.Stringp("backoffDelay", spec.BackoffDelay))
+		return
+	}
+	delay, _ := p.Duration()
+	target.BackoffDelay = durationpb.New(delay)
+	if spec.BackoffPolicy != nil && *spec.BackoffPolicy == eventingduckv1beta1.BackoffPolicyLinear {
+		target.BackoffPolicy = config.BackoffPolicy_LINEAR
+	} else {
+		target.BackoffPolicy = config.BackoffPolicy_EXPONENTIAL
+	}
+}
+
+// brokerSettings holds the settings of the targets config entry of a Broker that are not read
+// from the Broker and its Triggers.
+type brokerSettings struct {
+	// replays are the replays in progress, keyed by the name of their Trigger.
+	replays map[string]*config.Replay
+	// schemas are the event schemas of the Broker, keyed by event type.
+	schemas map[string]*config.EventSchema
+	// ingressAuth is how the ingress authenticates the requests sent to the Broker, or nil if any
+	// request is accepted.
+	ingressAuth *config.IngressAuth
+	// quota and namespaceQuota are the quotas of the Broker and of its namespace.
+	quota, namespaceQuota *config.Quota
+	// deadLetterAddress is the resolved address of the Broker's dead letter sink, if it is not a
+	// Pub/Sub topic.
+	deadLetterAddress string
+	// projectID is the project of the Pub/Sub resources of the Broker, or empty for the project of
+	// the BrokerCell.
+	projectID string
+}
+
 // addBrokerAndTriggersToConfig reconstructs the data entry for the given broker and adds it to targets-config.
-func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, brokerTargets config.Targets) {
+//
+// The webhook rejects invalid Trigger settings and delivery specs, so they should always parse here. If one
+// doesn't, the error is logged and the Trigger is configured the safest way: left out of the config if its
+// events could be delivered unfiltered or untransformed, or with the defaults otherwise.
+func addBrokerAndTriggersToConfig(ctx context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, settings brokerSettings, brokerTargets config.Targets) {
 	// TODO Maybe get rid of GCPCellAddressableMutation and add Delete() and Upsert(broker) methods to TargetsConfig. Now we always
 	//  delete or update the entire broker entry and we don't need partial updates per trigger.
 	// The code can be simplified to r.targetsConfig.Upsert(brokerConfigEntry)
@@ -121,12 +395,24 @@ func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, tr
 			Topic:        brokerresources.GenerateDecouplingTopicName(b),
 			Subscription: brokerresources.GenerateDecouplingSubscriptionName(b),
 			State:        brokerQueueState,
+			ProjectId:    settings.projectID,
 		})
 		if b.Status.IsReady() {
 			m.SetState(config.State_READY)
 		} else {
 			m.SetState(config.State_UNKNOWN)
 		}
+		m.SetOrderingKeyAttribute(b.GetOrderingKeyAttribute())
+		m.SetIngressAuth(settings.ingressAuth)
+		m.SetQuota(settings.quota)
+		m.SetNamespaceQuota(settings.namespaceQuota)
+		if window := b.GetDedupWindow(); window > 0 {
+			m.SetDedupWindow(durationpb.New(window))
+		}
+		if enforcement := schemaEnforcementToConfig(b); enforcement != config.SchemaEnforcement_SCHEMA_ENFORCEMENT_OFF {
+			m.SetSchemaEnforcement(enforcement)
+			m.SetEventSchemas(settings.schemas)
+		}
 
 		// Insert each Trigger to the config.
 		for _, t := range triggers {
@@ -142,11 +428,30 @@ func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, tr
 					RetryQueue: &config.Queue{
 						Topic:        brokerresources.GenerateRetryTopicName(t),
 						Subscription: brokerresources.GenerateRetrySubscriptionName(t),
+						ProjectId:    settings.projectID,
 					},
 				}
+				setDeliveryPolicy(ctx, target, b.Spec.Delivery, settings.deadLetterAddress)
 				if t.Spec.Filter != nil && t.Spec.Filter.Attributes != nil {
 					target.FilterAttributes = t.Spec.Filter.Attributes
 				}
+				filters, err := t.GetFilters()
+				if err != nil {
+					// Leave the Trigger out rather than delivering events it didn't ask for.
+					logging.FromContext(ctx).Error("Failed to parse trigger filters", zap.String("trigger", t.Name), zap.Error(err))
+					continue
+				}
+				target.Filters = filtersToConfig(filters)
+				transform, err := t.GetTransform()
+				if err != nil {
+					// Leave the Trigger out rather than delivering untransformed events.
+					logging.FromContext(ctx).Error("Failed to parse trigger transform", zap.String("trigger", t.Name), zap.Error(err))
+					continue
+				}
+				target.Transform = transformToConfig(transform)
+				setBatching(ctx, target, t)
+				setLimits(ctx, target, t)
+				target.Replay = settings.replays[t.Name]
 				// TODO(#939) May need to use "data plane readiness" for trigger in stead of the
 				//  overall status, see https://github.com/google/knative-gcp/issues/939#issuecomment-644337937
 				if t.Status.IsReady() {
@@ -159,6 +464,112 @@ func addBrokerAndTriggersToConfig(_ context.Context, b *brokerv1beta1.Broker, tr
 		}
 	})
 }
+
+// setBatching sets the batch settings of the Trigger on the target.
+func setBatching(ctx context.Context, target *config.Target, t *brokerv1beta1.Trigger) {
+	maxEvents, maxDelay, err := t.GetBatching()
+	if err != nil {
+		// Deliver the events one at a time instead.
+		logging.FromContext(ctx).Error("Failed to parse trigger batch settings", zap.String("trigger", t.Name), zap.Error(err))
+		return
+	}
+	if maxEvents <= 0 {
+		return
+	}
+	target.BatchMaxEvents = maxEvents
+	target.BatchMaxDelay = durationpb.New(maxDelay)
+}
+
+// setLimits sets the rate limit and maximum concurrency of the Trigger on the target.
+func setLimits(ctx context.Context, target *config.Target, t *brokerv1beta1.Trigger) {
+	rateLimit, maxConcurrency, err := t.GetLimits()
+	if err != nil {
+		// Deliver the events without limits instead.
+		logging.FromContext(ctx).Error("Failed to parse trigger limits", zap.String("trigger", t.Name), zap.Error(err))
+		return
+	}
+	target.RateLimit = rateLimit
+	target.MaxConcurrency = maxConcurrency
+}
+
+// transformToConfig converts the Trigger's transform to its targets config representation.
+func transformToConfig(t *brokerv1beta1.EventTransform) *config.Transform {
+	if t == nil {
+		return nil
+	}
+	transform := &config.Transform{
+		Type:             t.Type,
+		Source:           t.Source,
+		SetExtensions:    t.SetExtensions,
+		RemoveExtensions: t.RemoveExtensions,
+	}
+	if t.Data != nil {
+		transform.Data = &config.DataTransform{
+			Select: t.Data.Select,
+			Remove: t.Data.Remove,
+		}
+		if len(t.Data.Set) > 0 {
+			transform.Data.Set = make(map[string]string, len(t.Data.Set))
+			for p, v := range t.Data.Set {
+				value := string(v.Raw)
+				if value == "" {
+					value = "null"
+				}
+				transform.Data.Set[p] = value
+			}
+		}
+	}
+	return transform
+}
+
+// filtersToConfig converts the Trigger's advanced filters to their targets config representation.
+func filtersToConfig(filters []brokerv1beta1.SubscriptionsAPIFilter) []*config.Filter {
+	if len(filters) == 0 {
+		return nil
+	}
+	out := make([]*config.Filter, 0, len(filters))
+	for i := range filters {
+		out = append(out, filterToConfig(&filters[i]))
+	}
+	return out
+}
+
+func filterToConfig(f *brokerv1beta1.SubscriptionsAPIFilter) *config.Filter {
+	switch {
+	case f.All != nil:
+		return &config.Filter{Expression: &config.Filter_All{All: &config.FilterList{Filters: filtersToConfig(f.All)}}}
+	case f.Any != nil:
+		return &config.Filter{Expression: &config.Filter_Any{Any: &config.FilterList{Filters: filtersToConfig(f.Any)}}}
+	case f.Not != nil:
+		return &config.Filter{Expression: &config.Filter_Not{Not: filterToConfig(f.Not)}}
+	case f.Exact != nil:
+		return &config.Filter{Expression: &config.Filter_Exact{Exact: &config.AttributesFilter{Attributes: f.Exact}}}
+	case f.Prefix != nil:
+		return &config.Filter{Expression: &config.Filter_Prefix{Prefix: &config.AttributesFilter{Attributes: f.Prefix}}}
+	case f.Suffix != nil:
+		return &config.Filter{Expression: &config.Filter_Suffix{Suffix: &config.AttributesFilter{Attributes: f.Suffix}}}
+	case len(f.AnyOf) == 1:
+		for attr, values := range f.AnyOf {
+			return &config.Filter{Expression: &config.Filter_AnyOf{AnyOf: &config.AnyOfFilter{Attribute: attr, Values: values}}}
+		}
+	case f.AnyOf != nil:
+		// Every attribute must match one of its values. Sort the attributes so that the config
+		// is stable across reconciliations.
+		attrs := make([]string, 0, len(f.AnyOf))
+		for attr := range f.AnyOf {
+			attrs = append(attrs, attr)
+		}
+		sort.Strings(attrs)
+		all := make([]*config.Filter, 0, len(attrs))
+		for _, attr := range attrs {
+			all = append(all, &config.Filter{Expression: &config.Filter_AnyOf{AnyOf: &config.AnyOfFilter{Attribute: attr, Values: f.AnyOf[attr]}}})
+		}
+		return &config.Filter{Expression: &config.Filter_All{All: &config.FilterList{Filters: all}}}
+	}
+	// An empty filter; the webhook rejects it. It fails to compile in the data plane.
+	return &config.Filter{}
+}
+
 func (r *Reconciler) addChannelsToTargets(ctx context.Context, bc *intv1alpha1.BrokerCell, targets config.Targets) error {
 	// TODO(#866) Only select Channels that point to this brokercell by label selector once the
 	// webhook assigns the brokercell label, i.e.,
@@ -170,13 +581,20 @@ func (r *Reconciler) addChannelsToTargets(ctx context.Context, bc *intv1alpha1.B
 		return err
 	}
 	for _, channel := range channels {
-		addChannelToConfig(ctx, channel, targets)
+		projectID, err := r.tenantProject(channel.Namespace, channel.Status.ProjectID)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get the project of the channel", zap.String("Channel", channel.Name), zap.Error(err))
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to get the project of channel %v: %v", channel.Name, err)
+			return err
+		}
+		addChannelToConfig(ctx, channel, projectID, targets)
 	}
 	return nil
 }
 
 // addChannelToConfig reconstructs the data entry for the given Channel and adds it to targets-config.
-func addChannelToConfig(_ context.Context, c *v1beta1.Channel, targets config.Targets) {
+// projectID is the project of the Pub/Sub resources of the Channel, or empty for the project of the BrokerCell.
+func addChannelToConfig(ctx context.Context, c *v1beta1.Channel, projectID string, targets config.Targets) {
 	if c.Status.Address == nil {
 		// The address hasn't been set. The Channel reconciler will get to it. At which point the
 		// Channel will be modified, so the BrokerCell will reconcile again. For now, ignore this
@@ -205,6 +623,7 @@ func addChannelToConfig(_ context.Context, c *v1beta1.Channel, targets config.Ta
 			Topic:        channelresources.GenerateDecouplingTopicName(c),
 			Subscription: channelresources.GenerateDecouplingSubscriptionName(c),
 			State:        queueState,
+			ProjectId:    projectID,
 		})
 		if c.Status.IsReady() {
 			m.SetState(config.State_READY)
@@ -230,11 +649,19 @@ func addChannelToConfig(_ context.Context, c *v1beta1.Channel, targets config.Ta
 				RetryQueue: &config.Queue{
 					Topic:        channelresources.GenerateSubscriberRetryTopicName(c, s.UID),
 					Subscription: channelresources.GenerateSubscriberRetrySubscriptionName(c, s.UID),
+					ProjectId:    projectID,
 				},
 				// TODO(#939) May need to use "data plane readiness" for trigger in stead of the
 				//  overall status, see https://github.com/google/knative-gcp/issues/939#issuecomment-644337937
 				State: config.State_READY,
 			}
+			// The Subscription controller resolves the dead letter sink into its URI.
+			var deadLetterAddress string
+			if d := s.Delivery; d != nil && d.DeadLetterSink != nil && d.DeadLetterSink.URI != nil &&
+				!brokerv1beta1.IsPubSubDeadLetterSink(d.DeadLetterSink) {
+				deadLetterAddress = d.DeadLetterSink.URI.String()
+			}
+			setDeliveryPolicy(ctx, target, s.Delivery, deadLetterAddress)
 			m.UpsertTargets(target)
 		}
 	})
@@ -0,0 +1,632 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	_ "knative.dev/pkg/injection/clients/dynamicclient/fake"
+	reconcilertesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+func TestFiltersToConfig(t *testing.T) {
+	filters := []brokerv1beta1.SubscriptionsAPIFilter{{
+		Prefix: map[string]string{"type": "com.example."},
+	}, {
+		Not: &brokerv1beta1.SubscriptionsAPIFilter{
+			Exact: map[string]string{"source": "foo"},
+		},
+	}, {
+		Any: []brokerv1beta1.SubscriptionsAPIFilter{{
+			Suffix: map[string]string{"subject": ".png"},
+		}, {
+			AnyOf: map[string][]string{"subject": {"a", "b"}},
+		}},
+	}, {
+		AnyOf: map[string][]string{"type": {"c"}, "source": {"d", "e"}},
+	}}
+	want := []*config.Filter{{
+		Expression: &config.Filter_Prefix{Prefix: &config.AttributesFilter{Attributes: map[string]string{"type": "com.example."}}},
+	}, {
+		Expression: &config.Filter_Not{Not: &config.Filter{
+			Expression: &config.Filter_Exact{Exact: &config.AttributesFilter{Attributes: map[string]string{"source": "foo"}}},
+		}},
+	}, {
+		Expression: &config.Filter_Any{Any: &config.FilterList{Filters: []*config.Filter{{
+			Expression: &config.Filter_Suffix{Suffix: &config.AttributesFilter{Attributes: map[string]string{"subject": ".png"}}},
+		}, {
+			Expression: &config.Filter_AnyOf{AnyOf: &config.AnyOfFilter{Attribute: "subject", Values: []string{"a", "b"}}},
+		}}}},
+	}, {
+		Expression: &config.Filter_All{All: &config.FilterList{Filters: []*config.Filter{{
+			Expression: &config.Filter_AnyOf{AnyOf: &config.AnyOfFilter{Attribute: "source", Values: []string{"d", "e"}}},
+		}, {
+			Expression: &config.Filter_AnyOf{AnyOf: &config.AnyOfFilter{Attribute: "type", Values: []string{"c"}}},
+		}}}},
+	}}
+	if diff := cmp.Diff(want, filtersToConfig(filters), protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected filters (-want, +got): %s", diff)
+	}
+	if got := filtersToConfig(nil); got != nil {
+		t.Errorf("expected nil filters, got %v", got)
+	}
+}
+
+func TestDeadLetterAddress(t *testing.T) {
+	retry := int32(3)
+	cases := []struct {
+		name     string
+		delivery *eventingduckv1beta1.DeliverySpec
+		want     string
+		wantErr  bool
+	}{{
+		name: "no delivery spec",
+	}, {
+		name:     "no dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{Retry: &retry},
+	}, {
+		name: "pubsub dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "topic"}},
+		},
+	}, {
+		name: "http dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com", Path: "/dls"}},
+		},
+		want: "http://example.com/dls",
+	}, {
+		name: "unresolvable dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{Ref: &duckv1.KReference{APIVersion: "serving.knative.dev/v1", Kind: "Service", Name: "missing"}},
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := reconcilertesting.SetupFakeContext(t)
+			r := &Reconciler{uriResolver: resolver.NewURIResolver(ctx, func(types.NamespacedName) {})}
+			b := &brokerv1beta1.Broker{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "broker"},
+				Spec:       eventingv1beta1.BrokerSpec{Delivery: tc.delivery},
+			}
+			got, err := r.deadLetterAddress(ctx, b)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("deadLetterAddress error = %v, wantErr = %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("deadLetterAddress got=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetDeliveryPolicy(t *testing.T) {
+	retry := int32(3)
+	delay := "PT2S"
+	invalidDelay := "2s"
+	linear := eventingduckv1beta1.BackoffPolicyLinear
+	cases := []struct {
+		name              string
+		delivery          *eventingduckv1beta1.DeliverySpec
+		deadLetterAddress string
+		want              *config.Target
+	}{{
+		name: "no delivery spec",
+		want: &config.Target{},
+	}, {
+		name:     "exponential backoff by default",
+		delivery: &eventingduckv1beta1.DeliverySpec{Retry: &retry, BackoffDelay: &delay},
+		want: &config.Target{
+			MaxDeliveryAttempts: 3,
+			BackoffPolicy:       config.BackoffPolicy_EXPONENTIAL,
+			BackoffDelay:        durationpb.New(2 * time.Second),
+		},
+	}, {
+		name:     "linear backoff",
+		delivery: &eventingduckv1beta1.DeliverySpec{BackoffDelay: &delay, BackoffPolicy: &linear},
+		want: &config.Target{
+			BackoffPolicy: config.BackoffPolicy_LINEAR,
+			BackoffDelay:  durationpb.New(2 * time.Second),
+		},
+	}, {
+		name:     "invalid backoff delay",
+		delivery: &eventingduckv1beta1.DeliverySpec{BackoffDelay: &invalidDelay},
+		want:     &config.Target{},
+	}, {
+		name: "pubsub dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			Retry:          &retry,
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "topic"}},
+		},
+		want: &config.Target{
+			MaxDeliveryAttempts: 3,
+			DeadLetterTopic:     "topic",
+		},
+	}, {
+		name: "http dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+		},
+		deadLetterAddress: "http://example.com",
+		want: &config.Target{
+			DeadLetterAddress: "http://example.com",
+		},
+	}, {
+		name: "unresolved dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			Retry:          &retry,
+			BackoffDelay:   &delay,
+			DeadLetterSink: &duckv1.Destination{Ref: &duckv1.KReference{APIVersion: "serving.knative.dev/v1", Kind: "Service", Name: "missing"}},
+		},
+		want: &config.Target{},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := &config.Target{}
+			setDeliveryPolicy(context.Background(), got, tc.delivery, tc.deadLetterAddress)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("setDeliveryPolicy (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestSetBatching(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		want        *config.Target
+	}{{
+		name: "batching disabled",
+		want: &config.Target{},
+	}, {
+		name:        "default max delay",
+		annotations: map[string]string{brokerv1beta1.BatchMaxEventsAnnotation: "100"},
+		want: &config.Target{
+			BatchMaxEvents: 100,
+			BatchMaxDelay:  durationpb.New(brokerv1beta1.DefaultBatchMaxDelay),
+		},
+	}, {
+		name: "max delay",
+		annotations: map[string]string{
+			brokerv1beta1.BatchMaxEventsAnnotation: "10",
+			brokerv1beta1.BatchMaxDelayAnnotation:  "PT0.5S",
+		},
+		want: &config.Target{
+			BatchMaxEvents: 10,
+			BatchMaxDelay:  durationpb.New(500 * time.Millisecond),
+		},
+	}, {
+		name:        "invalid max events",
+		annotations: map[string]string{brokerv1beta1.BatchMaxEventsAnnotation: "ten"},
+		want:        &config.Target{},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got := &config.Target{}
+			setBatching(context.Background(), got, trig)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("setBatching (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestSetLimits(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		want        *config.Target
+	}{{
+		name: "no limits",
+		want: &config.Target{},
+	}, {
+		name: "rate limit and max concurrency",
+		annotations: map[string]string{
+			brokerv1beta1.RateLimitAnnotation:      "2.5",
+			brokerv1beta1.MaxConcurrencyAnnotation: "10",
+		},
+		want: &config.Target{
+			RateLimit:      2.5,
+			MaxConcurrency: 10,
+		},
+	}, {
+		name:        "invalid limits",
+		annotations: map[string]string{brokerv1beta1.MaxConcurrencyAnnotation: "ten"},
+		want:        &config.Target{},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got := &config.Target{}
+			setLimits(context.Background(), got, trig)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("setLimits (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestTransformToConfig(t *testing.T) {
+	transform := &brokerv1beta1.EventTransform{
+		Type:             "com.example.v2",
+		Source:           "example",
+		SetExtensions:    map[string]string{"team": "a"},
+		RemoveExtensions: []string{"traceparent"},
+		Data: &brokerv1beta1.DataTransform{
+			Select: []string{"/user"},
+			Remove: []string{"/user/email"},
+			Set: map[string]runtime.RawExtension{
+				"/version": {Raw: []byte(`2`)},
+				"/deleted": {},
+			},
+		},
+	}
+	want := &config.Transform{
+		Type:             "com.example.v2",
+		Source:           "example",
+		SetExtensions:    map[string]string{"team": "a"},
+		RemoveExtensions: []string{"traceparent"},
+		Data: &config.DataTransform{
+			Select: []string{"/user"},
+			Remove: []string{"/user/email"},
+			Set:    map[string]string{"/version": "2", "/deleted": "null"},
+		},
+	}
+	if diff := cmp.Diff(want, transformToConfig(transform), protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected transform (-want, +got): %s", diff)
+	}
+	if got := transformToConfig(nil); got != nil {
+		t.Errorf("expected nil transform, got %v", got)
+	}
+}
+
+func TestReplaysToConfig(t *testing.T) {
+	start := metav1.NewTime(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC))
+	replay := func(name, trigger string, opts ...func(*eventsv1alpha1.ReplayStatus)) *eventsv1alpha1.Replay {
+		r := &eventsv1alpha1.Replay{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name + "-uid")},
+			Spec:       eventsv1alpha1.ReplaySpec{Trigger: trigger, StartTime: start},
+		}
+		r.Status.InitializeConditions()
+		for _, opt := range opts {
+			opt(&r.Status)
+		}
+		return r
+	}
+	seeked := func(rs *eventsv1alpha1.ReplayStatus) {
+		rs.MarkSubscriptionSeeked("sub", end)
+	}
+	replayed := func(rs *eventsv1alpha1.ReplayStatus) {
+		rs.MarkReplayed(end)
+	}
+	deleted := func(r *eventsv1alpha1.Replay) *eventsv1alpha1.Replay {
+		r.DeletionTimestamp = &end
+		return r
+	}
+	got := replaysToConfig([]*eventsv1alpha1.Replay{
+		replay("pending", "t1"),
+		replay("in-progress", "t2", seeked),
+		replay("done", "t3", seeked, replayed),
+		deleted(replay("deleted", "t4", seeked)),
+	})
+	want := map[string]*config.Replay{
+		"t2": {
+			Id:           "in-progress-uid",
+			Subscription: "sub",
+			StartTime:    timestamppb.New(start.Time),
+			EndTime:      timestamppb.New(end.Time),
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected replays (-want, +got): %s", diff)
+	}
+}
+
+func TestIngressAuthToConfig(t *testing.T) {
+	address, _ := apis.ParseURL("http://ingress.example.com/ns/broker")
+	broker := func(annotations map[string]string) *brokerv1beta1.Broker {
+		b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+		b.Status.Address.URL = address
+		return b
+	}
+	cell := func(policy *intv1alpha1.IngressAuthPolicy) *intv1alpha1.BrokerCell {
+		return &intv1alpha1.BrokerCell{Spec: intv1alpha1.BrokerCellSpec{IngressAuth: policy}}
+	}
+	tests := []struct {
+		name   string
+		cell   *intv1alpha1.BrokerCell
+		broker *brokerv1beta1.Broker
+		want   *config.IngressAuth
+	}{{
+		name:   "no policy",
+		cell:   cell(nil),
+		broker: broker(nil),
+	}, {
+		name: "required by the brokercell",
+		cell: cell(&intv1alpha1.IngressAuthPolicy{
+			Mode:              intv1alpha1.IngressAuthRequired,
+			AllowedPrincipals: []string{"system:serviceaccount:ns:*"},
+		}),
+		broker: broker(nil),
+		want: &config.IngressAuth{
+			Audience:          "http://ingress.example.com/ns/broker",
+			AllowedPrincipals: []string{"system:serviceaccount:ns:*"},
+		},
+	}, {
+		name: "disabled by the broker",
+		cell: cell(&intv1alpha1.IngressAuthPolicy{Mode: intv1alpha1.IngressAuthRequired}),
+		broker: broker(map[string]string{
+			brokerv1beta1.IngressAuthAnnotation: brokerv1beta1.IngressAuthDisabled,
+		}),
+	}, {
+		name: "required by the broker",
+		cell: cell(&intv1alpha1.IngressAuthPolicy{
+			AllowedPrincipals: []string{"system:serviceaccount:ns:*"},
+		}),
+		broker: broker(map[string]string{
+			brokerv1beta1.IngressAuthAnnotation:              brokerv1beta1.IngressAuthRequired,
+			brokerv1beta1.IngressAudienceAnnotation:          "my-broker",
+			brokerv1beta1.IngressAllowedPrincipalsAnnotation: "sa@project.iam.gserviceaccount.com",
+		}),
+		want: &config.IngressAuth{
+			Audience:          "my-broker",
+			AllowedPrincipals: []string{"sa@project.iam.gserviceaccount.com"},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ingressAuthToConfig(test.cell, test.broker)
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected ingress auth (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestQuotasToConfig(t *testing.T) {
+	b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "broker",
+		Annotations: map[string]string{
+			brokerv1beta1.IngressEventsPerSecondAnnotation: "100",
+		},
+	}}
+	if diff := cmp.Diff(&config.Quota{EventsPerSecond: 100}, quotaToConfig(b), protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected broker quota (-want, +got): %s", diff)
+	}
+	if got := quotaToConfig(&brokerv1beta1.Broker{}); got != nil {
+		t.Errorf("unexpected broker quota: %v", got)
+	}
+
+	cell := &intv1alpha1.BrokerCell{Spec: intv1alpha1.BrokerCellSpec{
+		IngressQuotas: &intv1alpha1.IngressQuotas{
+			Default: &intv1alpha1.Quota{EventsPerSecond: 1000},
+			Namespaces: map[string]intv1alpha1.Quota{
+				"ns":        {BytesPerSecond: "1Mi"},
+				"unlimited": {},
+			},
+		},
+	}}
+	tests := []struct {
+		namespace string
+		want      *config.Quota
+	}{{
+		namespace: "ns",
+		want:      &config.Quota{BytesPerSecond: 1 << 20},
+	}, {
+		namespace: "other",
+		want:      &config.Quota{EventsPerSecond: 1000},
+	}, {
+		namespace: "unlimited",
+	}}
+	for _, test := range tests {
+		t.Run(test.namespace, func(t *testing.T) {
+			got := namespaceQuotaToConfig(cell, test.namespace)
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected namespace quota (-want, +got): %s", diff)
+			}
+		})
+	}
+	if got := namespaceQuotaToConfig(&intv1alpha1.BrokerCell{}, "ns"); got != nil {
+		t.Errorf("unexpected namespace quota: %v", got)
+	}
+}
+
+func TestTenantProject(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []*corev1.Namespace{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant",
+			Annotations: map[string]string{brokerv1beta1.ProjectAnnotation: "tenant-project"},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+	}} {
+		if err := indexer.Add(ns); err != nil {
+			t.Fatalf("failed to add namespace: %v", err)
+		}
+	}
+	r := &Reconciler{listers: listers{namespaceLister: corev1listers.NewNamespaceLister(indexer)}, projectID: "cell-project"}
+	tests := []struct {
+		name            string
+		namespace       string
+		statusProjectID string
+		want            string
+	}{{
+		name:      "annotated namespace",
+		namespace: "tenant",
+		want:      "tenant-project",
+	}, {
+		name:      "namespace without annotation",
+		namespace: "default",
+	}, {
+		name:      "missing namespace",
+		namespace: "missing",
+	}, {
+		name:            "project recorded in the status",
+		namespace:       "tenant",
+		statusProjectID: "old-project",
+		want:            "old-project",
+	}, {
+		name:            "project of the cell recorded in the status",
+		namespace:       "tenant",
+		statusProjectID: "cell-project",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := r.tenantProject(test.namespace, test.statusProjectID)
+			if err != nil {
+				t.Fatalf("tenantProject failed: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("tenantProject got=%q, want=%q", got, test.want)
+			}
+		})
+	}
+
+	failing := &Reconciler{listers: listers{namespaceLister: failingNamespaceLister{}}}
+	if _, err := failing.tenantProject("tenant", ""); err == nil {
+		t.Error("tenantProject succeeded with a failing namespace lister")
+	}
+	if got, err := failing.tenantProject("tenant", "tenant-project"); err != nil || got != "tenant-project" {
+		t.Errorf("tenantProject with the project recorded in the status got=(%q, %v), want=(%q, nil)", got, err, "tenant-project")
+	}
+
+	b := &brokerv1beta1.Broker{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "broker", UID: "broker-uid"},
+	}
+	b.Status.SetAddress(apis.HTTP("broker.example.com"))
+	trigger := &brokerv1beta1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "trigger", UID: "trigger-uid"},
+		Spec:       eventingv1beta1.TriggerSpec{Broker: "broker"},
+	}
+	targets := memory.NewEmptyTargets()
+	addBrokerAndTriggersToConfig(context.Background(), b, []*brokerv1beta1.Trigger{trigger}, brokerSettings{projectID: "tenant-project"}, targets)
+	tenant, ok := targets.GetCellTenantByKey(config.KeyFromBroker(b))
+	if !ok {
+		t.Fatal("broker is missing from the targets")
+	}
+	if got := tenant.DecoupleQueue.ProjectId; got != "tenant-project" {
+		t.Errorf("decouple queue project got=%q, want=%q", got, "tenant-project")
+	}
+	for _, target := range tenant.Targets {
+		if got := target.RetryQueue.ProjectId; got != "tenant-project" {
+			t.Errorf("retry queue project got=%q, want=%q", got, "tenant-project")
+		}
+	}
+}
+
+// failingNamespaceLister fails to get any namespace.
+type failingNamespaceLister struct {
+	corev1listers.NamespaceLister
+}
+
+func (failingNamespaceLister) Get(string) (*corev1.Namespace, error) {
+	return nil, errors.New("lister failure")
+}
+
+func TestEventSchemasToConfig(t *testing.T) {
+	b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "broker"}}
+	schema := func(namespace, name, broker, eventType string) *brokerv1beta1.EventSchema {
+		return &brokerv1beta1.EventSchema{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: brokerv1beta1.EventSchemaSpec{
+				Type:       eventType,
+				Broker:     broker,
+				JSONSchema: &runtime.RawExtension{Raw: []byte(`{"title": "` + name + `"}`)},
+			},
+		}
+	}
+	proto := schema("ns", "proto", "", "example.proto")
+	proto.Spec.JSONSchema = nil
+	proto.Spec.Proto = &brokerv1beta1.ProtoSchema{FileDescriptorSet: []byte("fds"), Message: "example.Order"}
+
+	got := eventSchemasToConfig(b, []*brokerv1beta1.EventSchema{
+		schema("ns", "b-namespace", "", "example.order"),
+		schema("ns", "z-broker", "broker", "example.order"),
+		schema("ns", "b-broker", "broker", "example.order"),
+		schema("ns", "b-namespace", "", "example.payment"),
+		schema("ns", "a-namespace", "", "example.payment"),
+		schema("ns", "other-broker", "other", "example.refund"),
+		schema("other", "other-namespace", "", "example.refund"),
+		proto,
+	})
+	want := map[string]*config.EventSchema{
+		"example.order":   {Name: "b-broker", JsonSchema: []byte(`{"title": "b-broker"}`)},
+		"example.payment": {Name: "a-namespace", JsonSchema: []byte(`{"title": "a-namespace"}`)},
+		"example.proto":   {Name: "proto", FileDescriptorSet: []byte("fds"), Message: "example.Order"},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected event schemas (-want, +got): %s", diff)
+	}
+
+	if got := eventSchemasToConfig(b, []*brokerv1beta1.EventSchema{schema("ns", "other-broker", "other", "example.refund")}); got != nil {
+		t.Errorf("unexpected event schemas for a broker without schemas: %v", got)
+	}
+}
+
+func TestSchemaEnforcementToConfig(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       config.SchemaEnforcement
+	}{
+		{annotation: "", want: config.SchemaEnforcement_SCHEMA_ENFORCEMENT_OFF},
+		{annotation: brokerv1beta1.SchemaEnforcementOff, want: config.SchemaEnforcement_SCHEMA_ENFORCEMENT_OFF},
+		{annotation: brokerv1beta1.SchemaEnforcementWarn, want: config.SchemaEnforcement_SCHEMA_ENFORCEMENT_WARN},
+		{annotation: brokerv1beta1.SchemaEnforcementReject, want: config.SchemaEnforcement_SCHEMA_ENFORCEMENT_REJECT},
+	}
+	for _, test := range tests {
+		b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{brokerv1beta1.SchemaEnforcementAnnotation: test.annotation},
+		}}
+		if got := schemaEnforcementToConfig(b); got != test.want {
+			t.Errorf("schemaEnforcementToConfig(%q) = %v, want %v", test.annotation, got, test.want)
+		}
+	}
+}
@@ -34,12 +34,14 @@ import (
 	hpav2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"knative.dev/pkg/network"
+	"knative.dev/pkg/resolver"
 
 	pkgreconciler "knative.dev/pkg/reconciler"
 
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	bcreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
+	eventslisters "github.com/google/knative-gcp/pkg/client/listers/events/v1alpha1"
 	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
@@ -69,6 +71,9 @@ type listers struct {
 	endpointsLister      corev1listers.EndpointsLister
 	deploymentLister     appsv1listers.DeploymentLister
 	podLister            corev1listers.PodLister
+	namespaceLister      corev1listers.NamespaceLister
+	replayLister         eventslisters.ReplayLister
+	eventSchemaLister    brokerlisters.EventSchemaLister
 }
 
 // NewReconciler creates a new BrokerCell reconciler.
@@ -114,6 +119,13 @@ type Reconciler struct {
 	deploymentRec *reconcilerutils.DeploymentReconciler
 	cmRec         *reconcilerutils.ConfigMapReconciler
 
+	// uriResolver resolves the dead letter sinks of Brokers.
+	uriResolver *resolver.URIResolver
+
+	// projectID is the GCP project of the BrokerCell. Tenants in this project are configured
+	// without a project, so that the data plane uses its own Pub/Sub client for them.
+	projectID string
+
 	env envConfig
 }
 
@@ -309,10 +321,16 @@ func (r *Reconciler) makeIngressArgs(bc *intv1alpha1.BrokerCell, authType authch
 			MemoryLimit:        bc.Spec.Components.Ingress.MemoryLimit,
 			RolloutRestartTime: bc.GetAnnotations()[resources.IngressRestartTimeAnnotationKey],
 			AuthType:           authType,
+			ClaimCheckBucket:   bc.GetAnnotations()[resources.ClaimCheckBucketAnnotationKey],
 		},
 		Port: r.env.IngressPort,
 		// TODO(#1804): remove this arg when enabling the feature by default.
 		EnableIngressFilter: getIngressFilteringEnabled(bc),
+		DedupRedisAddress:   bc.GetAnnotations()[resources.IngressDedupRedisAddressAnnotationKey],
+		DedupRedisSecret:    bc.GetAnnotations()[resources.IngressDedupRedisSecretAnnotationKey],
+		DedupRedisTLS:       bc.GetAnnotations()[resources.IngressDedupRedisTLSAnnotationKey] == "true",
+		ClaimCheckThreshold: bc.GetAnnotations()[resources.IngressClaimCheckThresholdAnnotationKey],
+		Publish:             bc.Spec.Components.Ingress.Publish,
 	}
 }
 
@@ -351,6 +369,7 @@ func (r *Reconciler) makeFanoutArgs(bc *intv1alpha1.BrokerCell, authType authche
 			MemoryLimit:        bc.Spec.Components.Fanout.MemoryLimit,
 			RolloutRestartTime: bc.GetAnnotations()[resources.FanoutRestartTimeAnnotationKey],
 			AuthType:           authType,
+			ClaimCheckBucket:   bc.GetAnnotations()[resources.ClaimCheckBucketAnnotationKey],
 		},
 	}
 }
@@ -381,6 +400,7 @@ func (r *Reconciler) makeRetryArgs(bc *intv1alpha1.BrokerCell, authType authchec
 			MemoryLimit:        bc.Spec.Components.Retry.MemoryLimit,
 			RolloutRestartTime: bc.GetAnnotations()[resources.RetryRestartTimeAnnotationKey],
 			AuthType:           authType,
+			ClaimCheckBucket:   bc.GetAnnotations()[resources.ClaimCheckBucketAnnotationKey],
 		},
 	}
 }
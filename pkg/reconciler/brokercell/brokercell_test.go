@@ -36,6 +36,7 @@ import (
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -73,6 +74,22 @@ var (
 	enableIngressFilteringAnnotation = map[string]string{
 		"events.cloud.google.com/ingressFilteringEnabled": "true",
 	}
+	dedupRedisAddressAnnotation = map[string]string{
+		"events.cloud.google.com/ingressDedupRedisAddress": "10.0.0.3:6379",
+		"events.cloud.google.com/ingressDedupRedisSecret":  "redis-secret",
+		"events.cloud.google.com/ingressDedupRedisTLS":     "true",
+	}
+	publishSettings = &intv1alpha1.PublishSettings{
+		DelayThreshold:    "50ms",
+		CountThreshold:    ptr.Int32(500),
+		ByteThreshold:     "5Mi",
+		BufferedByteLimit: "500Mi",
+		Timeout:           "1m",
+	}
+	claimCheckAnnotation = map[string]string{
+		"events.cloud.google.com/claimCheckBucket":           "claim-check-bucket",
+		"events.cloud.google.com/ingressClaimCheckThreshold": "1000000",
+	}
 
 	brokerCellReconciledEvent     = Eventf(corev1.EventTypeNormal, "BrokerCellReconciled", `BrokerCell reconciled: "testnamespace/test-brokercell"`)
 	brokerCellGCEvent             = Eventf(corev1.EventTypeNormal, "BrokerCellGarbageCollected", `BrokerCell garbage collected: "testnamespace/test-brokercell"`)
@@ -881,6 +898,93 @@ func TestAllCases(t *testing.T) {
 				brokerCellReconciledEvent,
 			},
 		},
+		{
+			Name: "BrokerCell with ingress dedup Redis address created successfully",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults,
+					WithBrokerCellAnnotations(dedupRedisAddressAnnotation)),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithDedupRedisAddress(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithBrokerCellAnnotations(dedupRedisAddressAnnotation),
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "BrokerCell with claim check bucket created successfully",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults,
+					WithBrokerCellAnnotations(claimCheckAnnotation)),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithClaimCheck(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithClaimCheck(t),
+				testingdata.RetryDeploymentWithClaimCheck(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithBrokerCellAnnotations(claimCheckAnnotation),
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "BrokerCell with ingress publish settings created successfully",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults,
+					WithBrokerCellIngressPublishSettings(publishSettings)),
+				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				testingdata.IngressDeploymentWithPublishSettings(t),
+				testingdata.IngressServiceWithStatus(t),
+				testingdata.FanoutDeploymentWithStatus(t),
+				testingdata.RetryDeploymentWithStatus(t),
+				testingdata.IngressHPA(t),
+				testingdata.FanoutHPA(t),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellSetDefaults,
+					WithBrokerCellIngressPublishSettings(publishSettings),
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
 		{
 			Name: "googlecloud created BrokerCell shouldn't be gc'ed because there are brokers",
 			Key:  testKey,
@@ -1049,6 +1153,9 @@ func TestAllCases(t *testing.T) {
 			endpointsLister:      testingListers.GetEndpointsLister(),
 			deploymentLister:     testingListers.GetDeploymentLister(),
 			podLister:            testingListers.GetPodLister(),
+			namespaceLister:      testingListers.GetNamespaceLister(),
+			replayLister:         testingListers.GetReplayLister(),
+			eventSchemaLister:    testingListers.GetEventSchemaLister(),
 		}
 
 		r, err := NewReconciler(base, ls)
@@ -1192,15 +1299,18 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 			}
 			testingListers := NewListers(objects)
 			ls := listers{
-				brokerLister:     testingListers.GetBrokerLister(),
-				channelLister:    testingListers.GetChannelLister(),
-				hpaLister:        testingListers.GetHPALister(),
-				triggerLister:    testingListers.GetTriggerLister(),
-				configMapLister:  testingListers.GetConfigMapLister(),
-				serviceLister:    testingListers.GetK8sServiceLister(),
-				endpointsLister:  testingListers.GetEndpointsLister(),
-				deploymentLister: testingListers.GetDeploymentLister(),
-				podLister:        testingListers.GetPodLister(),
+				brokerLister:      testingListers.GetBrokerLister(),
+				channelLister:     testingListers.GetChannelLister(),
+				hpaLister:         testingListers.GetHPALister(),
+				triggerLister:     testingListers.GetTriggerLister(),
+				configMapLister:   testingListers.GetConfigMapLister(),
+				serviceLister:     testingListers.GetK8sServiceLister(),
+				endpointsLister:   testingListers.GetEndpointsLister(),
+				deploymentLister:  testingListers.GetDeploymentLister(),
+				podLister:         testingListers.GetPodLister(),
+				namespaceLister:   testingListers.GetNamespaceLister(),
+				replayLister:      testingListers.GetReplayLister(),
+				eventSchemaLister: testingListers.GetEventSchemaLister(),
 			}
 			r, err := NewReconciler(base, ls)
 			if err != nil {
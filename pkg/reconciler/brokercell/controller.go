@@ -29,7 +29,9 @@ import (
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
+	eventschemainformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/eventschema"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
+	replayinformer "github.com/google/knative-gcp/pkg/client/injection/informers/events/v1alpha1/replay"
 	brokercellinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
 	hpainformer "github.com/google/knative-gcp/pkg/client/injection/kube/informers/autoscaling/v2beta2/horizontalpodautoscaler"
 	v1alpha1brokercell "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
@@ -38,6 +40,7 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler"
 	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 	customresourceutil "github.com/google/knative-gcp/pkg/utils/customresource"
 
@@ -48,6 +51,7 @@ import (
 	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
 	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
 	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
 	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	serviceaccountinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount"
@@ -55,6 +59,7 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
 	systemnamespacesecretinformer "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret"
+	"knative.dev/pkg/resolver"
 	"knative.dev/pkg/system"
 )
 
@@ -94,6 +99,9 @@ func NewController(
 		endpointsLister:      endpointsinformer.Get(ctx).Lister(),
 		deploymentLister:     deploymentinformer.Get(ctx).Lister(),
 		podLister:            podinformer.Get(ctx).Lister(),
+		namespaceLister:      namespaceinformer.Get(ctx).Lister(),
+		replayLister:         replayinformer.Get(ctx).Lister(),
+		eventSchemaLister:    eventschemainformer.Get(ctx).Lister(),
 	}
 
 	base := reconciler.NewBase(ctx, controllerAgentName, cmw)
@@ -101,7 +109,16 @@ func NewController(
 	if err != nil {
 		logger.Fatal("Failed to create BrokerCell reconciler", zap.Error(err))
 	}
+	// If there is an error, the projectID will be empty and the tenants are configured with their
+	// project even if it is the project of the BrokerCell, which the data plane also supports.
+	if r.projectID, err = utils.ProjectIDOrDefault(""); err != nil {
+		logger.Error("Failed to get the project ID of the BrokerCell", zap.Error(err))
+	}
 	impl := v1alpha1brokercell.NewImpl(ctx, r)
+	r.uriResolver = resolver.NewURIResolver(ctx, func(types.NamespacedName) {
+		// TODO(#866) Select the brokercell that's associated with the broker of the dead letter sink.
+		impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+	})
 
 	var latencyReporter *metrics.BrokerCellLatencyReporter
 	if r.env.InternalMetricsEnabled {
@@ -136,6 +153,23 @@ func NewController(
 		},
 	))
 
+	// Watch replays to add them to the configmap as soon as their subscription is seeked, and remove
+	// them once they are done.
+	replayinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
+		func(obj interface{}) {
+			// TODO(#866) Select the brokercell that's associated with the replay's broker.
+			impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+		},
+	))
+
+	// Watch event schemas to project them into the configmap of the brokers they apply to.
+	eventschemainformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
+		func(obj interface{}) {
+			// TODO(#866) Select the brokercells that are associated with the brokers of the schema.
+			impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+		},
+	))
+
 	// Watch GCP Channels and subscriptions on those channels to invoke configmap update immediately.
 	channelinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
 		func(obj interface{}) {
@@ -147,6 +181,15 @@ func NewController(
 		},
 	))
 
+	// Watch namespaces to configure the tenants in the project annotated on their namespace until
+	// the project is recorded in their status.
+	namespaceinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
+		func(obj interface{}) {
+			// TODO(#866) Select the brokercells that are associated with the brokers of the namespace.
+			impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+		},
+	))
+
 	// Watch data plane components created by brokercell so we can update brokercell status immediately.
 	// 1. Watch deployments for ingress, fanout and retry
 	deploymentinformer.Get(ctx).Informer().AddEventHandler(handleResourceUpdate(impl))
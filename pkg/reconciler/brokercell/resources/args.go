@@ -43,6 +43,24 @@ const (
 	// IngressFilteringEnabledAnnotationKey is the annotation key for enabling ingress filtering.
 	// TODO(#1804): remove this constant when enabling the feature by default.
 	IngressFilteringEnabledAnnotationKey = "events.cloud.google.com/ingressFilteringEnabled"
+	// IngressDedupRedisAddressAnnotationKey is the annotation key for the address of the Redis
+	// server shared by the ingress replicas to deduplicate events. If not set, each replica
+	// deduplicates the events it receives in memory.
+	IngressDedupRedisAddressAnnotationKey = "events.cloud.google.com/ingressDedupRedisAddress"
+	// IngressDedupRedisSecretAnnotationKey is the annotation key for the name of the Secret, in the
+	// namespace of the BrokerCell, holding the AUTH string of the Redis server in its "auth" key,
+	// and the PEM encoded CA certificates of the server in its "ca.crt" key. Both keys are optional.
+	IngressDedupRedisSecretAnnotationKey = "events.cloud.google.com/ingressDedupRedisSecret"
+	// IngressDedupRedisTLSAnnotationKey is the annotation key for enabling TLS on the connections
+	// to the Redis server.
+	IngressDedupRedisTLSAnnotationKey = "events.cloud.google.com/ingressDedupRedisTLS"
+	// ClaimCheckBucketAnnotationKey is the annotation key for the Cloud Storage bucket the ingress
+	// offloads the data of large events to, and the fanout and retry read it back from. If not
+	// set, events larger than a Pub/Sub message are rejected.
+	ClaimCheckBucketAnnotationKey = "events.cloud.google.com/claimCheckBucket"
+	// IngressClaimCheckThresholdAnnotationKey is the annotation key for the data size in bytes
+	// above which the ingress offloads events to the claim check bucket.
+	IngressClaimCheckThresholdAnnotationKey = "events.cloud.google.com/ingressClaimCheckThreshold"
 )
 
 var (
@@ -63,6 +81,8 @@ type Args struct {
 	MemoryLimit        string
 	RolloutRestartTime string
 	AuthType           authcheck.AuthType
+	// ClaimCheckBucket is the bucket holding the data of the offloaded events, if any.
+	ClaimCheckBucket string
 }
 
 // IngressArgs are the arguments to create a Broker's ingress Deployment.
@@ -71,6 +91,17 @@ type IngressArgs struct {
 	Port int
 	// TODO(#1804): remove this field when enabling the feature by default.
 	EnableIngressFilter bool
+	// DedupRedisAddress is the address of the Redis server used to deduplicate events, if any.
+	DedupRedisAddress string
+	// DedupRedisSecret is the name of the Secret holding the AUTH string and CA certificates of
+	// the Redis server, if any.
+	DedupRedisSecret string
+	// DedupRedisTLS is whether the connections to the Redis server use TLS.
+	DedupRedisTLS bool
+	// ClaimCheckThreshold is the data size in bytes above which events are offloaded, if set.
+	ClaimCheckThreshold string
+	// Publish are the settings of the Pub/Sub publisher, if any.
+	Publish *intv1alpha1.PublishSettings
 }
 
 // FanoutArgs are the arguments to create a Broker's fanout Deployment.
@@ -19,10 +19,12 @@ package resources
 import (
 	"strconv"
 
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/handler"
 	resourceutil "github.com/google/knative-gcp/pkg/utils/resource"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/kmeta"
@@ -42,6 +44,21 @@ func MakeIngressDeployment(args IngressArgs) *appsv1.Deployment {
 		Name:  "ENABLE_INGRESS_EVENT_FILTERING",
 		Value: strconv.FormatBool(args.EnableIngressFilter),
 	})
+	if args.DedupRedisAddress != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "DEDUP_REDIS_ADDRESS", Value: args.DedupRedisAddress})
+		if args.DedupRedisTLS {
+			container.Env = append(container.Env, corev1.EnvVar{Name: "DEDUP_REDIS_TLS", Value: "true"})
+		}
+		if args.DedupRedisSecret != "" {
+			container.Env = append(container.Env,
+				dedupRedisSecretEnv("DEDUP_REDIS_PASSWORD", args.DedupRedisSecret, "auth"),
+				dedupRedisSecretEnv("DEDUP_REDIS_CA_CERT", args.DedupRedisSecret, "ca.crt"))
+		}
+	}
+	if args.ClaimCheckBucket != "" && args.ClaimCheckThreshold != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "CLAIM_CHECK_THRESHOLD_BYTES", Value: args.ClaimCheckThreshold})
+	}
+	container.Env = append(container.Env, publishEnv(args.Publish)...)
 
 	container.Ports = append(container.Ports, corev1.ContainerPort{Name: "http", ContainerPort: int32(args.Port)})
 	container.ReadinessProbe = &corev1.Probe{
@@ -75,6 +92,46 @@ func MakeIngressDeployment(args IngressArgs) *appsv1.Deployment {
 	return deploymentTemplate(args.Args, []corev1.Container{container})
 }
 
+// publishEnv returns the environment variables setting the ingress publish settings. Byte sizes
+// are converted from quantities to bytes.
+func publishEnv(settings *intv1alpha1.PublishSettings) []corev1.EnvVar {
+	if settings == nil {
+		return nil
+	}
+	var env []corev1.EnvVar
+	if settings.DelayThreshold != "" {
+		env = append(env, corev1.EnvVar{Name: "PUBLISH_DELAY_THRESHOLD", Value: settings.DelayThreshold})
+	}
+	if settings.CountThreshold != nil {
+		env = append(env, corev1.EnvVar{Name: "PUBLISH_COUNT_THRESHOLD", Value: strconv.Itoa(int(*settings.CountThreshold))})
+	}
+	if q, err := resource.ParseQuantity(settings.ByteThreshold); err == nil {
+		env = append(env, corev1.EnvVar{Name: "PUBLISH_BYTE_THRESHOLD", Value: strconv.FormatInt(q.Value(), 10)})
+	}
+	if q, err := resource.ParseQuantity(settings.BufferedByteLimit); err == nil {
+		env = append(env, corev1.EnvVar{Name: "PUBLISH_BUFFERED_BYTES_LIMIT", Value: strconv.FormatInt(q.Value(), 10)})
+	}
+	if settings.Timeout != "" {
+		env = append(env, corev1.EnvVar{Name: "PUBLISH_TIMEOUT", Value: settings.Timeout})
+	}
+	return env
+}
+
+// dedupRedisSecretEnv returns an environment variable set from an optional key of the Secret of
+// the Redis server.
+func dedupRedisSecretEnv(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+				Optional:             &optionalSecretVolume,
+			},
+		},
+	}
+}
+
 // MakeFanoutDeployment creates the fanout Deployment object.
 func MakeFanoutDeployment(args FanoutArgs) *appsv1.Deployment {
 	container := containerTemplate(args.Args)
@@ -184,7 +241,7 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 
 // containerTemplate returns a common template for broker data plane containers.
 func containerTemplate(args Args) corev1.Container {
-	return corev1.Container{
+	container := corev1.Container{
 		Image: args.Image,
 		Name:  args.ComponentName,
 		Env: []corev1.EnvVar{
@@ -238,4 +295,8 @@ func containerTemplate(args Args) corev1.Container {
 			},
 		},
 	}
+	if args.ClaimCheckBucket != "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "CLAIM_CHECK_BUCKET", Value: args.ClaimCheckBucket})
+	}
+	return container
 }
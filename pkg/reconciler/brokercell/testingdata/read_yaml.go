@@ -38,6 +38,26 @@ func IngressDeploymentWithFilteringAnnotation(t *testing.T) *appsv1.Deployment {
 	return getDeployment(t, "testingdata/ingress_deployment_with_filtering_annotation.yaml")
 }
 
+func IngressDeploymentWithDedupRedisAddress(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/ingress_deployment_with_dedup_redis_address.yaml")
+}
+
+func IngressDeploymentWithClaimCheck(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/ingress_deployment_with_claim_check.yaml")
+}
+
+func IngressDeploymentWithPublishSettings(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/ingress_deployment_with_publish_settings.yaml")
+}
+
+func FanoutDeploymentWithClaimCheck(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/fanout_deployment_with_claim_check.yaml")
+}
+
+func RetryDeploymentWithClaimCheck(t *testing.T) *appsv1.Deployment {
+	return getDeployment(t, "testingdata/retry_deployment_with_claim_check.yaml")
+}
+
 func FanoutDeployment(t *testing.T) *appsv1.Deployment {
 	return getDeployment(t, "testingdata/fanout_deployment.yaml")
 }
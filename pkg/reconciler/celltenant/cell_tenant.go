@@ -21,6 +21,7 @@ package celltenant
 import (
 	"context"
 	"fmt"
+	"time"
 
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 
@@ -64,6 +65,10 @@ type Reconciler struct {
 	// pubsubClient is used as the Pubsub client when present.
 	PubsubClient *pubsub.Client
 
+	// Projects resolves the projects annotated on the namespaces of the tenants. If nil, the
+	// resources of all tenants are in ProjectID.
+	Projects *NamespaceProjects
+
 	DataresidencyStore *dataresidency.Store
 
 	// clusterRegion is the region where GKE is running.
@@ -94,17 +99,16 @@ func (r *Reconciler) FinalizeGCPCellTenant(ctx context.Context, b Statusable) er
 func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context, b Statusable) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Reconciling decoupling topic", zap.Any("broker", b))
-	// get ProjectID from metadata if projectID isn't set
-	projectID, err := utils.ProjectIDOrDefault(r.ProjectID)
+	// get ProjectID from the status, the namespace or metadata if projectID isn't set
+	projectID, namespaceProject, err := tenantProjectID(r.Projects, b.Object(), b.GetStatusProjectID(), r.ProjectID)
 	if err != nil {
 		logger.Error("Failed to find project id", zap.Error(err))
 		b.StatusUpdater().MarkTopicUnknown("ProjectIdNotFound", "Failed to find project id: %v", err)
 		b.StatusUpdater().MarkSubscriptionUnknown("ProjectIdNotFound", "Failed to find project id: %v", err)
 		return err
 	}
-	// Set the projectID in the status.
-	//TODO uncomment when eventing webhook allows this
-	//b.Status.ProjectID = projectID
+	// Set the projectID in the status, so that the resources are deleted in the same project.
+	b.SetStatusProjectID(projectID)
 
 	r.ClusterRegion, err = utils.ClusterRegion(r.ClusterRegion, metadataClient.NewDefaultMetadataClient)
 	if err != nil {
@@ -112,7 +116,7 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 		return err
 	}
 
-	client, err := r.getClientOrCreateNew(ctx, projectID, b.StatusUpdater())
+	client, err := r.getClientOrCreateNew(ctx, projectID, namespaceProject, b.StatusUpdater())
 	if err != nil {
 		logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 		return err
@@ -136,6 +140,10 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	//TODO uncomment when eventing webhook allows this
 	//b.Status.TopicID = topic.ID()
 
+	if err := r.reconcileRetentionSubscription(ctx, pubsubReconciler, topic, b); err != nil {
+		return err
+	}
+
 	// Check if PullSub exists, and if not, create it.
 	subID := b.GetSubscriptionName()
 	subConfig := pubsub.SubscriptionConfig{
@@ -145,6 +153,14 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 		// AckDeadline
 		// RetentionDuration
 	}
+	if b.MessageOrdering() {
+		// Failed deliveries of ordered events are redelivered from the decouple subscription
+		// instead of the retry topics, so that later events with the same ordering key cannot
+		// overtake them. Apply the delivery spec to it.
+		subConfig.EnableMessageOrdering = true
+		subConfig.RetryPolicy = reconcilerutilspubsub.RetryPolicy(ctx, b.DeliverySpec())
+		subConfig.DeadLetterPolicy = reconcilerutilspubsub.DeadLetterPolicy(projectID, b.DeliverySpec())
+	}
 	if _, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, b.Object(), b.StatusUpdater()); err != nil {
 		return err
 	}
@@ -156,12 +172,50 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	return nil
 }
 
+// reconcileRetentionSubscription creates the subscription retaining the events of the Broker for
+// replay if message retention is enabled, and deletes it otherwise. The subscription never expires
+// and is never pulled, so that a snapshot of it holds every event within the retention. Each replay
+// seeks its own temporary subscription to such a snapshot.
+func (r *Reconciler) reconcileRetentionSubscription(ctx context.Context, pubsubReconciler *reconcilerutilspubsub.Reconciler, topic *pubsub.Topic, b Statusable) error {
+	subID := b.GetRetentionSubscriptionName()
+	if subID == "" {
+		return nil
+	}
+	retention := b.MessageRetention()
+	if retention == 0 {
+		return pubsubReconciler.DeleteSubscription(ctx, subID, b.Object(), b.StatusUpdater())
+	}
+	subConfig := pubsub.SubscriptionConfig{
+		Topic:               topic,
+		Labels:              b.GetLabels(),
+		RetainAckedMessages: true,
+		RetentionDuration:   retention,
+		ExpirationPolicy:    time.Duration(0),
+	}
+	sub, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, b.Object(), b.StatusUpdater())
+	if err != nil {
+		return err
+	}
+	config, err := sub.Config(ctx)
+	if err != nil {
+		b.StatusUpdater().MarkSubscriptionUnknown("SubscriptionConfigUnknown", "Failed to get Pub/Sub subscription Config: %v", err)
+		return err
+	}
+	if config.RetentionDuration != retention {
+		if _, err := sub.Update(ctx, pubsub.SubscriptionConfigToUpdate{RetentionDuration: retention}); err != nil {
+			b.StatusUpdater().MarkSubscriptionFailed("SubscriptionConfigUpdateFailed", "Failed to update Pub/Sub subscription config: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, s Statusable) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Deleting decoupling topic")
 
-	// get ProjectID from metadata if projectID isn't set
-	projectID, err := utils.ProjectIDOrDefault(r.ProjectID)
+	// get ProjectID from the status, the namespace or metadata if projectID isn't set
+	projectID, namespaceProject, err := tenantProjectID(r.Projects, s.Object(), s.GetStatusProjectID(), r.ProjectID)
 	if err != nil {
 		logger.Error("Failed to find project id", zap.Error(err))
 		s.StatusUpdater().MarkTopicUnknown("FinalizeTopicProjectIdNotFound", "Failed to find project id: %v", err)
@@ -169,7 +223,7 @@ func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, s
 		return err
 	}
 
-	client, err := r.getClientOrCreateNew(ctx, projectID, s.StatusUpdater())
+	client, err := r.getClientOrCreateNew(ctx, projectID, namespaceProject, s.StatusUpdater())
 	if err != nil {
 		logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 		return err
@@ -182,6 +236,9 @@ func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, s
 	err = multierr.Append(nil, pubsubReconciler.DeleteTopic(ctx, topicID, s.Object(), s.StatusUpdater()))
 	subID := s.GetSubscriptionName()
 	err = multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, subID, s.Object(), s.StatusUpdater()))
+	if retentionSubID := s.GetRetentionSubscriptionName(); retentionSubID != "" {
+		err = multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, retentionSubID, s.Object(), s.StatusUpdater()))
+	}
 
 	return err
 }
@@ -192,8 +249,18 @@ func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, s
 var CreatePubsubClientFn reconcilerutilspubsub.CreateFn = pubsub.NewClient
 
 // getClientOrCreateNew Return the pubsubCient if it is valid, otherwise it tries to create a new client
-// and register it for later usage.
-func (r *Reconciler) getClientOrCreateNew(ctx context.Context, projectID string, su reconcilerutilspubsub.StatusUpdater) (*pubsub.Client, error) {
+// and register it for later usage. If namespaceProject is true, the client of the project annotated
+// on the namespace is returned instead.
+func (r *Reconciler) getClientOrCreateNew(ctx context.Context, projectID string, namespaceProject bool, su reconcilerutilspubsub.StatusUpdater) (*pubsub.Client, error) {
+	if namespaceProject {
+		client, err := r.Projects.Client(ctx, projectID)
+		if err != nil {
+			su.MarkTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client of project %q: %v", projectID, err)
+			su.MarkSubscriptionUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client of project %q: %v", projectID, err)
+			return nil, err
+		}
+		return client, nil
+	}
 	if r.PubsubClient != nil {
 		return r.PubsubClient, nil
 	}
@@ -18,6 +18,7 @@ package celltenant
 
 import (
 	"fmt"
+	"time"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/duck"
@@ -39,8 +40,13 @@ type Target interface {
 	StatusUpdater() reconcilerutilspubsub.StatusUpdater
 	GetTopicID() string
 	GetSubscriptionName() string
+	// GetSubscriptionFilter returns the Pub/Sub filter of the retry subscription, if any.
+	GetSubscriptionFilter() string
 	GetLabels() map[string]string
 	DeliverySpec() *eventingduckv1beta1.DeliverySpec
+	// GetStatusProjectID returns the project the Pub/Sub resources of the target were created in,
+	// or an empty string if it is not recorded.
+	GetStatusProjectID() string
 	SetStatusProjectID(projectID string)
 }
 
@@ -85,13 +91,23 @@ func (t *targetForTrigger) GetSubscriptionName() string {
 	return brokerresources.GenerateRetrySubscriptionName(t.trigger)
 }
 
+func (t *targetForTrigger) GetSubscriptionFilter() string {
+	if t.trigger.Spec.Filter == nil {
+		return ""
+	}
+	return reconcilerutilspubsub.AttributesFilter(t.trigger.Spec.Filter.Attributes)
+}
+
 func (t *targetForTrigger) DeliverySpec() *eventingduckv1beta1.DeliverySpec {
 	return t.deliverySpec
 }
 
-func (t *targetForTrigger) SetStatusProjectID(_ string) {
-	// TODO uncomment when eventing webhook allows this
-	// t.trigger.Status.ProjectID = projectID
+func (t *targetForTrigger) GetStatusProjectID() string {
+	return t.trigger.Status.ProjectID()
+}
+
+func (t *targetForTrigger) SetStatusProjectID(projectID string) {
+	t.trigger.Status.SetProjectID(projectID)
 }
 
 var _ Target = (*targetForSubscriberSpec)(nil)
@@ -145,10 +161,19 @@ func (s *targetForSubscriberSpec) GetSubscriptionName() string {
 	return channelresources.GenerateSubscriberRetrySubscriptionName(s.channel, s.subscriberSpec.UID)
 }
 
+func (s *targetForSubscriberSpec) GetSubscriptionFilter() string {
+	// Subscribers don't filter events.
+	return ""
+}
+
 func (s *targetForSubscriberSpec) DeliverySpec() *eventingduckv1beta1.DeliverySpec {
 	return s.subscriberSpec.Delivery
 }
 
+func (s *targetForSubscriberSpec) GetStatusProjectID() string {
+	return s.channel.Status.ProjectID
+}
+
 func (s *targetForSubscriberSpec) SetStatusProjectID(_ string) {
 	// ProjectID is stored on the Channel's status, not each subscriber's, so this is a noop.
 }
@@ -171,6 +196,10 @@ func (s *targetForSubscriberStatus) DeliverySpec() *eventingduckv1beta1.Delivery
 	return nil
 }
 
+func (s *targetForSubscriberStatus) GetStatusProjectID() string {
+	return s.channel.Status.ProjectID
+}
+
 func (s *targetForSubscriberStatus) SetStatusProjectID(_ string) {
 	// ProjectID is stored on the Channel's status, not each subscriber's, so this is a noop.
 }
@@ -200,6 +229,11 @@ func (s *targetForSubscriberStatus) GetSubscriptionName() string {
 	return channelresources.GenerateSubscriberRetrySubscriptionName(s.channel, s.subscriberStatus.UID)
 }
 
+func (s *targetForSubscriberStatus) GetSubscriptionFilter() string {
+	// Subscribers don't filter events.
+	return ""
+}
+
 var _ reconcilerutilspubsub.StatusUpdater = (*SubscriberStatus)(nil)
 
 type SubscriberStatus struct {
@@ -207,6 +241,8 @@ type SubscriberStatus struct {
 	topicMessage        string
 	subscriptionStatus  corev1.ConditionStatus
 	subscriptionMessage string
+	// deadLetterSinkMessage is set when the subscriber's dead letter sink cannot be resolved.
+	deadLetterSinkMessage string
 }
 
 func (s *SubscriberStatus) MarkTopicFailed(_, format string, args ...interface{}) {
@@ -239,8 +275,14 @@ func (s *SubscriberStatus) MarkSubscriptionReady(_ string) {
 	s.subscriptionMessage = ""
 }
 
+// MarkDeadLetterSinkUnresolved marks the subscriber as not ready because its dead letter sink
+// cannot be resolved.
+func (s *SubscriberStatus) MarkDeadLetterSinkUnresolved(format string, args ...interface{}) {
+	s.deadLetterSinkMessage = fmt.Sprintf(format, args...)
+}
+
 func (s *SubscriberStatus) Ready() corev1.ConditionStatus {
-	if s.topicStatus == corev1.ConditionFalse || s.subscriptionStatus == corev1.ConditionFalse {
+	if s.topicStatus == corev1.ConditionFalse || s.subscriptionStatus == corev1.ConditionFalse || s.deadLetterSinkMessage != "" {
 		return corev1.ConditionFalse
 	}
 	if s.topicStatus == corev1.ConditionUnknown || s.subscriptionStatus == corev1.ConditionUnknown {
@@ -256,6 +298,9 @@ func (s *SubscriberStatus) Message() string {
 	if s.subscriptionMessage != "" {
 		return s.subscriptionMessage
 	}
+	if s.deadLetterSinkMessage != "" {
+		return s.deadLetterSinkMessage
+	}
 	return ""
 }
 
@@ -271,6 +316,21 @@ type Statusable interface {
 	GetLabels() map[string]string
 	GetTopicID() string
 	GetSubscriptionName() string
+	// MessageOrdering returns true if events are delivered in the order of their ordering key.
+	MessageOrdering() bool
+	// DeliverySpec returns the delivery spec used to redeliver ordered events from the decouple
+	// subscription.
+	DeliverySpec() *eventingduckv1beta1.DeliverySpec
+	// MessageRetention returns how long events are retained for replay, or zero if they are not.
+	MessageRetention() time.Duration
+	// GetRetentionSubscriptionName returns the name of the subscription retaining events for
+	// replay, or an empty string if replay is not supported.
+	GetRetentionSubscriptionName() string
+	// GetStatusProjectID returns the project the Pub/Sub resources of the tenant were created in,
+	// or an empty string if it is not recorded.
+	GetStatusProjectID() string
+	// SetStatusProjectID records the project the Pub/Sub resources of the tenant are created in.
+	SetStatusProjectID(projectID string)
 }
 
 var _ Statusable = (*statusableForBroker)(nil)
@@ -331,6 +391,30 @@ func (b *statusableForBroker) GetSubscriptionName() string {
 	return brokerresources.GenerateDecouplingSubscriptionName(b.broker)
 }
 
+func (b *statusableForBroker) MessageOrdering() bool {
+	return b.broker.GetOrderingKeyAttribute() != ""
+}
+
+func (b *statusableForBroker) DeliverySpec() *eventingduckv1beta1.DeliverySpec {
+	return b.broker.Spec.Delivery
+}
+
+func (b *statusableForBroker) MessageRetention() time.Duration {
+	return b.broker.GetMessageRetention()
+}
+
+func (b *statusableForBroker) GetRetentionSubscriptionName() string {
+	return brokerresources.GenerateRetentionSubscriptionName(b.broker)
+}
+
+func (b *statusableForBroker) GetStatusProjectID() string {
+	return b.broker.Status.ProjectID()
+}
+
+func (b *statusableForBroker) SetStatusProjectID(projectID string) {
+	b.broker.Status.SetProjectID(projectID)
+}
+
 var _ Statusable = (*statusableForChannel)(nil)
 
 type statusableForChannel struct {
@@ -387,3 +471,27 @@ func (c *statusableForChannel) GetTopicID() string {
 func (c *statusableForChannel) GetSubscriptionName() string {
 	return channelresources.GenerateDecouplingSubscriptionName(c.ch)
 }
+
+func (c *statusableForChannel) MessageOrdering() bool {
+	return false
+}
+
+func (c *statusableForChannel) DeliverySpec() *eventingduckv1beta1.DeliverySpec {
+	return nil
+}
+
+func (c *statusableForChannel) MessageRetention() time.Duration {
+	return 0
+}
+
+func (c *statusableForChannel) GetRetentionSubscriptionName() string {
+	return ""
+}
+
+func (c *statusableForChannel) GetStatusProjectID() string {
+	return c.ch.Status.ProjectID
+}
+
+func (c *statusableForChannel) SetStatusProjectID(projectID string) {
+	c.ch.Status.ProjectID = projectID
+}
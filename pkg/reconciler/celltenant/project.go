@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celltenant
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// NamespaceProjects resolves the GCP project of the Pub/Sub resources of cell tenants from the
+// project annotation of their namespace, and caches a Pub/Sub client per annotated project.
+type NamespaceProjects struct {
+	namespaceLister corev1listers.NamespaceLister
+
+	clients map[string]*pubsub.Client
+	mut     sync.Mutex
+}
+
+// NewNamespaceProjects returns a NamespaceProjects reading the namespaces from the lister.
+func NewNamespaceProjects(namespaceLister corev1listers.NamespaceLister) *NamespaceProjects {
+	return &NamespaceProjects{
+		namespaceLister: namespaceLister,
+		clients:         make(map[string]*pubsub.Client),
+	}
+}
+
+// ProjectID returns the project annotated on the namespace of the object, or an empty string if
+// the namespace is not annotated.
+func (p *NamespaceProjects) ProjectID(obj runtime.Object) (string, error) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+	ns, err := p.namespaceLister.Get(m.GetNamespace())
+	if apierrs.IsNotFound(err) {
+		// The namespace is being deleted along with its tenants.
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return brokerv1beta1.GetNamespaceProject(ns), nil
+}
+
+// Client returns the Pub/Sub client of the project, creating it if it doesn't exist.
+func (p *NamespaceProjects) Client(ctx context.Context, projectID string) (*pubsub.Client, error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	if client, ok := p.clients[projectID]; ok {
+		return client, nil
+	}
+	client, err := CreatePubsubClientFn(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[projectID] = client
+	return client, nil
+}
+
+// tenantProjectID returns the project of the Pub/Sub resources of the object: the project
+// recorded in its status when they were created if any, or else the project annotated on its
+// namespace, or else the default project. It also returns true if the project is not the default
+// project, in which case its client is cached by p. p may be nil, in which case all the resources
+// are in the default project.
+func tenantProjectID(p *NamespaceProjects, obj runtime.Object, statusProjectID, defaultProjectID string) (string, bool, error) {
+	defaultProjectID, err := utils.ProjectIDOrDefault(defaultProjectID)
+	if err != nil {
+		return "", false, err
+	}
+	if p == nil {
+		return defaultProjectID, false, nil
+	}
+	projectID := statusProjectID
+	if projectID == "" {
+		if projectID, err = p.ProjectID(obj); err != nil {
+			return "", false, err
+		}
+	}
+	if projectID == "" || projectID == defaultProjectID {
+		return defaultProjectID, false, nil
+	}
+	return projectID, true, nil
+}
@@ -18,19 +18,15 @@ package celltenant
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 
 	"k8s.io/client-go/tools/record"
 
-	"github.com/rickb777/date/period"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/google/knative-gcp/pkg/logging"
-	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
@@ -38,13 +34,6 @@ import (
 	"github.com/google/knative-gcp/pkg/utils"
 )
 
-const (
-	defaultMinimumBackoff = 1 * time.Second
-	// Default maximum backoff duration used in the backoff retry policy for
-	// pubsub subscriptions. 600 seconds is the longest supported time.
-	defaultMaximumBackoff = 600 * time.Second
-)
-
 // TargetReconciler implements controller.Reconciler for CellTenant Targets.
 type TargetReconciler struct {
 	ProjectID string
@@ -52,6 +41,10 @@ type TargetReconciler struct {
 	// pubsubClient is used as the Pubsub client when present.
 	PubsubClient *pubsub.Client
 
+	// Projects resolves the projects annotated on the namespaces of the targets. If nil, the
+	// resources of all targets are in ProjectID.
+	Projects *NamespaceProjects
+
 	DataresidencyStore *dataresidency.Store
 
 	// ClusterRegion is the region where GKE is running.
@@ -61,9 +54,9 @@ type TargetReconciler struct {
 func (r *TargetReconciler) ReconcileRetryTopicAndSubscription(ctx context.Context, recorder record.EventRecorder, t Target) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Reconciling retry topic")
-	// get ProjectID from metadata
+	// get ProjectID from the status, the namespace or metadata
 	//TODO get from context
-	projectID, err := utils.ProjectIDOrDefault(r.ProjectID)
+	projectID, namespaceProject, err := tenantProjectID(r.Projects, t.Object(), t.GetStatusProjectID(), r.ProjectID)
 	if err != nil {
 		logger.Error("Failed to find project id", zap.Error(err))
 		t.StatusUpdater().MarkTopicUnknown("ProjectIdNotFound", "Failed to find project id: %v", err)
@@ -78,7 +71,7 @@ func (r *TargetReconciler) ReconcileRetryTopicAndSubscription(ctx context.Contex
 		return err
 	}
 
-	client, err := r.getClientOrCreateNew(ctx, projectID, t.StatusUpdater())
+	client, err := r.getClientOrCreateNew(ctx, projectID, namespaceProject, t.StatusUpdater())
 	if err != nil {
 		logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 		return err
@@ -102,8 +95,8 @@ func (r *TargetReconciler) ReconcileRetryTopicAndSubscription(ctx context.Contex
 	//TODO uncomment when eventing webhook allows this
 	//trig.Status.TopicID = topic.ID()
 
-	retryPolicy := getPubsubRetryPolicy(ctx, t.DeliverySpec())
-	deadLetterPolicy := getPubsubDeadLetterPolicy(projectID, t.DeliverySpec())
+	retryPolicy := reconcilerutilspubsub.RetryPolicy(ctx, t.DeliverySpec())
+	deadLetterPolicy := reconcilerutilspubsub.DeadLetterPolicy(projectID, t.DeliverySpec())
 
 	// Check if PullSub exists, and if not, create it.
 	subID := t.GetSubscriptionName()
@@ -112,6 +105,7 @@ func (r *TargetReconciler) ReconcileRetryTopicAndSubscription(ctx context.Contex
 		Labels:           t.GetLabels(),
 		RetryPolicy:      retryPolicy,
 		DeadLetterPolicy: deadLetterPolicy,
+		Filter:           t.GetSubscriptionFilter(),
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
@@ -126,77 +120,13 @@ func (r *TargetReconciler) ReconcileRetryTopicAndSubscription(ctx context.Contex
 	return nil
 }
 
-// getPubsubRetryPolicy gets the eventing retry policy from the Broker delivery
-// spec and translates it to a pubsub retry policy.
-func getPubsubRetryPolicy(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *pubsub.RetryPolicy {
-	if spec == nil {
-		return &pubsub.RetryPolicy{
-			MinimumBackoff: defaultMinimumBackoff,
-			MaximumBackoff: defaultMaximumBackoff,
-		}
-	}
-	// The Broker delivery spec is translated to a pubsub retry policy in the
-	// manner defined in the following post:
-	// https://github.com/google/knative-gcp/issues/1392#issuecomment-655617873
-
-	var minimumBackoff time.Duration
-	if spec.BackoffDelay != nil {
-		p, err := period.Parse(*spec.BackoffDelay)
-		if err != nil {
-			// Not actually fatal, we will just use zero, rather than the stored value. But log an
-			// error so that we are aware of the issue.
-			logging.FromContext(ctx).Error("Unable to parse DeliverySpec.BackoffDelay",
-				zap.Error(err), zap.Stringp("backoffDelay", spec.BackoffDelay))
-		} else {
-			minimumBackoff, _ = p.Duration()
-		}
-	}
-
-	var backoffPolicy eventingduckv1beta1.BackoffPolicyType
-	if spec.BackoffPolicy != nil {
-		backoffPolicy = *spec.BackoffPolicy
-	} else {
-		// Default to Exponential.
-		backoffPolicy = eventingduckv1beta1.BackoffPolicyExponential
-	}
-
-	var maximumBackoff time.Duration
-	switch backoffPolicy {
-	case eventingduckv1beta1.BackoffPolicyLinear:
-		maximumBackoff = minimumBackoff
-	case eventingduckv1beta1.BackoffPolicyExponential:
-		maximumBackoff = defaultMaximumBackoff
-	}
-	return &pubsub.RetryPolicy{
-		MinimumBackoff: minimumBackoff,
-		MaximumBackoff: maximumBackoff,
-	}
-}
-
-// getPubsubDeadLetterPolicy gets the eventing dead letter policy from the
-// Broker delivery spec and translates it to a pubsub dead letter policy.
-func getPubsubDeadLetterPolicy(projectID string, spec *eventingduckv1beta1.DeliverySpec) *pubsub.DeadLetterPolicy {
-	if spec == nil || spec.DeadLetterSink == nil {
-		return nil
-	}
-	// Translate to the pubsub dead letter policy format.
-
-	dlp := &pubsub.DeadLetterPolicy{
-		DeadLetterTopic: fmt.Sprintf("projects/%s/topics/%s", projectID, spec.DeadLetterSink.URI.Host),
-	}
-	if spec.Retry != nil {
-		dlp.MaxDeliveryAttempts = int(*spec.Retry)
-	}
-	return dlp
-}
-
 func (r *TargetReconciler) DeleteRetryTopicAndSubscription(ctx context.Context, recorder record.EventRecorder, t Target) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Deleting retry topic")
 
-	// get ProjectID from metadata
+	// get ProjectID from the status, the namespace or metadata
 	//TODO get from context
-	projectID, err := utils.ProjectIDOrDefault(r.ProjectID)
+	projectID, namespaceProject, err := tenantProjectID(r.Projects, t.Object(), t.GetStatusProjectID(), r.ProjectID)
 	if err != nil {
 		logger.Error("Failed to find project id", zap.Error(err))
 		t.StatusUpdater().MarkTopicUnknown("FinalizeTopicProjectIdNotFound", "Failed to find project id: %v", err)
@@ -204,7 +134,7 @@ func (r *TargetReconciler) DeleteRetryTopicAndSubscription(ctx context.Context,
 		return err
 	}
 
-	client, err := r.getClientOrCreateNew(ctx, projectID, t.StatusUpdater())
+	client, err := r.getClientOrCreateNew(ctx, projectID, namespaceProject, t.StatusUpdater())
 	if err != nil {
 		logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 		return err
@@ -222,8 +152,18 @@ func (r *TargetReconciler) DeleteRetryTopicAndSubscription(ctx context.Context,
 }
 
 // getClientOrCreateNew Return the pubsubCient if it is valid, otherwise it tries to create a new client
-// and register it for later usage.
-func (r *TargetReconciler) getClientOrCreateNew(ctx context.Context, projectID string, b reconcilerutilspubsub.StatusUpdater) (*pubsub.Client, error) {
+// and register it for later usage. If namespaceProject is true, the client of the project annotated
+// on the namespace is returned instead.
+func (r *TargetReconciler) getClientOrCreateNew(ctx context.Context, projectID string, namespaceProject bool, b reconcilerutilspubsub.StatusUpdater) (*pubsub.Client, error) {
+	if namespaceProject {
+		client, err := r.Projects.Client(ctx, projectID)
+		if err != nil {
+			b.MarkTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client of project %q: %v", projectID, err)
+			b.MarkSubscriptionUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client of project %q: %v", projectID, err)
+			return nil, err
+		}
+		return client, nil
+	}
 	if r.PubsubClient != nil {
 		return r.PubsubClient, nil
 	}
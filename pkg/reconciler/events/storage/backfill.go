@@ -0,0 +1,117 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+
+	. "cloud.google.com/go/storage"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+	"github.com/google/knative-gcp/pkg/reconciler/events/storage/resources"
+)
+
+// backfillPageSize is the number of objects listed by each reconciliation of a backfill.
+const backfillPageSize = 500
+
+// reconcileBackfill backfills the next page of the existing objects selected
+// by the backfill spec. It publishes the notification of each object to the
+// topic of the source, so that the events go through the same subscription and
+// conversion as the notified ones, and moves the cursor of the status past the
+// page. Updating the status enqueues the source again, until the last page.
+func (r *Reconciler) reconcileBackfill(ctx context.Context, storage *v1.CloudStorageSource) error {
+	backfill := storage.Spec.Backfill
+	if storage.Status.Backfill == nil {
+		now := metav1.NewTime(r.clock.Now())
+		storage.Status.Backfill = &v1.CloudStorageBackfillStatus{StartTime: &now}
+	}
+	status := storage.Status.Backfill
+	if status.CompletionTime != nil {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudStorageSource client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	prefix := backfill.ObjectNamePrefix
+	if prefix == "" {
+		prefix = storage.Spec.ObjectNamePrefix
+	}
+	it := client.Bucket(storage.Spec.Bucket).Objects(ctx, &Query{Prefix: prefix})
+	var objects []*ObjectAttrs
+	cursor, err := iterator.NewPager(it, backfillPageSize, status.Cursor).NextPage(&objects)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to list objects", zap.String("bucketName", storage.Spec.Bucket), zap.String("cursor", status.Cursor), zap.Error(err))
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	pubsubClient, err := r.pubsubClientProvider(ctx, storage.Status.ProjectID)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create PubSub client", zap.Error(err))
+		return err
+	}
+	defer pubsubClient.Close()
+	topic := pubsubClient.Topic(storage.Status.TopicID)
+	defer topic.Stop()
+
+	// The objects created after the backfill started are notified.
+	createdBefore := status.StartTime.Time
+	if backfill.CreatedBefore != nil {
+		createdBefore = backfill.CreatedBefore.Time
+	}
+	notificationConfig := resources.NotificationConfigName(storage.Spec.Bucket, storage.Status.NotificationID)
+	var results []gpubsub.PublishResult
+	for _, attrs := range objects {
+		if (backfill.CreatedAfter != nil && attrs.Created.Before(backfill.CreatedAfter.Time)) || !attrs.Created.Before(createdBefore) {
+			continue
+		}
+		msg, err := resources.MakeObjectFinalizedMessage(attrs, notificationConfig)
+		if err != nil {
+			return fmt.Errorf("failed to make the notification of object %q: %w", attrs.Name, err)
+		}
+		results = append(results, topic.Publish(ctx, msg))
+	}
+	// The page is backfilled again if any publish fails, events are delivered at least once.
+	for _, result := range results {
+		if _, err := result.Get(ctx); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to publish object notification", zap.String("topic", storage.Status.TopicID), zap.Error(err))
+			return fmt.Errorf("failed to publish object notifications: %w", err)
+		}
+	}
+
+	status.Cursor = cursor
+	status.ObjectsListed += int64(len(objects))
+	status.EventsPublished += int64(len(results))
+	if cursor == "" {
+		now := metav1.NewTime(r.clock.Now())
+		status.CompletionTime = &now
+		r.Recorder.Eventf(storage, corev1.EventTypeNormal, backfillCompleted, "Backfill completed with %d events for %d objects", status.EventsPublished, status.ObjectsListed)
+	}
+	return nil
+}
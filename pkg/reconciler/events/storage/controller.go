@@ -22,6 +22,7 @@ import (
 	"knative.dev/pkg/injection"
 
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/tools/cache"
 	serviceaccountinformers "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount"
 	"knative.dev/pkg/configmap"
@@ -33,6 +34,7 @@ import (
 	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/pullsubscription"
 	topicinformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/topic"
 	cloudstoragesourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudstoragesource"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
 	gstorage "github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
@@ -80,9 +82,11 @@ func newController(
 				ReceiveAdapterType:  string(converters.CloudStorage),
 				ConfigWatcher:       cmw,
 			}),
-		Identity:       identity.NewIdentity(ctx, ipm, gcpas),
-		storageLister:  cloudstoragesourceInformer.Lister(),
-		createClientFn: gstorage.NewClient,
+		Identity:             identity.NewIdentity(ctx, ipm, gcpas),
+		storageLister:        cloudstoragesourceInformer.Lister(),
+		createClientFn:       gstorage.NewClient,
+		pubsubClientProvider: gpubsub.NewClient,
+		clock:                clock.RealClock{},
 	}
 	impl := cloudstoragesourcereconciler.NewImpl(ctx, r)
 
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/storage"
+	raw "google.golang.org/api/storage/v1"
+)
+
+const (
+	// objectFinalize is the Cloud Storage notification event type of finalized objects.
+	objectFinalize = "OBJECT_FINALIZE"
+	// jsonAPIV1 is the payload format of the Cloud Storage notifications created by
+	// the CloudStorageSource, i.e. storage.JSONPayload.
+	jsonAPIV1 = "JSON_API_V1"
+)
+
+// NotificationConfigName returns the name of a bucket notification, as set in
+// the notificationConfig attribute of its messages.
+func NotificationConfigName(bucket, notificationID string) string {
+	return fmt.Sprintf("projects/_/buckets/%s/notificationConfigs/%s", bucket, notificationID)
+}
+
+// MakeObjectFinalizedMessage makes the Pub/Sub message of the notification
+// that Cloud Storage publishes when the object is finalized, so that the
+// backfilled objects are converted to the same events as the notified ones.
+func MakeObjectFinalizedMessage(attrs *storage.ObjectAttrs, notificationConfig string) (*pubsub.Message, error) {
+	data, err := json.Marshal(toRawObject(attrs))
+	if err != nil {
+		return nil, err
+	}
+	return &pubsub.Message{
+		Data: data,
+		Attributes: map[string]string{
+			"notificationConfig": notificationConfig,
+			"eventType":          objectFinalize,
+			"payloadFormat":      jsonAPIV1,
+			"bucketId":           attrs.Bucket,
+			"objectId":           attrs.Name,
+			"objectGeneration":   strconv.FormatInt(attrs.Generation, 10),
+			"eventTime":          formatTime(attrs.Created),
+		},
+	}, nil
+}
+
+// toRawObject converts the attributes of an object to its JSON API resource,
+// the payload of the JSON_API_V1 notifications.
+func toRawObject(attrs *storage.ObjectAttrs) *raw.Object {
+	o := &raw.Object{
+		Kind:                    "storage#object",
+		Id:                      fmt.Sprintf("%s/%s/%d", attrs.Bucket, attrs.Name, attrs.Generation),
+		Bucket:                  attrs.Bucket,
+		Name:                    attrs.Name,
+		Generation:              attrs.Generation,
+		Metageneration:          attrs.Metageneration,
+		ContentType:             attrs.ContentType,
+		ContentEncoding:         attrs.ContentEncoding,
+		ContentLanguage:         attrs.ContentLanguage,
+		ContentDisposition:      attrs.ContentDisposition,
+		CacheControl:            attrs.CacheControl,
+		StorageClass:            attrs.StorageClass,
+		Size:                    uint64(attrs.Size),
+		MediaLink:               attrs.MediaLink,
+		Metadata:                attrs.Metadata,
+		Etag:                    attrs.Etag,
+		KmsKeyName:              attrs.KMSKeyName,
+		EventBasedHold:          attrs.EventBasedHold,
+		TemporaryHold:           attrs.TemporaryHold,
+		TimeCreated:             formatTime(attrs.Created),
+		Updated:                 formatTime(attrs.Updated),
+		RetentionExpirationTime: formatTime(attrs.RetentionExpirationTime),
+	}
+	if len(attrs.MD5) > 0 {
+		o.Md5Hash = base64.StdEncoding.EncodeToString(attrs.MD5)
+	}
+	if attrs.CRC32C != 0 {
+		crc := make([]byte, 4)
+		binary.BigEndian.PutUint32(crc, attrs.CRC32C)
+		o.Crc32c = base64.StdEncoding.EncodeToString(crc)
+	}
+	return o
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
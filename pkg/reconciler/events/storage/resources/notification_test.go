@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+func TestMakeObjectFinalizedMessage(t *testing.T) {
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	attrs := &storage.ObjectAttrs{
+		Bucket:         "my-bucket",
+		Name:           "logs/a.txt",
+		ContentType:    "text/plain",
+		Size:           42,
+		MD5:            []byte{0x01, 0x02},
+		CRC32C:         0x0a0b0c0d,
+		Generation:     1614834367000000,
+		Metageneration: 1,
+		StorageClass:   "STANDARD",
+		Metadata:       map[string]string{"owner": "me"},
+		Created:        created,
+		Updated:        created,
+	}
+
+	msg, err := MakeObjectFinalizedMessage(attrs, NotificationConfigName("my-bucket", "7"))
+	if err != nil {
+		t.Fatalf("MakeObjectFinalizedMessage() = %v", err)
+	}
+
+	wantAttributes := map[string]string{
+		"notificationConfig": "projects/_/buckets/my-bucket/notificationConfigs/7",
+		"eventType":          "OBJECT_FINALIZE",
+		"payloadFormat":      "JSON_API_V1",
+		"bucketId":           "my-bucket",
+		"objectId":           "logs/a.txt",
+		"objectGeneration":   "1614834367000000",
+		"eventTime":          "2021-03-04T05:06:07Z",
+	}
+	if diff := cmp.Diff(wantAttributes, msg.Attributes); diff != "" {
+		t.Errorf("unexpected attributes (-want, +got) = %v", diff)
+	}
+
+	var gotData map[string]interface{}
+	if err := json.Unmarshal(msg.Data, &gotData); err != nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+	wantData := map[string]interface{}{
+		"kind":           "storage#object",
+		"id":             "my-bucket/logs/a.txt/1614834367000000",
+		"bucket":         "my-bucket",
+		"name":           "logs/a.txt",
+		"contentType":    "text/plain",
+		"size":           "42",
+		"md5Hash":        "AQI=",
+		"crc32c":         "CgsMDQ==",
+		"generation":     "1614834367000000",
+		"metageneration": "1",
+		"storageClass":   "STANDARD",
+		"metadata":       map[string]interface{}{"owner": "me"},
+		"timeCreated":    "2021-03-04T05:06:07Z",
+		"updated":        "2021-03-04T05:06:07Z",
+	}
+	if diff := cmp.Diff(wantData, gotData); diff != "" {
+		t.Errorf("unexpected data (-want, +got) = %v", diff)
+	}
+
+	// The message must be converted like the notifications.
+	msg.ID = "123"
+	msg.PublishTime = created
+	event, err := converters.NewPubSubConverter().Convert(context.Background(), msg, converters.CloudStorage)
+	if err != nil {
+		t.Fatalf("Convert() = %v", err)
+	}
+	if got, want := event.Type(), schemasv1.CloudStorageObjectFinalizedEventType; got != want {
+		t.Errorf("event type got=%s, want=%s", got, want)
+	}
+	if got, want := event.Source(), schemasv1.CloudStorageEventSource("my-bucket"); got != want {
+		t.Errorf("event source got=%s, want=%s", got, want)
+	}
+	if got, want := event.Subject(), schemasv1.CloudStorageEventSubject("logs/a.txt"); got != want {
+		t.Errorf("event subject got=%s, want=%s", got, want)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/reconciler"
 
@@ -32,6 +33,7 @@ import (
 	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	cloudstoragesourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudstoragesource"
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
 	gstorage "github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
@@ -43,9 +45,11 @@ import (
 const (
 	resourceGroup = "cloudstoragesources.events.cloud.google.com"
 
+	backfillCompleted            = "BackfillCompleted"
 	deleteNotificationFailed     = "NotificationDeleteFailed"
 	deletePubSubFailed           = "PubSubDeleteFailed"
 	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	reconciledBackfillFailed     = "BackfillReconcileFailed"
 	reconciledNotificationFailed = "NotificationReconcileFailed"
 	reconciledPubSubFailed       = "PubSubReconcileFailed"
 	reconciledSuccessReason      = "CloudStorageSourceReconciled"
@@ -74,6 +78,13 @@ type Reconciler struct {
 	// createClientFn is the function used to create the Storage client that interacts with GCS.
 	// This is needed so that we can inject a mock client for UTs purposes.
 	createClientFn gstorage.CreateFn
+
+	// pubsubClientProvider is the function used to create the Pub/Sub client that publishes
+	// the notifications of the backfilled objects.
+	pubsubClientProvider gpubsub.CreateFn
+
+	// clock is used to record the backfill times.
+	clock clock.Clock
 }
 
 // Check that our Reconciler implements Interface.
@@ -105,6 +116,12 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, storage *v1.CloudStorage
 	}
 	storage.Status.MarkNotificationReady(notification)
 
+	if storage.Spec.Backfill != nil {
+		if err := r.reconcileBackfill(ctx, storage); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, reconciledBackfillFailed, "Failed to backfill CloudStorageSource objects: %s", err.Error())
+		}
+	}
+
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, storage.Namespace, storage.Name)
 }
 
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
 
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -48,6 +50,7 @@ import (
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudstoragesource"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
 	gstorage "github.com/google/knative-gcp/pkg/gclient/storage/testing"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
@@ -100,6 +103,8 @@ var (
 	}
 
 	gServiceAccount = "test123@test123.iam.gserviceaccount.com"
+
+	backfillStart = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
 )
 
 func init() {
@@ -132,6 +137,24 @@ func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActi
 	return action
 }
 
+// newBackfillObjects returns the objects "object-000", "object-001", ... created at the given times.
+func newBackfillObjects(created ...time.Time) *gstorage.TestObjects {
+	objects := gstorage.NewTestObjects()
+	for i, c := range created {
+		objects.Put(fmt.Sprintf("object-%03d", i), gstorage.TestObject{Created: c})
+	}
+	return objects
+}
+
+// backfillObjectTimes returns n times to create backfill objects at.
+func backfillObjectTimes(n int, created time.Time) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = created
+	}
+	return times
+}
+
 func newSink() *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -1062,6 +1085,294 @@ func TestAllCases(t *testing.T) {
 				),
 			}},
 		},
+		func() TableRow {
+			published := &gpubsub.TestMessages{}
+			return TableRow{
+				Name: "backfill publishes the objects in the time window",
+				Objects: []runtime.Object{
+					reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+						reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+						reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+						reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+						reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+						reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+						reconcilertestingv1.WithCloudStorageSourceBackfill(&storagev1.CloudStorageBackfill{
+							CreatedAfter: &metav1.Time{Time: backfillStart.Add(-time.Hour)},
+						}),
+						reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+					),
+					reconcilertestingv1.NewTopic(storageName, testNS,
+						reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+							Topic:             testTopicID,
+							PropagationPolicy: "CreateDelete",
+							Project:           testProject,
+							EnablePublisher:   &falseVal,
+						}),
+						reconcilertestingv1.WithTopicReady(testTopicID),
+						reconcilertestingv1.WithTopicAddress(testTopicURI),
+						reconcilertestingv1.WithTopicProjectID(testProject),
+						reconcilertestingv1.WithTopicSetDefaults,
+					),
+					reconcilertestingv1.NewPullSubscription(storageName, testNS,
+						reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+							Topic: testTopicID,
+							PubSubSpec: gcpduckv1.PubSubSpec{
+								Project: testProject,
+								Secret:  &secret,
+								SourceSpec: duckv1.SourceSpec{
+									Sink: newSinkDestination(),
+								},
+							},
+							AdapterType: string(converters.CloudStorage),
+						}),
+						reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+					),
+					newSink(),
+				},
+				Key: testNS + "/" + storageName,
+				OtherTestData: map[string]interface{}{
+					"storage": gstorage.TestClientData{
+						BucketData: gstorage.TestBucketData{
+							AddNotificationID: notificationId,
+							Objects: newBackfillObjects(
+								backfillStart.Add(-2*time.Hour),
+								backfillStart.Add(-time.Minute),
+								backfillStart.Add(time.Minute)),
+						},
+					},
+					"pubsub": gpubsub.TestClientData{
+						TopicData: gpubsub.TestTopicData{
+							Published: published,
+						},
+					},
+				},
+				WantEvents: []string{
+					Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+					Eventf(corev1.EventTypeNormal, backfillCompleted, "Backfill completed with 1 events for 3 objects"),
+					Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, testNS, storageName),
+				},
+				WantPatches: []clientgotesting.PatchActionImpl{
+					patchFinalizers(testNS, storageName, true),
+				},
+				WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+					Object: reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+						reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+						reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+						reconcilertestingv1.WithCloudStorageSourceStatusObservedGeneration(generation),
+						reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+						reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+						reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+						reconcilertestingv1.WithCloudStorageSourceBackfill(&storagev1.CloudStorageBackfill{
+							CreatedAfter: &metav1.Time{Time: backfillStart.Add(-time.Hour)},
+						}),
+						reconcilertestingv1.WithInitCloudStorageSourceConditions,
+						reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+						reconcilertestingv1.WithCloudStorageSourceTopicReady(testTopicID),
+						reconcilertestingv1.WithCloudStorageSourceProjectID(testProject),
+						reconcilertestingv1.WithCloudStorageSourcePullSubscriptionReady,
+						reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+						reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
+						reconcilertestingv1.WithCloudStorageSourceNotificationReady(notificationId),
+						reconcilertestingv1.WithCloudStorageSourceBackfillStatus(&storagev1.CloudStorageBackfillStatus{
+							StartTime:       &metav1.Time{Time: backfillStart},
+							CompletionTime:  &metav1.Time{Time: backfillStart},
+							ObjectsListed:   3,
+							EventsPublished: 1,
+						}),
+						reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+					),
+				}},
+				PostConditions: []func(*testing.T, *TableRow){
+					func(t *testing.T, _ *TableRow) {
+						msgs := published.Messages()
+						if len(msgs) != 1 {
+							t.Fatalf("got %d published messages, want 1", len(msgs))
+						}
+						if got, want := msgs[0].Attributes["objectId"], "object-001"; got != want {
+							t.Errorf("published objectId got=%s, want=%s", got, want)
+						}
+					},
+				},
+			}
+		}(),
+		{
+			Name: "backfill resumes from the cursor",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithCloudStorageSourceBackfill(&storagev1.CloudStorageBackfill{}),
+					reconcilertestingv1.WithCloudStorageSourceBackfillStatus(&storagev1.CloudStorageBackfillStatus{
+						StartTime:       &metav1.Time{Time: backfillStart},
+						Cursor:          "500",
+						ObjectsListed:   500,
+						EventsPublished: 500,
+					}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(storageName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(storageName, testNS,
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Project: testProject,
+							Secret:  &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+						},
+						AdapterType: string(converters.CloudStorage),
+					}),
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+				),
+				newSink(),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					BucketData: gstorage.TestBucketData{
+						AddNotificationID: notificationId,
+						Objects:           newBackfillObjects(backfillObjectTimes(502, backfillStart.Add(-time.Hour))...),
+					},
+				},
+				"pubsub": gpubsub.TestClientData{
+					TopicData: gpubsub.TestTopicData{},
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+				Eventf(corev1.EventTypeNormal, backfillCompleted, "Backfill completed with 502 events for 502 objects"),
+				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, testNS, storageName),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, storageName, true),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceStatusObservedGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithCloudStorageSourceBackfill(&storagev1.CloudStorageBackfill{}),
+					reconcilertestingv1.WithInitCloudStorageSourceConditions,
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceTopicReady(testTopicID),
+					reconcilertestingv1.WithCloudStorageSourceProjectID(testProject),
+					reconcilertestingv1.WithCloudStorageSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
+					reconcilertestingv1.WithCloudStorageSourceNotificationReady(notificationId),
+					reconcilertestingv1.WithCloudStorageSourceBackfillStatus(&storagev1.CloudStorageBackfillStatus{
+						StartTime:       &metav1.Time{Time: backfillStart},
+						CompletionTime:  &metav1.Time{Time: backfillStart},
+						ObjectsListed:   502,
+						EventsPublished: 502,
+					}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+			}},
+		},
+		{
+			Name: "backfill publish fails",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithCloudStorageSourceBackfill(&storagev1.CloudStorageBackfill{}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(storageName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(storageName, testNS,
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Project: testProject,
+							Secret:  &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+						},
+						AdapterType: string(converters.CloudStorage),
+					}),
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+				),
+				newSink(),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					BucketData: gstorage.TestBucketData{
+						AddNotificationID: notificationId,
+						Objects:           newBackfillObjects(backfillObjectTimes(501, backfillStart.Add(-time.Hour))...),
+					},
+				},
+				"pubsub": gpubsub.TestClientData{
+					TopicData: gpubsub.TestTopicData{
+						PublishErr: errors.New("publish failed"),
+					},
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+				Eventf(corev1.EventTypeWarning, reconciledBackfillFailed, "Failed to backfill CloudStorageSource objects: failed to publish object notifications: publish failed"),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, storageName, true),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+					reconcilertestingv1.WithCloudStorageSourceProject(testProject),
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceStatusObservedGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+					reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudStorageSourceEventTypes([]string{schemasv1.CloudStorageObjectFinalizedEventType}),
+					reconcilertestingv1.WithCloudStorageSourceBackfill(&storagev1.CloudStorageBackfill{}),
+					reconcilertestingv1.WithInitCloudStorageSourceConditions,
+					reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+					reconcilertestingv1.WithCloudStorageSourceTopicReady(testTopicID),
+					reconcilertestingv1.WithCloudStorageSourceProjectID(testProject),
+					reconcilertestingv1.WithCloudStorageSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudStorageSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudStorageSourceSinkURI(storageSinkURL),
+					reconcilertestingv1.WithCloudStorageSourceNotificationReady(notificationId),
+					reconcilertestingv1.WithCloudStorageSourceBackfillStatus(&storagev1.CloudStorageBackfillStatus{
+						StartTime: &metav1.Time{Time: backfillStart},
+					}),
+					reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				),
+			}},
+		},
 		{
 			Name: "delete fails with non grpc error",
 			Objects: []runtime.Object{
@@ -1316,9 +1627,11 @@ func TestAllCases(t *testing.T) {
 					ReceiveAdapterType:  string(converters.CloudStorage),
 					ConfigWatcher:       cmw,
 				}),
-			Identity:       identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
-			storageLister:  listers.GetCloudStorageSourceLister(),
-			createClientFn: gstorage.TestClientCreator(testData["storage"]),
+			Identity:             identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			storageLister:        listers.GetCloudStorageSourceLister(),
+			createClientFn:       gstorage.TestClientCreator(testData["storage"]),
+			pubsubClientProvider: gpubsub.TestClientCreator(testData["pubsub"]),
+			clock:                clock.NewFakeClock(backfillStart),
 		}
 		return cloudstoragesource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudStorageSourceLister(), r.Recorder, r)
 	}))
@@ -1224,6 +1224,7 @@ func TestAllCases(t *testing.T) {
 			Base: &psreconciler.Base{
 				Base:                   reconciler.NewBase(ctx, controllerAgentName, cmw),
 				DeploymentLister:       listers.GetDeploymentLister(),
+				ConfigMapLister:        listers.GetConfigMapLister(),
 				PullSubscriptionLister: listers.GetPullSubscriptionLister(),
 				UriResolver:            resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 				ReceiveAdapterImage:    testImage,
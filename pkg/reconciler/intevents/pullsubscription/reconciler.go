@@ -26,6 +26,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
@@ -42,6 +43,7 @@ import (
 	"knative.dev/pkg/resolver"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	v1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	listers "github.com/google/knative-gcp/pkg/client/listers/intevents/v1"
 	"github.com/google/knative-gcp/pkg/reconciler"
@@ -79,6 +81,8 @@ type Base struct {
 	PullSubscriptionLister listers.PullSubscriptionLister
 	// serviceAccountLister for reading serviceAccounts.
 	ServiceAccountLister corev1listers.ServiceAccountLister
+	// ConfigMapLister for reading the shared receive adapter config.
+	ConfigMapLister corev1listers.ConfigMapLister
 
 	UriResolver *resolver.URIResolver
 
@@ -138,6 +142,19 @@ func (r *Base) ReconcileKind(ctx context.Context, ps *v1.PullSubscription) pkgre
 		ps.Status.TransformerURI = nil
 	}
 
+	// Dead letter sink is optional. Pub/Sub topics are handled by the dead letter policy of the
+	// subscription, any other dead letter sink is handled by the receive adapter.
+	if ps.Spec.Delivery != nil && ps.Spec.Delivery.DeadLetterSink != nil && !brokerv1beta1.IsPubSubDeadLetterSink(ps.Spec.Delivery.DeadLetterSink) {
+		deadLetterSinkURI, err := r.resolveDestination(ctx, *ps.Spec.Delivery.DeadLetterSink, ps)
+		if err != nil {
+			ps.Status.MarkNoDeadLetterSink("InvalidDeadLetterSink", err.Error())
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, "InvalidDeadLetterSink", "InvalidDeadLetterSink: %s", err.Error())
+		}
+		ps.Status.MarkDeadLetterSink(deadLetterSinkURI)
+	} else {
+		ps.Status.ClearDeadLetterSink()
+	}
+
 	subscriptionID, err := r.reconcileSubscription(ctx, ps)
 	if err != nil {
 		ps.Status.MarkNoSubscription(reconciledPubSubFailedReason, "Failed to reconcile Pub/Sub subscription: %s", err.Error())
@@ -145,7 +162,11 @@ func (r *Base) ReconcileKind(ctx context.Context, ps *v1.PullSubscription) pkgre
 	}
 	ps.Status.MarkSubscribed(subscriptionID)
 
-	err = r.reconcileDataPlaneResources(ctx, ps, r.ReconcileDataPlaneFn)
+	if usesSharedReceiveAdapter(ps) {
+		err = r.reconcileSharedReceiveAdapter(ctx, ps)
+	} else if err = r.removeFromSharedReceiveAdapter(ctx, ps); err == nil {
+		err = r.reconcileDataPlaneResources(ctx, ps, r.ReconcileDataPlaneFn)
+	}
 	if err != nil {
 		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledDataPlaneFailedReason, "Failed to reconcile Data Plane resource(s): %s", err.Error())
 	}
@@ -199,6 +220,7 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 	subConfig := pubsub.SubscriptionConfig{
 		Topic:               t,
 		RetainAckedMessages: ps.Spec.RetainAckedMessages,
+		Filter:              ps.Spec.Filter,
 	}
 
 	if ps.Spec.AckDeadline != nil {
@@ -219,6 +241,11 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 		subConfig.RetentionDuration = retentionDuration
 	}
 
+	if ps.Spec.Delivery != nil {
+		subConfig.RetryPolicy = reconcilerutilspubsub.RetryPolicy(ctx, ps.Spec.Delivery)
+		subConfig.DeadLetterPolicy = reconcilerutilspubsub.DeadLetterPolicy(ps.Status.ProjectID, ps.Spec.Delivery)
+	}
+
 	// Check if the topic of the subscription is "_deleted-topic_"
 	if subExists {
 		config, err := sub.Config(ctx)
@@ -239,6 +266,32 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 				logging.FromContext(ctx).Desugar().Error("Failed to create subscription", zap.Error(err))
 				return "", err
 			}
+		} else if config.Filter != subConfig.Filter {
+			// The filter of a subscription is immutable, so a new filter requires a new subscription.
+			logging.FromContext(ctx).Desugar().Info("Detected a new filter. Going to recreate the subscription.", zap.String("filter", subConfig.Filter))
+			sub, err = reconcilerutilspubsub.RecreateSubscription(ctx, client, sub, subConfig)
+			if err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to recreate subscription", zap.Error(err))
+				return "", err
+			}
+		} else if !equality.Semantic.DeepEqual(config.RetryPolicy, subConfig.RetryPolicy) ||
+			!equality.Semantic.DeepEqual(config.DeadLetterPolicy, subConfig.DeadLetterPolicy) {
+			// Update the subscription config in case the retry or dead letter policy changed. A nil policy
+			// doesn't change the subscription, so the empty policies remove the ones that are no longer set.
+			update := pubsub.SubscriptionConfigToUpdate{
+				RetryPolicy:      subConfig.RetryPolicy,
+				DeadLetterPolicy: subConfig.DeadLetterPolicy,
+			}
+			if update.RetryPolicy == nil {
+				update.RetryPolicy = &pubsub.RetryPolicy{}
+			}
+			if update.DeadLetterPolicy == nil {
+				update.DeadLetterPolicy = &pubsub.DeadLetterPolicy{}
+			}
+			if _, err := sub.Update(ctx, update); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to update subscription config", zap.Error(err))
+				return "", err
+			}
 		}
 	} else {
 		sub, err = client.CreateSubscription(ctx, subID, subConfig)
@@ -247,7 +300,7 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 			return "", err
 		}
 	}
-	// TODO update the subscription's config if needed.
+	// TODO update the rest of the subscription's config if needed.
 	return subID, nil
 }
 
@@ -321,16 +374,17 @@ func (r *Base) reconcileDataPlaneResources(ctx context.Context, ps *v1.PullSubsc
 	}
 
 	desired := resources.MakeReceiveAdapter(ctx, &resources.ReceiveAdapterArgs{
-		Image:            r.ReceiveAdapterImage,
-		PullSubscription: ps,
-		Labels:           resources.GetLabels(r.ControllerAgentName, ps.Name),
-		SubscriptionID:   ps.Status.SubscriptionID,
-		SinkURI:          ps.Status.SinkURI,
-		TransformerURI:   ps.Status.TransformerURI,
-		LoggingConfig:    loggingConfig,
-		MetricsConfig:    metricsConfig,
-		TracingConfig:    tracingConfig,
-		AuthType:         authType,
+		Image:             r.ReceiveAdapterImage,
+		PullSubscription:  ps,
+		Labels:            resources.GetLabels(r.ControllerAgentName, ps.Name),
+		SubscriptionID:    ps.Status.SubscriptionID,
+		SinkURI:           ps.Status.SinkURI,
+		TransformerURI:    ps.Status.TransformerURI,
+		DeadLetterSinkURI: ps.Status.DeadLetterSinkURI,
+		LoggingConfig:     loggingConfig,
+		MetricsConfig:     metricsConfig,
+		TracingConfig:     tracingConfig,
+		AuthType:          authType,
 	})
 
 	return f(ctx, desired, ps)
@@ -427,6 +481,10 @@ func (r *Base) FinalizeKind(ctx context.Context, ps *v1.PullSubscription) pkgrec
 		}
 	}
 
+	if err := r.removeFromSharedReceiveAdapter(ctx, ps); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledDataPlaneFailedReason, "Failed to remove from the shared Receive Adapter: %s", err.Error())
+	}
+
 	logging.FromContext(ctx).Desugar().Debug("Deleting Pub/Sub subscription")
 	if err := r.deleteSubscription(ctx, ps); err != nil {
 		return pkgreconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailedReason, "Failed to delete Pub/Sub subscription: %s", err.Error())
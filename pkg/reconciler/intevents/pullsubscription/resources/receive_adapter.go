@@ -18,7 +18,9 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -51,9 +53,12 @@ type ReceiveAdapterArgs struct {
 	SubscriptionID   string
 	SinkURI          *apis.URL
 	TransformerURI   *apis.URL
-	MetricsConfig    string
-	LoggingConfig    string
-	TracingConfig    string
+	// DeadLetterSinkURI is optional, unconvertible messages are dropped and
+	// rejected events retried without it.
+	DeadLetterSinkURI *apis.URL
+	MetricsConfig     string
+	LoggingConfig     string
+	TracingConfig     string
 	// There are three types: `secret`, `workload-identity-gsa` and `workload-identity`.
 	AuthType authcheck.AuthType
 }
@@ -65,6 +70,35 @@ const (
 	defaultResourceGroup = "pullsubscriptions.internal.events.cloud.google.com"
 )
 
+func getResourceGroup(ps *intereventsv1.PullSubscription) string {
+	if rg, ok := ps.Annotations["metrics-resource-group"]; ok {
+		return rg
+	}
+	return defaultResourceGroup
+}
+
+func getResourceName(ps *intereventsv1.PullSubscription) string {
+	// Needed for Channels, as we use a generate name for the PullSubscription.
+	if rn, ok := ps.Annotations["metrics-resource-name"]; ok {
+		return rn
+	}
+	return ps.Name
+}
+
+func getAdapterType(ps *intereventsv1.PullSubscription) string {
+	// If the PullSubscription has no Channel nor Source label, means that users created a PullSubscription manually.
+	// Then we set the adapter type to be PubSubPull.
+	_, isFromSource := ps.Labels[intevents.SourceLabelKey]
+	_, isFromChannel := ps.Labels[intevents.ChannelLabelKey]
+	if ps.Spec.Mapping != nil {
+		return string(converters.CloudPubSubMapping)
+	}
+	if !isFromSource && !isFromChannel {
+		return string(converters.PubSubPull)
+	}
+	return ps.Spec.AdapterType
+}
+
 func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *corev1.PodSpec {
 	// Convert CloudEvent Overrides to pod embeddable properties.
 	ceExtensions := ""
@@ -78,29 +112,15 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 		}
 	}
 
-	var resourceGroup = defaultResourceGroup
-	if rg, ok := args.PullSubscription.Annotations["metrics-resource-group"]; ok {
-		resourceGroup = rg
-	}
-	// Needed for Channels, as we use a generate name for the PullSubscription.
-	var resourceName = args.PullSubscription.Name
-	if rn, ok := args.PullSubscription.Annotations["metrics-resource-name"]; ok {
-		resourceName = rn
-	}
+	resourceGroup := getResourceGroup(args.PullSubscription)
+	resourceName := getResourceName(args.PullSubscription)
 
 	var transformerURI string
 	if args.TransformerURI != nil {
 		transformerURI = args.TransformerURI.String()
 	}
 
-	adapterType := args.PullSubscription.Spec.AdapterType
-	// If the PullSubscription has no Channel nor Source label, means that users created a PullSubscription manually.
-	// Then we set the adapter type to be PubSubPull.
-	_, isFromSource := args.PullSubscription.Labels[intevents.SourceLabelKey]
-	_, isFromChannel := args.PullSubscription.Labels[intevents.ChannelLabelKey]
-	if !isFromSource && !isFromChannel {
-		adapterType = string(converters.PubSubPull)
-	}
+	adapterType := getAdapterType(args.PullSubscription)
 
 	receiveAdapterContainer := corev1.Container{
 		Name:  "receive-adapter",
@@ -186,6 +206,31 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 		},
 	}
 
+	if args.DeadLetterSinkURI != nil {
+		receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+			Name:  "DEAD_LETTER_SINK_URI",
+			Value: args.DeadLetterSinkURI.String(),
+		})
+		if delivery := args.PullSubscription.Spec.Delivery; delivery != nil && delivery.Retry != nil {
+			receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+				Name:  "MAX_DELIVERY_ATTEMPTS",
+				Value: strconv.Itoa(int(*delivery.Retry)),
+			})
+		}
+	}
+
+	if args.PullSubscription.Spec.Mapping != nil {
+		mapping, err := json.Marshal(args.PullSubscription.Spec.Mapping)
+		if err != nil {
+			logging.FromContext(ctx).Warnw("failed to marshal the event mapping", zap.Error(err))
+		} else {
+			receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+				Name:  "EVENT_MAPPING",
+				Value: string(mapping),
+			})
+		}
+	}
+
 	// This is added purely for the TestCloudLogging E2E tests, which verify that the log line is
 	// written certain annotations are present.
 	receiveAdapterContainer.Env = testloggingutil.PropagateLoggingE2ETestAnnotation(
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	intereventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/shared"
+)
+
+// MakeSharedSubscription generates the entry of a PullSubscription in the shared receive
+// adapter config.
+func MakeSharedSubscription(ps *intereventsv1.PullSubscription) *shared.SubscriptionConfig {
+	sub := &shared.SubscriptionConfig{
+		Namespace:      ps.Namespace,
+		Name:           getResourceName(ps),
+		ResourceGroup:  getResourceGroup(ps),
+		ProjectID:      ps.Status.ProjectID,
+		TopicID:        ps.Spec.Topic,
+		SubscriptionID: ps.Status.SubscriptionID,
+		AdapterType:    getAdapterType(ps),
+		EventMapping:   ps.Spec.Mapping,
+	}
+	if ps.Status.SinkURI != nil {
+		sub.SinkURI = ps.Status.SinkURI.String()
+	}
+	if ps.Status.TransformerURI != nil {
+		sub.TransformerURI = ps.Status.TransformerURI.String()
+	}
+	if ps.Status.DeadLetterSinkURI != nil {
+		sub.DeadLetterSinkURI = ps.Status.DeadLetterSinkURI.String()
+		if ps.Spec.Delivery != nil && ps.Spec.Delivery.Retry != nil {
+			sub.MaxDeliveryAttempts = int(*ps.Spec.Delivery.Retry)
+		}
+	}
+	// Leave empty extensions unset, so that the entry compares equal to its serialized form.
+	if ps.Spec.CloudEventOverrides != nil && len(ps.Spec.CloudEventOverrides.Extensions) > 0 {
+		sub.Extensions = ps.Spec.CloudEventOverrides.Extensions
+	}
+	return sub
+}
+
+// MakeSharedReceiveAdapterConfigMap generates (but does not insert into K8s) the ConfigMap
+// holding a shard of the shared receive adapter config.
+func MakeSharedReceiveAdapterConfigMap(namespace, name string, cfg *shared.Config) (*corev1.ConfigMap, error) {
+	data, err := cfg.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("error serializing shared receive adapter config: %w", err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Data: map[string]string{shared.ConfigMapKey: string(data)},
+	}, nil
+}
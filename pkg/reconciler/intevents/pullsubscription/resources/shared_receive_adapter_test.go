@@ -0,0 +1,111 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+	intereventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/shared"
+)
+
+func TestMakeSharedSubscription(t *testing.T) {
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+			Labels: map[string]string{
+				intevents.SourceLabelKey: "my-source-name",
+			},
+			Annotations: map[string]string{
+				"metrics-resource-group": "storages.events.cloud.google.com",
+			},
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					CloudEventOverrides: &duckv1.CloudEventOverrides{
+						Extensions: map[string]string{"foo": "bar"},
+					},
+				},
+			},
+			Topic:       "topic",
+			AdapterType: string(converters.CloudStorage),
+		},
+		Status: intereventsv1.PullSubscriptionStatus{
+			PubSubStatus: gcpduckv1.PubSubStatus{
+				SinkURI:   apis.HTTP("sink"),
+				ProjectID: "project",
+			},
+			SubscriptionID: "subscription",
+		},
+	}
+
+	want := &shared.SubscriptionConfig{
+		Namespace:      "testnamespace",
+		Name:           "testname",
+		ResourceGroup:  "storages.events.cloud.google.com",
+		ProjectID:      "project",
+		TopicID:        "topic",
+		SubscriptionID: "subscription",
+		SinkURI:        "http://sink",
+		AdapterType:    string(converters.CloudStorage),
+		Extensions:     map[string]string{"foo": "bar"},
+	}
+	if diff := cmp.Diff(want, MakeSharedSubscription(ps)); diff != "" {
+		t.Errorf("unexpected subscription (-want, +got) = %v", diff)
+	}
+
+	// A manually created PullSubscription without overrides.
+	ps.Labels = nil
+	ps.Spec.CloudEventOverrides.Extensions = map[string]string{}
+	want.AdapterType = string(converters.PubSubPull)
+	want.Extensions = nil
+	if diff := cmp.Diff(want, MakeSharedSubscription(ps)); diff != "" {
+		t.Errorf("unexpected subscription of manual PullSubscription (-want, +got) = %v", diff)
+	}
+
+	// A PullSubscription with an event mapping.
+	ps.Spec.Mapping = &gcpduckv1.EventMapping{Type: "{{ .Attributes.type }}"}
+	want.AdapterType = string(converters.CloudPubSubMapping)
+	want.EventMapping = ps.Spec.Mapping
+	if diff := cmp.Diff(want, MakeSharedSubscription(ps)); diff != "" {
+		t.Errorf("unexpected subscription of mapped PullSubscription (-want, +got) = %v", diff)
+	}
+
+	// A PullSubscription with a dead letter sink handled by the receive adapter.
+	retry := int32(10)
+	ps.Spec.Delivery = &eventingduckv1beta1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter-sink")},
+		Retry:          &retry,
+	}
+	ps.Status.DeadLetterSinkURI = apis.HTTP("dead-letter-sink")
+	want.DeadLetterSinkURI = "http://dead-letter-sink"
+	want.MaxDeliveryAttempts = 10
+	if diff := cmp.Diff(want, MakeSharedSubscription(ps)); diff != "" {
+		t.Errorf("unexpected subscription of dead lettered PullSubscription (-want, +got) = %v", diff)
+	}
+}
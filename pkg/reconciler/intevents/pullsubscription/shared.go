@@ -0,0 +1,147 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	v1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/shared"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
+)
+
+// usesSharedReceiveAdapter returns true if the PullSubscription, usually through its source,
+// opted into the shared receive adapter.
+func usesSharedReceiveAdapter(ps *v1.PullSubscription) bool {
+	return ps.Annotations[duck.ReceiveAdapterAnnotation] == duck.SharedReceiveAdapter
+}
+
+// reconcileSharedReceiveAdapter adds the PullSubscription to the shared receive adapter config,
+// and deletes its dedicated receive adapter, if any.
+func (r *Base) reconcileSharedReceiveAdapter(ctx context.Context, ps *v1.PullSubscription) error {
+	if err := r.deleteReceiveAdapter(ctx, ps); err != nil {
+		ps.Status.MarkDeployedFailed("ReceiveAdapterDeleteFailed", "Error deleting the Receive Adapter: %s", err.Error())
+		return err
+	}
+
+	key := shared.Key(ps.Namespace, ps.Name)
+	desired := resources.MakeSharedSubscription(ps)
+	err := r.updateSharedReceiveAdapterConfig(ctx, key, func(cfg *shared.Config) bool {
+		if existing, ok := cfg.Subscriptions[key]; ok && equality.Semantic.DeepEqual(existing, desired) {
+			return false
+		}
+		if cfg.Subscriptions == nil {
+			cfg.Subscriptions = make(map[string]*shared.SubscriptionConfig)
+		}
+		cfg.Subscriptions[key] = desired
+		return true
+	})
+	if err != nil {
+		ps.Status.MarkDeployedFailed("SharedReceiveAdapterConfigFailed", "Error updating the shared Receive Adapter config: %s", err.Error())
+		return err
+	}
+
+	d, err := r.DeploymentLister.Deployments(system.Namespace()).Get(shared.DeploymentName)
+	if apierrors.IsNotFound(err) {
+		ps.Status.MarkDeployedFailed("SharedReceiveAdapterNotFound", "The shared Receive Adapter %q is not installed", shared.DeploymentName)
+		return nil
+	} else if err != nil {
+		ps.Status.MarkDeployedUnknown("SharedReceiveAdapterGetFailed", "Error getting the shared Receive Adapter: %s", err.Error())
+		return err
+	}
+	ps.Status.PropagateDeploymentAvailability(d)
+	return nil
+}
+
+// removeFromSharedReceiveAdapter removes the PullSubscription from the shared receive adapter
+// config, if present.
+func (r *Base) removeFromSharedReceiveAdapter(ctx context.Context, ps *v1.PullSubscription) error {
+	key := shared.Key(ps.Namespace, ps.Name)
+	return r.updateSharedReceiveAdapterConfig(ctx, key, func(cfg *shared.Config) bool {
+		if _, ok := cfg.Subscriptions[key]; !ok {
+			return false
+		}
+		delete(cfg.Subscriptions, key)
+		return true
+	})
+}
+
+// updateSharedReceiveAdapterConfig applies the update to the shard of the shared receive adapter
+// config holding the key, and writes the shard back if the update returns true. Concurrent writes
+// fail with a conflict, so that the losing PullSubscription gets requeued.
+func (r *Base) updateSharedReceiveAdapterConfig(ctx context.Context, key string, update func(*shared.Config) bool) error {
+	name := shared.ConfigMapName(key)
+	existing, err := r.ConfigMapLister.ConfigMaps(system.Namespace()).Get(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		logging.FromContext(ctx).Desugar().Error("Unable to get the shared Receive Adapter config", zap.Error(err))
+		return err
+	}
+	cfg := &shared.Config{}
+	if existing != nil {
+		if cfg, err = shared.ParseConfig([]byte(existing.Data[shared.ConfigMapKey])); err != nil {
+			return err
+		}
+	}
+	if !update(cfg) {
+		return nil
+	}
+
+	desired, err := resources.MakeSharedReceiveAdapterConfigMap(system.Namespace(), name, cfg)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		_, err = r.KubeClientSet.CoreV1().ConfigMaps(desired.Namespace).Create(ctx, desired, metav1.CreateOptions{})
+	} else {
+		existing = existing.DeepCopy()
+		existing.Data = desired.Data
+		_, err = r.KubeClientSet.CoreV1().ConfigMaps(existing.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Error writing the shared Receive Adapter config", zap.Error(err))
+	}
+	return err
+}
+
+// deleteReceiveAdapter deletes the dedicated receive adapter of a PullSubscription that opted into
+// the shared receive adapter.
+func (r *Base) deleteReceiveAdapter(ctx context.Context, ps *v1.PullSubscription) error {
+	name := resources.GenerateReceiveAdapterName(ps)
+	d, err := r.DeploymentLister.Deployments(ps.Namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(d, ps) {
+		return nil
+	}
+	err = r.KubeClientSet.AppsV1().Deployments(ps.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logging.FromContext(ctx).Desugar().Error("Error deleting Receive Adapter", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"knative.dev/pkg/injection"
 
 	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
 	serviceaccountinformers "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
@@ -33,12 +34,14 @@ import (
 	"knative.dev/pkg/metrics"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	"github.com/google/knative-gcp/pkg/apis/duck"
 	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/pullsubscription"
 	pullsubscriptionreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1/pullsubscription"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/shared"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
@@ -96,6 +99,7 @@ func newController(
 			Identity:               identity.NewIdentity(ctx, ipm, gcpas),
 			DeploymentLister:       deploymentInformer.Lister(),
 			ServiceAccountLister:   serviceAccountInformer.Lister(),
+			ConfigMapLister:        configmapinformer.Get(ctx).Lister(),
 			PullSubscriptionLister: pullSubscriptionLister,
 			ReceiveAdapterImage:    env.ReceiveAdapter,
 			CreateClientFn:         pubsub.NewClient,
@@ -125,6 +129,16 @@ func newController(
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	// The shared receive adapter isn't owned by any PullSubscription, so whenever it changes,
+	// enqueue all the PullSubscriptions using it.
+	onlySharedReceiveAdapter := pkgreconciler.AnnotationFilterFunc(duck.ReceiveAdapterAnnotation, duck.SharedReceiveAdapter, false)
+	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), shared.DeploymentName),
+		Handler: controller.HandleAll(func(interface{}) {
+			impl.FilteredGlobalResync(onlySharedReceiveAdapter, pullSubscriptionInformer.Informer())
+		}),
+	})
+
 	// Watch k8s service account, if a k8s service account resource changes, enqueue qualified pullsubscriptions from the same namespace.
 	serviceAccountInformer.Informer().AddEventHandler(authcheck.EnqueuePullSubscription(impl, pullSubscriptionLister))
 
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
@@ -41,6 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
@@ -49,12 +51,18 @@ import (
 
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
 
+	"github.com/google/knative-gcp/pkg/apis/duck"
 	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	pubsubv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1/pullsubscription"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/shared"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
@@ -77,6 +85,9 @@ const (
 	testTopicID = sourceUID + "-TOPIC"
 	generation  = 1
 
+	testFilter            = `attributes.domain = "example.com"`
+	testDeadLetterTopicID = "dead-letter-topic"
+
 	secretName = "testing-secret"
 
 	failedToReconcileSubscriptionMsg = `Failed to reconcile Pub/Sub subscription`
@@ -90,6 +101,8 @@ var (
 	transformerDNS = transformerName + ".mynamespace.svc.cluster.local"
 	transformerURI = apis.HTTP(transformerDNS)
 
+	deadLetterSinkURI = apis.HTTP("dead-letter-sink.mynamespace.svc.cluster.local")
+
 	sinkGVK = metav1.GroupVersionKind{
 		Group:   "testing.cloud.google.com",
 		Version: "v1",
@@ -98,6 +111,10 @@ var (
 
 	testSubscriptionID = fmt.Sprintf("cre-ps_%s_%s_%s", testNS, sourceName, sourceUID)
 
+	sharedAnnotations = map[string]string{
+		duck.ReceiveAdapterAnnotation: duck.SharedReceiveAdapter,
+	}
+
 	transformerGVK = metav1.GroupVersionKind{
 		Group:   "testing.cloud.google.com",
 		Version: "v1",
@@ -708,37 +725,40 @@ func TestAllCases(t *testing.T) {
 			OnlySubscriptions(testSubscriptionID),
 		},
 	}, {
-		Name: "successful create - reuse existing receive adapter - match",
+		Name: "successfully created subscription with retry and dead letter policy",
 		Objects: []runtime.Object{
 			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
 				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
 				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
 					PubSubSpec: gcpduckv1.PubSubSpec{
-						Secret:  &secret,
-						Project: testProject,
+						Secret:   &secret,
+						Project:  testProject,
+						Delivery: newPubSubDeadLetterDelivery(),
 					},
 					Topic: testTopicID,
 				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 			newSink(),
 			newSecret(),
-			newAvailableReceiveAdapter(context.Background(), testImage, nil),
-		},
-		OtherTestData: map[string]interface{}{
-			"pre": []PubsubAction{
-				Topic(testTopicID),
-			},
 		},
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+				Topic(testDeadLetterTopicID),
+			},
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, nil),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
@@ -746,28 +766,41 @@ func TestAllCases(t *testing.T) {
 				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
 				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
 					PubSubSpec: gcpduckv1.PubSubSpec{
-						Secret:  &secret,
-						Project: testProject,
+						Secret:   &secret,
+						Project:  testProject,
+						Delivery: newPubSubDeadLetterDelivery(),
 					},
 					Topic: testTopicID,
 				}),
 				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				reconcilertestingv1.WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
 				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
 				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
 				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 		PostConditions: []func(*testing.T, *TableRow){
 			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasRetryPolicy(testSubscriptionID, &pubsub.RetryPolicy{
+				MinimumBackoff: 5 * time.Second,
+				MaximumBackoff: 5 * time.Second,
+			}),
+			SubscriptionHasDeadLetterPolicy(testSubscriptionID, &pubsub.DeadLetterPolicy{
+				DeadLetterTopic:     fmt.Sprintf("projects/%s/topics/%s", testProject, testDeadLetterTopicID),
+				MaxDeliveryAttempts: 5,
+			}),
 		},
 	}, {
-		Name: "successful create - reuse existing receive adapter - mismatch",
+		Name: "successfully created subscription with filter",
 		Objects: []runtime.Object{
 			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
@@ -777,67 +810,64 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:  testTopicID,
+					Filter: testFilter,
 				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 			newSink(),
-			newTransformer(),
 			newSecret(),
-			newReceiveAdapter(context.Background(), "old"+testImage, nil),
-		},
-		OtherTestData: map[string]interface{}{
-			"pre": []PubsubAction{
-				Topic(testTopicID),
-			},
 		},
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
-		WantUpdates: []clientgotesting.UpdateActionImpl{{
-			ActionImpl: clientgotesting.ActionImpl{
-				Namespace: testNS,
-				Verb:      "update",
-				Resource:  receiveAdapterGVR(),
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
 			},
-			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
-		}},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, nil),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
-				//WithPullSubscriptionFinalizers(resourceGroup),
 				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
 				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
 					PubSubSpec: gcpduckv1.PubSubSpec{
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:  testTopicID,
+					Filter: testFilter,
 				}),
 				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
-				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
-				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
 				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 		PostConditions: []func(*testing.T, *TableRow){
 			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasFilter(testSubscriptionID, testFilter),
 		},
 	}, {
-		Name: "get existing receiver adapter fails",
+		Name: "filter changed - subscription recreated from a snapshot",
 		Objects: []runtime.Object{
 			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
@@ -847,31 +877,30 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:  testTopicID,
+					Filter: testFilter,
 				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 			newSink(),
-			newTransformer(),
 			newSecret(),
 		},
-		OtherTestData: map[string]interface{}{
-			"pre": []PubsubAction{
-				Topic(testTopicID),
-			},
-		},
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
-			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for get deployments"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
-		WithReactors: []clientgotesting.ReactionFunc{
-			InduceFailure("get", "deployments"),
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+				SubscriptionWithTopic(testSubscriptionID, testTopicID),
+			},
 		},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, nil),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
@@ -882,25 +911,32 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:  testTopicID,
+					Filter: testFilter,
 				}),
 				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
-				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				reconcilertestingv1.WithPullSubscriptionMarkDeployedUnknown("ReceiveAdapterGetFailed", "Error getting the Receive Adapter: inducing failure for get deployments"),
 				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
-				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
 				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 		PostConditions: []func(*testing.T, *TableRow){
 			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasFilter(testSubscriptionID, testFilter),
+			SnapshotDoesNotExist(testSubscriptionID),
 		},
 	}, {
-		Name: "create receiver adapter fails",
+		Name: "subscription created - snapshot named after it left untouched",
 		Objects: []runtime.Object{
 			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
@@ -910,34 +946,30 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:  testTopicID,
+					Filter: testFilter,
 				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 			newSink(),
-			newTransformer(),
 			newSecret(),
 		},
-		OtherTestData: map[string]interface{}{
-			"pre": []PubsubAction{
-				Topic(testTopicID),
-			},
-		},
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
-			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for create deployments"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
-		WithReactors: []clientgotesting.ReactionFunc{
-			InduceFailure("create", "deployments"),
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+				Snapshot(testSubscriptionID, testSubscriptionID),
+			},
 		},
 		WantCreates: []runtime.Object{
-			newReceiveAdapter(context.Background(), testImage, transformerURI),
-		},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
+			newReceiveAdapter(context.Background(), testImage, nil),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
@@ -948,68 +980,64 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:  testTopicID,
+					Filter: testFilter,
 				}),
 				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
-				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				reconcilertestingv1.WithPullSubscriptionMarkDeployedFailed("ReceiveAdapterCreateFailed", "Error creating the Receive Adapter: inducing failure for create deployments"),
 				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
-				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
 				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 		PostConditions: []func(*testing.T, *TableRow){
 			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasFilter(testSubscriptionID, testFilter),
+			SnapshotExists(testSubscriptionID),
 		},
 	}, {
-		Name: "update receiver adapter fails",
+		Name: "dead letter sink URI passed to the receive adapter",
 		Objects: []runtime.Object{
 			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
 				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
 				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
 					PubSubSpec: gcpduckv1.PubSubSpec{
-						Secret:  &secret,
-						Project: testProject,
+						Secret:   &secret,
+						Project:  testProject,
+						Delivery: newHTTPDeadLetterDelivery(),
 					},
 					Topic: testTopicID,
 				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 			newSink(),
-			newTransformer(),
 			newSecret(),
-			newReceiveAdapter(context.Background(), "old"+testImage, nil),
-		},
-		OtherTestData: map[string]interface{}{
-			"pre": []PubsubAction{
-				Topic(testTopicID),
-			},
 		},
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
-			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for update deployments"),
-		},
-		WithReactors: []clientgotesting.ReactionFunc{
-			InduceFailure("update", "deployments"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
-		WantUpdates: []clientgotesting.UpdateActionImpl{{
-			ActionImpl: clientgotesting.ActionImpl{
-				Namespace: testNS,
-				Verb:      "update",
-				Resource:  receiveAdapterGVR(),
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
 			},
-			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
-		}},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapterWithDeadLetterSink(context.Background(), testImage, deadLetterSinkURI),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
@@ -1017,71 +1045,60 @@ func TestAllCases(t *testing.T) {
 				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
 				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
 					PubSubSpec: gcpduckv1.PubSubSpec{
-						Secret:  &secret,
-						Project: testProject,
+						Secret:   &secret,
+						Project:  testProject,
+						Delivery: newHTTPDeadLetterDelivery(),
 					},
 					Topic: testTopicID,
 				}),
 				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
-				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				reconcilertestingv1.WithPullSubscriptionMarkDeployedFailed("ReceiveAdapterUpdateFailed", "Error updating the Receive Adapter: inducing failure for update deployments"),
 				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
-				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				reconcilertestingv1.WithPullSubscriptionMarkDeadLetterSink(deadLetterSinkURI),
+				// Updates
 				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 		PostConditions: []func(*testing.T, *TableRow){
 			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasDeadLetterPolicy(testSubscriptionID, nil),
 		},
 	}, {
-		Name: "propagate availability adapter fails",
+		Name: "cannot resolve dead letter sink",
 		Objects: []runtime.Object{
 			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
 				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
 				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
 					PubSubSpec: gcpduckv1.PubSubSpec{
-						Secret:  &secret,
-						Project: testProject,
+						Secret:   &secret,
+						Project:  testProject,
+						Delivery: newMissingDeadLetterDelivery(),
 					},
 					Topic: testTopicID,
 				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 			newSink(),
-			newTransformer(),
 			newSecret(),
-			newReceiveAdapter(context.Background(), "old"+testImage, nil),
-		},
-		OtherTestData: map[string]interface{}{
-			"pre": []PubsubAction{
-				Topic(testTopicID),
-			},
 		},
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
-			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for update deployments"),
-		},
-		WithReactors: []clientgotesting.ReactionFunc{
-			InduceFailure("update", "deployments"),
-		},
-		WantUpdates: []clientgotesting.UpdateActionImpl{{
-			ActionImpl: clientgotesting.ActionImpl{
-				Namespace: testNS,
-				Verb:      "update",
-				Resource:  receiveAdapterGVR(),
-			},
-			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
-		}},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
+			Eventf(corev1.EventTypeWarning, "InvalidDeadLetterSink",
+				`InvalidDeadLetterSink: sinks.testing.cloud.google.com "dead-letter-sink" not found`),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
@@ -1089,28 +1106,29 @@ func TestAllCases(t *testing.T) {
 				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
 				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
 					PubSubSpec: gcpduckv1.PubSubSpec{
-						Secret:  &secret,
-						Project: testProject,
+						Secret:   &secret,
+						Project:  testProject,
+						Delivery: newMissingDeadLetterDelivery(),
 					},
 					Topic: testTopicID,
 				}),
 				reconcilertestingv1.WithInitPullSubscriptionConditions,
-				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
-				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				reconcilertestingv1.WithPullSubscriptionMarkDeployedFailed("ReceiveAdapterUpdateFailed", "Error updating the Receive Adapter: inducing failure for update deployments"),
 				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
-				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
 				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeadLetterSink("InvalidDeadLetterSink",
+					`sinks.testing.cloud.google.com "dead-letter-sink" not found`),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 		}},
-		PostConditions: []func(*testing.T, *TableRow){
-			OnlySubscriptions(testSubscriptionID),
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
 		},
 	}, {
-		Name: "propagate availability adapter with termination log message",
+		Name: "delivery removed - retry and dead letter policies cleared",
 		Objects: []runtime.Object{
 			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
@@ -1122,28 +1140,507 @@ func TestAllCases(t *testing.T) {
 					},
 					Topic: testTopicID,
 				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
 				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
-				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
 				reconcilertestingv1.WithPullSubscriptionSetDefaults,
 			),
 			newSink(),
-			newTransformer(),
 			newSecret(),
-			newMinimumReplicasUnavailableAdapter(context.Background(), "old"+testImage, nil),
-			&corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "pod-1",
-					Namespace: testNS,
-					Labels:    resources.GetLabels(controllerAgentName, sourceName),
-				},
-				Status: corev1.PodStatus{
-					ContainerStatuses: []corev1.ContainerStatus{{
-						LastTerminationState: corev1.ContainerState{
-							Terminated: &corev1.ContainerStateTerminated{
-								Message: "checking authentication",
-							},
-						}}},
-				},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+				Topic(testDeadLetterTopicID),
+				SubscriptionWithConfig(testSubscriptionID, testTopicID, pubsub.SubscriptionConfig{
+					RetryPolicy: &pubsub.RetryPolicy{
+						MinimumBackoff: 5 * time.Second,
+						MaximumBackoff: 5 * time.Second,
+					},
+					DeadLetterPolicy: &pubsub.DeadLetterPolicy{
+						DeadLetterTopic:     fmt.Sprintf("projects/%s/topics/%s", testProject, testDeadLetterTopicID),
+						MaxDeliveryAttempts: 5,
+					},
+				}),
+			},
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+			SubscriptionHasRetryPolicy(testSubscriptionID, nil),
+			SubscriptionHasDeadLetterPolicy(testSubscriptionID, nil),
+		},
+	}, {
+		Name: "successful create - reuse existing receive adapter - match",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newSecret(),
+			newAvailableReceiveAdapter(context.Background(), testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "successful create - reuse existing receive adapter - mismatch",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newTransformer(),
+			newSecret(),
+			newReceiveAdapter(context.Background(), "old"+testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "update",
+				Resource:  receiveAdapterGVR(),
+			},
+			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				//WithPullSubscriptionFinalizers(resourceGroup),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "get existing receiver adapter fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newTransformer(),
+			newSecret(),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for get deployments"),
+		},
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("get", "deployments"),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployedUnknown("ReceiveAdapterGetFailed", "Error getting the Receive Adapter: inducing failure for get deployments"),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "create receiver adapter fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newTransformer(),
+			newSecret(),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for create deployments"),
+		},
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("create", "deployments"),
+		},
+		WantCreates: []runtime.Object{
+			newReceiveAdapter(context.Background(), testImage, transformerURI),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployedFailed("ReceiveAdapterCreateFailed", "Error creating the Receive Adapter: inducing failure for create deployments"),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "update receiver adapter fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newTransformer(),
+			newSecret(),
+			newReceiveAdapter(context.Background(), "old"+testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for update deployments"),
+		},
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("update", "deployments"),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "update",
+				Resource:  receiveAdapterGVR(),
+			},
+			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployedFailed("ReceiveAdapterUpdateFailed", "Error updating the Receive Adapter: inducing failure for update deployments"),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "propagate availability adapter fails",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newTransformer(),
+			newSecret(),
+			newReceiveAdapter(context.Background(), "old"+testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "DataPlaneReconcileFailed", "Failed to reconcile Data Plane resource(s): %s", "inducing failure for update deployments"),
+		},
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("update", "deployments"),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "update",
+				Resource:  receiveAdapterGVR(),
+			},
+			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployedFailed("ReceiveAdapterUpdateFailed", "Error updating the Receive Adapter: inducing failure for update deployments"),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkTransformer(transformerURI),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "propagate availability adapter with termination log message",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newTransformer(),
+			newSecret(),
+			newMinimumReplicasUnavailableAdapter(context.Background(), "old"+testImage, nil),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-1",
+					Namespace: testNS,
+					Labels:    resources.GetLabels(controllerAgentName, sourceName),
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								Message: "checking authentication",
+							},
+						}}},
+				},
 			},
 		},
 		OtherTestData: map[string]interface{}{
@@ -1276,6 +1773,208 @@ func TestAllCases(t *testing.T) {
 		PostConditions: []func(*testing.T, *TableRow){
 			OnlySubscriptions(testSubscriptionID),
 		},
+	}, {
+		Name: "shared receive adapter - replaces dedicated receive adapter",
+		// The shared receive adapter config lives in the system namespace.
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(sharedAnnotations),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newAvailableReceiveAdapter(context.Background(), testImage, nil),
+			NewDeployment(shared.DeploymentName, system.Namespace(), WithDeploymentAvailable()),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+				Name: deploymentName(),
+			},
+		},
+		WantCreates: []runtime.Object{
+			newSharedReceiveAdapterConfig(map[string]*shared.SubscriptionConfig{
+				shared.Key(testNS, sourceName): newSharedSubscription(),
+			}),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(sharedAnnotations),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployed(shared.DeploymentName, system.Namespace()),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "shared receive adapter - not installed",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(sharedAnnotations),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newSharedReceiveAdapterConfig(map[string]*shared.SubscriptionConfig{
+				shared.Key(testNS, sourceName): newSharedSubscription(),
+			}),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(sharedAnnotations),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployedFailed("SharedReceiveAdapterNotFound", fmt.Sprintf("The shared Receive Adapter %q is not installed", shared.DeploymentName)),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
+	}, {
+		Name: "dedicated receive adapter - removed from shared receive adapter",
+		// The shared receive adapter config lives in the system namespace.
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSink(),
+			newSecret(),
+			newAvailableReceiveAdapter(context.Background(), testImage, nil),
+			newSharedReceiveAdapterConfig(map[string]*shared.SubscriptionConfig{
+				shared.Key(testNS, sourceName): newSharedSubscription(),
+				shared.Key(testNS, "other"):    newSharedSubscription(),
+			}),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				Topic(testTopicID),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSharedReceiveAdapterConfig(map[string]*shared.SubscriptionConfig{
+				shared.Key(testNS, "other"): newSharedSubscription(),
+			}),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithInitPullSubscriptionConditions,
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				reconcilertestingv1.WithPullSubscriptionTransformerURI(nil),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlySubscriptions(testSubscriptionID),
+		},
 	}, {
 		Name: "deleting - failed to delete subscription",
 		Objects: []runtime.Object{
@@ -1344,6 +2043,47 @@ func TestAllCases(t *testing.T) {
 		},
 		Key:        testNS + "/" + sourceName,
 		WantEvents: nil,
+	}, {
+		Name: "deleting - removed from shared receive adapter",
+		// The shared receive adapter config lives in the system namespace.
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+				reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
+				reconcilertestingv1.WithPullSubscriptionObjectMetaGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionStatusObservedGeneration(generation),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(sharedAnnotations),
+				reconcilertestingv1.WithPullSubscriptionSpec(pubsubv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				reconcilertestingv1.WithPullSubscriptionMarkDeployed(shared.DeploymentName, system.Namespace()),
+				reconcilertestingv1.WithPullSubscriptionMarkSink(sinkURI),
+				reconcilertestingv1.WithPullSubscriptionProjectID(testProject),
+				reconcilertestingv1.WithPullSubscriptionDeleted,
+				reconcilertestingv1.WithPullSubscriptionSetDefaults,
+			),
+			newSharedReceiveAdapterConfig(map[string]*shared.SubscriptionConfig{
+				shared.Key(testNS, sourceName): newSharedSubscription(),
+			}),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub(testTopicID, testSubscriptionID),
+			},
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSharedReceiveAdapterConfig(map[string]*shared.SubscriptionConfig{}),
+		}},
+		PostConditions: []func(*testing.T, *TableRow){
+			NoSubscriptionsExist(),
+		},
+		Key:        testNS + "/" + sourceName,
+		WantEvents: nil,
 	}}
 
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
@@ -1388,6 +2128,7 @@ func TestAllCases(t *testing.T) {
 			Base: &psreconciler.Base{
 				Base:                   reconciler.NewBase(ctx, controllerAgentName, cmw),
 				DeploymentLister:       listers.GetDeploymentLister(),
+				ConfigMapLister:        listers.GetConfigMapLister(),
 				PullSubscriptionLister: listers.GetPullSubscriptionLister(),
 				UriResolver:            resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 				ReceiveAdapterImage:    testImage,
@@ -1421,6 +2162,21 @@ func newReceiveAdapter(ctx context.Context, image string, transformer *apis.URL)
 	return ra
 }
 
+func newReceiveAdapterWithDeadLetterSink(ctx context.Context, image string, deadLetterSink *apis.URL) runtime.Object {
+	ps := newPullSubscription()
+	ps.Spec.Delivery = newHTTPDeadLetterDelivery()
+	args := &resources.ReceiveAdapterArgs{
+		Image:             image,
+		PullSubscription:  ps,
+		Labels:            resources.GetLabels(controllerAgentName, sourceName),
+		SubscriptionID:    testSubscriptionID,
+		SinkURI:           sinkURI,
+		DeadLetterSinkURI: deadLetterSink,
+		AuthType:          authcheck.Secret,
+	}
+	return resources.MakeReceiveAdapter(ctx, args)
+}
+
 // newMinimumReplicasUnavailableAdapter is the adapter based on static configuration.
 func newMinimumReplicasUnavailableAdapter(ctx context.Context, image string, transformer *apis.URL) runtime.Object {
 	obj := newReceiveAdapter(ctx, image, transformer)
@@ -1436,6 +2192,39 @@ func newAvailableReceiveAdapter(ctx context.Context, image string, transformer *
 	return obj
 }
 
+func newPubSubDeadLetterDelivery() *eventingduckv1beta1.DeliverySpec {
+	linear := eventingduckv1beta1.BackoffPolicyLinear
+	return &eventingduckv1beta1.DeliverySpec{
+		BackoffDelay:  ptr.String("PT5S"),
+		BackoffPolicy: &linear,
+		Retry:         ptr.Int32(5),
+		DeadLetterSink: &duckv1.Destination{
+			URI: &apis.URL{Scheme: "pubsub", Host: testDeadLetterTopicID},
+		},
+	}
+}
+
+func newHTTPDeadLetterDelivery() *eventingduckv1beta1.DeliverySpec {
+	return &eventingduckv1beta1.DeliverySpec{
+		Retry: ptr.Int32(10),
+		DeadLetterSink: &duckv1.Destination{
+			URI: deadLetterSinkURI,
+		},
+	}
+}
+
+func newMissingDeadLetterDelivery() *eventingduckv1beta1.DeliverySpec {
+	return &eventingduckv1beta1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: "testing.cloud.google.com/v1",
+				Kind:       sinkGVK.Kind,
+				Name:       "dead-letter-sink",
+			},
+		},
+	}
+}
+
 func newPullSubscription() *pubsubv1.PullSubscription {
 	return reconcilertestingv1.NewPullSubscription(sourceName, testNS,
 		reconcilertestingv1.WithPullSubscriptionUID(sourceUID),
@@ -1450,6 +2239,29 @@ func newPullSubscription() *pubsubv1.PullSubscription {
 	)
 }
 
+func newSharedSubscription() *shared.SubscriptionConfig {
+	return &shared.SubscriptionConfig{
+		Namespace:      testNS,
+		Name:           sourceName,
+		ResourceGroup:  "pullsubscriptions.internal.events.cloud.google.com",
+		ProjectID:      testProject,
+		TopicID:        testTopicID,
+		SubscriptionID: testSubscriptionID,
+		SinkURI:        sinkURI.String(),
+		AdapterType:    string(converters.PubSubPull),
+	}
+}
+
+func newSharedReceiveAdapterConfig(subscriptions map[string]*shared.SubscriptionConfig) *corev1.ConfigMap {
+	// All the subscriptions are in the ConfigMap of the reconciled PullSubscription.
+	name := shared.ConfigMapName(shared.Key(testNS, sourceName))
+	cm, err := resources.MakeSharedReceiveAdapterConfigMap(system.Namespace(), name, &shared.Config{Subscriptions: subscriptions})
+	if err != nil {
+		panic(err)
+	}
+	return cm
+}
+
 func receiveAdapterGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{
 		Group:    "apps",
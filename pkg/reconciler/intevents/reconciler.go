@@ -32,6 +32,7 @@ import (
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 
+	apisduck "github.com/google/knative-gcp/pkg/apis/duck"
 	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
@@ -156,6 +157,13 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 		Annotations: resources.GetAnnotations(annotations, resourceGroup),
 	}
 
+	if mappable, ok := pubsubable.(duck.EventMappable); ok {
+		args.Mapping = mappable.EventMapping()
+	}
+	if filterable, ok := pubsubable.(duck.SubscriptionFilterable); ok {
+		args.Filter = filterable.SubscriptionFilter()
+	}
+
 	if v, present := pubsubable.GetObjectMeta().GetAnnotations()[testloggingutil.LoggingE2ETestAnnotation]; present {
 		// This is added purely for the TestCloudLogging E2E tests, which verify that the log line
 		// is written if this annotation is present.
@@ -177,11 +185,20 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 			logging.FromContext(ctx).Desugar().Error("Failed to create PullSubscription", zap.Any("ps", newPS), zap.Error(err))
 			return nil, pkgreconciler.NewEvent(corev1.EventTypeWarning, pullSubscriptionCreateFailedReason, "Creating PullSubscription failed with: %s", err.Error())
 		}
-		// Check whether the specs differ and update the PS if so.
-	} else if !equality.Semantic.DeepDerivative(newPS.Spec, ps.Spec) {
+		// Check whether the specs or the receive adapter choice differ and update the PS if so.
+	} else if !equality.Semantic.DeepDerivative(newPS.Spec, ps.Spec) ||
+		newPS.Annotations[apisduck.ReceiveAdapterAnnotation] != ps.Annotations[apisduck.ReceiveAdapterAnnotation] {
 		// Don't modify the informers copy.
 		desired := ps.DeepCopy()
 		desired.Spec = newPS.Spec
+		if v, present := newPS.Annotations[apisduck.ReceiveAdapterAnnotation]; present {
+			if desired.Annotations == nil {
+				desired.Annotations = make(map[string]string)
+			}
+			desired.Annotations[apisduck.ReceiveAdapterAnnotation] = v
+		} else {
+			delete(desired.Annotations, apisduck.ReceiveAdapterAnnotation)
+		}
 		logging.FromContext(ctx).Desugar().Debug("Updating PullSubscription", zap.Any("ps", desired))
 		ps, err = pullSubscriptions.Update(ctx, desired, v1.UpdateOptions{})
 		if err != nil {
@@ -44,6 +44,7 @@ import (
 	v1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	intereventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	fakePubsubClient "github.com/google/knative-gcp/pkg/client/clientset/versioned/fake"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1"
 	testingmetadata "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
 )
@@ -111,6 +112,7 @@ func ownerRef() metav1.OwnerReference {
 func TestCreates(t *testing.T) {
 	testCases := []struct {
 		name          string
+		pubsubable    kngcpduck.PubSubable
 		objects       []runtime.Object
 		expectedTopic *intereventsv1.Topic
 		expectedPS    *intereventsv1.PullSubscription
@@ -878,6 +880,111 @@ func TestCreates(t *testing.T) {
 				reconcilertestingv1.WithPullSubscriptionReady(oldSink.URI),
 			),
 		}},
+	}, {
+		name: "topic exists and is ready, pullsubscription is updated to use the shared receive adapter",
+		pubsubable: reconcilertestingv1.NewCloudStorageSource(name, testNS,
+			reconcilertestingv1.WithCloudStorageSourceSinkDestination(sink),
+			reconcilertestingv1.WithCloudStorageSourceAnnotations(map[string]string{
+				duck.ReceiveAdapterAnnotation: duck.SharedReceiveAdapter,
+			}),
+			reconcilertestingv1.WithCloudStorageSourceSetDefaults),
+		objects: []runtime.Object{
+			reconcilertestingv1.NewTopic(name, testNS,
+				reconcilertestingv1.WithTopicSpec(intereventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicLabels(map[string]string{
+					"receive-adapter":                     receiveAdapterName,
+					"events.cloud.google.com/source-name": name,
+				}),
+				reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithTopicProjectID(testProjectID),
+				reconcilertestingv1.WithTopicReadyAndPublisherDeployed(testTopicID),
+				reconcilertestingv1.WithTopicAddress(testTopicURI),
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+			reconcilertestingv1.NewPullSubscription(name, testNS,
+				reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+					Topic: testTopicID,
+					PubSubSpec: v1.PubSubSpec{
+						Secret: &secret,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sink,
+						},
+					},
+				}),
+				reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{
+					"receive-adapter":                     receiveAdapterName,
+					"events.cloud.google.com/source-name": name,
+				}),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+					"metrics-resource-group": resourceGroup,
+				}),
+				reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithPullSubscriptionReady(sink.URI),
+			),
+		},
+		expectedTopic: reconcilertestingv1.NewTopic(name, testNS,
+			reconcilertestingv1.WithTopicSpec(intereventsv1.TopicSpec{
+				Topic:             testTopicID,
+				PropagationPolicy: "CreateDelete",
+				EnablePublisher:   &falseVal,
+			}),
+			reconcilertestingv1.WithTopicLabels(map[string]string{
+				"receive-adapter":                     receiveAdapterName,
+				"events.cloud.google.com/source-name": name,
+			}),
+			reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			reconcilertestingv1.WithTopicReadyAndPublisherDeployed(testTopicID),
+			reconcilertestingv1.WithTopicProjectID(testProjectID),
+			reconcilertestingv1.WithTopicAddress(testTopicURI),
+			reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			reconcilertestingv1.WithTopicSetDefaults,
+		),
+		expectedPS: reconcilertestingv1.NewPullSubscription(name, testNS,
+			reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+				Topic: testTopicID,
+				PubSubSpec: v1.PubSubSpec{
+					SourceSpec: duckv1.SourceSpec{
+						Sink: sink,
+					},
+				},
+			}),
+			reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{
+				"receive-adapter":                     receiveAdapterName,
+				"events.cloud.google.com/source-name": name,
+			}),
+			reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+				"metrics-resource-group":      resourceGroup,
+				duck.ReceiveAdapterAnnotation: duck.SharedReceiveAdapter,
+			}),
+			reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			reconcilertestingv1.WithPullSubscriptionReady(sink.URI),
+		),
+		wantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(name, testNS,
+				reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+					Topic: testTopicID,
+					PubSubSpec: v1.PubSubSpec{
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sink,
+						},
+					},
+				}),
+				reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{
+					"receive-adapter":                     receiveAdapterName,
+					"events.cloud.google.com/source-name": name,
+				}),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+					"metrics-resource-group":      resourceGroup,
+					duck.ReceiveAdapterAnnotation: duck.SharedReceiveAdapter,
+				}),
+				reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithPullSubscriptionReady(sink.URI),
+			),
+		}},
 	}}
 
 	for _, tc := range testCases {
@@ -892,7 +999,11 @@ func TestCreates(t *testing.T) {
 			psBase.Logger = logtesting.TestLogger(t)
 
 			arl := pkgtesting.ActionRecorderList{cs}
-			topic, ps, err := psBase.ReconcilePubSub(context.Background(), pubsubable, testTopicID, resourceGroup)
+			source := tc.pubsubable
+			if source == nil {
+				source = pubsubable
+			}
+			topic, ps, err := psBase.ReconcilePubSub(context.Background(), source, testTopicID, resourceGroup)
 
 			if (tc.expectedErr != "" && err == nil) ||
 				(tc.expectedErr == "" && err != nil) ||
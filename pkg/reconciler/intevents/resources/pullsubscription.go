@@ -32,6 +32,8 @@ type PullSubscriptionArgs struct {
 	Owner       kmeta.OwnerRefable
 	Topic       string
 	AdapterType string
+	Mapping     *gcpduckv1.EventMapping
+	Filter      string
 	Labels      map[string]string
 	Annotations map[string]string
 }
@@ -52,14 +54,17 @@ func MakePullSubscription(args *PullSubscriptionArgs) *inteventsv1.PullSubscript
 				IdentitySpec: gcpduckv1.IdentitySpec{
 					ServiceAccountName: args.Spec.IdentitySpec.ServiceAccountName,
 				},
-				Secret:  args.Spec.Secret,
-				Project: args.Spec.Project,
+				Secret:   args.Spec.Secret,
+				Project:  args.Spec.Project,
+				Delivery: args.Spec.Delivery,
 				SourceSpec: duckv1.SourceSpec{
 					Sink: args.Spec.SourceSpec.Sink,
 				},
 			},
 			Topic:       args.Topic,
 			AdapterType: args.AdapterType,
+			Mapping:     args.Mapping,
+			Filter:      args.Filter,
 		},
 	}
 	if args.Spec.CloudEventOverrides != nil && args.Spec.CloudEventOverrides.Extensions != nil {
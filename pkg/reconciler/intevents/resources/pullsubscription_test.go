@@ -25,6 +25,8 @@ import (
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
@@ -59,6 +61,11 @@ func TestMakePullSubscription(t *testing.T) {
 						},
 					},
 				},
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{
+						URI: apis.HTTP("dead-letter-sink"),
+					},
+				},
 			},
 		},
 	}
@@ -69,6 +76,10 @@ func TestMakePullSubscription(t *testing.T) {
 		Owner:       source,
 		Topic:       "topic-abc",
 		AdapterType: "google.storage",
+		Mapping: &gcpduckv1.EventMapping{
+			Subject: "{{ .Attributes.objectId }}",
+		},
+		Filter:      `attributes.eventType = "OBJECT_FINALIZE"`,
 		Annotations: GetAnnotations(nil, "storages.events.cloud.google.com"),
 		Labels: map[string]string{
 			"receive-adapter":                     "storage.events.cloud.google.com",
@@ -121,9 +132,18 @@ func TestMakePullSubscription(t *testing.T) {
 						},
 					},
 				},
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{
+						URI: apis.HTTP("dead-letter-sink"),
+					},
+				},
 			},
 			Topic:       "topic-abc",
 			AdapterType: "google.storage",
+			Mapping: &gcpduckv1.EventMapping{
+				Subject: "{{ .Attributes.objectId }}",
+			},
+			Filter: `attributes.eventType = "OBJECT_FINALIZE"`,
 		},
 	}
 
@@ -27,6 +27,7 @@ import (
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
 	"cloud.google.com/go/pubsub"
@@ -114,6 +115,7 @@ func newController(
 	serviceAccountInformer.Informer().AddEventHandler(authcheck.EnqueueTopic(impl, topicLister))
 
 	cmw.Watch(tracingconfig.ConfigName, r.UpdateFromTracingConfigMap)
+	cmw.Watch(metrics.ConfigMapName(), r.UpdateFromMetricsConfigMap)
 
 	return impl
 }
@@ -23,6 +23,8 @@ import (
 	reconcilertesting "github.com/google/knative-gcp/pkg/reconciler/testing"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/metrics"
+	_ "knative.dev/pkg/metrics/testing"
 	"knative.dev/pkg/system"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
@@ -49,6 +51,13 @@ func TestNew(t *testing.T) {
 				Namespace: system.Namespace(),
 			},
 			Data: map[string]string{},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      metrics.ConfigMapName(),
+				Namespace: system.Namespace(),
+			},
+			Data: map[string]string{},
 		})
 	c := newController(ctx, cmw, reconcilertesting.NoopIAMPolicyManager, reconcilertesting.NewGCPAuthTestStore(t, nil), reconcilertesting.NewDataresidencyTestStore(t, nil))
 
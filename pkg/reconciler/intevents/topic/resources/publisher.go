@@ -37,6 +37,7 @@ type PublisherArgs struct {
 	Topic         *v1.Topic
 	Labels        map[string]string
 	TracingConfig string
+	MetricsConfig string
 	// There are three types: `secret`, `workload-identity-gsa` and `workload-identity`.
 	AuthType authcheck.AuthType
 }
@@ -69,8 +70,17 @@ func makePublisherPodSpec(args *PublisherArgs) *corev1.PodSpec {
 		}, {
 			Name:  "K_TRACING_CONFIG",
 			Value: args.TracingConfig,
+		}, {
+			Name:  "K_METRICS_CONFIG",
+			Value: args.MetricsConfig,
 		}},
 	}
+	if attr := args.Topic.GetOrderingKeyAttribute(); attr != "" {
+		publisherContainer.Env = append(publisherContainer.Env, corev1.EnvVar{
+			Name:  "ORDERING_KEY_ATTRIBUTE",
+			Value: attr,
+		})
+	}
 
 	// This is added purely for the TestCloudLogging E2E tests, which verify that the log line is
 	// written certain annotations are present.
@@ -56,6 +56,7 @@ func TestMakePublisher(t *testing.T) {
 		Topic:         topic,
 		Labels:        GetLabels("controller-name", "topic-name"),
 		TracingConfig: "TracingConfig-ABC123",
+		MetricsConfig: "MetricsConfig-ABC123",
 		AuthType:      authcheck.Secret,
 	})
 
@@ -117,6 +118,10 @@ func TestMakePublisher(t *testing.T) {
                 "name": "K_TRACING_CONFIG",
                 "value": "TracingConfig-ABC123"
               },
+              {
+                "name": "K_METRICS_CONFIG",
+                "value": "MetricsConfig-ABC123"
+              },
               {
                 "name": "GOOGLE_APPLICATION_CREDENTIALS",
                 "value": "/var/secrets/google/eventing-secret-key"
@@ -154,6 +159,7 @@ func TestMakePublisherWithServiceAccount(t *testing.T) {
 			Namespace: "topic-namespace",
 			Annotations: map[string]string{
 				duck.ClusterNameAnnotation: testingmetadata.FakeClusterName,
+				v1.OrderingKeyAnnotation:   "subject",
 			},
 		},
 		Spec: v1.TopicSpec{
@@ -170,6 +176,7 @@ func TestMakePublisherWithServiceAccount(t *testing.T) {
 		Topic:         topic,
 		Labels:        GetLabels("controller-name", "topic-name"),
 		TracingConfig: "TracingConfig-ABC123",
+		MetricsConfig: "MetricsConfig-ABC123",
 	})
 
 	yes := true
@@ -214,6 +221,12 @@ func TestMakePublisherWithServiceAccount(t *testing.T) {
 								}, {
 									Name:  "K_TRACING_CONFIG",
 									Value: "TracingConfig-ABC123",
+								}, {
+									Name:  "K_METRICS_CONFIG",
+									Value: "MetricsConfig-ABC123",
+								}, {
+									Name:  "ORDERING_KEY_ATTRIBUTE",
+									Value: "subject",
 								}},
 							}},
 							ServiceAccountName: "test",
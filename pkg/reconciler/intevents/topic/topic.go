@@ -35,6 +35,7 @@ import (
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
@@ -58,6 +59,9 @@ import (
 const (
 	resourceGroup = "topics.internal.events.cloud.google.com"
 
+	// publisherComponent is the component of the metrics of the Topic publishers.
+	publisherComponent = "publisher"
+
 	deleteTopicFailed               = "TopicDeleteFailed"
 	deleteWorkloadIdentityFailed    = "WorkloadIdentityDeleteFailed"
 	reconciledPublisherFailedReason = "PublisherReconcileFailed"
@@ -82,6 +86,7 @@ type Reconciler struct {
 
 	publisherImage string
 	tracingConfig  *tracingconfig.Config
+	metricsConfig  *metrics.ExporterOptions
 
 	// createClientFn is the function used to create the Pub/Sub client that interacts with Pub/Sub.
 	// This is needed so that we can inject a mock client for UTs purposes.
@@ -252,6 +257,11 @@ func (r *Reconciler) reconcilePublisher(ctx context.Context, topic *v1.Topic) (e
 		logging.FromContext(ctx).Desugar().Error("Error serializing tracing config", zap.Error(err))
 	}
 
+	metricsCfg, err := metrics.OptionsToJSON(r.metricsConfig)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Error serializing metrics config", zap.Error(err))
+	}
+
 	authType, err := authcheck.GetAuthTypeForSources(ctx, r.serviceAccountLister, authcheck.AuthTypeArgs{
 		Namespace:          topic.Namespace,
 		ServiceAccountName: topic.IdentitySpec().ServiceAccountName,
@@ -268,6 +278,7 @@ func (r *Reconciler) reconcilePublisher(ctx context.Context, topic *v1.Topic) (e
 		Topic:         topic,
 		Labels:        resources.GetLabels(controllerAgentName, topic.Name),
 		TracingConfig: tracingCfg,
+		MetricsConfig: metricsCfg,
 		AuthType:      authType,
 	})
 
@@ -306,6 +317,20 @@ func (r *Reconciler) UpdateFromTracingConfigMap(cfg *corev1.ConfigMap) {
 	// TODO: requeue all Topics. See https://github.com/google/knative-gcp/issues/457.
 }
 
+func (r *Reconciler) UpdateFromMetricsConfigMap(cfg *corev1.ConfigMap) {
+	if cfg != nil {
+		delete(cfg.Data, "_example")
+	}
+
+	r.metricsConfig = &metrics.ExporterOptions{
+		Domain:    metrics.Domain(),
+		Component: publisherComponent,
+		ConfigMap: cfg.Data,
+	}
+	r.Logger.Debugw("Updated Metrics config", zap.Any("metricsCfg", cfg))
+	// TODO: requeue all Topics. See https://github.com/google/knative-gcp/issues/457.
+}
+
 func (r *Reconciler) FinalizeKind(ctx context.Context, topic *v1.Topic) pkgreconciler.Event {
 	// If topic doesn't have ownerReference, and
 	// k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
@@ -29,6 +29,7 @@ import (
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 	channelreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/channel"
 )
@@ -113,6 +114,7 @@ func (r *Reconciler) syncSubscribers(ctx context.Context, channel *v1beta1.Chann
 		for _, s := range channel.Spec.SubscribableSpec.Subscribers {
 			t, status := celltenant.TargetFromSubscriberSpec(channel, s)
 			err := r.targetReconciler.ReconcileRetryTopicAndSubscription(ctx, r.Recorder, t)
+			checkDeadLetterSink(s, status)
 			writeSubscriberStatus(channel, s, status)
 			if err != nil {
 				return fmt.Errorf("unable to reconcile subscriber %q: %w", s.UID, err)
@@ -134,6 +136,21 @@ func (r *Reconciler) syncSubscribers(ctx context.Context, channel *v1beta1.Chann
 	return nil
 }
 
+// checkDeadLetterSink marks the subscriber as not ready if it has a dead letter sink that is not a
+// Pub/Sub topic and was not resolved to a URI by the Subscription controller.
+func checkDeadLetterSink(s eventingduckv1beta1.SubscriberSpec, status *celltenant.SubscriberStatus) {
+	if s.Delivery == nil || s.Delivery.DeadLetterSink == nil {
+		return
+	}
+	dls := s.Delivery.DeadLetterSink
+	if brokerv1beta1.IsPubSubDeadLetterSink(dls) {
+		return
+	}
+	if dls.URI == nil || dls.URI.Host == "" {
+		status.MarkDeadLetterSinkUnresolved("Unable to resolve the dead letter sink of subscriber %q", s.UID)
+	}
+}
+
 func writeSubscriberStatus(channel *v1beta1.Channel, s eventingduckv1beta1.SubscriberSpec, status *celltenant.SubscriberStatus) {
 	newStatus := eventingduckv1beta1.SubscriberStatus{
 		UID:                s.UID,
@@ -25,6 +25,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	duckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 
@@ -111,6 +112,22 @@ var (
 	replyDNS = "reply.mynamespace.svc.cluster.local"
 	replyURI = apis.HTTP(replyDNS)
 
+	backoffDelay  = "PT1S"
+	backoffPolicy = duckv1beta1.BackoffPolicyExponential
+
+	// The Subscription controller did not resolve the dead letter sink Ref into a URI.
+	unresolvedDeliverySpec = &duckv1beta1.DeliverySpec{
+		BackoffDelay:  &backoffDelay,
+		BackoffPolicy: &backoffPolicy,
+		DeadLetterSink: &duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Name:       "dead-letter-sink",
+			},
+		},
+	}
+
 	gServiceAccount = "test123@test123.iam.gserviceaccount.com"
 
 	channelFinalizerUpdatedEvent = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "test-channel" finalizers`)
@@ -258,6 +275,7 @@ func TestAllCasesChannel(t *testing.T) {
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 			),
 		}},
@@ -295,6 +313,7 @@ func TestAllCasesChannel(t *testing.T) {
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
 				WithChannelBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 			),
 		}},
@@ -361,6 +380,7 @@ func TestAllCasesChannel(t *testing.T) {
 					WithChannelUID(channelUID),
 					WithChannelReadyURI(channelURI),
 					WithChannelBrokerCellUnknown("BrokerCellNotReady", "BrokerCell knative-testing/default is not ready"),
+					WithChannelProjectID(testProject),
 					WithChannelSetDefaults,
 				),
 			},
@@ -403,6 +423,7 @@ func TestAllCasesChannel(t *testing.T) {
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 			),
 		}},
@@ -444,6 +465,7 @@ func TestAllCasesChannel(t *testing.T) {
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 			),
 		}},
@@ -483,6 +505,7 @@ func TestAllCasesChannel(t *testing.T) {
 				// TopicID is the empty string because we are using Ready just as a shortcut, rather
 				// than calling each method directly.
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 				WithChannelTopicUnknown("FinalizeTopicPubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
 				WithChannelSubscriptionUnknown("FinalizeSubscriptionPubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
@@ -523,6 +546,7 @@ func TestAllCasesChannel(t *testing.T) {
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 				WithChannelSubscribers(
 					duckv1beta1.SubscriberSpec{
@@ -559,6 +583,68 @@ func TestAllCasesChannel(t *testing.T) {
 			TopicExists("cre-sub_testnamespace_test-channel_testsubscription-def-123"),
 			SubscriptionExists("cre-sub_testnamespace_test-channel_testsubscription-def-123"),
 		},
+	}, {
+		Name: "Channel with Subscriber with unresolved dead letter sink",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelSetDefaults,
+				WithChannelSubscribers(
+					duckv1beta1.SubscriberSpec{
+						UID:           subscriptionUID,
+						Generation:    subscriptionGeneration,
+						SubscriberURI: subscriberURI,
+						ReplyURI:      replyURI,
+						Delivery:      unresolvedDeliverySpec,
+					})),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
+				WithChannelSetDefaults,
+				WithChannelSubscribers(
+					duckv1beta1.SubscriberSpec{
+						UID:           subscriptionUID,
+						Generation:    subscriptionGeneration,
+						SubscriberURI: subscriberURI,
+						ReplyURI:      replyURI,
+						Delivery:      unresolvedDeliverySpec,
+					}),
+				WithChannelSubscribersStatus(
+					duckv1beta1.SubscriberStatus{
+						UID:                subscriptionUID,
+						ObservedGeneration: subscriptionGeneration,
+						Ready:              "False",
+						Message:            `Unable to resolve the dead letter sink of subscriber "testsubscription-def-123"`,
+					}),
+			),
+		}},
+		WantEvents: []string{
+			channelFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-ch_testnamespace_test-channel_test-channel-abc-123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-ch_testnamespace_test-channel_test-channel-abc-123"`),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-sub_testnamespace_test-channel_testsubscription-def-123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-sub_testnamespace_test-channel_testsubscription-def-123"`),
+			channelReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, channelName, channelFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-ch_testnamespace_test-channel_test-channel-abc-123"),
+			SubscriptionExists("cre-ch_testnamespace_test-channel_test-channel-abc-123"),
+			TopicExists("cre-sub_testnamespace_test-channel_testsubscription-def-123"),
+			SubscriptionExists("cre-sub_testnamespace_test-channel_testsubscription-def-123"),
+		},
 	}, {
 		Name: "Channel updates existing Subscription",
 		Key:  testKey,
@@ -588,6 +674,7 @@ func TestAllCasesChannel(t *testing.T) {
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 				WithChannelSubscribers(
 					duckv1beta1.SubscriberSpec{
@@ -646,6 +733,7 @@ func TestAllCasesChannel(t *testing.T) {
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 			),
 		}},
@@ -787,6 +875,7 @@ func TestAllCasesChannel(t *testing.T) {
 			Object: NewChannel(channelName, testNS,
 				WithChannelUID(channelUID),
 				WithChannelReadyURI(channelURI),
+				WithChannelProjectID(testProject),
 				WithChannelSetDefaults,
 				WithChannelSubscribers(
 					duckv1beta1.SubscriberSpec{
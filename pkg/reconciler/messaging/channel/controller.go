@@ -36,6 +36,7 @@ import (
 
 	"knative.dev/pkg/injection"
 
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 
@@ -90,17 +91,20 @@ func newController(
 		}()
 	}
 
+	projects := celltenant.NewNamespaceProjects(namespaceinformer.Get(ctx).Lister())
 	r := &Reconciler{
 		Reconciler: celltenant.Reconciler{
 			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
 			BrokerCellLister:   bcInformer.Lister(),
 			ProjectID:          projectID,
 			PubsubClient:       client,
+			Projects:           projects,
 			DataresidencyStore: drs,
 		},
 		targetReconciler: &celltenant.TargetReconciler{
 			ProjectID:          projectID,
 			PubsubClient:       client,
+			Projects:           projects,
 			DataresidencyStore: drs,
 		},
 	}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/replay"
+	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
+	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
+	replayinformer "github.com/google/knative-gcp/pkg/client/injection/informers/events/v1alpha1/replay"
+	replayreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1alpha1/replay"
+	eventslisters "github.com/google/knative-gcp/pkg/client/listers/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "replay-controller"
+)
+
+type Constructor injection.ControllerConstructor
+
+// NewConstructor creates a constructor to make a Replay controller.
+func NewConstructor() Constructor {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		return newController(ctx, cmw)
+	}
+}
+
+func newController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	replayInformer := replayinformer.Get(ctx)
+
+	r := &Reconciler{
+		Base:           reconciler.NewBase(ctx, controllerAgentName, cmw),
+		replayLister:   replayInformer.Lister(),
+		triggerLister:  triggerinformer.Get(ctx).Lister(),
+		brokerLister:   brokerinformer.Get(ctx).Lister(),
+		podLister:      podinformer.Get(ctx).Lister(),
+		createClientFn: pubsub.NewClient,
+		now:            time.Now,
+	}
+	impl := replayreconciler.NewImpl(ctx, r)
+	r.enqueueAfter = impl.EnqueueAfter
+
+	r.Logger.Info("Setting up event handlers")
+
+	// Replays of a Trigger are replayed one at a time, so a Replay that completes or is deleted
+	// lets the next one start.
+	replayInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.HandleAll(enqueueNamespace(replayInformer.Lister(), impl.Enqueue)),
+		reconciler.DefaultResyncPeriod,
+	)
+
+	// Watch the broker data plane pods, which annotate themselves with the progress of the replays.
+	podinformer.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: pkgreconciler.ChainFilterFuncs(
+			pkgreconciler.NamespaceFilterFunc(system.Namespace()),
+			pkgreconciler.LabelExistsFilterFunc(brokercellresources.BrokerCellLabelKey),
+		),
+		Handler: handleProgressChange(replayInformer.Lister(), impl.Enqueue),
+	})
+
+	return impl
+}
+
+// enqueueNamespace enqueues the Replays in the namespace of the given Replay.
+func enqueueNamespace(replayLister eventslisters.ReplayLister, enqueue func(interface{})) func(interface{}) {
+	return func(obj interface{}) {
+		rp, ok := obj.(*eventsv1alpha1.Replay)
+		if !ok {
+			return
+		}
+		replays, err := replayLister.Replays(rp.Namespace).List(labels.Everything())
+		if err != nil {
+			return
+		}
+		for _, r := range replays {
+			enqueue(r)
+		}
+	}
+}
+
+// handleProgressChange enqueues the Replays whose progress changed in a broker data plane pod.
+func handleProgressChange(replayLister eventslisters.ReplayLister, enqueue func(interface{})) cache.ResourceEventHandler {
+	progress := func(obj interface{}) map[string]replay.Progress {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			p, _ := replay.ParseProgress(pod.GetAnnotations())
+			return p
+		}
+		return nil
+	}
+	enqueueReplays := func(uids map[string]bool) {
+		if len(uids) == 0 {
+			return
+		}
+		replays, err := replayLister.List(labels.Everything())
+		if err != nil {
+			return
+		}
+		for _, rp := range replays {
+			if uids[string(rp.UID)] {
+				enqueue(rp)
+			}
+		}
+	}
+	all := func(p map[string]replay.Progress) map[string]bool {
+		uids := make(map[string]bool, len(p))
+		for uid := range p {
+			uids[uid] = true
+		}
+		return uids
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			enqueueReplays(all(progress(obj)))
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldProgress, newProgress := progress(oldObj), progress(newObj)
+			changed := make(map[string]bool)
+			for uid, p := range newProgress {
+				if old, ok := oldProgress[uid]; !ok || !progressEqual(old, p) {
+					changed[uid] = true
+				}
+			}
+			for uid := range oldProgress {
+				if _, ok := newProgress[uid]; !ok {
+					changed[uid] = true
+				}
+			}
+			enqueueReplays(changed)
+		},
+		DeleteFunc: func(obj interface{}) {
+			enqueueReplays(all(progress(obj)))
+		},
+	}
+}
+
+func progressEqual(a, b replay.Progress) bool {
+	if a.Events != b.Events || a.Done != b.Done {
+		return false
+	}
+	if a.ReplayedThrough == nil || b.ReplayedThrough == nil {
+		return a.ReplayedThrough == b.ReplayedThrough
+	}
+	return a.ReplayedThrough.Equal(*b.ReplayedThrough)
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replay implements the Replay controller and reconciler, which
+// replays the events retained by a Broker to one of its Triggers.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/replay"
+	replayreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1alpha1/replay"
+	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
+	eventslisters "github.com/google/knative-gcp/pkg/client/listers/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// subscriptionExpiration is how long the temporary subscription of a Replay is kept once it is no
+// longer pulled, in case the Replay is deleted before the subscription is. It is the minimum
+// expiration policy allowed by Pub/Sub.
+const subscriptionExpiration = 24 * time.Hour
+
+// Reconciler implements controller.Reconciler for Replay resources.
+type Reconciler struct {
+	*reconciler.Base
+
+	replayLister  eventslisters.ReplayLister
+	triggerLister brokerlisters.TriggerLister
+	brokerLister  brokerlisters.BrokerLister
+
+	// podLister lists the broker data plane pods, which annotate themselves with the progress of
+	// the replays.
+	podLister corev1listers.PodLister
+
+	// createClientFn is the function used to create the Pub/Sub client that manages the temporary
+	// subscriptions of the Replays.
+	createClientFn reconcilerutilspubsub.CreateFn
+
+	// enqueueAfter enqueues the Replay after the delay, so that it is deleted once its TTL expires.
+	enqueueAfter func(obj interface{}, after time.Duration)
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// Check that Reconciler implements Interface and Finalizer
+var _ replayreconciler.Interface = (*Reconciler)(nil)
+var _ replayreconciler.Finalizer = (*Reconciler)(nil)
+
+// ReconcileKind implements Interface.ReconcileKind.
+func (r *Reconciler) ReconcileKind(ctx context.Context, rp *eventsv1alpha1.Replay) pkgreconciler.Event {
+	rp.Status.InitializeConditions()
+	if !rp.Status.IsDone() {
+		if err := r.reconcileReplay(ctx, rp); err != nil {
+			return err
+		}
+		if !rp.Status.IsDone() {
+			return nil
+		}
+	}
+	return r.reconcileDone(ctx, rp)
+}
+
+// FinalizeKind implements Finalizer.FinalizeKind.
+func (r *Reconciler) FinalizeKind(ctx context.Context, rp *eventsv1alpha1.Replay) pkgreconciler.Event {
+	return r.deleteSubscription(ctx, rp)
+}
+
+// reconcileReplay seeks the temporary subscription of the Replay, then aggregates its progress.
+func (r *Reconciler) reconcileReplay(ctx context.Context, rp *eventsv1alpha1.Replay) error {
+	b, err := r.getBroker(rp)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			rp.Status.MarkReplayFailed("TriggerNotFound", "Trigger %q or its Broker does not exist", rp.Spec.Trigger)
+			return nil
+		}
+		return err
+	}
+	if b.GetMessageRetention() == 0 {
+		rp.Status.MarkReplayFailed("RetentionDisabled", "Broker %q does not retain its events, set the %s annotation to replay them", b.Name, brokerv1beta1.MessageRetentionAnnotation)
+		return nil
+	}
+
+	if !rp.Status.IsSeeked() {
+		if err := r.seek(ctx, rp, b); err != nil {
+			return err
+		}
+		if !rp.Status.IsSeeked() {
+			return nil
+		}
+	}
+	return r.reconcileProgress(ctx, rp)
+}
+
+// getBroker returns the Broker of the Trigger of the Replay.
+func (r *Reconciler) getBroker(rp *eventsv1alpha1.Replay) (*brokerv1beta1.Broker, error) {
+	t, err := r.triggerLister.Triggers(rp.Namespace).Get(rp.Spec.Trigger)
+	if err != nil {
+		return nil, err
+	}
+	return r.brokerLister.Brokers(t.Namespace).Get(t.Spec.Broker)
+}
+
+// seek creates the temporary subscription of the Replay and seeks it to the events retained by the
+// Broker, unless another Replay of the Trigger goes first. Each Replay has its own subscription, so
+// the Replays of different Triggers are replayed concurrently. A Trigger is given a single replay
+// in the targets config though, so the Replays of a Trigger are replayed one at a time, in the
+// order they were created.
+func (r *Reconciler) seek(ctx context.Context, rp *eventsv1alpha1.Replay, b *brokerv1beta1.Broker) error {
+	first, err := r.firstReplay(rp)
+	if err != nil {
+		return err
+	}
+	if first != nil && first.UID != rp.UID {
+		rp.Status.MarkSubscriptionNotSeeked("Queued", "Waiting for Replay %q of Trigger %q to complete", first.Name, rp.Spec.Trigger)
+		return nil
+	}
+
+	// The temporary subscription is created in the project the Pub/Sub resources of the Broker
+	// were created in, next to the retention subscription.
+	projectID, err := utils.ProjectIDOrDefault(b.Status.ProjectID())
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to find project id", zap.Error(err))
+		return err
+	}
+	client, err := r.createClientFn(ctx, projectID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create Pub/Sub client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	// Events accepted from now on are delivered from the decouple queue, so they are not replayed.
+	end := metav1.NewTime(r.now())
+	subID := brokerresources.GenerateReplaySubscriptionName(rp)
+	// The subscription is recorded before it is created, so that it is deleted even if the Replay
+	// is deleted before the subscription is seeked.
+	rp.Status.ProjectID = projectID
+	rp.Status.SubscriptionID = subID
+	if err := createSubscription(ctx, client, brokerresources.GenerateRetentionSubscriptionName(b), subID); err != nil {
+		logging.FromContext(ctx).Error("Failed to seek the replay subscription", zap.String("subscription", subID), zap.Error(err))
+		rp.Status.MarkSubscriptionNotSeeked("SeekFailed", "Failed to seek Pub/Sub subscription %q: %v", subID, err)
+		return err
+	}
+	rp.Status.MarkSubscriptionSeeked(subID, end)
+	rp.Status.MarkReplaying(nil, 0)
+	return nil
+}
+
+// createSubscription creates the subscription subID on the topic of the retention subscription,
+// and seeks it to a snapshot of the retention subscription. The retention subscription is never
+// pulled, so the snapshot holds every event it retains. The snapshot is named after the
+// subscription, and each step tolerates having been done by a previous attempt.
+func createSubscription(ctx context.Context, client *pubsub.Client, retentionSubID, subID string) error {
+	retentionSub := client.Subscription(retentionSubID)
+	retentionConfig, err := retentionSub.Config(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the retention subscription: %w", err)
+	}
+	if _, err := retentionSub.CreateSnapshot(ctx, subID); err != nil && status.Code(err) != codes.AlreadyExists {
+		return fmt.Errorf("failed to create snapshot of the retention subscription: %w", err)
+	}
+	sub, err := client.CreateSubscription(ctx, subID, pubsub.SubscriptionConfig{
+		Topic:            retentionConfig.Topic,
+		Labels:           retentionConfig.Labels,
+		ExpirationPolicy: subscriptionExpiration,
+	})
+	if err != nil {
+		if status.Code(err) != codes.AlreadyExists {
+			return fmt.Errorf("failed to create the subscription: %w", err)
+		}
+		sub = client.Subscription(subID)
+	}
+	snapshot := client.Snapshot(subID)
+	if err := sub.SeekToSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+	if err := snapshot.Delete(ctx); err != nil {
+		// Snapshots expire on their own, and the snapshot is deleted again with the subscription.
+		logging.FromContext(ctx).Warn("Failed to delete the snapshot of the retention subscription", zap.String("snapshot", snapshot.ID()), zap.Error(err))
+	}
+	return nil
+}
+
+// firstReplay returns the Replay of the Trigger of rp that is replayed first: the one being
+// replayed, or else the oldest one that is not done.
+func (r *Reconciler) firstReplay(rp *eventsv1alpha1.Replay) (*eventsv1alpha1.Replay, error) {
+	replays, err := r.replayLister.Replays(rp.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var first *eventsv1alpha1.Replay
+	for _, other := range replays {
+		if other.Spec.Trigger != rp.Spec.Trigger || other.Status.IsDone() || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if other.Status.IsSeeked() {
+			return other, nil
+		}
+		if first == nil || before(other, first) {
+			first = other
+		}
+	}
+	return first, nil
+}
+
+// before returns whether a was created before b, breaking ties by name.
+func before(a, b *eventsv1alpha1.Replay) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// reconcileDone deletes the temporary subscription of a done Replay, which is no longer delivered
+// by the data plane, then deletes the Replay once its TTL expires.
+func (r *Reconciler) reconcileDone(ctx context.Context, rp *eventsv1alpha1.Replay) error {
+	if err := r.deleteSubscription(ctx, rp); err != nil {
+		return err
+	}
+
+	ttl := time.Duration(eventsv1alpha1.DefaultReplayTTLSecondsAfterFinished) * time.Second
+	if rp.Spec.TTLSecondsAfterFinished != nil {
+		ttl = time.Duration(*rp.Spec.TTLSecondsAfterFinished) * time.Second
+	}
+	finished := rp.Status.GetTopLevelCondition().LastTransitionTime.Inner.Time
+	if rp.Status.CompletionTime != nil {
+		finished = rp.Status.CompletionTime.Time
+	}
+	if remaining := finished.Add(ttl).Sub(r.now()); remaining > 0 {
+		r.enqueueAfter(rp, remaining)
+		return nil
+	}
+	logging.FromContext(ctx).Debug("Deleting the Replay, its TTL expired")
+	if err := r.RunClientSet.EventsV1alpha1().Replays(rp.Namespace).Delete(ctx, rp.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+		logging.FromContext(ctx).Error("Failed to delete the Replay", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// deleteSubscription deletes the temporary subscription of the Replay, and the snapshot it is
+// seeked to if the Replay is deleted before the subscription is seeked.
+func (r *Reconciler) deleteSubscription(ctx context.Context, rp *eventsv1alpha1.Replay) error {
+	if rp.Status.SubscriptionID == "" {
+		return nil
+	}
+	client, err := r.createClientFn(ctx, rp.Status.ProjectID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create Pub/Sub client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Subscription(rp.Status.SubscriptionID).Delete(ctx); err != nil && status.Code(err) != codes.NotFound {
+		logging.FromContext(ctx).Error("Failed to delete the replay subscription", zap.String("subscription", rp.Status.SubscriptionID), zap.Error(err))
+		return err
+	}
+	if err := client.Snapshot(rp.Status.SubscriptionID).Delete(ctx); err != nil && status.Code(err) != codes.NotFound {
+		logging.FromContext(ctx).Error("Failed to delete the replay snapshot", zap.String("snapshot", rp.Status.SubscriptionID), zap.Error(err))
+		return err
+	}
+	rp.Status.MarkSubscriptionDeleted()
+	return nil
+}
+
+// reconcileProgress aggregates the progress of the Replay reported by the broker retry pods. The
+// Replay is done once every pod that replays it has no more events to replay.
+func (r *Reconciler) reconcileProgress(ctx context.Context, rp *eventsv1alpha1.Replay) error {
+	pods, err := brokerresources.ListBrokerCellPods(r.podLister)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list broker data plane pods", zap.Error(err))
+		return err
+	}
+	var reporting, done int
+	var events int64
+	var replayedThrough *metav1.Time
+	for _, pod := range pods {
+		progress, err := replay.ParseProgress(pod.GetAnnotations())
+		if err != nil {
+			logging.FromContext(ctx).Warn("Failed to parse the replay progress of pod", zap.String("pod", pod.Name), zap.Error(err))
+			continue
+		}
+		p, ok := progress[string(rp.UID)]
+		if !ok {
+			continue
+		}
+		reporting++
+		events += p.Events
+		if p.Done {
+			done++
+		}
+		if p.ReplayedThrough != nil && (replayedThrough == nil || p.ReplayedThrough.After(replayedThrough.Time)) {
+			t := metav1.NewTime(*p.ReplayedThrough)
+			replayedThrough = &t
+		}
+	}
+	rp.Status.MarkReplaying(replayedThrough, events)
+	if reporting > 0 && done == reporting {
+		rp.Status.MarkReplayed(metav1.NewTime(r.now()))
+	}
+	return nil
+}
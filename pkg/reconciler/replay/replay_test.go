@@ -0,0 +1,562 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub/pstest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/system"
+
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/replay"
+	fakerunclient "github.com/google/knative-gcp/pkg/client/injection/client/fake"
+	replayreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1alpha1/replay"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	testNS        = "testnamespace"
+	testProject   = "test-project"
+	replayName    = "test-replay"
+	triggerName   = "test-trigger"
+	brokerName    = "test-broker"
+	replayUID     = "replay-uid"
+	brokerUID     = "abc123"
+	finalizerName = "replays.events.cloud.google.com"
+
+	decouplingTopicID       = "cre-bkr_testnamespace_test-broker_abc123"
+	retentionSubscriptionID = "cre-bkr-rtn_testnamespace_test-broker_abc123"
+	replaySubscriptionID    = "cre-rpl_testnamespace_test-replay_replay-uid"
+)
+
+var (
+	testKey = fmt.Sprintf("%s/%s", testNS, replayName)
+
+	startTime = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	now       = time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC)
+	through   = time.Date(2021, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	replaysResource = eventsv1alpha1.SchemeGroupVersion.WithResource("replays")
+)
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{
+		{
+			Name: "bad workqueue key",
+			Key:  "too/many/parts",
+		},
+		{
+			Name: "key not found",
+			Key:  testKey,
+		},
+		{
+			Name: "Trigger not found",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewReplay(replayName, testNS, triggerName, startTime, WithReplayUID(replayUID)),
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", replayName),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, replayName, finalizerName),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithInitReplayConditions,
+					WithReplayFailed("TriggerNotFound", `Trigger "test-trigger" or its Broker does not exist`),
+				),
+			}},
+		},
+		{
+			Name: "Broker does not retain its events",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplayFailed("RetentionDisabled", `Broker "test-broker" does not retain its events, set the events.cloud.google.com/messageRetention annotation to replay them`),
+				),
+			}},
+		},
+		{
+			Name: "Replay is queued behind an older Replay of its Trigger",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay("older-replay", testNS, triggerName, startTime,
+					WithReplayUID("older-uid"),
+					WithReplayCreationTimestamp(startTime)),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayCreationTimestamp(startTime.Add(time.Hour))),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub(decouplingTopicID, retentionSubscriptionID),
+				},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayCreationTimestamp(startTime.Add(time.Hour)),
+					WithInitReplayConditions,
+					WithReplaySubscriptionNotSeeked("Queued", `Waiting for Replay "older-replay" of Trigger "test-trigger" to complete`),
+				),
+			}},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions(retentionSubscriptionID),
+			},
+		},
+		{
+			Name: "Concurrent Replays of other Triggers are replayed from their own subscriptions",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewTrigger("other-trigger", testNS, brokerName),
+				NewReplay("other-replay", testNS, "other-trigger", startTime,
+					WithReplayUID("other-uid"),
+					WithReplayFinalizers(finalizerName),
+					WithReplayCreationTimestamp(startTime),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, "cre-rpl_testnamespace_other-replay_other-uid"),
+					WithReplaySubscriptionSeeked("cre-rpl_testnamespace_other-replay_other-uid", startTime),
+					WithReplayReplaying(nil, 0)),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayCreationTimestamp(startTime.Add(time.Hour))),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub(decouplingTopicID, retentionSubscriptionID),
+					SubscriptionWithTopic("cre-rpl_testnamespace_other-replay_other-uid", decouplingTopicID),
+				},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayCreationTimestamp(startTime.Add(time.Hour)),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(nil, 0),
+				),
+			}},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions(retentionSubscriptionID, "cre-rpl_testnamespace_other-replay_other-uid", replaySubscriptionID),
+				SnapshotDoesNotExist(replaySubscriptionID),
+			},
+		},
+		{
+			Name: "Replay is not queued behind a done Replay",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay("older-replay", testNS, triggerName, startTime,
+					WithReplayUID("older-uid"),
+					WithReplayCreationTimestamp(startTime),
+					WithInitReplayConditions,
+					WithReplaySubscriptionSeeked("cre-rpl_testnamespace_older-replay_older-uid", startTime),
+					WithReplayReplayed(startTime),
+					WithReplaySubscriptionDeleted),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayCreationTimestamp(startTime.Add(time.Hour))),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub(decouplingTopicID, retentionSubscriptionID),
+				},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayCreationTimestamp(startTime.Add(time.Hour)),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(nil, 0),
+				),
+			}},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions(retentionSubscriptionID, replaySubscriptionID),
+			},
+		},
+		{
+			Name: "Seek fails, the retention subscription does not exist",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName)),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic(decouplingTopicID),
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeWarning, "InternalError", `failed to get the retention subscription: rpc error: code = NotFound desc = subscription projects/test-project/subscriptions/cre-bkr-rtn_testnamespace_test-broker_abc123`),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionNotSeeked("SeekFailed", `Failed to seek Pub/Sub subscription "cre-rpl_testnamespace_test-replay_replay-uid": failed to get the retention subscription: rpc error: code = NotFound desc = subscription projects/test-project/subscriptions/cre-bkr-rtn_testnamespace_test-broker_abc123`),
+				),
+			}},
+			PostConditions: []func(*testing.T, *TableRow){
+				NoSubscriptionsExist(),
+			},
+			WantErr: true,
+		},
+		{
+			Name: "Subscription is created and seeked",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay(replayName, testNS, triggerName, startTime, WithReplayUID(replayUID)),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub(decouplingTopicID, retentionSubscriptionID),
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", replayName),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, replayName, finalizerName),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(nil, 0),
+				),
+			}},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions(retentionSubscriptionID, replaySubscriptionID),
+				SnapshotDoesNotExist(replaySubscriptionID),
+			},
+		},
+		{
+			Name: "Subscription and snapshot left by a previous attempt are reused",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionNotSeeked("SeekFailed", "Failed to seek")),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub(decouplingTopicID, retentionSubscriptionID),
+					Snapshot(replaySubscriptionID, retentionSubscriptionID),
+					SubscriptionWithTopic(replaySubscriptionID, decouplingTopicID),
+				},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(nil, 0),
+				),
+			}},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions(retentionSubscriptionID, replaySubscriptionID),
+				SnapshotDoesNotExist(replaySubscriptionID),
+			},
+		},
+		{
+			Name: "Progress is aggregated from the data plane pods",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(nil, 0)),
+				makeDataPlanePod(t, "retry-1", map[string]replay.Progress{
+					replayUID: {ReplayedThrough: &through, Events: 3, Done: true},
+				}),
+				makeDataPlanePod(t, "retry-2", map[string]replay.Progress{
+					replayUID:   {ReplayedThrough: &startTime, Events: 2},
+					"other-uid": {Events: 7},
+				}),
+				makeDataPlanePod(t, "retry-3", nil),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(&through, 5),
+				),
+			}},
+		},
+		{
+			Name: "All the data plane pods are done, the subscription is deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS, WithBrokerUID(brokerUID), WithBrokerMessageRetention("24h"), WithBrokerProjectID(testProject)),
+				NewTrigger(triggerName, testNS, brokerName),
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(nil, 0)),
+				makeDataPlanePod(t, "retry-1", map[string]replay.Progress{
+					replayUID: {ReplayedThrough: &through, Events: 3, Done: true},
+				}),
+				makeDataPlanePod(t, "retry-2", map[string]replay.Progress{
+					replayUID: {Done: true},
+				}),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub(decouplingTopicID, retentionSubscriptionID),
+					SubscriptionWithTopic(replaySubscriptionID, decouplingTopicID),
+				},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(&through, 3),
+					WithReplayReplayed(now),
+					WithReplaySubscriptionDeleted,
+				),
+			}},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions(retentionSubscriptionID),
+				wantEnqueuedAfter(24 * time.Hour),
+			},
+		},
+		{
+			Name: "Done Replay is kept until its TTL expires",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayTTLSecondsAfterFinished(3600),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(&through, 3),
+					WithReplayReplayed(now.Add(-30*time.Minute)),
+					WithReplaySubscriptionDeleted),
+			},
+			OtherTestData: map[string]interface{}{},
+			PostConditions: []func(*testing.T, *TableRow){
+				wantEnqueuedAfter(30 * time.Minute),
+			},
+		},
+		{
+			Name: "Done Replay is deleted once its TTL expires",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionSeeked(replaySubscriptionID, now),
+					WithReplayReplaying(&through, 3),
+					WithReplayReplayed(now.Add(-48*time.Hour)),
+					WithReplaySubscriptionDeleted),
+			},
+			WantDeletes: []clientgotesting.DeleteActionImpl{{
+				ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS, Verb: "delete", Resource: replaysResource},
+				Name: replayName,
+			}},
+		},
+		{
+			Name: "Deleted Replay has its subscription and snapshot deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewReplay(replayName, testNS, triggerName, startTime,
+					WithReplayUID(replayUID),
+					WithReplayFinalizers(finalizerName),
+					WithReplayDeletionTimestamp,
+					WithInitReplayConditions,
+					WithReplaySubscription(testProject, replaySubscriptionID),
+					WithReplaySubscriptionNotSeeked("SeekFailed", "Failed to seek")),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub(decouplingTopicID, retentionSubscriptionID),
+					Snapshot(replaySubscriptionID, retentionSubscriptionID),
+					SubscriptionWithTopic(replaySubscriptionID, decouplingTopicID),
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", replayName),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, replayName, ""),
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions(retentionSubscriptionID),
+				SnapshotDoesNotExist(replaySubscriptionID),
+			},
+		},
+	}
+
+	logger := logtesting.TestLogger(t)
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		// The fake clientset tracks Replays as "replaies", track them as "replays" as well so that
+		// their status can be updated.
+		replays, _ := listers.GetReplayLister().List(labels.Everything())
+		for _, rp := range replays {
+			if err := fakerunclient.Get(ctx).Tracker().Create(replaysResource, rp, rp.Namespace); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		srv := pstest.NewServer()
+		t.Cleanup(func() { srv.Close() })
+		if testData != nil {
+			psclient, _ := GetTestClientCreateFunc(srv.Addr)(ctx, testProject)
+			InjectPubsubClient(testData, psclient)
+			if testData["pre"] != nil {
+				for _, f := range testData["pre"].([]PubsubAction) {
+					f(ctx, t, psclient)
+				}
+			}
+		}
+
+		r := &Reconciler{
+			Base:           reconciler.NewBase(ctx, controllerAgentName, cmw),
+			replayLister:   listers.GetReplayLister(),
+			triggerLister:  listers.GetTriggerLister(),
+			brokerLister:   listers.GetBrokerLister(),
+			podLister:      listers.GetPodLister(),
+			createClientFn: GetTestClientCreateFunc(srv.Addr),
+			enqueueAfter: func(_ interface{}, after time.Duration) {
+				if testData != nil {
+					testData["enqueuedAfter"] = after
+				}
+			},
+			now: func() time.Time { return now },
+		}
+		return replayreconciler.NewReconciler(ctx, logger, r.RunClientSet, listers.GetReplayLister(), r.Recorder, r)
+	}))
+}
+
+// wantEnqueuedAfter checks that the Replay was enqueued again after the delay.
+func wantEnqueuedAfter(want time.Duration) func(*testing.T, *TableRow) {
+	return func(t *testing.T, r *TableRow) {
+		if got, _ := r.OtherTestData["enqueuedAfter"].(time.Duration); got != want {
+			t.Errorf("Replay enqueued after %v, want %v", got, want)
+		}
+	}
+}
+
+func patchFinalizers(namespace, name, finalizer string) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var finalizers []string
+	if finalizer != "" {
+		finalizers = append(finalizers, fmt.Sprintf("%q", finalizer))
+	}
+	patch := `{"metadata":{"finalizers":[` + strings.Join(finalizers, ",") + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func makeDataPlanePod(t *testing.T, name string, progress map[string]replay.Progress) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace(),
+			Name:      name,
+			Labels:    brokercellresources.CommonLabels(brokerresources.DefaultBrokerCellName),
+		},
+	}
+	if progress != nil {
+		b, err := json.Marshal(progress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pod.Annotations = map[string]string{replay.ProgressAnnotation: string(b)}
+	}
+	return pod
+}
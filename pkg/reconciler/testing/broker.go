@@ -169,6 +169,37 @@ func WithBrokerClass(bc string) BrokerOption {
 	}
 }
 
+// WithBrokerOrderingKey sets the Broker's ordering key attribute annotation.
+func WithBrokerOrderingKey(attr string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.OrderingKeyAnnotation] = attr
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerMessageRetention sets the Broker's message retention annotation.
+func WithBrokerMessageRetention(retention string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.MessageRetentionAnnotation] = retention
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerProjectID sets the project recorded in the Broker's status.
+func WithBrokerProjectID(projectID string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Status.SetProjectID(projectID)
+	}
+}
+
 func WithBrokerSetDefaults(b *brokerv1beta1.Broker) {
 	b.SetDefaults(context.Background())
 }
@@ -48,6 +48,14 @@ func WithBrokerCellAnnotations(annotations map[string]string) BrokerCellOption {
 	}
 }
 
+// WithBrokerCellIngressPublishSettings sets the publish settings of the ingress. It must be applied
+// after WithBrokerCellSetDefaults.
+func WithBrokerCellIngressPublishSettings(settings *intv1alpha1.PublishSettings) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Spec.Components.Ingress.Publish = settings
+	}
+}
+
 // WithInitBrokerCellConditions initializes the BrokerCell's conditions.
 func WithInitBrokerCellConditions(bc *intv1alpha1.BrokerCell) {
 	bc.Status.InitializeConditions()
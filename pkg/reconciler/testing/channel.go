@@ -128,6 +128,12 @@ func WithChannelBrokerCellUnknown(reason, message string) ChannelOption {
 	}
 }
 
+func WithChannelProjectID(projectID string) ChannelOption {
+	return func(c *v1beta1.Channel) {
+		c.Status.ProjectID = projectID
+	}
+}
+
 func WithChannelSpec(spec v1beta1.ChannelSpec) ChannelOption {
 	return func(c *v1beta1.Channel) {
 		c.Spec = spec
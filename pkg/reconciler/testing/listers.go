@@ -41,6 +41,7 @@ import (
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	EventsV1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
@@ -48,6 +49,7 @@ import (
 	fakeeventsclientset "github.com/google/knative-gcp/pkg/client/clientset/versioned/fake"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
 	eventslisters "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	eventsv1alpha1listers "github.com/google/knative-gcp/pkg/client/listers/events/v1alpha1"
 	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1"
 	intlisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
 	inteventsv1beta1listers "github.com/google/knative-gcp/pkg/client/listers/intevents/v1beta1"
@@ -202,10 +204,18 @@ func (l *Listers) GetTriggerLister() brokerlisters.TriggerLister {
 	return brokerlisters.NewTriggerLister(l.indexerFor(&brokerv1beta1.Trigger{}))
 }
 
+func (l *Listers) GetEventSchemaLister() brokerlisters.EventSchemaLister {
+	return brokerlisters.NewEventSchemaLister(l.indexerFor(&brokerv1beta1.EventSchema{}))
+}
+
 func (l *Listers) GetBrokerCellLister() intlisters.BrokerCellLister {
 	return intlisters.NewBrokerCellLister(l.indexerFor(&intv1alpha1.BrokerCell{}))
 }
 
+func (l *Listers) GetReplayLister() eventsv1alpha1listers.ReplayLister {
+	return eventsv1alpha1listers.NewReplayLister(l.indexerFor(&eventsv1alpha1.Replay{}))
+}
+
 func (l *Listers) GetHPALister() hpav2beta2listers.HorizontalPodAutoscalerLister {
 	return hpav2beta2listers.NewHorizontalPodAutoscalerLister(l.indexerFor(&hpav2beta2.HorizontalPodAutoscaler{}))
 }
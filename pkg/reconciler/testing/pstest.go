@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
@@ -53,6 +54,17 @@ func SubscriptionWithTopic(id string, tid string) PubsubAction {
 	}
 }
 
+func SubscriptionWithConfig(id string, tid string, config pubsub.SubscriptionConfig) PubsubAction {
+	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
+		config.Topic = c.Topic(tid)
+		_, err := c.CreateSubscription(ctx, id, config)
+		if err != nil {
+			t.Fatalf("Error creating subscription %q: %v", id, err)
+		}
+		t.Logf("Created subscription %q", id)
+	}
+}
+
 func TopicAndSub(tid, sid string) PubsubAction {
 	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
 		Topic(tid)(ctx, t, c)
@@ -60,6 +72,17 @@ func TopicAndSub(tid, sid string) PubsubAction {
 	}
 }
 
+// Snapshot creates a snapshot of the subscription sid, which doesn't need to exist.
+func Snapshot(id, sid string) PubsubAction {
+	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
+		_, err := c.Subscription(sid).CreateSnapshot(ctx, id)
+		if err != nil {
+			t.Fatalf("Error creating snapshot %q: %v", id, err)
+		}
+		t.Logf("Created snapshot %q", id)
+	}
+}
+
 func TopicExists(id string) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
@@ -143,6 +166,34 @@ func SubscriptionExists(id string) func(*testing.T, *rtesting.TableRow) {
 	}
 }
 
+// TopicExistsInProject checks that the topic exists in the given project rather than in the
+// project of the test client.
+func TopicExistsInProject(id, projectID string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		exist, err := c.TopicInProject(id, projectID).Exists(context.Background())
+		if err != nil {
+			t.Errorf("Error checking topic existence: %v", err)
+		} else if !exist {
+			t.Errorf("Expected topic %q to exist in project %q", id, projectID)
+		}
+	}
+}
+
+// SubscriptionExistsInProject checks that the subscription exists in the given project rather
+// than in the project of the test client.
+func SubscriptionExistsInProject(id, projectID string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		exist, err := c.SubscriptionInProject(id, projectID).Exists(context.Background())
+		if err != nil {
+			t.Errorf("Error checking subscription existence: %v", err)
+		} else if !exist {
+			t.Errorf("Expected subscription %q to exist in project %q", id, projectID)
+		}
+	}
+}
+
 func SubscriptionDoesNotExist(id string) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
@@ -155,6 +206,47 @@ func SubscriptionDoesNotExist(id string) func(*testing.T, *rtesting.TableRow) {
 	}
 }
 
+func SnapshotExists(id string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		it := c.Snapshots(context.Background())
+		for {
+			snapshot, err := it.Next()
+			if err == iterator.Done {
+				t.Errorf("Expected snapshot %q to exist", id)
+				return
+			}
+			if err != nil {
+				t.Errorf("Error listing snapshots: %v", err)
+				return
+			}
+			if snapshot.ID() == id {
+				return
+			}
+		}
+	}
+}
+
+func SnapshotDoesNotExist(id string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		it := c.Snapshots(context.Background())
+		for {
+			snapshot, err := it.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				t.Errorf("Error listing snapshots: %v", err)
+				return
+			}
+			if snapshot.ID() == id {
+				t.Errorf("Expected snapshot %q not to exist", id)
+			}
+		}
+	}
+}
+
 func SubscriptionHasRetryPolicy(id string, wantPolicy *pubsub.RetryPolicy) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
@@ -183,6 +275,20 @@ func SubscriptionHasDeadLetterPolicy(id string, wantPolicy *pubsub.DeadLetterPol
 	}
 }
 
+func SubscriptionHasFilter(id string, want string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if cfg.Filter != want {
+			t.Errorf("Pubsub config filter got=%q, want=%q", cfg.Filter, want)
+		}
+	}
+}
+
 func OnlySubscriptions(ids ...string) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
@@ -226,6 +332,7 @@ func TestPubsubClient(ctx context.Context, projectID string, opts ...pstest.Serv
 	client, _ := GetTestClientCreateFunc(srv.Addr)(ctx, projectID)
 	close := func() {
 		srv.Close()
+		forgetSnapshots(srv.Addr)
 		client.Close()
 	}
 	return client, close
@@ -236,7 +343,7 @@ func TestPubsubClient(ctx context.Context, projectID string, opts ...pstest.Serv
 // multiple projects. Eg. in sources multiple project is allowed for topics.
 func GetTestClientCreateFunc(target string) func(context.Context, string, ...option.ClientOption) (*pubsub.Client, error) {
 	return func(ctx context.Context, projectID string, opts ...option.ClientOption) (*pubsub.Client, error) {
-		newConn, err := grpc.Dial(target, grpc.WithInsecure())
+		newConn, err := grpc.Dial(target, grpc.WithInsecure(), grpc.WithUnaryInterceptor(snapshotsOf(target).intercept))
 		if err != nil {
 			panic(fmt.Errorf("failed to dial test pubsub connection: %v", err))
 		}
@@ -265,3 +372,34 @@ func GetFailedTestClientCreateFunc(target string, maxCallTime int) func(context.
 		return GetTestClientCreateFunc(target)(ctx, projectID)
 	}
 }
+
+func SubscriptionHasMessageOrdering(id string, want bool) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if cfg.EnableMessageOrdering != want {
+			t.Errorf("Pubsub config message ordering got=%v, want=%v", cfg.EnableMessageOrdering, want)
+		}
+	}
+}
+
+func SubscriptionRetainsAckedMessages(id string, want time.Duration) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if !cfg.RetainAckedMessages {
+			t.Errorf("Pubsub config doesn't retain acked messages")
+		}
+		if cfg.RetentionDuration != want {
+			t.Errorf("Pubsub config retention duration got=%v, want=%v", cfg.RetentionDuration, want)
+		}
+	}
+}
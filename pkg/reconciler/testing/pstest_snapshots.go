@@ -0,0 +1,112 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "google.golang.org/genproto/googleapis/pubsub/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	createSnapshotMethod = "/google.pubsub.v1.Subscriber/CreateSnapshot"
+	deleteSnapshotMethod = "/google.pubsub.v1.Subscriber/DeleteSnapshot"
+	listSnapshotsMethod  = "/google.pubsub.v1.Subscriber/ListSnapshots"
+	seekMethod           = "/google.pubsub.v1.Subscriber/Seek"
+)
+
+var (
+	snapshotsMu sync.Mutex
+	// snapshots holds the fake snapshots of each pstest server, by address.
+	snapshots = map[string]*fakeSnapshots{}
+)
+
+// fakeSnapshots emulates the Pub/Sub snapshots, which pstest doesn't support. It only records which
+// snapshots exist, seeking a subscription to a snapshot leaves the subscription unchanged.
+type fakeSnapshots struct {
+	mu    sync.Mutex
+	names map[string]*pb.Snapshot
+}
+
+func snapshotsOf(target string) *fakeSnapshots {
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+	s, ok := snapshots[target]
+	if !ok {
+		s = &fakeSnapshots{names: map[string]*pb.Snapshot{}}
+		snapshots[target] = s
+	}
+	return s
+}
+
+// forgetSnapshots drops the fake snapshots of the pstest server at target, whose address may be
+// reused by a later server.
+func forgetSnapshots(target string) {
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+	delete(snapshots, target)
+}
+
+// intercept is a grpc.UnaryClientInterceptor answering the snapshot calls, and passing the others
+// through to pstest.
+func (s *fakeSnapshots) intercept(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch method {
+	case createSnapshotMethod:
+		req := req.(*pb.CreateSnapshotRequest)
+		if _, ok := s.names[req.Name]; ok {
+			return status.Errorf(codes.AlreadyExists, "snapshot %q already exists", req.Name)
+		}
+		snapshot := &pb.Snapshot{
+			Name:       req.Name,
+			ExpireTime: timestamppb.New(time.Now().Add(7 * 24 * time.Hour)),
+		}
+		s.names[req.Name] = snapshot
+		proto.Merge(reply.(*pb.Snapshot), snapshot)
+		return nil
+	case deleteSnapshotMethod:
+		req := req.(*pb.DeleteSnapshotRequest)
+		if _, ok := s.names[req.Snapshot]; !ok {
+			return status.Errorf(codes.NotFound, "snapshot %q not found", req.Snapshot)
+		}
+		delete(s.names, req.Snapshot)
+		return nil
+	case listSnapshotsMethod:
+		resp := reply.(*pb.ListSnapshotsResponse)
+		for _, snapshot := range s.names {
+			resp.Snapshots = append(resp.Snapshots, snapshot)
+		}
+		return nil
+	case seekMethod:
+		req := req.(*pb.SeekRequest)
+		if name := req.GetSnapshot(); name != "" {
+			if _, ok := s.names[name]; !ok {
+				return status.Errorf(codes.NotFound, "snapshot %q not found", name)
+			}
+			return nil
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+)
+
+// ReplayOption enables further configuration of a Replay.
+type ReplayOption func(*eventsv1alpha1.Replay)
+
+// NewReplay creates a Replay of the Trigger from the start time with ReplayOptions.
+func NewReplay(name, namespace, trigger string, startTime time.Time, o ...ReplayOption) *eventsv1alpha1.Replay {
+	r := &eventsv1alpha1.Replay{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: eventsv1alpha1.ReplaySpec{
+			Trigger:   trigger,
+			StartTime: metav1.NewTime(startTime),
+		},
+	}
+	for _, opt := range o {
+		opt(r)
+	}
+	return r
+}
+
+func WithReplayUID(uid string) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.UID = types.UID(uid)
+	}
+}
+
+func WithReplayCreationTimestamp(t time.Time) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.CreationTimestamp = metav1.NewTime(t)
+	}
+}
+
+func WithReplayFinalizers(finalizers ...string) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.Finalizers = finalizers
+	}
+}
+
+func WithReplayDeletionTimestamp(r *eventsv1alpha1.Replay) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	r.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithReplayTTLSecondsAfterFinished(ttl int32) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.Spec.TTLSecondsAfterFinished = &ttl
+	}
+}
+
+// WithInitReplayConditions initializes the Replay's conditions.
+func WithInitReplayConditions(r *eventsv1alpha1.Replay) {
+	r.Status.InitializeConditions()
+}
+
+// WithReplaySubscription sets the temporary subscription of the Replay, which may not be seeked
+// yet.
+func WithReplaySubscription(projectID, subscriptionID string) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.Status.ProjectID = projectID
+		r.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithReplaySubscriptionSeeked(subscriptionID string, endTime time.Time) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.Status.MarkSubscriptionSeeked(subscriptionID, metav1.NewTime(endTime))
+	}
+}
+
+func WithReplaySubscriptionNotSeeked(reason, message string) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.Status.MarkSubscriptionNotSeeked(reason, message)
+	}
+}
+
+func WithReplaySubscriptionDeleted(r *eventsv1alpha1.Replay) {
+	r.Status.MarkSubscriptionDeleted()
+}
+
+func WithReplayReplaying(replayedThrough *time.Time, replayedEvents int64) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		var t *metav1.Time
+		if replayedThrough != nil {
+			mt := metav1.NewTime(*replayedThrough)
+			t = &mt
+		}
+		r.Status.MarkReplaying(t, replayedEvents)
+	}
+}
+
+func WithReplayReplayed(completionTime time.Time) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.Status.MarkReplayed(metav1.NewTime(completionTime))
+	}
+}
+
+func WithReplayFailed(reason, message string) ReplayOption {
+	return func(r *eventsv1alpha1.Replay) {
+		r.Status.MarkReplayFailed(reason, message)
+	}
+}
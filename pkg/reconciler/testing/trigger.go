@@ -183,6 +183,26 @@ func WithTriggerSubscriberResolvedUnknown(reason, message string) TriggerOption
 	}
 }
 
+func WithTriggerDeadLetterSinkResolvedSucceeded(t *brokerv1beta1.Trigger) {
+	t.Status.MarkDeadLetterSinkResolvedSucceeded()
+}
+
+func WithTriggerDeadLetterSinkNotConfigured(t *brokerv1beta1.Trigger) {
+	t.Status.MarkDeadLetterSinkNotConfigured()
+}
+
+func WithTriggerDeadLetterSinkResolvedFailed(reason, message string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkDeadLetterSinkResolvedFailed(reason, message)
+	}
+}
+
+func WithTriggerSubscriberUnavailable(reason, message string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkSubscriberUnavailable(reason, message)
+	}
+}
+
 func WithTriggerSubscriptionReady(t *brokerv1beta1.Trigger) {
 	t.Status.MarkSubscriptionReady("")
 }
@@ -214,12 +234,27 @@ func WithTriggerUID(uid string) TriggerOption {
 	}
 }
 
+// WithTriggerProjectID sets the project recorded in the Trigger's status.
+func WithTriggerProjectID(projectID string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.SetProjectID(projectID)
+	}
+}
+
 func WithTriggerFinalizers(finalizers ...string) TriggerOption {
 	return func(t *brokerv1beta1.Trigger) {
 		t.Finalizers = finalizers
 	}
 }
 
+func WithTriggerFilterAttributes(attributes map[string]string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Spec.Filter = &eventingv1beta1.TriggerFilter{
+			Attributes: attributes,
+		}
+	}
+}
+
 func WithTriggerSetDefaults(t *brokerv1beta1.Trigger) {
 	t.SetDefaults(context.Background())
 }
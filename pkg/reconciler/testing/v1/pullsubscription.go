@@ -150,6 +150,24 @@ func WithPullSubscriptionTransformerURI(uri *apis.URL) PullSubscriptionOption {
 	}
 }
 
+func WithPullSubscriptionDeadLetterSinkURI(uri *apis.URL) PullSubscriptionOption {
+	return func(s *v1.PullSubscription) {
+		s.Status.DeadLetterSinkURI = uri
+	}
+}
+
+func WithPullSubscriptionMarkDeadLetterSink(uri *apis.URL) PullSubscriptionOption {
+	return func(s *v1.PullSubscription) {
+		s.Status.MarkDeadLetterSink(uri)
+	}
+}
+
+func WithPullSubscriptionMarkNoDeadLetterSink(reason, message string) PullSubscriptionOption {
+	return func(s *v1.PullSubscription) {
+		s.Status.MarkNoDeadLetterSink(reason, message)
+	}
+}
+
 func WithPullSubscriptionMarkNoSubscription(reason, message string) PullSubscriptionOption {
 	return func(s *v1.PullSubscription) {
 		s.Status.MarkNoSubscription(reason, message)
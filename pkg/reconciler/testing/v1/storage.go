@@ -224,6 +224,18 @@ func WithCloudStorageSourceNotificationID(notificationID string) CloudStorageSou
 	}
 }
 
+func WithCloudStorageSourceBackfill(backfill *v1.CloudStorageBackfill) CloudStorageSourceOption {
+	return func(s *v1.CloudStorageSource) {
+		s.Spec.Backfill = backfill
+	}
+}
+
+func WithCloudStorageSourceBackfillStatus(status *v1.CloudStorageBackfillStatus) CloudStorageSourceOption {
+	return func(s *v1.CloudStorageSource) {
+		s.Status.Backfill = status
+	}
+}
+
 // WithCloudStorageSourceProjectId sets the status for Project ID.
 func WithCloudStorageSourceProjectID(projectID string) CloudStorageSourceOption {
 	return func(s *v1.CloudStorageSource) {
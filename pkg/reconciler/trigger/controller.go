@@ -23,7 +23,9 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/google/knative-gcp/pkg/logging"
@@ -31,18 +33,25 @@ import (
 	"knative.dev/eventing/pkg/duck"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/source"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	pkgcontroller "knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
+	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
+	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	reconcilerutils "github.com/google/knative-gcp/pkg/reconciler/utils"
 	"github.com/google/knative-gcp/pkg/utils"
 )
@@ -92,9 +101,11 @@ func newController(ctx context.Context, cmw configmap.Watcher, drs *dataresidenc
 	r := &Reconciler{
 		Base:         reconciler.NewBase(ctx, controllerAgentName, cmw),
 		brokerLister: brokerinformer.Get(ctx).Lister(),
+		podLister:    podinformer.Get(ctx).Lister(),
 		targetReconciler: &celltenant.TargetReconciler{
 			ProjectID:          projectID,
 			PubsubClient:       client,
+			Projects:           celltenant.NewNamespaceProjects(namespaceinformer.Get(ctx).Lister()),
 			DataresidencyStore: drs,
 		},
 	}
@@ -128,9 +139,55 @@ func newController(ctx context.Context, cmw configmap.Watcher, drs *dataresidenc
 		},
 	)
 
+	// Watch the broker data plane pods, which annotate themselves with their open circuit breakers.
+	podinformer.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: pkgreconciler.ChainFilterFuncs(
+			pkgreconciler.NamespaceFilterFunc(system.Namespace()),
+			pkgreconciler.LabelExistsFilterFunc(brokercellresources.BrokerCellLabelKey),
+		),
+		Handler: handleOpenCircuitsChange(triggerInformer.Lister(), impl.Enqueue),
+	})
+
 	return impl
 }
 
+// handleOpenCircuitsChange enqueues the Triggers whose circuit breakers opened or closed in a
+// broker data plane pod.
+func handleOpenCircuitsChange(triggerLister brokerlisters.TriggerLister, enqueue func(interface{})) cache.ResourceEventHandler {
+	openCircuits := func(obj interface{}) sets.String {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			return sets.NewString(circuitbreaker.OpenCircuitIDs(pod.GetAnnotations())...)
+		}
+		return sets.NewString()
+	}
+	enqueueTriggers := func(uids sets.String) {
+		if uids.Len() == 0 {
+			return
+		}
+		triggers, err := triggerLister.List(labels.Everything())
+		if err != nil {
+			return
+		}
+		for _, t := range triggers {
+			if uids.Has(string(t.UID)) {
+				enqueue(t)
+			}
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			enqueueTriggers(openCircuits(obj))
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldIDs, newIDs := openCircuits(oldObj), openCircuits(newObj)
+			enqueueTriggers(oldIDs.Difference(newIDs).Union(newIDs.Difference(oldIDs)))
+		},
+		DeleteFunc: func(obj interface{}) {
+			enqueueTriggers(openCircuits(obj))
+		},
+	}
+}
+
 func withAgentAndFinalizer(_ *pkgcontroller.Impl) pkgcontroller.Options {
 	return pkgcontroller.Options{
 		FinalizerName: finalizerName,
@@ -19,12 +19,15 @@ package trigger
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/google/knative-gcp/pkg/reconciler/celltenant"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 
 	"github.com/google/knative-gcp/pkg/logging"
 	"knative.dev/eventing/pkg/duck"
@@ -33,9 +36,11 @@ import (
 	"knative.dev/pkg/resolver"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	reconcilerutils "github.com/google/knative-gcp/pkg/reconciler/utils"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 )
@@ -53,6 +58,9 @@ type Reconciler struct {
 
 	brokerLister brokerlisters.BrokerLister
 
+	// podLister lists the broker data plane pods, which annotate themselves with their open circuit breakers.
+	podLister corev1listers.PodLister
+
 	// Dynamic tracker to track sources. It tracks the dependency between Triggers and Sources.
 	sourceTracker duck.ListableTracker
 
@@ -132,6 +140,14 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 		b.SetDefaults(ctx)
 	}
 
+	if err := r.resolveDeadLetterSink(ctx, t, b); err != nil {
+		return err
+	}
+
+	if t.Status.ProjectID() == "" && b.Status.ProjectID() != "" {
+		// The data plane uses the project of the Broker for the retry topics of its Triggers.
+		t.Status.SetProjectID(b.Status.ProjectID())
+	}
 	ct := celltenant.TargetFromTrigger(t, b.Spec.Delivery)
 	if err := r.targetReconciler.ReconcileRetryTopicAndSubscription(ctx, r.Recorder, ct); err != nil {
 		return err
@@ -141,6 +157,8 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 		return err
 	}
 
+	r.reconcileSubscriberAvailability(ctx, t)
+
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, triggerReconciled, "Trigger reconciled: \"%s/%s\"", t.Namespace, t.Name)
 }
 
@@ -183,6 +201,60 @@ func (r *Reconciler) resolveSubscriber(ctx context.Context, t *brokerv1beta1.Tri
 	return nil
 }
 
+// resolveDeadLetterSink checks that the dead letter sink of the Broker's delivery spec can be
+// resolved. Pub/Sub topic dead letter sinks are used as is by the retry subscription, any other
+// dead letter sink is resolved to the URI that the retry pool sends exhausted events to.
+func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, t *brokerv1beta1.Trigger, b *brokerv1beta1.Broker) error {
+	if b.Spec.Delivery == nil || b.Spec.Delivery.DeadLetterSink == nil {
+		t.Status.MarkDeadLetterSinkNotConfigured()
+		return nil
+	}
+	if brokerv1beta1.IsPubSubDeadLetterSink(b.Spec.Delivery.DeadLetterSink) {
+		t.Status.MarkDeadLetterSinkResolvedSucceeded()
+		return nil
+	}
+
+	dls := b.Spec.Delivery.DeadLetterSink.DeepCopy()
+	if dls.Ref != nil && dls.Ref.Namespace == "" {
+		// The dead letter sink is relative to the Broker.
+		dls.Ref.Namespace = b.GetNamespace()
+	}
+	if _, err := r.uriResolver.URIFromDestinationV1(ctx, *dls, t); err != nil {
+		logging.FromContext(ctx).Error("Unable to get the dead letter sink's URI", zap.Error(err))
+		t.Status.MarkDeadLetterSinkResolvedFailed("DeadLetterSinkResolveFailed", "Unable to get the dead letter sink's URI: %v", err)
+		return err
+	}
+	t.Status.MarkDeadLetterSinkResolvedSucceeded()
+	return nil
+}
+
+// reconcileSubscriberAvailability marks the subscriber unavailable while any broker data plane pod
+// has an open circuit breaker for the Trigger. The condition is informational, a Trigger with an
+// unavailable subscriber is still ready.
+func (r *Reconciler) reconcileSubscriberAvailability(ctx context.Context, t *brokerv1beta1.Trigger) {
+	pods, err := brokerresources.ListBrokerCellPods(r.podLister)
+	if err != nil {
+		// Leave the condition as is, it will be updated on the next reconciliation.
+		logging.FromContext(ctx).Error("Failed to list broker data plane pods", zap.Error(err))
+		return
+	}
+	var openPods []string
+	for _, pod := range pods {
+		for _, id := range circuitbreaker.OpenCircuitIDs(pod.GetAnnotations()) {
+			if id == string(t.UID) {
+				openPods = append(openPods, pod.Name)
+				break
+			}
+		}
+	}
+	if len(openPods) == 0 {
+		t.Status.ClearSubscriberAvailable()
+		return
+	}
+	sort.Strings(openPods)
+	t.Status.MarkSubscriberUnavailable("CircuitBreakerOpen", "The circuit breaker of the subscriber is open in pods: %s", strings.Join(openPods, ", "))
+}
+
 // hasGCPBrokerFinalizer checks if the Trigger object has a finalizer matching the one added by this controller.
 func hasGCPBrokerFinalizer(t *brokerv1beta1.Trigger) bool {
 	for _, f := range t.Finalizers {
@@ -43,12 +43,16 @@ import (
 	"knative.dev/pkg/controller"
 	. "knative.dev/pkg/reconciler/testing"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
+	"github.com/google/knative-gcp/pkg/broker/circuitbreaker"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1alpha1/resource"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 )
 
@@ -58,6 +62,7 @@ const (
 	brokerName        = "test-broker"
 	testUID           = "abc123"
 	testProject       = "test-project-id"
+	tenantProject     = "tenant-project-id"
 	testClusterRegion = "us-east1"
 
 	subscriberURI     = "http://example.com/subscriber/"
@@ -310,7 +315,9 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
 					WithTriggerSetDefaults,
 				),
 			}},
@@ -367,6 +374,52 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 			WantErr: true,
 		},
+		{
+			Name: "Dead letter sink doesn't exist",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(&eventingduckv1beta1.DeliverySpec{
+						DeadLetterSink: &duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: subscriberAPIVersion,
+								Kind:       subscriberKind,
+								Name:       "dead-letter-sink",
+							},
+						},
+					}),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerDeadLetterSinkResolvedFailed("DeadLetterSinkResolveFailed", `Unable to get the dead letter sink's URI: services.serving.knative.dev "dead-letter-sink" not found`),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", `services.serving.knative.dev "dead-letter-sink" not found`),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			WantErr: true,
+		},
 		{
 			Name: "Trigger created, broker ready, subscriber is addressable",
 			Key:  testKey,
@@ -393,7 +446,132 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 3,
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+					}),
+				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
+					Labels: map[string]string{
+						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
+					},
+				}),
+			},
+		},
+		{
+			Name: "Trigger created, retry topic and subscription are created in the project of the broker",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerProjectID(tenantProject),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+				NewNamespace(testNS,
+					WithNamespaceAnnotations(map[string]string{brokerv1beta1.ProjectAnnotation: "other-project-id"})),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(tenantProject),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("test-dead-letter-topic-id"),
+				TopicExistsInProject("cre-tgr_testnamespace_test-trigger_abc123", tenantProject),
+				SubscriptionExistsInProject("cre-tgr_testnamespace_test-trigger_abc123", tenantProject),
+			},
+		},
+		{
+			Name: "Trigger created with filter attributes, retry subscription is filtered",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerFilterAttributes(map[string]string{"type": "com.example.created", "region": ""}),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerFilterAttributes(map[string]string{"type": "com.example.created", "region": ""}),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
 					WithTriggerSetDefaults,
 				),
 			}},
@@ -414,6 +592,8 @@ func TestAllCasesTrigger(t *testing.T) {
 			PostConditions: []func(*testing.T, *TableRow){
 				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
 				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasFilter("cre-tgr_testnamespace_test-trigger_abc123",
+					`attributes:"ce-region" AND attributes."ce-type" = "com.example.created"`),
 				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
 					&pubsub.RetryPolicy{
 						MaximumBackoff: 5 * time.Second,
@@ -431,6 +611,58 @@ func TestAllCasesTrigger(t *testing.T) {
 				}),
 			},
 		},
+		{
+			Name: "Circuit breaker of the subscriber is open",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+				makeDataPlanePod("default-brokercell-fanout-1", testUID),
+				makeDataPlanePod("default-brokercell-fanout-2", "other-trigger-uid"),
+				makeDataPlanePod("default-brokercell-retry-1", "other-trigger-uid,"+testUID),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
+					WithTriggerSubscriberUnavailable("CircuitBreakerOpen",
+						"The circuit breaker of the subscriber is open in pods: default-brokercell-fanout-1, default-brokercell-retry-1"),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+		},
 		{
 			Name: "Sub already exists, update config",
 			Key:  testKey,
@@ -457,7 +689,9 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
 					WithTriggerSetDefaults,
 				),
 			}},
@@ -516,7 +750,9 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
 					WithTriggerSetDefaults,
 				),
 			}},
@@ -583,7 +819,9 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
 					WithTriggerSetDefaults,
 				),
 			}},
@@ -651,7 +889,9 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
 					WithTriggerSetDefaults,
 				),
 			}},
@@ -717,7 +957,9 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerDeadLetterSinkResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerProjectID(testProject),
 					WithTriggerSetDefaults,
 					WithTriggerDependencyUnknown("", ""),
 					WithTriggerTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
@@ -769,6 +1011,7 @@ func TestAllCasesTrigger(t *testing.T) {
 		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
 		// will create one in demand
 		testPSClient := psclient
+		celltenant.CreatePubsubClientFn = GetTestClientCreateFunc(srv.Addr)
 		if maxTime, ok := testData["maxPSClientCreateTime"]; ok {
 			// Overwrite the createPubsubClientFn to one that failed when called more than maxTime times.
 			// maxTime=0 is used to inject error
@@ -783,12 +1026,14 @@ func TestAllCasesTrigger(t *testing.T) {
 		r := &Reconciler{
 			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
 			brokerLister:       listers.GetBrokerLister(),
+			podLister:          listers.GetPodLister(),
 			sourceTracker:      duck.NewListableTracker(ctx, source.Get, func(types.NamespacedName) {}, 0),
 			addressableTracker: duck.NewListableTracker(ctx, addressable.Get, func(types.NamespacedName) {}, 0),
 			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 			targetReconciler: &celltenant.TargetReconciler{
 				ProjectID:          testProject,
 				PubsubClient:       testPSClient,
+				Projects:           celltenant.NewNamespaceProjects(listers.GetNamespaceLister()),
 				DataresidencyStore: drStore,
 				ClusterRegion:      testClusterRegion,
 			},
@@ -798,6 +1043,17 @@ func TestAllCasesTrigger(t *testing.T) {
 	}))
 }
 
+func makeDataPlanePod(name, openCircuits string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   system.Namespace(),
+			Name:        name,
+			Labels:      brokercellresources.CommonLabels(brokerresources.DefaultBrokerCellName),
+			Annotations: map[string]string{circuitbreaker.OpenCircuitsAnnotation: openCircuits},
+		},
+	}
+}
+
 func makeSubscriberAddressableAsUnstructured() *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/rickb777/date/period"
+	"go.uber.org/zap"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// DefaultMinimumBackoff is the minimum backoff duration used in the retry
+	// policy for pubsub subscriptions when no delivery spec is set.
+	DefaultMinimumBackoff = 1 * time.Second
+	// DefaultMaximumBackoff is the maximum backoff duration used in the backoff
+	// retry policy for pubsub subscriptions. 600 seconds is the longest supported time.
+	DefaultMaximumBackoff = 600 * time.Second
+)
+
+// RetryPolicy gets the eventing retry policy from a delivery spec and
+// translates it to a pubsub retry policy.
+func RetryPolicy(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *pubsub.RetryPolicy {
+	if spec == nil {
+		return &pubsub.RetryPolicy{
+			MinimumBackoff: DefaultMinimumBackoff,
+			MaximumBackoff: DefaultMaximumBackoff,
+		}
+	}
+	// The Broker delivery spec is translated to a pubsub retry policy in the
+	// manner defined in the following post:
+	// https://github.com/google/knative-gcp/issues/1392#issuecomment-655617873
+
+	var minimumBackoff time.Duration
+	if spec.BackoffDelay != nil {
+		p, err := period.Parse(*spec.BackoffDelay)
+		if err != nil {
+			// Not actually fatal, we will just use zero, rather than the stored value. But log an
+			// error so that we are aware of the issue.
+			logging.FromContext(ctx).Error("Unable to parse DeliverySpec.BackoffDelay",
+				zap.Error(err), zap.Stringp("backoffDelay", spec.BackoffDelay))
+		} else {
+			minimumBackoff, _ = p.Duration()
+		}
+	}
+
+	var backoffPolicy eventingduckv1beta1.BackoffPolicyType
+	if spec.BackoffPolicy != nil {
+		backoffPolicy = *spec.BackoffPolicy
+	} else {
+		// Default to Exponential.
+		backoffPolicy = eventingduckv1beta1.BackoffPolicyExponential
+	}
+
+	var maximumBackoff time.Duration
+	switch backoffPolicy {
+	case eventingduckv1beta1.BackoffPolicyLinear:
+		maximumBackoff = minimumBackoff
+	case eventingduckv1beta1.BackoffPolicyExponential:
+		maximumBackoff = DefaultMaximumBackoff
+	}
+	return &pubsub.RetryPolicy{
+		MinimumBackoff: minimumBackoff,
+		MaximumBackoff: maximumBackoff,
+	}
+}
+
+// DeadLetterPolicy gets the eventing dead letter policy from a delivery spec
+// and translates it to a pubsub dead letter policy.
+// Only Pub/Sub topic dead letter sinks are handled by Pub/Sub, any other
+// dead letter sink is handled by the retry pool.
+func DeadLetterPolicy(projectID string, spec *eventingduckv1beta1.DeliverySpec) *pubsub.DeadLetterPolicy {
+	if spec == nil || !brokerv1beta1.IsPubSubDeadLetterSink(spec.DeadLetterSink) {
+		return nil
+	}
+	// Translate to the pubsub dead letter policy format.
+
+	dlp := &pubsub.DeadLetterPolicy{
+		DeadLetterTopic: fmt.Sprintf("projects/%s/topics/%s", projectID, spec.DeadLetterSink.URI.Host),
+	}
+	if spec.Retry != nil {
+		dlp.MaxDeliveryAttempts = int(*spec.Retry)
+	}
+	return dlp
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	linear := eventingduckv1beta1.BackoffPolicyLinear
+	exponential := eventingduckv1beta1.BackoffPolicyExponential
+	testCases := []struct {
+		name string
+		spec *eventingduckv1beta1.DeliverySpec
+		want *pubsub.RetryPolicy
+	}{{
+		name: "nil spec",
+		want: &pubsub.RetryPolicy{MinimumBackoff: DefaultMinimumBackoff, MaximumBackoff: DefaultMaximumBackoff},
+	}, {
+		name: "linear",
+		spec: &eventingduckv1beta1.DeliverySpec{BackoffDelay: ptr.String("PT5S"), BackoffPolicy: &linear},
+		want: &pubsub.RetryPolicy{MinimumBackoff: 5 * time.Second, MaximumBackoff: 5 * time.Second},
+	}, {
+		name: "exponential",
+		spec: &eventingduckv1beta1.DeliverySpec{BackoffDelay: ptr.String("PT5S"), BackoffPolicy: &exponential},
+		want: &pubsub.RetryPolicy{MinimumBackoff: 5 * time.Second, MaximumBackoff: DefaultMaximumBackoff},
+	}, {
+		name: "default policy is exponential",
+		spec: &eventingduckv1beta1.DeliverySpec{BackoffDelay: ptr.String("PT5S")},
+		want: &pubsub.RetryPolicy{MinimumBackoff: 5 * time.Second, MaximumBackoff: DefaultMaximumBackoff},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := RetryPolicy(context.Background(), tc.spec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected retry policy (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDeadLetterPolicy(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *eventingduckv1beta1.DeliverySpec
+		want *pubsub.DeadLetterPolicy
+	}{{
+		name: "nil spec",
+	}, {
+		name: "http dead letter sink",
+		spec: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter-sink")},
+		},
+	}, {
+		name: "pubsub dead letter sink",
+		spec: &eventingduckv1beta1.DeliverySpec{
+			Retry:          ptr.Int32(10),
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: topic}},
+		},
+		want: &pubsub.DeadLetterPolicy{
+			DeadLetterTopic:     "projects/" + project + "/topics/" + topic,
+			MaxDeliveryAttempts: 10,
+		},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DeadLetterPolicy(project, tc.spec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected dead letter policy (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
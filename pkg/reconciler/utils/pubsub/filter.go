@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+)
+
+// The binary Pub/Sub encoding of CloudEvents stores the context attributes and extensions in
+// message attributes with this prefix, except for the data content type.
+const ceAttributePrefix = "ce-"
+
+// filterableContextAttributes maps the context attributes that Trigger filters match against the
+// same string as the one in Pub/Sub messages to the key of that message attribute.
+var filterableContextAttributes = map[string]string{
+	"specversion":     ceAttributePrefix + "specversion",
+	"type":            ceAttributePrefix + "type",
+	"source":          ceAttributePrefix + "source",
+	"subject":         ceAttributePrefix + "subject",
+	"id":              ceAttributePrefix + "id",
+	"datacontenttype": "Content-Type",
+}
+
+// unfilterableContextAttributes are the other context attributes that Trigger filters match, but
+// that are formatted differently, or not at all, in Pub/Sub messages.
+var unfilterableContextAttributes = sets.NewString("time", "schemaurl", "dataschema", "datamediatype", "datacontentencoding")
+
+// AttributesFilter returns the Pub/Sub subscription filter that only lets through the messages
+// holding binary encoded CloudEvents that can pass the exact match attributes filter of a Trigger.
+// An empty value matches any value, as in Trigger filters. Attributes that are formatted
+// differently in Pub/Sub messages, such as time, are left to the Trigger filter. The returned
+// filter is empty if nothing can be filtered, or if it would be longer than Pub/Sub accepts.
+func AttributesFilter(attributes map[string]string) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var clauses []string
+	for _, name := range names {
+		value := attributes[name]
+		key, isContextAttribute := filterableContextAttributes[name]
+		switch {
+		case isContextAttribute && value == "":
+			// Context attributes are always set, so any value matches.
+			continue
+		case isContextAttribute:
+		case !unfilterableContextAttributes.Has(name) && event.IsAlphaNumeric(name) && name == strings.ToLower(name):
+			key = ceAttributePrefix + name
+		default:
+			continue
+		}
+		if value == "" {
+			clauses = append(clauses, "attributes:"+duck.QuoteSubscriptionFilterString(key))
+		} else {
+			clauses = append(clauses, "attributes."+duck.QuoteSubscriptionFilterString(key)+" = "+duck.QuoteSubscriptionFilterString(value))
+		}
+	}
+	filter := strings.Join(clauses, " AND ")
+	if len(filter) > duck.MaxSubscriptionFilterLength {
+		return ""
+	}
+	return filter
+}
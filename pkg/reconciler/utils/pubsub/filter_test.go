@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+)
+
+func TestAttributesFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		want       string
+	}{{
+		name: "no attributes",
+	}, {
+		name: "context attributes and extensions",
+		attributes: map[string]string{
+			"type":            "com.example.created",
+			"source":          "//example.com",
+			"datacontenttype": "application/json",
+			"region":          "us",
+		},
+		want: `attributes."Content-Type" = "application/json" AND attributes."ce-region" = "us" AND attributes."ce-source" = "//example.com" AND attributes."ce-type" = "com.example.created"`,
+	}, {
+		name: "any value",
+		attributes: map[string]string{
+			"type":   "",
+			"region": "",
+		},
+		want: `attributes:"ce-region"`,
+	}, {
+		name: "unfilterable attributes",
+		attributes: map[string]string{
+			"time":      "2021-01-01T00:00:00Z",
+			"schemaurl": "https://example.com/schema",
+			"Region":    "us",
+			"type":      `com.example."quoted"`,
+		},
+		want: `attributes."ce-type" = "com.example.\"quoted\""`,
+	}, {
+		name: "too long",
+		attributes: map[string]string{
+			"type": strings.Repeat("a", duck.MaxSubscriptionFilterLength),
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := AttributesFilter(tc.attributes)
+			if got != tc.want {
+				t.Errorf("AttributesFilter() = %q, want %q", got, tc.want)
+			}
+			if err := duck.ValidateSubscriptionFilter(got); err != nil {
+				t.Errorf("AttributesFilter() = %q, which is invalid: %v", got, err)
+			}
+		})
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +37,7 @@ const (
 	subCreated       = "SubscriptionCreated"
 	subDeleted       = "SubscriptionDeleted"
 	subConfigUpdated = "SubscriptionConfigUpdated"
+	subRecreated     = "SubscriptionRecreated"
 )
 
 func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subConfig pubsub.SubscriptionConfig, obj runtime.Object, updater StatusUpdater) (*pubsub.Subscription, error) {
@@ -66,6 +69,19 @@ func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subCo
 			}
 			return r.createSubscription(ctx, id, subConfig, obj, updater)
 		}
+		// The filter of a subscription is immutable, so a new filter requires a new subscription.
+		if config.Filter != subConfig.Filter {
+			logger.Info("Detected a new filter. Going to recreate the subscription.", zap.String("filter", subConfig.Filter))
+			sub, err = RecreateSubscription(ctx, r.client, sub, subConfig)
+			if err != nil {
+				logger.Error("Failed to recreate Pub/Sub subscription", zap.Error(err))
+				updater.MarkSubscriptionFailed("SubscriptionRecreationFailed", "Failed to recreate Pub/Sub subscription with the new filter: %v", err)
+				return nil, err
+			}
+			r.recorder.Eventf(obj, corev1.EventTypeNormal, subRecreated, "Recreated PubSub subscription %q with filter %q", sub.ID(), subConfig.Filter)
+			updater.MarkSubscriptionReady(sub.ID())
+			return sub, nil
+		}
 		// Update the subscription config in case the retry or dead letter policy changed. A nil policy indicates no change.
 		if (subConfig.RetryPolicy != nil && !equality.Semantic.DeepEqual(config.RetryPolicy, subConfig.RetryPolicy)) ||
 			(subConfig.DeadLetterPolicy != nil && !equality.Semantic.DeepEqual(config.DeadLetterPolicy, subConfig.DeadLetterPolicy)) {
@@ -132,3 +148,36 @@ func (r *Reconciler) createSubscription(ctx context.Context, id string, subConfi
 	updater.MarkSubscriptionReady(sub.ID())
 	return sub, nil
 }
+
+// RecreateSubscription replaces sub with a new subscription of the same ID and the given config.
+// It is used to change the immutable settings of a subscription, such as its filter. The backlog
+// of sub is kept in a snapshot, named after the subscription, that the new subscription is seeked
+// to, so that the messages that weren't acknowledged, or were published while the subscription
+// was recreated, are still delivered. sub is left untouched if the snapshot can't be created. If
+// the recreation fails after sub is deleted, the snapshot is kept and named in the error, so that
+// the new subscription can be seeked to it manually.
+func RecreateSubscription(ctx context.Context, client *pubsub.Client, sub *pubsub.Subscription, subConfig pubsub.SubscriptionConfig) (*pubsub.Subscription, error) {
+	if _, err := sub.CreateSnapshot(ctx, sub.ID()); err != nil {
+		// A previous recreation was interrupted before the subscription was deleted. Its snapshot is
+		// reused, only the messages acknowledged since it was taken are delivered again.
+		if status.Code(err) != codes.AlreadyExists {
+			return nil, fmt.Errorf("failed to create snapshot of the subscription: %w", err)
+		}
+	}
+	if err := sub.Delete(ctx); err != nil {
+		return nil, fmt.Errorf("failed to delete the subscription: %w", err)
+	}
+	newSub, err := client.CreateSubscription(ctx, sub.ID(), subConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the subscription, its backlog is kept in snapshot %q: %w", sub.ID(), err)
+	}
+	snapshot := client.Snapshot(sub.ID())
+	if err := newSub.SeekToSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to seek the subscription, its backlog is kept in snapshot %q: %w", snapshot.ID(), err)
+	}
+	if err := snapshot.Delete(ctx); err != nil {
+		// Snapshots expire on their own, so a leftover one is harmless.
+		logging.FromContext(ctx).Warn("Failed to delete the snapshot of the recreated subscription", zap.String("snapshot", snapshot.ID()), zap.Error(err))
+	}
+	return newSub, nil
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/iterator"
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 
@@ -78,6 +79,49 @@ func TestReconcileSub(t *testing.T) {
 			},
 			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
 		},
+		{
+			name: "new sub created with filter",
+			pre:  []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic)},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				Filter: `attributes:domain`,
+			},
+			wantEvents:       []string{`Normal SubscriptionCreated Created PubSub subscription "test-sub"`},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+		{
+			name: "sub already exists, filter changed",
+			pre:  []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub)},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				Filter: `attributes:domain`,
+			},
+			wantEvents:       []string{`Normal SubscriptionRecreated Recreated PubSub subscription "test-sub" with filter "attributes:domain"`},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+		{
+			name: "sub already exists, filter changed, snapshot of an interrupted recreation",
+			pre: []reconcilertesting.PubsubAction{
+				reconcilertesting.TopicAndSub(topic, sub),
+				reconcilertesting.Snapshot(sub, sub),
+			},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				Filter: `attributes:domain`,
+			},
+			wantEvents:       []string{`Normal SubscriptionRecreated Recreated PubSub subscription "test-sub" with filter "attributes:domain"`},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+		{
+			name: "new sub created, snapshot named after it left untouched",
+			pre: []reconcilertesting.PubsubAction{
+				reconcilertesting.Topic(topic),
+				reconcilertesting.Snapshot(sub, sub),
+			},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				Filter: `attributes:domain`,
+			},
+			wantEvents:       []string{`Normal SubscriptionCreated Created PubSub subscription "test-sub"`},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+			wantSnapshots:    []string{sub},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -90,6 +134,7 @@ func TestReconcileSub(t *testing.T) {
 				subConfig.Labels = tc.wantSubConfig.Labels
 				subConfig.RetryPolicy = tc.wantSubConfig.RetryPolicy
 				subConfig.DeadLetterPolicy = tc.wantSubConfig.DeadLetterPolicy
+				subConfig.Filter = tc.wantSubConfig.Filter
 			}
 			res, err := r.ReconcileSubscription(context.Background(), sub, subConfig, obj, su)
 
@@ -97,6 +142,7 @@ func TestReconcileSub(t *testing.T) {
 			if res != nil {
 				verifySub(t, res, subConfig)
 			}
+			verifySnapshots(t, tr.client, tc.wantSnapshots)
 		})
 	}
 
@@ -160,4 +206,25 @@ func verifySub(t *testing.T, got *pubsub.Subscription, wantConfig pubsub.Subscri
 	if !reflect.DeepEqual(gotConfig.DeadLetterPolicy, wantConfig.DeadLetterPolicy) {
 		t.Errorf("Unexpected dead letter policy in config, got:%+v, want: %+v", gotConfig.DeadLetterPolicy, wantConfig.DeadLetterPolicy)
 	}
+	if gotConfig.Filter != wantConfig.Filter {
+		t.Errorf("Unexpected filter in config, got: %q, want: %q", gotConfig.Filter, wantConfig.Filter)
+	}
+}
+
+func verifySnapshots(t *testing.T, c *pubsub.Client, want []string) {
+	var got []string
+	it := c.Snapshots(context.Background())
+	for {
+		snapshot, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to list snapshots: %v", err)
+		}
+		got = append(got, snapshot.ID())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected snapshots, got: %v, want: %v", got, want)
+	}
 }
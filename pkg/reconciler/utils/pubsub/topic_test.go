@@ -123,6 +123,7 @@ type testCase struct {
 	wantEvents         []string
 	wantTopicCondition apis.Condition
 	wantSubCondition   apis.Condition
+	wantSnapshots      []string
 }
 
 // testRunner helps to setup resources such as pubsub client, as well as verify the common test case.
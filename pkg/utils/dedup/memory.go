@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// DefaultMemoryStoreSize is the default number of keys held by a memory store.
+const DefaultMemoryStoreSize = 100000
+
+// memoryStore is a Store backed by a bounded LRU cache. It is local to a replica, so it only
+// deduplicates the events sent to the same replica.
+type memoryStore struct {
+	// mux makes the lookup and addition of a key atomic.
+	mux   sync.Mutex
+	cache *cache.LRUExpireCache
+}
+
+// NewMemoryStore creates a Store that holds at most size keys in memory. The least recently
+// used keys are evicted first, so events may not be deduplicated over their whole window if more
+// than size events are accepted within it.
+func NewMemoryStore(size int) Store {
+	return &memoryStore{cache: cache.NewLRUExpireCache(size)}
+}
+
+// memoryEntry is the value of a key in the cache of a memoryStore.
+type memoryEntry struct {
+	entry Entry
+	// token is the token of the Add that added the key, while the key is pending.
+	token string
+}
+
+func (s *memoryStore) Add(_ context.Context, key string, ttl time.Duration) (Entry, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if e, ok := s.cache.Get(key); ok {
+		return e.(memoryEntry).entry, nil
+	}
+	token := uuid.New().String()
+	s.cache.Add(key, memoryEntry{entry: Entry{State: Pending}, token: token}, ttl)
+	return Entry{State: Added, Token: token}, nil
+}
+
+func (s *memoryStore) MarkPublished(_ context.Context, key, messageID string, window time.Duration) error {
+	s.cache.Add(key, memoryEntry{entry: Entry{State: Published, MessageID: messageID}}, window)
+	return nil
+}
+
+func (s *memoryStore) Remove(_ context.Context, key, token string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if e, ok := s.cache.Get(key); ok && e.(memoryEntry).token == token {
+		s.cache.Remove(key)
+	}
+	return nil
+}
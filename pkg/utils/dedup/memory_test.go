@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(10))
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+	for _, key := range []string{"a", "b", "c"} {
+		if entry, err := s.Add(ctx, key, time.Minute); err != nil || entry.State != Added {
+			t.Fatalf("Add(%q) got (%v, %v), want (Added, nil)", key, entry, err)
+		}
+	}
+	// The least recently used key is evicted.
+	if entry, err := s.Add(ctx, "a", time.Minute); err != nil || entry.State != Added {
+		t.Errorf("Add(evicted key) got (%v, %v), want (Added, nil)", entry, err)
+	}
+	if entry, err := s.Add(ctx, "c", time.Minute); err != nil || entry.State != Pending {
+		t.Errorf("Add(recent key) got (%v, %v), want (Pending, nil)", entry, err)
+	}
+}
+
+// testStore tests the behavior shared by all the Store implementations.
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	key := Key("b-uid", "source", "id")
+
+	if entry, err := s.Add(ctx, key, time.Minute); err != nil || entry.State != Added || entry.Token == "" {
+		t.Fatalf("Add(new key) got (%v, %v), want (Added with a token, nil)", entry, err)
+	}
+	if entry, err := s.Add(ctx, key, time.Minute); err != nil || entry != (Entry{State: Pending}) {
+		t.Errorf("Add(pending key) got (%v, %v), want (Pending, nil)", entry, err)
+	}
+	if err := s.MarkPublished(ctx, key, "message-id", time.Minute); err != nil {
+		t.Fatalf("MarkPublished got error: %v", err)
+	}
+	want := Entry{State: Published, MessageID: "message-id"}
+	if entry, err := s.Add(ctx, key, time.Minute); err != nil || entry != want {
+		t.Errorf("Add(published key) got (%v, %v), want (%v, nil)", entry, err, want)
+	}
+	if entry, err := s.Add(ctx, Key("b-uid", "source", "other-id"), time.Minute); err != nil || entry.State != Added {
+		t.Errorf("Add(other key) got (%v, %v), want (Added, nil)", entry, err)
+	}
+
+	// Only the token of the Add that added a pending key removes it.
+	if err := s.Remove(ctx, key, "token"); err != nil {
+		t.Fatalf("Remove(published key) got error: %v", err)
+	}
+	if entry, err := s.Add(ctx, key, time.Minute); err != nil || entry != want {
+		t.Errorf("Add(published key) after Remove got (%v, %v), want (%v, nil)", entry, err, want)
+	}
+	key = Key("b-uid", "source", "failed-id")
+	added, err := s.Add(ctx, key, 50*time.Millisecond)
+	if err != nil || added.State != Added {
+		t.Fatalf("Add(new key) got (%v, %v), want (Added, nil)", added, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	readded, err := s.Add(ctx, key, time.Minute)
+	if err != nil || readded.State != Added || readded.Token == added.Token {
+		t.Fatalf("Add(expired key) got (%v, %v), want (Added with a new token, nil)", readded, err)
+	}
+	if err := s.Remove(ctx, key, added.Token); err != nil {
+		t.Fatalf("Remove(expired token) got error: %v", err)
+	}
+	if entry, err := s.Add(ctx, key, time.Minute); err != nil || entry.State != Pending {
+		t.Errorf("Add(key re-added by another Add) after Remove got (%v, %v), want (Pending, nil)", entry, err)
+	}
+	if err := s.Remove(ctx, key, readded.Token); err != nil {
+		t.Fatalf("Remove got error: %v", err)
+	}
+	if entry, err := s.Add(ctx, key, time.Minute); err != nil || entry.State != Added {
+		t.Errorf("Add(removed key) got (%v, %v), want (Added, nil)", entry, err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("b-uid", "source", "id") != Key("b-uid", "source", "id") {
+		t.Error("Key is not deterministic")
+	}
+	for _, other := range []string{
+		Key("other-uid", "source", "id"),
+		Key("b-uid", "other-source", "id"),
+		Key("b-uid", "source", "other-id"),
+		Key("b-uid", "sourceid", ""),
+	} {
+		if other == Key("b-uid", "source", "id") {
+			t.Errorf("Key collision: %q", other)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// pendingPrefix is the prefix of the value of the keys whose event is being published,
+	// followed by the token of the Add that added the key.
+	pendingPrefix = "pending:"
+	// publishedPrefix is the prefix of the value of the keys whose event was published,
+	// followed by the message ID of the event.
+	publishedPrefix = "published:"
+)
+
+// removeScriptSource deletes a key only if it still holds the given value, so that a key that
+// expired and was added again by another replica is not removed.
+const removeScriptSource = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`
+
+var removeScript = redis.NewScript(removeScriptSource)
+
+// redisStore is a Store backed by a Redis server, e.g. Memorystore for Redis. It is shared by all
+// the replicas, so it deduplicates the events sent to any replica.
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Store backed by the Redis server configured by opts, including its
+// address, AUTH password and TLS configuration. Keys are expired by the server.
+func NewRedisStore(opts *redis.Options) Store {
+	return &redisStore{client: redis.NewClient(opts)}
+}
+
+func (s *redisStore) Add(ctx context.Context, key string, ttl time.Duration) (Entry, error) {
+	token := uuid.New().String()
+	for {
+		added, err := s.client.SetNX(ctx, key, pendingPrefix+token, ttl).Result()
+		if err != nil {
+			return Entry{}, err
+		}
+		if added {
+			return Entry{State: Added, Token: token}, nil
+		}
+		value, err := s.client.Get(ctx, key).Result()
+		if err == redis.Nil {
+			// The key expired since it was set, so try to add it again.
+			continue
+		}
+		if err != nil {
+			return Entry{}, err
+		}
+		if strings.HasPrefix(value, publishedPrefix) {
+			return Entry{State: Published, MessageID: strings.TrimPrefix(value, publishedPrefix)}, nil
+		}
+		return Entry{State: Pending}, nil
+	}
+}
+
+func (s *redisStore) MarkPublished(ctx context.Context, key, messageID string, window time.Duration) error {
+	return s.client.Set(ctx, key, publishedPrefix+messageID, window).Err()
+}
+
+func (s *redisStore) Remove(ctx context.Context, key, token string) error {
+	return removeScript.Run(ctx, s.client, []string{key}, pendingPrefix+token).Err()
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeRedisServer is a local stand-in for a Redis server that supports the commands used by the
+// store.
+type fakeRedisServer struct {
+	ln  net.Listener
+	mux sync.Mutex
+	// password, if set, must be sent with AUTH before any other command.
+	password string
+	values   map[string]string
+	// expirations maps keys to their expiration time.
+	expirations map[string]time.Time
+}
+
+func newFakeRedisServer(t *testing.T) *fakeRedisServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeRedisServer{ln: ln, values: make(map[string]string), expirations: make(map[string]time.Time)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedisServer) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	authenticated := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		var reply string
+		switch {
+		case strings.EqualFold(args[0], "AUTH"):
+			authenticated = len(args) == 2 && args[1] == s.password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case s.password != "" && !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		default:
+			reply = s.exec(args)
+		}
+		if _, err := io.WriteString(c, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedisServer) exec(args []string) string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	now := time.Now()
+	for key, exp := range s.expirations {
+		if !exp.After(now) {
+			delete(s.values, key)
+			delete(s.expirations, key)
+		}
+	}
+	switch cmd := strings.ToUpper(args[0]); {
+	case cmd == "SET" && len(args) >= 3:
+		key, value := args[1], args[2]
+		var nx, xx bool
+		var ttl time.Duration
+		for i := 3; i < len(args); i++ {
+			switch opt := strings.ToUpper(args[i]); opt {
+			case "NX":
+				nx = true
+			case "XX":
+				xx = true
+			case "PX", "EX":
+				if i+1 == len(args) {
+					return "-ERR syntax error\r\n"
+				}
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil {
+					return "-ERR value is not an integer or out of range\r\n"
+				}
+				ttl = time.Duration(n) * time.Millisecond
+				if opt == "EX" {
+					ttl = time.Duration(n) * time.Second
+				}
+				i++
+			default:
+				return "-ERR syntax error\r\n"
+			}
+		}
+		if _, ok := s.values[key]; (nx && ok) || (xx && !ok) {
+			return "$-1\r\n"
+		}
+		s.values[key] = value
+		delete(s.expirations, key)
+		if ttl > 0 {
+			s.expirations[key] = now.Add(ttl)
+		}
+		return "+OK\r\n"
+	case cmd == "GET" && len(args) == 2:
+		value, ok := s.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case cmd == "EVALSHA":
+		// Scripts are only run with EVAL, which the client falls back to.
+		return "-NOSCRIPT No matching script. Please use EVAL.\r\n"
+	case cmd == "EVAL" && len(args) == 5 && args[1] == removeScriptSource && args[2] == "1":
+		// The compare-and-delete of removeScript.
+		key := args[3]
+		if value, ok := s.values[key]; !ok || value != args[4] {
+			return ":0\r\n"
+		}
+		delete(s.values, key)
+		delete(s.expirations, key)
+		return ":1\r\n"
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	n, err := readLength(r, '*')
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		l, err := readLength(r, '$')
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, l+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:l])
+	}
+	if n == 0 {
+		return nil, errors.New("empty command")
+	}
+	return args, nil
+}
+
+func readLength(r *bufio.Reader, prefix byte) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if line[0] != prefix {
+		return 0, fmt.Errorf("unexpected line %q", line)
+	}
+	return strconv.Atoi(strings.TrimSpace(line[1:]))
+}
+
+func TestRedisStore(t *testing.T) {
+	srv := newFakeRedisServer(t)
+	testStore(t, NewRedisStore(&redis.Options{Addr: srv.ln.Addr().String()}))
+}
+
+func TestRedisStoreAuth(t *testing.T) {
+	srv := newFakeRedisServer(t)
+	srv.password = "secret"
+	testStore(t, NewRedisStore(&redis.Options{Addr: srv.ln.Addr().String(), Password: "secret"}))
+
+	s := NewRedisStore(&redis.Options{Addr: srv.ln.Addr().String()})
+	ctx := context.Background()
+	if _, err := s.Add(ctx, "key", time.Minute); err == nil || !strings.HasPrefix(err.Error(), "NOAUTH") {
+		t.Errorf("Add without password got error %v, want NOAUTH error", err)
+	}
+	if err := s.Remove(ctx, "key", "token"); err == nil || !strings.HasPrefix(err.Error(), "NOAUTH") {
+		t.Errorf("Remove without password got error %v, want NOAUTH error", err)
+	}
+}
+
+func TestRedisStoreUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	s := NewRedisStore(&redis.Options{Addr: addr, MaxRetries: -1})
+	if _, err := s.Add(context.Background(), "key", time.Minute); err == nil {
+		t.Error("Add got nil error, want connection error")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dedup provides stores of the events recently accepted by the broker ingress or the
+// Topic publisher, used to deduplicate the events sent more than once, e.g. when a producer
+// retries after a failed response.
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// State is the state of a key in a Store.
+type State int
+
+const (
+	// Added means the key was not in the store, and it is added as pending.
+	Added State = iota
+	// Pending means the event of the key is being published, so it is not known yet whether it
+	// will be accepted.
+	Pending
+	// Published means the event of the key was published, so its duplicates can be accepted.
+	Published
+)
+
+// Entry is the entry of a key in a Store.
+type Entry struct {
+	State State
+	// MessageID is the ID of the Pub/Sub message the event of the key was published as, if the
+	// key is Published.
+	MessageID string
+	// Token identifies the addition of the key, if the key is Added. It is passed to Remove so
+	// that only the adder of the key removes it.
+	Token string
+}
+
+// Store records the keys of the events accepted within a window.
+type Store interface {
+	// Add adds the key to the store as pending for the ttl, unless it is already in the store.
+	// It returns an Added entry if the key is added, or the entry of the key in the store
+	// otherwise.
+	Add(ctx context.Context, key string, ttl time.Duration) (Entry, error)
+
+	// MarkPublished marks the key as published for the window, once its event is published as
+	// the message messageID.
+	MarkPublished(ctx context.Context, key, messageID string, window time.Duration) error
+
+	// Remove removes the key added with the token from the store, so that an event that is not
+	// published is not considered a duplicate when it is sent again. The key is left alone if it
+	// expired and was added again, possibly by another replica, since the token differs.
+	Remove(ctx context.Context, key, token string) error
+}
+
+// Key returns the key of an event sent to a broker or a topic, identified by its uid. Events are
+// identified by their source and id. The source and id are hashed so that keys have a bounded
+// size.
+func Key(uid, source, id string) string {
+	h := sha256.New()
+	h.Write([]byte(source))
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+	return "dedup:" + uid + ":" + hex.EncodeToString(h.Sum(nil))
+}
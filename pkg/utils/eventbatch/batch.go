@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventbatch serves the requests that send a batch of events in the CloudEvents JSON
+// batch format, for the components that publish the events they receive to Pub/Sub.
+package eventbatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	nethttp "net/http"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+)
+
+// MaxEvents is the maximum number of events in a batch request, which is also the maximum number
+// of messages in a Pub/Sub publish request.
+const MaxEvents = 1000
+
+// ErrTooManyEvents is returned by Read if the batch has more than MaxEvents events.
+var ErrTooManyEvents = fmt.Errorf("batch has more than %d events", MaxEvents)
+
+// errBodyTooLarge is the message of the error returned by an http.MaxBytesReader.
+const errBodyTooLarge = "http: request body too large"
+
+// Result is the result of an event of a batch request.
+type Result struct {
+	ID        string `json:"id,omitempty"`
+	Source    string `json:"source,omitempty"`
+	MessageID string `json:"messageId,omitempty"`
+	// Code is the status code the event would have been responded with if it was sent alone.
+	Code  int    `json:"code"`
+	Error string `json:"error,omitempty"`
+}
+
+// Response is the body of the response to a batch request. Results are in the order of the events
+// in the batch.
+type Response struct {
+	Accepted int      `json:"accepted"`
+	Rejected int      `json:"rejected"`
+	Results  []Result `json:"results"`
+}
+
+// IsRequest returns true if the request is a batch of events in the CloudEvents JSON batch format.
+func IsRequest(request *nethttp.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return err == nil && mediaType == cev2.ApplicationCloudEventsBatchJSON
+}
+
+// Read reads the events of a batch request, without decoding them. The body of the request is
+// limited to maxBytes.
+func Read(response nethttp.ResponseWriter, request *nethttp.Request, maxBytes int64) ([]json.RawMessage, error) {
+	body, err := ioutil.ReadAll(nethttp.MaxBytesReader(response, request.Body, maxBytes))
+	if err != nil {
+		return nil, err
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, fmt.Errorf("malformed batch: %w", err)
+	}
+	if len(batch) > MaxEvents {
+		return nil, ErrTooManyEvents
+	}
+	return batch, nil
+}
+
+// ReadErrorCode returns the status code of a request whose batch cannot be read: 413 if the
+// request is too large, and 400 otherwise.
+func ReadErrorCode(err error) int {
+	if errors.Is(err, ErrTooManyEvents) || err.Error() == errBodyTooLarge {
+		return nethttp.StatusRequestEntityTooLarge
+	}
+	return nethttp.StatusBadRequest
+}
+
+// Decode decodes and validates the events of a batch. The event of an invalid entry is nil, and
+// its result has a 400 code and the validation error.
+func Decode(batch []json.RawMessage) ([]*cev2.Event, []Result) {
+	events := make([]*cev2.Event, len(batch))
+	results := make([]Result, len(batch))
+	for i, raw := range batch {
+		event, err := decodeEvent(raw)
+		if err != nil {
+			results[i] = Result{Code: nethttp.StatusBadRequest, Error: err.Error()}
+			continue
+		}
+		events[i] = event
+		results[i] = Result{ID: event.ID(), Source: event.Source()}
+	}
+	return events, results
+}
+
+// decodeEvent decodes and validates an event of a batch. Like transformer.AddTimeNow does for the
+// events of requests, it sets the time of the event to now if it has none.
+func decodeEvent(raw json.RawMessage) (*cev2.Event, error) {
+	event := cev2.NewEvent()
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, err
+	}
+	if event.Time().IsZero() {
+		event.SetTime(time.Now())
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Send sends the decoded events of a batch concurrently so that they are published in as few
+// Pub/Sub publish requests as possible, except that events with the same ordering key are sent
+// one after the other, in the order of the batch. send sends an event and sets its result. Once
+// an event is rejected, the next events with its ordering key are not sent, since they would be
+// delivered before it once it is sent again: they get its code, and skipped is called for each of
+// them.
+func Send(events []*cev2.Event, results []Result, orderingKey func(*cev2.Event) string, send func(*cev2.Event, *Result), skipped func(*cev2.Event, int)) {
+	// groups holds the indexes of the events sent by each goroutine. Events with the same ordering
+	// key are in the same group, and events without ordering key are in a group of their own.
+	var groups [][]int
+	keyGroups := make(map[string]int)
+	for i, event := range events {
+		if event == nil {
+			continue
+		}
+		key := orderingKey(event)
+		if key == "" {
+			groups = append(groups, []int{i})
+		} else if g, ok := keyGroups[key]; ok {
+			groups[g] = append(groups[g], i)
+		} else {
+			keyGroups[key] = len(groups)
+			groups = append(groups, []int{i})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group []int) {
+			defer wg.Done()
+			for n, i := range group {
+				send(events[i], &results[i])
+				if code := results[i].Code; code != nethttp.StatusAccepted {
+					for _, j := range group[n+1:] {
+						results[j].Code = code
+						results[j].Error = "Not sent because a previous event with the same ordering key was rejected"
+						skipped(events[j], code)
+					}
+					return
+				}
+			}
+		}(group)
+	}
+	wg.Wait()
+}
+
+// WriteResponse responds to a batch request with the results of its events. The status code is
+// the status code of the events if they all have the same, and 207 Multi-Status otherwise.
+func WriteResponse(response nethttp.ResponseWriter, results []Result) error {
+	body := Response{Results: results}
+	statusCode := nethttp.StatusAccepted
+	for i, r := range results {
+		if r.Code == nethttp.StatusAccepted {
+			body.Accepted++
+		} else {
+			body.Rejected++
+		}
+		if i == 0 {
+			statusCode = r.Code
+		} else if r.Code != statusCode {
+			statusCode = nethttp.StatusMultiStatus
+		}
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(statusCode)
+	return json.NewEncoder(response).Encode(body)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbatch
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		maxBytes int64
+		wantLen  int
+		wantCode int
+	}{{
+		name:     "batch",
+		body:     `[{"id":"1"},{"id":"2"}]`,
+		maxBytes: 100,
+		wantLen:  2,
+	}, {
+		name:     "malformed batch",
+		body:     `{"id":"1"}`,
+		maxBytes: 100,
+		wantCode: nethttp.StatusBadRequest,
+	}, {
+		name:     "too many events",
+		body:     "[" + strings.Repeat("{},", MaxEvents) + "{}]",
+		maxBytes: 10 * MaxEvents,
+		wantCode: nethttp.StatusRequestEntityTooLarge,
+	}, {
+		name:     "body too large",
+		body:     `[{"id":"1"},{"id":"2"}]`,
+		maxBytes: 10,
+		wantCode: nethttp.StatusRequestEntityTooLarge,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(nethttp.MethodPost, "/", strings.NewReader(tc.body))
+			batch, err := Read(httptest.NewRecorder(), req, tc.maxBytes)
+			if tc.wantCode != 0 {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				if got := ReadErrorCode(err); got != tc.wantCode {
+					t.Errorf("Unexpected status code of error %v, got %d, want %d", err, got, tc.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(batch) != tc.wantLen {
+				t.Errorf("Unexpected number of events, got %d, want %d", len(batch), tc.wantLen)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	events, results := Decode([]json.RawMessage{
+		json.RawMessage(`{"specversion":"1.0","id":"1","source":"test-source","type":"test-type"}`),
+		json.RawMessage(`{"specversion":"1.0","id":"2"}`),
+	})
+	if events[0] == nil || events[0].Time().IsZero() {
+		t.Errorf("Expected a valid event with a time, got %v", events[0])
+	}
+	if events[1] != nil {
+		t.Errorf("Expected no event for an invalid entry, got %v", events[1])
+	}
+	if results[1].Code != nethttp.StatusBadRequest || results[1].Error == "" {
+		t.Errorf("Unexpected result of an invalid entry: %+v", results[1])
+	}
+	if diff := cmp.Diff(Result{ID: "1", Source: "test-source"}, results[0]); diff != "" {
+		t.Errorf("Unexpected result (-want, +got): %s", diff)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var events []*cev2.Event
+	for _, subject := range []string{"a", "a", "a", "b", ""} {
+		e := cev2.NewEvent()
+		e.SetID(string(rune('1' + len(events))))
+		e.SetSubject(subject)
+		events = append(events, &e)
+	}
+	// The invalid entries of a batch have no event.
+	events = append(events, nil)
+	results := make([]Result, len(events))
+	results[5] = Result{Code: nethttp.StatusBadRequest}
+
+	var skipped []string
+	Send(events, results,
+		func(e *cev2.Event) string { return OrderingKey(e, "subject") },
+		func(e *cev2.Event, r *Result) {
+			r.Code = nethttp.StatusAccepted
+			if e.ID() == "2" {
+				r.Code = nethttp.StatusServiceUnavailable
+			}
+		},
+		func(e *cev2.Event, statusCode int) { skipped = append(skipped, e.ID()) })
+
+	want := []int{
+		nethttp.StatusAccepted,
+		nethttp.StatusServiceUnavailable,
+		nethttp.StatusServiceUnavailable,
+		nethttp.StatusAccepted,
+		nethttp.StatusAccepted,
+		nethttp.StatusBadRequest,
+	}
+	var got []int
+	for _, r := range results {
+		got = append(got, r.Code)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected status codes (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"3"}, skipped); diff != "" {
+		t.Errorf("Unexpected skipped events (-want, +got): %s", diff)
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		codes    []int
+		wantCode int
+	}{
+		{name: "empty", wantCode: nethttp.StatusAccepted},
+		{name: "same code", codes: []int{nethttp.StatusNotFound, nethttp.StatusNotFound}, wantCode: nethttp.StatusNotFound},
+		{name: "different codes", codes: []int{nethttp.StatusAccepted, nethttp.StatusNotFound}, wantCode: nethttp.StatusMultiStatus},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			results := make([]Result, len(tc.codes))
+			for i, code := range tc.codes {
+				results[i].Code = code
+			}
+			res := httptest.NewRecorder()
+			if err := WriteResponse(res, results); err != nil {
+				t.Fatal(err)
+			}
+			if res.Code != tc.wantCode {
+				t.Errorf("Unexpected status code, got %d, want %d", res.Code, tc.wantCode)
+			}
+		})
+	}
+}
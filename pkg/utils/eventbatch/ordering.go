@@ -0,0 +1,51 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbatch
+
+import (
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// OrderingKey returns the ordering key of the event, which is the value of the ordering key
+// attribute. If the attribute is empty, or the event does not have it, it returns an empty string
+// and the event is not ordered.
+func OrderingKey(event *cev2.Event, attribute string) string {
+	var v interface{}
+	switch attribute {
+	case "":
+		return ""
+	case "id":
+		return event.ID()
+	case "source":
+		return event.Source()
+	case "type":
+		return event.Type()
+	case "subject":
+		return event.Subject()
+	default:
+		v = event.Extensions()[attribute]
+	}
+	if v == nil {
+		return ""
+	}
+	key, err := types.Format(v)
+	if err != nil {
+		return ""
+	}
+	return key
+}
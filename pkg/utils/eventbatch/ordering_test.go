@@ -0,0 +1,48 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbatch
+
+import (
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+)
+
+func TestOrderingKey(t *testing.T) {
+	e := cev2.NewEvent()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetSubject("subject")
+	e.SetExtension("partitionkey", "key")
+	e.SetExtension("count", 3)
+
+	for attribute, want := range map[string]string{
+		"":             "",
+		"id":           "id",
+		"source":       "source",
+		"type":         "type",
+		"subject":      "subject",
+		"partitionkey": "key",
+		"count":        "3",
+		"missing":      "",
+	} {
+		if got := OrderingKey(&e, attribute); got != want {
+			t.Errorf("OrderingKey(%q) got %q, want %q", attribute, got, want)
+		}
+	}
+}